@@ -111,6 +111,25 @@ func (backupStatus *BackupStatus) IsInProgress() bool {
 		backupStatus.Phase == BackupPhaseRunning
 }
 
+// IsRunning checks if a certain backup is being taken, that is it
+// has been started on an instance and not yet finalized
+func (backupStatus *BackupStatus) IsRunning() bool {
+	return backupStatus.Phase == BackupPhaseStarted ||
+		backupStatus.Phase == BackupPhaseRunning
+}
+
+// GetRunningBackupNames returns the names of the backups being taken
+func (list BackupList) GetRunningBackupNames() []string {
+	var runningBackups []string
+	for _, backup := range list.Items {
+		if backup.Status.IsRunning() {
+			runningBackups = append(runningBackups, backup.Name)
+		}
+	}
+
+	return runningBackups
+}
+
 // GetPendingBackupNames returns the pending backup list
 func (list BackupList) GetPendingBackupNames() []string {
 	// Retry the backup if another backup is running
@@ -125,6 +125,18 @@ var _ = Describe("BackupStatus structure", func() {
 })
 
 var _ = Describe("BackupList structure", func() {
+	It("can tell the backups being taken", func() {
+		backupList := BackupList{
+			Items: []Backup{
+				{ObjectMeta: metav1.ObjectMeta{Name: "pending"}, Status: BackupStatus{Phase: BackupPhasePending}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "started"}, Status: BackupStatus{Phase: BackupPhaseStarted}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "running"}, Status: BackupStatus{Phase: BackupPhaseRunning}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "completed"}, Status: BackupStatus{Phase: BackupPhaseCompleted}},
+			},
+		}
+		Expect(backupList.GetRunningBackupNames()).To(Equal([]string{"started", "running"}))
+	})
+
 	It("can be sorted by name", func() {
 		backupList := BackupList{
 			Items: []Backup{
@@ -122,12 +122,13 @@ type BackupSpec struct {
 
 	// The policy to decide which instance should perform this backup. If empty,
 	// it defaults to `cluster.spec.backup.target`.
-	// Available options are empty string, `primary` and `prefer-standby`.
-	// `primary` to have backups run always on primary instances,
+	// Available options are empty string, `primary`, `prefer-standby` and
+	// `standby`. `primary` to have backups run always on primary instances,
 	// `prefer-standby` to have backups run preferably on the most updated
-	// standby, if available.
+	// standby, if available, `standby` to have backups run only on the most
+	// updated standby, deferring them while no standby is available.
 	// +optional
-	// +kubebuilder:validation:Enum=primary;prefer-standby
+	// +kubebuilder:validation:Enum=primary;prefer-standby;standby
 	Target BackupTarget `json:"target,omitempty"`
 
 	// The backup method to be used, possible options are `barmanObjectStore`,
@@ -292,6 +293,25 @@ type BackupStatus struct {
 	// A map containing the plugin metadata
 	// +optional
 	PluginMetadata map[string]string `json:"pluginMetadata,omitempty"`
+
+	// The outcome of the export of the global objects taken alongside
+	// the backup, when enabled with `.spec.backup.exportGlobals` in
+	// the cluster
+	// +optional
+	GlobalsExport *GlobalsExportStatus `json:"globalsExport,omitempty"`
+
+	// The estimated percentage of completion of a running backup taken
+	// with Barman Cloud. It is not set when the progress is unknown
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	BackupProgress *int32 `json:"backupProgress,omitempty"`
+
+	// The estimated amount of data, in bytes, already read from the
+	// data directory by a backup taken with Barman Cloud. It is not set
+	// when the progress is unknown
+	// +optional
+	BytesTransferred *int64 `json:"bytesTransferred,omitempty"`
 }
 
 // InstanceID contains the information to identify an instance
@@ -304,6 +324,24 @@ type InstanceID struct {
 	ContainerID string `json:"ContainerID,omitempty"`
 }
 
+// GlobalsExportStatus contains the outcome of the export of the global
+// objects, such as roles and tablespaces, taken alongside a backup
+type GlobalsExportStatus struct {
+	// When the global objects have been exported
+	// +optional
+	ExportedAt *metav1.Time `json:"exportedAt,omitempty"`
+
+	// The location, in the object store, of the file with the output
+	// of `pg_dumpall --globals-only`
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// The error raised while exporting the global objects, if any.
+	// The backup is taken anyway
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
 // +genclient
 // +kubebuilder:object:root=true
 // +kubebuilder:storageversion
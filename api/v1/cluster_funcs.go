@@ -19,10 +19,12 @@ package v1
 import (
 	"context"
 	"fmt"
+	"maps"
 	"regexp"
 	"slices"
 	"strconv"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/cloudnative-pg/machinery/pkg/image/reference"
@@ -30,7 +32,9 @@ import (
 	pgTime "github.com/cloudnative-pg/machinery/pkg/postgres/time"
 	"github.com/cloudnative-pg/machinery/pkg/postgres/version"
 	"github.com/cloudnative-pg/machinery/pkg/stringset"
+	"github.com/robfig/cron"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -248,6 +252,42 @@ func (r *ReplicationSlotsHAConfiguration) GetEnabled() bool {
 	return true
 }
 
+// GetOrphanedSlotsGracePeriod returns how long an HA replication slot not
+// corresponding to any instance is kept before being dropped
+func (r *ReplicationSlotsHAConfiguration) GetOrphanedSlotsGracePeriod() time.Duration {
+	if r == nil || r.OrphanedSlotsGracePeriod <= 0 {
+		return 0
+	}
+	return time.Duration(r.OrphanedSlotsGracePeriod) * time.Second
+}
+
+// GetCreateReplicaMethod returns the method used to create new replicas,
+// defaulting to CreateReplicaMethodFromBackup if empty
+func (r *ReplicationConfiguration) GetCreateReplicaMethod() CreateReplicaMethod {
+	if r == nil || r.CreateReplicaMethod == "" {
+		return CreateReplicaMethodFromBackup
+	}
+	return r.CreateReplicaMethod
+}
+
+// GetCredentialsRotationPeriod returns the period after which the
+// streaming replication credentials are rotated, zero if disabled
+func (r *ReplicationConfiguration) GetCredentialsRotationPeriod() time.Duration {
+	if r == nil || r.CredentialsRotationPeriod == nil {
+		return 0
+	}
+	return r.CredentialsRotationPeriod.Duration
+}
+
+// GetHBAScope returns the addresses from which the `streaming_replica`
+// user is allowed to connect
+func (r *ReplicationConfiguration) GetHBAScope() ReplicationHBAScope {
+	if r == nil || r.HBAScope == "" {
+		return ReplicationHBAScopeAll
+	}
+	return r.HBAScope
+}
+
 // ToPostgreSQLConfigurationKeyword returns the contained value as a valid PostgreSQL parameter to be injected
 // in the 'synchronous_standby_names' field
 func (s SynchronousReplicaConfigurationMethod) ToPostgreSQLConfigurationKeyword() string {
@@ -319,6 +359,26 @@ func (m *MonitoringConfiguration) AreDefaultQueriesDisabled() bool {
 	return m != nil && m.DisableDefaultQueries != nil && *m.DisableDefaultQueries
 }
 
+// GetWraparoundRiskThreshold returns the percentage of the freeze max age
+// above which the age of a database raises the WraparoundRisk condition
+func (m *MonitoringConfiguration) GetWraparoundRiskThreshold() int {
+	if m == nil || m.WraparoundRiskThreshold == 0 {
+		return DefaultWraparoundRiskThreshold
+	}
+
+	return m.WraparoundRiskThreshold
+}
+
+// GetStalePreparedTransactionsAge returns the age after which a prepared
+// transaction raises the StalePreparedTransactions condition
+func (m *MonitoringConfiguration) GetStalePreparedTransactionsAge() time.Duration {
+	if m == nil || m.StalePreparedTransactionsAge == 0 {
+		return DefaultStalePreparedTransactionsAge * time.Second
+	}
+
+	return time.Duration(m.StalePreparedTransactionsAge) * time.Second
+}
+
 // GetServerName returns the server name, defaulting to the name of the external cluster or using the one specified
 // in the BarmanObjectStore
 func (in ExternalCluster) GetServerName() string {
@@ -328,6 +388,13 @@ func (in ExternalCluster) GetServerName() string {
 	return in.Name
 }
 
+// GetWALObjectStore returns the object store containing the WAL files
+// of the external cluster, which is the one containing the base backups
+// unless a separate one is configured
+func (in ExternalCluster) GetWALObjectStore() *BarmanObjectStoreConfiguration {
+	return getWALObjectStore(in.BarmanObjectStore, in.WALObjectStore)
+}
+
 // IsEnabled returns true when this plugin is enabled
 func (config *PluginConfiguration) IsEnabled() bool {
 	if config.Enabled == nil {
@@ -336,6 +403,54 @@ func (config *PluginConfiguration) IsEnabled() bool {
 	return *config.Enabled
 }
 
+// GetMaxLag returns the amount of WAL, in bytes, a synchronous standby
+// can lag behind the primary before being replaced
+func (rotation *SynchronousStandbyLagRotation) GetMaxLag() int64 {
+	if rotation == nil || rotation.MaxLag == "" {
+		return DefaultSynchronousStandbyMaxLag
+	}
+
+	maxLag, err := resource.ParseQuantity(rotation.MaxLag)
+	if err != nil {
+		return DefaultSynchronousStandbyMaxLag
+	}
+	return maxLag.Value()
+}
+
+// GetDelay returns the time a synchronous standby needs to lag behind the
+// primary before being replaced
+func (rotation *SynchronousStandbyLagRotation) GetDelay() time.Duration {
+	if rotation == nil || rotation.Delay == nil {
+		return DefaultSynchronousStandbyRotationDelay * time.Second
+	}
+	return time.Duration(*rotation.Delay) * time.Second
+}
+
+// GetSynchronousStandbyLagRotation returns the configuration of the
+// replacement of the lagging synchronous standbys, or nil when disabled
+func (cluster *Cluster) GetSynchronousStandbyLagRotation() *SynchronousStandbyLagRotation {
+	if cluster.Spec.PostgresConfiguration.Synchronous == nil {
+		return nil
+	}
+	return cluster.Spec.PostgresConfiguration.Synchronous.LagRotation
+}
+
+// IsSynchronousCrossZonePreferred checks if the synchronous standbys
+// should be preferably chosen in a zone different from the primary's one
+func (cluster *Cluster) IsSynchronousCrossZonePreferred() bool {
+	return cluster.Spec.PostgresConfiguration.Synchronous != nil &&
+		cluster.Spec.PostgresConfiguration.Synchronous.PreferCrossZone
+}
+
+// GetDeprioritizedSynchronousStandbys returns the instances that have been
+// moved to the end of synchronous_standby_names because of their lag
+func (cluster *Cluster) GetDeprioritizedSynchronousStandbys() []string {
+	if cluster.Status.SynchronousStandbyRotation == nil {
+		return nil
+	}
+	return cluster.Status.SynchronousStandbyRotation.Deprioritized
+}
+
 // GetRoleSecretsName gets the name of the secret which is used to store the role's password
 func (roleConfiguration *RoleConfiguration) GetRoleSecretsName() string {
 	if roleConfiguration.PasswordSecret != nil {
@@ -352,6 +467,19 @@ func (roleConfiguration *RoleConfiguration) GetRoleInherit() bool {
 	return true
 }
 
+// GetMemberships returns the roles this role is a member of, including
+// the ones where the membership is granted WITH ADMIN OPTION
+func (roleConfiguration *RoleConfiguration) GetMemberships() []string {
+	memberships := make([]string, 0, len(roleConfiguration.InRoles)+len(roleConfiguration.InRolesWithAdminOption))
+	memberships = append(memberships, roleConfiguration.InRoles...)
+	for _, role := range roleConfiguration.InRolesWithAdminOption {
+		if !slices.Contains(memberships, role) {
+			memberships = append(memberships, role)
+		}
+	}
+	return memberships
+}
+
 // SetManagedRoleSecretVersion Add or update or delete the resource version of the managed role secret
 func (secretResourceVersion *SecretsResourceVersion) SetManagedRoleSecretVersion(secret string, version *string) {
 	if secretResourceVersion.ManagedRoleSecretVersions == nil {
@@ -546,6 +674,18 @@ func (cluster *Cluster) GetApplicationDatabaseName() string {
 	return ""
 }
 
+// GetApplicationDatabaseConnectionLimit gets the connection limit to be
+// applied to the application database, or nil if it must not be managed.
+// This is only supported when bootstrapping with initdb
+func (cluster *Cluster) GetApplicationDatabaseConnectionLimit() *int {
+	bootstrap := cluster.Spec.Bootstrap
+	if bootstrap == nil || bootstrap.InitDB == nil {
+		return nil
+	}
+
+	return bootstrap.InitDB.ConnectionLimit
+}
+
 // GetApplicationDatabaseOwner get the owner user of the application database for a specific bootstrap
 func (cluster *Cluster) GetApplicationDatabaseOwner() string {
 	bootstrap := cluster.Spec.Bootstrap
@@ -627,6 +767,12 @@ func (cluster *Cluster) GetServiceAnyName() string {
 	return fmt.Sprintf("%v%v", cluster.Name, ServiceAnySuffix)
 }
 
+// GetServiceHeadlessName return the name of the headless service that is
+// used as DNS domain for all the instances, when enabled
+func (cluster *Cluster) GetServiceHeadlessName() string {
+	return fmt.Sprintf("%v%v", cluster.Name, ServiceHeadlessSuffix)
+}
+
 // GetServiceReadName return the default name of the service that is used for
 // read transactions (including the primary)
 func (cluster *Cluster) GetServiceReadName() string {
@@ -683,6 +829,85 @@ func (cluster *Cluster) GetMaxSwitchoverDelay() int32 {
 	return DefaultMaxSwitchoverDelay
 }
 
+// GetSwitchoverCatchUpTimeout get the time in seconds the operator waits
+// for the new primary to catch up before aborting a switchover
+func (cluster *Cluster) GetSwitchoverCatchUpTimeout() int32 {
+	if cluster.Spec.SwitchoverCatchUpTimeout > 0 {
+		return cluster.Spec.SwitchoverCatchUpTimeout
+	}
+	return DefaultSwitchoverCatchUpTimeout
+}
+
+// GetSwitchoverOnShutdownTimeout gets the time in seconds the primary waits
+// for the switchover requested before being stopped. The wait is part of the
+// termination grace period of the Pod, so the time reserved for the smart
+// shutdown is left to stop PostgreSQL
+func (cluster *Cluster) GetSwitchoverOnShutdownTimeout() int32 {
+	timeout := cluster.GetMaxStopDelay()
+	if smartShutdownTimeout := cluster.GetSmartShutdownTimeout(); smartShutdownTimeout < timeout {
+		timeout -= smartShutdownTimeout
+	}
+	return min(timeout, cluster.GetMaxSwitchoverDelay())
+}
+
+// GetFailoverTopologyKey gets the node label defining the failure domain
+// to be preferred when choosing the failover candidate. An empty string
+// means that the failure domain is not taken into account
+func (cluster *Cluster) GetFailoverTopologyKey() string {
+	if cluster.Spec.FailoverTopology == nil {
+		return ""
+	}
+	if cluster.Spec.FailoverTopology.TopologyKey == "" {
+		return DefaultFailoverTopologyKey
+	}
+	return cluster.Spec.FailoverTopology.TopologyKey
+}
+
+// GetAutoSwitchback gets the configuration of the automatic switchback
+// to the preferred node of the primary, or nil if it is not enabled
+func (cluster *Cluster) GetAutoSwitchback() *AutoSwitchbackConfiguration {
+	if cluster.Spec.PrimaryPreference == nil {
+		return nil
+	}
+	return cluster.Spec.PrimaryPreference.AutoSwitchback
+}
+
+// GetCooldown gets the time elapsed since the last promotion, and since
+// the instance on the preferred node became ready, before switching back
+func (configuration *AutoSwitchbackConfiguration) GetCooldown() time.Duration {
+	if configuration.Cooldown == nil {
+		return DefaultAutoSwitchbackCooldown * time.Second
+	}
+	return time.Duration(*configuration.Cooldown) * time.Second
+}
+
+// GetMaintenanceWindowDelay gets the time to wait, from the passed moment,
+// for the next maintenance window to begin. It is zero when the switchback
+// is allowed at the passed moment
+func (configuration *AutoSwitchbackConfiguration) GetMaintenanceWindowDelay(now time.Time) time.Duration {
+	if len(configuration.MaintenanceWindows) == 0 {
+		return 0
+	}
+
+	var delay time.Duration
+	for _, window := range configuration.MaintenanceWindows {
+		schedule, err := cron.Parse(window.Schedule)
+		if err != nil {
+			continue
+		}
+
+		// The window is open if it began less than its duration ago
+		if !schedule.Next(now.Add(-window.Duration.Duration)).After(now) {
+			return 0
+		}
+		if next := schedule.Next(now).Sub(now); delay == 0 || next < delay {
+			delay = next
+		}
+	}
+
+	return delay
+}
+
 // GetPrimaryUpdateStrategy get the cluster primary update strategy,
 // defaulting to unsupervised
 func (cluster *Cluster) GetPrimaryUpdateStrategy() PrimaryUpdateStrategy {
@@ -714,6 +939,42 @@ func (cluster *Cluster) GetEnablePDB() bool {
 	return *cluster.Spec.EnablePDB
 }
 
+// GetJobRetention gets the minimum time a completed Job is kept and
+// the number of most recently completed Jobs kept regardless of it
+func (cluster *Cluster) GetJobRetention() (time.Duration, int) {
+	if cluster.Spec.JobRetention == nil {
+		return 0, 0
+	}
+
+	var ttl time.Duration
+	if cluster.Spec.JobRetention.TTL != nil {
+		ttl = cluster.Spec.JobRetention.TTL.Duration
+	}
+
+	return ttl, int(cluster.Spec.JobRetention.KeepLast)
+}
+
+// ShouldCleanupDivergedWALOnRejoin checks whether a former primary rejoining
+// the cluster needs to remove the WAL files of the diverged timeline,
+// defaults to true
+func (cluster *Cluster) ShouldCleanupDivergedWALOnRejoin() bool {
+	if cluster.Spec.CleanupDivergedWALOnRejoin == nil {
+		return true
+	}
+
+	return *cluster.Spec.CleanupDivergedWALOnRejoin
+}
+
+// GetBootstrapReadiness gets the policy defining when a freshly bootstrapped
+// cluster is marked as ready, defaults to BootstrapReadinessManagedObjects
+func (cluster *Cluster) GetBootstrapReadiness() BootstrapReadinessPolicy {
+	if cluster.Spec.BootstrapReadiness == "" {
+		return BootstrapReadinessManagedObjects
+	}
+
+	return cluster.Spec.BootstrapReadiness
+}
+
 // IsNodeMaintenanceWindowInProgress check if the upgrade mode is active or not
 func (cluster *Cluster) IsNodeMaintenanceWindowInProgress() bool {
 	return cluster.Spec.NodeMaintenanceWindow != nil && cluster.Spec.NodeMaintenanceWindow.InProgress
@@ -729,6 +990,16 @@ func (cluster *Cluster) GetPgCtlTimeoutForPromotion() int32 {
 	return timeout
 }
 
+// GetPromotionRecoveryTimeout returns the time to wait for a promoted
+// instance to end the recovery before raising the PromotionStuck condition
+func (cluster *Cluster) GetPromotionRecoveryTimeout() time.Duration {
+	timeout := cluster.Spec.PostgresConfiguration.PromotionRecoveryTimeout
+	if timeout <= 0 {
+		timeout = DefaultPromotionRecoveryTimeout
+	}
+	return time.Duration(timeout) * time.Second
+}
+
 // IsReusePVCEnabled check if in a maintenance window we should reuse PVCs
 func (cluster *Cluster) IsReusePVCEnabled() bool {
 	reusePVC := true
@@ -738,7 +1009,10 @@ func (cluster *Cluster) IsReusePVCEnabled() bool {
 	return reusePVC
 }
 
-// IsInstanceFenced check if in a given instance should be fenced
+// IsInstanceFenced check if in a given instance should be fenced.
+// Role selectors are resolved against the current primary every time
+// this function is called, so that they also cover the instances that
+// joined the cluster, or changed their role, after the fencing was requested
 func (cluster *Cluster) IsInstanceFenced(instance string) bool {
 	fencedInstances, err := utils.GetFencedInstances(cluster.Annotations)
 	if err != nil {
@@ -748,9 +1022,50 @@ func (cluster *Cluster) IsInstanceFenced(instance string) bool {
 	if fencedInstances.Has(utils.FenceAllInstances) {
 		return true
 	}
+
+	currentPrimary := cluster.Status.CurrentPrimary
+	if currentPrimary != "" {
+		if fencedInstances.Has(utils.FencePrimarySelector) && instance == currentPrimary {
+			return true
+		}
+		if fencedInstances.Has(utils.FenceReplicasSelector) && instance != currentPrimary {
+			return true
+		}
+	}
+
 	return fencedInstances.Has(instance)
 }
 
+// GetIntegrityCheckInstance gets the name of the replica that should
+// execute the integrity checks, preferring the fenced ones. It returns an
+// empty string when the integrity check is not configured or when there are
+// no replicas available
+func (cluster *Cluster) GetIntegrityCheckInstance() string {
+	if cluster.Spec.Managed == nil || cluster.Spec.Managed.IntegrityCheck == nil {
+		return ""
+	}
+
+	replicas := make([]string, 0, len(cluster.Status.InstanceNames))
+	for _, name := range cluster.Status.InstanceNames {
+		if name == cluster.Status.CurrentPrimary || name == cluster.Status.TargetPrimary {
+			continue
+		}
+		replicas = append(replicas, name)
+	}
+	if len(replicas) == 0 {
+		return ""
+	}
+	slices.Sort(replicas)
+
+	for _, name := range replicas {
+		if cluster.IsInstanceFenced(name) {
+			return name
+		}
+	}
+
+	return replicas[len(replicas)-1]
+}
+
 // ShouldResizeInUseVolumes is true when we should resize PVC we already
 // created
 func (cluster *Cluster) ShouldResizeInUseVolumes() bool {
@@ -761,6 +1076,76 @@ func (cluster *Cluster) ShouldResizeInUseVolumes() bool {
 	return *cluster.Spec.StorageConfiguration.ResizeInUseVolumes
 }
 
+// GetThreshold returns the percentage of the data volume capacity above
+// which an instance is considered under disk pressure
+func (configuration *DiskPressureConfiguration) GetThreshold() int {
+	if configuration == nil || configuration.Threshold == 0 {
+		return DefaultDiskPressureThreshold
+	}
+
+	return configuration.Threshold
+}
+
+// GetExpansionPercentage returns the percentage by which the data
+// volumes are increased at every automatic expansion
+func (configuration *DiskPressureConfiguration) GetExpansionPercentage() int {
+	if configuration == nil || configuration.ExpansionPercentage == 0 {
+		return DefaultDiskPressureExpansionPercentage
+	}
+
+	return configuration.ExpansionPercentage
+}
+
+// ShouldAutoExpand is true when the operator should increase the size
+// of the data volumes of an instance under disk pressure
+func (configuration *DiskPressureConfiguration) ShouldAutoExpand() bool {
+	return configuration != nil && configuration.AutoExpand
+}
+
+// GetRepackConfiguration returns the configuration of the periodic
+// execution of pg_repack, or nil when not configured
+func (cluster *Cluster) GetRepackConfiguration() *RepackConfiguration {
+	if cluster.Spec.Managed == nil || cluster.Spec.Managed.Maintenance == nil {
+		return nil
+	}
+
+	return cluster.Spec.Managed.Maintenance.Repack
+}
+
+// GetJobs returns the number of parallel jobs used by pg_repack
+func (configuration *RepackConfiguration) GetJobs() int {
+	if configuration.Jobs <= 0 {
+		return 1
+	}
+
+	return configuration.Jobs
+}
+
+// GetLockWaitTimeout returns the number of seconds pg_repack waits to
+// acquire the locks on a table
+func (configuration *RepackConfiguration) GetLockWaitTimeout() int {
+	if configuration.LockWaitTimeout <= 0 {
+		return DefaultRepackLockWaitTimeout
+	}
+
+	return configuration.LockWaitTimeout
+}
+
+// GetMaxReplicationLag returns the maximum replication lag, in bytes,
+// tolerated when starting a scheduled execution of pg_repack
+func (configuration *RepackConfiguration) GetMaxReplicationLag() int64 {
+	if configuration.MaxReplicationLag == "" {
+		return DefaultRepackMaxReplicationLag
+	}
+
+	maxReplicationLag, err := resource.ParseQuantity(configuration.MaxReplicationLag)
+	if err != nil {
+		return DefaultRepackMaxReplicationLag
+	}
+
+	return maxReplicationLag.Value()
+}
+
 // ShouldCreateApplicationSecret returns true if for this cluster,
 // during the bootstrap phase, we need to create a secret to store application credentials
 func (cluster *Cluster) ShouldCreateApplicationSecret() bool {
@@ -880,6 +1265,22 @@ func (cluster *Cluster) ShouldPgBaseBackupCreateApplicationDatabase() bool {
 	return pgBaseBackupParameters.Owner != "" && pgBaseBackupParameters.Database != ""
 }
 
+// GetRecoveryTargetAction returns the action to take once the recovery
+// target of the cluster is reached, defaulting to `promote`
+func (cluster *Cluster) GetRecoveryTargetAction() RecoveryTargetAction {
+	if cluster.Spec.Bootstrap == nil || cluster.Spec.Bootstrap.Recovery == nil {
+		return RecoveryTargetActionPromote
+	}
+
+	return cluster.Spec.Bootstrap.Recovery.RecoveryTarget.GetTargetAction()
+}
+
+// IsRecoveryPaused checks whether the recovery of the cluster
+// is paused at the recovery target, waiting to be promoted
+func (cluster *Cluster) IsRecoveryPaused() bool {
+	return meta.IsStatusConditionTrue(cluster.Status.Conditions, string(ConditionRecoveryPaused))
+}
+
 // ShouldRecoveryCreateApplicationDatabase returns true if the application database needs to be created during the
 // recovery job
 func (cluster *Cluster) ShouldRecoveryCreateApplicationDatabase() bool {
@@ -910,6 +1311,14 @@ func (cluster *Cluster) ShouldCreateWalArchiveVolume() bool {
 	return cluster.Spec.WalStorage != nil
 }
 
+// ShouldUpgradeMajorVersionInPlace returns true if the data directory must
+// be upgraded with pg_upgrade when the image is changed to a newer major
+// version of PostgreSQL
+func (cluster *Cluster) ShouldUpgradeMajorVersionInPlace() bool {
+	return cluster.Spec.PostgresConfiguration.MajorUpgrade != nil &&
+		cluster.Spec.PostgresConfiguration.MajorUpgrade.Strategy == MajorUpgradeStrategyPgUpgrade
+}
+
 // ShouldPromoteFromReplicaCluster returns true if the cluster should promote
 func (cluster *Cluster) ShouldPromoteFromReplicaCluster() bool {
 	// If there's no replica cluster configuration there's no
@@ -1008,6 +1417,139 @@ func (cluster Cluster) GetSlotNameFromInstanceName(instanceName string) string {
 	return cluster.Spec.ReplicationSlots.HighAvailability.GetSlotNameFromInstanceName(instanceName)
 }
 
+// UsesHAReplicationSlots checks whether the cluster uses the high
+// availability replication slots
+func (cluster *Cluster) UsesHAReplicationSlots() bool {
+	return cluster.Spec.ReplicationSlots != nil &&
+		cluster.Spec.ReplicationSlots.HighAvailability != nil &&
+		cluster.Spec.ReplicationSlots.HighAvailability.GetEnabled()
+}
+
+// GetRequiredWalSenders gets the number of WAL senders needed
+// to stream the WAL to every replica of the cluster
+func (cluster *Cluster) GetRequiredWalSenders() int {
+	return max(cluster.Spec.Instances-1, 0)
+}
+
+// GetRequiredReplicationSlots gets the number of replication slots
+// needed by the high availability replication slots, if used
+func (cluster *Cluster) GetRequiredReplicationSlots() int {
+	if !cluster.UsesHAReplicationSlots() {
+		return 0
+	}
+
+	return max(cluster.Spec.Instances-1, 0)
+}
+
+// sizeTierDefinition is the sizing applied to the instances of a cluster
+// using a size tier
+type sizeTierDefinition struct {
+	cpu        string
+	memory     string
+	parameters map[string]string
+}
+
+// sizeTiers contains the definition of every size tier. The memory-related
+// parameters follow the usual rules of thumb: shared_buffers is a quarter
+// of the memory, and effective_cache_size is three quarters of it
+var sizeTiers = map[SizeTier]sizeTierDefinition{
+	SizeTierSmall: {
+		cpu:    "1",
+		memory: "2Gi",
+		parameters: map[string]string{
+			"shared_buffers":       "512MB",
+			"effective_cache_size": "1536MB",
+			"maintenance_work_mem": "128MB",
+			"work_mem":             "8MB",
+		},
+	},
+	SizeTierMedium: {
+		cpu:    "2",
+		memory: "8Gi",
+		parameters: map[string]string{
+			"shared_buffers":       "2GB",
+			"effective_cache_size": "6GB",
+			"maintenance_work_mem": "512MB",
+			"work_mem":             "16MB",
+		},
+	},
+	SizeTierLarge: {
+		cpu:    "4",
+		memory: "32Gi",
+		parameters: map[string]string{
+			"shared_buffers":       "8GB",
+			"effective_cache_size": "24GB",
+			"maintenance_work_mem": "2GB",
+			"work_mem":             "32MB",
+		},
+	},
+}
+
+// GetResources gets the resource requirements of every generated Pod:
+// the ones explicitly set in the spec or, when missing, the ones of
+// the size tier of the cluster
+func (cluster *Cluster) GetResources() corev1.ResourceRequirements {
+	resources := cluster.Spec.Resources
+	if len(resources.Requests) > 0 || len(resources.Limits) > 0 {
+		return resources
+	}
+
+	tier, ok := sizeTiers[cluster.Spec.SizeTier]
+	if !ok {
+		return resources
+	}
+
+	tierResources := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(tier.cpu),
+		corev1.ResourceMemory: resource.MustParse(tier.memory),
+	}
+	return corev1.ResourceRequirements{
+		Requests: tierResources,
+		Limits:   tierResources.DeepCopy(),
+	}
+}
+
+// GetSizeTierParameters gets the PostgreSQL parameters derived from the
+// size tier of the cluster, nil if no size tier is set
+func (cluster *Cluster) GetSizeTierParameters() map[string]string {
+	tier, ok := sizeTiers[cluster.Spec.SizeTier]
+	if !ok {
+		return nil
+	}
+
+	return maps.Clone(tier.parameters)
+}
+
+// GetMinWalSenders gets the minimum value of `max_wal_senders` that is
+// suggested for the topology of the cluster, including some headroom
+func (cluster *Cluster) GetMinWalSenders() int {
+	return cluster.GetRequiredWalSenders() + ReplicationConnectionsHeadroom
+}
+
+// GetMinReplicationSlots gets the minimum value of `max_replication_slots`
+// that is suggested for the topology of the cluster, including some headroom
+func (cluster *Cluster) GetMinReplicationSlots() int {
+	return cluster.GetRequiredReplicationSlots() + ReplicationConnectionsHeadroom
+}
+
+// GetSourceSlotName returns the name of the replication slot used by the
+// designated primary of a replica cluster to stream from the source.
+// It returns an empty string if no replication slot prefix has been set
+// or the cluster is not streaming from an external cluster
+func (cluster Cluster) GetSourceSlotName() string {
+	if !cluster.IsReplica() || cluster.Spec.ReplicationSlots == nil || cluster.Spec.ReplicationSlots.Prefix == "" {
+		return ""
+	}
+
+	source, found := cluster.ExternalCluster(cluster.Spec.ReplicaCluster.Source)
+	if !found || len(source.ConnectionParameters) == 0 {
+		return ""
+	}
+
+	slotName := cluster.Spec.ReplicationSlots.Prefix + cluster.Name
+	return slotNameNegativeRegex.ReplaceAllString(strings.ToLower(slotName), "_")
+}
+
 // GetBarmanEndpointCAForReplicaCluster checks if this is a replica cluster which needs barman endpoint CA
 func (cluster Cluster) GetBarmanEndpointCAForReplicaCluster() *SecretKeySelector {
 	if !cluster.IsReplica() {
@@ -1040,6 +1582,13 @@ func (cluster *Cluster) GetClusterAltDNSNames() []string {
 		buildServiceNames(cluster.GetServiceReadOnlyName(), cluster.IsReadOnlyServiceEnabled()),
 	)
 
+	// Every instance is published under the domain of the headless service
+	if cluster.IsHeadlessServiceEnabled() {
+		for _, name := range buildServiceNames(cluster.GetServiceHeadlessName(), true) {
+			altDNSNames = append(altDNSNames, "*."+name)
+		}
+	}
+
 	if cluster.Spec.Managed != nil && cluster.Spec.Managed.Services != nil {
 		for _, service := range cluster.Spec.Managed.Services.Additional {
 			altDNSNames = append(altDNSNames, buildServiceNames(service.ServiceTemplate.ObjectMeta.Name, true)...)
@@ -1097,6 +1646,9 @@ func (cluster *Cluster) UsesConfigMap(config string) (ok bool) {
 	if _, ok := cluster.Status.ConfigMapResourceVersion.Metrics[config]; ok {
 		return true
 	}
+	if _, ok := cluster.Status.ConfigMapResourceVersion.IncludedConfiguration[config]; ok {
+		return true
+	}
 	return false
 }
 
@@ -1200,6 +1752,42 @@ func (cluster *Cluster) SetInheritedDataAndOwnership(obj *metav1.ObjectMeta) {
 	utils.SetAsOwnedBy(obj, cluster.ObjectMeta, cluster.TypeMeta)
 }
 
+// SetDefaultServiceMetadataAndOwnership sets the cluster as owner of the
+// passed default service, and then sets all the needed annotations and
+// labels, including the ones requested for the default services. The keys
+// of the latter are tracked in an annotation, so that they can be removed
+// from the service once they are removed from the cluster
+func (cluster *Cluster) SetDefaultServiceMetadataAndOwnership(obj *metav1.ObjectMeta) {
+	operatorLabels := maps.Clone(obj.Labels)
+	operatorAnnotations := maps.Clone(obj.Annotations)
+
+	cluster.SetInheritedData(obj)
+	if metadata := cluster.GetDefaultServicesMetadata(); metadata != nil {
+		maps.Copy(obj.Labels, metadata.Labels)
+		maps.Copy(obj.Annotations, metadata.Annotations)
+
+		// The metadata set by the operator takes precedence
+		maps.Copy(obj.Labels, operatorLabels)
+		maps.Copy(obj.Annotations, operatorAnnotations)
+		utils.LabelClusterName(obj, cluster.GetName())
+		utils.SetOperatorVersion(obj, versions.Version)
+
+		var keys utils.DefaultServicesMetadataKeys
+		for key, value := range metadata.Labels {
+			if obj.Labels[key] == value {
+				keys.Labels = append(keys.Labels, key)
+			}
+		}
+		for key, value := range metadata.Annotations {
+			if obj.Annotations[key] == value {
+				keys.Annotations = append(keys.Annotations, key)
+			}
+		}
+		utils.SetDefaultServicesMetadataKeys(obj, keys)
+	}
+	utils.SetAsOwnedBy(obj, cluster.ObjectMeta, cluster.TypeMeta)
+}
+
 // SetInheritedData sets all the needed annotations and labels
 func (cluster *Cluster) SetInheritedData(obj *metav1.ObjectMeta) {
 	utils.InheritAnnotations(obj, cluster.Annotations, cluster.GetFixedInheritedAnnotations(), configuration.Current)
@@ -1208,6 +1796,12 @@ func (cluster *Cluster) SetInheritedData(obj *metav1.ObjectMeta) {
 	utils.SetOperatorVersion(obj, versions.Version)
 }
 
+// IsSwitchoverBlockedByBackups checks whether the switchovers need to be
+// deferred while a backup is being taken
+func (cluster *Cluster) IsSwitchoverBlockedByBackups() bool {
+	return cluster.Spec.Backup != nil && cluster.Spec.Backup.BlockSwitchover
+}
+
 // ShouldForceLegacyBackup if present takes a backup without passing the name argument even on barman version 3.3.0+.
 // This is needed to test both backup system in the E2E suite
 func (cluster *Cluster) ShouldForceLegacyBackup() bool {
@@ -1264,6 +1858,190 @@ func (backupConfiguration *BackupConfiguration) IsBarmanBackupConfigured() bool
 		backupConfiguration.BarmanObjectStore.BarmanCredentials.ArePopulated()
 }
 
+// minRecoveryWindowRegex matches a recovery window expressed
+// in the same form of the retention policy
+var minRecoveryWindowRegex = regexp.MustCompile(`^([1-9][0-9]*)([dwm])$`)
+
+// GetMinRecoveryWindow returns the minimum recovery window requested for
+// the cluster, or zero when not set
+func (backupConfiguration *BackupConfiguration) GetMinRecoveryWindow() time.Duration {
+	if backupConfiguration == nil {
+		return 0
+	}
+
+	matches := minRecoveryWindowRegex.FindStringSubmatch(backupConfiguration.MinRecoveryWindow)
+	if len(matches) < 3 {
+		return 0
+	}
+
+	value, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0
+	}
+
+	day := 24 * time.Hour
+	unit := map[string]time.Duration{
+		"d": day,
+		"w": 7 * day,
+		"m": 30 * day,
+	}[matches[2]]
+	return time.Duration(value) * unit
+}
+
+// DefaultArchiveVerificationInterval is the default minimum interval
+// between two verifications of the WAL archive
+const DefaultArchiveVerificationInterval = 5 * time.Minute
+
+// GetInterval returns the minimum interval between two verifications
+// of the WAL archive
+func (configuration *ArchiveVerificationConfiguration) GetInterval() time.Duration {
+	if configuration == nil || configuration.Interval == nil {
+		return DefaultArchiveVerificationInterval
+	}
+	return configuration.Interval.Duration
+}
+
+// GetWALObjectStore returns the object store where the WAL files are
+// archived, which is the one containing the base backups unless a separate
+// one is configured
+func (backupConfiguration *BackupConfiguration) GetWALObjectStore() *BarmanObjectStoreConfiguration {
+	if backupConfiguration == nil {
+		return nil
+	}
+	return getWALObjectStore(backupConfiguration.BarmanObjectStore, backupConfiguration.WALObjectStore)
+}
+
+// destinationPathTemplateData is the data that can be referenced
+// in the destination path of an object store
+type destinationPathTemplateData struct {
+	Namespace   string
+	ClusterName string
+}
+
+// expandDestinationPath expands the Go template placeholders, such as
+// `{{.Namespace}}` and `{{.ClusterName}}`, in the destination path of an
+// object store
+func expandDestinationPath(destinationPath string, data destinationPathTemplateData) (string, error) {
+	return expandTemplate("destinationPath", destinationPath, data)
+}
+
+// expandTemplate expands the Go template placeholders in the passed text
+func expandTemplate(name string, text string, data any) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var result strings.Builder
+	if err := tmpl.Execute(&result, data); err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
+
+// GetBackupConfiguration gets the backup configuration of the cluster, with
+// the placeholders in the destination paths of the object stores expanded
+// using the metadata of the cluster
+func (cluster *Cluster) GetBackupConfiguration() *BackupConfiguration {
+	if cluster.Spec.Backup == nil {
+		return nil
+	}
+
+	data := destinationPathTemplateData{
+		Namespace:   cluster.Namespace,
+		ClusterName: cluster.Name,
+	}
+	result := cluster.Spec.Backup.DeepCopy()
+	for _, objectStore := range []*BarmanObjectStoreConfiguration{result.BarmanObjectStore, result.WALObjectStore} {
+		if objectStore == nil {
+			continue
+		}
+
+		// The destination path is validated by the webhook, and
+		// is kept as is in the unlikely case it can't be expanded
+		if destinationPath, err := expandDestinationPath(objectStore.DestinationPath, data); err == nil {
+			objectStore.DestinationPath = destinationPath
+		}
+	}
+	return result
+}
+
+// hbaRuleTemplateData is the data that can be referenced in the
+// pg_hba rules of a cluster
+type hbaRuleTemplateData struct {
+	ReplicationUser     string
+	ApplicationUser     string
+	ApplicationDatabase string
+}
+
+// expandHBARule expands the Go template placeholders, such as
+// `{{.ReplicationUser}}` and `{{.ApplicationUser}}`, in the passed
+// pg_hba rule
+func (cluster *Cluster) expandHBARule(rule string) (string, error) {
+	return expandTemplate("pg_hba", rule, hbaRuleTemplateData{
+		ReplicationUser:     StreamingReplicationUser,
+		ApplicationUser:     cluster.GetApplicationDatabaseOwner(),
+		ApplicationDatabase: cluster.GetApplicationDatabaseName(),
+	})
+}
+
+// expandHBARules expands the placeholders in the passed pg_hba rules
+func (cluster *Cluster) expandHBARules(rules []string) ([]string, error) {
+	result := make([]string, len(rules))
+	for idx, rule := range rules {
+		expandedRule, err := cluster.expandHBARule(rule)
+		if err != nil {
+			return nil, err
+		}
+		result[idx] = expandedRule
+	}
+	return result, nil
+}
+
+// GetPgHBA returns the pg_hba rules following the fixed rules set by
+// the operator, with the placeholders expanded. The rules are validated
+// by the webhook, and are kept as is in the unlikely case they can't
+// be expanded
+func (cluster *Cluster) GetPgHBA() []string {
+	rules, err := cluster.expandHBARules(cluster.Spec.PostgresConfiguration.PgHBA)
+	if err != nil {
+		return cluster.Spec.PostgresConfiguration.PgHBA
+	}
+	return rules
+}
+
+// GetPgHBAPrepend returns the pg_hba rules preceding the fixed rules
+// set by the operator, with the placeholders expanded
+func (cluster *Cluster) GetPgHBAPrepend() []string {
+	rules, err := cluster.expandHBARules(cluster.Spec.PostgresConfiguration.PgHBAPrepend)
+	if err != nil {
+		return cluster.Spec.PostgresConfiguration.PgHBAPrepend
+	}
+	return rules
+}
+
+// getWALObjectStore returns the WAL object store, if set, defaulting its
+// server name to the one of the base backups object store, or the base
+// backups object store otherwise
+func getWALObjectStore(
+	barmanObjectStore *BarmanObjectStoreConfiguration,
+	walObjectStore *BarmanObjectStoreConfiguration,
+) *BarmanObjectStoreConfiguration {
+	if barmanObjectStore == nil || walObjectStore == nil {
+		return barmanObjectStore
+	}
+
+	result := walObjectStore.DeepCopy()
+	if result.ServerName == "" {
+		result.ServerName = barmanObjectStore.ServerName
+	}
+	return result
+}
+
 // IsBarmanEndpointCASet returns true if we have a CA bundle for the endpoint
 // false otherwise
 func (backupConfiguration *BackupConfiguration) IsBarmanEndpointCASet() bool {
@@ -1362,6 +2140,66 @@ func (cluster *Cluster) IsReadOnlyServiceEnabled() bool {
 	return !slices.Contains(cluster.Spec.Managed.Services.DisabledDefaultServices, ServiceSelectorTypeRO)
 }
 
+// IsSwitchoverOnShutdownEnabled checks if the primary instance requests a
+// switchover before being stopped
+func (cluster *Cluster) IsSwitchoverOnShutdownEnabled() bool {
+	return cluster.Spec.SwitchoverOnShutdown != nil && cluster.Spec.SwitchoverOnShutdown.Enabled
+}
+
+// IsHeadlessServiceEnabled checks if the headless service, publishing every
+// instance under a stable DNS name, is enabled for the cluster
+func (cluster *Cluster) IsHeadlessServiceEnabled() bool {
+	if cluster.Spec.Managed == nil || cluster.Spec.Managed.Services == nil ||
+		cluster.Spec.Managed.Services.Headless == nil {
+		return false
+	}
+
+	return cluster.Spec.Managed.Services.Headless.Enabled
+}
+
+// GetReadWriteServiceExternalDNSHostname returns the host name ExternalDNS
+// should publish for the read-write service, or an empty string if not set
+func (cluster *Cluster) GetReadWriteServiceExternalDNSHostname() string {
+	if cluster.Spec.Managed == nil || cluster.Spec.Managed.Services == nil ||
+		cluster.Spec.Managed.Services.RW == nil {
+		return ""
+	}
+
+	return cluster.Spec.Managed.Services.RW.ExternalDNSHostname
+}
+
+// GetDefaultServicesMetadata returns the labels and annotations requested
+// for the default services, or nil if not set
+func (cluster *Cluster) GetDefaultServicesMetadata() *EmbeddedObjectMetadata {
+	if cluster.Spec.Managed == nil || cluster.Spec.Managed.Services == nil {
+		return nil
+	}
+
+	return cluster.Spec.Managed.Services.DefaultServicesMetadata
+}
+
+// ShouldReadOnlyServiceFallbackToPrimary returns true when the read-only
+// service needs to include the primary, as requested by the user when no
+// replica is ready
+func (cluster *Cluster) ShouldReadOnlyServiceFallbackToPrimary() bool {
+	if cluster.Spec.Managed == nil || cluster.Spec.Managed.Services == nil ||
+		cluster.Spec.Managed.Services.RO == nil || !cluster.Spec.Managed.Services.RO.FallbackToPrimary {
+		return false
+	}
+
+	if cluster.Status.CurrentPrimary == "" {
+		return false
+	}
+
+	for _, podName := range cluster.Status.InstancesStatus[PodHealthy] {
+		if podName != cluster.Status.CurrentPrimary {
+			return false
+		}
+	}
+
+	return true
+}
+
 // GetRecoverySourcePlugin returns the configuration of the plugin being
 // the recovery source of the cluster. If no such plugin have been configured,
 // nil is returned
@@ -1441,6 +2279,36 @@ func (target *RecoveryTarget) BuildPostgresOptions() string {
 	} else {
 		result += "recovery_target_inclusive = true\n"
 	}
+	if target.GetTargetAction() != RecoveryTargetActionPromote {
+		// The recovery job stops the instance by itself when the target
+		// action is `shutdown`, as PostgreSQL would not be able to resume
+		// the recovery once promoted otherwise
+		result += "recovery_target_action = pause\n"
+	}
 
 	return result
 }
+
+// GetTargetAction returns the action to take once the
+// recovery target is reached, defaulting to `promote`
+func (target *RecoveryTarget) GetTargetAction() RecoveryTargetAction {
+	if target == nil || target.TargetAction == "" {
+		return RecoveryTargetActionPromote
+	}
+
+	return target.TargetAction
+}
+
+// HasTarget checks whether a point where the recovery
+// should stop has been specified
+func (target *RecoveryTarget) HasTarget() bool {
+	if target == nil {
+		return false
+	}
+
+	return target.TargetXID != "" ||
+		target.TargetName != "" ||
+		target.TargetLSN != "" ||
+		target.TargetTime != "" ||
+		(target.TargetImmediate != nil && *target.TargetImmediate)
+}
@@ -167,6 +167,27 @@ var _ = Describe("Bootstrap via initdb", func() {
 		Expect(cluster.GetApplicationDatabaseName()).To(Equal("appDB"))
 	})
 
+	It("gets the application database connection limit from the initdb section", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database:        "appDB",
+						Owner:           "appOwner",
+						ConnectionLimit: ptr.To(25),
+					},
+				},
+			},
+		}
+		Expect(cluster.GetApplicationDatabaseConnectionLimit()).To(HaveValue(Equal(25)))
+
+		cluster.Spec.Bootstrap.InitDB.ConnectionLimit = nil
+		Expect(cluster.GetApplicationDatabaseConnectionLimit()).To(BeNil())
+
+		cluster.Spec.Bootstrap = nil
+		Expect(cluster.GetApplicationDatabaseConnectionLimit()).To(BeNil())
+	})
+
 	It("will run post application sql refs if specified for secrets", func() {
 		cluster := Cluster{
 			ObjectMeta: metav1.ObjectMeta{
@@ -445,6 +466,68 @@ var _ = Describe("external cluster list", func() {
 		Expect(server2.GetServerName()).To(BeEquivalentTo("testServer2"), "default server name")
 	})
 
+	It("returns the object store containing the WAL files", func() {
+		server := ExternalCluster{
+			Name: "origin",
+			BarmanObjectStore: &BarmanObjectStoreConfiguration{
+				DestinationPath: "s3://data/",
+				ServerName:      "origin-server",
+			},
+		}
+		Expect(server.GetWALObjectStore()).To(BeIdenticalTo(server.BarmanObjectStore))
+
+		server.WALObjectStore = &BarmanObjectStoreConfiguration{
+			DestinationPath: "s3://wal/",
+		}
+		walObjectStore := server.GetWALObjectStore()
+		Expect(walObjectStore.DestinationPath).To(Equal("s3://wal/"))
+		Expect(walObjectStore.ServerName).To(Equal("origin-server"))
+		Expect(server.WALObjectStore.ServerName).To(BeEmpty())
+
+		server.WALObjectStore.ServerName = "origin-wal"
+		Expect(server.GetWALObjectStore().ServerName).To(Equal("origin-wal"))
+
+		var backupConfiguration *BackupConfiguration
+		Expect(backupConfiguration.GetWALObjectStore()).To(BeNil())
+	})
+
+	It("expands the placeholders in the destination path of the object stores", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "team-a"},
+		}
+		Expect(cluster.GetBackupConfiguration()).To(BeNil())
+
+		cluster.Spec.Backup = &BackupConfiguration{
+			BarmanObjectStore: &BarmanObjectStoreConfiguration{
+				DestinationPath: "s3://backups/{{.Namespace}}/{{.ClusterName}}/",
+				ServerName:      "{{.ClusterName}}",
+			},
+			WALObjectStore: &BarmanObjectStoreConfiguration{
+				DestinationPath: "s3://wal/{{ .Namespace }}/",
+			},
+		}
+		backupConfiguration := cluster.GetBackupConfiguration()
+		Expect(backupConfiguration.BarmanObjectStore.DestinationPath).To(Equal("s3://backups/team-a/cluster-example/"))
+		Expect(backupConfiguration.BarmanObjectStore.ServerName).To(Equal("{{.ClusterName}}"))
+		Expect(backupConfiguration.GetWALObjectStore().DestinationPath).To(Equal("s3://wal/team-a/"))
+		Expect(cluster.Spec.Backup.BarmanObjectStore.DestinationPath).To(
+			Equal("s3://backups/{{.Namespace}}/{{.ClusterName}}/"), "the spec is not modified")
+	})
+
+	It("keeps the destination paths without placeholders as they are", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "team-a"},
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						DestinationPath: "s3://backups/",
+					},
+				},
+			},
+		}
+		Expect(cluster.GetBackupConfiguration().BarmanObjectStore.DestinationPath).To(Equal("s3://backups/"))
+	})
+
 	It("return the correct secrets number", func() {
 		Expect(emptyCluster.GetExternalClusterSecrets().ToList()).To(BeEmpty())
 		Expect(cluster.GetExternalClusterSecrets().ToList()).To(BeEmpty())
@@ -540,6 +623,15 @@ var _ = Describe("look up for secrets", Ordered, func() {
 			Expect(assertServiceNamesPresent(namesSet, "two")).To(BeEmpty(),
 				"missing service name")
 		})
+
+		It("should generate the names of the instances when the headless service is enabled", func() {
+			cluster.Spec.Managed.Services.Headless = &HeadlessServiceConfiguration{Enabled: true}
+			namesSet := stringset.From(cluster.GetClusterAltDNSNames())
+			Expect(namesSet.Len()).To(Equal(24))
+			Expect(namesSet.Has("*." + cluster.GetServiceHeadlessName())).To(BeTrue())
+			Expect(namesSet.Has(
+				fmt.Sprintf("*.%s.%s.svc", cluster.GetServiceHeadlessName(), cluster.Namespace))).To(BeTrue())
+		})
 	})
 })
 
@@ -935,6 +1027,57 @@ var _ = Describe("Fencing annotation", func() {
 			Expect(cluster.IsInstanceFenced("one")).To(BeFalse())
 		})
 	})
+
+	When("the replicas are fenced by role", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					utils.FencedInstanceAnnotation: "[\"role=replica\"]",
+				},
+			},
+			Status: ClusterStatus{
+				CurrentPrimary: "one",
+			},
+		}
+
+		It("fences every instance except the current primary", func() {
+			Expect(cluster.IsInstanceFenced("one")).To(BeFalse())
+			Expect(cluster.IsInstanceFenced("two")).To(BeTrue())
+			Expect(cluster.IsInstanceFenced("three")).To(BeTrue())
+		})
+
+		It("follows the current primary after a failover", func() {
+			failedOver := cluster.DeepCopy()
+			failedOver.Status.CurrentPrimary = "two"
+			Expect(failedOver.IsInstanceFenced("one")).To(BeTrue())
+			Expect(failedOver.IsInstanceFenced("two")).To(BeFalse())
+		})
+
+		It("doesn't fence anything until the primary is known", func() {
+			noPrimary := cluster.DeepCopy()
+			noPrimary.Status.CurrentPrimary = ""
+			Expect(noPrimary.IsInstanceFenced("one")).To(BeFalse())
+		})
+	})
+
+	When("the primary is fenced by role", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					utils.FencedInstanceAnnotation: "[\"role=primary\",\"three\"]",
+				},
+			},
+			Status: ClusterStatus{
+				CurrentPrimary: "one",
+			},
+		}
+
+		It("fences the current primary and the listed instances", func() {
+			Expect(cluster.IsInstanceFenced("one")).To(BeTrue())
+			Expect(cluster.IsInstanceFenced("two")).To(BeFalse())
+			Expect(cluster.IsInstanceFenced("three")).To(BeTrue())
+		})
+	})
 })
 
 var _ = Describe("Replication slots names for instances", func() {
@@ -1092,6 +1235,60 @@ var _ = Describe("Cluster ShouldRecoveryCreateApplicationDatabase", func() {
 	})
 })
 
+var _ = Describe("Recovery target action", func() {
+	It("defaults to promote", func() {
+		cluster := &Cluster{}
+		Expect(cluster.GetRecoveryTargetAction()).To(Equal(RecoveryTargetActionPromote))
+
+		cluster.Spec.Bootstrap = &BootstrapConfiguration{Recovery: &BootstrapRecovery{}}
+		Expect(cluster.GetRecoveryTargetAction()).To(Equal(RecoveryTargetActionPromote))
+		Expect(cluster.Spec.Bootstrap.Recovery.RecoveryTarget.BuildPostgresOptions()).To(BeEmpty())
+	})
+
+	It("leaves the promotion to the default configuration", func() {
+		target := &RecoveryTarget{
+			TargetLSN:    "1/1",
+			TargetAction: RecoveryTargetActionPromote,
+		}
+		Expect(target.BuildPostgresOptions()).ToNot(ContainSubstring("recovery_target_action"))
+	})
+
+	It("pauses the recovery when the target action is pause or shutdown", func() {
+		target := &RecoveryTarget{
+			TargetLSN:    "1/1",
+			TargetAction: RecoveryTargetActionPause,
+		}
+		Expect(target.BuildPostgresOptions()).To(ContainSubstring("recovery_target_action = pause\n"))
+
+		target.TargetAction = RecoveryTargetActionShutdown
+		Expect(target.BuildPostgresOptions()).To(ContainSubstring("recovery_target_action = pause\n"))
+	})
+
+	It("detects when a recovery target is specified", func() {
+		Expect((*RecoveryTarget)(nil).HasTarget()).To(BeFalse())
+		Expect((&RecoveryTarget{TargetTLI: "latest"}).HasTarget()).To(BeFalse())
+		Expect((&RecoveryTarget{TargetImmediate: ptr.To(false)}).HasTarget()).To(BeFalse())
+		Expect((&RecoveryTarget{TargetImmediate: ptr.To(true)}).HasTarget()).To(BeTrue())
+		Expect((&RecoveryTarget{TargetName: "before-migration"}).HasTarget()).To(BeTrue())
+	})
+
+	It("detects a recovery paused at the target", func() {
+		cluster := &Cluster{}
+		Expect(cluster.IsRecoveryPaused()).To(BeFalse())
+
+		cluster.Status.Conditions = []metav1.Condition{{
+			Type:   string(ConditionRecoveryPaused),
+			Status: metav1.ConditionTrue,
+			Reason: string(ConditionReasonRecoveryTargetReached),
+		}}
+		Expect(cluster.IsRecoveryPaused()).To(BeTrue())
+
+		cluster.Status.Conditions[0].Status = metav1.ConditionFalse
+		cluster.Status.Conditions[0].Reason = string(ConditionReasonPromotionRequested)
+		Expect(cluster.IsRecoveryPaused()).To(BeFalse())
+	})
+})
+
 var _ = Describe("Ephemeral volume size limits", func() {
 	It("doesn't panic if the specification is nil", func() {
 		var spec *EphemeralVolumesSizeLimitConfiguration
@@ -1557,6 +1754,98 @@ var _ = Describe("Cluster Managed Service Enablement", func() {
 			Expect(cluster.IsReadOnlyServiceEnabled()).To(BeFalse())
 		})
 	})
+
+	Describe("ShouldReadOnlyServiceFallbackToPrimary", func() {
+		BeforeEach(func() {
+			cluster.Status.CurrentPrimary = "cluster-1"
+			cluster.Status.InstancesStatus = map[PodStatus][]string{
+				PodHealthy: {"cluster-1"},
+				PodFailed:  {"cluster-2", "cluster-3"},
+			}
+		})
+
+		It("should return false if the fallback is not enabled", func() {
+			Expect(cluster.ShouldReadOnlyServiceFallbackToPrimary()).To(BeFalse())
+
+			cluster.Spec.Managed = &ManagedConfiguration{
+				Services: &ManagedServices{RO: &ReadOnlyServiceConfiguration{}},
+			}
+			Expect(cluster.ShouldReadOnlyServiceFallbackToPrimary()).To(BeFalse())
+		})
+
+		It("should return true only when no replica is ready", func() {
+			cluster.Spec.Managed = &ManagedConfiguration{
+				Services: &ManagedServices{RO: &ReadOnlyServiceConfiguration{FallbackToPrimary: true}},
+			}
+			Expect(cluster.ShouldReadOnlyServiceFallbackToPrimary()).To(BeTrue())
+
+			cluster.Status.InstancesStatus[PodHealthy] = []string{"cluster-1", "cluster-3"}
+			Expect(cluster.ShouldReadOnlyServiceFallbackToPrimary()).To(BeFalse())
+		})
+
+		It("should return false when there is no primary", func() {
+			cluster.Spec.Managed = &ManagedConfiguration{
+				Services: &ManagedServices{RO: &ReadOnlyServiceConfiguration{FallbackToPrimary: true}},
+			}
+			cluster.Status.CurrentPrimary = ""
+			Expect(cluster.ShouldReadOnlyServiceFallbackToPrimary()).To(BeFalse())
+		})
+	})
+
+	Describe("SetDefaultServiceMetadataAndOwnership", func() {
+		BeforeEach(func() {
+			cluster.Name = "cluster-example"
+		})
+
+		It("should only set the inherited data when no metadata is requested", func() {
+			var meta metav1.ObjectMeta
+			cluster.SetDefaultServiceMetadataAndOwnership(&meta)
+			Expect(meta.Labels).To(HaveKeyWithValue(utils.ClusterLabelName, "cluster-example"))
+			Expect(meta.Labels).To(HaveLen(1))
+			Expect(meta.OwnerReferences).To(HaveLen(1))
+		})
+
+		It("should add the requested labels and annotations", func() {
+			cluster.Spec.Managed = &ManagedConfiguration{
+				Services: &ManagedServices{
+					DefaultServicesMetadata: &EmbeddedObjectMetadata{
+						Labels:      map[string]string{"team": "dba"},
+						Annotations: map[string]string{"service.beta.kubernetes.io/aws-load-balancer-type": "nlb"},
+					},
+				},
+			}
+
+			var meta metav1.ObjectMeta
+			cluster.SetDefaultServiceMetadataAndOwnership(&meta)
+			Expect(meta.Labels).To(HaveKeyWithValue("team", "dba"))
+			Expect(meta.Labels).To(HaveKeyWithValue(utils.ClusterLabelName, "cluster-example"))
+			Expect(meta.Annotations).To(HaveKeyWithValue("service.beta.kubernetes.io/aws-load-balancer-type", "nlb"))
+			Expect(meta.Annotations).To(HaveKey(utils.OperatorVersionAnnotationName))
+			Expect(utils.GetDefaultServicesMetadataKeys(meta)).To(Equal(utils.DefaultServicesMetadataKeys{
+				Labels:      []string{"team"},
+				Annotations: []string{"service.beta.kubernetes.io/aws-load-balancer-type"},
+			}))
+		})
+
+		It("should not let the requested metadata override the one set by the operator", func() {
+			cluster.Spec.Managed = &ManagedConfiguration{
+				Services: &ManagedServices{
+					DefaultServicesMetadata: &EmbeddedObjectMetadata{
+						Labels:      map[string]string{utils.ClusterLabelName: "another-cluster"},
+						Annotations: map[string]string{utils.ExternalDNSHostnameAnnotationName: "other.example.com"},
+					},
+				},
+			}
+
+			meta := metav1.ObjectMeta{
+				Annotations: map[string]string{utils.ExternalDNSHostnameAnnotationName: "db.example.com"},
+			}
+			cluster.SetDefaultServiceMetadataAndOwnership(&meta)
+			Expect(meta.Labels).To(HaveKeyWithValue(utils.ClusterLabelName, "cluster-example"))
+			Expect(meta.Annotations).To(HaveKeyWithValue(utils.ExternalDNSHostnameAnnotationName, "db.example.com"))
+			Expect(meta.Annotations).ToNot(HaveKey(utils.DefaultServicesMetadataAnnotationName))
+		})
+	})
 })
 
 var _ = Describe("UpdateBackupTimes", func() {
@@ -1682,3 +1971,197 @@ var _ = Describe("UpdateBackupTimes", func() {
 			To(Equal(now))
 	})
 })
+
+var _ = Describe("GetIntegrityCheckInstance", func() {
+	var cluster *Cluster
+
+	BeforeEach(func() {
+		cluster = &Cluster{
+			Spec: ClusterSpec{Managed: &ManagedConfiguration{
+				IntegrityCheck: &IntegrityCheckConfiguration{Schedule: "0 0 3 * * 0"},
+			}},
+			Status: ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+				InstanceNames:  []string{"cluster-example-1", "cluster-example-2", "cluster-example-3"},
+			},
+		}
+	})
+
+	It("returns nothing when the integrity check is not configured", func() {
+		cluster.Spec.Managed = nil
+		Expect(cluster.GetIntegrityCheckInstance()).To(BeEmpty())
+	})
+
+	It("chooses the last replica", func() {
+		Expect(cluster.GetIntegrityCheckInstance()).To(Equal("cluster-example-3"))
+	})
+
+	It("prefers a fenced replica", func() {
+		cluster.Annotations = map[string]string{
+			utils.FencedInstanceAnnotation: `["cluster-example-2"]`,
+		}
+		Expect(cluster.GetIntegrityCheckInstance()).To(Equal("cluster-example-2"))
+	})
+
+	It("never chooses the primary", func() {
+		cluster.Status.InstanceNames = []string{"cluster-example-1"}
+		Expect(cluster.GetIntegrityCheckInstance()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("GetMinRecoveryWindow", func() {
+	DescribeTable("parses the requested minimum recovery window",
+		func(backup *BackupConfiguration, expected time.Duration) {
+			Expect(backup.GetMinRecoveryWindow()).To(Equal(expected))
+		},
+		Entry("no backup configuration", nil, time.Duration(0)),
+		Entry("no minimum recovery window", &BackupConfiguration{}, time.Duration(0)),
+		Entry("days", &BackupConfiguration{MinRecoveryWindow: "3d"}, 3*24*time.Hour),
+		Entry("weeks", &BackupConfiguration{MinRecoveryWindow: "2w"}, 14*24*time.Hour),
+		Entry("months", &BackupConfiguration{MinRecoveryWindow: "1m"}, 30*24*time.Hour),
+	)
+})
+
+var _ = Describe("Size tiers", func() {
+	DescribeTable("derive the memory-related parameters",
+		func(sizeTier SizeTier, sharedBuffers, effectiveCacheSize, maintenanceWorkMem, workMem string) {
+			cluster := Cluster{Spec: ClusterSpec{SizeTier: sizeTier}}
+			Expect(cluster.GetSizeTierParameters()).To(Equal(map[string]string{
+				"shared_buffers":       sharedBuffers,
+				"effective_cache_size": effectiveCacheSize,
+				"maintenance_work_mem": maintenanceWorkMem,
+				"work_mem":             workMem,
+			}))
+		},
+		Entry("small", SizeTierSmall, "512MB", "1536MB", "128MB", "8MB"),
+		Entry("medium", SizeTierMedium, "2GB", "6GB", "512MB", "16MB"),
+		Entry("large", SizeTierLarge, "8GB", "24GB", "2GB", "32MB"),
+	)
+
+	It("fit the shared buffers in the requested memory", func() {
+		for sizeTier := range sizeTiers {
+			cluster := Cluster{Spec: ClusterSpec{SizeTier: sizeTier}}
+			sharedBuffers, err := parsePostgresQuantityValue(cluster.GetSizeTierParameters()["shared_buffers"])
+			Expect(err).ToNot(HaveOccurred())
+			memory := cluster.GetResources().Requests[corev1.ResourceMemory]
+			Expect(memory.Cmp(sharedBuffers)).To(BeNumerically(">", 0))
+		}
+	})
+
+	It("don't set anything without a size tier", func() {
+		cluster := Cluster{}
+		Expect(cluster.GetSizeTierParameters()).To(BeNil())
+		Expect(cluster.GetResources()).To(Equal(corev1.ResourceRequirements{}))
+	})
+
+	It("are overridden by the explicit resources", func() {
+		resources := corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+		}
+		cluster := Cluster{Spec: ClusterSpec{SizeTier: SizeTierSmall, Resources: resources}}
+		Expect(cluster.GetResources()).To(Equal(resources))
+	})
+})
+
+var _ = Describe("Automatic switchback", func() {
+	It("uses the default cooldown when not specified", func() {
+		Expect((&AutoSwitchbackConfiguration{}).GetCooldown()).To(Equal(10 * time.Minute))
+		Expect((&AutoSwitchbackConfiguration{Cooldown: ptr.To(int32(30))}).GetCooldown()).
+			To(Equal(30 * time.Second))
+	})
+
+	It("allows the switchback at any time without maintenance windows", func() {
+		Expect((&AutoSwitchbackConfiguration{}).GetMaintenanceWindowDelay(time.Now())).To(BeZero())
+	})
+
+	It("computes the delay until the next maintenance window", func() {
+		configuration := &AutoSwitchbackConfiguration{
+			MaintenanceWindows: []SwitchbackWindow{
+				{Schedule: "0 0 2 * * *", Duration: metav1.Duration{Duration: time.Hour}},
+				{Schedule: "0 0 22 * * *", Duration: metav1.Duration{Duration: time.Hour}},
+			},
+		}
+
+		now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.Local)
+		Expect(configuration.GetMaintenanceWindowDelay(now)).To(Equal(10 * time.Hour))
+
+		now = time.Date(2024, 1, 1, 23, 30, 0, 0, time.Local)
+		Expect(configuration.GetMaintenanceWindowDelay(now)).To(Equal(150 * time.Minute))
+	})
+
+	It("allows the switchback while a maintenance window is open", func() {
+		configuration := &AutoSwitchbackConfiguration{
+			MaintenanceWindows: []SwitchbackWindow{
+				{Schedule: "0 0 2 * * *", Duration: metav1.Duration{Duration: time.Hour}},
+			},
+		}
+
+		now := time.Date(2024, 1, 1, 2, 30, 0, 0, time.Local)
+		Expect(configuration.GetMaintenanceWindowDelay(now)).To(BeZero())
+	})
+})
+
+var _ = Describe("pg_hba rules", func() {
+	var cluster *Cluster
+
+	BeforeEach(func() {
+		cluster = &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{Database: "appdb", Owner: "appuser"},
+				},
+				PostgresConfiguration: PostgresConfiguration{
+					PgHBA: []string{
+						"hostssl {{.ApplicationDatabase}} {{.ApplicationUser}} 10.0.0.0/8 scram-sha-256",
+						"hostssl all all all cert",
+					},
+					PgHBAPrepend: []string{
+						"hostssl replication {{.ReplicationUser}} 192.168.0.0/16 reject",
+					},
+				},
+			},
+		}
+	})
+
+	It("expands the placeholders in the rules", func() {
+		Expect(cluster.GetPgHBA()).To(Equal([]string{
+			"hostssl appdb appuser 10.0.0.0/8 scram-sha-256",
+			"hostssl all all all cert",
+		}))
+		Expect(cluster.GetPgHBAPrepend()).To(Equal([]string{
+			"hostssl replication streaming_replica 192.168.0.0/16 reject",
+		}))
+	})
+
+	It("keeps the rules as they are when they can't be expanded", func() {
+		cluster.Spec.PostgresConfiguration.PgHBA = []string{"host {{.Unknown}} all all md5"}
+		Expect(cluster.GetPgHBA()).To(Equal([]string{"host {{.Unknown}} all all md5"}))
+	})
+})
+
+var _ = Describe("Switchover on shutdown", func() {
+	It("is disabled by default", func() {
+		Expect((&Cluster{}).IsSwitchoverOnShutdownEnabled()).To(BeFalse())
+		Expect((&Cluster{Spec: ClusterSpec{SwitchoverOnShutdown: &SwitchoverOnShutdownConfiguration{}}}).
+			IsSwitchoverOnShutdownEnabled()).To(BeFalse())
+		Expect((&Cluster{Spec: ClusterSpec{SwitchoverOnShutdown: &SwitchoverOnShutdownConfiguration{Enabled: true}}}).
+			IsSwitchoverOnShutdownEnabled()).To(BeTrue())
+	})
+
+	It("leaves the smart shutdown timeout out of the termination grace period", func() {
+		Expect((&Cluster{}).GetSwitchoverOnShutdownTimeout()).To(BeEquivalentTo(1800 - 180))
+		Expect((&Cluster{Spec: ClusterSpec{MaxStopDelay: 300, SmartShutdownTimeout: ptr.To(int32(60))}}).
+			GetSwitchoverOnShutdownTimeout()).To(BeEquivalentTo(240))
+	})
+
+	It("never waits longer than the switchover delay", func() {
+		Expect((&Cluster{Spec: ClusterSpec{MaxSwitchoverDelay: 120}}).
+			GetSwitchoverOnShutdownTimeout()).To(BeEquivalentTo(120))
+	})
+
+	It("ignores a smart shutdown timeout not lower than the stop delay", func() {
+		Expect((&Cluster{Spec: ClusterSpec{MaxStopDelay: 60, SmartShutdownTimeout: ptr.To(int32(60))}}).
+			GetSwitchoverOnShutdownTimeout()).To(BeEquivalentTo(60))
+	})
+})
@@ -58,6 +58,11 @@ const (
 	// service name for every node (including non-ready ones)
 	ServiceAnySuffix = "-any"
 
+	// ServiceHeadlessSuffix is the suffix appended to the cluster name to get
+	// the name of the headless service publishing every node under a stable
+	// DNS name
+	ServiceHeadlessSuffix = "-headless"
+
 	// ServiceReadSuffix is the suffix appended to the cluster name to get the
 	// service name for every ready node that you can use to read data (including the primary)
 	ServiceReadSuffix = "-r"
@@ -127,6 +132,16 @@ const (
 	SnapshotOwnerReferenceCluster SnapshotOwnerReference = "cluster"
 )
 
+// ArchiveVerificationConfiguration configures the periodic verification
+// that the WAL files archived by PostgreSQL are in the object store
+type ArchiveVerificationConfiguration struct {
+	// The minimum interval between two verifications. As each verification
+	// downloads a WAL file from the object store, it can't be shorter
+	// than one minute. Defaults to 5 minutes
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
 // VolumeSnapshotConfiguration represents the configuration for the execution of snapshot backups.
 type VolumeSnapshotConfiguration struct {
 	// Labels are key-value pairs that will be added to .metadata.labels snapshot resources.
@@ -198,6 +213,15 @@ type ImageCatalogRef struct {
 	Major int `json:"major"`
 }
 
+// ImageInfo contains the details of a PostgreSQL image
+type ImageInfo struct {
+	// Image is the name of the image
+	Image string `json:"image"`
+
+	// MajorVersion is the major version of PostgreSQL in the image
+	MajorVersion int `json:"majorVersion"`
+}
+
 // +kubebuilder:validation:XValidation:rule="!(has(self.imageCatalogRef) && has(self.imageName))",message="imageName and imageCatalogRef are mutually exclusive"
 
 // ClusterSpec defines the desired state of Cluster
@@ -275,6 +299,10 @@ type ClusterSpec struct {
 	// +optional
 	ReplicationSlots *ReplicationSlotsConfiguration `json:"replicationSlots,omitempty"`
 
+	// Configuration of the creation of new replicas
+	// +optional
+	Replication *ReplicationConfiguration `json:"replication,omitempty"`
+
 	// Instructions to bootstrap this cluster
 	// +optional
 	Bootstrap *BootstrapConfiguration `json:"bootstrap,omitempty"`
@@ -318,6 +346,11 @@ type ClusterSpec struct {
 	// +optional
 	WalStorage *StorageConfiguration `json:"walStorage,omitempty"`
 
+	// The reaction of the operator when the data volumes are running
+	// out of space
+	// +optional
+	DiskPressure *DiskPressureConfiguration `json:"diskPressure,omitempty"`
+
 	// EphemeralVolumeSource allows the user to configure the source of ephemeral volumes.
 	// +optional
 	EphemeralVolumeSource *corev1.EphemeralVolumeSource `json:"ephemeralVolumeSource,omitempty"`
@@ -343,6 +376,14 @@ type ClusterSpec struct {
 	// +optional
 	SmartShutdownTimeout *int32 `json:"smartShutdownTimeout,omitempty"`
 
+	// The time in seconds a fenced instance allows the active sessions to
+	// complete their work, using a smart shutdown, before stopping them
+	// with a fast shutdown. Default value is 0, meaning that a fenced
+	// instance is immediately stopped with a fast shutdown
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	FencingGracePeriod int32 `json:"fencingGracePeriod,omitempty"`
+
 	// The time in seconds that is allowed for a primary PostgreSQL instance
 	// to gracefully shutdown during a switchover.
 	// Default value is 3600 seconds (1 hour).
@@ -350,6 +391,37 @@ type ClusterSpec struct {
 	// +optional
 	MaxSwitchoverDelay int32 `json:"switchoverDelay,omitempty"`
 
+	// The maximum replay lag, in bytes, that the new primary may have
+	// compared to the current WAL position of the primary when the operator
+	// initiates a switchover. When set, the switchover is deferred until the
+	// new primary catches up. Default: empty, meaning no check is done
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	SwitchoverMaxLagBytes *int64 `json:"switchoverMaxLagBytes,omitempty"`
+
+	// The time in seconds the operator waits for the new primary to catch
+	// up, according to `switchoverMaxLagBytes`, before aborting the
+	// switchover and leaving the current primary in place.
+	// Default value is 300 seconds (5 minutes).
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	SwitchoverCatchUpTimeout int32 `json:"switchoverCatchUpTimeout,omitempty"`
+
+	// Configures the switchover requested by the primary instance before
+	// being stopped, i.e. because its node is being drained
+	// +optional
+	SwitchoverOnShutdown *SwitchoverOnShutdownConfiguration `json:"switchoverOnShutdown,omitempty"`
+
+	// The maximum replay lag, in bytes, that a replica may have compared
+	// to the current WAL position of the primary to be considered
+	// promotable. When set, the operator continuously checks that at least
+	// one replica could be promoted, exposing the `cnpg_promotable_replicas`
+	// metric and the `NoPromotableReplica` condition. Default: empty,
+	// meaning no check is done
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	PromotableReplicaMaxLagBytes *int64 `json:"promotableReplicaMaxLagBytes,omitempty"`
+
 	// The amount of time (in seconds) to wait before triggering a failover
 	// after the primary PostgreSQL instance in the cluster was detected
 	// to be unhealthy
@@ -357,6 +429,35 @@ type ClusterSpec struct {
 	// +optional
 	FailoverDelay int32 `json:"failoverDelay,omitempty"`
 
+	// Configures the ranking of the failover candidates based on the
+	// topology of the nodes, preferring the replicas running in the same
+	// failure domain of the former primary
+	// +optional
+	FailoverTopology *FailoverTopologyConfiguration `json:"failoverTopology,omitempty"`
+
+	// The node where the primary instance should run, and the automatic
+	// switchback to it after the primary has been moved away, i.e. by the
+	// maintenance of the node
+	// +optional
+	PrimaryPreference *PrimaryPreferenceConfiguration `json:"primaryPreference,omitempty"`
+
+	// When enabled (default), a former primary rejoining the cluster as a
+	// replica after pg_rewind removes the WAL segments, partial files and
+	// timeline history files belonging to the timeline that diverged
+	// from the new primary
+	// +kubebuilder:default:=true
+	// +optional
+	CleanupDivergedWALOnRejoin *bool `json:"cleanupDivergedWALOnRejoin,omitempty"`
+
+	// Defines when a freshly bootstrapped cluster is marked as ready:
+	// `ManagedObjects` (default) waits for the managed roles and the
+	// databases to be created on the primary and to be received by every
+	// replica, while `Instances` only waits for every instance to be ready
+	// +kubebuilder:validation:Enum=Instances;ManagedObjects
+	// +kubebuilder:default:=ManagedObjects
+	// +optional
+	BootstrapReadiness BootstrapReadinessPolicy `json:"bootstrapReadiness,omitempty"`
+
 	// LivenessProbeTimeout is the time (in seconds) that is allowed for a PostgreSQL instance
 	// to successfully respond to the liveness probe (default 30).
 	// The Liveness probe failure threshold is derived from this value using the formula:
@@ -380,6 +481,14 @@ type ClusterSpec struct {
 	// +optional
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 
+	// SizeTier sets the resource requests and limits of every generated Pod
+	// and the memory-related PostgreSQL parameters from a predefined tier.
+	// An explicit `resources` section takes precedence over the tier
+	// resources, and the `postgresql.parameters` take precedence over the
+	// tier parameters
+	// +optional
+	SizeTier SizeTier `json:"sizeTier,omitempty"`
+
 	// EphemeralVolumesSizeLimit allows the user to set the limits for the ephemeral
 	// volumes
 	// +optional
@@ -472,6 +581,12 @@ type ClusterSpec struct {
 	// +optional
 	EnablePDB *bool `json:"enablePDB,omitempty"`
 
+	// The retention policy of the completed Jobs created by the operator,
+	// such as the ones bootstrapping or joining an instance. By default,
+	// completed Jobs are deleted as soon as the cluster is healthy
+	// +optional
+	JobRetention *JobRetentionConfiguration `json:"jobRetention,omitempty"`
+
 	// The plugins configuration, containing
 	// any plugin to be loaded with the corresponding configuration
 	// +optional
@@ -514,6 +629,10 @@ const (
 	// PhaseHealthy for a cluster doing nothing
 	PhaseHealthy = "Cluster in healthy state"
 
+	// PhaseHibernated for a cluster whose Pods have been deleted by the
+	// declarative hibernation, keeping the PVCs
+	PhaseHibernated = "Cluster is hibernated"
+
 	// PhaseUnknownPlugin is triggered when the required CNPG-i plugin have not been
 	// loaded still
 	PhaseUnknownPlugin = "Cluster cannot proceed to reconciliation due to an unknown plugin being required"
@@ -543,6 +662,37 @@ const (
 
 	// PhaseCannotCreateClusterObjects is set by the operator when is unable to create cluster resources
 	PhaseCannotCreateClusterObjects = "Unable to create required cluster objects"
+
+	// PhaseWaitingForManagedObjects is a waiting phase that is triggered, during the
+	// initial bootstrap, until the managed objects have been created and replicated
+	PhaseWaitingForManagedObjects = "Waiting for the managed objects to be replicated"
+
+	// PhaseRecoveryValidationFailed is set by the recovery job when one of the
+	// validation queries of the recovered data fails
+	PhaseRecoveryValidationFailed = "Recovery validation failed"
+
+	// PhaseMajorUpgrade is set by the operator while the data directory
+	// of the primary instance is being upgraded to a newer major version
+	PhaseMajorUpgrade = "Upgrading Postgres major version"
+
+	// PhaseMajorUpgradeFailed is set by the operator when the job upgrading
+	// the data directory to a newer major version fails
+	PhaseMajorUpgradeFailed = "Postgres major version upgrade failed"
+)
+
+// BootstrapReadinessPolicy defines when a freshly bootstrapped cluster is
+// marked as ready
+type BootstrapReadinessPolicy string
+
+const (
+	// BootstrapReadinessInstances marks the cluster as ready as soon as
+	// every instance is ready
+	BootstrapReadinessInstances BootstrapReadinessPolicy = "Instances"
+
+	// BootstrapReadinessManagedObjects marks the cluster as ready once
+	// the managed roles and databases have been created on the primary
+	// and received by every replica
+	BootstrapReadinessManagedObjects BootstrapReadinessPolicy = "ManagedObjects"
 )
 
 // EphemeralVolumesSizeLimitConfiguration contains the configuration of the ephemeral
@@ -557,6 +707,21 @@ type EphemeralVolumesSizeLimitConfiguration struct {
 	TemporaryData *resource.Quantity `json:"temporaryData,omitempty"`
 }
 
+// JobRetentionConfiguration defines how long the completed Jobs
+// created by the operator are kept
+type JobRetentionConfiguration struct {
+	// The minimum time a completed Job is kept, starting from its
+	// completion, before being deleted. Default: `0s`
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+
+	// The number of most recently completed Jobs that are kept
+	// regardless of their age. Default: `0`
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	KeepLast int32 `json:"keepLast,omitempty"`
+}
+
 // ServiceAccountTemplate contains the template needed to generate the service accounts
 type ServiceAccountTemplate struct {
 	// Metadata are the metadata to be used for the generated
@@ -564,6 +729,120 @@ type ServiceAccountTemplate struct {
 	Metadata Metadata `json:"metadata"`
 }
 
+// FailoverTopologyConfiguration configures the ranking of the failover
+// candidates based on the failure domain they are running in
+type FailoverTopologyConfiguration struct {
+	// The node label defining the failure domain of the instances.
+	// During a failover, a healthy replica running in the same failure
+	// domain of the former primary is preferred over the replicas in other
+	// failure domains, as long as it is not lagging behind them
+	// +kubebuilder:default:="topology.kubernetes.io/zone"
+	// +optional
+	TopologyKey string `json:"topologyKey,omitempty"`
+}
+
+// PrimaryPreferenceConfiguration defines the node where the primary
+// instance of the cluster should run
+type PrimaryPreferenceConfiguration struct {
+	// The name of the node where the primary instance should run
+	// +kubebuilder:validation:MinLength=1
+	NodeName string `json:"nodeName"`
+
+	// When set, the operator switches the primary back to the instance
+	// running on the preferred node, once the node is schedulable and
+	// ready again and the instance is healthy and streaming from the
+	// current primary
+	// +optional
+	AutoSwitchback *AutoSwitchbackConfiguration `json:"autoSwitchback,omitempty"`
+}
+
+// AutoSwitchbackConfiguration defines when the primary can be switched
+// back to the preferred node
+type AutoSwitchbackConfiguration struct {
+	// The minimum amount of time (in seconds) elapsed since the last
+	// promotion, and since the instance on the preferred node became
+	// ready, before switching back to it (default 600)
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Cooldown *int32 `json:"cooldown,omitempty"`
+
+	// The recurring time windows in which the switchback is allowed.
+	// When empty, the switchback can happen at any time
+	// +optional
+	MaintenanceWindows []SwitchbackWindow `json:"maintenanceWindows,omitempty"`
+}
+
+// SwitchbackWindow is a recurring time window in which the automatic
+// switchback is allowed
+type SwitchbackWindow struct {
+	// The beginning of the window. The schedule does not follow the same format
+	// used in Kubernetes CronJobs as it includes an additional seconds specifier,
+	// see https://pkg.go.dev/github.com/robfig/cron#hdr-CRON_Expression_Format
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// The duration of the window
+	Duration metav1.Duration `json:"duration"`
+}
+
+// FailoverDecision records the context in which the operator selected
+// the instance to be promoted during a failover
+type FailoverDecision struct {
+	// The timestamp when the new primary has been selected
+	DecisionTimestamp string `json:"decisionTimestamp"`
+
+	// The primary instance that was failing
+	FormerPrimary string `json:"formerPrimary"`
+
+	// The instance selected to be promoted
+	SelectedPrimary string `json:"selectedPrimary"`
+
+	// Why the selected instance has been preferred over the other candidates
+	Reason string `json:"reason"`
+
+	// The node label defining the failure domain of the instances, set
+	// when the failover topology has been taken into account
+	// +optional
+	TopologyKey string `json:"topologyKey,omitempty"`
+
+	// The instances considered during the failover
+	// +optional
+	Candidates []FailoverCandidateStatus `json:"candidates,omitempty"`
+}
+
+// FailoverCandidateStatus is the status of an instance at the time
+// of a failover decision
+type FailoverCandidateStatus struct {
+	// The name of the instance
+	Name string `json:"name"`
+
+	// Whether the Pod of the instance was ready
+	Ready bool `json:"ready"`
+
+	// The last WAL location received by the instance
+	// +optional
+	ReceivedLSN string `json:"receivedLSN,omitempty"`
+
+	// The last WAL location replayed by the instance
+	// +optional
+	ReplayLSN string `json:"replayLSN,omitempty"`
+
+	// The amount of WAL, in bytes, the instance received less than
+	// the most advanced instance
+	// +optional
+	LagBytes int64 `json:"lagBytes,omitempty"`
+
+	// The failure domain of the instance, set when the failover
+	// topology has been taken into account
+	// +optional
+	FailureDomain string `json:"failureDomain,omitempty"`
+
+	// Why the instance has not been selected, empty for
+	// the selected instance
+	// +optional
+	NotSelectedReason string `json:"notSelectedReason,omitempty"`
+}
+
 // PodTopologyLabels represent the topology of a Pod. map[labelName]labelValue
 type PodTopologyLabels map[string]string
 
@@ -628,6 +907,11 @@ type ManagedRoles struct {
 	// PasswordStatus gives the last transaction id and password secret version for each managed role
 	// +optional
 	PasswordStatus map[string]PasswordState `json:"passwordStatus,omitempty"`
+
+	// ConnectionLimits gives the connection limit currently applied in
+	// PostgreSQL to each managed role, where `-1` means no limit
+	// +optional
+	ConnectionLimits map[string]int64 `json:"connectionLimits,omitempty"`
 }
 
 // TablespaceState represents the state of a tablespace in a cluster
@@ -817,10 +1101,20 @@ type ClusterStatus struct {
 	// +optional
 	CurrentPrimaryFailingSinceTimestamp string `json:"currentPrimaryFailingSinceTimestamp,omitempty"`
 
+	// The timestamp when the operator started waiting for the new primary
+	// to catch up before initiating a switchover
+	// +optional
+	SwitchoverCatchUpSinceTimestamp string `json:"switchoverCatchUpSinceTimestamp,omitempty"`
+
 	// The timestamp when the last request for a new primary has occurred
 	// +optional
 	TargetPrimaryTimestamp string `json:"targetPrimaryTimestamp,omitempty"`
 
+	// The context of the last failover, recording the instances that have
+	// been considered and why the new primary has been selected
+	// +optional
+	LastFailoverDecision *FailoverDecision `json:"lastFailoverDecision,omitempty"`
+
 	// The integration needed by poolers referencing the cluster
 	// +optional
 	PoolerIntegrations *PoolerIntegrations `json:"poolerIntegrations,omitempty"`
@@ -841,6 +1135,17 @@ type ClusterStatus struct {
 	// +optional
 	InstanceNames []string `json:"instanceNames,omitempty"`
 
+	// List of the instances that were fenced during the last reconciliation
+	// loop, resolving the wildcard and the role selectors of the fencing annotation
+	// +optional
+	FencedInstances []string `json:"fencedInstances,omitempty"`
+
+	// The sorted IP addresses of the pods of the cluster, including the ones
+	// of the jobs creating new instances. Only tracked when the replication
+	// `hbaScope` is `instances`.
+	// +optional
+	PodIPs []string `json:"podIPs,omitempty"`
+
 	// OnlineUpdateEnabled shows if the online upgrade is enabled inside the cluster
 	// +optional
 	OnlineUpdateEnabled bool `json:"onlineUpdateEnabled,omitempty"`
@@ -853,6 +1158,11 @@ type ClusterStatus struct {
 	// +optional
 	Image string `json:"image,omitempty"`
 
+	// PGDataImageInfo contains the details of the image the data
+	// directories of the instances were created or last upgraded with
+	// +optional
+	PGDataImageInfo *ImageInfo `json:"pgDataImageInfo,omitempty"`
+
 	// PluginStatus is the status of the loaded plugins
 	// +optional
 	PluginStatus []PluginStatus `json:"pluginStatus,omitempty"`
@@ -867,6 +1177,141 @@ type ClusterStatus struct {
 	// WAL file, and Time of latest checkpoint
 	// +optional
 	DemotionToken string `json:"demotionToken,omitempty"`
+
+	// LastRejoinWALCleanup contains the outcome of the last removal of the
+	// diverged WAL files executed by a former primary while rejoining the
+	// cluster as a replica
+	// +optional
+	LastRejoinWALCleanup *RejoinWALCleanupStatus `json:"lastRejoinWALCleanup,omitempty"`
+
+	// IntegrityCheck contains the outcome of the last verification of
+	// the integrity of the data files
+	// +optional
+	IntegrityCheck *IntegrityCheckStatus `json:"integrityCheck,omitempty"`
+
+	// Repack contains the outcome of the last scheduled execution
+	// of `pg_repack`
+	// +optional
+	Repack *RepackStatus `json:"repack,omitempty"`
+
+	// SynchronousStandbyRotation contains the state of the replacement of
+	// the synchronous standbys lagging behind the primary
+	// +optional
+	SynchronousStandbyRotation *SynchronousStandbyRotationStatus `json:"synchronousStandbyRotation,omitempty"`
+
+	// BootstrapReadiness tracks the creation and the replication of the
+	// managed objects during the initial bootstrap of the cluster
+	// +optional
+	BootstrapReadiness *BootstrapReadinessStatus `json:"bootstrapReadiness,omitempty"`
+
+	// SourceReplicationSlot is the replication slot the designated primary
+	// of a replica cluster created on the source to stream from it
+	// +optional
+	SourceReplicationSlot *SourceReplicationSlotStatus `json:"sourceReplicationSlot,omitempty"`
+}
+
+// BootstrapReadinessStatus tracks the creation and the replication of the
+// managed objects during the initial bootstrap of the cluster
+type BootstrapReadinessStatus struct {
+	// ManagedObjectsLSN is the LSN of the primary after the managed
+	// objects have been created. The cluster is marked as ready once every
+	// replica has replayed it
+	// +optional
+	ManagedObjectsLSN string `json:"managedObjectsLSN,omitempty"`
+
+	// Completed is true when the cluster has been marked as ready for
+	// the first time
+	// +optional
+	Completed bool `json:"completed,omitempty"`
+}
+
+// IntegrityCheckStatus contains the outcome of the last verification
+// of the integrity of the data files
+type IntegrityCheckStatus struct {
+	// InstanceName is the name of the instance where the check has been executed
+	InstanceName string `json:"instanceName"`
+
+	// Method is the method used to verify the data files, either
+	// `checksums` or `amcheck`
+	Method string `json:"method"`
+
+	// Errors is the number of corruption errors that have been detected
+	Errors int `json:"errors"`
+
+	// Message contains the details of the first detected error
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// LastCheckTime is the moment when the check has been completed
+	LastCheckTime string `json:"lastCheckTime"`
+}
+
+// RepackResult is the outcome of a scheduled execution of pg_repack
+type RepackResult string
+
+const (
+	// RepackResultSucceeded means that every table has been repacked
+	RepackResultSucceeded RepackResult = "succeeded"
+
+	// RepackResultFailed means that at least one table couldn't be repacked
+	RepackResultFailed RepackResult = "failed"
+
+	// RepackResultSkipped means that the execution has been skipped
+	RepackResultSkipped RepackResult = "skipped"
+)
+
+// RepackStatus contains the outcome of the last scheduled
+// execution of pg_repack
+type RepackStatus struct {
+	// InstanceName is the name of the instance where pg_repack has been executed
+	InstanceName string `json:"instanceName"`
+
+	// Result is the outcome of the execution, either `succeeded`,
+	// `failed` or `skipped`
+	Result RepackResult `json:"result"`
+
+	// RepackedTables is the number of tables that have been repacked
+	// +optional
+	RepackedTables int `json:"repackedTables,omitempty"`
+
+	// ReclaimedBytes is the disk space that has been reclaimed, computed
+	// from the size of the repacked tables, including indexes and TOAST data
+	// +optional
+	ReclaimedBytes int64 `json:"reclaimedBytes,omitempty"`
+
+	// Message contains the reason why the execution has been skipped
+	// or the errors that have been raised
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// LastRunTime is the moment when the execution has been completed
+	LastRunTime string `json:"lastRunTime"`
+}
+
+// RejoinWALCleanupStatus contains the information about the WAL files removed
+// by a former primary after pg_rewind
+type RejoinWALCleanupStatus struct {
+	// InstanceName is the name of the former primary that removed the files
+	InstanceName string `json:"instanceName"`
+
+	// Timeline is the timeline the instance has been aligned to
+	Timeline int `json:"timeline"`
+
+	// RemovedFiles is the number of files removed from the WAL directory
+	RemovedFiles int `json:"removedFiles"`
+
+	// Timestamp is the moment when the cleanup has been executed
+	Timestamp string `json:"timestamp"`
+}
+
+// SourceReplicationSlotStatus identifies a replication slot created by the
+// operator on the source of a replica cluster
+type SourceReplicationSlotStatus struct {
+	// SlotName is the name of the replication slot
+	SlotName string `json:"slotName"`
+
+	// Source is the name of the external cluster hosting the replication slot
+	Source string `json:"source"`
 }
 
 // SwitchReplicaClusterStatus contains all the statuses regarding the switch of a cluster to a replica cluster
@@ -893,10 +1338,65 @@ type ClusterConditionType string
 const (
 	// ConditionContinuousArchiving represents whether WAL archiving is working
 	ConditionContinuousArchiving ClusterConditionType = "ContinuousArchiving"
+	// ConditionWALArchiveVerified represents whether the last WAL file archived
+	// by PostgreSQL has been found in the object store
+	ConditionWALArchiveVerified ClusterConditionType = "WALArchiveVerified"
 	// ConditionBackup represents the last backup's status
 	ConditionBackup ClusterConditionType = "LastBackupSucceeded"
 	// ConditionClusterReady represents whether a cluster is Ready
 	ConditionClusterReady ClusterConditionType = "Ready"
+	// ConditionReplicationSlotsCollision represents whether the replication
+	// slot used to stream from the source is shared with another cluster
+	ConditionReplicationSlotsCollision ClusterConditionType = "ReplicationSlotsCollision"
+	// ConditionHighAvailabilityDegraded represents whether the cluster has
+	// fewer ready instances than requested
+	ConditionHighAvailabilityDegraded ClusterConditionType = "HighAvailabilityDegraded"
+	// ConditionRecoveryWindowTooShort represents whether the recovery window
+	// of the cluster is shorter than the requested minimum
+	ConditionRecoveryWindowTooShort ClusterConditionType = "RecoveryWindowTooShort"
+	// ConditionStalePreparedTransactions represents whether the primary
+	// has prepared transactions older than the tolerated age
+	ConditionStalePreparedTransactions ClusterConditionType = "StalePreparedTransactions"
+	// ConditionDiskPressure represents whether the data volume of any
+	// instance is used above the configured threshold
+	ConditionDiskPressure ClusterConditionType = "DiskPressure"
+	// ConditionRecoveryPaused represents whether the recovery of the cluster
+	// has been paused at the recovery target, waiting to be promoted
+	ConditionRecoveryPaused ClusterConditionType = "RecoveryPaused"
+	// ConditionClockSkewDetected represents whether the clock of any
+	// instance differs from the one of the operator above the tolerated skew
+	ConditionClockSkewDetected ClusterConditionType = "ClockSkewDetected"
+	// ConditionReplicationLimitsExceeded represents whether `max_wal_senders`
+	// or `max_replication_slots` are too low for the number of replicas
+	ConditionReplicationLimitsExceeded ClusterConditionType = "ReplicationLimitsExceeded"
+	// ConditionConnectionsExhausted represents whether the client connections
+	// of any instance reached the limit available to non-superuser roles
+	ConditionConnectionsExhausted ClusterConditionType = "ConnectionsExhausted"
+	// ConditionPromotionStuck represents whether a promoted instance didn't
+	// end the recovery within the promotion recovery timeout
+	ConditionPromotionStuck ClusterConditionType = "PromotionStuck"
+	// ConditionWraparoundRisk represents whether the transaction ID or
+	// multixact ID age of any database on the primary exceeds the
+	// configured percentage of the freeze max age
+	ConditionWraparoundRisk ClusterConditionType = "WraparoundRisk"
+	// ConditionSafeMode represents whether the cluster is in safe mode,
+	// where the operator doesn't change the instances
+	ConditionSafeMode ClusterConditionType = "SafeMode"
+	// ConditionManagedRolesReconciled represents whether every role in
+	// `.spec.managed.roles` has been reconciled in the database
+	ConditionManagedRolesReconciled ClusterConditionType = "ManagedRolesReconciled"
+	// ConditionBootstrapFailed represents whether the job bootstrapping
+	// the first instance of the cluster failed
+	ConditionBootstrapFailed ClusterConditionType = "BootstrapFailed"
+	// ConditionNoPromotableReplica represents whether no replica of a
+	// highly available cluster could be promoted in case of failover
+	ConditionNoPromotableReplica ClusterConditionType = "NoPromotableReplica"
+	// ConditionSwitchbackDeferred represents whether the automatic
+	// switchback to the preferred node is being deferred
+	ConditionSwitchbackDeferred ClusterConditionType = "SwitchbackDeferred"
+	// ConditionIncludedConfigurationSkipped represents whether some of the
+	// configuration files referenced in `includeConfigMaps` were skipped
+	ConditionIncludedConfigurationSkipped ClusterConditionType = "IncludedConfigurationSkipped"
 )
 
 // ConditionStatus defines conditions of resources
@@ -937,6 +1437,19 @@ const (
 	// the WAL archiving is not working correctly
 	ConditionReasonContinuousArchivingFailing ConditionReason = "ContinuousArchivingFailing"
 
+	// ConditionReasonBackupCredentialsInvalid means that the condition has changed
+	// because the WAL archiving repeatedly failed with authentication errors,
+	// which usually happens when the credentials of the object store expired
+	ConditionReasonBackupCredentialsInvalid ConditionReason = "BackupCredentialsInvalid"
+
+	// ConditionReasonArchivedWALFound means that the last WAL file archived
+	// by PostgreSQL has been found in the object store
+	ConditionReasonArchivedWALFound ConditionReason = "ArchivedWALFound"
+
+	// ConditionReasonArchivedWALMissing means that the last WAL file archived
+	// by PostgreSQL is missing from the object store
+	ConditionReasonArchivedWALMissing ConditionReason = "ArchivedWALMissing"
+
 	// ClusterReady means that the condition changed because the cluster is ready and working properly
 	ClusterReady ConditionReason = "ClusterIsReady"
 
@@ -945,6 +1458,179 @@ const (
 
 	// DetachedVolume is the reason that is set when we do a rolling upgrade to add a PVC volume to a cluster
 	DetachedVolume ConditionReason = "DetachedVolume"
+
+	// ConditionReasonReplicationSlotsCollision means that the replication slot used
+	// to stream from the source is also used by another cluster
+	ConditionReasonReplicationSlotsCollision ConditionReason = "ReplicationSlotsCollision"
+
+	// ConditionReasonNoReplicationSlotsCollision means that the replication slot used
+	// to stream from the source is not used by any other cluster
+	ConditionReasonNoReplicationSlotsCollision ConditionReason = "NoReplicationSlotsCollision"
+
+	// ConditionReasonAllInstancesAvailable means that all the requested
+	// instances are ready
+	ConditionReasonAllInstancesAvailable ConditionReason = "AllInstancesAvailable"
+
+	// ConditionReasonInstancesUnavailable means that some of the requested
+	// instances are not ready
+	ConditionReasonInstancesUnavailable ConditionReason = "InstancesUnavailable"
+
+	// ConditionReasonSingleInstanceRemaining means that only one instance is
+	// ready in a cluster that has been requested to have more
+	ConditionReasonSingleInstanceRemaining ConditionReason = "SingleInstanceRemaining"
+
+	// ConditionReasonSingleInstanceCluster means that the cluster has
+	// intentionally been requested to have a single instance
+	ConditionReasonSingleInstanceCluster ConditionReason = "SingleInstanceCluster"
+
+	// ConditionReasonClusterHibernated means that the cluster instances
+	// are not running because the cluster has been hibernated
+	ConditionReasonClusterHibernated ConditionReason = "ClusterHibernated"
+
+	// ConditionReasonRecoveryWindowSufficient means that the recovery window
+	// of the cluster is not shorter than the requested minimum
+	ConditionReasonRecoveryWindowSufficient ConditionReason = "RecoveryWindowSufficient"
+
+	// ConditionReasonRecoveryWindowBelowMinimum means that the recovery window
+	// of the cluster is shorter than the requested minimum
+	ConditionReasonRecoveryWindowBelowMinimum ConditionReason = "RecoveryWindowBelowMinimum"
+
+	// ConditionReasonNoRecoverabilityPoint means that the cluster has no
+	// point of recoverability yet, as no backup has been completed
+	ConditionReasonNoRecoverabilityPoint ConditionReason = "NoRecoverabilityPoint"
+
+	// ConditionReasonPreparedTransactionsTooOld means that the primary has
+	// prepared transactions that have been neither committed nor rolled back
+	// for longer than the tolerated age
+	ConditionReasonPreparedTransactionsTooOld ConditionReason = "PreparedTransactionsTooOld"
+
+	// ConditionReasonNoStalePreparedTransactions means that the primary has
+	// no prepared transaction older than the tolerated age
+	ConditionReasonNoStalePreparedTransactions ConditionReason = "NoStalePreparedTransactions"
+
+	// ConditionReasonTransactionIDAgeTooHigh means that the transaction ID
+	// age of at least one database exceeds the configured percentage of
+	// `autovacuum_freeze_max_age`
+	ConditionReasonTransactionIDAgeTooHigh ConditionReason = "TransactionIDAgeTooHigh"
+
+	// ConditionReasonMultixactIDAgeTooHigh means that the multixact ID age
+	// of at least one database exceeds the configured percentage of
+	// `autovacuum_multixact_freeze_max_age`
+	ConditionReasonMultixactIDAgeTooHigh ConditionReason = "MultixactIDAgeTooHigh"
+
+	// ConditionReasonNoWraparoundRisk means that the transaction ID and
+	// multixact ID ages of every database are below the configured
+	// percentage of the freeze max age
+	ConditionReasonNoWraparoundRisk ConditionReason = "NoWraparoundRisk"
+
+	// ConditionReasonDataVolumeAlmostFull means that the data volume of
+	// at least one instance is used above the configured threshold
+	ConditionReasonDataVolumeAlmostFull ConditionReason = "DataVolumeAlmostFull"
+
+	// ConditionReasonDataVolumeUsageBelowThreshold means that the data
+	// volumes of every instance are used below the configured threshold
+	ConditionReasonDataVolumeUsageBelowThreshold ConditionReason = "DataVolumeUsageBelowThreshold"
+
+	// ConditionReasonRecoveryTargetReached means that the recovery has
+	// reached the recovery target, and is waiting to be promoted
+	ConditionReasonRecoveryTargetReached ConditionReason = "RecoveryTargetReached"
+
+	// ConditionReasonPromotionRequested means that the promotion of a
+	// cluster paused at the recovery target has been requested
+	ConditionReasonPromotionRequested ConditionReason = "PromotionRequested"
+
+	// ConditionReasonClockSkewAboveThreshold means that the clock of at
+	// least one instance differs from the one of the operator above the
+	// tolerated skew
+	ConditionReasonClockSkewAboveThreshold ConditionReason = "ClockSkewAboveThreshold"
+
+	// ConditionReasonClocksSynchronized means that the clocks of every
+	// instance differ from the one of the operator within the tolerated skew
+	ConditionReasonClocksSynchronized ConditionReason = "ClocksSynchronized"
+
+	// ConditionReasonNotEnoughWalSenders means that `max_wal_senders` is
+	// lower than the number of replicas
+	ConditionReasonNotEnoughWalSenders ConditionReason = "NotEnoughWalSenders"
+
+	// ConditionReasonNotEnoughReplicationSlots means that
+	// `max_replication_slots` is lower than the number of high
+	// availability replication slots
+	ConditionReasonNotEnoughReplicationSlots ConditionReason = "NotEnoughReplicationSlots"
+
+	// ConditionReasonReplicationLimitsSufficient means that `max_wal_senders`
+	// and `max_replication_slots` are enough for the number of replicas
+	ConditionReasonReplicationLimitsSufficient ConditionReason = "ReplicationLimitsSufficient"
+
+	// ConditionReasonConnectionsExhausted means that the client connections
+	// of at least one instance reached `max_connections`, minus the
+	// connection slots reserved to superusers
+	ConditionReasonConnectionsExhausted ConditionReason = "ConnectionsExhausted"
+
+	// ConditionReasonConnectionsAvailable means that every instance can
+	// accept new client connections
+	ConditionReasonConnectionsAvailable ConditionReason = "ConnectionsAvailable"
+
+	// ConditionReasonRecoveryNotEnded means that `pg_is_in_recovery()` still
+	// returned true on the promoted instance when the timeout expired
+	ConditionReasonRecoveryNotEnded ConditionReason = "RecoveryNotEnded"
+
+	// ConditionReasonRecoveryEnded means that the promoted instance ended
+	// the recovery and became the current primary
+	ConditionReasonRecoveryEnded ConditionReason = "RecoveryEnded"
+
+	// ConditionReasonSafeModeEnabled means that the cluster has been put in
+	// safe mode with the `cnpg.io/safeMode` annotation
+	ConditionReasonSafeModeEnabled ConditionReason = "SafeModeEnabled"
+
+	// ConditionReasonSafeModeDisabled means that the safe mode has been
+	// lifted, and the operator reconciles the instances again
+	ConditionReasonSafeModeDisabled ConditionReason = "SafeModeDisabled"
+
+	// ConditionReasonManagedRolesReconciled means that the managed roles
+	// in the database are aligned with the spec
+	ConditionReasonManagedRolesReconciled ConditionReason = "ManagedRolesReconciled"
+
+	// ConditionReasonManagedRolesCannotReconcile means that PostgreSQL
+	// refused to apply the changes to some managed roles, e.g. dropping a
+	// role owning objects
+	ConditionReasonManagedRolesCannotReconcile ConditionReason = "ManagedRolesCannotReconcile"
+
+	// ConditionReasonBootstrapJobFailed means that the bootstrap job failed
+	// without reporting a more specific reason
+	ConditionReasonBootstrapJobFailed ConditionReason = "BootstrapJobFailed"
+
+	// ConditionReasonBootstrapRetrying means that the resources of a failed
+	// bootstrap have been removed, and the bootstrap is being retried with
+	// the updated spec
+	ConditionReasonBootstrapRetrying ConditionReason = "BootstrapRetrying"
+
+	// ConditionReasonPromotableReplicasAvailable means that at least one
+	// replica is healthy, streaming and caught up with the primary
+	ConditionReasonPromotableReplicasAvailable ConditionReason = "PromotableReplicasAvailable"
+
+	// ConditionReasonNoReplicaPromotable means that every replica is
+	// either unhealthy, not streaming or lagging behind the primary
+	ConditionReasonNoReplicaPromotable ConditionReason = "NoReplicaPromotable"
+
+	// ConditionReasonSwitchbackCooldown means that the switchback is
+	// deferred until the cooldown expires
+	ConditionReasonSwitchbackCooldown ConditionReason = "SwitchbackCooldown"
+
+	// ConditionReasonOutsideMaintenanceWindow means that the switchback is
+	// deferred until the next maintenance window
+	ConditionReasonOutsideMaintenanceWindow ConditionReason = "OutsideMaintenanceWindow"
+
+	// ConditionReasonSwitchbackNotDeferred means that the switchback is
+	// not deferred
+	ConditionReasonSwitchbackNotDeferred ConditionReason = "SwitchbackNotDeferred"
+
+	// ConditionReasonIncludedConfigurationInvalid means that some of the
+	// configuration files to be included are missing or invalid
+	ConditionReasonIncludedConfigurationInvalid ConditionReason = "IncludedConfigurationInvalid"
+
+	// ConditionReasonIncludedConfigurationApplied means that every
+	// configuration file to be included has been applied
+	ConditionReasonIncludedConfigurationApplied ConditionReason = "IncludedConfigurationApplied"
 )
 
 // EmbeddedObjectMetadata contains metadata to be inherited by all resources related to a Cluster
@@ -1012,6 +1698,11 @@ const DefaultReplicationSlotsUpdateInterval = 30
 // DefaultReplicationSlotsHASlotPrefix is the default prefix for names of replication slots used for HA.
 const DefaultReplicationSlotsHASlotPrefix = "_cnpg_"
 
+// ReplicationConnectionsHeadroom is the number of WAL senders and replication
+// slots kept available on top of the ones needed by the replicas, i.e. for
+// cloning new replicas, replica clusters and logical replication
+const ReplicationConnectionsHeadroom = 4
+
 // SynchronizeReplicasConfiguration contains the configuration for the synchronization of user defined
 // physical replication slots
 type SynchronizeReplicasConfiguration struct {
@@ -1054,6 +1745,17 @@ type ReplicationSlotsConfiguration struct {
 	// Configures the synchronization of the user defined physical replication slots
 	// +optional
 	SynchronizeReplicas *SynchronizeReplicasConfiguration `json:"synchronizeReplicas,omitempty"`
+
+	// Prefix used to namespace the replication slots managed by the operator
+	// for this cluster. When set, it is used in place of the
+	// `highAvailability.slotPrefix` default, and the designated primary of a
+	// replica cluster streams from the source through the `<prefix><cluster name>`
+	// replication slot, which is created if missing.
+	// It may only contain lower case letters, numbers, and the underscore character.
+	// This can only be set at creation time.
+	// +kubebuilder:validation:Pattern=^[0-9a-z_]*$
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
 }
 
 // ReplicationSlotsHAConfiguration encapsulates the configuration
@@ -1083,6 +1785,111 @@ type ReplicationSlotsHAConfiguration struct {
 	// +kubebuilder:validation:Pattern=^[0-9a-z_]*$
 	// +optional
 	SlotPrefix string `json:"slotPrefix,omitempty"`
+
+	// The number of seconds the operator waits before dropping, from the
+	// primary, an HA replication slot that does not correspond to any
+	// instance of the cluster anymore, such as the one of a removed replica.
+	// Every dropped slot is reported with an event. By default (0), the
+	// slots are dropped as soon as they are found.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	OrphanedSlotsGracePeriod int `json:"orphanedSlotsGracePeriod,omitempty"`
+}
+
+// CreateReplicaMethod is the method used by the operator to create
+// the data directory of a new replica
+// +kubebuilder:validation:Enum=pg_basebackup;from-backup
+type CreateReplicaMethod string
+
+const (
+	// CreateReplicaMethodPgBaseBackup means that new replicas are always
+	// cloned from the primary using pg_basebackup
+	CreateReplicaMethodPgBaseBackup CreateReplicaMethod = "pg_basebackup"
+
+	// CreateReplicaMethodFromBackup means that new replicas are created
+	// from an existing backup, when a suitable one is available, falling
+	// back to pg_basebackup otherwise
+	CreateReplicaMethodFromBackup CreateReplicaMethod = "from-backup"
+)
+
+// CheckpointMode is the checkpoint mode requested by pg_basebackup
+// to the source server at the start of the copy
+// +kubebuilder:validation:Enum=fast;spread
+type CheckpointMode string
+
+const (
+	// CheckpointModeFast requests an immediate checkpoint
+	CheckpointModeFast CheckpointMode = "fast"
+
+	// CheckpointModeSpread requests a spread checkpoint
+	CheckpointModeSpread CheckpointMode = "spread"
+)
+
+// ReplicationConfiguration encapsulates the configuration used
+// by the operator to create new replicas
+type ReplicationConfiguration struct {
+	// The method used to create new replicas. With `from-backup` (default),
+	// the operator creates the replica from a suitable backup, if available,
+	// falling back to `pg_basebackup`. With `pg_basebackup`, new replicas are
+	// always cloned from the primary.
+	// +kubebuilder:default:=from-backup
+	// +optional
+	CreateReplicaMethod CreateReplicaMethod `json:"createReplicaMethod,omitempty"`
+
+	// Options for pg_basebackup, used when a replica is cloned from the primary
+	// +optional
+	PgBaseBackup *PgBaseBackupOptions `json:"pgBaseBackup,omitempty"`
+
+	// The period after which the operator rotates the credentials of the
+	// `streaming_replica` user, i.e. its TLS client certificate. Automatic
+	// rotation is disabled when not set. Only available when the replication
+	// certificate is managed by the operator.
+	// +optional
+	CredentialsRotationPeriod *metav1.Duration `json:"credentialsRotationPeriod,omitempty"`
+
+	// The addresses from which the `streaming_replica` user is allowed to
+	// connect, as set in `pg_hba.conf`. With `all` (default), any address is
+	// accepted. With `instances`, only the IP addresses of the pods of the
+	// cluster, including the ones of the jobs creating new instances, are
+	// accepted. The rules are refreshed whenever these addresses change.
+	// The `instances` scope prevents replica clusters from streaming from
+	// this cluster.
+	// +kubebuilder:default:=all
+	// +optional
+	HBAScope ReplicationHBAScope `json:"hbaScope,omitempty"`
+}
+
+// ReplicationHBAScope is the set of addresses from which the
+// `streaming_replica` user is allowed to connect
+// +kubebuilder:validation:Enum=all;instances
+type ReplicationHBAScope string
+
+const (
+	// ReplicationHBAScopeAll means that the `streaming_replica` user can
+	// connect from any address
+	ReplicationHBAScopeAll ReplicationHBAScope = "all"
+
+	// ReplicationHBAScopeInstances means that the `streaming_replica` user
+	// can only connect from the pods of the cluster
+	ReplicationHBAScopeInstances ReplicationHBAScope = "instances"
+)
+
+// PgBaseBackupOptions contains the options passed to pg_basebackup
+// when cloning a new replica from the primary
+type PgBaseBackupOptions struct {
+	// The checkpoint mode, `fast` or `spread` (default). A fast checkpoint
+	// lets the copy start immediately, at the cost of an I/O spike on
+	// the primary.
+	// +optional
+	Checkpoint CheckpointMode `json:"checkpoint,omitempty"`
+
+	// The maximum transfer rate of the data directory, expressed in
+	// kilobytes per second, or with a `k` or `M` suffix (e.g. `100M`).
+	// The accepted range goes from 32 kB/s to 1024 MB/s. Unlimited
+	// by default.
+	// +kubebuilder:validation:Pattern=`^[0-9]+[kM]?$`
+	// +optional
+	MaxRate string `json:"maxRate,omitempty"`
 }
 
 // KubernetesUpgradeStrategy tells the operator if the user want to
@@ -1120,6 +1927,21 @@ type NodeMaintenanceWindow struct {
 	InProgress bool `json:"inProgress,omitempty"`
 }
 
+// SizeTier is a predefined sizing of the instances of a cluster
+// +kubebuilder:validation:Enum=small;medium;large
+type SizeTier string
+
+const (
+	// SizeTierSmall requests 1 CPU and 2Gi of memory
+	SizeTierSmall SizeTier = "small"
+
+	// SizeTierMedium requests 2 CPUs and 8Gi of memory
+	SizeTierMedium SizeTier = "medium"
+
+	// SizeTierLarge requests 4 CPUs and 32Gi of memory
+	SizeTierLarge SizeTier = "large"
+)
+
 // PrimaryUpdateStrategy contains the strategy to follow when upgrading
 // the primary server of the cluster as part of rolling updates
 type PrimaryUpdateStrategy string
@@ -1151,10 +1973,27 @@ const (
 	// It is greater than one year in seconds, big enough to simulate an infinite timeout
 	DefaultPgCtlTimeoutForPromotion = 40000000
 
+	// DefaultPromotionRecoveryTimeout is the default number of seconds to
+	// wait for a promoted instance to end the recovery
+	DefaultPromotionRecoveryTimeout = 60
+
 	// DefaultMaxSwitchoverDelay is the default for the pg_ctl timeout in seconds when a primary PostgreSQL instance
 	// is gracefully shutdown during a switchover.
 	DefaultMaxSwitchoverDelay = 3600
 
+	// DefaultSwitchoverCatchUpTimeout is the default time in seconds the operator
+	// waits for the new primary to catch up before aborting a switchover
+	DefaultSwitchoverCatchUpTimeout = 300
+
+	// DefaultFailoverTopologyKey is the default node label defining the failure
+	// domain to be preferred when choosing the failover candidate
+	DefaultFailoverTopologyKey = "topology.kubernetes.io/zone"
+
+	// DefaultAutoSwitchbackCooldown is the default time in seconds elapsed
+	// since the last promotion before the primary is switched back to the
+	// preferred node
+	DefaultAutoSwitchbackCooldown = 600
+
 	// DefaultStartupDelay is the default value for startupDelay, startupDelay will be used to calculate the
 	// FailureThreshold of startupProbe, the formula is `FailureThreshold = ceiling(startDelay / periodSeconds)`,
 	// the minimum value is 1
@@ -1233,6 +2072,74 @@ type SynchronousReplicaConfiguration struct {
 	// +kubebuilder:default:=required
 	// +optional
 	DataDurability DataDurabilityLevel `json:"dataDurability,omitempty"`
+
+	// When set, a synchronous standby lagging behind the primary is replaced
+	// by a more caught-up replica, to keep the writes flowing
+	// +optional
+	LagRotation *SynchronousStandbyLagRotation `json:"lagRotation,omitempty"`
+
+	// When enabled, the replicas running in an availability zone different
+	// from the one of the primary, as defined by the
+	// `topology.kubernetes.io/zone` node label, are preferred as synchronous
+	// standbys. The replicas in the same zone of the primary are used
+	// only when there are not enough cross-zone replicas available
+	// +optional
+	PreferCrossZone bool `json:"preferCrossZone,omitempty"`
+}
+
+const (
+	// DefaultSynchronousStandbyMaxLag is the default amount of WAL, in bytes,
+	// a synchronous standby can lag behind the primary before being replaced
+	DefaultSynchronousStandbyMaxLag = 64 * 1024 * 1024
+
+	// DefaultSynchronousStandbyRotationDelay is the default time, in seconds,
+	// a synchronous standby needs to lag behind the primary before being replaced
+	DefaultSynchronousStandbyRotationDelay = 30
+)
+
+// SynchronousStandbyLagRotation contains the configuration of the
+// replacement of the synchronous standbys lagging behind the primary.
+// This is not a failover: the lagging standby keeps streaming as a
+// potential synchronous standby, with the lowest priority
+type SynchronousStandbyLagRotation struct {
+	// The amount of WAL, as a Kubernetes quantity, a synchronous standby
+	// can lag behind the primary before being replaced. Defaults to `64Mi`
+	// +optional
+	MaxLag string `json:"maxLag,omitempty"`
+
+	// The time in seconds the lag needs to be sustained before the
+	// synchronous standby is replaced. Defaults to `30`
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Delay *int32 `json:"delay,omitempty"`
+}
+
+// SynchronousStandbyRotationStatus contains the state of the replacement of
+// the synchronous standbys lagging behind the primary
+type SynchronousStandbyRotationStatus struct {
+	// LaggingSince contains, for each synchronous standby lagging behind
+	// the primary more than the configured threshold, the time when the
+	// lag has been first detected
+	// +optional
+	LaggingSince map[string]string `json:"laggingSince,omitempty"`
+
+	// Deprioritized contains the instances that have been moved to the end
+	// of `synchronous_standby_names` because of their lag, until they
+	// catch up with the primary
+	// +optional
+	Deprioritized []string `json:"deprioritized,omitempty"`
+}
+
+// ClientCertificateMapping maps the common name of a client certificate
+// to the PostgreSQL role it is allowed to connect as
+type ClientCertificateMapping struct {
+	// The common name (CN) of the client certificate
+	// +kubebuilder:validation:MinLength=1
+	CommonName string `json:"commonName"`
+
+	// The PostgreSQL role the certificate authenticates as
+	// +kubebuilder:validation:MinLength=1
+	Role string `json:"role"`
 }
 
 // PostgresConfiguration defines the PostgreSQL configuration
@@ -1241,20 +2148,43 @@ type PostgresConfiguration struct {
 	// +optional
 	Parameters map[string]string `json:"parameters,omitempty"`
 
+	// The list of config maps containing PostgreSQL configuration files
+	// to be included, in this order, after the settings managed by the
+	// operator and before the ones in `parameters`. They can't set the
+	// parameters reserved to the operator, nor include other files
+	// +optional
+	IncludeConfigMaps []ConfigMapKeySelector `json:"includeConfigMaps,omitempty"`
+
 	// Configuration of the PostgreSQL synchronous replication feature
 	// +optional
 	Synchronous *SynchronousReplicaConfiguration `json:"synchronous,omitempty"`
 
 	// PostgreSQL Host Based Authentication rules (lines to be appended
-	// to the pg_hba.conf file)
+	// to the pg_hba.conf file, after the fixed rules set by the operator).
+	// The `{{.ReplicationUser}}`, `{{.ApplicationUser}}` and
+	// `{{.ApplicationDatabase}}` placeholders are expanded
 	// +optional
 	PgHBA []string `json:"pg_hba,omitempty"`
 
+	// PostgreSQL Host Based Authentication rules to be placed at the
+	// beginning of the pg_hba.conf file, before the fixed rules set by
+	// the operator. As for `pg_hba`, placeholders are expanded. The
+	// `local` rules are not allowed, as the instance manager relies on
+	// the peer authentication
+	// +optional
+	PgHBAPrepend []string `json:"pgHBAPrepend,omitempty"`
+
 	// PostgreSQL User Name Maps rules (lines to be appended
 	// to the pg_ident.conf file)
 	// +optional
 	PgIdent []string `json:"pg_ident,omitempty"`
 
+	// Map the common names of the client certificates signed by the
+	// client CA to PostgreSQL roles, allowing applications to authenticate
+	// with their certificate instead of a password
+	// +optional
+	ClientCertificateMappings []ClientCertificateMapping `json:"clientCertificateMappings,omitempty"`
+
 	// Requirements to be met by sync replicas. This will affect how the "synchronous_standby_names" parameter will be
 	// set up.
 	// +optional
@@ -1274,14 +2204,74 @@ type PostgresConfiguration struct {
 	// +optional
 	PgCtlTimeoutForPromotion int32 `json:"promotionTimeout,omitempty"`
 
+	// Specifies the maximum number of seconds to wait, once an instance has
+	// been promoted, for `pg_is_in_recovery()` to return false before
+	// marking it as the current primary, which moves the role label and the
+	// `-rw` service to it. When the timeout expires, the `PromotionStuck`
+	// condition is raised and the check is retried. Default value is 60
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PromotionRecoveryTimeout int32 `json:"promotionRecoveryTimeout,omitempty"`
+
 	// If this parameter is true, the user will be able to invoke `ALTER SYSTEM`
 	// on this CloudNativePG Cluster.
 	// This should only be used for debugging and troubleshooting.
 	// Defaults to false.
 	// +optional
 	EnableAlterSystem bool `json:"enableAlterSystem,omitempty"`
+
+	// The workload profile whose tuned parameters are applied to the
+	// instances, between `oltp`, `olap` and `mixed`. Explicitly set
+	// parameters take precedence over the ones of the profile
+	// +optional
+	Profile WorkloadProfile `json:"profile,omitempty"`
+
+	// How the data directory is upgraded when the image of the
+	// cluster is changed to a newer major version of PostgreSQL
+	// +optional
+	MajorUpgrade *MajorUpgradeConfiguration `json:"majorUpgrade,omitempty"`
+}
+
+// MajorUpgradeStrategy is the way the data directory is upgraded to a
+// newer major version of PostgreSQL
+// +kubebuilder:validation:Enum=pg_upgrade
+type MajorUpgradeStrategy string
+
+const (
+	// MajorUpgradeStrategyPgUpgrade upgrades the data directory of the
+	// primary instance in place, using `pg_upgrade --link`
+	MajorUpgradeStrategyPgUpgrade MajorUpgradeStrategy = "pg_upgrade"
+)
+
+// MajorUpgradeConfiguration contains the configuration of the upgrades
+// to a newer major version of PostgreSQL
+type MajorUpgradeConfiguration struct {
+	// The strategy used to upgrade the data directory. When set to
+	// `pg_upgrade`, the operator shuts down the cluster and upgrades the
+	// data directory of the primary instance in place, then clones the
+	// replicas again
+	Strategy MajorUpgradeStrategy `json:"strategy"`
 }
 
+// WorkloadProfile is a predefined set of PostgreSQL parameters tuned
+// for a certain workload
+// +kubebuilder:validation:Enum=oltp;olap;mixed
+type WorkloadProfile string
+
+const (
+	// WorkloadProfileOLTP is tuned for transactional workloads, made of
+	// many short queries
+	WorkloadProfileOLTP WorkloadProfile = "oltp"
+
+	// WorkloadProfileOLAP is tuned for analytical workloads, made of few
+	// complex queries on large amounts of data
+	WorkloadProfileOLAP WorkloadProfile = "olap"
+
+	// WorkloadProfileMixed is tuned for workloads made of both
+	// transactional and analytical queries
+	WorkloadProfileMixed WorkloadProfile = "mixed"
+)
+
 // BootstrapConfiguration contains information about how to create the PostgreSQL
 // cluster. Only a single bootstrap method can be defined among the supported
 // ones. `initdb` will be used as the bootstrap method if left
@@ -1300,6 +2290,13 @@ type BootstrapConfiguration struct {
 	// PostgreSQL instance
 	// +optional
 	PgBaseBackup *BootstrapPgBaseBackup `json:"pg_basebackup,omitempty"`
+
+	// When set to true, the operator removes the job and the PVCs created
+	// by a failed bootstrap of the cluster, so that the bootstrap can be
+	// retried once the spec has been corrected. Clusters that have already
+	// been bootstrapped are never affected. Defaults to false.
+	// +optional
+	CleanupOnFailure bool `json:"cleanupOnFailure,omitempty"`
 }
 
 // LDAPScheme defines the possible schemes for LDAP
@@ -1423,6 +2420,11 @@ type CertificatesStatus struct {
 	// Expiration dates for all certificates.
 	// +optional
 	Expirations map[string]string `json:"expirations,omitempty"`
+
+	// The last time the credentials of the `streaming_replica` user
+	// have been rotated
+	// +optional
+	ReplicationCredentialsLastRotation string `json:"replicationCredentialsLastRotation,omitempty"`
 }
 
 // BootstrapInitDB is the configuration of the bootstrap process when
@@ -1438,6 +2440,14 @@ type BootstrapInitDB struct {
 	// +optional
 	Owner string `json:"owner,omitempty"`
 
+	// The maximum number of concurrent connections to the application
+	// database, applied with `ALTER DATABASE ... CONNECTION LIMIT` once
+	// the database has been created. `-1` means no limit (default: empty,
+	// leaving the PostgreSQL default unchanged)
+	// +kubebuilder:validation:Minimum=-1
+	// +optional
+	ConnectionLimit *int `json:"connectionLimit,omitempty"`
+
 	// Name of the secret containing the initial credentials for the
 	// owner of the user database. If empty a new secret will be
 	// created from scratch
@@ -1460,6 +2470,11 @@ type BootstrapInitDB struct {
 	// +optional
 	Encoding string `json:"encoding,omitempty"`
 
+	// The value to be passed as option `--locale` for initdb. When set,
+	// `localeCollate` and `localeCType` are not defaulted to `C`
+	// +optional
+	Locale string `json:"locale,omitempty"`
+
 	// The value to be passed as option `--lc-collate` for initdb (default:`C`)
 	// +optional
 	LocaleCollate string `json:"localeCollate,omitempty"`
@@ -1468,6 +2483,27 @@ type BootstrapInitDB struct {
 	// +optional
 	LocaleCType string `json:"localeCType,omitempty"`
 
+	// The value to be passed as option `--locale-provider` for initdb.
+	// Available from PostgreSQL 15 (`icu`) and 17 (`builtin`)
+	// +kubebuilder:validation:Enum=libc;icu;builtin
+	// +optional
+	LocaleProvider string `json:"localeProvider,omitempty"`
+
+	// The value to be passed as option `--icu-locale` for initdb.
+	// Requires `localeProvider` to be `icu`
+	// +optional
+	IcuLocale string `json:"icuLocale,omitempty"`
+
+	// The value to be passed as option `--icu-rules` for initdb.
+	// Requires `localeProvider` to be `icu` and PostgreSQL 16 or newer
+	// +optional
+	IcuRules string `json:"icuRules,omitempty"`
+
+	// The value to be passed as option `--builtin-locale` for initdb.
+	// Requires `localeProvider` to be `builtin`
+	// +optional
+	BuiltinLocale string `json:"builtinLocale,omitempty"`
+
 	// The value in megabytes (1 to 1024) to be passed to the `--wal-segsize`
 	// option for initdb (default: empty, resulting in PostgreSQL default: 16MB)
 	// +kubebuilder:validation:Minimum=1
@@ -1646,6 +2682,15 @@ type BootstrapRecovery struct {
 	// created from scratch
 	// +optional
 	Secret *LocalObjectReference `json:"secret,omitempty"`
+
+	// A list of SQL queries validating the recovered data, run in the
+	// application database once the recovery is completed and before
+	// the cluster is started. Each query runs in a read-only transaction,
+	// and fails if it raises an error or if the first column of its first
+	// row is `false`. When any query fails, the cluster is not started and
+	// is left in the `Recovery validation failed` phase for inspection
+	// +optional
+	ValidationSQL []string `json:"validationSQL,omitempty"`
 }
 
 // DataSource contains the configuration required to bootstrap a
@@ -1736,8 +2781,36 @@ type RecoveryTarget struct {
 	// in Postgres, `recovery_target_inclusive` will be true
 	// +optional
 	Exclusive *bool `json:"exclusive,omitempty"`
+
+	// The action to take once the recovery target is reached, mapped to
+	// `recovery_target_action`. With `promote` (default) the recovery ends
+	// and the cluster is started. With `pause` the recovery is paused,
+	// allowing the data to be inspected, while with `shutdown` the
+	// instance is stopped; in both cases the cluster is started only
+	// once promoted with the `promote` command of the `cnpg` plugin.
+	// Requires a recovery target to be specified
+	// +kubebuilder:validation:Enum=pause;promote;shutdown
+	// +optional
+	TargetAction RecoveryTargetAction `json:"targetAction,omitempty"`
 }
 
+// RecoveryTargetAction is the action to take once the recovery target is reached
+type RecoveryTargetAction string
+
+const (
+	// RecoveryTargetActionPause pauses the recovery once the target is
+	// reached, waiting for the cluster to be promoted
+	RecoveryTargetActionPause RecoveryTargetAction = "pause"
+
+	// RecoveryTargetActionPromote ends the recovery once the target
+	// is reached, and starts the cluster
+	RecoveryTargetActionPromote RecoveryTargetAction = "promote"
+
+	// RecoveryTargetActionShutdown stops the instance once the target
+	// is reached, waiting for the cluster to be promoted
+	RecoveryTargetActionShutdown RecoveryTargetAction = "shutdown"
+)
+
 // StorageConfiguration is the configuration used to create and reconcile PVCs,
 // usable for WAL volumes, PGDATA volumes, or tablespaces
 type StorageConfiguration struct {
@@ -1764,6 +2837,56 @@ type StorageConfiguration struct {
 	PersistentVolumeClaimTemplate *corev1.PersistentVolumeClaimSpec `json:"pvcTemplate,omitempty"`
 }
 
+const (
+	// DefaultDiskPressureThreshold is the default percentage of the data
+	// volume capacity above which an instance is under disk pressure
+	DefaultDiskPressureThreshold = 90
+
+	// DefaultDiskPressureExpansionPercentage is the default percentage by
+	// which the data volumes are increased at every automatic expansion
+	DefaultDiskPressureExpansionPercentage = 20
+)
+
+// DefaultWraparoundRiskThreshold is the default percentage of the freeze
+// max age above which the age of a database raises the WraparoundRisk
+// condition. The anti-wraparound autovacuum starts when the age reaches
+// the freeze max age, so a higher age means that it can't keep up
+const DefaultWraparoundRiskThreshold = 150
+
+// DefaultStalePreparedTransactionsAge is the default age, in seconds, after
+// which a transaction prepared for two-phase commit raises the
+// StalePreparedTransactions condition
+const DefaultStalePreparedTransactionsAge = 600
+
+// DiskPressureConfiguration defines how the operator reacts when the
+// data volumes of the instances are running out of space
+type DiskPressureConfiguration struct {
+	// The percentage of the data volume capacity above which an
+	// instance is considered under disk pressure (default 90)
+	// +kubebuilder:validation:Minimum=50
+	// +kubebuilder:validation:Maximum=99
+	// +optional
+	Threshold int `json:"threshold,omitempty"`
+
+	// When enabled, the operator increases the requested size of the data
+	// volume of an instance under disk pressure, up to `maxSize`. This
+	// requires the storage class to allow volume expansion
+	// +optional
+	AutoExpand bool `json:"autoExpand,omitempty"`
+
+	// The percentage by which the size of the data volumes is increased
+	// at every expansion (default 20)
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	ExpansionPercentage int `json:"expansionPercentage,omitempty"`
+
+	// The size beyond which the data volumes are never expanded.
+	// Required when `autoExpand` is enabled
+	// +optional
+	MaxSize string `json:"maxSize,omitempty"`
+}
+
 // TablespaceConfiguration is the configuration of a tablespace, and includes
 // the storage specification for the tablespace
 type TablespaceConfiguration struct {
@@ -1846,6 +2969,16 @@ type AffinityConfiguration struct {
 	// +optional
 	PodAntiAffinityType string `json:"podAntiAffinityType,omitempty"`
 
+	// When set to `true`, the operator adds a preferred pod anti-affinity
+	// term, on the same topology key, that keeps the instances away from the
+	// node of the current primary, using the `cnpg.io/instanceRole` pod label.
+	// This way, the synchronous standbys don't share the node of the primary
+	// even when the replicas are allowed to share nodes with each other.
+	// Being preferred, the term doesn't prevent the scheduling of the
+	// instances when there are not enough nodes. Default: `false`.
+	// +optional
+	EnablePrimaryReplicaAntiAffinity bool `json:"enablePrimaryReplicaAntiAffinity,omitempty"`
+
 	// AdditionalPodAntiAffinity allows to specify pod anti-affinity terms to be added to the ones generated
 	// by the operator if EnablePodAntiAffinity is set to true (default) or to be used exclusively if set to false.
 	// +optional
@@ -1877,6 +3010,11 @@ const (
 	// BackupTargetStandby means backups will be performed on a standby instance if available
 	BackupTargetStandby = BackupTarget("prefer-standby")
 
+	// BackupTargetStandbyOnly means backups will be performed on a standby instance,
+	// and deferred until one is available. It can only be set in the Backup and
+	// ScheduledBackup types
+	BackupTargetStandbyOnly = BackupTarget("standby")
+
 	// DefaultBackupTarget is the default BackupTarget
 	DefaultBackupTarget = BackupTargetStandby
 )
@@ -1894,6 +3032,14 @@ type BackupConfiguration struct {
 	// +optional
 	BarmanObjectStore *BarmanObjectStoreConfiguration `json:"barmanObjectStore,omitempty"`
 
+	// The configuration for the barman-cloud tool suite to be used to
+	// archive the WAL files in a different object store than the one
+	// containing the base backups, which is described by `barmanObjectStore`.
+	// The server name defaults to the one used for the base backups.
+	// When not set, WAL files are archived together with the base backups
+	// +optional
+	WALObjectStore *BarmanObjectStoreConfiguration `json:"walObjectStore,omitempty"`
+
 	// RetentionPolicy is the retention policy to be used for backups
 	// and WALs (i.e. '60d'). The retention policy is expressed in the form
 	// of `XXu` where `XX` is a positive integer and `u` is in `[dwm]` -
@@ -1903,6 +3049,41 @@ type BackupConfiguration struct {
 	// +optional
 	RetentionPolicy string `json:"retentionPolicy,omitempty"`
 
+	// MinRecoveryWindow is the minimum recovery window expected for the
+	// cluster, between the first point of recoverability and now, expressed
+	// in the same form of the retention policy (i.e. '7d'), where a month
+	// counts as 30 days. When set, the `RecoveryWindowTooShort` condition
+	// reports whether the recovery window is shorter than that
+	// +kubebuilder:validation:Pattern=^[1-9][0-9]*[dwm]$
+	// +optional
+	MinRecoveryWindow string `json:"minRecoveryWindow,omitempty"`
+
+	// The periodic verification that the last WAL file archived by
+	// PostgreSQL is actually available in the object store. The outcome
+	// is reported in the `WALArchiveVerified` condition.
+	// It's currently only applicable when using the BarmanObjectStore method.
+	// +optional
+	ArchiveVerification *ArchiveVerificationConfiguration `json:"archiveVerification,omitempty"`
+
+	// The compression level passed to `barman-cloud-wal-archive` when
+	// archiving the WAL files, where higher levels reduce the size of the
+	// archive at the cost of CPU time. It requires the `gzip` or `bzip2`
+	// compression to be set in the `wal` section of the object store used
+	// for the WAL files, and accepts values between 1 and 9
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=9
+	// +optional
+	WALCompressionLevel *int `json:"walCompressionLevel,omitempty"`
+
+	// When set to `true`, the global objects of the cluster, i.e. the
+	// roles, the tablespaces and the grants on them, are exported with
+	// `pg_dumpall --globals-only` by the instance taking each backup, and
+	// uploaded to the object store alongside the base backup, so that they
+	// can be reconstructed when restoring in a different cluster.
+	// It's currently only applicable when using the BarmanObjectStore method.
+	// +optional
+	ExportGlobals bool `json:"exportGlobals,omitempty"`
+
 	// The policy to decide which instance should perform backups. Available
 	// options are empty string, which will default to `prefer-standby` policy,
 	// `primary` to have backups run always on primary instances, `prefer-standby`
@@ -1911,6 +3092,13 @@ type BackupConfiguration struct {
 	// +kubebuilder:default:=prefer-standby
 	// +optional
 	Target BackupTarget `json:"target,omitempty"`
+
+	// When set to `true`, the switchovers initiated by the operator or
+	// requested by the user are deferred until the running backups
+	// complete, and the running backups are marked as failed when a
+	// failover happens. Default: false.
+	// +optional
+	BlockSwitchover bool `json:"blockSwitchover,omitempty"`
 }
 
 // MonitoringConfiguration is the type containing all the monitoring
@@ -1948,6 +3136,22 @@ type MonitoringConfiguration struct {
 	// The list of relabelings for the `PodMonitor`. Applied to samples before scraping.
 	// +optional
 	PodMonitorRelabelConfigs []monitoringv1.RelabelConfig `json:"podMonitorRelabelings,omitempty"`
+
+	// The percentage of `autovacuum_freeze_max_age` (or of
+	// `autovacuum_multixact_freeze_max_age` for multixact IDs) above which
+	// the age of the oldest database raises the `WraparoundRisk`
+	// condition (default 150)
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1000
+	// +optional
+	WraparoundRiskThreshold int `json:"wraparoundRiskThreshold,omitempty"`
+
+	// The age, in seconds, after which a transaction prepared for
+	// two-phase commit on the primary raises the `StalePreparedTransactions`
+	// condition (default 600)
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	StalePreparedTransactionsAge int `json:"stalePreparedTransactionsAge,omitempty"`
 }
 
 // ClusterMonitoringTLSConfiguration is the type containing the TLS configuration
@@ -2002,6 +3206,14 @@ type ExternalCluster struct {
 	// +optional
 	BarmanObjectStore *BarmanObjectStoreConfiguration `json:"barmanObjectStore,omitempty"`
 
+	// The configuration for the barman-cloud tool suite to be used to
+	// fetch the WAL files of this external cluster, when they are archived
+	// in a different object store than the one described by `barmanObjectStore`,
+	// containing the base backups.
+	// The server name defaults to the one used for the base backups
+	// +optional
+	WALObjectStore *BarmanObjectStoreConfiguration `json:"walObjectStore,omitempty"`
+
 	// The configuration of the plugin that is taking care
 	// of WAL archiving and backups for this external cluster
 	PluginConfiguration *PluginConfiguration `json:"plugin,omitempty"`
@@ -2052,6 +3264,74 @@ type ManagedServices struct {
 	// Additional is a list of additional managed services specified by the user.
 	// +optional
 	Additional []ManagedService `json:"additional,omitempty"`
+	// RO configures the default read-only (`-ro`) service.
+	// +optional
+	RO *ReadOnlyServiceConfiguration `json:"ro,omitempty"`
+	// RW configures the default read-write (`-rw`) service.
+	// +optional
+	RW *ReadWriteServiceConfiguration `json:"rw,omitempty"`
+	// Headless configures the optional headless (`-headless`) service.
+	// +optional
+	Headless *HeadlessServiceConfiguration `json:"headless,omitempty"`
+	// Labels and annotations added to the default `-rw`, `-ro` and `-r`
+	// services, for example to configure the load balancer of the cloud
+	// provider or a service mesh. They take precedence over the inherited
+	// metadata, while the labels and annotations set by the operator
+	// can't be overridden.
+	// +optional
+	DefaultServicesMetadata *EmbeddedObjectMetadata `json:"defaultServicesMetadata,omitempty"`
+}
+
+// ReadWriteServiceConfiguration configures the behavior of the default
+// read-write (`-rw`) service
+type ReadWriteServiceConfiguration struct {
+	// The fully qualified host name that ExternalDNS publishes for the
+	// `-rw` service, set in its `external-dns.alpha.kubernetes.io/hostname`
+	// annotation. As the service always selects the current primary, the
+	// host name follows the primary across switchovers and failovers.
+	// Requires ExternalDNS to be installed in the Kubernetes cluster.
+	// +optional
+	ExternalDNSHostname string `json:"externalDNSHostname,omitempty"`
+}
+
+// ReadOnlyServiceConfiguration configures the behavior of the default
+// read-only (`-ro`) service
+type ReadOnlyServiceConfiguration struct {
+	// When set to `true`, the `-ro` service temporarily includes the primary
+	// when no replica is ready, reverting to the replicas only as soon as
+	// one of them is ready again. This increases the load on the primary
+	// in exchange for the availability of the read-only traffic.
+	// Default: `false`.
+	// +optional
+	FallbackToPrimary bool `json:"fallbackToPrimary,omitempty"`
+}
+
+// SwitchoverOnShutdownConfiguration configures the switchover requested
+// by the primary instance before PostgreSQL is stopped
+type SwitchoverOnShutdownConfiguration struct {
+	// When set to `true`, a `preStop` hook is added to the instances:
+	// when the Pod of the primary is stopped while the cluster is healthy,
+	// for example because its node is being drained, the instance manager
+	// requests a switchover to the most aligned healthy replica and waits
+	// for its promotion before PostgreSQL is stopped. If there is no
+	// healthy replica, PostgreSQL is immediately stopped with a fast
+	// shutdown. Enabling or disabling it requires a rolling update of the
+	// instances. Default: `false`.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+// HeadlessServiceConfiguration configures the headless (`-headless`) service,
+// which publishes every instance under a stable DNS name
+type HeadlessServiceConfiguration struct {
+	// When set to `true`, the operator creates a headless service selecting
+	// all the instances, and every instance is published under the
+	// `<instance>.<cluster>-headless.<namespace>.svc` DNS name, for example
+	// to configure the replication from outside the Kubernetes cluster.
+	// Enabling or disabling it requires a rolling update of the instances.
+	// Default: `false`.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
 }
 
 // ManagedService represents a specific service managed by the cluster.
@@ -2080,6 +3360,97 @@ type ManagedConfiguration struct {
 	// Services roles managed by the `Cluster`
 	// +optional
 	Services *ManagedServices `json:"services,omitempty"`
+	// Periodic verification of the integrity of the data files, executed
+	// on a replica
+	// +optional
+	IntegrityCheck *IntegrityCheckConfiguration `json:"integrityCheck,omitempty"`
+	// Maintenance operations periodically executed by the instance manager
+	// +optional
+	Maintenance *MaintenanceConfiguration `json:"maintenance,omitempty"`
+}
+
+// MaintenanceConfiguration contains the maintenance operations
+// periodically executed by the instance manager
+type MaintenanceConfiguration struct {
+	// Periodic removal of the bloat of a set of tables with `pg_repack`
+	// +optional
+	Repack *RepackConfiguration `json:"repack,omitempty"`
+}
+
+const (
+	// DefaultRepackLockWaitTimeout is the default number of seconds
+	// pg_repack waits to acquire the locks on a table
+	DefaultRepackLockWaitTimeout = 60
+
+	// DefaultRepackMaxReplicationLag is the default maximum replication lag,
+	// in bytes, tolerated when starting a scheduled execution of pg_repack
+	DefaultRepackMaxReplicationLag = 1024 * 1024 * 1024
+)
+
+// RepackConfiguration configures the periodic removal of the bloat of a set
+// of tables with `pg_repack`, which needs to be available in the image and
+// installed as an extension in the databases containing the tables.
+// `pg_repack` rebuilds the tables online on the primary, holding an exclusive
+// lock only for a short time at the beginning and at the end of the operation:
+// unless `terminateConflictingBackends` is enabled, the tables whose locks
+// cannot be acquired within `lockWaitTimeout` are skipped.
+type RepackConfiguration struct {
+	// The schedule follows the same format used in Kubernetes CronJobs,
+	// see https://pkg.go.dev/github.com/robfig/cron#hdr-CRON_Expression_Format
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// The tables to be repacked
+	// +kubebuilder:validation:MinItems=1
+	Tables []RepackTable `json:"tables"`
+
+	// The number of parallel jobs used by `pg_repack` to rebuild the
+	// indexes of a table (default 1)
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Jobs int `json:"jobs,omitempty"`
+
+	// The number of seconds `pg_repack` waits to acquire the locks
+	// on a table (default 60)
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	LockWaitTimeout int `json:"lockWaitTimeout,omitempty"`
+
+	// When enabled, `pg_repack` cancels and then terminates the backends
+	// holding conflicting locks once `lockWaitTimeout` has expired, instead
+	// of skipping the table. Defaults to `false`
+	// +optional
+	TerminateConflictingBackends bool `json:"terminateConflictingBackends,omitempty"`
+
+	// The maximum replication lag of the replicas, as reported by the
+	// primary, tolerated when starting a scheduled run. The run is skipped
+	// when any replica lags behind more than that, as rebuilding the tables
+	// produces a large amount of WAL. Defaults to `1Gi`
+	// +optional
+	MaxReplicationLag string `json:"maxReplicationLag,omitempty"`
+}
+
+// RepackTable is a table to be repacked
+type RepackTable struct {
+	// The name of the database containing the table
+	// +kubebuilder:validation:MinLength=1
+	Database string `json:"database"`
+
+	// The name of the table, optionally qualified with the schema
+	// +kubebuilder:validation:MinLength=1
+	Table string `json:"table"`
+}
+
+// IntegrityCheckConfiguration configures the periodic verification of the
+// integrity of the data files. The check is executed on a replica, preferring
+// a fenced one: on a fenced replica having data checksums enabled, `pg_checksums --check`
+// is used, otherwise the B-tree indexes and the tables are verified online
+// with the non-blocking functions of the `amcheck` extension.
+type IntegrityCheckConfiguration struct {
+	// The schedule follows the same format used in Kubernetes CronJobs,
+	// see https://pkg.go.dev/github.com/robfig/cron#hdr-CRON_Expression_Format
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
 }
 
 // PluginConfiguration specifies a plugin that need to be loaded for this
@@ -2172,6 +3543,13 @@ type RoleConfiguration struct {
 	// +optional
 	InRoles []string `json:"inRoles,omitempty"`
 
+	// List of one or more existing roles to which this role will be
+	// added as a new member WITH ADMIN OPTION, allowing it to grant
+	// the membership in these roles to others. These roles don't need to be
+	// repeated in `inRoles`. Default empty.
+	// +optional
+	InRolesWithAdminOption []string `json:"inRolesWithAdminOption,omitempty"`
+
 	// Whether a role "inherits" the privileges of roles it is a member of.
 	// Defaults is `true`.
 	// +kubebuilder:default:=true
@@ -2319,6 +3697,12 @@ type ConfigMapResourceVersion struct {
 	// Map keys are the config map names, map values are the versions
 	// +optional
 	Metrics map[string]string `json:"metrics,omitempty"`
+
+	// A map with the versions of all the config maps containing the
+	// PostgreSQL configuration files to be included.
+	// Map keys are the config map names, map values are the versions
+	// +optional
+	IncludedConfiguration map[string]string `json:"includedConfiguration,omitempty"`
 }
 
 func init() {
@@ -18,11 +18,16 @@ package v1
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
+	"regexp"
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
+	barmanApi "github.com/cloudnative-pg/barman-cloud/pkg/api"
 	barmanWebhooks "github.com/cloudnative-pg/barman-cloud/pkg/api/webhooks"
 	"github.com/cloudnative-pg/machinery/pkg/image/reference"
 	"github.com/cloudnative-pg/machinery/pkg/log"
@@ -30,9 +35,11 @@ import (
 	"github.com/cloudnative-pg/machinery/pkg/stringset"
 	"github.com/cloudnative-pg/machinery/pkg/types"
 	storagesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	"github.com/robfig/cron"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -63,7 +70,21 @@ const (
 	DefaultApplicationUserName = DefaultApplicationDatabaseName
 )
 
-const sharedBuffersParameter = "shared_buffers"
+const (
+	sharedBuffersParameter           = "shared_buffers"
+	timezoneParameter                = "timezone"
+	dateStyleParameter               = "datestyle"
+	maxPreparedTransactionsParameter = "max_prepared_transactions"
+	maxConnectionsParameter          = "max_connections"
+
+	// defaultMaxConnections is the default value of max_connections
+	// in PostgreSQL
+	defaultMaxConnections = 100
+
+	// maxPreparedTransactionsLimit is the highest value accepted by
+	// PostgreSQL for max_prepared_transactions
+	maxPreparedTransactionsLimit = 262143
+)
 
 // clusterLog is for logging in this package.
 var clusterLog = log.WithName("cluster-resource").WithValues("version", "v1")
@@ -164,6 +185,12 @@ func (r *Cluster) setDefaults(preserveUserSettings bool) {
 			Enabled: ptr.To(true),
 		}
 	}
+	// The cluster-wide prefix, when set, namespaces the HA replication slots too
+	if prefix := r.Spec.ReplicationSlots.Prefix; prefix != "" &&
+		(r.Spec.ReplicationSlots.HighAvailability.SlotPrefix == "" ||
+			r.Spec.ReplicationSlots.HighAvailability.SlotPrefix == DefaultReplicationSlotsHASlotPrefix) {
+		r.Spec.ReplicationSlots.HighAvailability.SlotPrefix = prefix
+	}
 
 	if len(r.Spec.Tablespaces) > 0 {
 		r.defaultTablespaces()
@@ -274,6 +301,10 @@ func (r *Cluster) defaultInitDB() {
 	if r.Spec.Bootstrap.InitDB.Encoding == "" {
 		r.Spec.Bootstrap.InitDB.Encoding = "UTF8"
 	}
+	// When the generic locale is set, initdb derives the categories from it
+	if r.Spec.Bootstrap.InitDB.Locale != "" {
+		return
+	}
 	if r.Spec.Bootstrap.InitDB.LocaleCollate == "" {
 		r.Spec.Bootstrap.InitDB.LocaleCollate = "C"
 	}
@@ -310,8 +341,10 @@ var _ webhook.Validator = &Cluster{}
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (r *Cluster) ValidateCreate() (admission.Warnings, error) {
 	clusterLog.Info("validate create", "name", r.Name, "namespace", r.Namespace)
-	allErrs := r.Validate()
-	allWarnings := r.getAdmissionWarnings()
+	allErrs := append(r.Validate(), r.validateMinPostgresMajor()...)
+	allErrs = append(allErrs, r.validateFencedUntil(nil)...)
+	allWarnings := append(r.getAdmissionWarnings(), r.getMinPostgresMajorAdmissionWarnings()...)
+	allWarnings = append(allWarnings, r.getFencingAdmissionWarnings(nil)...)
 
 	if len(allErrs) == 0 {
 		return allWarnings, nil
@@ -341,6 +374,7 @@ func (r *Cluster) Validate() (allErrs field.ErrorList) {
 		r.validateMaxSyncReplicas,
 		r.validateStorageSize,
 		r.validateWalStorageSize,
+		r.validateDiskPressure,
 		r.validateEphemeralVolumeSource,
 		r.validateTablespaceStorageSize,
 		r.validateName,
@@ -349,20 +383,31 @@ func (r *Cluster) Validate() (allErrs field.ErrorList) {
 		r.validateTablespaceBackupSnapshot,
 		r.validateBootstrapRecoverySource,
 		r.validateBootstrapRecoveryDataSource,
+		r.validateBootstrapRecoveryValidationSQL,
+		r.validateJobRetention,
 		r.validateExternalClusters,
 		r.validateTolerations,
+		r.validateInheritedMetadata,
 		r.validateAntiAffinity,
 		r.validateReplicaMode,
 		r.validateBackupConfiguration,
 		r.validateRetentionPolicy,
 		r.validateConfiguration,
+		r.validatePgHBA,
+		r.validatePgIdent,
 		r.validateSynchronousReplicaConfiguration,
 		r.validateLDAP,
 		r.validateReplicationSlots,
+		r.validateReplication,
+		r.validateFailoverTopology,
+		r.validatePrimaryPreference,
 		r.validateEnv,
 		r.validateManagedServices,
 		r.validateManagedRoles,
+		r.validateIntegrityCheck,
+		r.validateRepack,
 		r.validateManagedExtensions,
+		r.validateMajorUpgrade,
 		r.validateResources,
 		r.validateHibernationAnnotation,
 		r.validatePromotionToken,
@@ -389,7 +434,8 @@ func (r *Cluster) ValidateUpdate(old runtime.Object) (admission.Warnings, error)
 	)
 
 	if len(allErrs) == 0 {
-		return r.getAdmissionWarnings(), nil
+		allWarnings := append(r.getAdmissionWarnings(), r.getLocaleChangeAdmissionWarnings(oldCluster)...)
+		return append(allWarnings, r.getFencingAdmissionWarnings(oldCluster)...), nil
 	}
 
 	return nil, apierrors.NewInvalid(
@@ -416,6 +462,8 @@ func (r *Cluster) ValidateChanges(old *Cluster) (allErrs field.ErrorList) {
 		r.validateReplicationSlotsChange,
 		r.validateWALLevelChange,
 		r.validateReplicaClusterChange,
+		r.validateWalSegmentSizeChange,
+		r.validateFencedUntil,
 	}
 	for _, validate := range validations {
 		allErrs = append(allErrs, validate(old)...)
@@ -432,6 +480,53 @@ func (r *Cluster) ValidateDelete() (admission.Warnings, error) {
 	return nil, nil
 }
 
+// validateFailoverTopology validates the topology key used to rank
+// the failover candidates
+func (r *Cluster) validateFailoverTopology() field.ErrorList {
+	if r.Spec.FailoverTopology == nil || r.Spec.FailoverTopology.TopologyKey == "" {
+		return nil
+	}
+
+	var result field.ErrorList
+	topologyKey := r.Spec.FailoverTopology.TopologyKey
+	for _, msg := range validationutil.IsQualifiedName(topologyKey) {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "failoverTopology", "topologyKey"),
+			topologyKey,
+			msg))
+	}
+
+	return result
+}
+
+// validatePrimaryPreference validates the maintenance windows of the
+// automatic switchback to the preferred node of the primary
+func (r *Cluster) validatePrimaryPreference() field.ErrorList {
+	autoSwitchback := r.GetAutoSwitchback()
+	if autoSwitchback == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	windowsPath := field.NewPath("spec", "primaryPreference", "autoSwitchback", "maintenanceWindows")
+	for idx, window := range autoSwitchback.MaintenanceWindows {
+		if _, err := cron.Parse(window.Schedule); err != nil {
+			result = append(result, field.Invalid(
+				windowsPath.Index(idx).Child("schedule"),
+				window.Schedule,
+				err.Error()))
+		}
+		if window.Duration.Duration <= 0 {
+			result = append(result, field.Invalid(
+				windowsPath.Index(idx).Child("duration"),
+				window.Duration.String(),
+				"the duration of the maintenance window must be positive"))
+		}
+	}
+
+	return result
+}
+
 // validateLDAP validates the ldap postgres configuration
 func (r *Cluster) validateLDAP() field.ErrorList {
 	// No validating if not specified
@@ -528,6 +623,17 @@ func (r *Cluster) validateInitDB() field.ErrorList {
 				"WAL segment size must be a power of 2"))
 	}
 
+	if initDBOptions.ConnectionLimit != nil && *initDBOptions.ConnectionLimit < -1 {
+		result = append(
+			result,
+			field.Invalid(
+				field.NewPath("spec", "bootstrap", "initdb", "connectionLimit"),
+				*initDBOptions.ConnectionLimit,
+				"connection limit must be -1 (no limit) or a non-negative number"))
+	}
+
+	result = append(result, r.validateInitDBLocale()...)
+
 	if initDBOptions.PostInitApplicationSQLRefs != nil {
 		for _, item := range initDBOptions.PostInitApplicationSQLRefs.SecretRefs {
 			if item.Name == "" || item.Key == "" {
@@ -555,6 +661,64 @@ func (r *Cluster) validateInitDB() field.ErrorList {
 	return result
 }
 
+// validateInitDBLocale checks that the locale provider settings passed
+// to initdb are consistent and supported by the PostgreSQL version in use
+func (r *Cluster) validateInitDBLocale() field.ErrorList {
+	var result field.ErrorList
+
+	initDBOptions := r.Spec.Bootstrap.InitDB
+	basePath := field.NewPath("spec", "bootstrap", "initdb")
+
+	// The requirements are validated only when the version can be
+	// detected, the validateImageName function will report the error
+	// otherwise
+	var pgMajor uint64
+	if pgVersion, err := r.GetPostgresqlVersion(); err == nil {
+		pgMajor = pgVersion.Major()
+	}
+
+	requireProvider := func(name, value, provider string) {
+		if value != "" && initDBOptions.LocaleProvider != provider {
+			result = append(result, field.Invalid(
+				basePath.Child(name),
+				value,
+				fmt.Sprintf("%s requires localeProvider to be %q", name, provider)))
+		}
+	}
+	requireVersion := func(name, value string, major uint64) {
+		if value != "" && pgMajor != 0 && pgMajor < major {
+			result = append(result, field.Invalid(
+				basePath.Child(name),
+				value,
+				fmt.Sprintf("%s requires PostgreSQL %d or newer", name, major)))
+		}
+	}
+
+	requireProvider("icuLocale", initDBOptions.IcuLocale, "icu")
+	requireProvider("icuRules", initDBOptions.IcuRules, "icu")
+	requireProvider("builtinLocale", initDBOptions.BuiltinLocale, "builtin")
+	requireVersion("icuRules", initDBOptions.IcuRules, 16)
+
+	switch initDBOptions.LocaleProvider {
+	case "icu":
+		requireVersion("localeProvider", initDBOptions.LocaleProvider, 15)
+		if initDBOptions.IcuLocale == "" && initDBOptions.Locale == "" {
+			result = append(result, field.Required(
+				basePath.Child("icuLocale"),
+				"icuLocale or locale must be set when localeProvider is \"icu\""))
+		}
+	case "builtin":
+		requireVersion("localeProvider", initDBOptions.LocaleProvider, 17)
+		if initDBOptions.BuiltinLocale == "" && initDBOptions.Locale == "" {
+			result = append(result, field.Required(
+				basePath.Child("builtinLocale"),
+				"builtinLocale or locale must be set when localeProvider is \"builtin\""))
+		}
+	}
+
+	return result
+}
+
 func (r *Cluster) validateImport() field.ErrorList {
 	// If it's not configured, everything is ok
 	if r.Spec.Bootstrap == nil {
@@ -879,6 +1043,56 @@ func (r *Cluster) validateBootstrapRecoverySource() field.ErrorList {
 	return result
 }
 
+// validateBootstrapRecoveryValidationSQL is used to ensure that the
+// queries validating the recovered data can be run
+func (r *Cluster) validateBootstrapRecoveryValidationSQL() field.ErrorList {
+	if r.Spec.Bootstrap == nil || r.Spec.Bootstrap.Recovery == nil ||
+		len(r.Spec.Bootstrap.Recovery.ValidationSQL) == 0 {
+		return nil
+	}
+
+	var result field.ErrorList
+	path := field.NewPath("spec", "bootstrap", "recovery", "validationSQL")
+
+	// A replica cluster never exits from recovery, so the recovered
+	// data can't be validated before starting it
+	if r.IsReplica() {
+		result = append(result, field.Forbidden(
+			path,
+			"validating the recovered data is not allowed in a replica cluster"))
+	}
+
+	for idx, query := range r.Spec.Bootstrap.Recovery.ValidationSQL {
+		if strings.TrimSpace(query) == "" {
+			result = append(result, field.Invalid(
+				path.Index(idx),
+				query,
+				"the validation query cannot be empty"))
+		}
+	}
+
+	return result
+}
+
+// validateJobRetention is used to ensure that the retention policy
+// of the completed Jobs is valid
+func (r *Cluster) validateJobRetention() field.ErrorList {
+	if r.Spec.JobRetention == nil || r.Spec.JobRetention.TTL == nil {
+		return nil
+	}
+
+	if r.Spec.JobRetention.TTL.Duration < 0 {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "jobRetention", "ttl"),
+				r.Spec.JobRetention.TTL.Duration.String(),
+				"the retention of the completed Jobs cannot be negative"),
+		}
+	}
+
+	return nil
+}
+
 // validateBootstrapRecoveryDataSource is used to ensure that the data
 // source is correctly defined
 func (r *Cluster) validateBootstrapRecoveryDataSource() field.ErrorList {
@@ -998,6 +1212,41 @@ func (r *Cluster) validateImageName() field.ErrorList {
 	return result
 }
 
+// validateMinPostgresMajor rejects the new clusters running a PostgreSQL
+// major version older than the minimum one allowed by the operator
+func (r *Cluster) validateMinPostgresMajor() field.ErrorList {
+	minMajor := configuration.Current.MinPostgresMajor
+	if minMajor <= 0 {
+		return nil
+	}
+
+	pgVersion, err := r.GetPostgresqlVersion()
+	if err != nil {
+		// We can't detect the version: a warning will be raised by
+		// getMinPostgresMajorAdmissionWarnings
+		return nil
+	}
+
+	if pgVersion.Major() >= uint64(minMajor) {
+		return nil
+	}
+
+	path := field.NewPath("spec", "imageName")
+	value := r.GetImageName()
+	if r.Spec.ImageCatalogRef != nil {
+		path = field.NewPath("spec", "imageCatalogRef", "major")
+		value = strconv.Itoa(r.Spec.ImageCatalogRef.Major)
+	}
+
+	return field.ErrorList{
+		field.Invalid(
+			path,
+			value,
+			fmt.Sprintf("PostgreSQL %d is not allowed: the operator requires at least PostgreSQL %d",
+				pgVersion.Major(), minMajor)),
+	}
+}
+
 // validateImagePullPolicy validates the image pull policy,
 // ensuring it is one of "Always", "Never" or "IfNotPresent" when defined
 func (r *Cluster) validateImagePullPolicy() field.ErrorList {
@@ -1075,6 +1324,259 @@ func (r *Cluster) validateResources() field.ErrorList {
 	return result
 }
 
+// validatePgHBA checks the syntax of the pg_hba rules, once their
+// placeholders are expanded
+func (r *Cluster) validatePgHBA() field.ErrorList {
+	var result field.ErrorList
+
+	result = append(result, r.validateHBARules(
+		field.NewPath("spec", "postgresql", "pg_hba"),
+		r.Spec.PostgresConfiguration.PgHBA,
+		true)...)
+	result = append(result, r.validateHBARules(
+		field.NewPath("spec", "postgresql", "pgHBAPrepend"),
+		r.Spec.PostgresConfiguration.PgHBAPrepend,
+		false)...)
+
+	return result
+}
+
+// validateHBARules checks the syntax of a list of pg_hba rules
+func (r *Cluster) validateHBARules(path *field.Path, rules []string, allowLocal bool) field.ErrorList {
+	var result field.ErrorList
+
+	for idx, rule := range rules {
+		rulePath := path.Index(idx)
+		expandedRule, err := r.expandHBARule(rule)
+		if err != nil {
+			result = append(result, field.Invalid(rulePath, rule,
+				fmt.Sprintf("invalid rule template, only {{.ReplicationUser}}, {{.ApplicationUser}} and "+
+					"{{.ApplicationDatabase}} can be referenced: %v", err)))
+			continue
+		}
+
+		if err := validateHBARule(expandedRule); err != nil {
+			result = append(result, field.Invalid(rulePath, rule, err.Error()))
+			continue
+		}
+
+		if fields := splitConfigurationEntry(expandedRule); !allowLocal && len(fields) > 0 && fields[0] == "local" {
+			result = append(result, field.Invalid(rulePath, rule,
+				"local rules can't precede the ones set by the operator"))
+		}
+	}
+
+	return result
+}
+
+// hbaConnectionTypes are the connection types accepted in pg_hba.conf
+var hbaConnectionTypes = stringset.From([]string{
+	"local", "host", "hostssl", "hostnossl", "hostgssenc", "hostnogssenc",
+})
+
+// hbaAuthenticationMethods are the authentication methods accepted in pg_hba.conf
+var hbaAuthenticationMethods = stringset.From([]string{
+	"trust", "reject", "scram-sha-256", "md5", "password", "gss", "sspi",
+	"ident", "peer", "ldap", "radius", "cert", "pam", "bsd", "oauth",
+})
+
+// hbaHostnameRegex matches the host names, and the domain suffixes
+// starting with a dot, accepted in the address field of pg_hba.conf
+var hbaHostnameRegex = regexp.MustCompile(
+	`^\.?[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?)*$`)
+
+// validateHBARule checks the syntax of a pg_hba.conf rule, as PostgreSQL
+// would do when reloading the configuration. Empty lines and comments are
+// accepted, while the inclusion of other files is not
+func validateHBARule(rule string) error {
+	if strings.ContainsAny(rule, "\r\n") {
+		return errors.New("a rule can't span multiple lines")
+	}
+	if strings.Count(rule, `"`)%2 != 0 {
+		return errors.New("unterminated quoted string")
+	}
+
+	fields := splitConfigurationEntry(rule)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	connectionType := fields[0]
+	if strings.HasPrefix(connectionType, "include") {
+		return fmt.Errorf("%q directives are not supported", connectionType)
+	}
+	if !hbaConnectionTypes.Has(connectionType) {
+		return fmt.Errorf("invalid connection type %q", connectionType)
+	}
+
+	// The local connections have no address
+	minFields := 5
+	if connectionType == "local" {
+		minFields = 4
+	}
+	if len(fields) < minFields {
+		return errors.New("missing fields, the rule must contain the connection type, the database, " +
+			"the user, the address (when not local) and the authentication method")
+	}
+
+	if err := validateHBAList("database", fields[1]); err != nil {
+		return err
+	}
+	if err := validateHBAList("user", fields[2]); err != nil {
+		return err
+	}
+
+	methodIndex := 3
+	if connectionType != "local" {
+		addressFields, err := validateHBAAddress(fields[3:])
+		if err != nil {
+			return err
+		}
+		methodIndex += addressFields
+	}
+	if methodIndex >= len(fields) {
+		return errors.New("missing authentication method")
+	}
+
+	method := fields[methodIndex]
+	if !hbaAuthenticationMethods.Has(method) {
+		return fmt.Errorf("invalid authentication method %q", method)
+	}
+	if method == "cert" && connectionType != "hostssl" {
+		return errors.New("the cert authentication method is only supported on hostssl connections")
+	}
+	if method == "peer" && connectionType != "local" {
+		return errors.New("the peer authentication method is only supported on local connections")
+	}
+
+	for _, option := range fields[methodIndex+1:] {
+		if name, _, found := strings.Cut(option, "="); !found || name == "" {
+			return fmt.Errorf("invalid authentication option %q, expected name=value", option)
+		}
+	}
+
+	return nil
+}
+
+// validateHBAAddress checks the address of a pg_hba.conf rule, which
+// can be followed by a separate IP mask, and returns the number of
+// fields it uses
+func validateHBAAddress(fields []string) (int, error) {
+	address := fields[0]
+	switch {
+	case address == "all" || address == "samehost" || address == "samenet":
+		return 1, nil
+
+	case strings.Contains(address, "/"):
+		if _, _, err := net.ParseCIDR(address); err != nil {
+			return 0, fmt.Errorf("invalid address %q", address)
+		}
+		return 1, nil
+
+	case net.ParseIP(address) != nil:
+		if len(fields) < 2 || net.ParseIP(fields[1]) == nil {
+			return 0, fmt.Errorf("the IP address %q must be followed by a mask, or use the CIDR notation", address)
+		}
+		return 2, nil
+
+	case hbaHostnameRegex.MatchString(address):
+		return 1, nil
+
+	default:
+		return 0, fmt.Errorf("invalid address %q", address)
+	}
+}
+
+// validateHBAList checks a comma separated list of database or user
+// names, where every element can be quoted
+func validateHBAList(fieldName string, value string) error {
+	inQuotes := false
+	elementLength := 0
+	for _, char := range value {
+		switch {
+		case char == '"':
+			inQuotes = !inQuotes
+			elementLength++
+		case char == ',' && !inQuotes:
+			if elementLength == 0 {
+				return fmt.Errorf("empty element in the %s field %q", fieldName, value)
+			}
+			elementLength = 0
+		default:
+			elementLength++
+		}
+	}
+
+	if elementLength == 0 {
+		return fmt.Errorf("empty element in the %s field %q", fieldName, value)
+	}
+	return nil
+}
+
+// validatePgIdent checks that every user name map entry is made of
+// the map name, the system user name and the PostgreSQL user name
+func (r *Cluster) validatePgIdent() field.ErrorList {
+	var result field.ErrorList
+
+	for idx, entry := range r.Spec.PostgresConfiguration.PgIdent {
+		path := field.NewPath("spec", "postgresql", "pg_ident").Index(idx)
+		if strings.ContainsAny(entry, "\r\n") {
+			result = append(result, field.Invalid(path, entry,
+				"a user name map entry can't span multiple lines"))
+			continue
+		}
+
+		tokens := splitConfigurationEntry(entry)
+		switch {
+		case len(tokens) == 0:
+			// Empty lines and comments are allowed
+		case len(tokens) != 3:
+			result = append(result, field.Invalid(path, entry,
+				"a user name map entry must be in the 'map-name system-username database-username' format"))
+		case tokens[0] == "local":
+			result = append(result, field.Invalid(path, entry,
+				"the 'local' user name map is reserved for the operator"))
+		}
+	}
+
+	return result
+}
+
+// splitConfigurationEntry splits a pg_hba.conf or pg_ident.conf entry in its tokens, honoring
+// double quotes and ignoring everything following a comment
+func splitConfigurationEntry(entry string) []string {
+	var tokens []string
+	var current strings.Builder
+	inQuotes := false
+
+	for _, c := range entry {
+		switch {
+		case c == '"':
+			inQuotes = !inQuotes
+			current.WriteRune(c)
+		case inQuotes:
+			current.WriteRune(c)
+		case c == '#':
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+			}
+			return tokens
+		case c == ' ' || c == '\t':
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(c)
+		}
+	}
+
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens
+}
+
 func (r *Cluster) validateSynchronousReplicaConfiguration() field.ErrorList {
 	if r.Spec.PostgresConfiguration.Synchronous == nil {
 		return nil
@@ -1094,6 +1596,17 @@ func (r *Cluster) validateSynchronousReplicaConfiguration() field.ErrorList {
 		result = append(result, err)
 	}
 
+	if lagRotation := r.Spec.PostgresConfiguration.Synchronous.LagRotation; lagRotation != nil &&
+		lagRotation.MaxLag != "" {
+		maxLag, err := resource.ParseQuantity(lagRotation.MaxLag)
+		if err != nil || maxLag.Sign() <= 0 {
+			result = append(result, field.Invalid(
+				field.NewPath("spec", "postgresql", "synchronous", "lagRotation", "maxLag"),
+				lagRotation.MaxLag,
+				"The maximum lag must be a positive quantity"))
+		}
+	}
+
 	return result
 }
 
@@ -1199,6 +1712,40 @@ func (r *Cluster) validateConfiguration() field.ErrorList {
 		}
 	}
 
+	for key, value := range r.Spec.PostgresConfiguration.Parameters {
+		switch strings.ToLower(key) {
+		case timezoneParameter:
+			if !isValidTimezone(value) {
+				result = append(
+					result,
+					field.Invalid(
+						field.NewPath("spec", "postgresql", "parameters", key),
+						value,
+						"invalid `timezone`. Must be a time zone name, such as `Europe/Rome`, "+
+							"or a POSIX time zone specification, such as `UTC+3`"))
+			}
+		case dateStyleParameter:
+			if err := validateDateStyle(value); err != nil {
+				result = append(
+					result,
+					field.Invalid(
+						field.NewPath("spec", "postgresql", "parameters", key),
+						value,
+						fmt.Sprintf("invalid `datestyle`: %v", err)))
+			}
+		case maxPreparedTransactionsParameter:
+			if n, err := strconv.Atoi(value); err != nil || n < 0 || n > maxPreparedTransactionsLimit {
+				result = append(
+					result,
+					field.Invalid(
+						field.NewPath("spec", "postgresql", "parameters", key),
+						value,
+						fmt.Sprintf("invalid `max_prepared_transactions`: must be an integer between 0 and %d",
+							maxPreparedTransactionsLimit)))
+			}
+		}
+	}
+
 	walLogHintsValue, walLogHintsSet := r.Spec.PostgresConfiguration.Parameters[postgres.ParameterWalLogHints]
 	if walLogHintsSet {
 		walLogHintsActivated, err := postgres.ParsePostgresConfigBoolean(walLogHintsValue)
@@ -1224,6 +1771,10 @@ func (r *Cluster) validateConfiguration() field.ErrorList {
 	result = append(result, validateWalSizeConfiguration(
 		r.Spec.PostgresConfiguration, r.Spec.WalStorage.GetSizeOrNil())...)
 
+	// verify min_wal_size and max_wal_size can hold at least two WAL segments
+	result = append(result, validateWalSizeAgainstSegmentSize(
+		r.Spec.PostgresConfiguration, getInitDBWalSegmentSize(r))...)
+
 	if err := validateSyncReplicaElectionConstraint(
 		r.Spec.PostgresConfiguration.SyncReplicaElectionConstraint,
 	); err != nil {
@@ -1233,6 +1784,49 @@ func (r *Cluster) validateConfiguration() field.ErrorList {
 	return result
 }
 
+// timezoneNameRegex matches the names of the time zones in the IANA
+// database (e.g. `Europe/Rome`, `Etc/GMT+3`, `UTC`)
+var timezoneNameRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_+\-]*(/[A-Za-z0-9_+\-]+)*$`)
+
+// timezonePosixRegex matches a POSIX time zone specification
+// (e.g. `UTC+3`, `<+0330>-3:30`, `EST5EDT,M3.2.0,M11.1.0`)
+var timezonePosixRegex = regexp.MustCompile(
+	`^([A-Za-z]{3,}|<[A-Za-z0-9+\-]+>)[+\-]?\d{1,2}(:\d{2}){0,2}` +
+		`(([A-Za-z]{3,}|<[A-Za-z0-9+\-]+>)([+\-]?\d{1,2}(:\d{2}){0,2})?(,[A-Za-z0-9.:/]+){0,2})?$`)
+
+// isValidTimezone checks if the passed value is an acceptable value for
+// the PostgreSQL `timezone` parameter. The check is only syntactic as the
+// time zone database used by PostgreSQL lives in the operand image.
+func isValidTimezone(value string) bool {
+	return timezoneNameRegex.MatchString(value) || timezonePosixRegex.MatchString(value)
+}
+
+// validateDateStyle checks if the passed value is an acceptable value for
+// the PostgreSQL `datestyle` parameter, which is made of an output format
+// specification and/or an input/output ordering of the day, month and year
+func validateDateStyle(value string) error {
+	var outputFormat, fieldOrder string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		switch strings.ToLower(item) {
+		case "iso", "postgres", "sql", "german":
+			if outputFormat != "" && !strings.EqualFold(outputFormat, item) {
+				return fmt.Errorf("conflicting output formats %q and %q", outputFormat, item)
+			}
+			outputFormat = item
+		case "dmy", "euro", "european", "mdy", "us", "noneuro", "noneuropean", "ymd":
+			if fieldOrder != "" && !strings.EqualFold(fieldOrder, item) {
+				return fmt.Errorf("conflicting field orders %q and %q", fieldOrder, item)
+			}
+			fieldOrder = item
+		default:
+			return fmt.Errorf("unknown keyword %q", item)
+		}
+	}
+
+	return nil
+}
+
 // validateWalSizeConfiguration verifies that min_wal_size < max_wal_size < wal volume size
 func validateWalSizeConfiguration(
 	postgresConfig PostgresConfiguration, walVolumeSize *resource.Quantity,
@@ -1318,6 +1912,54 @@ func validateWalSizeConfiguration(
 	return result
 }
 
+// getInitDBWalSegmentSize returns the WAL segment size, in megabytes,
+// requested for the initdb bootstrap, or 0 when the default is used
+func getInitDBWalSegmentSize(cluster *Cluster) int {
+	if cluster.Spec.Bootstrap == nil || cluster.Spec.Bootstrap.InitDB == nil {
+		return 0
+	}
+
+	return cluster.Spec.Bootstrap.InitDB.WalSegmentSize
+}
+
+// validateWalSizeAgainstSegmentSize verifies that min_wal_size and
+// max_wal_size, when specified, are at least twice the WAL segment size,
+// as PostgreSQL would otherwise refuse to start
+func validateWalSizeAgainstSegmentSize(
+	postgresConfig PostgresConfiguration, walSegmentSize int,
+) field.ErrorList {
+	if walSegmentSize == 0 {
+		return nil
+	}
+
+	var result field.ErrorList
+	minimumValue := resource.MustParse(fmt.Sprintf("%dMi", 2*walSegmentSize))
+	for _, key := range []string{"min_wal_size", "max_wal_size"} {
+		value := postgresConfig.Parameters[key]
+		if value == "" {
+			continue
+		}
+
+		quantity, err := parsePostgresQuantityValue(value)
+		if err != nil {
+			// Already reported by validateWalSizeConfiguration
+			continue
+		}
+
+		if quantity.Cmp(minimumValue) < 0 {
+			result = append(
+				result,
+				field.Invalid(
+					field.NewPath("spec", "postgresql", "parameters", key),
+					value,
+					fmt.Sprintf("Invalid value. Parameter %s should be at least twice the WAL segment size (%dMB)",
+						key, walSegmentSize)))
+		}
+	}
+
+	return result
+}
+
 // parsePostgresQuantityValue converts the  sizes in the PostgreSQL configuration
 // into kubernetes resource.Quantity values
 // Ref: Numeric with Unit @ https://www.postgresql.org/docs/current/config-setting.html#CONFIG-SETTING-NAMES-VALUES
@@ -1419,20 +2061,40 @@ func (r *Cluster) validateImageChange(old *Cluster) field.ErrorList {
 		return result
 	}
 
-	status := version.IsUpgradePossible(oldVersion, newVersion)
+	switch {
+	case version.IsUpgradePossible(oldVersion, newVersion):
+		// Minor version changes are applied with a rolling update
+
+	case old.Status.PGDataImageInfo != nil &&
+		newVersion.Major() == uint64(old.Status.PGDataImageInfo.MajorVersion): //nolint:gosec
+		// Going back to the major version of the data directories,
+		// after an upgrade that didn't complete
 
-	if !status {
+	case newVersion.Major() < oldVersion.Major():
 		result = append(
 			result,
 			field.Invalid(
 				newImagePath,
 				newVersion,
-				fmt.Sprintf("can't upgrade between majors %v and %v",
-					oldVersion, newVersion)))
-	}
+				fmt.Sprintf("can't downgrade from major %v to %v",
+					oldVersion.Major(), newVersion.Major())))
 
-	return result
-}
+	case !r.ShouldUpgradeMajorVersionInPlace() || !utils.IsMajorUpgradeAllowed(&r.ObjectMeta):
+		// The data directories can only be moved to a newer major version
+		// with pg_upgrade, and the annotation guards against unintended
+		// image changes
+		result = append(
+			result,
+			field.Invalid(
+				newImagePath,
+				newVersion,
+				fmt.Sprintf("can't upgrade between majors %v and %v without the %q major upgrade strategy "+
+					"and the %q annotation set to \"true\"",
+					oldVersion, newVersion, MajorUpgradeStrategyPgUpgrade, utils.AllowMajorUpgradeAnnotationName)))
+	}
+
+	return result
+}
 
 // Validate the recovery target to ensure that the mutual exclusivity
 // of options is respected and plus validating the format of targetTime
@@ -1499,6 +2161,23 @@ func (r *Cluster) validateRecoveryTarget() field.ErrorList {
 		}
 	}
 
+	// PostgreSQL takes the target action only when stopping
+	// at a recovery target, which a replica cluster never does
+	if targetAction := recoveryTarget.GetTargetAction(); targetAction != RecoveryTargetActionPromote {
+		if !recoveryTarget.HasTarget() {
+			result = append(result, field.Invalid(
+				field.NewPath("spec", "bootstrap", "recovery", "recoveryTarget", "targetAction"),
+				targetAction,
+				"the target action can be set to 'pause' or 'shutdown' only when a recovery target is specified"))
+		}
+		if r.IsReplica() {
+			result = append(result, field.Invalid(
+				field.NewPath("spec", "bootstrap", "recovery", "recoveryTarget", "targetAction"),
+				targetAction,
+				"the target action can't be set to 'pause' or 'shutdown' in a replica cluster"))
+		}
+	}
+
 	return result
 }
 
@@ -1618,6 +2297,47 @@ func (r *Cluster) validateWalStorageSize() field.ErrorList {
 	return result
 }
 
+func (r *Cluster) validateDiskPressure() field.ErrorList {
+	var result field.ErrorList
+
+	diskPressure := r.Spec.DiskPressure
+	if diskPressure == nil {
+		return result
+	}
+
+	maxSizePath := field.NewPath("spec", "diskPressure", "maxSize")
+	if diskPressure.MaxSize == "" {
+		if diskPressure.AutoExpand {
+			result = append(result, field.Required(
+				maxSizePath,
+				"Maximum size is required when the automatic expansion is enabled"))
+		}
+		return result
+	}
+
+	maxSize, err := resource.ParseQuantity(diskPressure.MaxSize)
+	if err != nil {
+		return append(result, field.Invalid(
+			maxSizePath,
+			diskPressure.MaxSize,
+			"Maximum size value isn't valid"))
+	}
+
+	if r.Spec.StorageConfiguration.Size == "" {
+		return result
+	}
+
+	size, err := resource.ParseQuantity(r.Spec.StorageConfiguration.Size)
+	if err == nil && maxSize.Cmp(size) < 0 {
+		result = append(result, field.Invalid(
+			maxSizePath,
+			diskPressure.MaxSize,
+			"Maximum size cannot be lower than the storage size"))
+	}
+
+	return result
+}
+
 func (r *Cluster) validateEphemeralVolumeSource() field.ErrorList {
 	var result field.ErrorList
 
@@ -1886,6 +2606,16 @@ func (r *Cluster) validateExternalCluster(externalCluster *ExternalCluster, path
 				"one of connectionParameters, plugin and barmanObjectStore is required"))
 	}
 
+	result = append(result, validateInheritedCredentials(
+		externalCluster.BarmanObjectStore,
+		path.Child("barmanObjectStore"),
+	)...)
+	result = append(result, validateWALObjectStore(
+		externalCluster.BarmanObjectStore,
+		externalCluster.WALObjectStore,
+		path.Child("walObjectStore"),
+	)...)
+
 	return result
 }
 
@@ -1930,6 +2660,28 @@ func (r *Cluster) validateUnixPermissionIdentifierChange(old *Cluster) field.Err
 	return result
 }
 
+// validateWalSegmentSizeChange prevents changing the WAL segment size
+// after the cluster has been bootstrapped, as it is fixed by initdb
+func (r *Cluster) validateWalSegmentSizeChange(old *Cluster) field.ErrorList {
+	if r.Spec.Bootstrap == nil || r.Spec.Bootstrap.InitDB == nil ||
+		old.Spec.Bootstrap == nil || old.Spec.Bootstrap.InitDB == nil {
+		return nil
+	}
+
+	newWalSegmentSize := getInitDBWalSegmentSize(r)
+	oldWalSegmentSize := getInitDBWalSegmentSize(old)
+	if newWalSegmentSize == oldWalSegmentSize {
+		return nil
+	}
+
+	return field.ErrorList{
+		field.Invalid(
+			field.NewPath("spec", "bootstrap", "initdb", "walSegmentSize"),
+			newWalSegmentSize,
+			"walSegmentSize is an immutable field in the spec"),
+	}
+}
+
 func (r *Cluster) validatePromotionToken() field.ErrorList {
 	var result field.ErrorList
 
@@ -2072,6 +2824,34 @@ func (r *Cluster) validateReplicaClusterExternalClusters() field.ErrorList {
 	return result
 }
 
+// validateInheritedMetadata checks that the labels to be propagated to every
+// managed resource are well-formed and don't clash with the ones reserved to
+// the operator
+func (r *Cluster) validateInheritedMetadata() field.ErrorList {
+	if r.Spec.InheritedMetadata == nil {
+		return nil
+	}
+
+	return validateUserLabels(field.NewPath("spec", "inheritedMetadata", "labels"), r.Spec.InheritedMetadata.Labels)
+}
+
+// validateUserLabels checks that the labels requested by the user are valid
+// and don't use the keys reserved to the operator
+func validateUserLabels(path *field.Path, labels map[string]string) field.ErrorList {
+	allErrors := validation.ValidateLabels(labels, path)
+	for key := range labels {
+		if key == utils.ClusterRoleLabelName || strings.HasPrefix(key, utils.MetadataNamespace+"/") {
+			allErrors = append(allErrors, field.Invalid(
+				path.Key(key),
+				key,
+				fmt.Sprintf("the label is reserved to the operator and cannot be inherited, "+
+					"avoid the %q key and the %q prefix", utils.ClusterRoleLabelName, utils.MetadataNamespace+"/")))
+		}
+	}
+
+	return allErrors
+}
+
 // validateTolerations check and validate the tolerations field
 // This code is almost a verbatim copy of
 // https://github.com/kubernetes/kubernetes/blob/4d38d21/pkg/apis/core/validation/validation.go#L3147
@@ -2180,10 +2960,191 @@ func (r *Cluster) validateBackupConfiguration() field.ErrorList {
 	if r.Spec.Backup == nil {
 		return nil
 	}
-	return barmanWebhooks.ValidateBackupConfiguration(
+	path := field.NewPath("spec", "backup")
+	result := barmanWebhooks.ValidateBackupConfiguration(
 		r.Spec.Backup.BarmanObjectStore,
-		field.NewPath("spec", "backup", "barmanObjectStore"),
+		path.Child("barmanObjectStore"),
 	)
+	result = append(result, validateInheritedCredentials(
+		r.Spec.Backup.BarmanObjectStore,
+		path.Child("barmanObjectStore"),
+	)...)
+	result = append(result, validateDestinationPathTemplate(
+		r.Spec.Backup.BarmanObjectStore,
+		path.Child("barmanObjectStore"),
+	)...)
+	result = append(result, validateWALObjectStore(
+		r.Spec.Backup.BarmanObjectStore,
+		r.Spec.Backup.WALObjectStore,
+		path.Child("walObjectStore"),
+	)...)
+	result = append(result, validateDestinationPathTemplate(
+		r.Spec.Backup.WALObjectStore,
+		path.Child("walObjectStore"),
+	)...)
+	result = append(result, r.validateArchiveVerification(path.Child("archiveVerification"))...)
+	result = append(result, r.validateWALCompressionLevel(path.Child("walCompressionLevel"))...)
+	return result
+}
+
+// validateArchiveVerification checks that the verification of the WAL
+// archive is only enabled with an object store, and not too frequently
+func (r *Cluster) validateArchiveVerification(path *field.Path) field.ErrorList {
+	configuration := r.Spec.Backup.ArchiveVerification
+	if configuration == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	if r.Spec.Backup.BarmanObjectStore == nil {
+		result = append(result, field.Invalid(
+			path,
+			configuration,
+			"the verification of the WAL archive requires barmanObjectStore to be configured"))
+	}
+
+	if configuration.GetInterval() < time.Minute {
+		result = append(result, field.Invalid(
+			path.Child("interval"),
+			configuration.GetInterval().String(),
+			"the verification of the WAL archive cannot be run more than once per minute"))
+	}
+
+	return result
+}
+
+// walCompressionLevels maps the WAL compression algorithms supporting
+// a compression level to the range of the accepted levels
+var walCompressionLevels = map[barmanApi.CompressionType][2]int{
+	barmanApi.CompressionTypeGzip:  {1, 9},
+	barmanApi.CompressionTypeBzip2: {1, 9},
+}
+
+// validateWALCompressionLevel checks that the compression level of the WAL
+// files is supported by the compression algorithm selected for them
+func (r *Cluster) validateWALCompressionLevel(path *field.Path) field.ErrorList {
+	level := r.Spec.Backup.WALCompressionLevel
+	if level == nil {
+		return nil
+	}
+
+	var compression barmanApi.CompressionType
+	if walObjectStore := r.Spec.Backup.GetWALObjectStore(); walObjectStore != nil && walObjectStore.Wal != nil {
+		compression = walObjectStore.Wal.Compression
+	}
+	if compression == "" {
+		return field.ErrorList{field.Invalid(
+			path,
+			*level,
+			"the WAL compression level requires the compression of the WAL files to be enabled")}
+	}
+
+	levelRange, ok := walCompressionLevels[compression]
+	if !ok {
+		return field.ErrorList{field.Invalid(
+			path,
+			*level,
+			fmt.Sprintf("the %s compression doesn't support a compression level", compression))}
+	}
+
+	if *level < levelRange[0] || *level > levelRange[1] {
+		return field.ErrorList{field.Invalid(
+			path,
+			*level,
+			fmt.Sprintf("the %s compression level must be between %d and %d",
+				compression, levelRange[0], levelRange[1]))}
+	}
+
+	return nil
+}
+
+// validateInheritedCredentials checks that an object store whose credentials
+// are inherited from the environment the pods are running in, i.e. from the
+// identity bound to their service account, doesn't also reference static
+// credentials stored in a secret
+func validateInheritedCredentials(
+	configuration *BarmanObjectStoreConfiguration,
+	path *field.Path,
+) field.ErrorList {
+	if configuration == nil {
+		return nil
+	}
+
+	// The other combinations of static and inherited credentials
+	// are already rejected by the barman-cloud validation
+	if s3 := configuration.AWS; s3 != nil && s3.InheritFromIAMRole && s3.SessionToken != nil {
+		return field.ErrorList{
+			field.Invalid(
+				path.Child("s3Credentials", "sessionToken"),
+				s3.SessionToken,
+				"sessionToken cannot be used together with inheritFromIAMRole"),
+		}
+	}
+
+	return nil
+}
+
+// validateDestinationPathTemplate checks that the placeholders in the
+// destination path of an object store can be expanded, and only reference
+// the supported fields
+func validateDestinationPathTemplate(
+	configuration *BarmanObjectStoreConfiguration,
+	path *field.Path,
+) field.ErrorList {
+	if configuration == nil {
+		return nil
+	}
+
+	if _, err := expandDestinationPath(
+		configuration.DestinationPath,
+		destinationPathTemplateData{Namespace: "namespace", ClusterName: "cluster"},
+	); err != nil {
+		return field.ErrorList{
+			field.Invalid(
+				path.Child("destinationPath"),
+				configuration.DestinationPath,
+				fmt.Sprintf("invalid destination path template, only {{.Namespace}} and "+
+					"{{.ClusterName}} can be referenced: %v", err)),
+		}
+	}
+
+	return nil
+}
+
+// validateWALObjectStore validates the object store used for the WAL
+// files, when it is separated from the one containing the base backups
+func validateWALObjectStore(
+	barmanObjectStore *BarmanObjectStoreConfiguration,
+	walObjectStore *BarmanObjectStoreConfiguration,
+	path *field.Path,
+) field.ErrorList {
+	if walObjectStore == nil {
+		return nil
+	}
+
+	if barmanObjectStore == nil {
+		return field.ErrorList{
+			field.Invalid(
+				path,
+				walObjectStore,
+				"walObjectStore requires barmanObjectStore to be set"),
+		}
+	}
+
+	result := barmanWebhooks.ValidateBackupConfiguration(walObjectStore, path)
+	result = append(result, validateInheritedCredentials(walObjectStore, path)...)
+
+	// The WAL object store shares the endpoint CA bundle with
+	// the one containing the base backups
+	if walObjectStore.EndpointCA != nil &&
+		(barmanObjectStore.EndpointCA == nil || *walObjectStore.EndpointCA != *barmanObjectStore.EndpointCA) {
+		result = append(result, field.Invalid(
+			path.Child("endpointCA"),
+			walObjectStore.EndpointCA,
+			"the endpointCA of walObjectStore must be the same as the one of barmanObjectStore"))
+	}
+
+	return result
 }
 
 // validateRetentionPolicy validates the retention policy configuration
@@ -2214,6 +3175,17 @@ func (r *Cluster) validateReplicationSlots() field.ErrorList {
 		return nil
 	}
 
+	if prefix := replicationSlots.Prefix; prefix != "" && replicationSlots.HighAvailability != nil &&
+		replicationSlots.HighAvailability.SlotPrefix != "" &&
+		replicationSlots.HighAvailability.SlotPrefix != prefix {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "replicationSlots", "highAvailability", "slotPrefix"),
+				replicationSlots.HighAvailability.SlotPrefix,
+				"The highAvailability slot prefix must match the replication slots prefix, when set"),
+		}
+	}
+
 	if errs := r.Spec.ReplicationSlots.SynchronizeReplicas.compileRegex(); len(errs) > 0 {
 		return field.ErrorList{
 			field.Invalid(
@@ -2226,10 +3198,81 @@ func (r *Cluster) validateReplicationSlots() field.ErrorList {
 	return nil
 }
 
+// validateReplication validates the options used to create new replicas
+func (r *Cluster) validateReplication() field.ErrorList {
+	if r.Spec.Replication == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	if r.Spec.Replication.PgBaseBackup != nil && r.Spec.Replication.PgBaseBackup.MaxRate != "" {
+		maxRate := r.Spec.Replication.PgBaseBackup.MaxRate
+		maxRatePath := field.NewPath("spec", "replication", "pgBaseBackup", "maxRate")
+		rate, err := parsePgBaseBackupMaxRate(maxRate)
+		switch {
+		case err != nil:
+			result = append(result, field.Invalid(maxRatePath, maxRate, err.Error()))
+		case rate < 32 || rate > 1024*1024:
+			result = append(result,
+				field.Invalid(maxRatePath, maxRate, "maxRate must be between 32 kB/s and 1024 MB/s"))
+		}
+	}
+
+	if period := r.Spec.Replication.CredentialsRotationPeriod; period != nil {
+		periodPath := field.NewPath("spec", "replication", "credentialsRotationPeriod")
+		if period.Duration < time.Hour {
+			result = append(result,
+				field.Invalid(periodPath, period.String(), "credentialsRotationPeriod must be at least one hour"))
+		}
+		if r.Spec.Certificates != nil && r.Spec.Certificates.ReplicationTLSSecret != "" {
+			result = append(result,
+				field.Invalid(periodPath, period.String(),
+					"credentials rotation is not supported when the replication TLS secret is user-provided"))
+		}
+	}
+
+	return result
+}
+
+// parsePgBaseBackupMaxRate parses a pg_basebackup transfer rate,
+// returning it in kilobytes per second
+func parsePgBaseBackupMaxRate(maxRate string) (int, error) {
+	multiplier := 1
+	value := maxRate
+	switch {
+	case strings.HasSuffix(maxRate, "k"):
+		value = strings.TrimSuffix(maxRate, "k")
+	case strings.HasSuffix(maxRate, "M"):
+		value = strings.TrimSuffix(maxRate, "M")
+		multiplier = 1024
+	}
+
+	rate, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid transfer rate: %w", err)
+	}
+
+	return rate * multiplier, nil
+}
+
 func (r *Cluster) validateReplicationSlotsChange(old *Cluster) field.ErrorList {
 	newReplicationSlots := r.Spec.ReplicationSlots
 	oldReplicationSlots := old.Spec.ReplicationSlots
 
+	if oldReplicationSlots != nil && oldReplicationSlots.Prefix != "" &&
+		(newReplicationSlots == nil || newReplicationSlots.Prefix != oldReplicationSlots.Prefix) {
+		var newPrefix string
+		if newReplicationSlots != nil {
+			newPrefix = newReplicationSlots.Prefix
+		}
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "replicationSlots", "prefix"),
+				newPrefix,
+				"Cannot change the replication slots prefix"),
+		}
+	}
+
 	if oldReplicationSlots == nil || oldReplicationSlots.HighAvailability == nil ||
 		!oldReplicationSlots.HighAvailability.GetEnabled() {
 		return nil
@@ -2279,12 +3322,70 @@ func (r *Cluster) validateWALLevelChange(old *Cluster) field.ErrorList {
 	return errs
 }
 
+// validateIntegrityCheck validates the schedule of the integrity check
+func (r *Cluster) validateIntegrityCheck() field.ErrorList {
+	if r.Spec.Managed == nil || r.Spec.Managed.IntegrityCheck == nil {
+		return nil
+	}
+
+	schedule := r.Spec.Managed.IntegrityCheck.Schedule
+	if _, err := cron.Parse(schedule); err != nil {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "managed", "integrityCheck", "schedule"),
+				schedule, err.Error()),
+		}
+	}
+
+	return nil
+}
+
+// validateRepack validates the configuration of the periodic
+// execution of pg_repack
+func (r *Cluster) validateRepack() field.ErrorList {
+	configuration := r.GetRepackConfiguration()
+	if configuration == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	repackPath := field.NewPath("spec", "managed", "maintenance", "repack")
+
+	if _, err := cron.Parse(configuration.Schedule); err != nil {
+		result = append(result, field.Invalid(
+			repackPath.Child("schedule"),
+			configuration.Schedule, err.Error()))
+	}
+
+	if configuration.MaxReplicationLag != "" {
+		if _, err := resource.ParseQuantity(configuration.MaxReplicationLag); err != nil {
+			result = append(result, field.Invalid(
+				repackPath.Child("maxReplicationLag"),
+				configuration.MaxReplicationLag,
+				"Maximum replication lag value isn't valid"))
+		}
+	}
+
+	seen := make(map[RepackTable]bool, len(configuration.Tables))
+	for i, table := range configuration.Tables {
+		if seen[table] {
+			result = append(result, field.Duplicate(
+				repackPath.Child("tables").Index(i),
+				fmt.Sprintf("%s.%s", table.Database, table.Table)))
+		}
+		seen[table] = true
+	}
+
+	return result
+}
+
 func (r *Cluster) validateManagedServices() field.ErrorList {
 	reservedNames := []string{
 		r.GetServiceReadWriteName(),
 		r.GetServiceReadOnlyName(),
 		r.GetServiceReadName(),
 		r.GetServiceAnyName(),
+		r.GetServiceHeadlessName(),
 	}
 	containsDuplicateNames := func(names []string) bool {
 		seen := make(map[string]bool)
@@ -2346,6 +3447,23 @@ func (r *Cluster) validateManagedServices() field.ErrorList {
 		))
 	}
 
+	if metadata := managedServices.DefaultServicesMetadata; metadata != nil {
+		metadataPath := basePath.Child("defaultServicesMetadata")
+		errs = append(errs, validateUserLabels(metadataPath.Child("labels"), metadata.Labels)...)
+		errs = append(errs, apivalidation.ValidateAnnotations(metadata.Annotations, metadataPath.Child("annotations"))...)
+	}
+
+	if hostname := r.GetReadWriteServiceExternalDNSHostname(); hostname != "" {
+		hostnamePath := basePath.Child("rw", "externalDNSHostname")
+		for _, msg := range validationutil.IsDNS1123Subdomain(hostname) {
+			errs = append(errs, field.Invalid(hostnamePath, hostname, msg))
+		}
+		if !strings.Contains(hostname, ".") {
+			errs = append(errs, field.Invalid(hostnamePath, hostname,
+				"must be a fully qualified domain name"))
+		}
+	}
+
 	return errs
 }
 
@@ -2379,6 +3497,7 @@ func (r *Cluster) validateManagedRoles() field.ErrorList {
 		return nil
 	}
 
+	maxConnections, hasMaxConnections := r.getMaxConnections()
 	managedRoles := make(map[string]interface{})
 	for _, role := range r.Spec.Managed.Roles {
 		_, found := managedRoles[role.Name]
@@ -2399,6 +3518,15 @@ func (r *Cluster) validateManagedRoles() field.ErrorList {
 					role.ConnectionLimit,
 					"Connection limit should be positive, unless defaulting to -1"))
 		}
+		if hasMaxConnections && role.ConnectionLimit > int64(maxConnections) {
+			result = append(
+				result,
+				field.Invalid(
+					field.NewPath("spec", "managed", "roles"),
+					role.ConnectionLimit,
+					fmt.Sprintf("Connection limit of role %s cannot be greater than max_connections (%d)",
+						role.Name, maxConnections)))
+		}
 		if postgres.IsRoleReserved(role.Name) {
 			result = append(
 				result,
@@ -2417,9 +3545,103 @@ func (r *Cluster) validateManagedRoles() field.ErrorList {
 		}
 	}
 
+	result = append(result, r.validateManagedRoleMemberships()...)
+
 	return result
 }
 
+// validateManagedRoleMemberships checks that the memberships of the managed
+// roles don't reference roles that are meant to be absent and don't form
+// a cycle, which PostgreSQL would refuse
+func (r *Cluster) validateManagedRoleMemberships() field.ErrorList {
+	var result field.ErrorList
+
+	memberships := make(map[string][]string, len(r.Spec.Managed.Roles))
+	absentRoles := make(map[string]bool)
+	for _, role := range r.Spec.Managed.Roles {
+		if role.Ensure == EnsureAbsent {
+			absentRoles[role.Name] = true
+			continue
+		}
+		memberships[role.Name] = role.GetMemberships()
+	}
+
+	for idx, role := range r.Spec.Managed.Roles {
+		path := field.NewPath("spec", "managed", "roles").Index(idx)
+		for _, parent := range memberships[role.Name] {
+			switch {
+			case parent == role.Name:
+				result = append(result, field.Invalid(path, parent,
+					fmt.Sprintf("Role %s cannot be a member of itself", role.Name)))
+			case absentRoles[parent]:
+				result = append(result, field.Invalid(path, parent,
+					fmt.Sprintf("Role %s cannot be a member of role %s, which is ensured to be absent",
+						role.Name, parent)))
+			}
+		}
+	}
+
+	// Detect the cycles through a depth-first visit of the membership graph
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(memberships))
+	var visit func(name string, path []string) []string
+	visit = func(name string, path []string) []string {
+		state[name] = visiting
+		path = append(path, name)
+		for _, parent := range memberships[name] {
+			if parent == name {
+				continue
+			}
+			switch state[parent] {
+			case visiting:
+				return append(path[slices.Index(path, parent):], parent)
+			case unvisited:
+				if cycle := visit(parent, path); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		state[name] = visited
+		return nil
+	}
+
+	for idx, role := range r.Spec.Managed.Roles {
+		if state[role.Name] != unvisited || role.Ensure == EnsureAbsent {
+			continue
+		}
+		if cycle := visit(role.Name, nil); cycle != nil {
+			result = append(result, field.Invalid(
+				field.NewPath("spec", "managed", "roles").Index(idx),
+				role.Name,
+				fmt.Sprintf("Circular role membership: %s", strings.Join(cycle, " -> "))))
+			break
+		}
+	}
+
+	return result
+}
+
+// getMaxConnections returns the value of max_connections requested for
+// PostgreSQL. The second return value is false when the parameter is not valid,
+// leaving its validation to the configuration checks
+func (r *Cluster) getMaxConnections() (int, bool) {
+	value, ok := r.Spec.PostgresConfiguration.Parameters[maxConnectionsParameter]
+	if !ok {
+		return defaultMaxConnections, true
+	}
+
+	maxConnections, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, false
+	}
+
+	return maxConnections, true
+}
+
 // validateManagedExtensions validate the managed extensions parameters set by the user
 func (r *Cluster) validateManagedExtensions() field.ErrorList {
 	allErrors := field.ErrorList{}
@@ -2428,6 +3650,21 @@ func (r *Cluster) validateManagedExtensions() field.ErrorList {
 	return allErrors
 }
 
+// validateMajorUpgrade checks that the data directories are upgraded in
+// place only in clusters having a primary instance
+func (r *Cluster) validateMajorUpgrade() field.ErrorList {
+	if !r.ShouldUpgradeMajorVersionInPlace() || !r.IsReplica() {
+		return nil
+	}
+
+	return field.ErrorList{
+		field.Invalid(
+			field.NewPath("spec", "postgresql", "majorUpgrade", "strategy"),
+			r.Spec.PostgresConfiguration.MajorUpgrade.Strategy,
+			"the data directories of a replica cluster can't be upgraded with pg_upgrade"),
+	}
+}
+
 func (r *Cluster) validatePgFailoverSlots() field.ErrorList {
 	var result field.ErrorList
 	var pgFailoverSlots postgres.ManagedExtension
@@ -2489,7 +3726,55 @@ func (r *Cluster) validatePgFailoverSlots() field.ErrorList {
 }
 
 func (r *Cluster) getAdmissionWarnings() admission.Warnings {
-	return r.getMaintenanceWindowsAdmissionWarnings()
+	result := append(r.getMaintenanceWindowsAdmissionWarnings(), r.getPreparedTransactionsAdmissionWarnings()...)
+	return append(result, r.getReplicationLimitsAdmissionWarnings()...)
+}
+
+// getMinPostgresMajorAdmissionWarnings warns the user when the minimum
+// PostgreSQL major version can't be enforced, as the version can't be
+// detected from the image name
+func (r *Cluster) getMinPostgresMajorAdmissionWarnings() admission.Warnings {
+	if configuration.Current.MinPostgresMajor <= 0 {
+		return nil
+	}
+
+	if _, err := r.GetPostgresqlVersion(); err == nil {
+		return nil
+	}
+
+	return admission.Warnings{
+		fmt.Sprintf("Unable to detect the PostgreSQL major version from the image %q: "+
+			"the minimum PostgreSQL %d version required by the operator can't be enforced",
+			r.GetImageName(), configuration.Current.MinPostgresMajor),
+	}
+}
+
+// getLocaleChangeAdmissionWarnings warns the user when the locale settings
+// passed to initdb are changed, as they are only used while bootstrapping
+// the cluster
+func (r *Cluster) getLocaleChangeAdmissionWarnings(old *Cluster) admission.Warnings {
+	if r.Spec.Bootstrap == nil || r.Spec.Bootstrap.InitDB == nil ||
+		old.Spec.Bootstrap == nil || old.Spec.Bootstrap.InitDB == nil {
+		return nil
+	}
+
+	current, previous := r.Spec.Bootstrap.InitDB, old.Spec.Bootstrap.InitDB
+	if current.Encoding == previous.Encoding &&
+		current.Locale == previous.Locale &&
+		current.LocaleCollate == previous.LocaleCollate &&
+		current.LocaleCType == previous.LocaleCType &&
+		current.LocaleProvider == previous.LocaleProvider &&
+		current.IcuLocale == previous.IcuLocale &&
+		current.IcuRules == previous.IcuRules &&
+		current.BuiltinLocale == previous.BuiltinLocale {
+		return nil
+	}
+
+	return admission.Warnings{
+		"The encoding and locale settings in `.spec.bootstrap.initdb` are only used when " +
+			"the cluster is created and cannot be changed afterwards. The `timezone` and " +
+			"`datestyle` parameters in `.spec.postgresql.parameters` can be changed at any time",
+	}
 }
 
 func (r *Cluster) getMaintenanceWindowsAdmissionWarnings() admission.Warnings {
@@ -2503,6 +3788,142 @@ func (r *Cluster) getMaintenanceWindowsAdmissionWarnings() admission.Warnings {
 	return result
 }
 
+// getPreparedTransactionsAdmissionWarnings warns the user about the risks
+// of enabling two-phase commit
+func (r *Cluster) getPreparedTransactionsAdmissionWarnings() admission.Warnings {
+	value := r.Spec.PostgresConfiguration.Parameters[maxPreparedTransactionsParameter]
+	if n, err := strconv.Atoi(value); err != nil || n <= 0 {
+		return nil
+	}
+
+	return admission.Warnings{
+		"Prepared transactions that are never committed or rolled back keep holding their locks " +
+			"and prevent vacuum from removing dead rows. The operator reports them in the " +
+			"`StalePreparedTransactions` condition but never rolls them back",
+	}
+}
+
+// getReplicationLimitsAdmissionWarnings warns the user when `max_wal_senders`
+// or `max_replication_slots` are set lower than the suggested minimum for
+// the topology of the cluster
+func (r *Cluster) getReplicationLimitsAdmissionWarnings() admission.Warnings {
+	// Replication is disabled with the minimal WAL level
+	if r.Spec.PostgresConfiguration.Parameters[postgres.ParameterWalLevel] == string(postgres.WalLevelValueMinimal) {
+		return nil
+	}
+
+	var result admission.Warnings
+
+	limits := []struct {
+		parameter string
+		minimum   int
+	}{
+		{postgres.ParameterMaxWalSenders, r.GetMinWalSenders()},
+		{postgres.ParameterMaxReplicationSlots, r.GetMinReplicationSlots()},
+	}
+	for _, limit := range limits {
+		value, err := strconv.Atoi(r.Spec.PostgresConfiguration.Parameters[limit.parameter])
+		if err != nil || value >= limit.minimum {
+			continue
+		}
+
+		result = append(result, fmt.Sprintf(
+			"`%s` is set to %d, while at least %d is suggested for a cluster with %d instances. "+
+				"Replicas might be unable to connect, and the operator will report it in the "+
+				"`ReplicationLimitsExceeded` condition",
+			limit.parameter, value, limit.minimum, r.Spec.Instances))
+	}
+
+	return result
+}
+
+// validateFencedUntil checks the fencing expiry time, rejecting it when it
+// is already in the past while the fencing is being set. The old cluster
+// is nil when validating a new one
+func (r *Cluster) validateFencedUntil(old *Cluster) field.ErrorList {
+	value, ok := r.Annotations[utils.FencedUntilAnnotation]
+	if !ok {
+		return nil
+	}
+
+	if old != nil &&
+		old.Annotations[utils.FencedUntilAnnotation] == value &&
+		old.Annotations[utils.FencedInstanceAnnotation] == r.Annotations[utils.FencedInstanceAnnotation] {
+		return nil
+	}
+
+	path := field.NewPath("metadata", "annotations", utils.FencedUntilAnnotation)
+	fencedUntil, err := utils.GetFencedUntil(r.Annotations)
+	if err != nil {
+		return field.ErrorList{field.Invalid(path, value, err.Error())}
+	}
+
+	if _, isFenced := r.Annotations[utils.FencedInstanceAnnotation]; isFenced && !fencedUntil.After(time.Now()) {
+		return field.ErrorList{
+			field.Invalid(path, value, "the fencing expiry time must be in the future"),
+		}
+	}
+
+	return nil
+}
+
+// getFencingAdmissionWarnings warns the user when the fencing annotation
+// is changed to cover the primary, which is kept as such while its
+// PostgreSQL server is shut down. The old cluster is nil when validating a
+// new one
+func (r *Cluster) getFencingAdmissionWarnings(old *Cluster) admission.Warnings {
+	if old != nil &&
+		old.Annotations[utils.FencedInstanceAnnotation] == r.Annotations[utils.FencedInstanceAnnotation] {
+		return nil
+	}
+
+	fencedInstances, err := utils.GetFencedInstances(r.Annotations)
+	if err != nil || fencedInstances.Len() == 0 {
+		return nil
+	}
+
+	primary := r.Status.CurrentPrimary
+	if primary == "" {
+		// Without a primary, only the fencing of every instance is known to
+		// include it
+		if !fencedInstances.Has(utils.FenceAllInstances) && !fencedInstances.Has(utils.FencePrimarySelector) {
+			return nil
+		}
+	} else if !r.IsInstanceFenced(primary) || (old != nil && old.IsInstanceFenced(primary)) {
+		return nil
+	}
+
+	fencedPods := make([]string, 0, len(r.Status.InstanceNames))
+	for _, name := range r.Status.InstanceNames {
+		if r.IsInstanceFenced(name) {
+			fencedPods = append(fencedPods, name)
+		}
+	}
+	if len(fencedPods) == 0 {
+		fencedPods = fencedInstances.ToSortedList()
+	}
+
+	if r.Spec.Instances == 1 {
+		return admission.Warnings{
+			fmt.Sprintf("Fencing %s shuts down the only PostgreSQL instance of the cluster %q: "+
+				"the database will be unavailable until the fencing is lifted with "+
+				"`kubectl cnpg fencing off %s \"*\"`",
+				strings.Join(fencedPods, ", "), r.Name, r.Name),
+		}
+	}
+
+	primaryDescription := "the primary"
+	if primary != "" {
+		primaryDescription = fmt.Sprintf("the primary %q", primary)
+	}
+	return admission.Warnings{
+		fmt.Sprintf("Fencing %s includes %s, which keeps its role while PostgreSQL is shut down: "+
+			"the cluster %q will not accept writes until the fencing is lifted. "+
+			"Run a switchover with `kubectl cnpg promote` first to keep the database available",
+			strings.Join(fencedPods, ", "), primaryDescription, r.Name),
+	}
+}
+
 // validate whether the hibernation configuration is valid
 func (r *Cluster) validateHibernationAnnotation() field.ErrorList {
 	value, ok := r.Annotations[utils.HibernationAnnotationName]
@@ -23,6 +23,7 @@ import (
 	"strings"
 	"time"
 
+	barmanApi "github.com/cloudnative-pg/barman-cloud/pkg/api"
 	"github.com/cloudnative-pg/machinery/pkg/image/reference"
 	pgversion "github.com/cloudnative-pg/machinery/pkg/postgres/version"
 	storagesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
@@ -334,6 +335,126 @@ var _ = Describe("initdb options validation", func() {
 		result := cluster.validateSuperuserSecret()
 		Expect(result).To(HaveLen(1))
 	})
+
+	DescribeTable("validates the locale provider settings",
+		func(imageName string, initDB *BootstrapInitDB, expectedErrors int) {
+			cluster := Cluster{
+				Spec: ClusterSpec{
+					ImageName: imageName,
+					Bootstrap: &BootstrapConfiguration{
+						InitDB: initDB,
+					},
+				},
+			}
+			Expect(cluster.validateInitDB()).To(HaveLen(expectedErrors))
+		},
+		Entry("libc locale",
+			"postgres:13", &BootstrapInitDB{Locale: "it_IT.UTF-8"}, 0),
+		Entry("icu provider with an ICU locale",
+			"postgres:16", &BootstrapInitDB{LocaleProvider: "icu", IcuLocale: "it-IT"}, 0),
+		Entry("icu provider with the generic locale",
+			"postgres:16", &BootstrapInitDB{LocaleProvider: "icu", Locale: "it-IT"}, 0),
+		Entry("icu provider without any locale",
+			"postgres:16", &BootstrapInitDB{LocaleProvider: "icu"}, 1),
+		Entry("icu provider on an unsupported version",
+			"postgres:14", &BootstrapInitDB{LocaleProvider: "icu", IcuLocale: "it-IT"}, 1),
+		Entry("ICU locale without the icu provider",
+			"postgres:16", &BootstrapInitDB{IcuLocale: "it-IT"}, 1),
+		Entry("ICU rules on an unsupported version",
+			"postgres:15", &BootstrapInitDB{LocaleProvider: "icu", IcuLocale: "it-IT", IcuRules: "&a < g"}, 1),
+		Entry("builtin provider",
+			"postgres:17", &BootstrapInitDB{LocaleProvider: "builtin", BuiltinLocale: "C.UTF-8"}, 0),
+		Entry("builtin provider on an unsupported version",
+			"postgres:16", &BootstrapInitDB{LocaleProvider: "builtin", BuiltinLocale: "C.UTF-8"}, 1),
+		Entry("builtin locale without the builtin provider",
+			"postgres:17", &BootstrapInitDB{BuiltinLocale: "C.UTF-8"}, 1),
+	)
+
+	Describe("walSegmentSize", func() {
+		newCluster := func(walSegmentSize int, parameters map[string]string) *Cluster {
+			return &Cluster{
+				Spec: ClusterSpec{
+					Bootstrap: &BootstrapConfiguration{
+						InitDB: &BootstrapInitDB{
+							WalSegmentSize: walSegmentSize,
+						},
+					},
+					PostgresConfiguration: PostgresConfiguration{
+						Parameters: parameters,
+					},
+				},
+			}
+		}
+
+		It("accepts a 64MB WAL segment size", func() {
+			Expect(newCluster(64, nil).validateInitDB()).To(BeEmpty())
+		})
+
+		It("rejects a WAL segment size which is not a power of 2", func() {
+			Expect(newCluster(48, nil).validateInitDB()).To(HaveLen(1))
+		})
+
+		It("rejects WAL sizes smaller than twice the WAL segment size", func() {
+			cluster := newCluster(64, map[string]string{
+				"min_wal_size": "80MB",
+				"max_wal_size": "100MB",
+			})
+			Expect(validateWalSizeAgainstSegmentSize(
+				cluster.Spec.PostgresConfiguration, getInitDBWalSegmentSize(cluster))).To(HaveLen(2))
+		})
+
+		It("accepts WAL sizes holding at least two WAL segments", func() {
+			cluster := newCluster(64, map[string]string{
+				"min_wal_size": "128MB",
+				"max_wal_size": "1GB",
+			})
+			Expect(validateWalSizeAgainstSegmentSize(
+				cluster.Spec.PostgresConfiguration, getInitDBWalSegmentSize(cluster))).To(BeEmpty())
+		})
+
+		It("does not check the WAL sizes when the default segment size is used", func() {
+			cluster := newCluster(0, map[string]string{
+				"min_wal_size": "16MB",
+			})
+			Expect(validateWalSizeAgainstSegmentSize(
+				cluster.Spec.PostgresConfiguration, getInitDBWalSegmentSize(cluster))).To(BeEmpty())
+		})
+
+		It("prevents changing the WAL segment size of an existing cluster", func() {
+			Expect(newCluster(64, nil).validateWalSegmentSizeChange(newCluster(0, nil))).To(HaveLen(1))
+			Expect(newCluster(32, nil).validateWalSegmentSizeChange(newCluster(64, nil))).To(HaveLen(1))
+		})
+
+		It("allows keeping the WAL segment size of an existing cluster", func() {
+			Expect(newCluster(64, nil).validateWalSegmentSizeChange(newCluster(64, nil))).To(BeEmpty())
+		})
+
+		It("ignores clusters that were not bootstrapped with initdb", func() {
+			oldCluster := newCluster(0, nil)
+			oldCluster.Spec.Bootstrap = nil
+			Expect(newCluster(64, nil).validateWalSegmentSizeChange(oldCluster)).To(BeEmpty())
+		})
+	})
+
+	DescribeTable("connectionLimit",
+		func(connectionLimit *int, expectedErrors int) {
+			cluster := &Cluster{
+				Spec: ClusterSpec{
+					Bootstrap: &BootstrapConfiguration{
+						InitDB: &BootstrapInitDB{
+							ConnectionLimit: connectionLimit,
+						},
+					},
+				},
+			}
+			Expect(cluster.validateInitDB()).To(HaveLen(expectedErrors))
+		},
+		Entry("not set", nil, 0),
+		Entry("no limit", ptr.To(-1), 0),
+		Entry("no connections allowed", ptr.To(0), 0),
+		Entry("a positive limit", ptr.To(50), 0),
+		Entry("a limit lower than -1", ptr.To(-2), 1),
+	)
 })
 
 var _ = Describe("cluster configuration", func() {
@@ -514,6 +635,30 @@ var _ = Describe("Defaulting webhook", func() {
 		Expect(cluster.Spec.Bootstrap.InitDB.Database).To(Equal("testdb"))
 		Expect(cluster.Spec.Bootstrap.InitDB.Owner).To(Equal("testuser"))
 	})
+
+	It("should default the encoding and the locale categories", func() {
+		cluster := Cluster{}
+		cluster.Default()
+		Expect(cluster.Spec.Bootstrap.InitDB.Encoding).To(Equal("UTF8"))
+		Expect(cluster.Spec.Bootstrap.InitDB.LocaleCollate).To(Equal("C"))
+		Expect(cluster.Spec.Bootstrap.InitDB.LocaleCType).To(Equal("C"))
+	})
+
+	It("should not default the locale categories when the locale is set", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Locale: "it_IT.UTF-8",
+					},
+				},
+			},
+		}
+		cluster.Default()
+		Expect(cluster.Spec.Bootstrap.InitDB.Encoding).To(Equal("UTF8"))
+		Expect(cluster.Spec.Bootstrap.InitDB.LocaleCollate).To(BeEmpty())
+		Expect(cluster.Spec.Bootstrap.InitDB.LocaleCType).To(BeEmpty())
+	})
 })
 
 var _ = Describe("Image name validation", func() {
@@ -632,6 +777,150 @@ var _ = DescribeTable("parsePostgresQuantityValue",
 	Entry("reject non-numeric", "non-numeric", resource.Quantity{}, true),
 )
 
+var _ = Describe("pg_hba validation", func() {
+	It("allows valid rules, comments and empty lines", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					PgHBA: []string{
+						"hostssl app app 10.244.0.0/16 scram-sha-256",
+						"host all all 192.168.1.1 255.255.255.255 md5",
+						"host all all fd00::/8 md5 # IPv6 clients",
+						"hostnossl all all .example.com reject",
+						`local "my db" "+admins,john" peer map=admins`,
+						"hostssl all all all ldap ldapserver=ldap.example.com ldapprefix=\"cn=\"",
+						"# a comment",
+						"",
+					},
+				},
+			},
+		}
+		Expect(cluster.validatePgHBA()).To(BeEmpty())
+	})
+
+	DescribeTable("rejects malformed rules",
+		func(rule string, message string) {
+			cluster := Cluster{
+				Spec: ClusterSpec{
+					PostgresConfiguration: PostgresConfiguration{
+						PgHBA: []string{rule},
+					},
+				},
+			}
+			result := cluster.validatePgHBA()
+			Expect(result).To(HaveLen(1))
+			Expect(result[0].Field).To(Equal("spec.postgresql.pg_hba[0]"))
+			Expect(result[0].Detail).To(ContainSubstring(message))
+		},
+		Entry("unknown connection type", "hostx all all all md5", "invalid connection type"),
+		Entry("missing method", "host all all 10.0.0.0/8", "missing fields"),
+		Entry("missing address", "host all all md5", "missing fields"),
+		Entry("invalid address", "host all all 10.0.0.0/33 md5", "invalid address"),
+		Entry("IP address without mask", "host all all 10.0.0.1 md5 clientcert=verify-ca", "must be followed by a mask"),
+		Entry("unknown method", "host all all all md6", "invalid authentication method"),
+		Entry("empty database", "host app, all all md5", "empty element in the database field"),
+		Entry("malformed option", "host all all all ldap ldapserver", "invalid authentication option"),
+		Entry("cert without SSL", "host all all all cert", "only supported on hostssl"),
+		Entry("peer on TCP", "host all all all peer", "only supported on local"),
+		Entry("include directive", "include_dir conf.d", "directives are not supported"),
+		Entry("unterminated quotes", `host "app all all md5`, "unterminated quoted string"),
+		Entry("multiple lines", "host all all all md5\nlocal all all trust", "can't span multiple lines"),
+		Entry("unknown placeholder", "host {{.Database}} all all md5", "invalid rule template"),
+	)
+
+	It("expands the placeholders before validating the rules", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{Database: "app", Owner: "app"},
+				},
+				PostgresConfiguration: PostgresConfiguration{
+					PgHBA: []string{
+						"hostssl {{.ApplicationDatabase}} {{.ApplicationUser}} all scram-sha-256",
+						"hostssl app {{.ReplicationUser}} all cert",
+					},
+				},
+			},
+		}
+		Expect(cluster.validatePgHBA()).To(BeEmpty())
+
+		cluster.Spec.Bootstrap = nil
+		Expect(cluster.validatePgHBA()).To(HaveLen(1))
+	})
+
+	It("rejects local rules preceding the ones set by the operator", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					PgHBAPrepend: []string{
+						"hostssl all all 10.0.0.0/8 reject",
+						"local all all trust",
+					},
+				},
+			},
+		}
+		result := cluster.validatePgHBA()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.postgresql.pgHBAPrepend[1]"))
+	})
+})
+
+var _ = Describe("pg_ident validation", func() {
+	It("allows user name maps, comments and empty lines", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					PgIdent: []string{
+						"certmap app.example.com app",
+						`krb /^(.*)@EXAMPLE\.COM$ \1 # Kerberos principals`,
+						`spaces "John Smith" john`,
+						"# a comment",
+						"",
+					},
+				},
+			},
+		}
+		Expect(cluster.validatePgIdent()).To(BeEmpty())
+	})
+
+	It("rejects entries with a wrong number of fields", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					PgIdent: []string{
+						"certmap app.example.com",
+						"certmap app.example.com app extra",
+						`spaces John Smith john`,
+					},
+				},
+			},
+		}
+		Expect(cluster.validatePgIdent()).To(HaveLen(3))
+	})
+
+	It("rejects entries spanning multiple lines", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					PgIdent: []string{"certmap app.example.com app\nlocal root postgres"},
+				},
+			},
+		}
+		Expect(cluster.validatePgIdent()).To(HaveLen(1))
+	})
+
+	It("rejects entries for the map reserved for the operator", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					PgIdent: []string{"local root postgres"},
+				},
+			},
+		}
+		Expect(cluster.validatePgIdent()).To(HaveLen(1))
+	})
+})
+
 var _ = Describe("configuration change validation", func() {
 	It("doesn't complain when the configuration is exactly the same", func() {
 		clusterOld := Cluster{
@@ -1316,6 +1605,105 @@ var _ = Describe("configuration change validation", func() {
 			Expect(cluster.validateConfiguration()).To(BeEmpty())
 		})
 	})
+
+	DescribeTable("timezone and datestyle",
+		func(parameters map[string]string, isValid bool) {
+			cluster := Cluster{
+				Spec: ClusterSpec{
+					Instances: 1,
+					PostgresConfiguration: PostgresConfiguration{
+						Parameters: parameters,
+					},
+				},
+			}
+			if isValid {
+				Expect(cluster.validateConfiguration()).To(BeEmpty())
+			} else {
+				Expect(cluster.validateConfiguration()).To(HaveLen(1))
+			}
+		},
+		Entry("time zone name", map[string]string{"timezone": "Europe/Rome"}, true),
+		Entry("time zone name with offset", map[string]string{"TimeZone": "Etc/GMT+3"}, true),
+		Entry("UTC", map[string]string{"timezone": "UTC"}, true),
+		Entry("POSIX specification", map[string]string{"timezone": "EST5EDT,M3.2.0,M11.1.0"}, true),
+		Entry("POSIX specification with numeric abbreviation", map[string]string{"timezone": "<+0330>-3:30"}, true),
+		Entry("invalid time zone", map[string]string{"timezone": "Europe/../Rome"}, false),
+		Entry("empty time zone", map[string]string{"timezone": ""}, false),
+		Entry("datestyle", map[string]string{"datestyle": "ISO, DMY"}, true),
+		Entry("datestyle with a single keyword", map[string]string{"DateStyle": "German"}, true),
+		Entry("datestyle with an unknown keyword", map[string]string{"datestyle": "ISO, XYZ"}, false),
+		Entry("datestyle with conflicting formats", map[string]string{"datestyle": "ISO, SQL"}, false),
+		Entry("datestyle with conflicting orders", map[string]string{"datestyle": "DMY, YMD"}, false),
+	)
+
+	DescribeTable("max_prepared_transactions",
+		func(value string, isValid bool) {
+			cluster := Cluster{
+				Spec: ClusterSpec{
+					Instances: 1,
+					PostgresConfiguration: PostgresConfiguration{
+						Parameters: map[string]string{"max_prepared_transactions": value},
+					},
+				},
+			}
+			if isValid {
+				Expect(cluster.validateConfiguration()).To(BeEmpty())
+			} else {
+				Expect(cluster.validateConfiguration()).To(HaveLen(1))
+			}
+		},
+		Entry("disabled", "0", true),
+		Entry("enabled", "100", true),
+		Entry("negative", "-1", false),
+		Entry("too high", "262144", false),
+		Entry("not a number", "many", false),
+	)
+
+	It("warns about the risks of two-phase commit", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"max_prepared_transactions": "100"},
+				},
+			},
+		}
+		Expect(cluster.getPreparedTransactionsAdmissionWarnings()).To(HaveLen(1))
+
+		cluster.Spec.PostgresConfiguration.Parameters["max_prepared_transactions"] = "0"
+		Expect(cluster.getPreparedTransactionsAdmissionWarnings()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("validate the major upgrade strategy", func() {
+	It("accepts the pg_upgrade strategy in a primary cluster", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					MajorUpgrade: &MajorUpgradeConfiguration{
+						Strategy: MajorUpgradeStrategyPgUpgrade,
+					},
+				},
+			},
+		}
+		Expect(cluster.validateMajorUpgrade()).To(BeEmpty())
+	})
+
+	It("rejects the pg_upgrade strategy in a replica cluster", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled: ptr.To(true),
+					Source:  "origin",
+				},
+				PostgresConfiguration: PostgresConfiguration{
+					MajorUpgrade: &MajorUpgradeConfiguration{
+						Strategy: MajorUpgradeStrategyPgUpgrade,
+					},
+				},
+			},
+		}
+		Expect(cluster.validateMajorUpgrade()).To(HaveLen(1))
+	})
 })
 
 var _ = Describe("validate image name change", func() {
@@ -1357,6 +1745,140 @@ var _ = Describe("validate image name change", func() {
 			}
 			Expect(clusterNew.validateImageChange(&clusterOld)).To(BeEmpty())
 		})
+
+		It("doesn't complain on minor upgrades with registry-prefixed images", func() {
+			clusterOld := Cluster{
+				Spec: ClusterSpec{
+					ImageName: "registry.example.com:5000/postgresql/postgresql:14.5",
+				},
+			}
+			clusterNew := Cluster{
+				Spec: ClusterSpec{
+					ImageName: "registry.example.com:5000/postgresql/postgresql:14.7-1",
+				},
+			}
+			Expect(clusterNew.validateImageChange(&clusterOld)).To(BeEmpty())
+		})
+
+		It("complains on major upgrades without the allowMajorUpgrade annotation", func() {
+			clusterOld := Cluster{
+				Spec: ClusterSpec{
+					ImageName: "ghcr.io/cloudnative-pg/postgresql:14.5-1",
+				},
+			}
+			clusterNew := Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						utils.AllowMajorUpgradeAnnotationName: "false",
+					},
+				},
+				Spec: ClusterSpec{
+					ImageName: "ghcr.io/cloudnative-pg/postgresql:15.2",
+				},
+			}
+			Expect(clusterNew.validateImageChange(&clusterOld)).To(HaveLen(1))
+		})
+
+		It("complains on major upgrades with only the allowMajorUpgrade annotation", func() {
+			clusterOld := Cluster{
+				Spec: ClusterSpec{
+					ImageName: "ghcr.io/cloudnative-pg/postgresql:14.5-1",
+				},
+			}
+			clusterNew := Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						utils.AllowMajorUpgradeAnnotationName: "true",
+					},
+				},
+				Spec: ClusterSpec{
+					ImageName: "ghcr.io/cloudnative-pg/postgresql:15.2",
+				},
+			}
+			Expect(clusterNew.validateImageChange(&clusterOld)).To(HaveLen(1))
+		})
+
+		It("complains on major upgrades with only the pg_upgrade strategy", func() {
+			clusterOld := Cluster{
+				Spec: ClusterSpec{
+					ImageName: "ghcr.io/cloudnative-pg/postgresql:16.4",
+				},
+			}
+			clusterNew := Cluster{
+				Spec: ClusterSpec{
+					ImageName: "ghcr.io/cloudnative-pg/postgresql:17.0",
+					PostgresConfiguration: PostgresConfiguration{
+						MajorUpgrade: &MajorUpgradeConfiguration{
+							Strategy: MajorUpgradeStrategyPgUpgrade,
+						},
+					},
+				},
+			}
+			Expect(clusterNew.validateImageChange(&clusterOld)).To(HaveLen(1))
+		})
+
+		It("doesn't complain on major upgrades with the pg_upgrade strategy and the annotation", func() {
+			clusterOld := Cluster{
+				Spec: ClusterSpec{
+					ImageName: "ghcr.io/cloudnative-pg/postgresql:16.4",
+				},
+			}
+			clusterNew := Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						utils.AllowMajorUpgradeAnnotationName: "true",
+					},
+				},
+				Spec: ClusterSpec{
+					ImageName: "ghcr.io/cloudnative-pg/postgresql:17.0",
+					PostgresConfiguration: PostgresConfiguration{
+						MajorUpgrade: &MajorUpgradeConfiguration{
+							Strategy: MajorUpgradeStrategyPgUpgrade,
+						},
+					},
+				},
+			}
+			Expect(clusterNew.validateImageChange(&clusterOld)).To(BeEmpty())
+		})
+
+		It("doesn't complain going back to the major version of the data directories", func() {
+			clusterOld := Cluster{
+				Spec: ClusterSpec{
+					ImageName: "ghcr.io/cloudnative-pg/postgresql:17.0",
+				},
+				Status: ClusterStatus{
+					PGDataImageInfo: &ImageInfo{
+						Image:        "ghcr.io/cloudnative-pg/postgresql:16.4",
+						MajorVersion: 16,
+					},
+				},
+			}
+			clusterNew := Cluster{
+				Spec: ClusterSpec{
+					ImageName: "ghcr.io/cloudnative-pg/postgresql:16.4",
+				},
+			}
+			Expect(clusterNew.validateImageChange(&clusterOld)).To(BeEmpty())
+		})
+
+		It("complains on major downgrades even with the allowMajorUpgrade annotation", func() {
+			clusterOld := Cluster{
+				Spec: ClusterSpec{
+					ImageName: "ghcr.io/cloudnative-pg/postgresql:15.2",
+				},
+			}
+			clusterNew := Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						utils.AllowMajorUpgradeAnnotationName: "true",
+					},
+				},
+				Spec: ClusterSpec{
+					ImageName: "ghcr.io/cloudnative-pg/postgresql:14.5-1",
+				},
+			}
+			Expect(clusterNew.validateImageChange(&clusterOld)).To(HaveLen(1))
+		})
 	})
 	Context("using image catalog", func() {
 		It("complains on major upgrades", func() {
@@ -1814,6 +2336,81 @@ var _ = Describe("recovery target", func() {
 			Expect(cluster.validateRecoveryTarget()).To(HaveLen(1))
 		})
 	})
+
+	When("targetAction is specified", func() {
+		It("allows pausing at a recovery target", func() {
+			cluster := Cluster{
+				Spec: ClusterSpec{
+					Bootstrap: &BootstrapConfiguration{
+						Recovery: &BootstrapRecovery{
+							RecoveryTarget: &RecoveryTarget{
+								TargetTime:   "2020-01-01 01:01:00",
+								TargetAction: RecoveryTargetActionPause,
+							},
+						},
+					},
+				},
+			}
+			Expect(cluster.validateRecoveryTarget()).To(BeEmpty())
+		})
+
+		It("allows promoting without a recovery target", func() {
+			cluster := Cluster{
+				Spec: ClusterSpec{
+					Bootstrap: &BootstrapConfiguration{
+						Recovery: &BootstrapRecovery{
+							RecoveryTarget: &RecoveryTarget{
+								TargetTLI:    "latest",
+								TargetAction: RecoveryTargetActionPromote,
+							},
+						},
+					},
+				},
+			}
+			Expect(cluster.validateRecoveryTarget()).To(BeEmpty())
+		})
+
+		It("prevents pausing or shutting down without a recovery target", func() {
+			cluster := Cluster{
+				Spec: ClusterSpec{
+					Bootstrap: &BootstrapConfiguration{
+						Recovery: &BootstrapRecovery{
+							RecoveryTarget: &RecoveryTarget{
+								TargetTLI:    "latest",
+								TargetAction: RecoveryTargetActionShutdown,
+							},
+						},
+					},
+				},
+			}
+			Expect(cluster.validateRecoveryTarget()).To(HaveLen(1))
+
+			cluster.Spec.Bootstrap.Recovery.RecoveryTarget.TargetImmediate = ptr.To(false)
+			cluster.Spec.Bootstrap.Recovery.RecoveryTarget.BackupID = "20220616T031500"
+			cluster.Spec.Bootstrap.Recovery.RecoveryTarget.TargetAction = RecoveryTargetActionPause
+			Expect(cluster.validateRecoveryTarget()).To(HaveLen(1))
+		})
+
+		It("prevents pausing in a replica cluster", func() {
+			cluster := Cluster{
+				Spec: ClusterSpec{
+					ReplicaCluster: &ReplicaClusterConfiguration{
+						Enabled: ptr.To(true),
+						Source:  "origin",
+					},
+					Bootstrap: &BootstrapConfiguration{
+						Recovery: &BootstrapRecovery{
+							RecoveryTarget: &RecoveryTarget{
+								TargetLSN:    "1/1",
+								TargetAction: RecoveryTargetActionPause,
+							},
+						},
+					},
+				},
+			}
+			Expect(cluster.validateRecoveryTarget()).To(HaveLen(1))
+		})
+	})
 })
 
 var _ = Describe("primary update strategy", func() {
@@ -1953,6 +2550,28 @@ var _ = Describe("validateSynchronousReplicaConfiguration", func() {
 		Expect(errors).To(BeEmpty())
 	})
 
+	It("returns an error when the maximum lag of the synchronous standbys is not valid", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Instances: 3,
+				PostgresConfiguration: PostgresConfiguration{
+					Synchronous: &SynchronousReplicaConfiguration{
+						Number: 1,
+						LagRotation: &SynchronousStandbyLagRotation{
+							MaxLag: "lots",
+						},
+					},
+				},
+			},
+		}
+		errors := cluster.validateSynchronousReplicaConfiguration()
+		Expect(errors).To(HaveLen(1))
+		Expect(errors[0].Field).To(Equal("spec.postgresql.synchronous.lagRotation.maxLag"))
+
+		cluster.Spec.PostgresConfiguration.Synchronous.LagRotation.MaxLag = "128Mi"
+		Expect(cluster.validateSynchronousReplicaConfiguration()).To(BeEmpty())
+	})
+
 	It("returns an error when number of synchronous replicas is greater than the total instances and standbys", func() {
 		cluster := &Cluster{
 			Spec: ClusterSpec{
@@ -2323,47 +2942,168 @@ var _ = Describe("bootstrap recovery validation", func() {
 				},
 			},
 		}
-
-		result := cluster.validateRecoveryApplicationDatabase()
-		Expect(result).To(BeEmpty())
+
+		result := cluster.validateRecoveryApplicationDatabase()
+		Expect(result).To(BeEmpty())
+	})
+
+	It("does not complain when bootstrap recovery source matches one of the names of external clusters", func() {
+		recoveryCluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						Source: "test",
+					},
+				},
+				ExternalClusters: []ExternalCluster{
+					{
+						Name: "test",
+					},
+				},
+			},
+		}
+		errorsList := recoveryCluster.validateBootstrapRecoverySource()
+		Expect(errorsList).To(BeEmpty())
+	})
+
+	It("complains when bootstrap recovery source does not match one of the names of external clusters", func() {
+		recoveryCluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						Source: "test",
+					},
+				},
+				ExternalClusters: []ExternalCluster{
+					{
+						Name: "another-test",
+					},
+				},
+			},
+		}
+		errorsList := recoveryCluster.validateBootstrapRecoverySource()
+		Expect(errorsList).ToNot(BeEmpty())
+	})
+
+	It("accepts queries validating the recovered data", func() {
+		recoveryCluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						Source:        "test",
+						ValidationSQL: []string{"SELECT count(*) > 0 FROM orders"},
+					},
+				},
+			},
+		}
+		Expect(recoveryCluster.validateBootstrapRecoveryValidationSQL()).To(BeEmpty())
+	})
+
+	It("complains about empty validation queries", func() {
+		recoveryCluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						Source:        "test",
+						ValidationSQL: []string{"SELECT 1", "  "},
+					},
+				},
+			},
+		}
+		errorsList := recoveryCluster.validateBootstrapRecoveryValidationSQL()
+		Expect(errorsList).To(HaveLen(1))
+		Expect(errorsList[0].Field).To(Equal("spec.bootstrap.recovery.validationSQL[1]"))
+	})
+
+	It("complains about validation queries in a replica cluster", func() {
+		recoveryCluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						Source:        "test",
+						ValidationSQL: []string{"SELECT 1"},
+					},
+				},
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled: ptr.To(true),
+					Source:  "test",
+				},
+			},
+		}
+		Expect(recoveryCluster.validateBootstrapRecoveryValidationSQL()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("replication limits warnings", func() {
+	It("warns when max_wal_senders is too low for the number of instances", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Instances: 5,
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{
+						"max_wal_senders":       "5",
+						"max_replication_slots": "32",
+					},
+				},
+			},
+		}
+		warnings := cluster.getReplicationLimitsAdmissionWarnings()
+		Expect(warnings).To(HaveLen(1))
+		Expect(warnings[0]).To(ContainSubstring("`max_wal_senders` is set to 5, while at least 8"))
+	})
+
+	It("doesn't warn when the replication limits are not set or are enough", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Instances: 3,
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{
+						"max_replication_slots": "32",
+					},
+				},
+			},
+		}
+		Expect(cluster.getReplicationLimitsAdmissionWarnings()).To(BeEmpty())
+	})
+
+	It("doesn't warn when the WAL level is minimal", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Instances: 1,
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{
+						"wal_level":       "minimal",
+						"max_wal_senders": "0",
+					},
+				},
+			},
+		}
+		Expect(cluster.getReplicationLimitsAdmissionWarnings()).To(BeEmpty())
 	})
+})
 
-	It("does not complain when bootstrap recovery source matches one of the names of external clusters", func() {
-		recoveryCluster := &Cluster{
+var _ = Describe("job retention validation", func() {
+	It("accepts a positive retention of the completed Jobs", func() {
+		cluster := &Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					Recovery: &BootstrapRecovery{
-						Source: "test",
-					},
-				},
-				ExternalClusters: []ExternalCluster{
-					{
-						Name: "test",
-					},
+				JobRetention: &JobRetentionConfiguration{
+					TTL:      &metav1.Duration{Duration: time.Hour},
+					KeepLast: 2,
 				},
 			},
 		}
-		errorsList := recoveryCluster.validateBootstrapRecoverySource()
-		Expect(errorsList).To(BeEmpty())
+		Expect(cluster.validateJobRetention()).To(BeEmpty())
 	})
 
-	It("complains when bootstrap recovery source does not match one of the names of external clusters", func() {
-		recoveryCluster := &Cluster{
+	It("complains about a negative retention of the completed Jobs", func() {
+		cluster := &Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					Recovery: &BootstrapRecovery{
-						Source: "test",
-					},
-				},
-				ExternalClusters: []ExternalCluster{
-					{
-						Name: "another-test",
-					},
+				JobRetention: &JobRetentionConfiguration{
+					TTL: &metav1.Duration{Duration: -time.Hour},
 				},
 			},
 		}
-		errorsList := recoveryCluster.validateBootstrapRecoverySource()
-		Expect(errorsList).ToNot(BeEmpty())
+		Expect(cluster.validateJobRetention()).To(HaveLen(1))
 	})
 })
 
@@ -2405,6 +3145,54 @@ var _ = Describe("toleration validation", func() {
 	})
 })
 
+var _ = Describe("inherited metadata validation", func() {
+	It("doesn't complain if no inherited metadata is set", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateInheritedMetadata()).To(BeEmpty())
+	})
+
+	It("accepts custom labels", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				InheritedMetadata: &EmbeddedObjectMetadata{
+					Labels: map[string]string{
+						"cost-center":             "finance",
+						"example.com/environment": "production",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateInheritedMetadata()).To(BeEmpty())
+	})
+
+	It("complains about labels reserved to the operator", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				InheritedMetadata: &EmbeddedObjectMetadata{
+					Labels: map[string]string{
+						utils.ClusterLabelName:     "another-cluster",
+						utils.ClusterRoleLabelName: "primary",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateInheritedMetadata()).To(HaveLen(2))
+	})
+
+	It("complains about malformed labels", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				InheritedMetadata: &EmbeddedObjectMetadata{
+					Labels: map[string]string{
+						"cost center": "finance",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateInheritedMetadata()).ToNot(BeEmpty())
+	})
+})
+
 var _ = Describe("validate anti-affinity", func() {
 	t := true
 	f := false
@@ -3111,6 +3899,313 @@ var _ = Describe("Backup validation", func() {
 		err := cluster.validateBackupConfiguration()
 		Expect(err).To(HaveLen(1))
 	})
+
+	Context("with credentials inherited from the environment", func() {
+		newCluster := func(credentials BarmanCredentials) *Cluster {
+			return &Cluster{
+				Spec: ClusterSpec{
+					Backup: &BackupConfiguration{
+						BarmanObjectStore: &BarmanObjectStoreConfiguration{
+							DestinationPath:   "s3://data/",
+							BarmanCredentials: credentials,
+						},
+					},
+				},
+			}
+		}
+
+		It("doesn't require any static secret", func() {
+			Expect(newCluster(BarmanCredentials{
+				AWS: &S3Credentials{InheritFromIAMRole: true},
+			}).validateBackupConfiguration()).To(BeEmpty())
+			Expect(newCluster(BarmanCredentials{
+				Azure: &AzureCredentials{InheritFromAzureAD: true},
+			}).validateBackupConfiguration()).To(BeEmpty())
+			Expect(newCluster(BarmanCredentials{
+				Google: &GoogleCredentials{GKEEnvironment: true},
+			}).validateBackupConfiguration()).To(BeEmpty())
+		})
+
+		It("complains if static keys are set too", func() {
+			cluster := newCluster(BarmanCredentials{
+				AWS: &S3Credentials{
+					InheritFromIAMRole: true,
+					AccessKeyIDReference: &SecretKeySelector{
+						LocalObjectReference: LocalObjectReference{Name: "aws-creds"},
+						Key:                  "ACCESS_KEY_ID",
+					},
+					SecretAccessKeyReference: &SecretKeySelector{
+						LocalObjectReference: LocalObjectReference{Name: "aws-creds"},
+						Key:                  "ACCESS_SECRET_KEY",
+					},
+				},
+			})
+			Expect(cluster.validateBackupConfiguration()).ToNot(BeEmpty())
+		})
+
+		It("complains if a session token is set too", func() {
+			cluster := newCluster(BarmanCredentials{
+				AWS: &S3Credentials{
+					InheritFromIAMRole: true,
+					SessionToken: &SecretKeySelector{
+						LocalObjectReference: LocalObjectReference{Name: "aws-creds"},
+						Key:                  "SESSION_TOKEN",
+					},
+				},
+			})
+			result := cluster.validateBackupConfiguration()
+			Expect(result).To(HaveLen(1))
+			Expect(result[0].Field).To(Equal("spec.backup.barmanObjectStore.s3Credentials.sessionToken"))
+		})
+
+		It("complains if a storage key is set together with Azure AD", func() {
+			cluster := newCluster(BarmanCredentials{
+				Azure: &AzureCredentials{
+					InheritFromAzureAD: true,
+					StorageKey: &SecretKeySelector{
+						LocalObjectReference: LocalObjectReference{Name: "azure-creds"},
+						Key:                  "AZURE_STORAGE_KEY",
+					},
+				},
+			})
+			Expect(cluster.validateBackupConfiguration()).ToNot(BeEmpty())
+		})
+	})
+
+	Context("with a separate WAL object store", func() {
+		endpointCA := &SecretKeySelector{
+			LocalObjectReference: LocalObjectReference{Name: "minio-ca"},
+			Key:                  "ca.crt",
+		}
+
+		newCluster := func(walObjectStore *BarmanObjectStoreConfiguration) *Cluster {
+			return &Cluster{
+				Spec: ClusterSpec{
+					Backup: &BackupConfiguration{
+						BarmanObjectStore: &BarmanObjectStoreConfiguration{
+							DestinationPath: "s3://data/",
+							EndpointCA:      endpointCA,
+							BarmanCredentials: BarmanCredentials{
+								AWS: &S3Credentials{InheritFromIAMRole: true},
+							},
+						},
+						WALObjectStore: walObjectStore,
+					},
+				},
+			}
+		}
+
+		It("accepts a WAL object store with its own credentials", func() {
+			cluster := newCluster(&BarmanObjectStoreConfiguration{
+				DestinationPath: "s3://wal/",
+				EndpointCA:      endpointCA,
+				BarmanCredentials: BarmanCredentials{
+					Azure: &AzureCredentials{InheritFromAzureAD: true},
+				},
+			})
+			Expect(cluster.validateBackupConfiguration()).To(BeEmpty())
+		})
+
+		It("complains if the WAL object store has no credentials", func() {
+			cluster := newCluster(&BarmanObjectStoreConfiguration{
+				DestinationPath: "s3://wal/",
+			})
+			Expect(cluster.validateBackupConfiguration()).To(HaveLen(1))
+		})
+
+		It("complains if the WAL object store uses a different endpoint CA", func() {
+			cluster := newCluster(&BarmanObjectStoreConfiguration{
+				DestinationPath: "s3://wal/",
+				EndpointCA: &SecretKeySelector{
+					LocalObjectReference: LocalObjectReference{Name: "another-ca"},
+					Key:                  "ca.crt",
+				},
+				BarmanCredentials: BarmanCredentials{
+					AWS: &S3Credentials{InheritFromIAMRole: true},
+				},
+			})
+			Expect(cluster.validateBackupConfiguration()).To(HaveLen(1))
+		})
+
+		It("complains if the WAL object store is set without the base backup one", func() {
+			cluster := newCluster(&BarmanObjectStoreConfiguration{
+				DestinationPath: "s3://wal/",
+				BarmanCredentials: BarmanCredentials{
+					AWS: &S3Credentials{InheritFromIAMRole: true},
+				},
+			})
+			cluster.Spec.Backup.BarmanObjectStore = nil
+			Expect(cluster.validateBackupConfiguration()).To(HaveLen(1))
+		})
+
+		It("validates the WAL object store of the external clusters", func() {
+			cluster := &Cluster{
+				Spec: ClusterSpec{
+					ExternalClusters: []ExternalCluster{
+						{
+							Name: "origin",
+							BarmanObjectStore: &BarmanObjectStoreConfiguration{
+								DestinationPath: "s3://data/",
+								BarmanCredentials: BarmanCredentials{
+									AWS: &S3Credentials{InheritFromIAMRole: true},
+								},
+							},
+							WALObjectStore: &BarmanObjectStoreConfiguration{
+								DestinationPath: "s3://wal/",
+							},
+						},
+					},
+				},
+			}
+			Expect(cluster.validateExternalClusters()).To(HaveLen(1))
+
+			cluster.Spec.ExternalClusters[0].WALObjectStore.BarmanCredentials.AWS = &S3Credentials{
+				InheritFromIAMRole: true,
+			}
+			Expect(cluster.validateExternalClusters()).To(BeEmpty())
+		})
+	})
+
+	Context("with the verification of the WAL archive", func() {
+		newCluster := func(configuration *ArchiveVerificationConfiguration) *Cluster {
+			return &Cluster{
+				Spec: ClusterSpec{
+					Backup: &BackupConfiguration{
+						BarmanObjectStore: &BarmanObjectStoreConfiguration{
+							DestinationPath: "s3://data/",
+							BarmanCredentials: BarmanCredentials{
+								AWS: &S3Credentials{InheritFromIAMRole: true},
+							},
+						},
+						ArchiveVerification: configuration,
+					},
+				},
+			}
+		}
+
+		It("accepts the default interval", func() {
+			cluster := newCluster(&ArchiveVerificationConfiguration{})
+			Expect(cluster.validateBackupConfiguration()).To(BeEmpty())
+		})
+
+		It("complains if the interval is shorter than one minute", func() {
+			cluster := newCluster(&ArchiveVerificationConfiguration{
+				Interval: &metav1.Duration{Duration: 30 * time.Second},
+			})
+			Expect(cluster.validateBackupConfiguration()).To(HaveLen(1))
+		})
+
+		It("complains if there is no object store to verify", func() {
+			cluster := newCluster(&ArchiveVerificationConfiguration{})
+			cluster.Spec.Backup.BarmanObjectStore = nil
+			Expect(cluster.validateBackupConfiguration()).To(HaveLen(1))
+		})
+	})
+
+	Context("with the WAL compression level", func() {
+		newCluster := func(compression barmanApi.CompressionType, level int) *Cluster {
+			return &Cluster{
+				Spec: ClusterSpec{
+					Backup: &BackupConfiguration{
+						BarmanObjectStore: &BarmanObjectStoreConfiguration{
+							DestinationPath: "s3://data/",
+							BarmanCredentials: BarmanCredentials{
+								AWS: &S3Credentials{InheritFromIAMRole: true},
+							},
+							Wal: &WalBackupConfiguration{
+								Compression: compression,
+							},
+						},
+						WALCompressionLevel: ptr.To(level),
+					},
+				},
+			}
+		}
+
+		DescribeTable("validates the level against the compression algorithm",
+			func(compression barmanApi.CompressionType, level int, valid bool) {
+				result := newCluster(compression, level).validateBackupConfiguration()
+				if valid {
+					Expect(result).To(BeEmpty())
+				} else {
+					Expect(result).To(HaveLen(1))
+					Expect(result[0].Field).To(Equal("spec.backup.walCompressionLevel"))
+				}
+			},
+			Entry("gzip with a valid level", barmanApi.CompressionTypeGzip, 9, true),
+			Entry("bzip2 with a valid level", barmanApi.CompressionTypeBzip2, 1, true),
+			Entry("gzip with a level too high", barmanApi.CompressionTypeGzip, 10, false),
+			Entry("bzip2 with a level too low", barmanApi.CompressionTypeBzip2, 0, false),
+			Entry("snappy, not supporting levels", barmanApi.CompressionTypeSnappy, 5, false),
+			Entry("no compression", barmanApi.CompressionType(""), 5, false),
+		)
+
+		It("uses the compression of the WAL object store when set", func() {
+			cluster := newCluster("", 5)
+			cluster.Spec.Backup.WALObjectStore = &BarmanObjectStoreConfiguration{
+				DestinationPath: "s3://wal/",
+				BarmanCredentials: BarmanCredentials{
+					AWS: &S3Credentials{InheritFromIAMRole: true},
+				},
+				Wal: &WalBackupConfiguration{
+					Compression: barmanApi.CompressionTypeGzip,
+				},
+			}
+			Expect(cluster.validateBackupConfiguration()).To(BeEmpty())
+		})
+
+		It("complains if there is no object store", func() {
+			cluster := newCluster("", 5)
+			cluster.Spec.Backup.BarmanObjectStore = nil
+			Expect(cluster.validateBackupConfiguration()).To(HaveLen(1))
+		})
+	})
+
+	Context("with a templated destination path", func() {
+		newCluster := func(destinationPath, walDestinationPath string) *Cluster {
+			cluster := &Cluster{
+				Spec: ClusterSpec{
+					Backup: &BackupConfiguration{
+						BarmanObjectStore: &BarmanObjectStoreConfiguration{
+							DestinationPath: destinationPath,
+							BarmanCredentials: BarmanCredentials{
+								AWS: &S3Credentials{InheritFromIAMRole: true},
+							},
+						},
+					},
+				},
+			}
+			if walDestinationPath != "" {
+				cluster.Spec.Backup.WALObjectStore = &BarmanObjectStoreConfiguration{
+					DestinationPath: walDestinationPath,
+					BarmanCredentials: BarmanCredentials{
+						AWS: &S3Credentials{InheritFromIAMRole: true},
+					},
+				}
+			}
+			return cluster
+		}
+
+		It("accepts the supported placeholders", func() {
+			cluster := newCluster("s3://backups/{{.Namespace}}/{{.ClusterName}}/",
+				"s3://wal/{{ .Namespace }}-{{ .ClusterName }}/")
+			Expect(cluster.validateBackupConfiguration()).To(BeEmpty())
+		})
+
+		It("complains if the template can't be parsed", func() {
+			cluster := newCluster("s3://backups/{{.Namespace/", "")
+			result := cluster.validateBackupConfiguration()
+			Expect(result).To(HaveLen(1))
+			Expect(result[0].Field).To(Equal("spec.backup.barmanObjectStore.destinationPath"))
+		})
+
+		It("complains if the template references an unknown field", func() {
+			cluster := newCluster("s3://backups/{{.Namespace}}/", "s3://wal/{{.Labels}}/")
+			result := cluster.validateBackupConfiguration()
+			Expect(result).To(HaveLen(1))
+			Expect(result[0].Field).To(Equal("spec.backup.walObjectStore.destinationPath"))
+		})
+	})
 })
 
 var _ = Describe("Backup retention policy validation", func() {
@@ -3612,6 +4707,53 @@ var _ = Describe("validation of replication slots configuration", func() {
 		Expect(errors).To(BeEmpty())
 	})
 
+	It("uses the replication slots prefix for the HA slots", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: versions.DefaultImageName,
+				ReplicationSlots: &ReplicationSlotsConfiguration{
+					Prefix: "_one_",
+					HighAvailability: &ReplicationSlotsHAConfiguration{
+						Enabled:    ptr.To(true),
+						SlotPrefix: "_cnpg_",
+					},
+				},
+			},
+		}
+		cluster.Default()
+		Expect(cluster.Spec.ReplicationSlots.HighAvailability.SlotPrefix).To(Equal("_one_"))
+		Expect(cluster.validateReplicationSlots()).To(BeEmpty())
+	})
+
+	It("rejects an HA slot prefix different from the replication slots prefix", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: versions.DefaultImageName,
+				ReplicationSlots: &ReplicationSlotsConfiguration{
+					Prefix: "_one_",
+					HighAvailability: &ReplicationSlotsHAConfiguration{
+						Enabled:    ptr.To(true),
+						SlotPrefix: "_two_",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateReplicationSlots()).To(HaveLen(1))
+	})
+
+	It("prevents changing the replication slots prefix", func() {
+		oldCluster := &Cluster{
+			Spec: ClusterSpec{
+				ReplicationSlots: &ReplicationSlotsConfiguration{
+					Prefix: "_one_",
+				},
+			},
+		}
+		newCluster := oldCluster.DeepCopy()
+		newCluster.Spec.ReplicationSlots.Prefix = "_two_"
+		Expect(newCluster.validateReplicationSlotsChange(oldCluster)).To(HaveLen(1))
+	})
+
 	It("should not return an error when SynchronizeReplicasConfiguration is nil", func() {
 		cluster := &Cluster{
 			Spec: ClusterSpec{
@@ -3626,6 +4768,54 @@ var _ = Describe("validation of replication slots configuration", func() {
 	})
 })
 
+var _ = Describe("validation of replication configuration", func() {
+	It("accepts an empty configuration", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateReplication()).To(BeEmpty())
+	})
+
+	DescribeTable("validates the pg_basebackup maximum transfer rate",
+		func(maxRate string, expectedErrors int) {
+			cluster := &Cluster{
+				Spec: ClusterSpec{
+					Replication: &ReplicationConfiguration{
+						PgBaseBackup: &PgBaseBackupOptions{
+							Checkpoint: CheckpointModeFast,
+							MaxRate:    maxRate,
+						},
+					},
+				},
+			}
+			Expect(cluster.validateReplication()).To(HaveLen(expectedErrors))
+		},
+		Entry("plain kilobytes", "1024", 0),
+		Entry("kilobytes with suffix", "32k", 0),
+		Entry("megabytes with suffix", "1024M", 0),
+		Entry("below the minimum", "31k", 1),
+		Entry("above the maximum", "1025M", 1),
+		Entry("invalid value", "fast", 1),
+	)
+
+	DescribeTable("validates the credentials rotation period",
+		func(period time.Duration, replicationTLSSecret string, expectedErrors int) {
+			cluster := &Cluster{
+				Spec: ClusterSpec{
+					Replication: &ReplicationConfiguration{
+						CredentialsRotationPeriod: &metav1.Duration{Duration: period},
+					},
+					Certificates: &CertificatesConfiguration{
+						ReplicationTLSSecret: replicationTLSSecret,
+					},
+				},
+			}
+			Expect(cluster.validateReplication()).To(HaveLen(expectedErrors))
+		},
+		Entry("a valid period", 24*time.Hour, "", 0),
+		Entry("a too short period", time.Minute, "", 1),
+		Entry("a user-provided replication secret", 24*time.Hour, "my-replication-secret", 1),
+	)
+})
+
 var _ = Describe("Environment variables validation", func() {
 	When("an environment variable is given", func() {
 		It("detects if it is valid", func() {
@@ -3713,6 +4903,53 @@ var _ = Describe("Storage configuration validation", func() {
 	})
 })
 
+var _ = Describe("Disk pressure configuration validation", func() {
+	It("succeeds if no disk pressure configuration is present", func() {
+		cluster := Cluster{}
+		Expect(cluster.validateDiskPressure()).To(BeEmpty())
+	})
+
+	It("succeeds if the maximum size is greater than the storage size", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				StorageConfiguration: StorageConfiguration{Size: "1Gi"},
+				DiskPressure:         &DiskPressureConfiguration{AutoExpand: true, MaxSize: "10Gi"},
+			},
+		}
+		Expect(cluster.validateDiskPressure()).To(BeEmpty())
+	})
+
+	It("complains if the automatic expansion is enabled without a maximum size", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				StorageConfiguration: StorageConfiguration{Size: "1Gi"},
+				DiskPressure:         &DiskPressureConfiguration{AutoExpand: true},
+			},
+		}
+		Expect(cluster.validateDiskPressure()).To(HaveLen(1))
+	})
+
+	It("complains if the maximum size is not valid", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				StorageConfiguration: StorageConfiguration{Size: "1Gi"},
+				DiskPressure:         &DiskPressureConfiguration{MaxSize: "ten gigabytes"},
+			},
+		}
+		Expect(cluster.validateDiskPressure()).To(HaveLen(1))
+	})
+
+	It("complains if the maximum size is lower than the storage size", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				StorageConfiguration: StorageConfiguration{Size: "10Gi"},
+				DiskPressure:         &DiskPressureConfiguration{MaxSize: "1Gi"},
+			},
+		}
+		Expect(cluster.validateDiskPressure()).To(HaveLen(1))
+	})
+})
+
 var _ = Describe("Ephemeral volume configuration validation", func() {
 	It("succeeds if no ephemeral configuration is present", func() {
 		cluster := Cluster{
@@ -3808,6 +5045,49 @@ var _ = Describe("Role management validation", func() {
 		Expect(cluster.validateManagedRoles()).To(HaveLen(1))
 	})
 
+	It("should produce an error on a connection limit greater than max_connections", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"max_connections": "50"},
+				},
+				Managed: &ManagedConfiguration{
+					Roles: []RoleConfiguration{
+						{
+							Name:            "app",
+							ConnectionLimit: 20,
+						},
+						{
+							Name:            "reporting",
+							ConnectionLimit: 80,
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateManagedRoles()).To(HaveLen(1))
+	})
+
+	It("should compare the connection limit with the default max_connections", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Roles: []RoleConfiguration{
+						{
+							Name:            "app",
+							ConnectionLimit: 100,
+						},
+						{
+							Name:            "reporting",
+							ConnectionLimit: 101,
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateManagedRoles()).To(HaveLen(1))
+	})
+
 	It("should produce an error if the role is reserved", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{
@@ -3881,6 +5161,97 @@ var _ = Describe("Role management validation", func() {
 		}
 		Expect(cluster.validateManagedRoles()).To(HaveLen(1))
 	})
+
+	It("should succeed with a hierarchy of role memberships", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Roles: []RoleConfiguration{
+						{
+							Name:    "app_user",
+							InRoles: []string{"app_readwrite"},
+						},
+						{
+							Name:                   "app_readwrite",
+							InRoles:                []string{"app_readonly"},
+							InRolesWithAdminOption: []string{"pg_monitor"},
+						},
+						{
+							Name: "app_readonly",
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateManagedRoles()).To(BeEmpty())
+	})
+
+	It("should produce an error if a role is a member of itself", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Roles: []RoleConfiguration{
+						{
+							Name:                   "app_admin",
+							InRolesWithAdminOption: []string{"app_admin"},
+						},
+					},
+				},
+			},
+		}
+		result := cluster.validateManagedRoles()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Detail).To(ContainSubstring("member of itself"))
+	})
+
+	It("should produce an error if a role is a member of an absent role", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Roles: []RoleConfiguration{
+						{
+							Name:    "app_user",
+							InRoles: []string{"legacy"},
+						},
+						{
+							Name:   "legacy",
+							Ensure: EnsureAbsent,
+						},
+					},
+				},
+			},
+		}
+		result := cluster.validateManagedRoles()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Detail).To(ContainSubstring("ensured to be absent"))
+	})
+
+	It("should produce an error on circular memberships", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Roles: []RoleConfiguration{
+						{
+							Name:    "app_user",
+							InRoles: []string{"app_readwrite"},
+						},
+						{
+							Name:    "app_readwrite",
+							InRoles: []string{"app_readonly"},
+						},
+						{
+							Name:                   "app_readonly",
+							InRolesWithAdminOption: []string{"app_readwrite"},
+						},
+					},
+				},
+			},
+		}
+		result := cluster.validateManagedRoles()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Detail).To(Equal(
+			"Circular role membership: app_readwrite -> app_readonly -> app_readwrite"))
+	})
 })
 
 var _ = Describe("Managed Extensions validation", func() {
@@ -4670,6 +6041,49 @@ var _ = Describe("Validate hibernation", func() {
 	})
 })
 
+var _ = Describe("validateFencedUntil", func() {
+	newCluster := func(fencedInstances, fencedUntil string) *Cluster {
+		annotations := map[string]string{
+			utils.FencedUntilAnnotation: fencedUntil,
+		}
+		if fencedInstances != "" {
+			annotations[utils.FencedInstanceAnnotation] = fencedInstances
+		}
+		return &Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: annotations,
+			},
+		}
+	}
+	future := time.Now().Add(time.Hour).Format(time.RFC3339)
+	past := time.Now().Add(-time.Hour).Format(time.RFC3339)
+
+	It("accepts clusters without an expiry time", func() {
+		Expect((&Cluster{}).validateFencedUntil(nil)).To(BeEmpty())
+	})
+
+	It("accepts an expiry time in the future", func() {
+		Expect(newCluster(`["*"]`, future).validateFencedUntil(nil)).To(BeEmpty())
+	})
+
+	It("rejects an expiry time in the past when fencing", func() {
+		Expect(newCluster(`["*"]`, past).validateFencedUntil(nil)).To(HaveLen(1))
+		Expect(newCluster(`["*"]`, past).validateFencedUntil(newCluster("", past))).To(HaveLen(1))
+	})
+
+	It("rejects an invalid expiry time", func() {
+		Expect(newCluster(`["*"]`, "tomorrow").validateFencedUntil(nil)).To(HaveLen(1))
+	})
+
+	It("accepts an expiry time in the past when no instance is fenced", func() {
+		Expect(newCluster("", past).validateFencedUntil(nil)).To(BeEmpty())
+	})
+
+	It("accepts an expiry time that passed after the fencing was set", func() {
+		Expect(newCluster(`["*"]`, past).validateFencedUntil(newCluster(`["*"]`, past))).To(BeEmpty())
+	})
+})
+
 var _ = Describe("validateManagedServices", func() {
 	var cluster *Cluster
 
@@ -4796,6 +6210,55 @@ var _ = Describe("validateManagedServices", func() {
 			Expect(errs[0].Field).To(Equal("spec.managed.services.disabledDefaultServices"))
 		})
 	})
+
+	Context("external DNS host name of the rw service", func() {
+		It("should allow a fully qualified domain name", func() {
+			cluster.Spec.Managed.Services.RW = &ReadWriteServiceConfiguration{
+				ExternalDNSHostname: "db.example.com",
+			}
+			Expect(cluster.validateManagedServices()).To(BeEmpty())
+		})
+
+		It("should reject invalid host names", func() {
+			for _, hostname := range []string{"DB.example.com", "db_primary.example.com", "db", "db.example.com."} {
+				cluster.Spec.Managed.Services.RW = &ReadWriteServiceConfiguration{
+					ExternalDNSHostname: hostname,
+				}
+				errs := cluster.validateManagedServices()
+				Expect(errs).ToNot(BeEmpty(), hostname)
+				Expect(errs[0].Field).To(Equal("spec.managed.services.rw.externalDNSHostname"))
+			}
+		})
+	})
+
+	Context("metadata of the default services", func() {
+		It("should allow valid labels and annotations", func() {
+			cluster.Spec.Managed.Services.DefaultServicesMetadata = &EmbeddedObjectMetadata{
+				Labels:      map[string]string{"team": "dba"},
+				Annotations: map[string]string{"service.beta.kubernetes.io/aws-load-balancer-type": "nlb"},
+			}
+			Expect(cluster.validateManagedServices()).To(BeEmpty())
+		})
+
+		It("should reject the labels reserved to the operator", func() {
+			cluster.Spec.Managed.Services.DefaultServicesMetadata = &EmbeddedObjectMetadata{
+				Labels: map[string]string{utils.ClusterLabelName: "another-cluster"},
+			}
+			errs := cluster.validateManagedServices()
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Field).To(Equal(
+				fmt.Sprintf("spec.managed.services.defaultServicesMetadata.labels[%s]", utils.ClusterLabelName)))
+		})
+
+		It("should reject invalid annotations", func() {
+			cluster.Spec.Managed.Services.DefaultServicesMetadata = &EmbeddedObjectMetadata{
+				Annotations: map[string]string{"invalid key!": "value"},
+			}
+			errs := cluster.validateManagedServices()
+			Expect(errs).ToNot(BeEmpty())
+			Expect(errs[0].Field).To(Equal("spec.managed.services.defaultServicesMetadata.annotations"))
+		})
+	})
 })
 
 var _ = Describe("ServiceTemplate Validation", func() {
@@ -4937,3 +6400,278 @@ var _ = Describe("setDefaultPlugins", func() {
 			ContainElement(PluginConfiguration{Name: "predefined-plugin1", Enabled: ptr.To(true)}))
 	})
 })
+
+var _ = Describe("fencing admission warnings", func() {
+	newCluster := func(instances int, fencedInstances string) *Cluster {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "cluster-example",
+				Annotations: map[string]string{},
+			},
+			Spec: ClusterSpec{
+				Instances: instances,
+			},
+			Status: ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+			},
+		}
+		for i := 1; i <= instances; i++ {
+			cluster.Status.InstanceNames = append(cluster.Status.InstanceNames, fmt.Sprintf("cluster-example-%d", i))
+		}
+		if fencedInstances != "" {
+			cluster.Annotations[utils.FencedInstanceAnnotation] = fencedInstances
+		}
+		return cluster
+	}
+
+	It("warns when the only instance of the cluster is fenced", func() {
+		warnings := newCluster(1, `["*"]`).getFencingAdmissionWarnings(newCluster(1, ""))
+		Expect(warnings).To(HaveLen(1))
+		Expect(warnings[0]).To(SatisfyAll(
+			ContainSubstring("cluster-example-1"),
+			ContainSubstring("only PostgreSQL instance"),
+			ContainSubstring("kubectl cnpg fencing off cluster-example"),
+		))
+	})
+
+	It("warns when the primary of a multi-instance cluster is fenced", func() {
+		warnings := newCluster(3, `["cluster-example-1"]`).getFencingAdmissionWarnings(nil)
+		Expect(warnings).To(HaveLen(1))
+		Expect(warnings[0]).To(SatisfyAll(
+			ContainSubstring(`the primary "cluster-example-1"`),
+			ContainSubstring("kubectl cnpg promote"),
+		))
+	})
+
+	It("names every fenced pod", func() {
+		warnings := newCluster(3, `["*"]`).getFencingAdmissionWarnings(nil)
+		Expect(warnings).To(HaveLen(1))
+		Expect(warnings[0]).To(ContainSubstring("cluster-example-1, cluster-example-2, cluster-example-3"))
+	})
+
+	It("doesn't warn when only replicas are fenced", func() {
+		Expect(newCluster(3, `["cluster-example-2"]`).getFencingAdmissionWarnings(nil)).To(BeEmpty())
+		Expect(newCluster(3, `["role=replica"]`).getFencingAdmissionWarnings(nil)).To(BeEmpty())
+	})
+
+	It("doesn't warn when the fencing is unchanged", func() {
+		Expect(newCluster(1, `["*"]`).getFencingAdmissionWarnings(newCluster(1, `["*"]`))).To(BeEmpty())
+	})
+
+	It("doesn't warn again when the primary was already fenced", func() {
+		Expect(newCluster(3, `["cluster-example-1","cluster-example-2"]`).
+			getFencingAdmissionWarnings(newCluster(3, `["cluster-example-1"]`))).To(BeEmpty())
+	})
+
+	It("warns when fencing every instance of a cluster without a primary", func() {
+		cluster := newCluster(1, `["*"]`)
+		cluster.Status = ClusterStatus{}
+		Expect(cluster.getFencingAdmissionWarnings(nil)).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("locale change admission warnings", func() {
+	newCluster := func(locale string) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Encoding: "UTF8",
+						Locale:   locale,
+					},
+				},
+			},
+		}
+	}
+
+	It("doesn't warn when the locale is unchanged", func() {
+		Expect(newCluster("it_IT.UTF-8").getLocaleChangeAdmissionWarnings(newCluster("it_IT.UTF-8"))).
+			To(BeEmpty())
+	})
+
+	It("warns when the locale is changed", func() {
+		Expect(newCluster("en_US.UTF-8").getLocaleChangeAdmissionWarnings(newCluster("it_IT.UTF-8"))).
+			To(HaveLen(1))
+	})
+
+	It("doesn't warn when the cluster isn't bootstrapped with initdb", func() {
+		Expect((&Cluster{}).getLocaleChangeAdmissionWarnings(newCluster("it_IT.UTF-8"))).To(BeEmpty())
+	})
+})
+
+var _ = Describe("failover topology validation", func() {
+	It("accepts an empty configuration", func() {
+		cluster := &Cluster{Spec: ClusterSpec{FailoverTopology: &FailoverTopologyConfiguration{}}}
+		Expect(cluster.validateFailoverTopology()).To(BeEmpty())
+		Expect(cluster.GetFailoverTopologyKey()).To(Equal(DefaultFailoverTopologyKey))
+	})
+
+	It("accepts a valid label name", func() {
+		cluster := &Cluster{Spec: ClusterSpec{FailoverTopology: &FailoverTopologyConfiguration{
+			TopologyKey: "example.com/rack",
+		}}}
+		Expect(cluster.validateFailoverTopology()).To(BeEmpty())
+	})
+
+	It("complains about an invalid label name", func() {
+		cluster := &Cluster{Spec: ClusterSpec{FailoverTopology: &FailoverTopologyConfiguration{
+			TopologyKey: "not a label",
+		}}}
+		Expect(cluster.validateFailoverTopology()).ToNot(BeEmpty())
+	})
+})
+
+var _ = Describe("primary preference validation", func() {
+	newCluster := func(windows ...SwitchbackWindow) *Cluster {
+		return &Cluster{Spec: ClusterSpec{PrimaryPreference: &PrimaryPreferenceConfiguration{
+			NodeName: "node-1",
+			AutoSwitchback: &AutoSwitchbackConfiguration{
+				MaintenanceWindows: windows,
+			},
+		}}}
+	}
+
+	It("accepts a cluster without a primary preference", func() {
+		Expect((&Cluster{}).validatePrimaryPreference()).To(BeEmpty())
+	})
+
+	It("accepts valid maintenance windows", func() {
+		cluster := newCluster(SwitchbackWindow{
+			Schedule: "0 0 2 * * *",
+			Duration: metav1.Duration{Duration: time.Hour},
+		})
+		Expect(cluster.validatePrimaryPreference()).To(BeEmpty())
+	})
+
+	It("complains about an invalid schedule", func() {
+		cluster := newCluster(SwitchbackWindow{
+			Schedule: "every night",
+			Duration: metav1.Duration{Duration: time.Hour},
+		})
+		Expect(cluster.validatePrimaryPreference()).To(HaveLen(1))
+	})
+
+	It("complains about an empty maintenance window", func() {
+		cluster := newCluster(SwitchbackWindow{
+			Schedule: "0 0 2 * * *",
+		})
+		Expect(cluster.validatePrimaryPreference()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("validateIntegrityCheck", func() {
+	It("accepts a cluster without integrity checks", func() {
+		Expect((&Cluster{}).validateIntegrityCheck()).To(BeEmpty())
+	})
+
+	It("accepts a valid schedule", func() {
+		cluster := &Cluster{Spec: ClusterSpec{Managed: &ManagedConfiguration{
+			IntegrityCheck: &IntegrityCheckConfiguration{Schedule: "0 0 3 * * 0"},
+		}}}
+		Expect(cluster.validateIntegrityCheck()).To(BeEmpty())
+	})
+
+	It("rejects an invalid schedule", func() {
+		cluster := &Cluster{Spec: ClusterSpec{Managed: &ManagedConfiguration{
+			IntegrityCheck: &IntegrityCheckConfiguration{Schedule: "every sunday"},
+		}}}
+		Expect(cluster.validateIntegrityCheck()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("validateRepack", func() {
+	repackCluster := func(configuration *RepackConfiguration) *Cluster {
+		return &Cluster{Spec: ClusterSpec{Managed: &ManagedConfiguration{
+			Maintenance: &MaintenanceConfiguration{Repack: configuration},
+		}}}
+	}
+
+	It("accepts a cluster without pg_repack", func() {
+		Expect((&Cluster{}).validateRepack()).To(BeEmpty())
+	})
+
+	It("accepts a valid configuration", func() {
+		cluster := repackCluster(&RepackConfiguration{
+			Schedule:          "0 0 2 * * 6",
+			Tables:            []RepackTable{{Database: "app", Table: "events"}},
+			MaxReplicationLag: "512Mi",
+		})
+		Expect(cluster.validateRepack()).To(BeEmpty())
+	})
+
+	It("rejects an invalid schedule and replication lag", func() {
+		cluster := repackCluster(&RepackConfiguration{
+			Schedule:          "every saturday",
+			Tables:            []RepackTable{{Database: "app", Table: "events"}},
+			MaxReplicationLag: "a lot",
+		})
+		Expect(cluster.validateRepack()).To(HaveLen(2))
+	})
+
+	It("rejects duplicate tables", func() {
+		cluster := repackCluster(&RepackConfiguration{
+			Schedule: "0 0 2 * * 6",
+			Tables: []RepackTable{
+				{Database: "app", Table: "events"},
+				{Database: "app", Table: "public.events"},
+				{Database: "app", Table: "events"},
+			},
+		})
+		Expect(cluster.validateRepack()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("minimum PostgreSQL major version", func() {
+	const digest = "sha256:3b6f3fd6b2ab2a4f3a1e0a51fd4b14d0a36c3f3cd9cbd2be4bb9e70b6e2e1b33"
+
+	BeforeEach(func() {
+		previous := configuration.Current.MinPostgresMajor
+		configuration.Current.MinPostgresMajor = 14
+		DeferCleanup(func() {
+			configuration.Current.MinPostgresMajor = previous
+		})
+	})
+
+	It("accepts every version when no minimum is configured", func() {
+		configuration.Current.MinPostgresMajor = 0
+		cluster := &Cluster{Spec: ClusterSpec{ImageName: "postgres:12.18"}}
+		Expect(cluster.validateMinPostgresMajor()).To(BeEmpty())
+		Expect(cluster.getMinPostgresMajorAdmissionWarnings()).To(BeEmpty())
+	})
+
+	It("rejects the versions older than the minimum", func() {
+		cluster := &Cluster{Spec: ClusterSpec{ImageName: "postgres:13.14"}}
+		result := cluster.validateMinPostgresMajor()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.imageName"))
+		Expect(result[0].Detail).To(ContainSubstring("requires at least PostgreSQL 14"))
+	})
+
+	It("accepts the versions matching or exceeding the minimum", func() {
+		for _, imageName := range []string{"postgres:14.11", "postgres:16.2", "postgres:17.0@" + digest} {
+			cluster := &Cluster{Spec: ClusterSpec{ImageName: imageName}}
+			Expect(cluster.validateMinPostgresMajor()).To(BeEmpty(), imageName)
+			Expect(cluster.getMinPostgresMajorAdmissionWarnings()).To(BeEmpty(), imageName)
+		}
+	})
+
+	It("rejects digest-pinned images with an older version tag", func() {
+		cluster := &Cluster{Spec: ClusterSpec{ImageName: "postgres:13.14@" + digest}}
+		Expect(cluster.validateMinPostgresMajor()).To(HaveLen(1))
+	})
+
+	It("rejects image catalogs referencing an older major", func() {
+		cluster := &Cluster{Spec: ClusterSpec{
+			ImageCatalogRef: &ImageCatalogRef{Major: 13},
+		}}
+		result := cluster.validateMinPostgresMajor()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.imageCatalogRef.major"))
+	})
+
+	It("warns without rejecting the cluster when the version can't be detected", func() {
+		cluster := &Cluster{Spec: ClusterSpec{ImageName: "postgres@" + digest}}
+		Expect(cluster.validateMinPostgresMajor()).To(BeEmpty())
+		Expect(cluster.getMinPostgresMajorAdmissionWarnings()).To(HaveLen(1))
+	})
+})
@@ -110,12 +110,14 @@ type DatabaseSpec struct {
 	// +optional
 	IsTemplate *bool `json:"isTemplate,omitempty"`
 
-	// True when connections to this database are allowed
+	// True when connections to this database are allowed. Setting it
+	// to `false` blocks the new connections, without terminating the
+	// existing ones
 	// +optional
 	AllowConnections *bool `json:"allowConnections,omitempty"`
 
-	// Connection limit, -1 means no limit and -2 means the
-	// database is not valid
+	// Connection limit, -1 means no limit
+	// +kubebuilder:validation:Minimum=-1
 	// +optional
 	ConnectionLimit *int `json:"connectionLimit,omitempty"`
 
@@ -128,6 +130,47 @@ type DatabaseSpec struct {
 	// +kubebuilder:default:=retain
 	// +optional
 	ReclaimPolicy DatabaseReclaimPolicy `json:"databaseReclaimPolicy,omitempty"`
+
+	// When true, the operator creates a Secret named
+	// `<cluster>-<database>-app` with the parameters needed to connect
+	// to this database as its owner. The password is taken from the
+	// application user secret or from the password secret of the managed
+	// role owning the database
+	// +optional
+	ConnectionSecret bool `json:"connectionSecret,omitempty"`
+
+	// The list of extensions to be managed in the database
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Extensions []ExtensionSpec `json:"extensions,omitempty"`
+}
+
+// ExtensionSpec configures an extension in a database
+type ExtensionSpec struct {
+	// Name of the extension
+	Name string `json:"name"`
+
+	// Ensure the extension is `present` or `absent` - defaults to "present".
+	// An extension removed from the list is left in the database, and is
+	// only dropped when marked as `absent`
+	// +kubebuilder:default:="present"
+	// +kubebuilder:validation:Enum=present;absent
+	// +optional
+	Ensure EnsureOption `json:"ensure,omitempty"`
+
+	// The version of the extension. When set, the extension is created
+	// with this version, and updated to it if a different one is
+	// installed. When empty, the default version is installed and the
+	// extension is never updated
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// The schema where the extension objects are created. When empty,
+	// the current default schema is used, and the extension is never
+	// moved
+	// +optional
+	Schema string `json:"schema,omitempty"`
 }
 
 // DatabaseStatus defines the observed state of Database
@@ -144,6 +187,23 @@ type DatabaseStatus struct {
 	// Message is the reconciliation output message
 	// +optional
 	Message string `json:"message,omitempty"`
+
+	// Extensions is the status of the managed extensions
+	// +optional
+	Extensions []ExtensionStatus `json:"extensions,omitempty"`
+}
+
+// ExtensionStatus is the status of a managed extension
+type ExtensionStatus struct {
+	// Name of the extension
+	Name string `json:"name"`
+
+	// Applied is true if the extension was reconciled correctly
+	Applied bool `json:"applied"`
+
+	// Message is the reconciliation error of the extension, if any
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // +genclient
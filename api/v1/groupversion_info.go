@@ -51,6 +51,9 @@ var (
 	// PoolerKind is the kind name of Poolers
 	PoolerKind = "Pooler"
 
+	// DatabaseKind is the kind name of Databases
+	DatabaseKind = "Database"
+
 	// ImageCatalogKind is the kind name of namespaced image catalogs
 	ImageCatalogKind = "ImageCatalog"
 
@@ -16,11 +16,42 @@ limitations under the License.
 
 package v1
 
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
 // IsPaused returns whether all database should be paused or not.
 func (in PgBouncerSpec) IsPaused() bool {
 	return in.Paused != nil && *in.Paused
 }
 
+// IsDrainEnabled returns whether the connections should be drained
+// before terminating a PgBouncer pod
+func (in PgBouncerSpec) IsDrainEnabled() bool {
+	return in.Drain != nil && in.Drain.Enabled
+}
+
+// GetDrainTimeoutSeconds returns the maximum number of seconds to wait
+// for the connections to be drained
+func (in PgBouncerSpec) GetDrainTimeoutSeconds() int32 {
+	if in.Drain != nil && in.Drain.TimeoutSeconds > 0 {
+		return in.Drain.TimeoutSeconds
+	}
+
+	return DefaultPgBouncerDrainTimeoutSeconds
+}
+
+// GetTerminationGracePeriodSeconds returns the termination grace period
+// of the PgBouncer pods, as specified in the pod template or the
+// Kubernetes default one
+func (in *Pooler) GetTerminationGracePeriodSeconds() int64 {
+	if in.Spec.Template != nil && in.Spec.Template.Spec.TerminationGracePeriodSeconds != nil {
+		return *in.Spec.Template.Spec.TerminationGracePeriodSeconds
+	}
+
+	return corev1.DefaultTerminationGracePeriodSeconds
+}
+
 // GetAuthQuerySecretName returns the specified AuthQuerySecret name for PgBouncer
 // if provided or the default name otherwise.
 func (in *Pooler) GetAuthQuerySecretName() string {
@@ -34,4 +34,31 @@ var _ = Describe("Pooler type tests", func() {
 		}
 		Expect(pgbouncer.IsPaused()).To(BeTrue())
 	})
+
+	It("pgbouncer pools are not drained by default", func() {
+		pgbouncer := PgBouncerSpec{}
+		Expect(pgbouncer.IsDrainEnabled()).To(BeFalse())
+		Expect(pgbouncer.GetDrainTimeoutSeconds()).To(BeEquivalentTo(DefaultPgBouncerDrainTimeoutSeconds))
+	})
+
+	It("pgbouncer pools can be drained", func() {
+		pgbouncer := PgBouncerSpec{
+			Drain: &PgBouncerDrainConfiguration{
+				Enabled:        true,
+				TimeoutSeconds: 50,
+			},
+		}
+		Expect(pgbouncer.IsDrainEnabled()).To(BeTrue())
+		Expect(pgbouncer.GetDrainTimeoutSeconds()).To(BeEquivalentTo(50))
+	})
+
+	It("uses the termination grace period of the pod template", func() {
+		pooler := Pooler{}
+		Expect(pooler.GetTerminationGracePeriodSeconds()).To(BeEquivalentTo(30))
+
+		gracePeriod := int64(60)
+		pooler.Spec.Template = &PodTemplateSpec{}
+		pooler.Spec.Template.Spec.TerminationGracePeriodSeconds = &gracePeriod
+		Expect(pooler.GetTerminationGracePeriodSeconds()).To(BeEquivalentTo(60))
+	})
 })
@@ -37,6 +37,10 @@ const (
 
 	// DefaultPgBouncerPoolerAuthQuery is the default auth_query for PgBouncer
 	DefaultPgBouncerPoolerAuthQuery = "SELECT usename, passwd FROM public.user_search($1)"
+
+	// DefaultPgBouncerDrainTimeoutSeconds is the default number of seconds
+	// PgBouncer waits for the connections to be drained
+	DefaultPgBouncerDrainTimeoutSeconds = 20
 )
 
 // PgBouncerPoolMode is the mode of PgBouncer
@@ -175,6 +179,14 @@ type PgBouncerSpec struct {
 	// +optional
 	PgHBA []string `json:"pg_hba,omitempty"`
 
+	// The pool settings of specific databases, overriding the global ones.
+	// They are rendered into the `[databases]` section of the PgBouncer
+	// configuration, before the fallback entry matching every other
+	// database. Changing them reloads the PgBouncer configuration without
+	// restarting the pods.
+	// +optional
+	Databases []PgBouncerDatabaseConfiguration `json:"databases,omitempty"`
+
 	// When set to `true`, PgBouncer will disconnect from the PostgreSQL
 	// server, first waiting for all queries to complete, and pause all new
 	// client connections until this value is set to `false` (default). Internally,
@@ -182,6 +194,63 @@ type PgBouncerSpec struct {
 	// +kubebuilder:default:=false
 	// +optional
 	Paused *bool `json:"paused,omitempty"`
+
+	// Configures the draining of the connections performed before
+	// a PgBouncer pod is terminated, i.e. during a rollout
+	// +optional
+	Drain *PgBouncerDrainConfiguration `json:"drain,omitempty"`
+}
+
+// PgBouncerDatabaseConfiguration contains the pool settings of a database
+type PgBouncerDatabaseConfiguration struct {
+	// The name of the database. It must be a valid PostgreSQL database
+	// name, even if the existence of the database is only checked by
+	// PgBouncer when a client connects to it.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// The pool mode of the database, overriding the global one
+	// +optional
+	PoolMode PgBouncerPoolMode `json:"poolMode,omitempty"`
+
+	// The maximum size of the pools of the database, overriding the
+	// `default_pool_size` parameter
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	PoolSize *int32 `json:"poolSize,omitempty"`
+
+	// The minimum size of the pools of the database, overriding the
+	// `min_pool_size` parameter
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinPoolSize *int32 `json:"minPoolSize,omitempty"`
+
+	// The maximum number of server connections to the database, overriding
+	// the `max_db_connections` parameter
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxDBConnections *int32 `json:"maxDBConnections,omitempty"`
+}
+
+// PgBouncerDrainConfiguration configures how PgBouncer drains the
+// connections before its pod is terminated
+type PgBouncerDrainConfiguration struct {
+	// When set to `true`, a `preStop` hook issues the `PAUSE` and
+	// `WAIT_CLOSE` commands on the PgBouncer administrative console,
+	// letting the running transactions complete before the pod is
+	// terminated. Default: `false`.
+	// +kubebuilder:default:=false
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// The maximum number of seconds to wait for the connections to be
+	// drained. It must be lower than the `terminationGracePeriodSeconds`
+	// of the pod template. Default: `20`.
+	// +kubebuilder:default:=20
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
 }
 
 // PoolerStatus defines the observed state of Pooler
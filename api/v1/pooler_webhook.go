@@ -18,6 +18,7 @@ package v1
 
 import (
 	"fmt"
+	"regexp"
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
 	"github.com/cloudnative-pg/machinery/pkg/stringset"
@@ -30,10 +31,18 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
+// pgbouncerAdminDatabase is the name of the virtual database of the
+// PgBouncer administrative console
+const pgbouncerAdminDatabase = "pgbouncer"
+
 var (
 	// poolerLog is for logging in this package.
 	poolerLog = log.WithName("pooler-resource").WithValues("version", "v1")
 
+	// pgbouncerDatabaseNameRegex matches the database names that can be
+	// listed in the `[databases]` section of the PgBouncer configuration
+	pgbouncerDatabaseNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_$-]*$`)
+
 	// AllowedPgbouncerGenericConfigurationParameters is the list of allowed parameters for PgBouncer
 	AllowedPgbouncerGenericConfigurationParameters = stringset.From([]string{
 		"application_name_add_host",
@@ -166,9 +175,63 @@ func (r *Pooler) validatePgBouncer() field.ErrorList {
 		result = append(result, r.validatePgbouncerGenericParameters()...)
 	}
 
+	if r.Spec.PgBouncer != nil && len(r.Spec.PgBouncer.Databases) > 0 {
+		result = append(result, r.validatePgBouncerDatabases()...)
+	}
+
+	if r.Spec.PgBouncer != nil && r.Spec.PgBouncer.IsDrainEnabled() {
+		result = append(result, r.validatePgBouncerDrain()...)
+	}
+
 	return result
 }
 
+// validatePgBouncerDatabases checks that the databases with specific pool
+// settings have a plausible name and are listed only once. Whether the
+// databases exist can only be checked by PgBouncer at connection time
+func (r *Pooler) validatePgBouncerDatabases() field.ErrorList {
+	var result field.ErrorList
+
+	basePath := field.NewPath("spec", "pgbouncer", "databases")
+	seen := stringset.New()
+	for idx, database := range r.Spec.PgBouncer.Databases {
+		namePath := basePath.Index(idx).Child("name")
+		switch {
+		case len(database.Name) == 0 || len(database.Name) > 63 ||
+			!pgbouncerDatabaseNameRegex.MatchString(database.Name):
+			result = append(result, field.Invalid(namePath, database.Name,
+				"must be a valid database name, made of up to 63 letters, digits, "+
+					"underscores, dollar signs and hyphens, and not starting with a digit"))
+		case database.Name == pgbouncerAdminDatabase:
+			result = append(result, field.Invalid(namePath, database.Name,
+				"is reserved to the PgBouncer administrative console"))
+		case seen.Has(database.Name):
+			result = append(result, field.Duplicate(namePath, database.Name))
+		}
+		seen.Put(database.Name)
+	}
+
+	return result
+}
+
+// validatePgBouncerDrain checks that the connections can be drained
+// within the termination grace period of the pod
+func (r *Pooler) validatePgBouncerDrain() field.ErrorList {
+	timeout := r.Spec.PgBouncer.GetDrainTimeoutSeconds()
+	gracePeriod := r.GetTerminationGracePeriodSeconds()
+	if int64(timeout) < gracePeriod {
+		return nil
+	}
+
+	return field.ErrorList{
+		field.Invalid(
+			field.NewPath("spec", "pgbouncer", "drain", "timeoutSeconds"),
+			timeout,
+			fmt.Sprintf("must be lower than the terminationGracePeriodSeconds of the pod template (%d)",
+				gracePeriod)),
+	}
+}
+
 func (r *Pooler) validateCluster() field.ErrorList {
 	var result field.ErrorList
 	if r.Spec.Cluster.Name == "" {
@@ -18,6 +18,7 @@ package v1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -128,4 +129,68 @@ var _ = Describe("Pooler validation", func() {
 		}
 		Expect(pooler.validatePgbouncerGenericParameters()).To(BeEmpty())
 	})
+
+	It("allows draining the connections within the termination grace period", func() {
+		pooler := Pooler{
+			Spec: PoolerSpec{
+				PgBouncer: &PgBouncerSpec{
+					Drain: &PgBouncerDrainConfiguration{Enabled: true},
+				},
+			},
+		}
+		Expect(pooler.validatePgBouncer()).To(BeEmpty())
+	})
+
+	It("doesn't allow a drain timeout exceeding the termination grace period", func() {
+		gracePeriod := int64(10)
+		pooler := Pooler{
+			Spec: PoolerSpec{
+				PgBouncer: &PgBouncerSpec{
+					Drain: &PgBouncerDrainConfiguration{Enabled: true, TimeoutSeconds: 10},
+				},
+				Template: &PodTemplateSpec{},
+			},
+		}
+		pooler.Spec.Template.Spec.TerminationGracePeriodSeconds = &gracePeriod
+		Expect(pooler.validatePgBouncer()).To(HaveLen(1))
+
+		pooler.Spec.PgBouncer.Drain.Enabled = false
+		Expect(pooler.validatePgBouncer()).To(BeEmpty())
+	})
+
+	It("allows databases with specific pool settings", func() {
+		pooler := Pooler{
+			Spec: PoolerSpec{
+				PgBouncer: &PgBouncerSpec{
+					Databases: []PgBouncerDatabaseConfiguration{
+						{Name: "oltp", PoolMode: PgBouncerPoolModeTransaction},
+						{Name: "analytics_2024", PoolMode: PgBouncerPoolModeSession},
+					},
+				},
+			},
+		}
+		Expect(pooler.validatePgBouncer()).To(BeEmpty())
+	})
+
+	It("doesn't allow invalid, reserved or duplicate database names", func() {
+		pooler := Pooler{
+			Spec: PoolerSpec{
+				PgBouncer: &PgBouncerSpec{
+					Databases: []PgBouncerDatabaseConfiguration{
+						{Name: "app"},
+						{Name: "1app"},
+						{Name: "app = host=evil"},
+						{Name: "pgbouncer"},
+						{Name: "app"},
+					},
+				},
+			},
+		}
+		errs := pooler.validatePgBouncer()
+		Expect(errs).To(HaveLen(4))
+		Expect(errs[0].Field).To(Equal("spec.pgbouncer.databases[1].name"))
+		Expect(errs[1].Field).To(Equal("spec.pgbouncer.databases[2].name"))
+		Expect(errs[2].Field).To(Equal("spec.pgbouncer.databases[3].name"))
+		Expect(errs[3].Type).To(Equal(field.ErrorTypeDuplicate))
+	})
 })
@@ -17,6 +17,11 @@ limitations under the License.
 package v1
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
@@ -56,6 +61,49 @@ func (scheduledBackup *ScheduledBackup) GetSchedule() string {
 	return scheduledBackup.Spec.Schedule
 }
 
+// GetScheduleLocation gets the time zone used to compute the
+// schedule of this scheduled backup
+func (scheduledBackup *ScheduledBackup) GetScheduleLocation() (*time.Location, error) {
+	if scheduledBackup.Spec.TimeZone == "" {
+		return time.Local, nil
+	}
+
+	// "Local" would make the schedule depend on where the operator runs
+	if strings.EqualFold(scheduledBackup.Spec.TimeZone, "Local") {
+		return nil, fmt.Errorf("the time zone must be defined in the IANA time zone database")
+	}
+
+	return time.LoadLocation(scheduledBackup.Spec.TimeZone)
+}
+
+// ParseSchedule parses the cron-like schedule of this scheduled backup,
+// whose activation times are computed in its time zone
+func (scheduledBackup *ScheduledBackup) ParseSchedule() (cron.Schedule, error) {
+	schedule, err := cron.Parse(scheduledBackup.GetSchedule())
+	if err != nil {
+		return nil, err
+	}
+
+	location, err := scheduledBackup.GetScheduleLocation()
+	if err != nil {
+		return nil, err
+	}
+
+	return timeZoneSchedule{Schedule: schedule, location: location}, nil
+}
+
+// timeZoneSchedule is a cron schedule whose activation
+// times are computed in a given time zone
+type timeZoneSchedule struct {
+	cron.Schedule
+	location *time.Location
+}
+
+// Next implements the cron.Schedule interface
+func (schedule timeZoneSchedule) Next(t time.Time) time.Time {
+	return schedule.Schedule.Next(t.In(schedule.location))
+}
+
 // GetStatus gets the status that the caller may update
 func (scheduledBackup *ScheduledBackup) GetStatus() *ScheduledBackupStatus {
 	return &scheduledBackup.Status
@@ -17,6 +17,8 @@ limitations under the License.
 package v1
 
 import (
+	"time"
+
 	"k8s.io/utils/ptr"
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
@@ -94,4 +96,37 @@ var _ = Describe("Scheduled backup", func() {
 		Expect(result).To(HaveLen(1))
 		Expect(result[0].Field).To(Equal("spec.onlineConfiguration"))
 	})
+
+	It("computes the next run in the time zone across a DST transition", func() {
+		scheduledBackup := &ScheduledBackup{
+			Spec: ScheduledBackupSpec{
+				Schedule: "0 0 2 * * *",
+				TimeZone: "America/New_York",
+			},
+		}
+		schedule, err := scheduledBackup.ParseSchedule()
+		Expect(err).ToNot(HaveOccurred())
+
+		// The daylight saving time ends on 2024-11-03 in New York
+		next := schedule.Next(time.Date(2024, 11, 2, 0, 0, 0, 0, time.UTC))
+		Expect(next.UTC()).To(Equal(time.Date(2024, 11, 2, 6, 0, 0, 0, time.UTC)))
+
+		next = schedule.Next(next)
+		Expect(next.UTC()).To(Equal(time.Date(2024, 11, 3, 7, 0, 0, 0, time.UTC)))
+		Expect(next.Hour()).To(Equal(2))
+	})
+
+	It("complains if the time zone is not valid", func() {
+		for _, timeZone := range []string{"Mars/Olympus_Mons", "Local"} {
+			scheduledBackup := &ScheduledBackup{
+				Spec: ScheduledBackupSpec{
+					Schedule: "0 0 2 * * *",
+					TimeZone: timeZone,
+				},
+			}
+			result := scheduledBackup.validate()
+			Expect(result).To(HaveLen(1))
+			Expect(result[0].Field).To(Equal("spec.timeZone"))
+		}
+	})
 })
@@ -35,6 +35,13 @@ type ScheduledBackupSpec struct {
 	// see https://pkg.go.dev/github.com/robfig/cron#hdr-CRON_Expression_Format
 	Schedule string `json:"schedule"`
 
+	// The name of the time zone used to compute the schedule, as defined
+	// in the IANA time zone database (i.e. `America/New_York`). The
+	// schedule follows the daylight saving time transitions of the zone.
+	// Defaults to the time zone of the operator, which is usually UTC
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
 	// The cluster to backup
 	Cluster LocalObjectReference `json:"cluster"`
 
@@ -49,11 +56,12 @@ type ScheduledBackupSpec struct {
 
 	// The policy to decide which instance should perform this backup. If empty,
 	// it defaults to `cluster.spec.backup.target`.
-	// Available options are empty string, `primary` and `prefer-standby`.
-	// `primary` to have backups run always on primary instances,
+	// Available options are empty string, `primary`, `prefer-standby` and
+	// `standby`. `primary` to have backups run always on primary instances,
 	// `prefer-standby` to have backups run preferably on the most updated
-	// standby, if available.
-	// +kubebuilder:validation:Enum=primary;prefer-standby
+	// standby, if available, `standby` to have backups run only on the most
+	// updated standby, deferring them while no standby is available.
+	// +kubebuilder:validation:Enum=primary;prefer-standby;standby
 	// +optional
 	Target BackupTarget `json:"target,omitempty"`
 
@@ -91,6 +91,12 @@ func (r *ScheduledBackup) validate() field.ErrorList {
 				field.NewPath("spec", "schedule"),
 				r.Spec.Schedule, err.Error()))
 	}
+	if _, err := r.GetScheduleLocation(); err != nil {
+		result = append(result,
+			field.Invalid(
+				field.NewPath("spec", "timeZone"),
+				r.Spec.TimeZone, err.Error()))
+	}
 	if r.Spec.Method == BackupMethodVolumeSnapshot && !utils.HaveVolumeSnapshot() {
 		result = append(result, field.Invalid(
 			field.NewPath("spec", "method"),
@@ -79,6 +79,51 @@ func (in *AffinityConfiguration) DeepCopy() *AffinityConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArchiveVerificationConfiguration) DeepCopyInto(out *ArchiveVerificationConfiguration) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArchiveVerificationConfiguration.
+func (in *ArchiveVerificationConfiguration) DeepCopy() *ArchiveVerificationConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ArchiveVerificationConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoSwitchbackConfiguration) DeepCopyInto(out *AutoSwitchbackConfiguration) {
+	*out = *in
+	if in.Cooldown != nil {
+		in, out := &in.Cooldown, &out.Cooldown
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]SwitchbackWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoSwitchbackConfiguration.
+func (in *AutoSwitchbackConfiguration) DeepCopy() *AutoSwitchbackConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoSwitchbackConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AvailableArchitecture) DeepCopyInto(out *AvailableArchitecture) {
 	*out = *in
@@ -134,6 +179,21 @@ func (in *BackupConfiguration) DeepCopyInto(out *BackupConfiguration) {
 		*out = new(pkgapi.BarmanObjectStoreConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.WALObjectStore != nil {
+		in, out := &in.WALObjectStore, &out.WALObjectStore
+		*out = new(pkgapi.BarmanObjectStoreConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ArchiveVerification != nil {
+		in, out := &in.ArchiveVerification, &out.ArchiveVerification
+		*out = new(ArchiveVerificationConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WALCompressionLevel != nil {
+		in, out := &in.WALCompressionLevel, &out.WALCompressionLevel
+		*out = new(int)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupConfiguration.
@@ -332,6 +392,21 @@ func (in *BackupStatus) DeepCopyInto(out *BackupStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.GlobalsExport != nil {
+		in, out := &in.GlobalsExport, &out.GlobalsExport
+		*out = new(GlobalsExportStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackupProgress != nil {
+		in, out := &in.BackupProgress, &out.BackupProgress
+		*out = new(int32)
+		**out = **in
+	}
+	if in.BytesTransferred != nil {
+		in, out := &in.BytesTransferred, &out.BytesTransferred
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupStatus.
@@ -377,6 +452,11 @@ func (in *BootstrapConfiguration) DeepCopy() *BootstrapConfiguration {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BootstrapInitDB) DeepCopyInto(out *BootstrapInitDB) {
 	*out = *in
+	if in.ConnectionLimit != nil {
+		in, out := &in.ConnectionLimit, &out.ConnectionLimit
+		*out = new(int)
+		**out = **in
+	}
 	if in.Secret != nil {
 		in, out := &in.Secret, &out.Secret
 		*out = new(api.LocalObjectReference)
@@ -459,6 +539,21 @@ func (in *BootstrapPgBaseBackup) DeepCopy() *BootstrapPgBaseBackup {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapReadinessStatus) DeepCopyInto(out *BootstrapReadinessStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapReadinessStatus.
+func (in *BootstrapReadinessStatus) DeepCopy() *BootstrapReadinessStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapReadinessStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BootstrapRecovery) DeepCopyInto(out *BootstrapRecovery) {
 	*out = *in
@@ -482,6 +577,11 @@ func (in *BootstrapRecovery) DeepCopyInto(out *BootstrapRecovery) {
 		*out = new(api.LocalObjectReference)
 		**out = **in
 	}
+	if in.ValidationSQL != nil {
+		in, out := &in.ValidationSQL, &out.ValidationSQL
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapRecovery.
@@ -552,6 +652,21 @@ func (in *CertificatesStatus) DeepCopy() *CertificatesStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientCertificateMapping) DeepCopyInto(out *ClientCertificateMapping) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientCertificateMapping.
+func (in *ClientCertificateMapping) DeepCopy() *ClientCertificateMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientCertificateMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Cluster) DeepCopyInto(out *Cluster) {
 	*out = *in
@@ -703,6 +818,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(ReplicationSlotsConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Replication != nil {
+		in, out := &in.Replication, &out.Replication
+		*out = new(ReplicationConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Bootstrap != nil {
 		in, out := &in.Bootstrap, &out.Bootstrap
 		*out = new(BootstrapConfiguration)
@@ -744,6 +864,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(StorageConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DiskPressure != nil {
+		in, out := &in.DiskPressure, &out.DiskPressure
+		*out = new(DiskPressureConfiguration)
+		**out = **in
+	}
 	if in.EphemeralVolumeSource != nil {
 		in, out := &in.EphemeralVolumeSource, &out.EphemeralVolumeSource
 		*out = new(corev1.EphemeralVolumeSource)
@@ -754,6 +879,36 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.SwitchoverMaxLagBytes != nil {
+		in, out := &in.SwitchoverMaxLagBytes, &out.SwitchoverMaxLagBytes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SwitchoverOnShutdown != nil {
+		in, out := &in.SwitchoverOnShutdown, &out.SwitchoverOnShutdown
+		*out = new(SwitchoverOnShutdownConfiguration)
+		**out = **in
+	}
+	if in.PromotableReplicaMaxLagBytes != nil {
+		in, out := &in.PromotableReplicaMaxLagBytes, &out.PromotableReplicaMaxLagBytes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.FailoverTopology != nil {
+		in, out := &in.FailoverTopology, &out.FailoverTopology
+		*out = new(FailoverTopologyConfiguration)
+		**out = **in
+	}
+	if in.PrimaryPreference != nil {
+		in, out := &in.PrimaryPreference, &out.PrimaryPreference
+		*out = new(PrimaryPreferenceConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CleanupDivergedWALOnRejoin != nil {
+		in, out := &in.CleanupDivergedWALOnRejoin, &out.CleanupDivergedWALOnRejoin
+		*out = new(bool)
+		**out = **in
+	}
 	if in.LivenessProbeTimeout != nil {
 		in, out := &in.LivenessProbeTimeout, &out.LivenessProbeTimeout
 		*out = new(int32)
@@ -836,6 +991,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.JobRetention != nil {
+		in, out := &in.JobRetention, &out.JobRetention
+		*out = new(JobRetentionConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Plugins != nil {
 		in, out := &in.Plugins, &out.Plugins
 		*out = make(PluginConfigurationList, len(*in))
@@ -930,6 +1090,11 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.LastFailoverDecision != nil {
+		in, out := &in.LastFailoverDecision, &out.LastFailoverDecision
+		*out = new(FailoverDecision)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.PoolerIntegrations != nil {
 		in, out := &in.PoolerIntegrations, &out.PoolerIntegrations
 		*out = new(PoolerIntegrations)
@@ -952,6 +1117,21 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.FencedInstances != nil {
+		in, out := &in.FencedInstances, &out.FencedInstances
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodIPs != nil {
+		in, out := &in.PodIPs, &out.PodIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PGDataImageInfo != nil {
+		in, out := &in.PGDataImageInfo, &out.PGDataImageInfo
+		*out = new(ImageInfo)
+		**out = **in
+	}
 	if in.PluginStatus != nil {
 		in, out := &in.PluginStatus, &out.PluginStatus
 		*out = make([]PluginStatus, len(*in))
@@ -960,6 +1140,36 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		}
 	}
 	out.SwitchReplicaClusterStatus = in.SwitchReplicaClusterStatus
+	if in.LastRejoinWALCleanup != nil {
+		in, out := &in.LastRejoinWALCleanup, &out.LastRejoinWALCleanup
+		*out = new(RejoinWALCleanupStatus)
+		**out = **in
+	}
+	if in.IntegrityCheck != nil {
+		in, out := &in.IntegrityCheck, &out.IntegrityCheck
+		*out = new(IntegrityCheckStatus)
+		**out = **in
+	}
+	if in.Repack != nil {
+		in, out := &in.Repack, &out.Repack
+		*out = new(RepackStatus)
+		**out = **in
+	}
+	if in.SynchronousStandbyRotation != nil {
+		in, out := &in.SynchronousStandbyRotation, &out.SynchronousStandbyRotation
+		*out = new(SynchronousStandbyRotationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BootstrapReadiness != nil {
+		in, out := &in.BootstrapReadiness, &out.BootstrapReadiness
+		*out = new(BootstrapReadinessStatus)
+		**out = **in
+	}
+	if in.SourceReplicationSlot != nil {
+		in, out := &in.SourceReplicationSlot, &out.SourceReplicationSlot
+		*out = new(SourceReplicationSlotStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatus.
@@ -982,6 +1192,13 @@ func (in *ConfigMapResourceVersion) DeepCopyInto(out *ConfigMapResourceVersion)
 			(*out)[key] = val
 		}
 	}
+	if in.IncludedConfiguration != nil {
+		in, out := &in.IncludedConfiguration, &out.IncludedConfiguration
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapResourceVersion.
@@ -1115,6 +1332,11 @@ func (in *DatabaseSpec) DeepCopyInto(out *DatabaseSpec) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
+		*out = make([]ExtensionSpec, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseSpec.
@@ -1135,6 +1357,11 @@ func (in *DatabaseStatus) DeepCopyInto(out *DatabaseStatus) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
+		*out = make([]ExtensionStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseStatus.
@@ -1147,6 +1374,21 @@ func (in *DatabaseStatus) DeepCopy() *DatabaseStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskPressureConfiguration) DeepCopyInto(out *DiskPressureConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskPressureConfiguration.
+func (in *DiskPressureConfiguration) DeepCopy() *DiskPressureConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskPressureConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EmbeddedObjectMetadata) DeepCopyInto(out *EmbeddedObjectMetadata) {
 	*out = *in
@@ -1201,6 +1443,36 @@ func (in *EphemeralVolumesSizeLimitConfiguration) DeepCopy() *EphemeralVolumesSi
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtensionSpec) DeepCopyInto(out *ExtensionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtensionSpec.
+func (in *ExtensionSpec) DeepCopy() *ExtensionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtensionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtensionStatus) DeepCopyInto(out *ExtensionStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtensionStatus.
+func (in *ExtensionStatus) DeepCopy() *ExtensionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtensionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalCluster) DeepCopyInto(out *ExternalCluster) {
 	*out = *in
@@ -1236,6 +1508,11 @@ func (in *ExternalCluster) DeepCopyInto(out *ExternalCluster) {
 		*out = new(pkgapi.BarmanObjectStoreConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.WALObjectStore != nil {
+		in, out := &in.WALObjectStore, &out.WALObjectStore
+		*out = new(pkgapi.BarmanObjectStoreConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.PluginConfiguration != nil {
 		in, out := &in.PluginConfiguration, &out.PluginConfiguration
 		*out = new(PluginConfiguration)
@@ -1274,6 +1551,90 @@ func (in ExternalClusterList) DeepCopy() ExternalClusterList {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailoverCandidateStatus) DeepCopyInto(out *FailoverCandidateStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailoverCandidateStatus.
+func (in *FailoverCandidateStatus) DeepCopy() *FailoverCandidateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FailoverCandidateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailoverDecision) DeepCopyInto(out *FailoverDecision) {
+	*out = *in
+	if in.Candidates != nil {
+		in, out := &in.Candidates, &out.Candidates
+		*out = make([]FailoverCandidateStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailoverDecision.
+func (in *FailoverDecision) DeepCopy() *FailoverDecision {
+	if in == nil {
+		return nil
+	}
+	out := new(FailoverDecision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailoverTopologyConfiguration) DeepCopyInto(out *FailoverTopologyConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailoverTopologyConfiguration.
+func (in *FailoverTopologyConfiguration) DeepCopy() *FailoverTopologyConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(FailoverTopologyConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlobalsExportStatus) DeepCopyInto(out *GlobalsExportStatus) {
+	*out = *in
+	if in.ExportedAt != nil {
+		in, out := &in.ExportedAt, &out.ExportedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalsExportStatus.
+func (in *GlobalsExportStatus) DeepCopy() *GlobalsExportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GlobalsExportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HeadlessServiceConfiguration) DeepCopyInto(out *HeadlessServiceConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HeadlessServiceConfiguration.
+func (in *HeadlessServiceConfiguration) DeepCopy() *HeadlessServiceConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(HeadlessServiceConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImageCatalog) DeepCopyInto(out *ImageCatalog) {
 	*out = *in
@@ -1368,6 +1729,21 @@ func (in *ImageCatalogSpec) DeepCopy() *ImageCatalogSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageInfo) DeepCopyInto(out *ImageInfo) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageInfo.
+func (in *ImageInfo) DeepCopy() *ImageInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Import) DeepCopyInto(out *Import) {
 	*out = *in
@@ -1445,57 +1821,107 @@ func (in *InstanceReportedState) DeepCopy() *InstanceReportedState {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LDAPBindAsAuth) DeepCopyInto(out *LDAPBindAsAuth) {
+func (in *IntegrityCheckConfiguration) DeepCopyInto(out *IntegrityCheckConfiguration) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPBindAsAuth.
-func (in *LDAPBindAsAuth) DeepCopy() *LDAPBindAsAuth {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrityCheckConfiguration.
+func (in *IntegrityCheckConfiguration) DeepCopy() *IntegrityCheckConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(LDAPBindAsAuth)
+	out := new(IntegrityCheckConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LDAPBindSearchAuth) DeepCopyInto(out *LDAPBindSearchAuth) {
+func (in *IntegrityCheckStatus) DeepCopyInto(out *IntegrityCheckStatus) {
 	*out = *in
-	if in.BindPassword != nil {
-		in, out := &in.BindPassword, &out.BindPassword
-		*out = new(corev1.SecretKeySelector)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPBindSearchAuth.
-func (in *LDAPBindSearchAuth) DeepCopy() *LDAPBindSearchAuth {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrityCheckStatus.
+func (in *IntegrityCheckStatus) DeepCopy() *IntegrityCheckStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(LDAPBindSearchAuth)
+	out := new(IntegrityCheckStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LDAPConfig) DeepCopyInto(out *LDAPConfig) {
+func (in *JobRetentionConfiguration) DeepCopyInto(out *JobRetentionConfiguration) {
 	*out = *in
-	if in.BindAsAuth != nil {
-		in, out := &in.BindAsAuth, &out.BindAsAuth
-		*out = new(LDAPBindAsAuth)
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(metav1.Duration)
 		**out = **in
 	}
-	if in.BindSearchAuth != nil {
-		in, out := &in.BindSearchAuth, &out.BindSearchAuth
-		*out = new(LDAPBindSearchAuth)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPConfig.
-func (in *LDAPConfig) DeepCopy() *LDAPConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobRetentionConfiguration.
+func (in *JobRetentionConfiguration) DeepCopy() *JobRetentionConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(JobRetentionConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAPBindAsAuth) DeepCopyInto(out *LDAPBindAsAuth) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPBindAsAuth.
+func (in *LDAPBindAsAuth) DeepCopy() *LDAPBindAsAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(LDAPBindAsAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAPBindSearchAuth) DeepCopyInto(out *LDAPBindSearchAuth) {
+	*out = *in
+	if in.BindPassword != nil {
+		in, out := &in.BindPassword, &out.BindPassword
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPBindSearchAuth.
+func (in *LDAPBindSearchAuth) DeepCopy() *LDAPBindSearchAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(LDAPBindSearchAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAPConfig) DeepCopyInto(out *LDAPConfig) {
+	*out = *in
+	if in.BindAsAuth != nil {
+		in, out := &in.BindAsAuth, &out.BindAsAuth
+		*out = new(LDAPBindAsAuth)
+		**out = **in
+	}
+	if in.BindSearchAuth != nil {
+		in, out := &in.BindSearchAuth, &out.BindSearchAuth
+		*out = new(LDAPBindSearchAuth)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPConfig.
+func (in *LDAPConfig) DeepCopy() *LDAPConfig {
 	if in == nil {
 		return nil
 	}
@@ -1504,6 +1930,41 @@ func (in *LDAPConfig) DeepCopy() *LDAPConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceConfiguration) DeepCopyInto(out *MaintenanceConfiguration) {
+	*out = *in
+	if in.Repack != nil {
+		in, out := &in.Repack, &out.Repack
+		*out = new(RepackConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceConfiguration.
+func (in *MaintenanceConfiguration) DeepCopy() *MaintenanceConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MajorUpgradeConfiguration) DeepCopyInto(out *MajorUpgradeConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MajorUpgradeConfiguration.
+func (in *MajorUpgradeConfiguration) DeepCopy() *MajorUpgradeConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(MajorUpgradeConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagedConfiguration) DeepCopyInto(out *ManagedConfiguration) {
 	*out = *in
@@ -1519,6 +1980,16 @@ func (in *ManagedConfiguration) DeepCopyInto(out *ManagedConfiguration) {
 		*out = new(ManagedServices)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.IntegrityCheck != nil {
+		in, out := &in.IntegrityCheck, &out.IntegrityCheck
+		*out = new(IntegrityCheckConfiguration)
+		**out = **in
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = new(MaintenanceConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedConfiguration.
@@ -1573,6 +2044,13 @@ func (in *ManagedRoles) DeepCopyInto(out *ManagedRoles) {
 			(*out)[key] = val
 		}
 	}
+	if in.ConnectionLimits != nil {
+		in, out := &in.ConnectionLimits, &out.ConnectionLimits
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedRoles.
@@ -1616,6 +2094,26 @@ func (in *ManagedServices) DeepCopyInto(out *ManagedServices) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.RO != nil {
+		in, out := &in.RO, &out.RO
+		*out = new(ReadOnlyServiceConfiguration)
+		**out = **in
+	}
+	if in.RW != nil {
+		in, out := &in.RW, &out.RW
+		*out = new(ReadWriteServiceConfiguration)
+		**out = **in
+	}
+	if in.Headless != nil {
+		in, out := &in.Headless, &out.Headless
+		*out = new(HeadlessServiceConfiguration)
+		**out = **in
+	}
+	if in.DefaultServicesMetadata != nil {
+		in, out := &in.DefaultServicesMetadata, &out.DefaultServicesMetadata
+		*out = new(EmbeddedObjectMetadata)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedServices.
@@ -1766,6 +2264,66 @@ func (in *PasswordState) DeepCopy() *PasswordState {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBaseBackupOptions) DeepCopyInto(out *PgBaseBackupOptions) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBaseBackupOptions.
+func (in *PgBaseBackupOptions) DeepCopy() *PgBaseBackupOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBaseBackupOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBouncerDatabaseConfiguration) DeepCopyInto(out *PgBouncerDatabaseConfiguration) {
+	*out = *in
+	if in.PoolSize != nil {
+		in, out := &in.PoolSize, &out.PoolSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MinPoolSize != nil {
+		in, out := &in.MinPoolSize, &out.MinPoolSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxDBConnections != nil {
+		in, out := &in.MaxDBConnections, &out.MaxDBConnections
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerDatabaseConfiguration.
+func (in *PgBouncerDatabaseConfiguration) DeepCopy() *PgBouncerDatabaseConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBouncerDatabaseConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBouncerDrainConfiguration) DeepCopyInto(out *PgBouncerDrainConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerDrainConfiguration.
+func (in *PgBouncerDrainConfiguration) DeepCopy() *PgBouncerDrainConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBouncerDrainConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PgBouncerIntegrationStatus) DeepCopyInto(out *PgBouncerIntegrationStatus) {
 	*out = *in
@@ -1822,11 +2380,23 @@ func (in *PgBouncerSpec) DeepCopyInto(out *PgBouncerSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]PgBouncerDatabaseConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Paused != nil {
 		in, out := &in.Paused, &out.Paused
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Drain != nil {
+		in, out := &in.Drain, &out.Drain
+		*out = new(PgBouncerDrainConfiguration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerSpec.
@@ -2163,6 +2733,11 @@ func (in *PostgresConfiguration) DeepCopyInto(out *PostgresConfiguration) {
 			(*out)[key] = val
 		}
 	}
+	if in.IncludeConfigMaps != nil {
+		in, out := &in.IncludeConfigMaps, &out.IncludeConfigMaps
+		*out = make([]ConfigMapKeySelector, len(*in))
+		copy(*out, *in)
+	}
 	if in.Synchronous != nil {
 		in, out := &in.Synchronous, &out.Synchronous
 		*out = new(SynchronousReplicaConfiguration)
@@ -2173,11 +2748,21 @@ func (in *PostgresConfiguration) DeepCopyInto(out *PostgresConfiguration) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.PgHBAPrepend != nil {
+		in, out := &in.PgHBAPrepend, &out.PgHBAPrepend
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.PgIdent != nil {
 		in, out := &in.PgIdent, &out.PgIdent
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ClientCertificateMappings != nil {
+		in, out := &in.ClientCertificateMappings, &out.ClientCertificateMappings
+		*out = make([]ClientCertificateMapping, len(*in))
+		copy(*out, *in)
+	}
 	in.SyncReplicaElectionConstraint.DeepCopyInto(&out.SyncReplicaElectionConstraint)
 	if in.AdditionalLibraries != nil {
 		in, out := &in.AdditionalLibraries, &out.AdditionalLibraries
@@ -2189,6 +2774,11 @@ func (in *PostgresConfiguration) DeepCopyInto(out *PostgresConfiguration) {
 		*out = new(LDAPConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MajorUpgrade != nil {
+		in, out := &in.MajorUpgrade, &out.MajorUpgrade
+		*out = new(MajorUpgradeConfiguration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresConfiguration.
@@ -2201,6 +2791,56 @@ func (in *PostgresConfiguration) DeepCopy() *PostgresConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrimaryPreferenceConfiguration) DeepCopyInto(out *PrimaryPreferenceConfiguration) {
+	*out = *in
+	if in.AutoSwitchback != nil {
+		in, out := &in.AutoSwitchback, &out.AutoSwitchback
+		*out = new(AutoSwitchbackConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrimaryPreferenceConfiguration.
+func (in *PrimaryPreferenceConfiguration) DeepCopy() *PrimaryPreferenceConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PrimaryPreferenceConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadOnlyServiceConfiguration) DeepCopyInto(out *ReadOnlyServiceConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadOnlyServiceConfiguration.
+func (in *ReadOnlyServiceConfiguration) DeepCopy() *ReadOnlyServiceConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadOnlyServiceConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadWriteServiceConfiguration) DeepCopyInto(out *ReadWriteServiceConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadWriteServiceConfiguration.
+func (in *ReadWriteServiceConfiguration) DeepCopy() *ReadWriteServiceConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadWriteServiceConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RecoveryTarget) DeepCopyInto(out *RecoveryTarget) {
 	*out = *in
@@ -2226,6 +2866,71 @@ func (in *RecoveryTarget) DeepCopy() *RecoveryTarget {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RejoinWALCleanupStatus) DeepCopyInto(out *RejoinWALCleanupStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RejoinWALCleanupStatus.
+func (in *RejoinWALCleanupStatus) DeepCopy() *RejoinWALCleanupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RejoinWALCleanupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepackConfiguration) DeepCopyInto(out *RepackConfiguration) {
+	*out = *in
+	if in.Tables != nil {
+		in, out := &in.Tables, &out.Tables
+		*out = make([]RepackTable, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepackConfiguration.
+func (in *RepackConfiguration) DeepCopy() *RepackConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(RepackConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepackStatus) DeepCopyInto(out *RepackStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepackStatus.
+func (in *RepackStatus) DeepCopy() *RepackStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RepackStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepackTable) DeepCopyInto(out *RepackTable) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepackTable.
+func (in *RepackTable) DeepCopy() *RepackTable {
+	if in == nil {
+		return nil
+	}
+	out := new(RepackTable)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReplicaClusterConfiguration) DeepCopyInto(out *ReplicaClusterConfiguration) {
 	*out = *in
@@ -2251,6 +2956,31 @@ func (in *ReplicaClusterConfiguration) DeepCopy() *ReplicaClusterConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationConfiguration) DeepCopyInto(out *ReplicationConfiguration) {
+	*out = *in
+	if in.PgBaseBackup != nil {
+		in, out := &in.PgBaseBackup, &out.PgBaseBackup
+		*out = new(PgBaseBackupOptions)
+		**out = **in
+	}
+	if in.CredentialsRotationPeriod != nil {
+		in, out := &in.CredentialsRotationPeriod, &out.CredentialsRotationPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationConfiguration.
+func (in *ReplicationConfiguration) DeepCopy() *ReplicationConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReplicationSlotsConfiguration) DeepCopyInto(out *ReplicationSlotsConfiguration) {
 	*out = *in
@@ -2313,6 +3043,11 @@ func (in *RoleConfiguration) DeepCopyInto(out *RoleConfiguration) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.InRolesWithAdminOption != nil {
+		in, out := &in.InRolesWithAdminOption, &out.InRolesWithAdminOption
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Inherit != nil {
 		in, out := &in.Inherit, &out.Inherit
 		*out = new(bool)
@@ -2612,6 +3347,21 @@ func (in *StorageConfiguration) DeepCopy() *StorageConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceReplicationSlotStatus) DeepCopyInto(out *SourceReplicationSlotStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceReplicationSlotStatus.
+func (in *SourceReplicationSlotStatus) DeepCopy() *SourceReplicationSlotStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceReplicationSlotStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SwitchReplicaClusterStatus) DeepCopyInto(out *SwitchReplicaClusterStatus) {
 	*out = *in
@@ -2627,6 +3377,37 @@ func (in *SwitchReplicaClusterStatus) DeepCopy() *SwitchReplicaClusterStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwitchbackWindow) DeepCopyInto(out *SwitchbackWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwitchbackWindow.
+func (in *SwitchbackWindow) DeepCopy() *SwitchbackWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(SwitchbackWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwitchoverOnShutdownConfiguration) DeepCopyInto(out *SwitchoverOnShutdownConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwitchoverOnShutdownConfiguration.
+func (in *SwitchoverOnShutdownConfiguration) DeepCopy() *SwitchoverOnShutdownConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(SwitchoverOnShutdownConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SyncReplicaElectionConstraints) DeepCopyInto(out *SyncReplicaElectionConstraints) {
 	*out = *in
@@ -2691,6 +3472,11 @@ func (in *SynchronousReplicaConfiguration) DeepCopyInto(out *SynchronousReplicaC
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.LagRotation != nil {
+		in, out := &in.LagRotation, &out.LagRotation
+		*out = new(SynchronousStandbyLagRotation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SynchronousReplicaConfiguration.
@@ -2703,6 +3489,53 @@ func (in *SynchronousReplicaConfiguration) DeepCopy() *SynchronousReplicaConfigu
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SynchronousStandbyLagRotation) DeepCopyInto(out *SynchronousStandbyLagRotation) {
+	*out = *in
+	if in.Delay != nil {
+		in, out := &in.Delay, &out.Delay
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SynchronousStandbyLagRotation.
+func (in *SynchronousStandbyLagRotation) DeepCopy() *SynchronousStandbyLagRotation {
+	if in == nil {
+		return nil
+	}
+	out := new(SynchronousStandbyLagRotation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SynchronousStandbyRotationStatus) DeepCopyInto(out *SynchronousStandbyRotationStatus) {
+	*out = *in
+	if in.LaggingSince != nil {
+		in, out := &in.LaggingSince, &out.LaggingSince
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Deprioritized != nil {
+		in, out := &in.Deprioritized, &out.Deprioritized
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SynchronousStandbyRotationStatus.
+func (in *SynchronousStandbyRotationStatus) DeepCopy() *SynchronousStandbyRotationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SynchronousStandbyRotationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TablespaceConfiguration) DeepCopyInto(out *TablespaceConfiguration) {
 	*out = *in
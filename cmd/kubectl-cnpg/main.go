@@ -29,7 +29,11 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/backup"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/certificate"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/config"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/copytable"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/destroy"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/explain"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/failover"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/fence"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/fio"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/hibernate"
@@ -43,10 +47,13 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/promote"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/psql"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/reload"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/replication"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/report"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/restart"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/restorepoint"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/snapshot"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/status"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/switchover"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/versions"
 
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
@@ -112,7 +119,11 @@ func main() {
 	subcommands := []*cobra.Command{
 		backup.NewCmd(),
 		certificate.NewCmd(),
+		config.NewCmd(),
+		copytable.NewCmd(),
 		destroy.NewCmd(),
+		explain.NewCmd(),
+		failover.NewCmd(),
 		fence.NewCmd(),
 		fio.NewCmd(),
 		hibernate.NewCmd(),
@@ -125,11 +136,14 @@ func main() {
 		psql.NewCmd(),
 		publication.NewCmd(),
 		reload.NewCmd(),
+		replication.NewCmd(),
 		report.NewCmd(),
 		restart.NewCmd(),
+		restorepoint.NewCmd(),
 		snapshot.NewCmd(),
 		status.NewCmd(),
 		subscription.NewCmd(),
+		switchover.NewCmd(),
 		versions.NewCmd(),
 	}
 
@@ -34,6 +34,9 @@ func NewCmd() *cobra.Command {
 	var pprofHTTPServer bool
 	var leaderLeaseDuration int
 	var leaderRenewDeadline int
+	var minPostgresMajor int
+	var execTimeout int
+	var execRetries int
 
 	cmd := cobra.Command{
 		Use:           "controller [flags]",
@@ -50,6 +53,11 @@ func NewCmd() *cobra.Command {
 				},
 				pprofHTTPServer,
 				port,
+				minPostgresMajor,
+				execConfiguration{
+					timeout: execTimeout,
+					retries: execRetries,
+				},
 				configuration.Current,
 			)
 		},
@@ -71,6 +79,15 @@ func NewCmd() *cobra.Command {
 		"the operator configuration. Values are merged with the ConfigMap's one, overwriting them if already defined")
 	cmd.Flags().IntVar(&port, "webhook-port", 9443, "The port the controller should be listening on."+
 		" If modified, take care to update the service pointing to it")
+	cmd.Flags().IntVar(&minPostgresMajor, "min-postgres-major", 0, "The minimum PostgreSQL major version "+
+		"allowed when creating a new cluster. Overrides the MIN_POSTGRES_MAJOR configuration option. "+
+		"Defaults to 0, meaning no restriction")
+	cmd.Flags().IntVar(&execTimeout, "exec-timeout", 0, "The timeout, in seconds, of the commands executed "+
+		"inside the instance pods. Overrides the EXEC_TIMEOUT configuration option. "+
+		"Defaults to 0, meaning that the configuration option is used")
+	cmd.Flags().IntVar(&execRetries, "exec-retries", -1, "The number of times a command executed "+
+		"inside the instance pods is retried after a transient failure. Overrides the EXEC_RETRIES "+
+		"configuration option. Defaults to -1, meaning that the configuration option is used")
 	cmd.Flags().BoolVar(
 		&pprofHTTPServer,
 		"pprof-server",
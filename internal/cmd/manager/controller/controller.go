@@ -82,6 +82,14 @@ type leaderElectionConfiguration struct {
 	renewDeadline time.Duration
 }
 
+// execConfiguration holds the flags overriding the policy used to
+// execute commands inside the instance pods, where negative or zero
+// values mean that the operator configuration is used
+type execConfiguration struct {
+	timeout int
+	retries int
+}
+
 // RunController is the main procedure of the operator, and is used as the
 // controller-manager of the operator and as the controller of a certain
 // PostgreSQL instance.
@@ -95,6 +103,8 @@ func RunController(
 	leaderConfig leaderElectionConfiguration,
 	pprofDebug bool,
 	port int,
+	minPostgresMajor int,
+	execConfig execConfiguration,
 	conf *configuration.Data,
 ) error {
 	ctx := context.Background()
@@ -175,6 +185,15 @@ func RunController(
 	if err != nil {
 		return err
 	}
+	if minPostgresMajor > 0 {
+		conf.MinPostgresMajor = minPostgresMajor
+	}
+	if execConfig.timeout > 0 {
+		conf.ExecTimeout = execConfig.timeout
+	}
+	if execConfig.retries >= 0 {
+		conf.ExecRetries = execConfig.retries
+	}
 
 	setupLog.Info("Operator configuration loaded", "configuration", conf)
 
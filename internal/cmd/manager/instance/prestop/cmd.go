@@ -0,0 +1,169 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package prestop implements the "instance prestop" subcommand of the operator
+package prestop
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+)
+
+// promotionPollInterval is the interval between two checks
+// of the promotion of the new primary
+const promotionPollInterval = time.Second
+
+// NewCmd creates the "instance prestop" subcommand, used as the
+// preStop hook of the PostgreSQL container
+func NewCmd() *cobra.Command {
+	var timeout time.Duration
+	var podName string
+	var clusterName string
+	var namespace string
+
+	cmd := &cobra.Command{
+		Use:           "prestop [flags]",
+		Short:         "Hand over the primary role before the instance is stopped",
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := log.IntoContext(
+				cmd.Context(),
+				log.GetLogger().WithValues("logger", "instance-prestop"),
+			)
+
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			return preStop(ctx, client.ObjectKey{Namespace: namespace, Name: clusterName}, podName)
+		},
+	}
+
+	cmd.Flags().DurationVar(&timeout, "timeout", apiv1.DefaultMaxSwitchoverDelay*time.Second,
+		"The maximum time to wait for the promotion of the new primary")
+	cmd.Flags().StringVar(&podName, "pod-name", os.Getenv("POD_NAME"), "The name of this pod, to "+
+		"be checked against the cluster state")
+	cmd.Flags().StringVar(&clusterName, "cluster-name", os.Getenv("CLUSTER_NAME"), "The name of the "+
+		"current cluster in k8s")
+	cmd.Flags().StringVar(&namespace, "namespace", os.Getenv("NAMESPACE"), "The namespace of "+
+		"the cluster and of the Pod in k8s")
+
+	return cmd
+}
+
+func preStop(ctx context.Context, clusterKey client.ObjectKey, podName string) error {
+	contextLogger := log.FromContext(ctx)
+
+	targetPrimary, err := requestSwitchover(ctx)
+	if err != nil {
+		contextLogger.Error(err, "Error while requesting the switchover, proceeding with the shutdown")
+		return err
+	}
+	if targetPrimary == "" {
+		return nil
+	}
+
+	cli, err := management.NewControllerRuntimeClient()
+	if err != nil {
+		contextLogger.Error(err, "while building the controller runtime client")
+		return err
+	}
+
+	contextLogger.Info("Waiting for the promotion of the new primary", "targetPrimary", targetPrimary)
+	if err := waitForPromotion(ctx, cli, clusterKey, podName, promotionPollInterval); err != nil {
+		contextLogger.Error(err, "The new primary has not been promoted, proceeding with the shutdown",
+			"targetPrimary", targetPrimary)
+		return err
+	}
+
+	contextLogger.Info("The new primary has been promoted, proceeding with the shutdown",
+		"targetPrimary", targetPrimary)
+	return nil
+}
+
+// requestSwitchover asks the instance manager to hand over the primary role,
+// returning the name of the new primary, or an empty string if no switchover
+// has been requested
+func requestSwitchover(ctx context.Context) (string, error) {
+	contextLogger := log.FromContext(ctx)
+
+	switchoverURL := url.Local(url.PathPgSwitchover, url.LocalPort)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, switchoverURL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			contextLogger.Error(err, "Can't close the connection",
+				"switchoverURL", switchoverURL,
+				"statusCode", resp.StatusCode,
+			)
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusAccepted:
+		return string(body), nil
+	case http.StatusOK:
+		contextLogger.Info("No switchover needed before shutting down", "reason", string(body))
+		return "", nil
+	default:
+		return "", fmt.Errorf("invalid status code: %v, body: %s", resp.StatusCode, string(body))
+	}
+}
+
+// waitForPromotion waits until the instance is no longer
+// the current primary of the cluster
+func waitForPromotion(
+	ctx context.Context,
+	cli client.Client,
+	clusterKey client.ObjectKey,
+	podName string,
+	pollInterval time.Duration,
+) error {
+	return wait.PollUntilContextCancel(ctx, pollInterval, true,
+		func(ctx context.Context) (bool, error) {
+			var cluster apiv1.Cluster
+			if err := cli.Get(ctx, clusterKey, &cluster); err != nil {
+				log.FromContext(ctx).Warning("Error while getting the cluster, retrying", "err", err)
+				return false, nil
+			}
+
+			return cluster.Status.CurrentPrimary != podName, nil
+		})
+}
@@ -40,7 +40,10 @@ import (
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/run/lifecycle"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/archiving"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/externalservers"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/integrity"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/maintenance"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/roles"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/slots/runner"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/tablespaces"
@@ -77,6 +80,7 @@ func NewCmd() *cobra.Command {
 	var namespace string
 	var statusPortTLS bool
 	var metricsPortTLS bool
+	var pprofServer bool
 
 	cmd := &cobra.Command{
 		Use: "run [flags]",
@@ -101,7 +105,7 @@ func NewCmd() *cobra.Command {
 			instance.MetricsPortTLS = metricsPortTLS
 
 			err := retry.OnError(retry.DefaultRetry, isRunSubCommandRetryable, func() error {
-				return runSubCommand(ctx, instance)
+				return runSubCommand(ctx, instance, pprofServer)
 			})
 
 			if errors.Is(err, errNoFreeWALSpace) {
@@ -130,10 +134,12 @@ func NewCmd() *cobra.Command {
 		"Enable TLS for communicating with the operator")
 	cmd.Flags().BoolVar(&metricsPortTLS, "metrics-port-tls", false,
 		"Enable TLS for metrics scraping")
+	cmd.Flags().BoolVar(&pprofServer, "pprof-server", false,
+		"If true it will start a pprof debug http server on localhost:6060. Defaults to false.")
 	return cmd
 }
 
-func runSubCommand(ctx context.Context, instance *postgres.Instance) error {
+func runSubCommand(ctx context.Context, instance *postgres.Instance, pprofServer bool) error {
 	var err error
 
 	contextLogger := log.FromContext(ctx)
@@ -183,6 +189,7 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error {
 		Metrics: server.Options{
 			BindAddress: "0", // TODO: merge metrics to the manager one
 		},
+		PprofBindAddress: getPprofServerAddress(pprofServer),
 		BaseContext: func() context.Context {
 			return ctx
 		},
@@ -197,7 +204,12 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error {
 	exitedConditions := concurrency.MultipleExecuted{}
 
 	metricsExporter := metricserver.NewExporter(instance)
-	reconciler := controller.NewInstanceReconciler(instance, mgr.GetClient(), metricsExporter)
+	reconciler := controller.NewInstanceReconciler(
+		instance,
+		mgr.GetClient(),
+		metricsExporter,
+		mgr.GetEventRecorderFor("instance-manager"),
+	)
 	err = ctrl.NewControllerManagedBy(mgr).
 		For(&apiv1.Cluster{}).
 		Named("instance-cluster").
@@ -267,6 +279,24 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error {
 		return err
 	}
 
+	integrityChecker := integrity.NewChecker(instance, reconciler.GetClient())
+	if err = mgr.Add(integrityChecker); err != nil {
+		contextLogger.Error(err, "unable to create integrity checker")
+		return err
+	}
+
+	repacker := maintenance.NewRepacker(instance, reconciler.GetClient())
+	if err = mgr.Add(repacker); err != nil {
+		contextLogger.Error(err, "unable to create repacker")
+		return err
+	}
+
+	archiveVerifier := archiving.NewVerifier(instance, reconciler.GetClient())
+	if err = mgr.Add(archiveVerifier); err != nil {
+		contextLogger.Error(err, "unable to create archive verifier")
+		return err
+	}
+
 	// onlineUpgradeCtx is a child context of the postgres context.
 	// onlineUpgradeCtx will be the context passed to all the manager handled Runnables via Start(ctx),
 	// its deletion will imply all Runnables to stop, but will be handled
@@ -337,3 +367,13 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error {
 
 	return nil
 }
+
+// getPprofServerAddress returns the address of the pprof server. The server
+// is bound to localhost only, and can be reached with a port-forward
+func getPprofServerAddress(enabled bool) string {
+	if enabled {
+		return "localhost:6060"
+	}
+
+	return ""
+}
@@ -0,0 +1,38 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package run
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pprof server", func() {
+	It("is not started by default", func() {
+		Expect(getPprofServerAddress(false)).To(BeEmpty())
+	})
+
+	It("is only bound to localhost when enabled", func() {
+		Expect(getPprofServerAddress(true)).To(Equal("localhost:6060"))
+	})
+
+	It("is disabled by default in the run command", func() {
+		flag := NewCmd().Flags().Lookup("pprof-server")
+		Expect(flag).ToNot(BeNil())
+		Expect(flag.DefValue).To(Equal("false"))
+	})
+})
@@ -0,0 +1,129 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package upgrade implements the "instance upgrade" subcommand of the operator,
+// upgrading the data directory to a newer major version of PostgreSQL
+package upgrade
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/kballard/go-shellquote"
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/istio"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/linkerd"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	postgresSpec "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// NewCmd creates the "upgrade" subcommand
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrade the data directory to a newer major version of PostgreSQL",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return fmt.Errorf("missing subcommand")
+		},
+	}
+
+	cmd.AddCommand(newPrepareCmd())
+	cmd.AddCommand(newExecuteCmd())
+
+	return cmd
+}
+
+// newPrepareCmd creates the "upgrade prepare" subcommand, which runs
+// in the image of the previous major version
+func newPrepareCmd() *cobra.Command {
+	var destination string
+
+	cmd := &cobra.Command{
+		Use:           "prepare [flags]",
+		Short:         "Copy the PostgreSQL installation to be upgraded from",
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			if err := postgres.CopyPostgresInstallation(ctx, destination); err != nil {
+				log.FromContext(ctx).Error(err, "Error while copying the PostgreSQL installation")
+				return err
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&destination, "destination", postgresSpec.MajorUpgradeOldBinariesDirectory,
+		"The directory where the PostgreSQL installation is copied")
+
+	return cmd
+}
+
+// newExecuteCmd creates the "upgrade execute" subcommand, which runs
+// in the image of the new major version
+func newExecuteCmd() *cobra.Command {
+	var (
+		initDBFlagsString string
+		oldInstallation   string
+		pgData            string
+		pgWal             string
+	)
+
+	cmd := &cobra.Command{
+		Use:           "execute [flags]",
+		Short:         "Upgrade the data directory with pg_upgrade",
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			contextLogger := log.FromContext(ctx)
+
+			initDBFlags, err := shellquote.Split(initDBFlagsString)
+			if err != nil {
+				contextLogger.Error(err, "Error while parsing initdb flags")
+				return err
+			}
+
+			info := postgres.MajorUpgradeInfo{
+				PgData:                   pgData,
+				PgWal:                    pgWal,
+				OldInstallationDirectory: oldInstallation,
+				InitDBOptions:            initDBFlags,
+			}
+			if err := info.Upgrade(ctx); err != nil {
+				contextLogger.Error(err, "Error while upgrading the data directory")
+				return err
+			}
+			return nil
+		},
+		PostRunE: func(cmd *cobra.Command, _ []string) error {
+			if err := istio.TryInvokeQuitEndpoint(cmd.Context()); err != nil {
+				return err
+			}
+
+			return linkerd.TryInvokeShutdownEndpoint(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVar(&initDBFlagsString, "initdb-flags", "", "The list of flags to be passed "+
+		"to initdb while creating the upgraded data directory")
+	cmd.Flags().StringVar(&oldInstallation, "old-installation", postgresSpec.MajorUpgradeOldBinariesDirectory,
+		"The directory where the PostgreSQL installation to be upgraded from has been copied")
+	cmd.Flags().StringVar(&pgData, "pg-data", os.Getenv("PGDATA"), "The PGDATA to be upgraded")
+	cmd.Flags().StringVar(&pgWal, "pg-wal", "", "The PGWAL to be upgraded")
+
+	return cmd
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/pgbouncer/drain"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/pgbouncer/run"
 )
 
@@ -37,6 +38,7 @@ func NewCmd() *cobra.Command {
 	}
 
 	cmd.AddCommand(run.NewCmd())
+	cmd.AddCommand(drain.NewCmd())
 
 	return cmd
 }
@@ -0,0 +1,69 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package drain implements the "pgbouncer drain" subcommand of the operator
+package drain
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/spf13/cobra"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/pgbouncer/management/controller"
+)
+
+// NewCmd creates the "pgbouncer drain" subcommand, used as the
+// preStop hook of the PgBouncer container
+func NewCmd() *cobra.Command {
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:           "drain",
+		Short:         "Drain the PgBouncer connections before the pod is terminated",
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := log.IntoContext(
+				cmd.Context(),
+				log.GetLogger().WithValues("logger", "pgbouncer-drain"),
+			)
+			contextLogger := log.FromContext(ctx)
+
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			contextLogger.Info("Draining the PgBouncer connections", "timeout", timeout)
+			if err := controller.NewPgBouncerInstance().Drain(ctx); err != nil {
+				contextLogger.Error(err, "Error while draining the PgBouncer connections")
+				return err
+			}
+
+			contextLogger.Info("PgBouncer connections drained")
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(
+		&timeout,
+		"timeout",
+		apiv1.DefaultPgBouncerDrainTimeoutSeconds*time.Second,
+		"The maximum time to wait for the running transactions to complete",
+	)
+
+	return cmd
+}
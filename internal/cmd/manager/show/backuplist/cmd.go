@@ -0,0 +1,109 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backuplist implement the backup-list command
+package backuplist
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	barmanCapabilities "github.com/cloudnative-pg/barman-cloud/pkg/capabilities"
+	barmanCommand "github.com/cloudnative-pg/barman-cloud/pkg/command"
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	cacheClient "github.com/cloudnative-pg/cloudnative-pg/internal/management/cache/client"
+)
+
+// errNoBackupConfigured is raised when the cluster has no object store configured
+var errNoBackupConfigured = errors.New("no object store is configured for this cluster")
+
+// NewCmd creates the new cobra command
+func NewCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use: "backup-list",
+		Short: "Prints the catalog of the backups in the object store, " +
+			"in the JSON format of barman-cloud-backup-list",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			contextLogger := log.FromContext(ctx)
+
+			if err := run(ctx); err != nil {
+				contextLogger.Error(err, "Error while extracting the backup catalog")
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	return &cmd
+}
+
+func run(ctx context.Context) error {
+	cluster, err := cacheClient.GetCluster()
+	if err != nil {
+		return fmt.Errorf("failed to get cluster: %w", err)
+	}
+
+	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
+		return errNoBackupConfigured
+	}
+	barmanConfiguration := cluster.GetBackupConfiguration().BarmanObjectStore
+
+	// The catalog is kept in the object store containing the base backups,
+	// which may be different from the one receiving the WAL files
+	env, err := cacheClient.GetEnv(cache.BackupCatalogKey)
+	if err != nil {
+		return fmt.Errorf("failed to get envs: %w", err)
+	}
+
+	// Set the barman server name as specified by the user.
+	// If not explicitly configured use the cluster name
+	serverName := barmanConfiguration.ServerName
+	if serverName == "" {
+		serverName = cluster.Name
+	}
+
+	options := []string{"--format", "json"}
+	if barmanConfiguration.EndpointURL != "" {
+		options = append(options, "--endpoint-url", barmanConfiguration.EndpointURL)
+	}
+	options, err = barmanCommand.AppendCloudProviderOptionsFromConfiguration(ctx, options, barmanConfiguration)
+	if err != nil {
+		return err
+	}
+	options = append(options, barmanConfiguration.DestinationPath, serverName)
+
+	var stderr bytes.Buffer
+	command := exec.Command(barmanCapabilities.BarmanCloudBackupList, options...) // #nosec G204
+	command.Env = env
+	command.Stdout = os.Stdout
+	command.Stderr = &stderr
+	if err := command.Run(); err != nil {
+		return fmt.Errorf("while running %s: %w (%s)",
+			barmanCapabilities.BarmanCloudBackupList, err, stderr.String())
+	}
+
+	return nil
+}
@@ -20,6 +20,7 @@ package show
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/show/backuplist"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/show/walarchivequeue"
 )
 
@@ -32,6 +33,7 @@ func NewCmd() *cobra.Command {
 	}
 
 	cmd.AddCommand(walarchivequeue.NewCmd())
+	cmd.AddCommand(backuplist.NewCmd())
 
 	return &cmd
 }
@@ -0,0 +1,157 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walarchive
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	barmanCapabilities "github.com/cloudnative-pg/barman-cloud/pkg/capabilities"
+	"github.com/cloudnative-pg/barman-cloud/pkg/spool"
+	"github.com/cloudnative-pg/machinery/pkg/execlog"
+	"github.com/cloudnative-pg/machinery/pkg/log"
+)
+
+// barmanCloudOutputLines is the number of lines of the error output of
+// barman-cloud-wal-archive kept to classify its failures
+const barmanCloudOutputLines = 20
+
+// barmanCloudError is a failure of barman-cloud-wal-archive, together
+// with the last lines of its error output
+type barmanCloudError struct {
+	err    error
+	output []string
+}
+
+// Error implements the error interface, reporting the last line of the
+// error output, where barman-cloud writes the reason of the failure
+func (e *barmanCloudError) Error() string {
+	if len(e.output) == 0 {
+		return e.err.Error()
+	}
+	return fmt.Sprintf("%v: %s", e.err, e.output[len(e.output)-1])
+}
+
+// Unwrap returns the error of the command, i.e. its exit status
+func (e *barmanCloudError) Unwrap() error {
+	return e.err
+}
+
+// outputTail is a writer keeping the last non-empty lines written to it.
+// The command output is written one line at a time by execlog
+type outputTail struct {
+	lines []string
+}
+
+// Write implements the io.Writer interface
+func (t *outputTail) Write(p []byte) (int, error) {
+	if line := strings.TrimSpace(string(p)); line != "" {
+		t.lines = append(t.lines, line)
+		if len(t.lines) > barmanCloudOutputLines {
+			t.lines = t.lines[1:]
+		}
+	}
+	return len(p), nil
+}
+
+// archiveWALFileList archives the passed WAL files in parallel. The first one
+// is the WAL file requested by PostgreSQL, while the other ones are marked
+// in the spool once archived, so that they are not archived again
+func archiveWALFileList(
+	ctx context.Context,
+	walSpool *spool.WALSpool,
+	env []string,
+	walNames []string,
+	options []string,
+) []error {
+	contextLog := log.FromContext(ctx)
+	result := make([]error, len(walNames))
+
+	var waitGroup sync.WaitGroup
+	for idx := range walNames {
+		waitGroup.Add(1)
+		go func(walIndex int) {
+			defer waitGroup.Done()
+
+			walName := walNames[walIndex]
+			startTime := time.Now()
+			err := archiveWithBarmanCloud(ctx, env, walName, options)
+			endTime := time.Now()
+			if err == nil && walIndex != 0 {
+				err = walSpool.Touch(walName)
+			}
+			result[walIndex] = err
+
+			if err != nil {
+				contextLog.Warning("Failed archiving WAL: PostgreSQL will retry",
+					"walName", walName,
+					"startTime", startTime,
+					"endTime", endTime,
+					"elapsedWalTime", endTime.Sub(startTime),
+					"error", err)
+				return
+			}
+			contextLog.Info("Archived WAL file",
+				"walName", walName,
+				"startTime", startTime,
+				"endTime", endTime,
+				"elapsedWalTime", endTime.Sub(startTime))
+		}(idx)
+	}
+
+	waitGroup.Wait()
+	return result
+}
+
+// archiveWithBarmanCloud archives a WAL file with barman-cloud-wal-archive.
+// The output of the command is streamed to the log, while the last lines
+// of its error output are kept in the returned barmanCloudError
+func archiveWithBarmanCloud(ctx context.Context, env []string, walName string, options []string) error {
+	const commandName = barmanCapabilities.BarmanCloudWalArchive
+
+	args := append(slices.Clone(options), walName)
+	log.FromContext(ctx).Info("Executing "+commandName, "walName", walName, "options", args)
+
+	cmd := exec.Command(commandName, args...) // #nosec G204
+	cmd.Env = env
+
+	logger := log.WithName(commandName)
+	stderr := &outputTail{}
+	streamingCmd, err := execlog.RunStreamingNoWaitWithWriter(
+		cmd,
+		commandName,
+		&execlog.LogWriter{Logger: logger.WithValues(execlog.PipeKey, execlog.StdOut)},
+		io.MultiWriter(&execlog.LogWriter{Logger: logger.WithValues(execlog.PipeKey, execlog.StdErr)}, stderr),
+	)
+	if err == nil {
+		err = streamingCmd.Wait()
+	}
+	if err != nil {
+		return &barmanCloudError{
+			err:    fmt.Errorf("unexpected failure invoking %s: %w", commandName, err),
+			output: stderr.lines,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walarchive
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/cloudnative-pg/barman-cloud/pkg/spool"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeBarmanCloudWalArchive fails archiving the WAL file named FAILING,
+// writing to its stderr what barman-cloud-wal-archive writes when the
+// access key is not valid anymore
+const fakeBarmanCloudWalArchive = `#!/bin/sh
+for wal; do :; done
+if [ "$(basename "$wal")" = "FAILING" ]; then
+  echo "2024-11-05 09:14:27,118 [2134] INFO: Found credentials in environment variables." >&2
+  echo "2024-11-05 09:14:27,402 [2134] ERROR: Barman cloud WAL archiver exception: ` +
+	`An error occurred (InvalidAccessKeyId) when calling the PutObject operation: ` +
+	`The AWS Access Key Id you provided does not exist in our records." >&2
+  exit 1
+fi
+`
+
+var _ = Describe("archiveWALFileList", func() {
+	var walSpool *spool.WALSpool
+
+	BeforeEach(func() {
+		binDir := GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(binDir, "barman-cloud-wal-archive"),
+			[]byte(fakeBarmanCloudWalArchive), 0o700)).To(Succeed()) // #nosec
+		GinkgoT().Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+		var err error
+		walSpool, err = spool.New(GinkgoT().TempDir())
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("marks the pre-archived WAL files in the spool", func(ctx SpecContext) {
+		errs := archiveWALFileList(ctx, walSpool, os.Environ(),
+			[]string{"000000010000000000000001", "000000010000000000000002"}, []string{"s3://wal/", "cluster"})
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0]).ToNot(HaveOccurred())
+		Expect(errs[1]).ToNot(HaveOccurred())
+
+		Expect(walSpool.Contains("000000010000000000000001")).To(BeFalse())
+		Expect(walSpool.Contains("000000010000000000000002")).To(BeTrue())
+	})
+
+	It("keeps the error output of barman-cloud-wal-archive", func(ctx SpecContext) {
+		errs := archiveWALFileList(ctx, walSpool, os.Environ(),
+			[]string{"FAILING"}, []string{"s3://wal/", "cluster"})
+		Expect(errs).To(HaveLen(1))
+
+		var barmanErr *barmanCloudError
+		Expect(errors.As(errs[0], &barmanErr)).To(BeTrue())
+		Expect(barmanErr.output).To(HaveLen(2))
+		Expect(errs[0].Error()).To(ContainSubstring("exit status 1"))
+		Expect(errs[0].Error()).To(ContainSubstring("(InvalidAccessKeyId)"))
+
+		var exitErr *exec.ExitError
+		Expect(errors.As(errs[0], &exitErr)).To(BeTrue())
+		Expect(exitErr.ExitCode()).To(Equal(1))
+
+		Expect(isAuthenticationFailure(getFailureOutput(errs[0]))).To(BeTrue())
+	})
+})
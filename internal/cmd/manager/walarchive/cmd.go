@@ -27,6 +27,7 @@ import (
 	"time"
 
 	barmanArchiver "github.com/cloudnative-pg/barman-cloud/pkg/archiver"
+	"github.com/cloudnative-pg/barman-cloud/pkg/spool"
 	"github.com/cloudnative-pg/machinery/pkg/fileutils"
 	"github.com/cloudnative-pg/machinery/pkg/log"
 	"github.com/cloudnative-pg/machinery/pkg/stringset"
@@ -90,13 +91,8 @@ func NewCmd() *cobra.Command {
 					contextLog.Error(err, logErrorMessage)
 				}
 
-				condition := metav1.Condition{
-					Type:    string(apiv1.ConditionContinuousArchiving),
-					Status:  metav1.ConditionFalse,
-					Reason:  string(apiv1.ConditionReasonContinuousArchivingFailing),
-					Message: err.Error(),
-				}
-				if errCond := conditions.Patch(ctx, typedClient, cluster, &condition); errCond != nil {
+				condition := getArchiveFailureCondition(cluster, err)
+				if errCond := conditions.Patch(ctx, typedClient, cluster, condition); errCond != nil {
 					contextLog.Error(errCond, "Error changing wal archiving condition (wal archiving failed)")
 				}
 				return err
@@ -176,9 +172,11 @@ func run(
 		return fmt.Errorf("failed to get envs: %w", err)
 	}
 
+	walObjectStore := cluster.GetBackupConfiguration().GetWALObjectStore()
+	applyWALCompressionLevel(walObjectStore, cluster.Spec.Backup.WALCompressionLevel)
 	maxParallel := 1
-	if cluster.Spec.Backup.BarmanObjectStore.Wal != nil {
-		maxParallel = cluster.Spec.Backup.BarmanObjectStore.Wal.MaxParallel
+	if walObjectStore.Wal != nil {
+		maxParallel = walObjectStore.Wal.MaxParallel
 	}
 
 	// Create the archiver
@@ -194,7 +192,7 @@ func run(
 
 	// Step 1: Check if the archive location is safe to perform archiving
 	if utils.IsEmptyWalArchiveCheckEnabled(&cluster.ObjectMeta) {
-		if err := checkWalArchive(ctx, cluster, walArchiver, pgData); err != nil {
+		if err := checkWalArchive(ctx, cluster, walObjectStore, walArchiver, pgData); err != nil {
 			return err
 		}
 	}
@@ -217,14 +215,19 @@ func run(
 	walFilesList := walArchiver.GatherWALFilesToArchive(ctx, walName, maxParallel)
 
 	options, err := walArchiver.BarmanCloudWalArchiveOptions(
-		ctx, cluster.Spec.Backup.BarmanObjectStore, cluster.Name)
+		ctx, walObjectStore, cluster.Name)
 	if err != nil {
 		return err
 	}
 
+	walSpool, err := spool.New(postgres.SpoolDirectory)
+	if err != nil {
+		return fmt.Errorf("while opening the spool directory: %w", err)
+	}
+
 	// Step 5: archive the WAL files in parallel
 	uploadStartTime := time.Now()
-	walStatus := walArchiver.ArchiveList(ctx, walFilesList, options)
+	walStatus := archiveWALFileList(ctx, walSpool, env, walFilesList, options)
 	if len(walStatus) > 1 {
 		contextLog.Info("Completed archive command (parallel)",
 			"walsCount", len(walStatus),
@@ -238,7 +241,17 @@ func run(
 	// is the one raised by the file that PostgreSQL has requested to archive.
 	// The other errors are related to WAL files that were pre-archived as
 	// a performance optimization and are just logged
-	return walStatus[0].Err
+	if walStatus[0] != nil {
+		return walStatus[0]
+	}
+
+	// The archive location has been checked, and the flag file is not
+	// needed anymore once the first WAL file has been archived
+	if err := fileutils.RemoveFile(path.Join(pgData, pgManagement.CheckEmptyWalArchiveFile)); err != nil {
+		return fmt.Errorf("error while deleting the check WAL file flag: %w", err)
+	}
+
+	return nil
 }
 
 // archiveWALViaPlugins requests every capable plugin to archive the passed
@@ -275,6 +288,20 @@ func archiveWALViaPlugins(
 	return client.ArchiveWAL(ctx, cluster, walName)
 }
 
+// applyWALCompressionLevel passes the compression level of the WAL files,
+// when set, to barman-cloud-wal-archive. The level is not part of the
+// barman-cloud configuration, so it's added to the additional arguments
+// of the command, where it takes precedence over the user-defined ones
+func applyWALCompressionLevel(walObjectStore *apiv1.BarmanObjectStoreConfiguration, level *int) {
+	if level == nil || walObjectStore.Wal == nil || walObjectStore.Wal.Compression == "" {
+		return
+	}
+
+	walObjectStore.Wal.ArchiveAdditionalCommandArgs = append(
+		[]string{fmt.Sprintf("--compression-level=%d", *level)},
+		walObjectStore.Wal.ArchiveAdditionalCommandArgs...)
+}
+
 // isCheckWalArchiveFlagFilePresent returns true if the file CheckEmptyWalArchiveFile is present in the PGDATA directory
 func isCheckWalArchiveFlagFilePresent(ctx context.Context, pgDataDirectory string) bool {
 	contextLogger := log.FromContext(ctx)
@@ -296,12 +323,13 @@ func isCheckWalArchiveFlagFilePresent(ctx context.Context, pgDataDirectory strin
 func checkWalArchive(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
+	walObjectStore *apiv1.BarmanObjectStoreConfiguration,
 	walArchiver *barmanArchiver.WALArchiver,
 	pgData string,
 ) error {
 	contextLogger := log.FromContext(ctx)
 	checkWalOptions, err := walArchiver.BarmanCloudCheckWalArchiveOptions(
-		ctx, cluster.Spec.Backup.BarmanObjectStore, cluster.Name)
+		ctx, walObjectStore, cluster.Name)
 	if err != nil {
 		contextLogger.Error(err, "while getting barman-cloud-wal-archive options")
 		return err
@@ -0,0 +1,64 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walarchive
+
+import (
+	barmanApi "github.com/cloudnative-pg/barman-cloud/pkg/api"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("applyWALCompressionLevel", func() {
+	newObjectStore := func(compression barmanApi.CompressionType, args ...string) *apiv1.BarmanObjectStoreConfiguration {
+		return &apiv1.BarmanObjectStoreConfiguration{
+			DestinationPath: "s3://wal/",
+			Wal: &apiv1.WalBackupConfiguration{
+				Compression:                  compression,
+				ArchiveAdditionalCommandArgs: args,
+			},
+		}
+	}
+
+	It("passes the compression level to barman-cloud-wal-archive", func() {
+		objectStore := newObjectStore(barmanApi.CompressionTypeGzip, "--read-timeout=60")
+		applyWALCompressionLevel(objectStore, ptr.To(9))
+		Expect(objectStore.Wal.ArchiveAdditionalCommandArgs).To(Equal([]string{
+			"--compression-level=9",
+			"--read-timeout=60",
+		}))
+	})
+
+	It("does nothing when the compression level is not set", func() {
+		objectStore := newObjectStore(barmanApi.CompressionTypeBzip2)
+		applyWALCompressionLevel(objectStore, nil)
+		Expect(objectStore.Wal.ArchiveAdditionalCommandArgs).To(BeEmpty())
+	})
+
+	It("does nothing when the WAL files are not compressed", func() {
+		objectStore := newObjectStore("")
+		applyWALCompressionLevel(objectStore, ptr.To(5))
+		Expect(objectStore.Wal.ArchiveAdditionalCommandArgs).To(BeEmpty())
+
+		objectStore.Wal = nil
+		applyWALCompressionLevel(objectStore, ptr.To(5))
+		Expect(objectStore.Wal).To(BeNil())
+	})
+})
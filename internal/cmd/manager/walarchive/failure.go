@@ -0,0 +1,120 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walarchive
+
+import (
+	"errors"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// authenticationFailureMessagePrefix marks the message of the
+// ContinuousArchiving condition when the failure was classified as an
+// authentication failure, so that the next failure doesn't need to
+// classify the stored message again
+const authenticationFailureMessagePrefix = "Authentication failure: "
+
+// authenticationFailurePatterns are the fragments, in lowercase, of the
+// messages reported by barman-cloud and by the cloud providers SDKs when
+// the credentials to access the object store are not valid anymore
+var authenticationFailurePatterns = []string{
+	"accessdenied",
+	"access denied",
+	"invalidaccesskeyid",
+	"invalidclienttokenid",
+	"signaturedoesnotmatch",
+	"expiredtoken",
+	"token has expired",
+	"authenticationfailed",
+	"authorizationfailure",
+	"invalid_grant",
+	"unauthorized",
+	"forbidden",
+	"nocredentialserror",
+	"unable to locate credentials",
+	"invalid credentials",
+}
+
+// isAuthenticationFailure checks if the passed error output reports that
+// the object store refused the credentials
+func isAuthenticationFailure(output string) bool {
+	output = strings.ToLower(output)
+	for _, pattern := range authenticationFailurePatterns {
+		if strings.Contains(output, pattern) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getFailureOutput gets the error output of barman-cloud-wal-archive
+// when it caused the passed error, as the error itself only carries
+// the exit status of the command
+func getFailureOutput(err error) string {
+	var barmanErr *barmanCloudError
+	if errors.As(err, &barmanErr) {
+		return strings.Join(barmanErr.output, "\n")
+	}
+
+	return err.Error()
+}
+
+// getArchiveFailureCondition builds the ContinuousArchiving condition
+// describing the passed archiving failure. Authentication failures are
+// reported with a dedicated reason when they happen repeatedly, telling
+// expired credentials apart from transient failures
+func getArchiveFailureCondition(cluster *apiv1.Cluster, err error) *metav1.Condition {
+	condition := &metav1.Condition{
+		Type:    string(apiv1.ConditionContinuousArchiving),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionReasonContinuousArchivingFailing),
+		Message: err.Error(),
+	}
+	if !isAuthenticationFailure(getFailureOutput(err)) {
+		return condition
+	}
+
+	condition.Message = authenticationFailureMessagePrefix + condition.Message
+	if isPreviousAuthenticationFailure(cluster) {
+		condition.Reason = string(apiv1.ConditionReasonBackupCredentialsInvalid)
+	}
+
+	return condition
+}
+
+// isPreviousAuthenticationFailure checks if the current ContinuousArchiving
+// condition reports a failure that was classified as an authentication failure
+func isPreviousAuthenticationFailure(cluster *apiv1.Cluster) bool {
+	previous := meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionContinuousArchiving))
+	if previous == nil || previous.Status != metav1.ConditionFalse {
+		return false
+	}
+
+	switch previous.Reason {
+	case string(apiv1.ConditionReasonBackupCredentialsInvalid):
+		return true
+	case string(apiv1.ConditionReasonContinuousArchivingFailing):
+		return strings.HasPrefix(previous.Message, authenticationFailureMessagePrefix)
+	default:
+		return false
+	}
+}
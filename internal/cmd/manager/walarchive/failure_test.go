@@ -0,0 +1,141 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walarchive
+
+import (
+	"errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("archive failure classification", func() {
+	const authErrorOutput = "2024-11-05 09:14:27,402 [2134] ERROR: Barman cloud WAL archiver exception: " +
+		"An error occurred (InvalidAccessKeyId) when calling the PutObject operation: " +
+		"The AWS Access Key Id you provided does not exist in our records."
+
+	// newBarmanCloudError builds the error returned when barman-cloud-wal-archive
+	// exits with the passed error output
+	newBarmanCloudError := func(output ...string) error {
+		return &barmanCloudError{
+			err:    errors.New("unexpected failure invoking barman-cloud-wal-archive: exit status 1"),
+			output: output,
+		}
+	}
+	authError := newBarmanCloudError(
+		"2024-11-05 09:14:27,118 [2134] INFO: Found credentials in environment variables.",
+		authErrorOutput,
+	)
+
+	newCluster := func(conditions ...metav1.Condition) *apiv1.Cluster {
+		return &apiv1.Cluster{
+			Status: apiv1.ClusterStatus{
+				Conditions: conditions,
+			},
+		}
+	}
+
+	DescribeTable("detects the authentication failures",
+		func(output string, expected bool) {
+			Expect(isAuthenticationFailure(output)).To(Equal(expected))
+		},
+		Entry("AWS invalid key", authErrorOutput, true),
+		Entry("AWS expired token", "ExpiredToken: The security token included in the request is expired", true),
+		Entry("Azure", "AuthenticationFailed: Server failed to authenticate the request", true),
+		Entry("Google Cloud", "invalid_grant: Invalid JWT Signature", true),
+		Entry("network failure", "Could not connect to the endpoint URL", false),
+		Entry("generic failure", "exit status 4", false),
+	)
+
+	It("reports a generic failure at the first authentication error", func() {
+		condition := getArchiveFailureCondition(newCluster(), authError)
+		Expect(condition.Type).To(Equal(string(apiv1.ConditionContinuousArchiving)))
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonContinuousArchivingFailing)))
+		Expect(condition.Message).To(Equal(authenticationFailureMessagePrefix +
+			"unexpected failure invoking barman-cloud-wal-archive: exit status 1: " + authErrorOutput))
+	})
+
+	It("reports invalid credentials on repeated authentication errors", func() {
+		cluster := newCluster(*getArchiveFailureCondition(newCluster(), authError))
+
+		condition := getArchiveFailureCondition(cluster, authError)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonBackupCredentialsInvalid)))
+
+		cluster = newCluster(*condition)
+		condition = getArchiveFailureCondition(cluster, authError)
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonBackupCredentialsInvalid)))
+	})
+
+	It("reports invalid credentials when the authentication error is not on the last line", func() {
+		authErrorWithTraceback := newBarmanCloudError(
+			"2024-11-05 09:14:27,402 [2134] ERROR: Barman cloud WAL archiver exception: "+
+				"Server failed to authenticate the request. ErrorCode:AuthenticationFailed",
+			"Traceback (most recent call last):",
+			"  File \"/usr/local/lib/python3.11/site-packages/barman/cloud.py\", line 1043, in upload_fileobj",
+			"barman.exceptions.BarmanException: exit status 1",
+		)
+		Expect(authErrorWithTraceback.Error()).ToNot(ContainSubstring("AuthenticationFailed"))
+
+		cluster := newCluster(*getArchiveFailureCondition(newCluster(), authErrorWithTraceback))
+		condition := getArchiveFailureCondition(cluster, authErrorWithTraceback)
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonBackupCredentialsInvalid)))
+	})
+
+	It("doesn't classify again the message of a previous failure", func() {
+		cluster := newCluster(metav1.Condition{
+			Type:    string(apiv1.ConditionContinuousArchiving),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(apiv1.ConditionReasonContinuousArchivingFailing),
+			Message: "unexpected failure invoking barman-cloud-wal-archive: exit status 1: " + authErrorOutput,
+		})
+
+		condition := getArchiveFailureCondition(cluster, authError)
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonContinuousArchivingFailing)))
+	})
+
+	It("doesn't report invalid credentials after archiving worked", func() {
+		cluster := newCluster(metav1.Condition{
+			Type:   string(apiv1.ConditionContinuousArchiving),
+			Status: metav1.ConditionTrue,
+			Reason: string(apiv1.ConditionReasonContinuousArchivingSuccess),
+		})
+
+		condition := getArchiveFailureCondition(cluster, authError)
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonContinuousArchivingFailing)))
+	})
+
+	It("reports a generic failure for non authentication errors", func() {
+		cluster := newCluster(*getArchiveFailureCondition(newCluster(), authError))
+
+		condition := getArchiveFailureCondition(cluster, newBarmanCloudError(
+			"2024-11-05 09:14:27,402 [2134] ERROR: Barman cloud WAL archiver exception: "+
+				"Could not connect to the endpoint URL: \"https://s3.eu-west-1.amazonaws.com/wal\""))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonContinuousArchivingFailing)))
+	})
+
+	It("classifies the error output of barman-cloud-wal-archive, not its exit status", func() {
+		Expect(isAuthenticationFailure(getFailureOutput(authError))).To(BeTrue())
+		Expect(isAuthenticationFailure(getFailureOutput(newBarmanCloudError()))).To(BeFalse())
+		Expect(getFailureOutput(errSwitchoverInProgress)).To(Equal(errSwitchoverInProgress.Error()))
+	})
+})
@@ -183,7 +183,8 @@ func run(ctx context.Context, pgData string, podName string, args []string) erro
 	}
 	if postgres.IsWALFile(walName) {
 		// If this is a regular WAL file, we try to prefetch
-		if walFilesList, err = gatherWALFilesToRestore(walName, maxParallel); err != nil {
+		walFilesList, err = gatherWALFilesToRestore(walName, maxParallel, getWALSegmentSize(cluster))
+		if err != nil {
 			return fmt.Errorf("while generating the list of WAL files to restore: %w", err)
 		}
 	} else {
@@ -341,33 +342,45 @@ func GetRecoverConfiguration(
 		if externalCluster.BarmanObjectStore == nil {
 			return "", nil, nil, ErrNoBackupConfigured
 		}
-		configuration := externalCluster.BarmanObjectStore
+		configuration := externalCluster.GetWALObjectStore()
 		if configuration.EndpointCA != nil && configuration.BarmanCredentials.AWS != nil {
 			env = append(env, fmt.Sprintf("AWS_CA_BUNDLE=%s", postgres.BarmanRestoreEndpointCACertificateLocation))
 		} else if configuration.EndpointCA != nil && configuration.BarmanCredentials.Azure != nil {
 			env = append(env, fmt.Sprintf("REQUESTS_CA_BUNDLE=%s", postgres.BarmanRestoreEndpointCACertificateLocation))
 		}
-		return externalCluster.Name, env, externalCluster.BarmanObjectStore, nil
+		return externalCluster.Name, env, configuration, nil
 	}
 
 	// Otherwise, let's use the object store which we are using to
 	// back up this cluster
 	if cluster.Spec.Backup != nil && cluster.Spec.Backup.BarmanObjectStore != nil {
-		configuration := cluster.Spec.Backup.BarmanObjectStore
+		configuration := cluster.GetBackupConfiguration().GetWALObjectStore()
 		if configuration.EndpointCA != nil && configuration.BarmanCredentials.AWS != nil {
 			env = append(env, fmt.Sprintf("AWS_CA_BUNDLE=%s", postgres.BarmanBackupEndpointCACertificateLocation))
 		} else if configuration.EndpointCA != nil && configuration.BarmanCredentials.Azure != nil {
 			env = append(env, fmt.Sprintf("REQUESTS_CA_BUNDLE=%s", postgres.BarmanBackupEndpointCACertificateLocation))
 		}
-		return cluster.Name, env, cluster.Spec.Backup.BarmanObjectStore, nil
+		return cluster.Name, env, configuration, nil
 	}
 
 	return "", nil, nil, ErrNoBackupConfigured
 }
 
+// getWALSegmentSize returns the size, in bytes, of the WAL segments
+// chosen at bootstrap, or nil when the PostgreSQL default is used
+func getWALSegmentSize(cluster *apiv1.Cluster) *int64 {
+	if cluster.Spec.Bootstrap == nil || cluster.Spec.Bootstrap.InitDB == nil ||
+		cluster.Spec.Bootstrap.InitDB.WalSegmentSize == 0 {
+		return nil
+	}
+
+	segmentSize := int64(cluster.Spec.Bootstrap.InitDB.WalSegmentSize) * 1024 * 1024
+	return &segmentSize
+}
+
 // gatherWALFilesToRestore files a list of possible WAL files to restore, always
 // including as the first one the requested WAL file
-func gatherWALFilesToRestore(walName string, parallel int) (walList []string, err error) {
+func gatherWALFilesToRestore(walName string, parallel int, segmentSize *int64) (walList []string, err error) {
 	var segment postgres.Segment
 
 	segment, err = postgres.SegmentFromName(walName)
@@ -378,9 +391,9 @@ func gatherWALFilesToRestore(walName string, parallel int) (walList []string, er
 		// Let's just avoid prefetching in this case
 		return []string{walName}, nil
 	}
-	// NextSegments would accept postgresVersion and segmentSize,
-	// but we do not have this info here, so we pass nil.
-	segmentList := segment.NextSegments(parallel, nil, nil)
+	// NextSegments would accept postgresVersion too, but we do not have
+	// this info here, so we pass nil.
+	segmentList := segment.NextSegments(parallel, nil, segmentSize)
 	walList = make([]string, len(segmentList))
 	for idx := range segmentList {
 		walList[idx] = segmentList[idx].Name()
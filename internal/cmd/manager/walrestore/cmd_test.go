@@ -110,3 +110,98 @@ var _ = Describe("Function isStreamingAvailable", func() {
 		Expect(isStreamingAvailable(&cluster, "primaryPod")).To(BeTrue())
 	})
 })
+
+var _ = Describe("Function GetRecoverConfiguration", func() {
+	dataObjectStore := &apiv1.BarmanObjectStoreConfiguration{
+		DestinationPath: "s3://data/",
+	}
+	walObjectStore := &apiv1.BarmanObjectStoreConfiguration{
+		DestinationPath: "s3://wal/",
+	}
+
+	It("fetches the WAL files from the WAL object store of the cluster", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{
+					BarmanObjectStore: dataObjectStore,
+					WALObjectStore:    walObjectStore,
+				},
+			},
+		}
+		cluster.Name = "cluster-example"
+
+		name, _, configuration, err := GetRecoverConfiguration(cluster, "cluster-example-2")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(name).To(Equal("cluster-example"))
+		Expect(configuration.DestinationPath).To(Equal("s3://wal/"))
+	})
+
+	It("fetches the WAL files from the WAL object store of the source of a replica cluster", func() {
+		cluster := &apiv1.Cluster{
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+			},
+			Spec: apiv1.ClusterSpec{
+				ExternalClusters: []apiv1.ExternalCluster{
+					{
+						Name:              "origin",
+						BarmanObjectStore: dataObjectStore,
+						WALObjectStore:    walObjectStore,
+					},
+				},
+				ReplicaCluster: &apiv1.ReplicaClusterConfiguration{
+					Enabled: ptr.To(true),
+					Source:  "origin",
+				},
+			},
+		}
+
+		name, _, configuration, err := GetRecoverConfiguration(cluster, "cluster-example-1")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(name).To(Equal("origin"))
+		Expect(configuration.DestinationPath).To(Equal("s3://wal/"))
+	})
+
+	It("uses the base backup object store when there's no WAL object store", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{
+					BarmanObjectStore: dataObjectStore,
+				},
+			},
+		}
+
+		_, _, configuration, err := GetRecoverConfiguration(cluster, "cluster-example-2")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(configuration.DestinationPath).To(Equal("s3://data/"))
+	})
+})
+
+var _ = Describe("Function gatherWALFilesToRestore", func() {
+	It("prefetches the following segments of a WAL file with the default size", func() {
+		Expect(gatherWALFilesToRestore("0000000100000001000000FE", 3, nil)).To(Equal([]string{
+			"0000000100000001000000FE",
+			"0000000100000001000000FF",
+			"000000010000000200000000",
+		}))
+	})
+
+	It("prefetches the following segments of a WAL file with the size chosen at bootstrap", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{WalSegmentSize: 64},
+				},
+			},
+		}
+		Expect(gatherWALFilesToRestore("00000001000000010000003E", 3, getWALSegmentSize(cluster))).To(Equal([]string{
+			"00000001000000010000003E",
+			"00000001000000010000003F",
+			"000000010000000200000000",
+		}))
+	})
+
+	It("only restores the requested file when it is not a WAL segment", func() {
+		Expect(gatherWALFilesToRestore("00000002.history", 3, nil)).To(Equal([]string{"00000002.history"}))
+	})
+})
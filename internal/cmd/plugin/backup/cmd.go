@@ -60,6 +60,8 @@ func (options backupCommandOptions) getOnlineConfiguration() *apiv1.OnlineConfig
 // NewCmd creates the new "backup" subcommand
 func NewCmd() *cobra.Command {
 	var backupName, backupTarget, backupMethod, online, immediateCheckpoint, waitForArchive string
+	var waitForCompletion bool
+	var timeout time.Duration
 
 	backupSubcommand := &cobra.Command{
 		Use:     "backup [cluster]",
@@ -72,6 +74,13 @@ func NewCmd() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			clusterName := args[0]
 
+			if timeout != 0 && !waitForCompletion {
+				return fmt.Errorf("timeout: can only be used together with --wait")
+			}
+			if timeout < 0 {
+				return fmt.Errorf("timeout: %s is not a valid timeout", timeout)
+			}
+
 			if len(backupName) == 0 {
 				backupName = fmt.Sprintf(
 					"%s-%s",
@@ -85,6 +94,7 @@ func NewCmd() *cobra.Command {
 				"",
 				string(apiv1.BackupTargetPrimary),
 				string(apiv1.BackupTargetStandby),
+				string(apiv1.BackupTargetStandbyOnly),
 			}
 			if !slices.Contains(allowedBackupTargets, backupTarget) {
 				return fmt.Errorf("backup-target: %s is not supported by the backup command", backupTarget)
@@ -127,7 +137,7 @@ func NewCmd() *cobra.Command {
 				return fmt.Errorf("while parsing the wait-for-archive value: %w", err)
 			}
 
-			return createBackup(
+			if err := createBackup(
 				cmd.Context(),
 				backupCommandOptions{
 					backupName:          backupName,
@@ -137,7 +147,15 @@ func NewCmd() *cobra.Command {
 					online:              parsedOnline,
 					immediateCheckpoint: parsedImmediateCheckpoint,
 					waitForArchive:      parsedWaitForArchive,
-				})
+				}); err != nil {
+				return err
+			}
+
+			if !waitForCompletion {
+				return nil
+			}
+
+			return waitForBackup(cmd.Context(), cmd.OutOrStdout(), backupName, timeout, waitPollInterval)
 		},
 	}
 
@@ -154,7 +172,7 @@ func NewCmd() *cobra.Command {
 		"t",
 		"",
 		"If present, will override the backup target defined in cluster, "+
-			"valid values are primary and prefer-standby.",
+			"valid values are primary, prefer-standby and standby.",
 	)
 	backupSubcommand.Flags().StringVarP(
 		&backupMethod,
@@ -188,6 +206,15 @@ func NewCmd() *cobra.Command {
 			optionalAcceptedValues,
 	)
 
+	backupSubcommand.Flags().BoolVar(&waitForCompletion, "wait", false,
+		"Wait for the backup to complete, printing its progress. The command "+
+			"fails if the backup fails or doesn't complete within the timeout")
+	backupSubcommand.Flags().DurationVar(&timeout, "timeout", 0,
+		"The maximum time to wait for the backup to complete, "+
+			"used together with --wait. Defaults to waiting forever")
+
+	backupSubcommand.AddCommand(newUsageCmd())
+
 	return backupSubcommand
 }
 
@@ -0,0 +1,394 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cheynewallace/tabby"
+	"github.com/cloudnative-pg/barman-cloud/pkg/catalog"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// backupUsage is the space used by a base backup in the object store
+type backupUsage struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name,omitempty"`
+	EndTime   *time.Time `json:"endTime,omitempty"`
+	BeginWal  string     `json:"beginWal,omitempty"`
+	SizeBytes *int64     `json:"sizeBytes,omitempty"`
+}
+
+// objectStoreUsage is the space used by the backups of a cluster
+// in the object store
+type objectStoreUsage struct {
+	ClusterName     string        `json:"clusterName"`
+	DestinationPath string        `json:"destinationPath"`
+	ServerName      string        `json:"serverName"`
+	Backups         []backupUsage `json:"backups"`
+
+	// BaseBackupsSizeBytes is the size of the base backups reporting it
+	BaseBackupsSizeBytes int64 `json:"baseBackupsSizeBytes"`
+
+	// WALSegments is the number of WAL segments archived since the
+	// beginning of the oldest base backup
+	WALSegments int64 `json:"walSegments"`
+
+	// EstimatedWALSizeBytes is the uncompressed size of the archived WAL segments
+	EstimatedWALSizeBytes int64 `json:"estimatedWalSizeBytes"`
+
+	// EstimatedTotalSizeBytes is the sum of the size of the base backups
+	// and the estimated size of the WAL archive
+	EstimatedTotalSizeBytes int64 `json:"estimatedTotalSizeBytes"`
+
+	// GrowthBytesPerDay is the daily growth of the base backups, computed
+	// between the oldest and the newest backups reporting their size
+	GrowthBytesPerDay *int64 `json:"growthBytesPerDay,omitempty"`
+}
+
+// barmanBackupSizes contains the sizes reported by barman-cloud-backup-list,
+// which are not part of the barman catalog
+type barmanBackupSizes struct {
+	List []struct {
+		ID   string `json:"backup_id"`
+		Size *int64 `json:"size"`
+	} `json:"backups_list"`
+}
+
+func newUsageCmd() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "usage [cluster]",
+		Short: "Report the space used by the backups of a PostgreSQL Cluster in the object store",
+		Args:  plugin.RequiresArguments(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return plugin.CompleteClusters(cmd.Context(), args, toComplete), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format := plugin.OutputFormat(output)
+			switch format {
+			case plugin.OutputFormatText, plugin.OutputFormatJSON, plugin.OutputFormatYAML:
+			default:
+				return fmt.Errorf("output: %s is not supported by the backup usage command", output)
+			}
+
+			return showUsage(cmd.Context(), args[0], format)
+		},
+	}
+
+	cmd.Flags().StringVarP(
+		&output,
+		"output",
+		"o",
+		string(plugin.OutputFormatText),
+		"Output format. One of text, json, or yaml",
+	)
+
+	return cmd
+}
+
+// showUsage prints the object store usage of the given cluster
+func showUsage(ctx context.Context, clusterName string, format plugin.OutputFormat) error {
+	var cluster apiv1.Cluster
+	if err := plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName},
+		&cluster,
+	); err != nil {
+		return fmt.Errorf("while getting cluster %s: %w", clusterName, err)
+	}
+
+	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
+		return fmt.Errorf("cluster %s has no object store configured", clusterName)
+	}
+
+	var primaryPod corev1.Pod
+	if err := plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: cluster.Status.CurrentPrimary},
+		&primaryPod,
+	); err != nil {
+		return fmt.Errorf("while getting the primary instance of cluster %s: %w", clusterName, err)
+	}
+
+	rawCatalog, err := getBackupCatalog(ctx, primaryPod)
+	if err != nil {
+		return err
+	}
+
+	lastArchivedWAL, err := getLastArchivedWAL(ctx, primaryPod)
+	if err != nil {
+		return err
+	}
+
+	usage, err := newObjectStoreUsage(&cluster, rawCatalog, lastArchivedWAL)
+	if err != nil {
+		return err
+	}
+
+	if format != plugin.OutputFormatText {
+		return plugin.Print(usage, format, os.Stdout)
+	}
+
+	usage.print()
+	return nil
+}
+
+// getBackupCatalog extracts the catalog of the backups from the object store
+// running barman-cloud-backup-list inside the given instance, which has
+// the credentials to access it
+func getBackupCatalog(ctx context.Context, pod corev1.Pod) (string, error) {
+	timeout := time.Minute
+	stdout, _, err := utils.ExecCommandWithRetry(
+		ctx,
+		kubernetes.NewForConfigOrDie(plugin.Config),
+		plugin.Config,
+		pod,
+		specs.PostgresContainerName,
+		&timeout,
+		utils.DefaultExecBackoff,
+		"/controller/manager", "show", "backup-list")
+	if err != nil {
+		return "", fmt.Errorf("while listing the backups from instance %s: %w", pod.Name, err)
+	}
+
+	return stdout, nil
+}
+
+// getLastArchivedWAL gets the name of the last WAL file archived by the
+// given instance
+func getLastArchivedWAL(ctx context.Context, pod corev1.Pod) (string, error) {
+	timeout := time.Second * 10
+	stdout, _, err := utils.ExecCommandWithRetry(
+		ctx,
+		kubernetes.NewForConfigOrDie(plugin.Config),
+		plugin.Config,
+		pod,
+		specs.PostgresContainerName,
+		&timeout,
+		utils.DefaultExecBackoff,
+		"psql", "-XAtc", "SELECT COALESCE(last_archived_wal, '') FROM pg_catalog.pg_stat_archiver")
+	if err != nil {
+		return "", fmt.Errorf("while getting the last archived WAL from instance %s: %w", pod.Name, err)
+	}
+
+	return strings.TrimSpace(stdout), nil
+}
+
+// newObjectStoreUsage computes the object store usage of a cluster given
+// the output of barman-cloud-backup-list and the last archived WAL file
+func newObjectStoreUsage(
+	cluster *apiv1.Cluster,
+	rawCatalog string,
+	lastArchivedWAL string,
+) (*objectStoreUsage, error) {
+	backupCatalog, err := catalog.NewCatalogFromBarmanCloudBackupList(rawCatalog)
+	if err != nil {
+		return nil, fmt.Errorf("while parsing the backup catalog: %w", err)
+	}
+
+	var sizes barmanBackupSizes
+	if err := json.Unmarshal([]byte(rawCatalog), &sizes); err != nil {
+		return nil, fmt.Errorf("while parsing the backup sizes: %w", err)
+	}
+	sizeByID := make(map[string]*int64, len(sizes.List))
+	for _, item := range sizes.List {
+		sizeByID[item.ID] = item.Size
+	}
+
+	barmanConfiguration := cluster.GetBackupConfiguration().BarmanObjectStore
+	usage := &objectStoreUsage{
+		ClusterName:     cluster.Name,
+		DestinationPath: barmanConfiguration.DestinationPath,
+		ServerName:      barmanConfiguration.ServerName,
+		Backups:         make([]backupUsage, 0, len(backupCatalog.List)),
+	}
+	if usage.ServerName == "" {
+		usage.ServerName = cluster.Name
+	}
+
+	for idx := range backupCatalog.List {
+		item := &backupCatalog.List[idx]
+		backup := backupUsage{
+			ID:        item.ID,
+			Name:      item.BackupName,
+			BeginWal:  item.BeginWal,
+			SizeBytes: sizeByID[item.ID],
+		}
+		if !item.EndTime.IsZero() {
+			endTime := item.EndTime
+			backup.EndTime = &endTime
+		}
+		if backup.SizeBytes != nil {
+			usage.BaseBackupsSizeBytes += *backup.SizeBytes
+		}
+		usage.Backups = append(usage.Backups, backup)
+	}
+	sort.SliceStable(usage.Backups, func(i, j int) bool {
+		return usage.Backups[i].ID < usage.Backups[j].ID
+	})
+
+	if len(usage.Backups) > 0 {
+		walSegmentSize := postgres.DefaultWALSegmentSize
+		if cluster.Spec.Bootstrap != nil && cluster.Spec.Bootstrap.InitDB != nil &&
+			cluster.Spec.Bootstrap.InitDB.WalSegmentSize != 0 {
+			walSegmentSize = int64(cluster.Spec.Bootstrap.InitDB.WalSegmentSize) * 1024 * 1024
+		}
+		usage.WALSegments = countWALSegments(
+			usage.Backups[0].BeginWal, lastArchivedWAL, walSegmentSize)
+		usage.EstimatedWALSizeBytes = usage.WALSegments * walSegmentSize
+	}
+	usage.EstimatedTotalSizeBytes = usage.BaseBackupsSizeBytes + usage.EstimatedWALSizeBytes
+	usage.GrowthBytesPerDay = getGrowthBytesPerDay(usage.Backups)
+
+	return usage, nil
+}
+
+// countWALSegments counts the WAL segments between the two passed
+// WAL file names, inclusive, ignoring timeline changes. Zero is
+// returned if either name is not a WAL segment name
+func countWALSegments(firstWAL, lastWAL string, walSegmentSize int64) int64 {
+	first, err := postgres.SegmentFromName(firstWAL)
+	if err != nil {
+		return 0
+	}
+	// The last archived file may be a partial WAL file or
+	// a backup history file
+	if len(lastWAL) > 24 {
+		lastWAL = lastWAL[:24]
+	}
+	last, err := postgres.SegmentFromName(lastWAL)
+	if err != nil {
+		return 0
+	}
+
+	segmentsPerLog := int64(0x100000000) / walSegmentSize
+	firstNumber := int64(first.Log)*segmentsPerLog + int64(first.Seg)
+	lastNumber := int64(last.Log)*segmentsPerLog + int64(last.Seg)
+	if lastNumber < firstNumber {
+		return 0
+	}
+
+	return lastNumber - firstNumber + 1
+}
+
+// getGrowthBytesPerDay computes the daily growth of the base backups,
+// between the oldest and the newest backups reporting their size
+func getGrowthBytesPerDay(backups []backupUsage) *int64 {
+	var oldest, newest *backupUsage
+	for idx := range backups {
+		backup := &backups[idx]
+		if backup.SizeBytes == nil || backup.EndTime == nil {
+			continue
+		}
+		if oldest == nil || backup.EndTime.Before(*oldest.EndTime) {
+			oldest = backup
+		}
+		if newest == nil || backup.EndTime.After(*newest.EndTime) {
+			newest = backup
+		}
+	}
+
+	if oldest == nil || newest == nil {
+		return nil
+	}
+
+	elapsed := newest.EndTime.Sub(*oldest.EndTime)
+	if elapsed < time.Hour {
+		return nil
+	}
+
+	growth := int64(float64(*newest.SizeBytes-*oldest.SizeBytes) / elapsed.Hours() * 24)
+	return &growth
+}
+
+func (usage *objectStoreUsage) print() {
+	summary := tabby.New()
+	summary.AddLine("Cluster:", usage.ClusterName)
+	summary.AddLine("Destination path:", usage.DestinationPath)
+	summary.AddLine("Server name:", usage.ServerName)
+	summary.Print()
+	fmt.Println()
+
+	if len(usage.Backups) == 0 {
+		fmt.Println("No backups found in the object store")
+		return
+	}
+
+	backups := tabby.New()
+	backups.AddHeader("Backup ID", "Name", "End time", "Begin WAL", "Size")
+	for _, backup := range usage.Backups {
+		endTime := ""
+		if backup.EndTime != nil {
+			endTime = backup.EndTime.Format(time.RFC3339)
+		}
+		backups.AddLine(backup.ID, backup.Name, endTime, backup.BeginWal, formatOptionalBytes(backup.SizeBytes))
+	}
+	backups.Print()
+	fmt.Println()
+
+	totals := tabby.New()
+	totals.AddLine("Base backups:", formatBytes(usage.BaseBackupsSizeBytes))
+	totals.AddLine("WAL archive (estimated, uncompressed):",
+		fmt.Sprintf("%s (%d segments)", formatBytes(usage.EstimatedWALSizeBytes), usage.WALSegments))
+	totals.AddLine("Total (estimated):", formatBytes(usage.EstimatedTotalSizeBytes))
+	totals.AddLine("Base backup growth per day:", formatOptionalBytes(usage.GrowthBytesPerDay))
+	totals.Print()
+}
+
+func formatOptionalBytes(value *int64) string {
+	if value == nil {
+		return "n/a"
+	}
+	return formatBytes(*value)
+}
+
+// formatBytes formats a size using the IEC units
+func formatBytes(value int64) string {
+	const unit = 1024
+	sign := ""
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+	if value < unit {
+		return fmt.Sprintf("%s%d B", sign, value)
+	}
+
+	div, exp := int64(unit), 0
+	for n := value / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%s%.1f %ciB", sign, float64(value)/float64(div), "KMGTPE"[exp])
+}
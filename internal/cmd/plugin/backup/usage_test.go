@@ -0,0 +1,126 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("backup usage", func() {
+	cluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+		Spec: apiv1.ClusterSpec{
+			Backup: &apiv1.BackupConfiguration{
+				BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+					DestinationPath: "s3://backups/",
+				},
+			},
+		},
+	}
+
+	const twoBackupsCatalog = `{"backups_list": [
+	{
+		"backup_id": "20241016T000000",
+		"backup_name": "second",
+		"begin_time": "Wed Oct 16 00:00:00 2024",
+		"end_time": "Wed Oct 16 00:10:00 2024",
+		"begin_wal": "000000010000000000000010",
+		"end_wal": "000000010000000000000010",
+		"size": 3221225472
+	},
+	{
+		"backup_id": "20241014T000000",
+		"backup_name": "first",
+		"begin_time": "Mon Oct 14 00:00:00 2024",
+		"end_time": "Mon Oct 14 00:10:00 2024",
+		"begin_wal": "000000010000000000000002",
+		"end_wal": "000000010000000000000002",
+		"size": 1073741824
+	}
+]}`
+
+	It("reports the usage of a store with zero backups", func() {
+		usage, err := newObjectStoreUsage(cluster, `{"backups_list": []}`, "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(usage.ServerName).To(Equal("cluster-example"))
+		Expect(usage.Backups).To(BeEmpty())
+		Expect(usage.EstimatedTotalSizeBytes).To(BeZero())
+		Expect(usage.GrowthBytesPerDay).To(BeNil())
+	})
+
+	It("reports the usage broken down by backup", func() {
+		usage, err := newObjectStoreUsage(cluster, twoBackupsCatalog, "000000010000000000000011")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(usage.Backups).To(HaveLen(2))
+		Expect(usage.Backups[0].ID).To(Equal("20241014T000000"))
+		Expect(*usage.Backups[0].SizeBytes).To(BeEquivalentTo(1 << 30))
+		Expect(usage.Backups[1].ID).To(Equal("20241016T000000"))
+		Expect(usage.BaseBackupsSizeBytes).To(BeEquivalentTo(4 << 30))
+
+		By("estimating the WAL archive since the oldest backup", func() {
+			Expect(usage.WALSegments).To(BeEquivalentTo(16))
+			Expect(usage.EstimatedWALSizeBytes).To(BeEquivalentTo(16 << 24))
+			Expect(usage.EstimatedTotalSizeBytes).To(BeEquivalentTo(4<<30 + 16<<24))
+		})
+
+		By("computing the daily growth", func() {
+			Expect(usage.GrowthBytesPerDay).ToNot(BeNil())
+			Expect(*usage.GrowthBytesPerDay).To(BeEquivalentTo(1 << 30))
+		})
+	})
+
+	It("handles backups not reporting their size", func() {
+		usage, err := newObjectStoreUsage(cluster, `{"backups_list": [{
+			"backup_id": "20241014T000000",
+			"begin_time": "Mon Oct 14 00:00:00 2024",
+			"end_time": "Mon Oct 14 00:10:00 2024",
+			"begin_wal": "000000010000000000000002"
+		}]}`, "000000010000000000000002.00000028.backup")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(usage.Backups[0].SizeBytes).To(BeNil())
+		Expect(usage.BaseBackupsSizeBytes).To(BeZero())
+		Expect(usage.WALSegments).To(BeEquivalentTo(1))
+		Expect(usage.GrowthBytesPerDay).To(BeNil())
+	})
+
+	DescribeTable("counts the WAL segments",
+		func(first, last string, segmentSize int64, expected int64) {
+			Expect(countWALSegments(first, last, segmentSize)).To(Equal(expected))
+		},
+		Entry("same segment", "000000010000000000000002", "000000010000000000000002", int64(1<<24), int64(1)),
+		Entry("across log files", "0000000100000000000000FF", "000000020000000100000001", int64(1<<24), int64(3)),
+		Entry("bigger segments", "000000010000000000000000", "000000010000000100000000", int64(1<<26), int64(65)),
+		Entry("invalid names", "", "000000010000000000000002", int64(1<<24), int64(0)),
+		Entry("history file", "000000010000000000000002", "00000002.history", int64(1<<24), int64(0)),
+	)
+
+	DescribeTable("formats sizes",
+		func(value int64, expected string) {
+			Expect(formatBytes(value)).To(Equal(expected))
+		},
+		Entry("bytes", int64(512), "512 B"),
+		Entry("kibibytes", int64(1536), "1.5 KiB"),
+		Entry("gibibytes", int64(3<<30), "3.0 GiB"),
+		Entry("negative", int64(-2<<20), "-2.0 MiB"),
+	)
+})
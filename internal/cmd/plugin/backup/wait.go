@@ -0,0 +1,121 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+// waitPollInterval is the interval between two checks of the
+// status of the backup being waited for
+const waitPollInterval = 2 * time.Second
+
+// errBackupFailed is returned when the backup being waited for fails
+var errBackupFailed = errors.New("backup failed")
+
+// waitForBackup waits for the passed backup to complete, printing every
+// change of its phase. It returns an error when the backup fails or doesn't
+// complete within the timeout. A zero timeout means waiting forever
+func waitForBackup(
+	ctx context.Context,
+	out io.Writer,
+	backupName string,
+	timeout time.Duration,
+	pollInterval time.Duration,
+) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var backup apiv1.Backup
+	var lastPhase apiv1.BackupPhase
+	err := wait.PollUntilContextCancel(ctx, pollInterval, true,
+		func(ctx context.Context) (bool, error) {
+			if err := plugin.Client.Get(
+				ctx,
+				client.ObjectKey{Namespace: plugin.Namespace, Name: backupName},
+				&backup,
+			); err != nil {
+				return false, err
+			}
+
+			if backup.Status.Phase != lastPhase {
+				lastPhase = backup.Status.Phase
+				_, _ = fmt.Fprintf(out, "backup/%s phase: %s\n", backupName, lastPhase)
+			}
+
+			switch backup.Status.Phase {
+			case apiv1.BackupPhaseCompleted:
+				return true, nil
+			case apiv1.BackupPhaseFailed:
+				return false, fmt.Errorf("%w: backup/%s: %s", errBackupFailed, backupName, backup.Status.Error)
+			default:
+				return false, nil
+			}
+		})
+	if errors.Is(err, errBackupFailed) {
+		return err
+	}
+	if wait.Interrupted(err) {
+		if lastPhase == "" {
+			lastPhase = apiv1.BackupPhasePending
+		}
+		return fmt.Errorf("timeout waiting for backup/%s to complete, current phase: %s", backupName, lastPhase)
+	}
+	if err != nil {
+		return fmt.Errorf("while waiting for backup/%s: %w", backupName, err)
+	}
+
+	printBackupResult(out, &backup)
+	return nil
+}
+
+// printBackupResult prints the name and the location of a completed backup
+func printBackupResult(out io.Writer, backup *apiv1.Backup) {
+	_, _ = fmt.Fprintf(out, "backup/%s completed\n", backup.Name)
+
+	if backup.Status.BackupID != "" {
+		_, _ = fmt.Fprintf(out, "Backup ID: %s\n", backup.Status.BackupID)
+	}
+	if backup.Status.DestinationPath != "" {
+		_, _ = fmt.Fprintf(out, "Destination path: %s\n", backup.Status.DestinationPath)
+	}
+	if backup.Status.ServerName != "" {
+		_, _ = fmt.Fprintf(out, "Server name: %s\n", backup.Status.ServerName)
+	}
+
+	snapshots := make([]string, 0, len(backup.Status.BackupSnapshotStatus.Elements))
+	for _, element := range backup.Status.BackupSnapshotStatus.Elements {
+		snapshots = append(snapshots, element.Name)
+	}
+	if len(snapshots) > 0 {
+		_, _ = fmt.Fprintf(out, "Volume snapshots: %s\n", strings.Join(snapshots, ", "))
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("waiting for a backup", func() {
+	const (
+		namespace    = "default"
+		backupName   = "cluster-example-backup"
+		pollInterval = 10 * time.Millisecond
+	)
+
+	BeforeEach(func() {
+		plugin.Namespace = namespace
+		plugin.Client = fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithStatusSubresource(&apiv1.Backup{}).
+			WithObjects(&apiv1.Backup{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      backupName,
+				},
+				Spec: apiv1.BackupSpec{
+					Cluster: apiv1.LocalObjectReference{Name: "cluster-example"},
+				},
+			}).
+			Build()
+	})
+
+	setStatus := func(ctx context.Context, status apiv1.BackupStatus) {
+		var backup apiv1.Backup
+		Expect(plugin.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: backupName}, &backup)).
+			To(Succeed())
+		backup.Status = status
+		Expect(plugin.Client.Status().Update(ctx, &backup)).To(Succeed())
+	}
+
+	startWaiting := func(ctx context.Context, out *bytes.Buffer, timeout time.Duration) chan error {
+		done := make(chan error, 1)
+		go func() {
+			defer GinkgoRecover()
+			done <- waitForBackup(ctx, out, backupName, timeout, pollInterval)
+		}()
+		return done
+	}
+
+	It("blocks until the backup is completed and reports the result", func(ctx SpecContext) {
+		var out bytes.Buffer
+		done := startWaiting(ctx, &out, 0)
+
+		setStatus(ctx, apiv1.BackupStatus{Phase: apiv1.BackupPhaseRunning})
+		Consistently(done, 100*time.Millisecond).ShouldNot(Receive())
+
+		setStatus(ctx, apiv1.BackupStatus{
+			Phase:           apiv1.BackupPhaseCompleted,
+			BackupID:        "20241015T120000",
+			DestinationPath: "s3://backups/",
+		})
+		Eventually(done).Should(Receive(BeNil()))
+
+		Expect(out.String()).To(ContainSubstring("backup/" + backupName + " phase: running"))
+		Expect(out.String()).To(ContainSubstring("backup/" + backupName + " completed"))
+		Expect(out.String()).To(ContainSubstring("Backup ID: 20241015T120000"))
+		Expect(out.String()).To(ContainSubstring("Destination path: s3://backups/"))
+	})
+
+	It("fails when the backup fails", func(ctx SpecContext) {
+		var out bytes.Buffer
+		done := startWaiting(ctx, &out, 0)
+
+		setStatus(ctx, apiv1.BackupStatus{
+			Phase: apiv1.BackupPhaseFailed,
+			Error: "can't connect to the object store",
+		})
+
+		var err error
+		Eventually(done).Should(Receive(&err))
+		Expect(err).To(MatchError(errBackupFailed))
+		Expect(err.Error()).To(ContainSubstring("can't connect to the object store"))
+	})
+
+	It("fails when the backup doesn't complete within the timeout", func(ctx SpecContext) {
+		var out bytes.Buffer
+		setStatus(ctx, apiv1.BackupStatus{Phase: apiv1.BackupPhaseRunning})
+
+		err := waitForBackup(ctx, &out, backupName, 50*time.Millisecond, pollInterval)
+		Expect(err).To(MatchError(ContainSubstring("timeout waiting for backup/" + backupName)))
+		Expect(err.Error()).To(ContainSubstring("current phase: running"))
+	})
+})
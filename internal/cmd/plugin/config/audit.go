@@ -0,0 +1,138 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cheynewallace/tabby"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// settingsQuery extracts the effective configuration of an instance
+const settingsQuery = "SELECT name, setting, COALESCE(unit, '') FROM pg_catalog.pg_settings ORDER BY name"
+
+// audit checks the effective configuration of a cluster against a policy
+func audit(ctx context.Context, clusterName, policyFile string, format plugin.OutputFormat) error {
+	rawPolicy, err := os.ReadFile(policyFile) // #nosec
+	if err != nil {
+		return fmt.Errorf("while reading the policy: %w", err)
+	}
+	auditPolicy, err := parsePolicy(rawPolicy)
+	if err != nil {
+		return err
+	}
+
+	var cluster apiv1.Cluster
+	if err := plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName},
+		&cluster,
+	); err != nil {
+		return fmt.Errorf("while getting cluster %s: %w", clusterName, err)
+	}
+
+	var primaryPod corev1.Pod
+	if err := plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: cluster.Status.CurrentPrimary},
+		&primaryPod,
+	); err != nil {
+		return fmt.Errorf("while getting the primary instance of cluster %s: %w", clusterName, err)
+	}
+
+	rawSettings, err := getSettings(ctx, primaryPod)
+	if err != nil {
+		return err
+	}
+
+	violations := auditPolicy.check(parseSettings(rawSettings))
+
+	if format != plugin.OutputFormatText {
+		if err := plugin.Print(violations, format, os.Stdout); err != nil {
+			return err
+		}
+	} else {
+		printViolations(clusterName, violations)
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("cluster %s doesn't comply with the policy: %d violation(s) found",
+			clusterName, len(violations))
+	}
+
+	return nil
+}
+
+// getSettings reads the content of pg_settings from the given instance
+func getSettings(ctx context.Context, pod corev1.Pod) (string, error) {
+	timeout := time.Second * 10
+	stdout, _, err := utils.ExecCommandWithRetry(
+		ctx,
+		kubernetes.NewForConfigOrDie(plugin.Config),
+		plugin.Config,
+		pod,
+		specs.PostgresContainerName,
+		&timeout,
+		utils.DefaultExecBackoff,
+		"psql", "-XAt", "-F", "\t", "-c", settingsQuery)
+	if err != nil {
+		return "", fmt.Errorf("while reading the configuration of instance %s: %w", pod.Name, err)
+	}
+
+	return stdout, nil
+}
+
+// parseSettings parses the output of settingsQuery
+func parseSettings(rawSettings string) map[string]setting {
+	result := make(map[string]setting)
+	for _, line := range strings.Split(rawSettings, "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		result[fields[0]] = setting{Value: fields[1], Unit: fields[2]}
+	}
+
+	return result
+}
+
+// printViolations prints the violations in a human-readable format
+func printViolations(clusterName string, violations []violation) {
+	if len(violations) == 0 {
+		fmt.Printf("Cluster %s complies with the policy\n", clusterName)
+		return
+	}
+
+	table := tabby.New()
+	table.AddHeader("Parameter", "Value", "Violation")
+	for _, item := range violations {
+		table.AddLine(item.Parameter, item.Value, item.Message)
+	}
+	table.Print()
+}
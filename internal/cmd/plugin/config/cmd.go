@@ -0,0 +1,78 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+// NewCmd creates the new "config" command
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "config",
+		Short:   `PostgreSQL configuration related commands`,
+		GroupID: plugin.GroupIDCluster,
+	}
+
+	cmd.AddCommand(newAuditCmd())
+
+	return cmd
+}
+
+func newAuditCmd() *cobra.Command {
+	var policyFile string
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "audit [cluster]",
+		Short: `Compare the effective configuration of a cluster against a policy`,
+		Long: `Reads the effective configuration of the primary instance from pg_settings ` +
+			`and checks it against the rules of a policy file, printing the violations. ` +
+			`The command exits with a non-zero status when the cluster doesn't comply ` +
+			`with the policy.`,
+		Args: plugin.RequiresArguments(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return plugin.CompleteClusters(cmd.Context(), args, toComplete), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format := plugin.OutputFormat(output)
+			switch format {
+			case plugin.OutputFormatText, plugin.OutputFormatJSON, plugin.OutputFormatYAML:
+			default:
+				return fmt.Errorf("output: %s is not supported by the config audit command", output)
+			}
+
+			return audit(cmd.Context(), args[0], policyFile, format)
+		},
+	}
+
+	cmd.Flags().StringVar(&policyFile, "policy", "", "The file containing the policy to check")
+	_ = cmd.MarkFlagRequired("policy")
+	cmd.Flags().StringVarP(
+		&output,
+		"output",
+		"o",
+		string(plugin.OutputFormatText),
+		"Output format. One of text, json, or yaml",
+	)
+
+	return cmd
+}
@@ -0,0 +1,297 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// policy is a set of rules the configuration of a cluster must comply with
+type policy struct {
+	Rules []policyRule `json:"rules"`
+}
+
+// policyRule is a constraint on the value of one or more parameters
+type policyRule struct {
+	// Parameter is the name of the parameter, and may contain the
+	// wildcards supported by path.Match
+	Parameter string `json:"parameter"`
+
+	// Value is the required value of the parameter
+	Value *string `json:"value,omitempty"`
+
+	// Forbidden is the list of values the parameter must not have
+	Forbidden []string `json:"forbidden,omitempty"`
+
+	// Min is the lowest accepted value of the parameter. When expressed
+	// without a unit, it uses the unit of the parameter in pg_settings
+	Min *string `json:"min,omitempty"`
+
+	// Max is the highest accepted value of the parameter. When expressed
+	// without a unit, it uses the unit of the parameter in pg_settings
+	Max *string `json:"max,omitempty"`
+}
+
+// setting is a row of pg_settings
+type setting struct {
+	Value string
+	Unit  string
+}
+
+// violation is a parameter whose value doesn't comply with the policy
+type violation struct {
+	Parameter string `json:"parameter"`
+	Value     string `json:"value"`
+	Message   string `json:"message"`
+}
+
+// unitFactors maps the units used by PostgreSQL to a common base,
+// which is bytes for memory and milliseconds for time
+var unitFactors = map[string]struct {
+	kind   string
+	factor float64
+}{
+	"B":   {kind: "memory", factor: 1},
+	"kB":  {kind: "memory", factor: 1 << 10},
+	"MB":  {kind: "memory", factor: 1 << 20},
+	"GB":  {kind: "memory", factor: 1 << 30},
+	"TB":  {kind: "memory", factor: 1 << 40},
+	"us":  {kind: "time", factor: 0.001},
+	"ms":  {kind: "time", factor: 1},
+	"s":   {kind: "time", factor: 1000},
+	"min": {kind: "time", factor: 60 * 1000},
+	"h":   {kind: "time", factor: 60 * 60 * 1000},
+	"d":   {kind: "time", factor: 24 * 60 * 60 * 1000},
+}
+
+var quantityRegex = regexp.MustCompile(`^\s*(-?[0-9]+(?:\.[0-9]+)?)\s*([a-zA-Z]*)\s*$`)
+
+// parsePolicy parses and validates a policy
+func parsePolicy(content []byte) (*policy, error) {
+	var result policy
+	if err := yaml.UnmarshalStrict(content, &result); err != nil {
+		return nil, fmt.Errorf("while parsing the policy: %w", err)
+	}
+
+	for idx, rule := range result.Rules {
+		if rule.Parameter == "" {
+			return nil, fmt.Errorf("rule %d of the policy has no parameter", idx+1)
+		}
+		if _, err := path.Match(rule.Parameter, ""); err != nil {
+			return nil, fmt.Errorf("invalid parameter pattern %q: %w", rule.Parameter, err)
+		}
+		if rule.Value == nil && len(rule.Forbidden) == 0 && rule.Min == nil && rule.Max == nil {
+			return nil, fmt.Errorf("rule for %q has no constraint", rule.Parameter)
+		}
+	}
+
+	return &result, nil
+}
+
+// check returns the violations of the policy in the given settings
+func (p *policy) check(settings map[string]setting) []violation {
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var result []violation
+	for _, rule := range p.Rules {
+		matched := false
+		for _, name := range names {
+			if ok, _ := path.Match(rule.Parameter, name); !ok {
+				continue
+			}
+			matched = true
+			result = append(result, rule.check(name, settings[name])...)
+		}
+
+		if !matched && !strings.ContainsAny(rule.Parameter, "*?[") {
+			result = append(result, violation{
+				Parameter: rule.Parameter,
+				Message:   "unknown parameter",
+			})
+		}
+	}
+
+	return result
+}
+
+// check returns the violations of the rule for a certain parameter
+func (rule policyRule) check(name string, value setting) []violation {
+	var result []violation
+	addViolation := func(format string, args ...interface{}) {
+		result = append(result, violation{
+			Parameter: name,
+			Value:     value.String(),
+			Message:   fmt.Sprintf(format, args...),
+		})
+	}
+
+	if rule.Value != nil && !value.equals(*rule.Value) {
+		addViolation("must be %s", *rule.Value)
+	}
+
+	for _, forbidden := range rule.Forbidden {
+		if value.equals(forbidden) {
+			addViolation("must not be %s", forbidden)
+		}
+	}
+
+	for _, bound := range []struct {
+		limit    *string
+		violated func(value, limit float64) bool
+		message  string
+	}{
+		{
+			limit:    rule.Min,
+			violated: func(value, limit float64) bool { return value < limit },
+			message:  "must be at least %s",
+		},
+		{
+			limit:    rule.Max,
+			violated: func(value, limit float64) bool { return value > limit },
+			message:  "must be at most %s",
+		},
+	} {
+		if bound.limit == nil {
+			continue
+		}
+
+		current, err := value.normalize()
+		if err != nil {
+			addViolation("not a numeric parameter")
+			continue
+		}
+		limit, err := normalizeQuantity(*bound.limit, value.Unit)
+		if err != nil {
+			addViolation("invalid limit %s: %v", *bound.limit, err)
+			continue
+		}
+		if bound.violated(current, limit) {
+			addViolation(bound.message, *bound.limit)
+		}
+	}
+
+	return result
+}
+
+// String implements the fmt.Stringer interface
+func (s setting) String() string {
+	if s.Unit == "" {
+		return s.Value
+	}
+	return fmt.Sprintf("%s (%s)", s.Value, s.Unit)
+}
+
+// equals checks whether the setting has the given value, comparing
+// quantities after converting them to the same unit
+func (s setting) equals(value string) bool {
+	if strings.EqualFold(s.Value, value) {
+		return true
+	}
+
+	current, err := s.normalize()
+	if err != nil {
+		return false
+	}
+	expected, err := normalizeQuantity(value, s.Unit)
+	if err != nil {
+		return false
+	}
+
+	return current == expected
+}
+
+// normalize converts the setting to the base unit
+func (s setting) normalize() (float64, error) {
+	value, err := strconv.ParseFloat(s.Value, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	_, factor, err := parseUnit(s.Unit)
+	if err != nil {
+		return 0, err
+	}
+
+	return value * factor, nil
+}
+
+// normalizeQuantity converts a quantity to the base unit. Quantities
+// without a unit are expressed in the unit of the parameter
+func normalizeQuantity(quantity string, settingUnit string) (float64, error) {
+	matches := quantityRegex.FindStringSubmatch(quantity)
+	if matches == nil {
+		return 0, fmt.Errorf("not a number")
+	}
+
+	value, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return 0, err
+	}
+
+	settingKind, settingFactor, err := parseUnit(settingUnit)
+	if err != nil {
+		return 0, err
+	}
+
+	if matches[2] == "" {
+		return value * settingFactor, nil
+	}
+
+	kind, factor, err := parseUnit(matches[2])
+	if err != nil {
+		return 0, err
+	}
+	if kind != settingKind {
+		return 0, fmt.Errorf("unit %s can't be used for this parameter", matches[2])
+	}
+
+	return value * factor, nil
+}
+
+// parseUnit parses a unit as reported by pg_settings, like "8kB"
+func parseUnit(unit string) (kind string, factor float64, err error) {
+	if unit == "" {
+		return "", 1, nil
+	}
+
+	multiplier := 1.0
+	digits := strings.IndexFunc(unit, func(r rune) bool { return r < '0' || r > '9' })
+	if digits > 0 {
+		if multiplier, err = strconv.ParseFloat(unit[:digits], 64); err != nil {
+			return "", 0, err
+		}
+		unit = unit[digits:]
+	}
+
+	unitFactor, ok := unitFactors[unit]
+	if !ok {
+		return "", 0, fmt.Errorf("unknown unit %s", unit)
+	}
+
+	return unitFactor.kind, multiplier * unitFactor.factor, nil
+}
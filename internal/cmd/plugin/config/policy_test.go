@@ -0,0 +1,126 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("config audit policy", func() {
+	settings := parseSettings("archive_mode\ton\t\n" +
+		"fsync\toff\t\n" +
+		"log_checkpoints\ton\t\n" +
+		"log_connections\toff\t\n" +
+		"max_connections\t100\t\n" +
+		"shared_buffers\t16384\t8kB\n" +
+		"statement_timeout\t0\tms\n")
+
+	check := func(rawPolicy string) []violation {
+		auditPolicy, err := parsePolicy([]byte(rawPolicy))
+		Expect(err).ToNot(HaveOccurred())
+		return auditPolicy.check(settings)
+	}
+
+	It("accepts a compliant configuration", func() {
+		Expect(check(`
+rules:
+- parameter: archive_mode
+  value: "on"
+- parameter: shared_buffers
+  min: 128MB
+  max: 1GB
+- parameter: max_connections
+  min: "50"
+`)).To(BeEmpty())
+	})
+
+	It("reports required and forbidden values", func() {
+		violations := check(`
+rules:
+- parameter: fsync
+  value: "on"
+- parameter: archive_mode
+  forbidden: ["on", "always"]
+`)
+		Expect(violations).To(HaveLen(2))
+		Expect(violations[0].Parameter).To(Equal("fsync"))
+		Expect(violations[0].Message).To(Equal("must be on"))
+		Expect(violations[1].Parameter).To(Equal("archive_mode"))
+		Expect(violations[1].Message).To(Equal("must not be on"))
+	})
+
+	It("supports wildcards", func() {
+		violations := check(`
+rules:
+- parameter: log_*
+  value: "on"
+`)
+		Expect(violations).To(HaveLen(1))
+		Expect(violations[0].Parameter).To(Equal("log_connections"))
+	})
+
+	It("converts the units of the limits", func() {
+		violations := check(`
+rules:
+- parameter: shared_buffers
+  min: 1GB
+- parameter: statement_timeout
+  value: 0s
+`)
+		Expect(violations).To(HaveLen(1))
+		Expect(violations[0].Parameter).To(Equal("shared_buffers"))
+		Expect(violations[0].Value).To(Equal("16384 (8kB)"))
+		Expect(violations[0].Message).To(Equal("must be at least 1GB"))
+	})
+
+	It("reports limits on non numeric parameters and incompatible units", func() {
+		violations := check(`
+rules:
+- parameter: fsync
+  max: "1"
+- parameter: shared_buffers
+  max: 10s
+`)
+		Expect(violations).To(HaveLen(2))
+		Expect(violations[0].Message).To(Equal("not a numeric parameter"))
+		Expect(violations[1].Message).To(ContainSubstring("can't be used for this parameter"))
+	})
+
+	It("reports unknown parameters, unless the rule uses a wildcard", func() {
+		violations := check(`
+rules:
+- parameter: not_existing
+  value: "on"
+- parameter: not_existing_*
+  value: "on"
+`)
+		Expect(violations).To(HaveLen(1))
+		Expect(violations[0].Message).To(Equal("unknown parameter"))
+	})
+
+	It("rejects invalid policies", func() {
+		_, err := parsePolicy([]byte(`rules: [{parameter: fsync}]`))
+		Expect(err).To(HaveOccurred())
+
+		_, err = parsePolicy([]byte(`rules: [{value: "on"}]`))
+		Expect(err).To(HaveOccurred())
+
+		_, err = parsePolicy([]byte(`rules: [{parameter: fsync, required: "on"}]`))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,74 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package copytable implements the command to copy the rows of a table
+// from a cluster to another one
+package copytable
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+// NewCmd creates the new "copy-table" command
+func NewCmd() *cobra.Command {
+	var dbName string
+	var destinationDBName string
+	var where string
+	var truncateFirst bool
+
+	cmd := &cobra.Command{
+		Use:   "copy-table [source cluster] [destination cluster] [schema.table]",
+		Short: "Copy the rows of a table from a cluster to another one",
+		Long: `Stream the rows of a table from the primary of the source cluster to the
+primary of the destination cluster, using COPY TO STDOUT and COPY FROM STDIN.
+The table must already exist in the destination database, with the same columns.
+The rows are loaded in a single transaction, that is rolled back if the copy fails.`,
+		GroupID: plugin.GroupIDDatabase,
+		Args:    plugin.RequiresArguments(3),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) >= 2 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return plugin.CompleteClusters(cmd.Context(), args, toComplete), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			schemaName, tableName := parseTableName(args[2])
+			if destinationDBName == "" {
+				destinationDBName = dbName
+			}
+
+			tc := newTableCopy(args[0], args[1], schemaName, tableName)
+			tc.sourceDatabase = dbName
+			tc.destinationDatabase = destinationDBName
+			tc.where = where
+			tc.truncateFirst = truncateFirst
+			return tc.run(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVar(&dbName, "dbname", "app",
+		"The database containing the table in the source cluster")
+	cmd.Flags().StringVar(&destinationDBName, "destination-dbname", "",
+		"The database containing the table in the destination cluster, defaults to the value of --dbname")
+	cmd.Flags().StringVar(&where, "where", "",
+		"A condition limiting the rows to be copied, i.e. \"created_at > now() - interval '1 day'\"")
+	cmd.Flags().BoolVar(&truncateFirst, "truncate-first", false,
+		"Truncate the table in the destination cluster before loading the rows")
+
+	return cmd
+}
@@ -0,0 +1,287 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package copytable
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/logical"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/psql"
+)
+
+// copyCompletedRegex matches the command tag returned by
+// PostgreSQL when COPY FROM STDIN is completed
+var copyCompletedRegex = regexp.MustCompile(`(?m)^COPY (\d+)$`)
+
+// column is a column of the copied table, as returned by json_agg
+type column struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// tableCopy copies the rows of a table from the primary instance
+// of a cluster to the primary instance of another one
+type tableCopy struct {
+	sourceCluster       string
+	destinationCluster  string
+	sourceDatabase      string
+	destinationDatabase string
+	schemaName          string
+	tableName           string
+
+	// where is an optional condition limiting the rows to be copied
+	where string
+
+	// truncateFirst empties the destination table before loading the rows
+	truncateFirst bool
+
+	// runSQL executes a SQL statement in the passed database of the
+	// primary instance of a cluster, returning its output
+	runSQL func(ctx context.Context, clusterName, dbName, sqlCommand string) (string, error)
+
+	// stream runs psql in the passed database of the primary instance
+	// of a cluster, connecting its standard input and output to the
+	// passed reader and writer
+	stream func(
+		ctx context.Context,
+		clusterName, dbName string,
+		args []string,
+		stdin io.Reader,
+		stdout io.Writer,
+	) error
+}
+
+// newTableCopy creates a new tableCopy for the passed table,
+// running psql inside the primary instances of the clusters
+func newTableCopy(sourceCluster, destinationCluster, schemaName, tableName string) *tableCopy {
+	return &tableCopy{
+		sourceCluster:       sourceCluster,
+		destinationCluster:  destinationCluster,
+		sourceDatabase:      "app",
+		destinationDatabase: "app",
+		schemaName:          schemaName,
+		tableName:           tableName,
+		runSQL: func(ctx context.Context, clusterName, dbName, sqlCommand string) (string, error) {
+			output, err := logical.RunSQLWithOutput(ctx, clusterName, dbName, sqlCommand)
+			return strings.TrimSpace(string(output)), err
+		},
+		stream: func(
+			ctx context.Context,
+			clusterName, dbName string,
+			args []string,
+			stdin io.Reader,
+			stdout io.Writer,
+		) error {
+			cmd, err := psql.NewCommand(ctx, psql.CommandOptions{
+				Replica:   false,
+				Namespace: plugin.Namespace,
+				PassStdin: stdin != nil,
+				Args:      append([]string{dbName}, args...),
+				Name:      clusterName,
+			})
+			if err != nil {
+				return err
+			}
+			return cmd.Stream(ctx, stdin, stdout)
+		},
+	}
+}
+
+// parseTableName splits a table name in the "schema.table"
+// format, defaulting to the public schema
+func parseTableName(name string) (string, string) {
+	if schemaName, tableName, found := strings.Cut(name, "."); found {
+		return schemaName, tableName
+	}
+	return "public", name
+}
+
+// qualifiedName returns the quoted name of the table, including its schema
+func (tc *tableCopy) qualifiedName() string {
+	return pq.QuoteIdentifier(tc.schemaName) + "." + pq.QuoteIdentifier(tc.tableName)
+}
+
+func (tc *tableCopy) run(ctx context.Context) error {
+	sourceColumns, err := tc.getColumns(ctx, tc.sourceCluster, tc.sourceDatabase)
+	if err != nil {
+		return err
+	}
+	destinationColumns, err := tc.getColumns(ctx, tc.destinationCluster, tc.destinationDatabase)
+	if err != nil {
+		return err
+	}
+	if err := compareColumns(sourceColumns, destinationColumns); err != nil {
+		return fmt.Errorf("table %s of cluster %s doesn't match table %s of cluster %s: %w",
+			tc.qualifiedName(), tc.sourceCluster, tc.qualifiedName(), tc.destinationCluster, err)
+	}
+
+	rows, err := tc.copyRows(ctx, sourceColumns)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Copied %d rows of table %s from cluster %s to cluster %s\n",
+		rows, tc.qualifiedName(), tc.sourceCluster, tc.destinationCluster)
+	return nil
+}
+
+// getColumns gets the columns of the table that can be copied,
+// in their physical order, from the primary instance of a cluster.
+// Generated columns are skipped, as they can't be loaded with COPY
+func (tc *tableCopy) getColumns(ctx context.Context, clusterName, dbName string) ([]column, error) {
+	output, err := tc.runSQL(ctx, clusterName, dbName, fmt.Sprintf(
+		"SELECT pg_catalog.json_agg(pg_catalog.json_build_object("+
+			"'name', a.attname, 'type', pg_catalog.format_type(a.atttypid, a.atttypmod)) ORDER BY a.attnum) "+
+			"FROM pg_catalog.pg_attribute a "+
+			"WHERE a.attrelid = pg_catalog.to_regclass(%s) AND a.attnum > 0 "+
+			"AND NOT a.attisdropped AND a.attgenerated = ''",
+		pq.QuoteLiteral(tc.qualifiedName())))
+	if err != nil {
+		return nil, fmt.Errorf("while getting the columns of table %s in cluster %s: %w",
+			tc.qualifiedName(), clusterName, err)
+	}
+	if output == "" {
+		return nil, fmt.Errorf("table %s not found in database %s of cluster %s",
+			tc.qualifiedName(), dbName, clusterName)
+	}
+
+	var columns []column
+	if err := json.Unmarshal([]byte(output), &columns); err != nil {
+		return nil, fmt.Errorf("while decoding the columns of table %s in cluster %s: %w",
+			tc.qualifiedName(), clusterName, err)
+	}
+	return columns, nil
+}
+
+// compareColumns checks that the source and the destination tables
+// have the same columns, with the same types
+func compareColumns(source, destination []column) error {
+	destinationTypes := make(map[string]string, len(destination))
+	for _, col := range destination {
+		destinationTypes[col.Name] = col.Type
+	}
+
+	var mismatches []string
+	for _, col := range source {
+		destinationType, found := destinationTypes[col.Name]
+		switch {
+		case !found:
+			mismatches = append(mismatches, fmt.Sprintf("column %s is missing in the destination", col.Name))
+		case destinationType != col.Type:
+			mismatches = append(mismatches, fmt.Sprintf("column %s is %s in the source and %s in the destination",
+				col.Name, col.Type, destinationType))
+		}
+		delete(destinationTypes, col.Name)
+	}
+	for name := range destinationTypes {
+		mismatches = append(mismatches, fmt.Sprintf("column %s is missing in the source", name))
+	}
+
+	if len(mismatches) == 0 {
+		return nil
+	}
+	sort.Strings(mismatches)
+	return fmt.Errorf("%s", strings.Join(mismatches, ", "))
+}
+
+// copyRows streams the rows from the source to the destination cluster,
+// returning the number of rows that have been loaded. The rows are loaded
+// in a single transaction, that is committed only if the source
+// cluster has successfully sent every row
+func (tc *tableCopy) copyRows(ctx context.Context, columns []column) (int64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	columnNames := make([]string, len(columns))
+	for i, col := range columns {
+		columnNames[i] = pq.QuoteIdentifier(col.Name)
+	}
+	columnList := strings.Join(columnNames, ", ")
+
+	reader, writer := io.Pipe()
+	sourceErrChan := make(chan error, 1)
+	go func() {
+		err := tc.writeScript(ctx, writer, columnList)
+		// Closing the pipe without the final COMMIT makes the
+		// destination psql roll back the transaction
+		_ = writer.CloseWithError(err)
+		sourceErrChan <- err
+	}()
+
+	var destinationOutput bytes.Buffer
+	destinationErr := tc.stream(ctx, tc.destinationCluster, tc.destinationDatabase,
+		[]string{"-X", "-v", "ON_ERROR_STOP=1"}, reader, &destinationOutput)
+	if destinationErr != nil {
+		// Stop the source psql, which may be still streaming rows
+		cancel()
+	}
+	_ = reader.Close()
+
+	if sourceErr := <-sourceErrChan; sourceErr != nil {
+		return 0, fmt.Errorf("while reading table %s from cluster %s: %w",
+			tc.qualifiedName(), tc.sourceCluster, sourceErr)
+	}
+	if destinationErr != nil {
+		return 0, fmt.Errorf("while loading table %s into cluster %s: %w",
+			tc.qualifiedName(), tc.destinationCluster, destinationErr)
+	}
+
+	matches := copyCompletedRegex.FindStringSubmatch(destinationOutput.String())
+	if matches == nil {
+		return 0, fmt.Errorf("unexpected output loading table %s into cluster %s: %s",
+			tc.qualifiedName(), tc.destinationCluster, destinationOutput.String())
+	}
+	return strconv.ParseInt(matches[1], 10, 64)
+}
+
+// writeScript writes the script loading the rows into the destination
+// cluster, streaming them from the source cluster
+func (tc *tableCopy) writeScript(ctx context.Context, writer io.Writer, columnList string) error {
+	var prologue strings.Builder
+	prologue.WriteString("BEGIN;\n")
+	if tc.truncateFirst {
+		fmt.Fprintf(&prologue, "TRUNCATE TABLE %s;\n", tc.qualifiedName())
+	}
+	fmt.Fprintf(&prologue, "COPY %s (%s) FROM STDIN;\n", tc.qualifiedName(), columnList)
+	if _, err := io.WriteString(writer, prologue.String()); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s", columnList, tc.qualifiedName())
+	if tc.where != "" {
+		query += " WHERE " + tc.where
+	}
+	if err := tc.stream(ctx, tc.sourceCluster, tc.sourceDatabase,
+		[]string{"-X", "-q", "-v", "ON_ERROR_STOP=1", "-c", fmt.Sprintf("COPY (%s) TO STDOUT", query)},
+		nil, writer); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(writer, "\\.\nCOMMIT;\n")
+	return err
+}
@@ -0,0 +1,190 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package copytable
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeCluster simulates the copied table in the primary instance of a cluster
+type fakeCluster struct {
+	columns []column
+	rows    []string
+
+	// sourceErr makes the cluster fail after having sent its rows
+	sourceErr error
+
+	// receivedScript is the last script loaded into the cluster
+	receivedScript string
+	// receivedSourceArgs are the psql arguments used to read the rows
+	receivedSourceArgs []string
+}
+
+// newFakeTableCopy creates a tableCopy between two fake clusters,
+// loading the rows into the destination only if the script is committed
+func newFakeTableCopy(clusters map[string]*fakeCluster) *tableCopy {
+	tc := newTableCopy("source", "destination", "public", "items")
+	tc.runSQL = func(_ context.Context, clusterName, _, _ string) (string, error) {
+		cluster := clusters[clusterName]
+		if cluster.columns == nil {
+			return "", nil
+		}
+		output, err := json.Marshal(cluster.columns)
+		return string(output), err
+	}
+	tc.stream = func(
+		_ context.Context,
+		clusterName, _ string,
+		args []string,
+		stdin io.Reader,
+		stdout io.Writer,
+	) error {
+		cluster := clusters[clusterName]
+		if stdin == nil {
+			cluster.receivedSourceArgs = args
+			for _, row := range cluster.rows {
+				if _, err := fmt.Fprintln(stdout, row); err != nil {
+					return err
+				}
+			}
+			return cluster.sourceErr
+		}
+
+		script, err := io.ReadAll(stdin)
+		cluster.receivedScript = string(script)
+		if err != nil {
+			return err
+		}
+
+		var loaded []string
+		inCopy := false
+		scanner := bufio.NewScanner(strings.NewReader(string(script)))
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case strings.HasPrefix(line, "TRUNCATE"):
+				cluster.rows = nil
+			case strings.HasPrefix(line, "COPY"):
+				inCopy = true
+			case line == "\\.":
+				inCopy = false
+				_, _ = fmt.Fprintf(stdout, "COPY %d\n", len(loaded))
+			case line == "COMMIT;":
+				cluster.rows = append(cluster.rows, loaded...)
+			case inCopy:
+				loaded = append(loaded, line)
+			}
+		}
+		return nil
+	}
+	return tc
+}
+
+var _ = Describe("parseTableName", func() {
+	It("defaults to the public schema", func() {
+		schemaName, tableName := parseTableName("items")
+		Expect(schemaName).To(Equal("public"))
+		Expect(tableName).To(Equal("items"))
+	})
+
+	It("splits the schema from the table name", func() {
+		schemaName, tableName := parseTableName("sales.items")
+		Expect(schemaName).To(Equal("sales"))
+		Expect(tableName).To(Equal("items"))
+	})
+})
+
+var _ = Describe("copy-table", func() {
+	var (
+		ctx      context.Context
+		columns  []column
+		source   *fakeCluster
+		dest     *fakeCluster
+		clusters map[string]*fakeCluster
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		columns = []column{{Name: "id", Type: "integer"}, {Name: "name", Type: "text"}}
+		source = &fakeCluster{columns: columns, rows: []string{"1\tfoo", "2\tbar"}}
+		dest = &fakeCluster{columns: columns, rows: []string{"3\tbaz"}}
+		clusters = map[string]*fakeCluster{"source": source, "destination": dest}
+	})
+
+	It("copies the rows of a table between two clusters", func() {
+		tc := newFakeTableCopy(clusters)
+		rows, err := tc.copyRows(ctx, columns)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rows).To(BeEquivalentTo(2))
+		Expect(dest.rows).To(Equal([]string{"3\tbaz", "1\tfoo", "2\tbar"}))
+		Expect(dest.receivedScript).To(Equal(
+			"BEGIN;\nCOPY \"public\".\"items\" (\"id\", \"name\") FROM STDIN;\n" +
+				"1\tfoo\n2\tbar\n\\.\nCOMMIT;\n"))
+		Expect(source.receivedSourceArgs).To(ContainElement(
+			"COPY (SELECT \"id\", \"name\" FROM \"public\".\"items\") TO STDOUT"))
+
+		Expect(tc.run(ctx)).To(Succeed())
+	})
+
+	It("truncates the destination table and filters the rows when requested", func() {
+		tc := newFakeTableCopy(clusters)
+		tc.truncateFirst = true
+		tc.where = "id > 1"
+		_, err := tc.copyRows(ctx, columns)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dest.receivedScript).To(HavePrefix(
+			"BEGIN;\nTRUNCATE TABLE \"public\".\"items\";\n"))
+		Expect(source.receivedSourceArgs).To(ContainElement(
+			"COPY (SELECT \"id\", \"name\" FROM \"public\".\"items\" WHERE id > 1) TO STDOUT"))
+		Expect(dest.rows).To(Equal([]string{"1\tfoo", "2\tbar"}))
+	})
+
+	It("doesn't commit the rows when the source cluster fails", func() {
+		source.sourceErr = errors.New("connection lost")
+		tc := newFakeTableCopy(clusters)
+		_, err := tc.copyRows(ctx, columns)
+		Expect(err).To(MatchError(ContainSubstring("connection lost")))
+		Expect(dest.receivedScript).ToNot(ContainSubstring("COMMIT"))
+		Expect(dest.rows).To(Equal([]string{"3\tbaz"}))
+	})
+
+	It("refuses to copy when the destination columns don't match", func() {
+		dest.columns = []column{{Name: "id", Type: "bigint"}, {Name: "description", Type: "text"}}
+		tc := newFakeTableCopy(clusters)
+		err := tc.run(ctx)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("column description is missing in the source"))
+		Expect(err.Error()).To(ContainSubstring("column id is integer in the source and bigint in the destination"))
+		Expect(err.Error()).To(ContainSubstring("column name is missing in the destination"))
+		Expect(dest.receivedScript).To(BeEmpty())
+	})
+
+	It("refuses to copy when the destination table doesn't exist", func() {
+		dest.columns = nil
+		tc := newFakeTableCopy(clusters)
+		Expect(tc.run(ctx)).To(MatchError(ContainSubstring("not found in database app of cluster destination")))
+	})
+})
@@ -0,0 +1,74 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package explain
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+// NewCmd creates the new "explain" command
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "explain",
+		Short:   `Explain the decisions taken by the operator on a cluster`,
+		GroupID: plugin.GroupIDTroubleshooting,
+	}
+
+	cmd.AddCommand(newFailoverCmd())
+
+	return cmd
+}
+
+func newFailoverCmd() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "failover [cluster]",
+		Short: `Show why the operator selected the new primary during the last failover`,
+		Long: `Shows the last failover decision recorded by the operator in the status of ` +
+			`[cluster]: the instances that have been considered, their WAL position at ` +
+			`decision time, the rules that have been applied, and the selected instance.`,
+		Args: plugin.RequiresArguments(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return plugin.CompleteClusters(cmd.Context(), args, toComplete), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format := plugin.OutputFormat(output)
+			switch format {
+			case plugin.OutputFormatText, plugin.OutputFormatJSON, plugin.OutputFormatYAML:
+			default:
+				return fmt.Errorf("output: %s is not supported by the explain failover command", output)
+			}
+
+			return explainFailover(cmd.Context(), args[0], format)
+		},
+	}
+
+	cmd.Flags().StringVarP(
+		&output,
+		"output",
+		"o",
+		string(plugin.OutputFormatText),
+		"Output format. One of text, json, or yaml",
+	)
+
+	return cmd
+}
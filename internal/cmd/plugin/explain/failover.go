@@ -0,0 +1,99 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package explain
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/cheynewallace/tabby"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+// explainFailover prints the last failover decision recorded in the
+// status of a cluster
+func explainFailover(ctx context.Context, clusterName string, format plugin.OutputFormat) error {
+	var cluster apiv1.Cluster
+	if err := plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName},
+		&cluster,
+	); err != nil {
+		return fmt.Errorf("while getting cluster %s: %w", clusterName, err)
+	}
+
+	decision := cluster.Status.LastFailoverDecision
+	if format != plugin.OutputFormatText {
+		return plugin.Print(decision, format, os.Stdout)
+	}
+
+	printFailoverDecision(os.Stdout, clusterName, decision)
+	return nil
+}
+
+// printFailoverDecision prints a failover decision in a human-readable format
+func printFailoverDecision(writer io.Writer, clusterName string, decision *apiv1.FailoverDecision) {
+	if decision == nil {
+		_, _ = fmt.Fprintf(writer, "No failover has been recorded for cluster %s\n", clusterName)
+		return
+	}
+
+	summary := tabby.NewCustom(tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0))
+	summary.AddLine("Cluster:", clusterName)
+	summary.AddLine("Decided at:", decision.DecisionTimestamp)
+	summary.AddLine("Former primary:", decision.FormerPrimary)
+	summary.AddLine("Selected primary:", decision.SelectedPrimary)
+	summary.AddLine("Reason:", decision.Reason)
+	if decision.TopologyKey != "" {
+		summary.AddLine("Topology key:", decision.TopologyKey)
+	}
+	summary.Print()
+
+	if len(decision.Candidates) == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintln(writer)
+	_, _ = fmt.Fprintln(writer, "Candidates")
+	candidates := tabby.NewCustom(tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0))
+	candidates.AddHeader("Instance", "Ready", "Received LSN", "Replay LSN", "Lag (bytes)",
+		"Failure domain", "Outcome")
+	for _, item := range decision.Candidates {
+		outcome := "selected"
+		if item.NotSelectedReason != "" {
+			outcome = "not selected: " + item.NotSelectedReason
+		}
+		candidates.AddLine(item.Name, item.Ready, formatValue(item.ReceivedLSN), formatValue(item.ReplayLSN),
+			item.LagBytes, formatValue(item.FailureDomain), outcome)
+	}
+	candidates.Print()
+}
+
+// formatValue replaces an empty value with a dash, to keep the
+// table readable
+func formatValue(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
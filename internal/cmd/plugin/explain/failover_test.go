@@ -0,0 +1,79 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package explain
+
+import (
+	"bytes"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("printFailoverDecision", func() {
+	It("reports when no failover has been recorded", func() {
+		var buffer bytes.Buffer
+		printFailoverDecision(&buffer, "cluster-example", nil)
+		Expect(buffer.String()).To(Equal("No failover has been recorded for cluster cluster-example\n"))
+	})
+
+	It("renders the candidates and the rationale of the decision", func() {
+		decision := &apiv1.FailoverDecision{
+			DecisionTimestamp: "2024-11-04T10:00:00.000000Z",
+			FormerPrimary:     "cluster-example-1",
+			SelectedPrimary:   "cluster-example-3",
+			Reason:            "most advanced healthy instance in the failure domain of the former primary",
+			TopologyKey:       "topology.kubernetes.io/zone",
+			Candidates: []apiv1.FailoverCandidateStatus{
+				{
+					Name:              "cluster-example-2",
+					Ready:             true,
+					ReceivedLSN:       "0/6000000",
+					ReplayLSN:         "0/6000000",
+					FailureDomain:     "zone-b",
+					NotSelectedReason: `running in the "zone-b" failure domain, while the former primary was in "zone-a"`,
+				},
+				{
+					Name:          "cluster-example-3",
+					Ready:         true,
+					ReceivedLSN:   "0/6000000",
+					ReplayLSN:     "0/6000000",
+					FailureDomain: "zone-a",
+				},
+				{
+					Name:              "cluster-example-1",
+					NotSelectedReason: "failing primary",
+				},
+			},
+		}
+
+		var buffer bytes.Buffer
+		printFailoverDecision(&buffer, "cluster-example", decision)
+		output := buffer.String()
+
+		Expect(output).To(ContainSubstring("Former primary:    cluster-example-1"))
+		Expect(output).To(ContainSubstring("Selected primary:  cluster-example-3"))
+		Expect(output).To(ContainSubstring(
+			"Reason:            most advanced healthy instance in the failure domain of the former primary"))
+		Expect(output).To(ContainSubstring("Topology key:      topology.kubernetes.io/zone"))
+		Expect(output).To(MatchRegexp(`cluster-example-2\s+true\s+0/6000000\s+0/6000000\s+0\s+zone-b\s+` +
+			`not selected: running in the "zone-b" failure domain`))
+		Expect(output).To(MatchRegexp(`cluster-example-3\s+true\s+0/6000000\s+0/6000000\s+0\s+zone-a\s+selected`))
+		Expect(output).To(MatchRegexp(`cluster-example-1\s+false\s+-\s+-\s+0\s+-\s+not selected: failing primary`))
+	})
+})
@@ -0,0 +1,101 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package failover implements the kubectl-cnpg failover command
+package failover
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+// NewCmd creates the new "failover" command
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "failover",
+		Short:   `Test the failover of a cluster`,
+		GroupID: plugin.GroupIDCluster,
+	}
+
+	cmd.AddCommand(newSimulateCmd())
+
+	return cmd
+}
+
+func newSimulateCmd() *cobra.Command {
+	var (
+		autoRevert bool
+		timeout    time.Duration
+		output     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "simulate [cluster]",
+		Short: `Run a failover drill, switching over to a replica`,
+		Long: `Runs a failover drill on a healthy [cluster], switching over to one of its ` +
+			`healthy replicas and waiting for it to become the primary. The drill is ` +
+			`recorded in the events of the cluster, and its report shows the health of ` +
+			`the cluster before and after the switchover. With --auto-revert, the ` +
+			`original primary is promoted again once the new one is healthy.`,
+		Args: plugin.RequiresArguments(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return plugin.CompleteClusters(cmd.Context(), args, toComplete), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format := plugin.OutputFormat(output)
+			switch format {
+			case plugin.OutputFormatText, plugin.OutputFormatJSON, plugin.OutputFormatYAML:
+			default:
+				return fmt.Errorf("output: %s is not supported by the failover simulate command", output)
+			}
+
+			drill := newFailoverDrill(args[0], autoRevert, timeout)
+			report, err := drill.run(cmd.Context())
+			if report != nil {
+				if printErr := printReport(report, format); printErr != nil {
+					return printErr
+				}
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().BoolVar(
+		&autoRevert,
+		"auto-revert",
+		false,
+		"Switch back to the original primary once the new one is healthy",
+	)
+	cmd.Flags().DurationVar(
+		&timeout,
+		"timeout",
+		5*time.Minute,
+		"Maximum time to wait for each switchover to complete",
+	)
+	cmd.Flags().StringVarP(
+		&output,
+		"output",
+		"o",
+		string(plugin.OutputFormatText),
+		"Output format. One of text, json, or yaml",
+	)
+
+	return cmd
+}
@@ -0,0 +1,326 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package failover
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cheynewallace/tabby"
+	pgTime "github.com/cloudnative-pg/machinery/pkg/postgres/time"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources/status"
+)
+
+// drillPollInterval is the interval between two checks of the
+// cluster status while waiting for a switchover to complete
+const drillPollInterval = 2 * time.Second
+
+// The reasons of the events recording the progress of a failover drill,
+// distinct from the ones of real failovers and switchovers
+const (
+	eventReasonDrillStarted   = "FailoverDrillStarted"
+	eventReasonDrillCompleted = "FailoverDrillCompleted"
+	eventReasonDrillReverted  = "FailoverDrillReverted"
+	eventReasonDrillFailed    = "FailoverDrillFailed"
+)
+
+// clusterHealth is the health of the cluster at a given moment of the drill
+type clusterHealth struct {
+	Time           time.Time `json:"time"`
+	Phase          string    `json:"phase"`
+	CurrentPrimary string    `json:"currentPrimary"`
+	ReadyInstances int       `json:"readyInstances"`
+	Instances      int       `json:"instances"`
+}
+
+// drillReport is the outcome of a failover drill
+type drillReport struct {
+	Cluster         string         `json:"cluster"`
+	OriginalPrimary string         `json:"originalPrimary"`
+	TargetPrimary   string         `json:"targetPrimary"`
+	Before          clusterHealth  `json:"before"`
+	AfterSwitchover *clusterHealth `json:"afterSwitchover,omitempty"`
+	AfterRevert     *clusterHealth `json:"afterRevert,omitempty"`
+	Error           string         `json:"error,omitempty"`
+}
+
+// failoverDrill switches over a healthy cluster to one of its replicas,
+// recording the operation as a drill and optionally switching back
+type failoverDrill struct {
+	clusterName string
+	autoRevert  bool
+	timeout     time.Duration
+
+	// switchover requests the promotion of the passed instance
+	switchover func(ctx context.Context, cluster *apiv1.Cluster, instanceName string) error
+}
+
+// newFailoverDrill creates a new failoverDrill for the passed cluster,
+// waiting at most timeout for each switchover to complete
+func newFailoverDrill(clusterName string, autoRevert bool, timeout time.Duration) *failoverDrill {
+	return &failoverDrill{
+		clusterName: clusterName,
+		autoRevert:  autoRevert,
+		timeout:     timeout,
+		switchover:  requestSwitchover,
+	}
+}
+
+// run executes the drill, returning its report. The report is nil
+// if the drill has been refused before touching the cluster
+func (fd *failoverDrill) run(ctx context.Context) (*drillReport, error) {
+	cluster, err := fd.getCluster(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	targetPrimary, err := getDrillTarget(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &drillReport{
+		Cluster:         fd.clusterName,
+		OriginalPrimary: cluster.Status.CurrentPrimary,
+		TargetPrimary:   targetPrimary,
+		Before:          getClusterHealth(cluster),
+	}
+
+	fail := func(err error) (*drillReport, error) {
+		report.Error = err.Error()
+		_ = recordEvent(ctx, cluster, corev1.EventTypeWarning, eventReasonDrillFailed,
+			fmt.Sprintf("Failover drill failed: %v", err))
+		return report, err
+	}
+
+	if err := recordEvent(ctx, cluster, corev1.EventTypeNormal, eventReasonDrillStarted,
+		fmt.Sprintf("Failover drill: switching over from %s to %s",
+			report.OriginalPrimary, targetPrimary)); err != nil {
+		return nil, err
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "Switching over cluster %s from %s to %s\n",
+		fd.clusterName, report.OriginalPrimary, targetPrimary)
+	promotedCluster, err := fd.switchoverAndWait(ctx, cluster, targetPrimary)
+	if err != nil {
+		return fail(err)
+	}
+	afterSwitchover := getClusterHealth(promotedCluster)
+	report.AfterSwitchover = &afterSwitchover
+
+	if err := recordEvent(ctx, cluster, corev1.EventTypeNormal, eventReasonDrillCompleted,
+		fmt.Sprintf("Failover drill: %s is the new healthy primary", targetPrimary)); err != nil {
+		return report, err
+	}
+
+	if !fd.autoRevert {
+		return report, nil
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "Switching cluster %s back to %s\n",
+		fd.clusterName, report.OriginalPrimary)
+	revertedCluster, err := fd.switchoverAndWait(ctx, promotedCluster, report.OriginalPrimary)
+	if err != nil {
+		return fail(err)
+	}
+	afterRevert := getClusterHealth(revertedCluster)
+	report.AfterRevert = &afterRevert
+
+	if err := recordEvent(ctx, cluster, corev1.EventTypeNormal, eventReasonDrillReverted,
+		fmt.Sprintf("Failover drill: switched back to %s", report.OriginalPrimary)); err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
+
+func (fd *failoverDrill) getCluster(ctx context.Context) (*apiv1.Cluster, error) {
+	var cluster apiv1.Cluster
+	if err := plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: fd.clusterName},
+		&cluster,
+	); err != nil {
+		return nil, fmt.Errorf("while getting cluster %s: %w", fd.clusterName, err)
+	}
+
+	return &cluster, nil
+}
+
+// switchoverAndWait promotes the passed instance and waits for it to be
+// the primary of a healthy cluster, returning the updated cluster
+func (fd *failoverDrill) switchoverAndWait(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instanceName string,
+) (*apiv1.Cluster, error) {
+	if err := fd.switchover(ctx, cluster, instanceName); err != nil {
+		return nil, fmt.Errorf("while switching over to %s: %w", instanceName, err)
+	}
+
+	var updatedCluster *apiv1.Cluster
+	err := wait.PollUntilContextTimeout(ctx, drillPollInterval, fd.timeout, true,
+		func(ctx context.Context) (bool, error) {
+			var err error
+			if updatedCluster, err = fd.getCluster(ctx); err != nil {
+				return false, err
+			}
+
+			return updatedCluster.Status.CurrentPrimary == instanceName &&
+				isClusterHealthy(updatedCluster), nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("while waiting for %s to be the primary of a healthy cluster: %w",
+			instanceName, err)
+	}
+
+	return updatedCluster, nil
+}
+
+// requestSwitchover asks the operator to promote the passed instance
+func requestSwitchover(ctx context.Context, cluster *apiv1.Cluster, instanceName string) error {
+	origCluster := cluster.DeepCopy()
+	cluster.Status.TargetPrimary = instanceName
+	cluster.Status.TargetPrimaryTimestamp = pgTime.GetCurrentTimestamp()
+	return status.RegisterPhaseWithOrigCluster(
+		ctx,
+		plugin.Client,
+		cluster,
+		origCluster,
+		apiv1.PhaseSwitchover,
+		fmt.Sprintf("Switching over to %v (failover drill)", instanceName),
+	)
+}
+
+// isClusterHealthy checks if every instance of the cluster is ready
+// and no switchover or failover is in progress
+func isClusterHealthy(cluster *apiv1.Cluster) bool {
+	return cluster.Status.Phase == apiv1.PhaseHealthy &&
+		cluster.Status.CurrentPrimary != "" &&
+		cluster.Status.CurrentPrimary == cluster.Status.TargetPrimary &&
+		cluster.Status.ReadyInstances == cluster.Spec.Instances
+}
+
+// getDrillTarget chooses the replica to switch over to, refusing to run
+// the drill on a cluster that isn't healthy
+func getDrillTarget(cluster *apiv1.Cluster) (string, error) {
+	if !isClusterHealthy(cluster) {
+		return "", fmt.Errorf("cluster %s is not healthy (phase: %q, %d/%d instances ready), "+
+			"refusing to run the failover drill",
+			cluster.Name, cluster.Status.Phase, cluster.Status.ReadyInstances, cluster.Spec.Instances)
+	}
+
+	replicas := slices.DeleteFunc(
+		slices.Clone(cluster.Status.InstancesStatus[apiv1.PodHealthy]),
+		func(name string) bool {
+			return name == cluster.Status.CurrentPrimary
+		})
+	if len(replicas) == 0 {
+		return "", fmt.Errorf("cluster %s has no healthy replica to switch over to", cluster.Name)
+	}
+
+	slices.Sort(replicas)
+	return replicas[0], nil
+}
+
+func getClusterHealth(cluster *apiv1.Cluster) clusterHealth {
+	return clusterHealth{
+		Time:           time.Now().UTC(),
+		Phase:          cluster.Status.Phase,
+		CurrentPrimary: cluster.Status.CurrentPrimary,
+		ReadyInstances: cluster.Status.ReadyInstances,
+		Instances:      cluster.Spec.Instances,
+	}
+}
+
+// recordEvent records an event about the drill on the cluster
+func recordEvent(ctx context.Context, cluster *apiv1.Cluster, eventType, reason, message string) error {
+	now := metav1.Now()
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: cluster.Name + "-",
+			Namespace:    cluster.Namespace,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: apiv1.GroupVersion.String(),
+			Kind:       apiv1.ClusterKind,
+			Name:       cluster.Name,
+			Namespace:  cluster.Namespace,
+			UID:        cluster.UID,
+		},
+		Type:           eventType,
+		Reason:         reason,
+		Message:        message,
+		Source:         corev1.EventSource{Component: "kubectl-cnpg"},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	}
+	if err := plugin.Client.Create(ctx, event); err != nil {
+		return fmt.Errorf("while recording the %s event: %w", reason, err)
+	}
+
+	return nil
+}
+
+func printReport(report *drillReport, format plugin.OutputFormat) error {
+	if format != plugin.OutputFormatText {
+		return plugin.Print(report, format, os.Stdout)
+	}
+
+	printDrillReport(os.Stdout, report)
+	return nil
+}
+
+// printDrillReport prints a drill report in a human-readable format
+func printDrillReport(writer io.Writer, report *drillReport) {
+	summary := tabby.NewCustom(tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0))
+	summary.AddLine("Cluster:", report.Cluster)
+	summary.AddLine("Original primary:", report.OriginalPrimary)
+	summary.AddLine("Target primary:", report.TargetPrimary)
+	if report.Error != "" {
+		summary.AddLine("Error:", report.Error)
+	}
+	summary.Print()
+
+	_, _ = fmt.Fprintln(writer)
+	health := tabby.NewCustom(tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0))
+	health.AddHeader("Stage", "Time", "Primary", "Ready instances", "Phase")
+	addHealth := func(stage string, item *clusterHealth) {
+		if item == nil {
+			return
+		}
+		health.AddLine(stage, item.Time.Format(time.RFC3339), item.CurrentPrimary,
+			fmt.Sprintf("%d/%d", item.ReadyInstances, item.Instances), item.Phase)
+	}
+	addHealth("before", &report.Before)
+	addHealth("after switchover", report.AfterSwitchover)
+	addHealth("after revert", report.AfterRevert)
+	health.Print()
+}
@@ -0,0 +1,170 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package failover
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("failover simulate", func() {
+	const (
+		namespace   = "default"
+		clusterName = "cluster-example"
+	)
+
+	var switchovers []string
+
+	setupClient := func(phase string) {
+		plugin.Namespace = namespace
+		plugin.Client = fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithStatusSubresource(&apiv1.Cluster{}).
+			WithObjects(&apiv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      clusterName,
+				},
+				Spec: apiv1.ClusterSpec{
+					Instances: 3,
+				},
+				Status: apiv1.ClusterStatus{
+					Phase:          phase,
+					CurrentPrimary: clusterName + "-1",
+					TargetPrimary:  clusterName + "-1",
+					ReadyInstances: 3,
+					InstancesStatus: map[apiv1.PodStatus][]string{
+						apiv1.PodHealthy: {clusterName + "-3", clusterName + "-1", clusterName + "-2"},
+					},
+				},
+			}).
+			Build()
+	}
+
+	// completeSwitchover simulates the operator promoting the
+	// requested instance
+	completeSwitchover := func(ctx context.Context, cluster *apiv1.Cluster, instanceName string) error {
+		switchovers = append(switchovers, instanceName)
+		cluster.Status.CurrentPrimary = instanceName
+		cluster.Status.TargetPrimary = instanceName
+		return plugin.Client.Status().Update(ctx, cluster)
+	}
+
+	getEventReasons := func(ctx context.Context) []string {
+		var events corev1.EventList
+		Expect(plugin.Client.List(ctx, &events, client.InNamespace(namespace))).To(Succeed())
+		reasons := make([]string, 0, len(events.Items))
+		for _, event := range events.Items {
+			Expect(event.InvolvedObject.Name).To(Equal(clusterName))
+			reasons = append(reasons, event.Reason)
+		}
+		return reasons
+	}
+
+	newDrill := func(autoRevert bool) *failoverDrill {
+		return &failoverDrill{
+			clusterName: clusterName,
+			autoRevert:  autoRevert,
+			timeout:     time.Second,
+			switchover:  completeSwitchover,
+		}
+	}
+
+	BeforeEach(func() {
+		switchovers = nil
+	})
+
+	It("switches over to a healthy replica", func(ctx SpecContext) {
+		setupClient(apiv1.PhaseHealthy)
+
+		report, err := newDrill(false).run(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(switchovers).To(Equal([]string{clusterName + "-2"}))
+		Expect(report.OriginalPrimary).To(Equal(clusterName + "-1"))
+		Expect(report.TargetPrimary).To(Equal(clusterName + "-2"))
+		Expect(report.Before.CurrentPrimary).To(Equal(clusterName + "-1"))
+		Expect(report.AfterSwitchover).ToNot(BeNil())
+		Expect(report.AfterSwitchover.CurrentPrimary).To(Equal(clusterName + "-2"))
+		Expect(report.AfterRevert).To(BeNil())
+		Expect(getEventReasons(ctx)).To(ConsistOf(eventReasonDrillStarted, eventReasonDrillCompleted))
+	})
+
+	It("switches back to the original primary with auto-revert", func(ctx SpecContext) {
+		setupClient(apiv1.PhaseHealthy)
+
+		report, err := newDrill(true).run(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(switchovers).To(Equal([]string{clusterName + "-2", clusterName + "-1"}))
+		Expect(report.AfterSwitchover.CurrentPrimary).To(Equal(clusterName + "-2"))
+		Expect(report.AfterRevert).ToNot(BeNil())
+		Expect(report.AfterRevert.CurrentPrimary).To(Equal(clusterName + "-1"))
+		Expect(getEventReasons(ctx)).To(ConsistOf(
+			eventReasonDrillStarted, eventReasonDrillCompleted, eventReasonDrillReverted))
+	})
+
+	It("refuses to run on a cluster that isn't healthy", func(ctx SpecContext) {
+		setupClient(apiv1.PhaseUpgrade)
+
+		report, err := newDrill(false).run(ctx)
+		Expect(err).To(MatchError(ContainSubstring("is not healthy")))
+		Expect(report).To(BeNil())
+		Expect(switchovers).To(BeEmpty())
+		Expect(getEventReasons(ctx)).To(BeEmpty())
+	})
+
+	It("reports a switchover that doesn't complete in time", func(ctx SpecContext) {
+		setupClient(apiv1.PhaseHealthy)
+		drill := newDrill(true)
+		drill.timeout = 10 * time.Millisecond
+		drill.switchover = func(context.Context, *apiv1.Cluster, string) error {
+			return nil
+		}
+
+		report, err := drill.run(ctx)
+		Expect(err).To(HaveOccurred())
+		Expect(report.AfterSwitchover).To(BeNil())
+		Expect(report.Error).ToNot(BeEmpty())
+		Expect(getEventReasons(ctx)).To(ConsistOf(eventReasonDrillStarted, eventReasonDrillFailed))
+	})
+
+	It("requests the switchover to the operator", func(ctx SpecContext) {
+		setupClient(apiv1.PhaseHealthy)
+		var cluster apiv1.Cluster
+		Expect(plugin.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: clusterName}, &cluster)).
+			To(Succeed())
+
+		Expect(requestSwitchover(ctx, &cluster, clusterName+"-2")).To(Succeed())
+
+		Expect(plugin.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: clusterName}, &cluster)).
+			To(Succeed())
+		Expect(cluster.Status.TargetPrimary).To(Equal(clusterName + "-2"))
+		Expect(cluster.Status.CurrentPrimary).To(Equal(clusterName + "-1"))
+		Expect(cluster.Status.Phase).To(Equal(apiv1.PhaseSwitchover))
+	})
+})
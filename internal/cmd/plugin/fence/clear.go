@@ -0,0 +1,141 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fence
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// clearPollInterval is the interval between two checks of the
+// readiness of the instances after the fencing has been cleared
+const clearPollInterval = 2 * time.Second
+
+// fencingClear removes the fencing annotation from a cluster as a whole,
+// unfencing every instance in it. It doesn't need to parse the content of
+// the annotation, so it works even if the annotation is malformed
+type fencingClear struct {
+	clusterName string
+
+	// confirm asks the user whether to proceed with the removal
+	// of the fencing annotation
+	confirm func(annotationValue string) bool
+
+	// waitForReady waits for the instances of the cluster
+	// to be ready again after the fencing has been cleared
+	waitForReady func(ctx context.Context) error
+}
+
+// newFencingClear creates a new fencingClear for the passed cluster, asking
+// for confirmation if required and waiting at most timeout for the
+// instances to be ready
+func newFencingClear(clusterName string, confirmationRequired bool, timeout time.Duration) *fencingClear {
+	return &fencingClear{
+		clusterName: clusterName,
+		confirm: func(annotationValue string) bool {
+			if !confirmationRequired {
+				return true
+			}
+			return askToProceed(clusterName, annotationValue)
+		},
+		waitForReady: func(ctx context.Context) error {
+			return waitForInstancesReady(ctx, clusterName, timeout)
+		},
+	}
+}
+
+func (fc *fencingClear) run(ctx context.Context) error {
+	var cluster apiv1.Cluster
+	if err := plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: fc.clusterName},
+		&cluster,
+	); err != nil {
+		return fmt.Errorf("while trying to get cluster %v: %w", fc.clusterName, err)
+	}
+
+	annotationValue, ok := cluster.Annotations[utils.FencedInstanceAnnotation]
+	if !ok {
+		fmt.Printf("%s has no fenced instances\n", fc.clusterName)
+		return nil
+	}
+
+	if !fc.confirm(annotationValue) {
+		fmt.Println("Aborted, the fencing annotation has not been removed")
+		return nil
+	}
+
+	origCluster := cluster.DeepCopy()
+	delete(cluster.Annotations, utils.FencedInstanceAnnotation)
+	if err := plugin.Client.Patch(ctx, &cluster, client.MergeFrom(origCluster)); err != nil {
+		return fmt.Errorf("while removing the fencing annotation from cluster %v: %w", fc.clusterName, err)
+	}
+	fmt.Printf("fencing cleared for %s, waiting for the instances to be ready\n", fc.clusterName)
+
+	if err := fc.waitForReady(ctx); err != nil {
+		return fmt.Errorf("the fencing has been cleared, but the instances of cluster %v are not ready yet: %w",
+			fc.clusterName, err)
+	}
+
+	fmt.Printf("%s unfenced, every instance is ready\n", fc.clusterName)
+	return nil
+}
+
+// askToProceed shows the fencing annotation that is going to be
+// removed and asks the user whether to proceed
+func askToProceed(clusterName, annotationValue string) bool {
+	fmt.Printf("The %s annotation of cluster %s is going to be removed, unfencing every instance.\n",
+		utils.FencedInstanceAnnotation, clusterName)
+	fmt.Printf("Current value: %s\n", annotationValue)
+	fmt.Printf("Do you want to proceed? [y/n]: ")
+	reader := bufio.NewReader(os.Stdin)
+	answer, err := reader.ReadString('\n')
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// waitForInstancesReady waits for every instance of the cluster to be ready
+func waitForInstancesReady(ctx context.Context, clusterName string, timeout time.Duration) error {
+	return wait.PollUntilContextTimeout(ctx, clearPollInterval, timeout, false,
+		func(ctx context.Context) (bool, error) {
+			var cluster apiv1.Cluster
+			if err := plugin.Client.Get(
+				ctx,
+				client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName},
+				&cluster,
+			); err != nil {
+				return false, err
+			}
+
+			fmt.Printf("%d/%d instances ready\n", cluster.Status.ReadyInstances, cluster.Spec.Instances)
+			return cluster.Status.ReadyInstances == cluster.Spec.Instances, nil
+		})
+}
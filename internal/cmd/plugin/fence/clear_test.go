@@ -0,0 +1,130 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fence
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("fencing clear", func() {
+	const (
+		namespace   = "default"
+		clusterName = "cluster-example"
+	)
+
+	setupClient := func(annotations map[string]string) {
+		plugin.Namespace = namespace
+		plugin.Client = fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(&apiv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   namespace,
+					Name:        clusterName,
+					Annotations: annotations,
+				},
+				Spec: apiv1.ClusterSpec{
+					Instances: 3,
+				},
+			}).
+			Build()
+	}
+
+	getAnnotations := func(ctx context.Context) map[string]string {
+		var cluster apiv1.Cluster
+		Expect(plugin.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: clusterName}, &cluster)).
+			To(Succeed())
+		return cluster.Annotations
+	}
+
+	DescribeTable("removes the fencing annotation and waits for the instances",
+		func(ctx SpecContext, annotationValue string) {
+			setupClient(map[string]string{
+				utils.FencedInstanceAnnotation: annotationValue,
+				"unrelated":                    "value",
+			})
+
+			var confirmedValue string
+			waited := false
+			fc := &fencingClear{
+				clusterName: clusterName,
+				confirm: func(value string) bool {
+					confirmedValue = value
+					return true
+				},
+				waitForReady: func(ctx context.Context) error {
+					Expect(getAnnotations(ctx)).ToNot(HaveKey(utils.FencedInstanceAnnotation))
+					waited = true
+					return nil
+				},
+			}
+
+			Expect(fc.run(ctx)).To(Succeed())
+			Expect(confirmedValue).To(Equal(annotationValue))
+			Expect(waited).To(BeTrue())
+			Expect(getAnnotations(ctx)).To(Equal(map[string]string{"unrelated": "value"}))
+		},
+		Entry("the whole cluster is fenced", `["*"]`),
+		Entry("some instances are fenced", `["cluster-example-1","cluster-example-3"]`),
+		Entry("the annotation is malformed", `["cluster-example-1"`),
+	)
+
+	It("leaves the annotation in place when not confirmed", func(ctx SpecContext) {
+		setupClient(map[string]string{utils.FencedInstanceAnnotation: `["*"]`})
+
+		fc := &fencingClear{
+			clusterName: clusterName,
+			confirm:     func(string) bool { return false },
+			waitForReady: func(context.Context) error {
+				Fail("the instances should not be waited for")
+				return nil
+			},
+		}
+
+		Expect(fc.run(ctx)).To(Succeed())
+		Expect(getAnnotations(ctx)).To(HaveKeyWithValue(utils.FencedInstanceAnnotation, `["*"]`))
+	})
+
+	It("does nothing when the cluster is not fenced", func(ctx SpecContext) {
+		setupClient(nil)
+
+		fc := &fencingClear{
+			clusterName: clusterName,
+			confirm: func(string) bool {
+				Fail("no confirmation should be asked")
+				return false
+			},
+			waitForReady: func(context.Context) error {
+				Fail("the instances should not be waited for")
+				return nil
+			},
+		}
+
+		Expect(fc.run(ctx)).To(Succeed())
+	})
+})
@@ -19,6 +19,7 @@ package fence
 import (
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -56,6 +57,67 @@ var (
 	}
 )
 
+func newFenceClearCmd() *cobra.Command {
+	var confirmationSkipped bool
+	var timeout time.Duration
+
+	fenceClearCmd := &cobra.Command{
+		Use:   "clear [cluster]",
+		Short: `Remove the fencing annotation from [cluster], unfencing every instance`,
+		Long: `Remove the fencing annotation from [cluster] as a whole, unfencing every instance,
+including the ones fenced via "*". This is meant to be used in an emergency, and
+works even if the content of the annotation is malformed.
+After the annotation is removed, the command waits for every instance to be ready.`,
+		Args: plugin.RequiresArguments(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return newFencingClear(args[0], !confirmationSkipped, timeout).run(cmd.Context())
+		},
+	}
+
+	fenceClearCmd.Flags().BoolVarP(&confirmationSkipped, "yes", "y", false,
+		"Remove the fencing annotation without asking for confirmation")
+	fenceClearCmd.Flags().DurationVar(&timeout, "timeout", 5*time.Minute,
+		"The maximum time to wait for the instances to be ready after the fencing is cleared")
+
+	return fenceClearCmd
+}
+
+func newFenceStatusCmd() *cobra.Command {
+	var output string
+
+	fenceStatusCmd := &cobra.Command{
+		Use:   "status [cluster]",
+		Short: `Show the fenced instances of [cluster]`,
+		Long: `Show the instances of [cluster] that are fenced, resolving the "*" wildcard,
+together with their role and the time when they stopped being ready after
+having been fenced.`,
+		Args: plugin.RequiresArguments(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return plugin.CompleteClusters(cmd.Context(), args, toComplete), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format := plugin.OutputFormat(output)
+			switch format {
+			case plugin.OutputFormatText, plugin.OutputFormatJSON, plugin.OutputFormatYAML:
+			default:
+				return fmt.Errorf("output: %s is not supported by the fencing status command", output)
+			}
+
+			return fencingStatus(cmd.Context(), args[0], format)
+		},
+	}
+
+	fenceStatusCmd.Flags().StringVarP(
+		&output,
+		"output",
+		"o",
+		string(plugin.OutputFormatText),
+		"Output format. One of text, json, or yaml",
+	)
+
+	return fenceStatusCmd
+}
+
 // NewCmd creates the new "fencing" command
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -65,6 +127,8 @@ func NewCmd() *cobra.Command {
 	}
 	cmd.AddCommand(fenceOnCmd)
 	cmd.AddCommand(fenceOffCmd)
+	cmd.AddCommand(newFenceClearCmd())
+	cmd.AddCommand(newFenceStatusCmd())
 
 	return cmd
 }
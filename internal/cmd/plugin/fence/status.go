@@ -0,0 +1,164 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fence
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cheynewallace/tabby"
+	"github.com/cloudnative-pg/machinery/pkg/stringset"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// fencedInstance is the fencing status of an instance
+type fencedInstance struct {
+	// Name is the name of the fenced instance
+	Name string `json:"name"`
+
+	// Role is the role of the instance, either primary or replica
+	Role string `json:"role"`
+
+	// FencedSince is the time when the instance stopped being ready
+	// after having been fenced, empty when the fencing has not been
+	// applied yet
+	FencedSince string `json:"fencedSince,omitempty"`
+}
+
+// fencingStatus prints the list of the fenced instances of a cluster
+func fencingStatus(ctx context.Context, clusterName string, format plugin.OutputFormat) error {
+	var cluster apiv1.Cluster
+	if err := plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName},
+		&cluster,
+	); err != nil {
+		return fmt.Errorf("while trying to get cluster %v: %w", clusterName, err)
+	}
+
+	var pods corev1.PodList
+	if err := plugin.Client.List(
+		ctx,
+		&pods,
+		client.InNamespace(plugin.Namespace),
+		client.MatchingLabels{
+			utils.ClusterLabelName: clusterName,
+			utils.PodRoleLabelName: string(utils.PodRoleInstance),
+		},
+	); err != nil {
+		return fmt.Errorf("while listing the instances of cluster %v: %w", clusterName, err)
+	}
+
+	instances, err := getFencedInstances(&cluster, pods.Items)
+	if err != nil {
+		return err
+	}
+
+	if format != plugin.OutputFormatText {
+		return plugin.Print(instances, format, os.Stdout)
+	}
+
+	printFencedInstances(os.Stdout, instances)
+	return nil
+}
+
+// getFencedInstances resolves the content of the fencing annotation of the
+// cluster, including the "*" wildcard and the role selectors, into the list
+// of the fenced instances
+func getFencedInstances(cluster *apiv1.Cluster, pods []corev1.Pod) ([]fencedInstance, error) {
+	fencedNames, err := utils.GetFencedInstances(cluster.Annotations)
+	if err != nil {
+		return nil, fmt.Errorf("while reading the fenced instances of cluster %v: %w", cluster.Name, err)
+	}
+
+	podsByName := make(map[string]*corev1.Pod, len(pods))
+	for idx := range pods {
+		podsByName[pods[idx].Name] = &pods[idx]
+	}
+
+	resolvedNames := stringset.New()
+	for _, name := range fencedNames.ToList() {
+		if name != utils.FenceAllInstances && !utils.IsFencingSelector(name) {
+			resolvedNames.Put(name)
+		}
+	}
+	for name := range podsByName {
+		if cluster.IsInstanceFenced(name) {
+			resolvedNames.Put(name)
+		}
+	}
+	names := resolvedNames.ToSortedList()
+
+	instances := make([]fencedInstance, 0, len(names))
+	for _, name := range names {
+		instance := fencedInstance{
+			Name: name,
+			Role: specs.ClusterRoleLabelReplica,
+		}
+		if name == cluster.Status.CurrentPrimary {
+			instance.Role = specs.ClusterRoleLabelPrimary
+		}
+		if pod, ok := podsByName[name]; ok {
+			instance.FencedSince = getFencedSince(pod)
+		}
+		instances = append(instances, instance)
+	}
+
+	return instances, nil
+}
+
+// getFencedSince returns the time when the passed pod stopped being
+// ready, or an empty string if the pod is still ready
+func getFencedSince(pod *corev1.Pod) string {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady && condition.Status != corev1.ConditionTrue {
+			return condition.LastTransitionTime.UTC().Format(time.RFC3339)
+		}
+	}
+
+	return ""
+}
+
+// printFencedInstances prints the list of the fenced instances in
+// a human-readable format
+func printFencedInstances(writer io.Writer, instances []fencedInstance) {
+	if len(instances) == 0 {
+		_, _ = fmt.Fprintln(writer, "No instances fenced")
+		return
+	}
+
+	table := tabby.NewCustom(tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0))
+	table.AddHeader("Instance", "Role", "Fenced since")
+	for _, instance := range instances {
+		fencedSince := instance.FencedSince
+		if fencedSince == "" {
+			fencedSince = "-"
+		}
+		table.AddLine(instance.Name, instance.Role, fencedSince)
+	}
+	table.Print()
+}
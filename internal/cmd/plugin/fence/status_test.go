@@ -0,0 +1,162 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fence
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("fencing status", func() {
+	const (
+		namespace   = "default"
+		clusterName = "cluster-example"
+	)
+
+	fencedAt := metav1.NewTime(time.Date(2024, 10, 1, 12, 30, 0, 0, time.UTC))
+
+	newPod := func(name string, ready bool) corev1.Pod {
+		status := corev1.ConditionTrue
+		if !ready {
+			status = corev1.ConditionFalse
+		}
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      name,
+				Labels: map[string]string{
+					utils.ClusterLabelName: clusterName,
+					utils.PodRoleLabelName: string(utils.PodRoleInstance),
+				},
+			},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{
+					{Type: corev1.PodReady, Status: status, LastTransitionTime: fencedAt},
+				},
+			},
+		}
+	}
+
+	newCluster := func(annotations map[string]string) *apiv1.Cluster {
+		return &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   namespace,
+				Name:        clusterName,
+				Annotations: annotations,
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+			},
+		}
+	}
+
+	pods := []corev1.Pod{
+		newPod("cluster-example-1", true),
+		newPod("cluster-example-2", false),
+		newPod("cluster-example-3", false),
+	}
+
+	It("fails when the cluster doesn't exist", func(ctx SpecContext) {
+		plugin.Namespace = namespace
+		plugin.Client = fake.NewClientBuilder().WithScheme(scheme.BuildWithAllKnownScheme()).Build()
+
+		Expect(fencingStatus(ctx, clusterName, plugin.OutputFormatText)).To(HaveOccurred())
+	})
+
+	It("reports no instances when the annotation is absent or empty", func() {
+		for _, annotations := range []map[string]string{nil, {utils.FencedInstanceAnnotation: "[]"}} {
+			instances, err := getFencedInstances(newCluster(annotations), pods)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(instances).To(BeEmpty())
+
+			var buffer bytes.Buffer
+			printFencedInstances(&buffer, instances)
+			Expect(buffer.String()).To(Equal("No instances fenced\n"))
+		}
+	})
+
+	It("lists the fenced instances with their role and fencing time", func() {
+		cluster := newCluster(map[string]string{
+			utils.FencedInstanceAnnotation: `["cluster-example-2","cluster-example-1"]`,
+		})
+		instances, err := getFencedInstances(cluster, pods)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(instances).To(Equal([]fencedInstance{
+			{Name: "cluster-example-1", Role: "primary"},
+			{Name: "cluster-example-2", Role: "replica", FencedSince: "2024-10-01T12:30:00Z"},
+		}))
+
+		var buffer bytes.Buffer
+		printFencedInstances(&buffer, instances)
+		Expect(buffer.String()).To(SatisfyAll(
+			ContainSubstring("cluster-example-1  primary  -"),
+			ContainSubstring("cluster-example-2  replica  2024-10-01T12:30:00Z"),
+		))
+	})
+
+	It("resolves the wildcard into every instance", func() {
+		cluster := newCluster(map[string]string{utils.FencedInstanceAnnotation: `["*"]`})
+		instances, err := getFencedInstances(cluster, pods)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(instances).To(HaveLen(3))
+		Expect(instances[0].Name).To(Equal("cluster-example-1"))
+		Expect(instances[2].Name).To(Equal("cluster-example-3"))
+	})
+
+	It("resolves the role selectors using the current primary", func() {
+		cluster := newCluster(map[string]string{utils.FencedInstanceAnnotation: `["role=replica"]`})
+		instances, err := getFencedInstances(cluster, pods)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(instances).To(HaveLen(2))
+		Expect(instances[0].Name).To(Equal("cluster-example-2"))
+		Expect(instances[1].Name).To(Equal("cluster-example-3"))
+	})
+
+	It("fails when the annotation is malformed", func() {
+		cluster := newCluster(map[string]string{utils.FencedInstanceAnnotation: "not-json"})
+		_, err := getFencedInstances(cluster, pods)
+		Expect(err).To(MatchError(utils.ErrorFencedInstancesSyntax))
+	})
+
+	It("prints the fenced instances as JSON", func() {
+		cluster := newCluster(map[string]string{utils.FencedInstanceAnnotation: `["cluster-example-3"]`})
+		instances, err := getFencedInstances(cluster, pods)
+		Expect(err).ToNot(HaveOccurred())
+
+		var buffer bytes.Buffer
+		Expect(plugin.Print(instances, plugin.OutputFormatJSON, &buffer)).To(Succeed())
+
+		var decoded []map[string]string
+		Expect(json.Unmarshal(buffer.Bytes(), &decoded)).To(Succeed())
+		Expect(decoded).To(Equal([]map[string]string{
+			{"name": "cluster-example-3", "role": "replica", "fencedSince": "2024-10-01T12:30:00Z"},
+		}))
+	})
+})
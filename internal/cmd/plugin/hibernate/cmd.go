@@ -33,18 +33,7 @@ var (
 			return plugin.CompleteClusters(cmd.Context(), args, toComplete), cobra.ShellCompDirectiveNoFileComp
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			clusterName := args[0]
-			force, err := cmd.Flags().GetBool("force")
-			if err != nil {
-				return err
-			}
-
-			hibernateOn, err := newOnCommand(cmd.Context(), clusterName, force)
-			if err != nil {
-				return err
-			}
-
-			return hibernateOn.execute()
+			return hibernateOn(cmd.Context(), args[0])
 		},
 	}
 
@@ -56,9 +45,7 @@ var (
 			return plugin.CompleteClusters(cmd.Context(), args, toComplete), cobra.ShellCompDirectiveNoFileComp
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			clusterName := args[0]
-			off := newOffCommand(cmd.Context(), clusterName)
-			return off.execute()
+			return hibernateOff(cmd.Context(), args[0])
 		},
 	}
 
@@ -105,6 +92,9 @@ func NewCmd() *cobra.Command {
 		"force",
 		false,
 		"Force the hibernation procedure even if the preconditions are not met")
+	_ = hibernateOnCmd.Flags().MarkDeprecated(
+		"force",
+		"the hibernation is performed by the operator, which waits for the cluster to be healthy")
 	hibernateStatusCmd.Flags().
 		StringP(
 			"output",
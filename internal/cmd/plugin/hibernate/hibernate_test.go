@@ -0,0 +1,82 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hibernate
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("declarative hibernation", func() {
+	const (
+		namespace   = "default"
+		clusterName = "cluster-example"
+	)
+
+	BeforeEach(func() {
+		plugin.Namespace = namespace
+		plugin.Client = fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(&apiv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: clusterName},
+				Status:     apiv1.ClusterStatus{CurrentPrimary: clusterName + "-2"},
+			}).
+			Build()
+	})
+
+	getAnnotation := func(ctx context.Context) string {
+		var cluster apiv1.Cluster
+		Expect(plugin.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: clusterName}, &cluster)).
+			To(Succeed())
+		return cluster.Annotations[utils.HibernationAnnotationName]
+	}
+
+	It("annotates the cluster to hibernate it, keeping the Cluster resource", func(ctx SpecContext) {
+		Expect(hibernateOn(ctx, clusterName)).To(Succeed())
+		Expect(getAnnotation(ctx)).To(Equal(string(utils.HibernationAnnotationValueOn)))
+
+		// requesting it twice is harmless
+		Expect(hibernateOn(ctx, clusterName)).To(Succeed())
+		Expect(getAnnotation(ctx)).To(Equal(string(utils.HibernationAnnotationValueOn)))
+	})
+
+	It("lifts the hibernation", func(ctx SpecContext) {
+		Expect(hibernateOn(ctx, clusterName)).To(Succeed())
+		Expect(hibernateOff(ctx, clusterName)).To(Succeed())
+		Expect(getAnnotation(ctx)).To(Equal(string(utils.HibernationAnnotationValueOff)))
+	})
+
+	It("leaves a cluster which is not hibernated untouched", func(ctx SpecContext) {
+		Expect(hibernateOff(ctx, clusterName)).To(Succeed())
+		Expect(getAnnotation(ctx)).To(BeEmpty())
+	})
+
+	It("fails when the cluster doesn't exist", func(ctx SpecContext) {
+		Expect(hibernateOn(ctx, "missing")).ToNot(Succeed())
+	})
+})
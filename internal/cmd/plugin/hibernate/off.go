@@ -32,7 +32,41 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
-// offCommand represent the `hibernate off` command
+// hibernateOff lifts the declarative hibernation of the cluster. The
+// operator recreates the Pods reattaching the existing PVCs, starting
+// from the last primary and then the replicas.
+// A cluster that doesn't exist anymore may have been hibernated by a
+// previous version of the plugin, and is recreated from its PVCs
+func hibernateOff(ctx context.Context, clusterName string) error {
+	var cluster apiv1.Cluster
+	err := plugin.Client.Get(
+		ctx,
+		types.NamespacedName{Name: clusterName, Namespace: plugin.Namespace},
+		&cluster,
+	)
+	if apierrs.IsNotFound(err) {
+		return newOffCommand(ctx, clusterName).execute()
+	}
+	if err != nil {
+		return fmt.Errorf("could not get cluster: %w", err)
+	}
+
+	if cluster.Annotations[utils.HibernationAnnotationName] != string(utils.HibernationAnnotationValueOn) {
+		fmt.Printf("Cluster %s is not hibernated\n", clusterName)
+		return nil
+	}
+
+	if _, err := setHibernationAnnotation(ctx, clusterName, utils.HibernationAnnotationValueOff); err != nil {
+		return err
+	}
+
+	fmt.Printf("Cluster %s will be rehydrated, starting from %s\n", clusterName, cluster.Status.CurrentPrimary)
+	return nil
+}
+
+// offCommand represent the `hibernate off` command for the clusters
+// hibernated by the previous versions of the plugin, which deleted the
+// Cluster resource keeping only the PVCs of the primary
 type offCommand struct {
 	ctx         context.Context
 	clusterName string
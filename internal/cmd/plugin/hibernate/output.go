@@ -45,6 +45,7 @@ type statusOutputManager interface {
 	addHibernationSummaryInformation(level statusLevel, statusMessage, clusterName string)
 	addClusterManifestInformation(cluster *apiv1.Cluster)
 	addPVCGroupInformation(pvcs []corev1.PersistentVolumeClaim)
+	addLastPrimaryInformation(instanceName string)
 	// execute renders the output
 	execute() error
 }
@@ -123,6 +124,12 @@ func (t *textStatusOutputManager) addPVCGroupInformation(
 	t.textPrinter.AddLine(value)
 }
 
+func (t *textStatusOutputManager) addLastPrimaryInformation(instanceName string) {
+	t.textPrinter.AddHeader(aurora.Green("Instances"))
+	t.textPrinter.AddLine("Last primary", instanceName)
+	t.textPrinter.AddLine()
+}
+
 func (t *textStatusOutputManager) execute() error {
 	// do not remove this is to flush the writer cache into the buffer
 	t.textPrinter.Print()
@@ -205,6 +212,10 @@ func (t *structuredStatusOutputManager) addPVCGroupInformation(
 	t.mapToSerialize["pgControlData"] = tmp
 }
 
+func (t *structuredStatusOutputManager) addLastPrimaryInformation(instanceName string) {
+	t.mapToSerialize["lastPrimary"] = instanceName
+}
+
 func (t *structuredStatusOutputManager) execute() error {
 	return plugin.Print(t.mapToSerialize, t.format, os.Stdout)
 }
@@ -23,10 +23,13 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/hibernation"
 )
 
 // statusLevel describes if the output should communicate an ok,warning or error status
@@ -65,12 +68,12 @@ func newStatusCommandTextOutput(ctx context.Context, clusterName string) *status
 }
 
 func (cmd *statusCommand) execute() error {
-	isDeployed, err := cmd.isClusterDeployed()
+	cluster, err := cmd.getCluster()
 	if err != nil {
 		return err
 	}
-	if isDeployed {
-		return cmd.clusterIsAlreadyRunningOutput()
+	if cluster != nil {
+		return cmd.declarativeHibernationOutput(cluster)
 	}
 
 	pvcs, err := getHibernatedPVCGroup(cmd.ctx, cmd.clusterName)
@@ -97,8 +100,25 @@ func (cmd *statusCommand) clusterHibernatedOutput(pvcs []corev1.PersistentVolume
 	return cmd.outputManager.execute()
 }
 
-func (cmd *statusCommand) clusterIsAlreadyRunningOutput() error {
-	cmd.outputManager.addHibernationSummaryInformation(warningLevel, "No Hibernation. Cluster Deployed.", cmd.clusterName)
+// declarativeHibernationOutput reports the status of a cluster
+// hibernated by setting the hibernation annotation
+func (cmd *statusCommand) declarativeHibernationOutput(cluster *apiv1.Cluster) error {
+	condition := meta.FindStatusCondition(cluster.Status.Conditions, hibernation.HibernationConditionType)
+	switch {
+	case condition == nil:
+		cmd.outputManager.addHibernationSummaryInformation(warningLevel, "No Hibernation. Cluster Deployed.",
+			cmd.clusterName)
+		return cmd.outputManager.execute()
+
+	case condition.Status == metav1.ConditionTrue:
+		cmd.outputManager.addHibernationSummaryInformation(okLevel, "Cluster Hibernated", cmd.clusterName)
+
+	default:
+		cmd.outputManager.addHibernationSummaryInformation(warningLevel,
+			fmt.Sprintf("Hibernation in progress: %s", condition.Message), cmd.clusterName)
+	}
+
+	cmd.outputManager.addLastPrimaryInformation(cluster.Status.CurrentPrimary)
 	return cmd.outputManager.execute()
 }
 
@@ -107,17 +127,19 @@ func (cmd *statusCommand) noHibernatedPVCsOutput() error {
 	return cmd.outputManager.execute()
 }
 
-func (cmd *statusCommand) isClusterDeployed() (bool, error) {
+// getCluster gets the Cluster resource, which doesn't exist anymore
+// if it has been hibernated by a previous version of the plugin
+func (cmd *statusCommand) getCluster() (*apiv1.Cluster, error) {
 	var cluster apiv1.Cluster
 
 	// Get the Cluster object
 	err := plugin.Client.Get(cmd.ctx, client.ObjectKey{Namespace: plugin.Namespace, Name: cmd.clusterName}, &cluster)
 	if apierrs.IsNotFound(err) {
-		return false, nil
+		return nil, nil
 	}
 	if err != nil {
-		return false, fmt.Errorf("error while fetching the cluster resource: %w", err)
+		return nil, fmt.Errorf("error while fetching the cluster resource: %w", err)
 	}
 
-	return true, nil
+	return &cluster, nil
 }
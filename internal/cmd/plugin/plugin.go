@@ -144,13 +144,14 @@ func GetPGControlData(
 ) (string, error) {
 	timeout := time.Second * 10
 	clientInterface := kubernetes.NewForConfigOrDie(Config)
-	stdout, _, err := utils.ExecCommand(
+	stdout, _, err := utils.ExecCommandWithRetry(
 		ctx,
 		clientInterface,
 		Config,
 		pod,
 		specs.PostgresContainerName,
 		&timeout,
+		utils.DefaultExecBackoff,
 		"pg_controldata")
 	if err != nil {
 		return "", err
@@ -17,7 +17,6 @@ limitations under the License.
 package promote
 
 import (
-	"context"
 	"fmt"
 	"strconv"
 
@@ -28,21 +27,35 @@ import (
 
 // NewCmd create the new "promote" subcommand
 func NewCmd() *cobra.Command {
+	var force bool
+
 	promoteCmd := &cobra.Command{
-		Use:     "promote [cluster] [node]",
-		Short:   "Promote the pod named [cluster]-[node] or [node] to primary",
+		Use:   "promote [cluster] [node]",
+		Short: "Promote the pod named [cluster]-[node] or [node] to primary",
+		Long: `Promote the pod named [cluster]-[node] or [node] to primary.
+To avoid having two primaries, the promotion is refused while the current
+primary is reachable and not fenced, unless --force is given. Use the
+switchover command to promote a replica while the primary is running.
+When the recovery of [cluster] is paused at the recovery target, the node
+can be omitted to end the recovery and start the cluster.`,
 		GroupID: plugin.GroupIDCluster,
-		Args:    plugin.RequiresArguments(2),
-		RunE: func(_ *cobra.Command, args []string) error {
-			ctx := context.Background()
+		Args:    plugin.RequiresArguments(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			clusterName := args[0]
+			if len(args) == 1 {
+				return PromoteRecovery(ctx, clusterName)
+			}
 			node := args[1]
 			if _, err := strconv.Atoi(args[1]); err == nil {
 				node = fmt.Sprintf("%s-%s", clusterName, node)
 			}
-			return Promote(ctx, clusterName, node)
+			return newEmergencyPromotion(clusterName, node, force).run(ctx)
 		},
 	}
 
+	promoteCmd.Flags().BoolVar(&force, "force", false,
+		"Promote the instance even if the current primary is reachable and not fenced")
+
 	return promoteCmd
 }
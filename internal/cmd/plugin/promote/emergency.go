@@ -0,0 +1,121 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promote
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/logrusorgru/aurora/v4"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/plugin/resources"
+)
+
+// emergencyPromotion promotes an instance of the cluster, after having
+// checked that the current primary is fenced or unreachable, so that
+// the cluster can't end up with two primaries
+type emergencyPromotion struct {
+	clusterName string
+	target      string
+	force       bool
+
+	// isInstanceReachable checks if the instance manager running
+	// in the passed pod answers to the status requests
+	isInstanceReachable func(ctx context.Context, pod corev1.Pod) bool
+
+	// promote requests the promotion of the target instance
+	promote func(ctx context.Context) error
+}
+
+// newEmergencyPromotion creates a new promotion of the target
+// instance of the passed cluster
+func newEmergencyPromotion(clusterName, target string, force bool) *emergencyPromotion {
+	return &emergencyPromotion{
+		clusterName:         clusterName,
+		target:              target,
+		force:               force,
+		isInstanceReachable: isInstanceReachable,
+		promote: func(ctx context.Context) error {
+			return Promote(ctx, clusterName, target)
+		},
+	}
+}
+
+func (p *emergencyPromotion) run(ctx context.Context) error {
+	var cluster apiv1.Cluster
+	if err := plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: p.clusterName},
+		&cluster,
+	); err != nil {
+		return fmt.Errorf("cluster %s not found in namespace %s: %w", p.clusterName, plugin.Namespace, err)
+	}
+
+	if cluster.Status.TargetPrimary == p.target {
+		return p.promote(ctx)
+	}
+
+	if err := p.ensurePrimaryIsDown(ctx, &cluster); err != nil {
+		if !p.force {
+			return fmt.Errorf("%w. Use the switchover command to promote a replica while the "+
+				"primary is running, fence the primary, or use --force to promote %s anyway",
+				err, p.target)
+		}
+		fmt.Println(aurora.Red(fmt.Sprintf(
+			"WARNING: %v. Promoting %s anyway, as requested with --force: "+
+				"the cluster could have two primaries accepting writes", err, p.target)))
+	}
+
+	return p.promote(ctx)
+}
+
+// ensurePrimaryIsDown checks that the current primary of the cluster
+// is fenced or unreachable
+func (p *emergencyPromotion) ensurePrimaryIsDown(ctx context.Context, cluster *apiv1.Cluster) error {
+	currentPrimary := cluster.Status.CurrentPrimary
+	if currentPrimary == "" || cluster.IsInstanceFenced(currentPrimary) {
+		return nil
+	}
+
+	var pod corev1.Pod
+	err := plugin.Client.Get(ctx, client.ObjectKey{Namespace: plugin.Namespace, Name: currentPrimary}, &pod)
+	if apierrs.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("while getting the current primary %s: %w", currentPrimary, err)
+	}
+
+	if !p.isInstanceReachable(ctx, pod) {
+		return nil
+	}
+
+	return fmt.Errorf("the current primary %s of cluster %s is still reachable",
+		currentPrimary, p.clusterName)
+}
+
+// isInstanceReachable checks if the instance manager running
+// in the passed pod answers to the status requests
+func isInstanceReachable(ctx context.Context, pod corev1.Pod) bool {
+	_, errs := resources.ExtractInstancesStatus(ctx, plugin.Config, []corev1.Pod{pod})
+	return len(errs) == 0
+}
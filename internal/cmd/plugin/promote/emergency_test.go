@@ -0,0 +1,130 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promote
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("emergency promotion", func() {
+	const (
+		namespace      = "default"
+		clusterName    = "cluster-example"
+		currentPrimary = clusterName + "-1"
+		target         = clusterName + "-2"
+	)
+
+	var (
+		promoted         bool
+		primaryReachable bool
+		cluster          *apiv1.Cluster
+	)
+
+	setupClient := func(objects ...client.Object) {
+		plugin.Namespace = namespace
+		plugin.Client = fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(append(objects, cluster)...).
+			Build()
+	}
+
+	primaryPod := func() *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: currentPrimary},
+		}
+	}
+
+	newTestPromotion := func(force bool) *emergencyPromotion {
+		p := newEmergencyPromotion(clusterName, target, force)
+		p.isInstanceReachable = func(context.Context, corev1.Pod) bool {
+			return primaryReachable
+		}
+		p.promote = func(context.Context) error {
+			promoted = true
+			return nil
+		}
+		return p
+	}
+
+	BeforeEach(func() {
+		promoted = false
+		primaryReachable = true
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: clusterName},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: currentPrimary,
+				TargetPrimary:  currentPrimary,
+			},
+		}
+	})
+
+	It("refuses to promote while the current primary is reachable", func(ctx SpecContext) {
+		setupClient(primaryPod())
+		err := newTestPromotion(false).run(ctx)
+		Expect(err).To(MatchError(ContainSubstring("is still reachable")))
+		Expect(err).To(MatchError(ContainSubstring("--force")))
+		Expect(promoted).To(BeFalse())
+	})
+
+	It("promotes with --force while the current primary is reachable", func(ctx SpecContext) {
+		setupClient(primaryPod())
+		Expect(newTestPromotion(true).run(ctx)).To(Succeed())
+		Expect(promoted).To(BeTrue())
+	})
+
+	It("promotes when the current primary is unreachable", func(ctx SpecContext) {
+		primaryReachable = false
+		setupClient(primaryPod())
+		Expect(newTestPromotion(false).run(ctx)).To(Succeed())
+		Expect(promoted).To(BeTrue())
+	})
+
+	It("promotes when the pod of the current primary doesn't exist", func(ctx SpecContext) {
+		setupClient()
+		Expect(newTestPromotion(false).run(ctx)).To(Succeed())
+		Expect(promoted).To(BeTrue())
+	})
+
+	It("promotes when the current primary is fenced", func(ctx SpecContext) {
+		cluster.Annotations = map[string]string{
+			utils.FencedInstanceAnnotation: `["` + utils.FencePrimarySelector + `"]`,
+		}
+		setupClient(primaryPod())
+		Expect(newTestPromotion(false).run(ctx)).To(Succeed())
+		Expect(promoted).To(BeTrue())
+	})
+
+	It("doesn't check the current primary when the target is already the primary", func(ctx SpecContext) {
+		cluster.Status.TargetPrimary = target
+		setupClient(primaryPod())
+		Expect(newTestPromotion(false).run(ctx)).To(Succeed())
+		Expect(promoted).To(BeTrue())
+	})
+})
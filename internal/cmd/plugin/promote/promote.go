@@ -20,16 +20,28 @@ package promote
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
 
 	pgTime "github.com/cloudnative-pg/machinery/pkg/postgres/time"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources/status"
 )
 
+// runningBackupsPollInterval is the interval between two checks of the
+// backups that are deferring a switchover
+const runningBackupsPollInterval = 5 * time.Second
+
 // Promote command implementation
 func Promote(ctx context.Context, clusterName string, serverName string) error {
 	var cluster apiv1.Cluster
@@ -54,6 +66,10 @@ func Promote(ctx context.Context, clusterName string, serverName string) error {
 		return fmt.Errorf("new primary node %s not found in namespace %s: %w", serverName, plugin.Namespace, err)
 	}
 
+	if err := waitForRunningBackups(ctx, os.Stdout, &cluster, runningBackupsPollInterval); err != nil {
+		return err
+	}
+
 	// The Pod exists, let's update status fields
 	origCluster := cluster.DeepCopy()
 	cluster.Status.TargetPrimary = serverName
@@ -71,3 +87,78 @@ func Promote(ctx context.Context, clusterName string, serverName string) error {
 	fmt.Printf("Node %s in cluster %s will be promoted\n", serverName, clusterName)
 	return nil
 }
+
+// PromoteRecovery requests the promotion of a cluster whose
+// recovery has been paused at the recovery target
+func PromoteRecovery(ctx context.Context, clusterName string) error {
+	var cluster apiv1.Cluster
+
+	// Get the Cluster object
+	err := plugin.Client.Get(ctx, client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName}, &cluster)
+	if err != nil {
+		return fmt.Errorf("cluster %s not found in namespace %s: %w", clusterName, plugin.Namespace, err)
+	}
+
+	if !cluster.IsRecoveryPaused() {
+		return fmt.Errorf("the recovery of cluster %s is not paused at the recovery target, "+
+			"please specify the node to be promoted", clusterName)
+	}
+
+	condition := metav1.Condition{
+		Type:    string(apiv1.ConditionRecoveryPaused),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionReasonPromotionRequested),
+		Message: "The promotion of the cluster has been requested",
+	}
+	if err := conditions.Patch(ctx, plugin.Client, &cluster, &condition); err != nil {
+		return fmt.Errorf("while requesting the promotion of cluster %s: %w", clusterName, err)
+	}
+
+	fmt.Printf("Cluster %s will end the recovery and be promoted\n", clusterName)
+	return nil
+}
+
+// waitForRunningBackups defers the switchover until the running backups
+// complete, when the cluster is configured to block switchovers during backups
+func waitForRunningBackups(
+	ctx context.Context,
+	out io.Writer,
+	cluster *apiv1.Cluster,
+	pollInterval time.Duration,
+) error {
+	if !cluster.IsSwitchoverBlockedByBackups() {
+		return nil
+	}
+
+	var lastRunningBackups []string
+	err := wait.PollUntilContextCancel(ctx, pollInterval, true,
+		func(ctx context.Context) (bool, error) {
+			var backupList apiv1.BackupList
+			if err := plugin.Client.List(ctx, &backupList, client.InNamespace(cluster.Namespace)); err != nil {
+				return false, err
+			}
+
+			var clusterBackups apiv1.BackupList
+			for _, backup := range backupList.Items {
+				if backup.Spec.Cluster.Name == cluster.Name {
+					clusterBackups.Items = append(clusterBackups.Items, backup)
+				}
+			}
+
+			runningBackups := clusterBackups.GetRunningBackupNames()
+			if len(runningBackups) > 0 && !slices.Equal(runningBackups, lastRunningBackups) {
+				_, _ = fmt.Fprintf(out, "Cluster %s is configured to block switchovers during backups, "+
+					"waiting for these backups to complete: %s\n",
+					cluster.Name, strings.Join(runningBackups, ", "))
+			}
+			lastRunningBackups = runningBackups
+
+			return len(runningBackups) == 0, nil
+		})
+	if err != nil {
+		return fmt.Errorf("while waiting for the running backups of cluster %s to complete: %w",
+			cluster.Name, err)
+	}
+
+	return nil
+}
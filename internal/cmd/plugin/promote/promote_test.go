@@ -0,0 +1,93 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promote
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("switchover during a backup", func() {
+	const (
+		namespace   = "default"
+		clusterName = "cluster-example"
+	)
+
+	var (
+		cluster *apiv1.Cluster
+		backup  *apiv1.Backup
+		out     *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		out = &bytes.Buffer{}
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: clusterName},
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{BlockSwitchover: true},
+			},
+		}
+		backup = &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "backup-example"},
+			Spec:       apiv1.BackupSpec{Cluster: apiv1.LocalObjectReference{Name: clusterName}},
+			Status:     apiv1.BackupStatus{Phase: apiv1.BackupPhaseRunning},
+		}
+		plugin.Namespace = namespace
+		plugin.Client = fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(cluster, backup).
+			WithStatusSubresource(backup).
+			Build()
+	})
+
+	It("waits for the running backups to complete", func(ctx SpecContext) {
+		go func() {
+			defer GinkgoRecover()
+			time.Sleep(50 * time.Millisecond)
+			backup.Status.Phase = apiv1.BackupPhaseCompleted
+			Expect(plugin.Client.Status().Update(context.Background(), backup)).To(Succeed())
+		}()
+
+		Expect(waitForRunningBackups(ctx, out, cluster, 10*time.Millisecond)).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("waiting for these backups to complete: backup-example"))
+	})
+
+	It("keeps waiting while the backups are running", func(ctx SpecContext) {
+		waitCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+
+		Expect(waitForRunningBackups(waitCtx, out, cluster, 10*time.Millisecond)).
+			To(MatchError(ContainSubstring("while waiting for the running backups")))
+	})
+
+	It("doesn't wait when switchovers are not blocked by backups", func(ctx SpecContext) {
+		cluster.Spec.Backup.BlockSwitchover = false
+		Expect(waitForRunningBackups(ctx, out, cluster, 10*time.Millisecond)).To(Succeed())
+		Expect(out.String()).To(BeEmpty())
+	})
+})
@@ -19,6 +19,7 @@ package psql
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"syscall"
@@ -186,6 +187,22 @@ func (psql *Command) Output() ([]byte, error) {
 	return cmd.Output()
 }
 
+// Stream starts a psql process inside the target pod, connecting its
+// standard input and output to the passed reader and writer. The process
+// is killed when the passed context is canceled
+func (psql *Command) Stream(ctx context.Context, stdin io.Reader, stdout io.Writer) error {
+	kubectlExec, err := psql.getKubectlInvocation()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, psql.kubectlPath, kubectlExec[1:]...) // nolint:gosec
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
 // ErrMissingPod is raised when we can't find a Pod having the desired role
 type ErrMissingPod struct {
 	role string
@@ -0,0 +1,94 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+// NewCmd creates the new "replication" command
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "replication",
+		Short:   `Streaming replication related commands`,
+		GroupID: plugin.GroupIDCluster,
+	}
+
+	rotateCredentialsCmd := &cobra.Command{
+		Use:   "rotate-credentials [cluster]",
+		Short: `Rotate the credentials of the streaming_replica user`,
+		Long: `Requests the operator to issue a new TLS client certificate for the ` +
+			`streaming_replica user. Replicas pick up the new credentials with a ` +
+			`reload, without interrupting streaming replication.`,
+		Args: plugin.RequiresArguments(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return plugin.CompleteClusters(cmd.Context(), args, toComplete), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RotateCredentials(cmd.Context(), args[0])
+		},
+	}
+	cmd.AddCommand(rotateCredentialsCmd)
+	cmd.AddCommand(newSlotCmd())
+
+	return cmd
+}
+
+// newSlotCmd creates the new "replication slot" command
+func newSlotCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "slot",
+		Short: `Replication slots related commands`,
+	}
+
+	cmd.AddCommand(newSlotResetCmd())
+
+	return cmd
+}
+
+func newSlotResetCmd() *cobra.Command {
+	var slotName string
+	var force bool
+	var confirmationSkipped bool
+
+	slotResetCmd := &cobra.Command{
+		Use:   "reset [cluster]",
+		Short: `Drop and recreate a replication slot on the primary of [cluster]`,
+		Long: `Drop and recreate a physical or logical replication slot on the primary of [cluster],
+with the same type, output plugin and database. The slot restarts from the current
+position of the primary, so any WAL file retained only by it may be removed.
+A slot that is in use is not reset, unless --force is specified.`,
+		Args: plugin.RequiresArguments(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return plugin.CompleteClusters(cmd.Context(), args, toComplete), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return newSlotReset(args[0], slotName, force, !confirmationSkipped).run(cmd.Context())
+		},
+	}
+
+	slotResetCmd.Flags().StringVar(&slotName, "slot", "", "The name of the replication slot to be reset (required)")
+	_ = slotResetCmd.MarkFlagRequired("slot")
+	slotResetCmd.Flags().BoolVar(&force, "force", false,
+		"Reset the replication slot even if it is in use, disconnecting its consumer")
+	slotResetCmd.Flags().BoolVarP(&confirmationSkipped, "yes", "y", false,
+		"Reset the replication slot without asking for confirmation")
+
+	return slotResetCmd
+}
@@ -0,0 +1,61 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package replication implements the commands to manage the streaming
+// replication of a cluster
+package replication
+
+import (
+	"context"
+	"fmt"
+
+	pgTime "github.com/cloudnative-pg/machinery/pkg/postgres/time"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// RotateCredentials requests the operator to rotate the credentials
+// of the streaming replication user of the cluster
+func RotateCredentials(ctx context.Context, clusterName string) error {
+	var cluster apiv1.Cluster
+
+	err := plugin.Client.Get(ctx, client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName}, &cluster)
+	if err != nil {
+		return err
+	}
+
+	if cluster.Spec.Certificates != nil && cluster.Spec.Certificates.ReplicationTLSSecret != "" {
+		return fmt.Errorf("the replication TLS secret of cluster %s is user-provided and can't be rotated "+
+			"by the operator", clusterName)
+	}
+
+	origCluster := cluster.DeepCopy()
+	if cluster.Annotations == nil {
+		cluster.Annotations = make(map[string]string)
+	}
+	cluster.Annotations[utils.ReplicationCredentialsRotationAnnotationName] = pgTime.GetCurrentTimestamp()
+	cluster.ManagedFields = nil
+
+	if err := plugin.Client.Patch(ctx, &cluster, client.MergeFrom(origCluster)); err != nil {
+		return err
+	}
+
+	fmt.Printf("The streaming replication credentials of %s will be rotated\n", clusterName)
+	return nil
+}
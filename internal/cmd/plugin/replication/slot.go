@@ -0,0 +1,216 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/logical"
+)
+
+// maintenanceDatabase is the database used to inspect and recreate
+// the physical replication slots
+const maintenanceDatabase = "postgres"
+
+// replicationSlot is a row of pg_replication_slots, as returned by row_to_json
+type replicationSlot struct {
+	SlotName  string `json:"slot_name"`
+	Plugin    string `json:"plugin"`
+	SlotType  string `json:"slot_type"`
+	Database  string `json:"database"`
+	Temporary bool   `json:"temporary"`
+	Active    bool   `json:"active"`
+	ActivePID *int   `json:"active_pid"`
+	TwoPhase  bool   `json:"two_phase"`
+}
+
+// slotReset drops and recreates a replication slot on the primary
+// instance of a cluster
+type slotReset struct {
+	clusterName string
+	slotName    string
+
+	// force allows resetting a slot that is in use
+	force bool
+
+	// confirm asks the user whether to proceed with the reset of the slot
+	confirm func(slot *replicationSlot) bool
+
+	// runSQL executes a SQL statement in the passed database of the
+	// primary instance, returning its output
+	runSQL func(ctx context.Context, dbName string, sqlCommand string) (string, error)
+}
+
+// newSlotReset creates a new slotReset for the passed slot, asking for
+// confirmation if required
+func newSlotReset(clusterName, slotName string, force, confirmationRequired bool) *slotReset {
+	return &slotReset{
+		clusterName: clusterName,
+		slotName:    slotName,
+		force:       force,
+		confirm: func(slot *replicationSlot) bool {
+			if !confirmationRequired {
+				return true
+			}
+			return askToResetSlot(clusterName, slot)
+		},
+		runSQL: func(ctx context.Context, dbName string, sqlCommand string) (string, error) {
+			output, err := logical.RunSQLWithOutput(ctx, clusterName, dbName, sqlCommand)
+			return strings.TrimSpace(string(output)), err
+		},
+	}
+}
+
+func (sr *slotReset) run(ctx context.Context) error {
+	slot, err := sr.getSlot(ctx)
+	if err != nil {
+		return err
+	}
+
+	if slot.Temporary {
+		return fmt.Errorf("replication slot %s is temporary and can't be reset", sr.slotName)
+	}
+
+	if slot.Active {
+		if !sr.force {
+			return fmt.Errorf("replication slot %s is active (in use by PID %s), "+
+				"use --force to reset it anyway", sr.slotName, describePID(slot.ActivePID))
+		}
+		fmt.Printf("WARNING: replication slot %s is active (in use by PID %s), "+
+			"its consumer will be disconnected\n", sr.slotName, describePID(slot.ActivePID))
+	}
+
+	if !sr.confirm(slot) {
+		fmt.Println("Aborted, the replication slot has not been reset")
+		return nil
+	}
+
+	dbName := maintenanceDatabase
+	if slot.SlotType == "logical" {
+		// Logical replication slots are bound to the database
+		// they have been created in
+		dbName = slot.Database
+	}
+
+	for _, sqlCommand := range buildSlotResetCommands(slot) {
+		if _, err := sr.runSQL(ctx, dbName, sqlCommand); err != nil {
+			return fmt.Errorf("while resetting replication slot %s (%s): %w", sr.slotName, sqlCommand, err)
+		}
+	}
+
+	fmt.Printf("Replication slot %s has been reset\n", sr.slotName)
+	return nil
+}
+
+// getSlot gets the slot to be reset from the primary instance
+func (sr *slotReset) getSlot(ctx context.Context) (*replicationSlot, error) {
+	output, err := sr.runSQL(ctx, maintenanceDatabase, fmt.Sprintf(
+		"SELECT row_to_json(s) FROM pg_catalog.pg_replication_slots s WHERE slot_name = %s",
+		pq.QuoteLiteral(sr.slotName)))
+	if err != nil {
+		return nil, fmt.Errorf("while getting replication slot %s: %w", sr.slotName, err)
+	}
+	if output == "" {
+		return nil, fmt.Errorf("replication slot %s not found in the primary instance of cluster %s",
+			sr.slotName, sr.clusterName)
+	}
+
+	var slot replicationSlot
+	if err := json.Unmarshal([]byte(output), &slot); err != nil {
+		return nil, fmt.Errorf("while decoding replication slot %s: %w", sr.slotName, err)
+	}
+	return &slot, nil
+}
+
+// dropSlotCommand drops a replication slot, disconnecting its consumer.
+// A terminated walsender releases the slot asynchronously and the consumer
+// may connect again in the meantime, so the drop is retried until the slot
+// is not in use anymore
+const dropSlotCommand = `DO $$
+BEGIN
+  FOR attempt IN 1..%[2]d LOOP
+    PERFORM pg_catalog.pg_terminate_backend(active_pid) FROM pg_catalog.pg_replication_slots
+      WHERE slot_name = %[1]s AND active_pid IS NOT NULL;
+    BEGIN
+      PERFORM pg_catalog.pg_drop_replication_slot(%[1]s);
+      RETURN;
+    EXCEPTION WHEN object_in_use THEN
+      PERFORM pg_catalog.pg_sleep(0.1);
+    END;
+  END LOOP;
+  RAISE EXCEPTION 'replication slot %% is still in use', %[1]s;
+END
+$$`
+
+// dropSlotAttempts is the number of times the drop of a replication
+// slot is attempted, waiting 100ms between two attempts
+const dropSlotAttempts = 100
+
+// buildSlotResetCommands returns the SQL statements dropping and recreating
+// the passed slot with the same type, output plugin and options
+func buildSlotResetCommands(slot *replicationSlot) []string {
+	slotName := pq.QuoteLiteral(slot.SlotName)
+
+	commands := []string{fmt.Sprintf(dropSlotCommand, slotName, dropSlotAttempts)}
+
+	if slot.SlotType == "logical" {
+		createCommand := fmt.Sprintf("SELECT pg_catalog.pg_create_logical_replication_slot(%s, %s",
+			slotName, pq.QuoteLiteral(slot.Plugin))
+		if slot.TwoPhase {
+			createCommand += ", twophase => true"
+		}
+		commands = append(commands, createCommand+")")
+	} else {
+		commands = append(commands, fmt.Sprintf(
+			"SELECT pg_catalog.pg_create_physical_replication_slot(%s, immediately_reserve => true)", slotName))
+	}
+
+	return commands
+}
+
+// describePID returns a description of the PID of the
+// process using a replication slot
+func describePID(pid *int) string {
+	if pid == nil {
+		return "unknown"
+	}
+	return fmt.Sprintf("%d", *pid)
+}
+
+// askToResetSlot shows the replication slot that is going
+// to be reset and asks the user whether to proceed
+func askToResetSlot(clusterName string, slot *replicationSlot) bool {
+	fmt.Printf("The %s replication slot %s of cluster %s is going to be dropped and recreated.\n",
+		slot.SlotType, slot.SlotName, clusterName)
+	fmt.Println("Any WAL file retained only by this slot may be removed, and its consumer will " +
+		"restart from the current position of the primary.")
+	fmt.Printf("Do you want to proceed? [y/n]: ")
+	reader := bufio.NewReader(os.Stdin)
+	answer, err := reader.ReadString('\n')
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,117 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("replication slot reset", func() {
+	const clusterName = "cluster-example"
+
+	var executed []string
+
+	// newTestSlotReset creates a slotReset working on a primary
+	// having the passed slot, recording the executed statements
+	newTestSlotReset := func(slotJSON string, force bool) *slotReset {
+		executed = nil
+		return &slotReset{
+			clusterName: clusterName,
+			slotName:    "my_slot",
+			force:       force,
+			confirm: func(*replicationSlot) bool {
+				return true
+			},
+			runSQL: func(_ context.Context, dbName string, sqlCommand string) (string, error) {
+				if strings.Contains(sqlCommand, "row_to_json") {
+					return slotJSON, nil
+				}
+				executed = append(executed, dbName+": "+sqlCommand)
+				return "", nil
+			},
+		}
+	}
+
+	It("resets an inactive physical slot", func(ctx SpecContext) {
+		sr := newTestSlotReset(
+			`{"slot_name":"my_slot","plugin":null,"slot_type":"physical","database":null,`+
+				`"temporary":false,"active":false,"active_pid":null}`, false)
+		Expect(sr.run(ctx)).To(Succeed())
+		Expect(executed).To(HaveLen(2))
+		Expect(executed[0]).To(HavePrefix("postgres: DO $$"))
+		Expect(executed[1]).To(Equal(
+			"postgres: SELECT pg_catalog.pg_create_physical_replication_slot('my_slot', immediately_reserve => true)"))
+	})
+
+	It("resets an inactive logical slot in its database", func(ctx SpecContext) {
+		sr := newTestSlotReset(
+			`{"slot_name":"my_slot","plugin":"pgoutput","slot_type":"logical","database":"app",`+
+				`"temporary":false,"active":false,"active_pid":null,"two_phase":true}`, false)
+		Expect(sr.run(ctx)).To(Succeed())
+		Expect(executed).To(HaveLen(2))
+		Expect(executed[0]).To(HavePrefix("app: DO $$"))
+		Expect(executed[1]).To(Equal(
+			"app: SELECT pg_catalog.pg_create_logical_replication_slot('my_slot', 'pgoutput', twophase => true)"))
+	})
+
+	It("refuses to reset an active slot without force", func(ctx SpecContext) {
+		sr := newTestSlotReset(
+			`{"slot_name":"my_slot","plugin":null,"slot_type":"physical","database":null,`+
+				`"temporary":false,"active":true,"active_pid":1234}`, false)
+		Expect(sr.run(ctx)).To(MatchError(ContainSubstring("is active (in use by PID 1234)")))
+		Expect(executed).To(BeEmpty())
+	})
+
+	It("disconnects the consumer of an active slot with force", func(ctx SpecContext) {
+		sr := newTestSlotReset(
+			`{"slot_name":"my_slot","plugin":null,"slot_type":"physical","database":null,`+
+				`"temporary":false,"active":true,"active_pid":1234}`, true)
+		Expect(sr.run(ctx)).To(Succeed())
+		Expect(executed).To(HaveLen(2))
+		Expect(executed[0]).To(ContainSubstring("pg_terminate_backend(active_pid)"))
+	})
+
+	It("terminates the consumer and drops the slot in a single statement", func() {
+		commands := buildSlotResetCommands(&replicationSlot{SlotName: "my_slot", SlotType: "physical"})
+		Expect(commands).To(HaveLen(2))
+		Expect(commands[0]).To(ContainSubstring(
+			"PERFORM pg_catalog.pg_terminate_backend(active_pid) FROM pg_catalog.pg_replication_slots\n" +
+				"      WHERE slot_name = 'my_slot' AND active_pid IS NOT NULL;"))
+		Expect(commands[0]).To(ContainSubstring("PERFORM pg_catalog.pg_drop_replication_slot('my_slot');"))
+		Expect(commands[0]).To(ContainSubstring("EXCEPTION WHEN object_in_use THEN"))
+		Expect(commands[0]).To(ContainSubstring("FOR attempt IN 1..100 LOOP"))
+		Expect(commands[0]).To(ContainSubstring("RAISE EXCEPTION 'replication slot % is still in use', 'my_slot';"))
+	})
+
+	It("doesn't reset the slot when the user doesn't confirm", func(ctx SpecContext) {
+		sr := newTestSlotReset(
+			`{"slot_name":"my_slot","plugin":null,"slot_type":"physical","database":null,`+
+				`"temporary":false,"active":false,"active_pid":null}`, false)
+		sr.confirm = func(*replicationSlot) bool { return false }
+		Expect(sr.run(ctx)).To(Succeed())
+		Expect(executed).To(BeEmpty())
+	})
+
+	It("fails when the slot doesn't exist", func(ctx SpecContext) {
+		sr := newTestSlotReset("", false)
+		Expect(sr.run(ctx)).To(MatchError(ContainSubstring("not found")))
+	})
+})
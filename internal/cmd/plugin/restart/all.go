@@ -0,0 +1,247 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package restart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/promote"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/plugin/resources"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// rollPollInterval is the interval between two checks of the
+// state of the cluster while rolling it
+const rollPollInterval = 2 * time.Second
+
+// clusterRoll restarts every instance of a cluster, one at a time,
+// beginning with the replicas and ending with the primary, which
+// is restarted via a switchover
+type clusterRoll struct {
+	clusterName string
+
+	// waitForReplica waits for a restarted replica to be ready
+	// and streaming from the primary
+	waitForReplica func(ctx context.Context, instanceName string, oldUID types.UID) error
+
+	// waitForSwitchover waits for the switchover to the target
+	// primary to be completed
+	waitForSwitchover func(ctx context.Context, targetPrimary string) error
+}
+
+// newClusterRoll creates a new clusterRoll for the passed cluster, waiting
+// at most timeout for each instance to be back after its restart
+func newClusterRoll(clusterName string, timeout time.Duration) *clusterRoll {
+	return &clusterRoll{
+		clusterName: clusterName,
+		waitForReplica: func(ctx context.Context, instanceName string, oldUID types.UID) error {
+			return waitForReplicaStreaming(ctx, clusterName, instanceName, oldUID, timeout)
+		},
+		waitForSwitchover: func(ctx context.Context, targetPrimary string) error {
+			return waitForSwitchoverCompleted(ctx, clusterName, targetPrimary, timeout)
+		},
+	}
+}
+
+// restartAll restarts every instance of the cluster, in the right order
+func restartAll(ctx context.Context, clusterName string, timeout time.Duration) error {
+	return newClusterRoll(clusterName, timeout).run(ctx)
+}
+
+func (roll *clusterRoll) run(ctx context.Context) error {
+	var cluster apiv1.Cluster
+	if err := plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: roll.clusterName},
+		&cluster,
+	); err != nil {
+		return fmt.Errorf("while trying to get cluster %v: %w", roll.clusterName, err)
+	}
+
+	if err := checkClusterCanBeRolled(&cluster); err != nil {
+		return err
+	}
+
+	replicas, primary := getRollOrder(&cluster)
+	steps := len(replicas) + 1
+
+	for idx, instanceName := range replicas {
+		fmt.Printf("[%d/%d] restarting replica %s\n", idx+1, steps, instanceName)
+
+		var pod corev1.Pod
+		if err := plugin.Client.Get(
+			ctx,
+			client.ObjectKey{Namespace: plugin.Namespace, Name: instanceName},
+			&pod,
+		); err != nil {
+			return fmt.Errorf("while getting POD %v: %w", instanceName, err)
+		}
+		if err := plugin.Client.Delete(ctx, &pod); err != nil {
+			return fmt.Errorf("while deleting POD %v: %w", instanceName, err)
+		}
+
+		if err := roll.waitForReplica(ctx, instanceName, pod.UID); err != nil {
+			return fmt.Errorf("while waiting for replica %v to be streaming: %w", instanceName, err)
+		}
+		fmt.Printf("[%d/%d] replica %s is ready and streaming\n", idx+1, steps, instanceName)
+	}
+
+	targetPrimary := replicas[0]
+	fmt.Printf("[%d/%d] restarting primary %s via switchover to %s\n", steps, steps, primary, targetPrimary)
+	if err := promote.Promote(ctx, roll.clusterName, targetPrimary); err != nil {
+		return fmt.Errorf("while switching over to %v: %w", targetPrimary, err)
+	}
+	if err := roll.waitForSwitchover(ctx, targetPrimary); err != nil {
+		return fmt.Errorf("while waiting for the switchover to %v: %w", targetPrimary, err)
+	}
+
+	fmt.Printf("%s restarted, the current primary is %s\n", roll.clusterName, targetPrimary)
+	return nil
+}
+
+// checkClusterCanBeRolled returns an error if the cluster is not
+// in a state allowing a safe restart of all its instances
+func checkClusterCanBeRolled(cluster *apiv1.Cluster) error {
+	if cluster.Status.Phase != apiv1.PhaseHealthy {
+		return fmt.Errorf("cluster %s is not healthy (phase: %q), refusing to restart it",
+			cluster.Name, cluster.Status.Phase)
+	}
+
+	if cluster.Status.CurrentPrimary != cluster.Status.TargetPrimary {
+		return fmt.Errorf("cluster %s has a switchover in progress, refusing to restart it", cluster.Name)
+	}
+
+	if cluster.Status.ReadyInstances != cluster.Spec.Instances {
+		return fmt.Errorf("cluster %s has %d ready instances out of %d, refusing to restart it",
+			cluster.Name, cluster.Status.ReadyInstances, cluster.Spec.Instances)
+	}
+
+	fencedInstances, err := utils.GetFencedInstances(cluster.Annotations)
+	if err != nil {
+		return err
+	}
+	if fencedInstances.Len() > 0 {
+		return fmt.Errorf("cluster %s has fenced instances, refusing to restart it", cluster.Name)
+	}
+
+	if cluster.Annotations[utils.HibernationAnnotationName] == string(utils.HibernationAnnotationValueOn) {
+		return fmt.Errorf("cluster %s is hibernated, refusing to restart it", cluster.Name)
+	}
+
+	if len(cluster.Status.InstanceNames) < 2 {
+		return fmt.Errorf("cluster %s has a single instance and cannot be restarted without downtime, "+
+			"use `restart %s %s` instead", cluster.Name, cluster.Name, cluster.Status.CurrentPrimary)
+	}
+
+	return nil
+}
+
+// getRollOrder returns the replicas of the cluster, in the order they
+// should be restarted, and the primary, which should be restarted last
+func getRollOrder(cluster *apiv1.Cluster) (replicas []string, primary string) {
+	primary = cluster.Status.CurrentPrimary
+	for _, instanceName := range cluster.Status.InstanceNames {
+		if instanceName != primary {
+			replicas = append(replicas, instanceName)
+		}
+	}
+	slices.Sort(replicas)
+
+	return replicas, primary
+}
+
+// waitForReplicaStreaming waits for the Pod of a replica to be recreated,
+// ready, and streaming from the current primary
+func waitForReplicaStreaming(
+	ctx context.Context,
+	clusterName, instanceName string,
+	oldUID types.UID,
+	timeout time.Duration,
+) error {
+	return wait.PollUntilContextTimeout(ctx, rollPollInterval, timeout, false,
+		func(ctx context.Context) (bool, error) {
+			var pod corev1.Pod
+			err := plugin.Client.Get(ctx, client.ObjectKey{Namespace: plugin.Namespace, Name: instanceName}, &pod)
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			if err != nil {
+				return false, err
+			}
+			if pod.UID == oldUID || !utils.IsPodReady(pod) {
+				return false, nil
+			}
+
+			_, primaryPod, err := resources.GetInstancePods(ctx, clusterName)
+			if err != nil {
+				return false, err
+			}
+			if primaryPod.Name == "" {
+				return false, nil
+			}
+
+			status, errs := resources.ExtractInstancesStatus(ctx, plugin.Config, []corev1.Pod{primaryPod})
+			if len(errs) > 0 || len(status.Items) == 0 {
+				return false, nil
+			}
+
+			return slices.ContainsFunc(status.Items[0].ReplicationInfo, func(info postgres.PgStatReplication) bool {
+				return info.ApplicationName == instanceName && info.State == "streaming"
+			}), nil
+		})
+}
+
+// waitForSwitchoverCompleted waits for the target primary to be the current
+// primary of a healthy cluster
+func waitForSwitchoverCompleted(
+	ctx context.Context,
+	clusterName, targetPrimary string,
+	timeout time.Duration,
+) error {
+	return wait.PollUntilContextTimeout(ctx, rollPollInterval, timeout, false,
+		func(ctx context.Context) (bool, error) {
+			var cluster apiv1.Cluster
+			if err := plugin.Client.Get(
+				ctx,
+				client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName},
+				&cluster,
+			); err != nil {
+				return false, err
+			}
+
+			if cluster.Status.TargetPrimary != targetPrimary {
+				return false, errors.New("the target primary has been changed while waiting for the switchover")
+			}
+
+			return cluster.Status.CurrentPrimary == targetPrimary &&
+				cluster.Status.Phase == apiv1.PhaseHealthy &&
+				cluster.Status.ReadyInstances == cluster.Spec.Instances, nil
+		})
+}
@@ -0,0 +1,186 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package restart
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("restart --all", func() {
+	const (
+		namespace   = "default"
+		clusterName = "cluster-example"
+	)
+
+	var cluster *apiv1.Cluster
+
+	newPod := func(name string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      name,
+				UID:       types.UID(name + "-uid"),
+			},
+		}
+	}
+
+	podExists := func(ctx context.Context, name string) bool {
+		var pod corev1.Pod
+		err := plugin.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &pod)
+		if apierrors.IsNotFound(err) {
+			return false
+		}
+		Expect(err).ToNot(HaveOccurred())
+		return true
+	}
+
+	getCluster := func(ctx context.Context) *apiv1.Cluster {
+		var current apiv1.Cluster
+		Expect(plugin.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: clusterName}, &current)).
+			To(Succeed())
+		return &current
+	}
+
+	setupClient := func() {
+		plugin.Namespace = namespace
+		plugin.Client = fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(
+				cluster,
+				newPod("cluster-example-1"),
+				newPod("cluster-example-2"),
+				newPod("cluster-example-3"),
+			).
+			WithStatusSubresource(&apiv1.Cluster{}).
+			Build()
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      clusterName,
+			},
+			Spec: apiv1.ClusterSpec{
+				Instances: 3,
+			},
+			Status: apiv1.ClusterStatus{
+				Phase:          apiv1.PhaseHealthy,
+				CurrentPrimary: "cluster-example-2",
+				TargetPrimary:  "cluster-example-2",
+				ReadyInstances: 3,
+				InstanceNames:  []string{"cluster-example-1", "cluster-example-2", "cluster-example-3"},
+			},
+		}
+	})
+
+	It("restarts the replicas first and the primary last via a switchover", func(ctx SpecContext) {
+		setupClient()
+
+		var steps []string
+		roll := &clusterRoll{
+			clusterName: clusterName,
+			waitForReplica: func(ctx context.Context, instanceName string, oldUID types.UID) error {
+				// The replica has been restarted, while the primary is still
+				// up and running, accepting writes
+				Expect(oldUID).To(Equal(types.UID(instanceName + "-uid")))
+				Expect(podExists(ctx, instanceName)).To(BeFalse())
+				Expect(podExists(ctx, "cluster-example-2")).To(BeTrue())
+				Expect(getCluster(ctx).Status.TargetPrimary).To(Equal("cluster-example-2"))
+
+				// Simulate the operator recreating the Pod
+				recreatedPod := newPod(instanceName)
+				recreatedPod.UID = types.UID(instanceName + "-new-uid")
+				Expect(plugin.Client.Create(ctx, recreatedPod)).To(Succeed())
+
+				steps = append(steps, "replica "+instanceName)
+				return nil
+			},
+			waitForSwitchover: func(ctx context.Context, targetPrimary string) error {
+				// The switchover is the only operation involving the primary,
+				// which has never been deleted
+				Expect(podExists(ctx, "cluster-example-2")).To(BeTrue())
+				current := getCluster(ctx)
+				Expect(current.Status.TargetPrimary).To(Equal(targetPrimary))
+				Expect(current.Status.Phase).To(Equal(apiv1.PhaseSwitchover))
+
+				steps = append(steps, "switchover "+targetPrimary)
+				return nil
+			},
+		}
+
+		Expect(roll.run(ctx)).To(Succeed())
+		Expect(steps).To(Equal([]string{
+			"replica cluster-example-1",
+			"replica cluster-example-3",
+			"switchover cluster-example-1",
+		}))
+	})
+
+	DescribeTable("refuses to restart a cluster that is not in a safe state",
+		func(ctx SpecContext, mutate func(cluster *apiv1.Cluster)) {
+			mutate(cluster)
+			setupClient()
+
+			roll := &clusterRoll{
+				clusterName: clusterName,
+				waitForReplica: func(context.Context, string, types.UID) error {
+					Fail("no replica should be restarted")
+					return nil
+				},
+				waitForSwitchover: func(context.Context, string) error {
+					Fail("no switchover should be requested")
+					return nil
+				},
+			}
+			Expect(roll.run(ctx)).ToNot(Succeed())
+			Expect(podExists(ctx, "cluster-example-1")).To(BeTrue())
+			Expect(podExists(ctx, "cluster-example-3")).To(BeTrue())
+		},
+		Entry("unhealthy cluster", func(cluster *apiv1.Cluster) {
+			cluster.Status.Phase = apiv1.PhaseFailOver
+		}),
+		Entry("missing ready instances", func(cluster *apiv1.Cluster) {
+			cluster.Status.ReadyInstances = 2
+		}),
+		Entry("fenced instance", func(cluster *apiv1.Cluster) {
+			cluster.Annotations = map[string]string{
+				utils.FencedInstanceAnnotation: `["cluster-example-3"]`,
+			}
+		}),
+		Entry("single instance cluster", func(cluster *apiv1.Cluster) {
+			cluster.Spec.Instances = 1
+			cluster.Status.ReadyInstances = 1
+			cluster.Status.InstanceNames = []string{"cluster-example-2"}
+		}),
+	)
+})
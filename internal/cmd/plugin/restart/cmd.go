@@ -19,6 +19,7 @@ package restart
 import (
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -27,18 +28,29 @@ import (
 
 // NewCmd creates the new "reset" command
 func NewCmd() *cobra.Command {
+	var all bool
+	var timeout time.Duration
+
 	restartCmd := &cobra.Command{
 		Use:   "restart clusterName [instance]",
 		Short: `Restart a cluster or a single instance in a cluster`,
 		Long: `If only the cluster name is specified, the whole cluster will be restarted, 
 rolling out new configurations if present.
 If a specific instance is specified, only that instance will be restarted, 
-in-place if it is a primary, deleting the pod if it is a replica.`,
+in-place if it is a primary, deleting the pod if it is a replica.
+With --all, every instance is restarted one at a time, replicas first, waiting
+for each of them to be ready and streaming, and the primary last, via a switchover.`,
 		Args:    cobra.RangeArgs(1, 2),
 		GroupID: plugin.GroupIDCluster,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			clusterName := args[0]
+			if all {
+				if len(args) > 1 {
+					return fmt.Errorf("cannot specify an instance together with --all")
+				}
+				return restartAll(ctx, clusterName, timeout)
+			}
 			if len(args) == 1 {
 				return restart(ctx, clusterName)
 			}
@@ -50,5 +62,10 @@ in-place if it is a primary, deleting the pod if it is a replica.`,
 		},
 	}
 
+	restartCmd.Flags().BoolVar(&all, "all", false,
+		"Restart every instance, replicas first and the primary last via a switchover")
+	restartCmd.Flags().DurationVar(&timeout, "timeout", 10*time.Minute,
+		"The maximum time to wait for each instance to be back after its restart, used with --all")
+
 	return restartCmd
 }
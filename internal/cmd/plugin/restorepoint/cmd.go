@@ -0,0 +1,58 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package restorepoint implements the commands to manage the named
+// restore points of a cluster
+package restorepoint
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+// NewCmd creates the new "restore-point" command
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "restore-point",
+		Short:   `Named restore points related commands`,
+		GroupID: plugin.GroupIDDatabase,
+	}
+
+	cmd.AddCommand(newCreateCmd())
+
+	return cmd
+}
+
+func newCreateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "create [cluster] [name]",
+		Short: `Create a named restore point on the primary of [cluster]`,
+		Long: `Create a named restore point on the primary of [cluster], printing its LSN.
+The name of the restore point can be used as the recovery target (targetName)
+when bootstrapping a new cluster from a backup of [cluster].`,
+		Args: plugin.RequiresArguments(2),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) != 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return plugin.CompleteClusters(cmd.Context(), args, toComplete), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return newRestorePointCreation(args[0], args[1]).run(cmd.Context())
+		},
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package restorepoint
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/logical"
+)
+
+// maxRestorePointNameLength is the maximum length of the name of
+// a restore point accepted by PostgreSQL
+const maxRestorePointNameLength = 63
+
+// restorePointCreation creates a named restore point on the primary
+// instance of a cluster
+type restorePointCreation struct {
+	clusterName      string
+	restorePointName string
+
+	// getCluster gets the cluster where the restore point is created
+	getCluster func(ctx context.Context) (*apiv1.Cluster, error)
+
+	// runSQL executes a SQL statement on the primary instance,
+	// returning its output
+	runSQL func(ctx context.Context, sqlCommand string) (string, error)
+}
+
+// newRestorePointCreation creates a new restorePointCreation for
+// the passed cluster
+func newRestorePointCreation(clusterName, restorePointName string) *restorePointCreation {
+	return &restorePointCreation{
+		clusterName:      clusterName,
+		restorePointName: restorePointName,
+		getCluster: func(ctx context.Context) (*apiv1.Cluster, error) {
+			var cluster apiv1.Cluster
+			err := plugin.Client.Get(ctx, client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName}, &cluster)
+			return &cluster, err
+		},
+		runSQL: func(ctx context.Context, sqlCommand string) (string, error) {
+			output, err := logical.RunSQLWithOutput(ctx, clusterName, "postgres", sqlCommand)
+			return strings.TrimSpace(string(output)), err
+		},
+	}
+}
+
+func (rp *restorePointCreation) run(ctx context.Context) error {
+	if rp.restorePointName == "" {
+		return fmt.Errorf("the name of the restore point can't be empty")
+	}
+	if len(rp.restorePointName) > maxRestorePointNameLength {
+		return fmt.Errorf("the name of the restore point can't be longer than %d characters",
+			maxRestorePointNameLength)
+	}
+
+	cluster, err := rp.getCluster(ctx)
+	if err != nil {
+		return fmt.Errorf("while getting cluster %s: %w", rp.clusterName, err)
+	}
+
+	// Restore points are WAL records, and they can only
+	// be written by a primary instance
+	if cluster.IsReplica() {
+		return fmt.Errorf("cluster %s is a replica cluster and can't create restore points, "+
+			"create them in the source cluster instead", rp.clusterName)
+	}
+	if cluster.Status.CurrentPrimary == "" {
+		return fmt.Errorf("cluster %s has no primary instance", rp.clusterName)
+	}
+
+	lsn, err := rp.runSQL(ctx, fmt.Sprintf("SELECT pg_catalog.pg_create_restore_point(%s)",
+		pq.QuoteLiteral(rp.restorePointName)))
+	if err != nil {
+		return fmt.Errorf("while creating restore point %s on %s: %w",
+			rp.restorePointName, cluster.Status.CurrentPrimary, err)
+	}
+
+	fmt.Printf("Restore point %s created on %s at LSN %s\n",
+		rp.restorePointName, cluster.Status.CurrentPrimary, lsn)
+	return nil
+}
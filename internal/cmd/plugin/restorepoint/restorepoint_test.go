@@ -0,0 +1,85 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package restorepoint
+
+import (
+	"context"
+	"strings"
+
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("restore point creation", func() {
+	var (
+		cluster  *apiv1.Cluster
+		executed []string
+	)
+
+	BeforeEach(func() {
+		executed = nil
+		cluster = &apiv1.Cluster{
+			Status: apiv1.ClusterStatus{CurrentPrimary: "cluster-example-1"},
+		}
+	})
+
+	newTestRestorePointCreation := func(name string) *restorePointCreation {
+		return &restorePointCreation{
+			clusterName:      "cluster-example",
+			restorePointName: name,
+			getCluster: func(context.Context) (*apiv1.Cluster, error) {
+				return cluster, nil
+			},
+			runSQL: func(_ context.Context, sqlCommand string) (string, error) {
+				executed = append(executed, sqlCommand)
+				return "0/3000090", nil
+			},
+		}
+	}
+
+	It("creates the restore point on the primary", func(ctx SpecContext) {
+		Expect(newTestRestorePointCreation("before_upgrade").run(ctx)).To(Succeed())
+		Expect(executed).To(Equal([]string{
+			"SELECT pg_catalog.pg_create_restore_point('before_upgrade')",
+		}))
+	})
+
+	It("quotes the name of the restore point", func(ctx SpecContext) {
+		Expect(newTestRestorePointCreation("it's here").run(ctx)).To(Succeed())
+		Expect(executed).To(Equal([]string{
+			"SELECT pg_catalog.pg_create_restore_point('it''s here')",
+		}))
+	})
+
+	It("refuses to create a restore point in a replica cluster", func(ctx SpecContext) {
+		cluster.Spec.ReplicaCluster = &apiv1.ReplicaClusterConfiguration{Enabled: ptr.To(true)}
+		Expect(newTestRestorePointCreation("before_upgrade").run(ctx)).
+			To(MatchError(ContainSubstring("is a replica cluster")))
+		Expect(executed).To(BeEmpty())
+	})
+
+	It("refuses an invalid name", func(ctx SpecContext) {
+		Expect(newTestRestorePointCreation("").run(ctx)).To(HaveOccurred())
+		Expect(newTestRestorePointCreation(strings.Repeat("x", 64)).run(ctx)).
+			To(MatchError(ContainSubstring("longer than 63 characters")))
+		Expect(executed).To(BeEmpty())
+	})
+})
@@ -69,6 +69,10 @@ type PostgresqlStatus struct {
 
 	// The size of the cluster
 	TotalClusterSize string
+
+	// RunningBackups contains the backups of the cluster which are
+	// currently running
+	RunningBackups []apiv1.Backup `json:"runningBackups,omitempty"`
 }
 
 func (fullStatus *PostgresqlStatus) getReplicationSlotList() postgres.PgReplicationSlotList {
@@ -127,6 +131,7 @@ func Status(
 
 	status.printBasicInfo(ctx, clientInterface)
 	status.printHibernationInfo()
+	status.printSafeModeInfo()
 	status.printDemotionTokenInfo()
 	status.printPromotionTokenInfo()
 	if verbosity > 1 {
@@ -134,6 +139,7 @@ func Status(
 		status.printCertificatesStatus()
 	}
 	status.printBackupStatus()
+	status.printRunningBackups()
 	status.printBasebackupStatus(verbosity)
 	status.printReplicaStatus(verbosity)
 	if verbosity > 0 {
@@ -185,6 +191,18 @@ func extractPostgresqlStatus(ctx context.Context, cluster apiv1.Cluster) *Postgr
 	); err != nil {
 		errs = append(errs, err)
 	}
+
+	var backupList apiv1.BackupList
+	if err := plugin.Client.List(ctx, &backupList, client.InNamespace(plugin.Namespace)); err != nil {
+		errs = append(errs, err)
+	}
+	var runningBackups []apiv1.Backup
+	for _, backup := range backupList.Items {
+		if backup.Spec.Cluster.Name == cluster.Name && backup.Status.Phase == apiv1.BackupPhaseRunning {
+			runningBackups = append(runningBackups, backup)
+		}
+	}
+
 	// Extract the status from the instances
 	status := PostgresqlStatus{
 		Cluster:                 &cluster,
@@ -192,6 +210,7 @@ func extractPostgresqlStatus(ctx context.Context, cluster apiv1.Cluster) *Postgr
 		PrimaryPod:              primaryPod,
 		PodDisruptionBudgetList: pdbl,
 		ErrorList:               errs,
+		RunningBackups:          runningBackups,
 	}
 	return &status
 }
@@ -207,13 +226,14 @@ func (fullStatus *PostgresqlStatus) getClusterSize(ctx context.Context, client k
 	timeout := time.Second * 10
 
 	// Compute the disk space through `du`
-	output, _, err := utils.ExecCommand(
+	output, _, err := utils.ExecCommandWithRetry(
 		ctx,
 		client,
 		plugin.Config,
 		fullStatus.PrimaryPod,
 		specs.PostgresContainerName,
 		&timeout,
+		utils.DefaultExecBackoff,
 		"du",
 		"-sLh",
 		specs.PgDataPath)
@@ -343,6 +363,27 @@ func (fullStatus *PostgresqlStatus) printHibernationInfo() {
 	fmt.Println()
 }
 
+func (fullStatus *PostgresqlStatus) printSafeModeInfo() {
+	cluster := fullStatus.Cluster
+
+	safeModeCondition := meta.FindStatusCondition(
+		cluster.Status.Conditions,
+		string(apiv1.ConditionSafeMode),
+	)
+	if safeModeCondition == nil || safeModeCondition.Status != metav1.ConditionTrue {
+		return
+	}
+
+	safeModeStatus := tabby.New()
+	safeModeStatus.AddLine("Message", safeModeCondition.Message)
+	safeModeStatus.AddLine("Time", safeModeCondition.LastTransitionTime.Time.UTC())
+
+	fmt.Println(aurora.Yellow("Safe mode"))
+	safeModeStatus.Print()
+
+	fmt.Println()
+}
+
 func (fullStatus *PostgresqlStatus) printTokenStatus(token string) {
 	primaryInstanceStatus := fullStatus.tryGetPrimaryInstance()
 
@@ -449,9 +490,10 @@ func (fullStatus *PostgresqlStatus) printPostgresConfiguration(
 	var errs []error
 
 	// Read PostgreSQL configuration from custom.conf
-	customConf, _, err := utils.ExecCommand(ctx, client, plugin.Config, fullStatus.PrimaryPod,
+	customConf, _, err := utils.ExecCommandWithRetry(ctx, client, plugin.Config, fullStatus.PrimaryPod,
 		specs.PostgresContainerName,
 		&timeout,
+		utils.DefaultExecBackoff,
 		"cat",
 		path.Join(specs.PgDataPath, constants.PostgresqlCustomConfigurationFile))
 	if err != nil {
@@ -459,9 +501,9 @@ func (fullStatus *PostgresqlStatus) printPostgresConfiguration(
 	}
 
 	// Read PostgreSQL HBA Rules from pg_hba.conf
-	pgHBAConf, _, err := utils.ExecCommand(ctx, client, plugin.Config, fullStatus.PrimaryPod,
+	pgHBAConf, _, err := utils.ExecCommandWithRetry(ctx, client, plugin.Config, fullStatus.PrimaryPod,
 		specs.PostgresContainerName,
-		&timeout, "cat", path.Join(specs.PgDataPath, constants.PostgresqlHBARulesFile))
+		&timeout, utils.DefaultExecBackoff, "cat", path.Join(specs.PgDataPath, constants.PostgresqlHBARulesFile))
 	if err != nil {
 		errs = append(errs, err)
 	}
@@ -519,6 +561,65 @@ func (fullStatus *PostgresqlStatus) printBackupStatus() {
 	fmt.Println()
 }
 
+func (fullStatus *PostgresqlStatus) printRunningBackups() {
+	if len(fullStatus.RunningBackups) == 0 {
+		return
+	}
+
+	fmt.Println(aurora.Green("Running backups"))
+	status := tabby.New()
+	status.AddHeader("Name", "Method", "Started at", "Transferred", "Progress")
+	for _, backup := range fullStatus.RunningBackups {
+		startedAt := "-"
+		if backup.Status.StartedAt != nil {
+			startedAt = backup.Status.StartedAt.Format(time.RFC3339)
+		}
+		transferred := "-"
+		if backup.Status.BytesTransferred != nil {
+			transferred = formatBytes(*backup.Status.BytesTransferred)
+		}
+		status.AddLine(
+			backup.Name,
+			backup.Status.Method,
+			startedAt,
+			transferred,
+			getBackupProgressBar(backup.Status.BackupProgress),
+		)
+	}
+	status.Print()
+	fmt.Println()
+}
+
+// getBackupProgressBar renders the progress of a running backup, which
+// is only known for some of the backup methods
+func getBackupProgressBar(progress *int32) string {
+	const width = 20
+
+	if progress == nil {
+		return "running"
+	}
+
+	done := int(*progress) * width / 100
+	return fmt.Sprintf("[%s%s] %d%%",
+		strings.Repeat("#", done), strings.Repeat("-", width-done), *progress)
+}
+
+// formatBytes renders an amount of bytes with a binary unit
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	value := float64(size)
+	exponent := 0
+	for value >= unit*unit && exponent < 4 {
+		value /= unit
+		exponent++
+	}
+	return fmt.Sprintf("%.1f %ciB", value/unit, "KMGTP"[exponent])
+}
+
 func getWalArchivingStatus(isArchivingWAL bool, lastFailedWAL string) string {
 	switch {
 	case isArchivingWAL:
@@ -715,6 +816,8 @@ func (fullStatus *PostgresqlStatus) printInstancesStatus() {
 		"Replication role",
 		"Status",
 		"QoS",
+		"Write Lag Bytes",
+		"Replay Lag Bytes",
 		"Manager Version",
 		"Node")
 
@@ -729,6 +832,8 @@ func (fullStatus *PostgresqlStatus) printInstancesStatus() {
 				apierrs.ReasonForError(instance.Error),
 				instance.Pod.Status.QOSClass,
 				"-",
+				"-",
+				"-",
 				instance.Pod.Spec.NodeName,
 			)
 			continue
@@ -739,12 +844,15 @@ func (fullStatus *PostgresqlStatus) printInstancesStatus() {
 		}
 
 		replicaRole := getReplicaRole(instance, fullStatus)
+		writeLag, replayLag := fullStatus.getPrintableStandbyLag(instance.Pod.Name)
 		status.AddLine(
 			instance.Pod.Name,
 			getCurrentLSN(instance),
 			replicaRole,
 			statusMsg,
 			instance.Pod.Status.QOSClass,
+			writeLag,
+			replayLag,
 			instance.InstanceManagerVersion,
 			instance.Pod.Spec.NodeName,
 		)
@@ -815,6 +923,25 @@ func (fullStatus *PostgresqlStatus) tryGetPrimaryInstance() *postgres.Postgresql
 	return nil
 }
 
+// getPrintableStandbyLag gets the amount of WAL not yet written and not yet
+// replayed by a standby, as reported by the primary in pg_stat_replication.
+// The lag is not available for the primary and for the standbys that are
+// not streaming from it, e.g. while they are starting up
+func (fullStatus *PostgresqlStatus) getPrintableStandbyLag(instanceName string) (string, string) {
+	primaryInstanceStatus := fullStatus.tryGetPrimaryInstance()
+	if primaryInstanceStatus == nil {
+		return "-", "-"
+	}
+
+	for _, replication := range primaryInstanceStatus.ReplicationInfo {
+		if replication.ApplicationName == instanceName {
+			return formatBytes(replication.WriteLagBytes), formatBytes(replication.ReplayLagBytes)
+		}
+	}
+
+	return "-", "-"
+}
+
 func getCurrentLSN(instance postgres.PostgresqlStatus) types.LSN {
 	if instance.IsPrimary {
 		return instance.CurrentLsn
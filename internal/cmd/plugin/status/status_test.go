@@ -20,9 +20,12 @@ import (
 	"fmt"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -76,3 +79,76 @@ var _ = Describe("getPrimaryStartTime", func() {
 		})
 	})
 })
+
+var _ = Describe("getBackupProgressBar", func() {
+	It("renders the progress of the backup", func() {
+		Expect(getBackupProgressBar(ptr.To(int32(45)))).To(Equal("[#########-----------] 45%"))
+		Expect(getBackupProgressBar(ptr.To(int32(100)))).To(Equal("[####################] 100%"))
+	})
+
+	It("shows the backup as running when the progress is unknown", func() {
+		Expect(getBackupProgressBar(nil)).To(Equal("running"))
+	})
+})
+
+var _ = Describe("formatBytes", func() {
+	It("uses binary units", func() {
+		Expect(formatBytes(512)).To(Equal("512 B"))
+		Expect(formatBytes(1536)).To(Equal("1.5 KiB"))
+		Expect(formatBytes(3 * 1024 * 1024 * 1024)).To(Equal("3.0 GiB"))
+	})
+})
+
+var _ = Describe("getPrintableStandbyLag", func() {
+	newStatus := func(replicationInfo ...postgres.PgStatReplication) *PostgresqlStatus {
+		return &PostgresqlStatus{
+			Cluster: &apiv1.Cluster{},
+			InstanceStatus: &postgres.PostgresqlStatusList{
+				Items: []postgres.PostgresqlStatus{
+					{
+						Pod:             &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+						IsPrimary:       true,
+						ReplicationInfo: replicationInfo,
+					},
+					{
+						Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}},
+					},
+				},
+			},
+		}
+	}
+
+	It("reports the lag of a standby streaming from the primary", func() {
+		fullStatus := newStatus(postgres.PgStatReplication{
+			ApplicationName: "cluster-example-2",
+			WriteLagBytes:   1024,
+			ReplayLagBytes:  3 * 1024 * 1024,
+		})
+
+		writeLag, replayLag := fullStatus.getPrintableStandbyLag("cluster-example-2")
+		Expect(writeLag).To(Equal("1.0 KiB"))
+		Expect(replayLag).To(Equal("3.0 MiB"))
+	})
+
+	It("doesn't report any lag for the primary", func() {
+		fullStatus := newStatus(postgres.PgStatReplication{ApplicationName: "cluster-example-2"})
+
+		writeLag, replayLag := fullStatus.getPrintableStandbyLag("cluster-example-1")
+		Expect(writeLag).To(Equal("-"))
+		Expect(replayLag).To(Equal("-"))
+	})
+
+	It("doesn't report any lag when the primary has no receivers", func() {
+		writeLag, replayLag := newStatus().getPrintableStandbyLag("cluster-example-2")
+		Expect(writeLag).To(Equal("-"))
+		Expect(replayLag).To(Equal("-"))
+	})
+
+	It("doesn't report any lag for a standby not streaming yet", func() {
+		fullStatus := newStatus(postgres.PgStatReplication{ApplicationName: "cluster-example-3"})
+
+		writeLag, replayLag := fullStatus.getPrintableStandbyLag("cluster-example-2")
+		Expect(writeLag).To(Equal("-"))
+		Expect(replayLag).To(Equal("-"))
+	})
+})
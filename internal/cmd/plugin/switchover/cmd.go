@@ -0,0 +1,80 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package switchover implements the kubectl-cnpg switchover command
+package switchover
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+// defaultMaxLagBytes is the default maximum replay lag of the target
+// instance, equal to the default size of a WAL segment
+const defaultMaxLagBytes = 16 * 1024 * 1024
+
+// NewCmd creates the new "switchover" command
+func NewCmd() *cobra.Command {
+	var (
+		target      string
+		maxLagBytes int64
+		force       bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "switchover [cluster] --to [node]",
+		Short: "Switch over to the pod named [cluster]-[node] or [node]",
+		Long: `Switches over [cluster] to the pod named [cluster]-[node] or [node], which ` +
+			`must be a ready replica streaming from the current primary. The switchover is ` +
+			`refused if the replay lag of the target exceeds --max-lag-bytes, unless ` +
+			`--force is given.`,
+		GroupID: plugin.GroupIDCluster,
+		Args:    plugin.RequiresArguments(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return plugin.CompleteClusters(cmd.Context(), args, toComplete), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName := args[0]
+			if target == "" {
+				return errors.New("the target instance must be specified with --to")
+			}
+			if maxLagBytes < 0 {
+				return errors.New("--max-lag-bytes must not be negative")
+			}
+			if _, err := strconv.Atoi(target); err == nil {
+				target = fmt.Sprintf("%s-%s", clusterName, target)
+			}
+
+			return newSwitchover(clusterName, target, maxLagBytes, force).run(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVar(&target, "to", "", "The instance to be promoted")
+	cmd.Flags().Int64Var(
+		&maxLagBytes,
+		"max-lag-bytes",
+		defaultMaxLagBytes,
+		"The maximum replay lag, in bytes, of the target instance",
+	)
+	cmd.Flags().BoolVar(&force, "force", false, "Switch over even if the target instance is lagging")
+
+	return cmd
+}
@@ -0,0 +1,158 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package switchover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/promote"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/plugin/resources"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// switchover promotes a replica of the cluster, after having checked
+// it is streaming from the primary and caught up with it
+type switchover struct {
+	clusterName string
+	target      string
+	maxLagBytes int64
+	force       bool
+
+	// getReplicationInfo gets the content of pg_stat_replication
+	// from the current primary of the cluster
+	getReplicationInfo func(ctx context.Context) (postgres.PgStatReplicationList, error)
+
+	// promote requests the promotion of the target instance
+	promote func(ctx context.Context) error
+}
+
+// newSwitchover creates a new switchover of the passed cluster
+// to the target instance
+func newSwitchover(clusterName, target string, maxLagBytes int64, force bool) *switchover {
+	return &switchover{
+		clusterName: clusterName,
+		target:      target,
+		maxLagBytes: maxLagBytes,
+		force:       force,
+		getReplicationInfo: func(ctx context.Context) (postgres.PgStatReplicationList, error) {
+			return getPrimaryReplicationInfo(ctx, clusterName)
+		},
+		promote: func(ctx context.Context) error {
+			return promote.Promote(ctx, clusterName, target)
+		},
+	}
+}
+
+func (s *switchover) run(ctx context.Context) error {
+	var cluster apiv1.Cluster
+	if err := plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: s.clusterName},
+		&cluster,
+	); err != nil {
+		return fmt.Errorf("cluster %s not found in namespace %s: %w", s.clusterName, plugin.Namespace, err)
+	}
+
+	if cluster.Status.CurrentPrimary != cluster.Status.TargetPrimary {
+		return fmt.Errorf("a switchover or a failover is already in progress in cluster %s (from %s to %s)",
+			s.clusterName, cluster.Status.CurrentPrimary, cluster.Status.TargetPrimary)
+	}
+	if cluster.Status.CurrentPrimary == s.target {
+		return fmt.Errorf("%s is already the primary instance of cluster %s", s.target, s.clusterName)
+	}
+
+	var pod corev1.Pod
+	if err := plugin.Client.Get(ctx, client.ObjectKey{Namespace: plugin.Namespace, Name: s.target}, &pod); err != nil {
+		return fmt.Errorf("target instance %s not found in namespace %s: %w", s.target, plugin.Namespace, err)
+	}
+	if pod.Labels[utils.ClusterLabelName] != s.clusterName {
+		return fmt.Errorf("%s is not an instance of cluster %s", s.target, s.clusterName)
+	}
+	if !utils.IsPodReady(pod) {
+		return fmt.Errorf("target instance %s is not ready", s.target)
+	}
+
+	replicationInfo, err := s.getReplicationInfo(ctx)
+	if err != nil {
+		return fmt.Errorf("while getting the replication status from the primary of cluster %s: %w",
+			s.clusterName, err)
+	}
+	if err := s.checkTargetReplication(replicationInfo); err != nil {
+		return err
+	}
+
+	return s.promote(ctx)
+}
+
+// checkTargetReplication checks that the target is streaming from the
+// primary and, unless forced, that it is caught up with it
+func (s *switchover) checkTargetReplication(replicationInfo postgres.PgStatReplicationList) error {
+	for _, info := range replicationInfo {
+		if info.ApplicationName != s.target {
+			continue
+		}
+
+		if info.State != "streaming" {
+			return fmt.Errorf("target instance %s is not streaming from the primary (state: %q)",
+				s.target, info.State)
+		}
+
+		if info.ReplayLagBytes > s.maxLagBytes {
+			if !s.force {
+				return fmt.Errorf("target instance %s is lagging %d bytes behind the primary, "+
+					"more than %d bytes. Retry later, or use --force to switch over anyway",
+					s.target, info.ReplayLagBytes, s.maxLagBytes)
+			}
+			fmt.Printf("Target instance %s is lagging %d bytes behind the primary, switching over anyway\n",
+				s.target, info.ReplayLagBytes)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("target instance %s is not streaming from the primary", s.target)
+}
+
+// getPrimaryReplicationInfo gets the content of pg_stat_replication
+// from the current primary of the cluster
+func getPrimaryReplicationInfo(ctx context.Context, clusterName string) (postgres.PgStatReplicationList, error) {
+	_, primaryPod, err := resources.GetInstancePods(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if primaryPod.Name == "" {
+		return nil, errors.New("the primary instance is not available")
+	}
+
+	status, errs := resources.ExtractInstancesStatus(ctx, plugin.Config, []corev1.Pod{primaryPod})
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	if len(status.Items) == 0 {
+		return nil, errors.New("the primary instance didn't report its status")
+	}
+
+	return status.Items[0].ReplicationInfo, nil
+}
@@ -0,0 +1,154 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package switchover
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("switchover", func() {
+	const (
+		namespace   = "default"
+		clusterName = "cluster-example"
+		target      = clusterName + "-2"
+	)
+
+	var (
+		promoted        bool
+		replicationInfo postgres.PgStatReplicationList
+	)
+
+	newPod := func(name string, ready bool) *corev1.Pod {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      name,
+				Labels:    map[string]string{utils.ClusterLabelName: clusterName},
+			},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{Type: corev1.ContainersReady, Status: status}},
+			},
+		}
+	}
+
+	setupClient := func(targetPrimary string, targetReady bool) {
+		plugin.Namespace = namespace
+		plugin.Client = fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(
+				&apiv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: clusterName},
+					Spec:       apiv1.ClusterSpec{Instances: 3},
+					Status: apiv1.ClusterStatus{
+						CurrentPrimary: clusterName + "-1",
+						TargetPrimary:  targetPrimary,
+					},
+				},
+				newPod(clusterName+"-1", true),
+				newPod(target, targetReady),
+			).
+			Build()
+	}
+
+	newTestSwitchover := func(to string, force bool) *switchover {
+		s := newSwitchover(clusterName, to, 1024, force)
+		s.getReplicationInfo = func(context.Context) (postgres.PgStatReplicationList, error) {
+			return replicationInfo, nil
+		}
+		s.promote = func(context.Context) error {
+			promoted = true
+			return nil
+		}
+		return s
+	}
+
+	BeforeEach(func() {
+		promoted = false
+		replicationInfo = postgres.PgStatReplicationList{
+			{ApplicationName: target, State: "streaming", ReplayLagBytes: 512},
+		}
+	})
+
+	It("promotes a streaming replica that is caught up", func(ctx SpecContext) {
+		setupClient(clusterName+"-1", true)
+		Expect(newTestSwitchover(target, false).run(ctx)).To(Succeed())
+		Expect(promoted).To(BeTrue())
+	})
+
+	It("refuses to promote the current primary", func(ctx SpecContext) {
+		setupClient(clusterName+"-1", true)
+		Expect(newTestSwitchover(clusterName+"-1", false).run(ctx)).To(
+			MatchError(ContainSubstring("already the primary")))
+		Expect(promoted).To(BeFalse())
+	})
+
+	It("refuses to switch over while another switchover is in progress", func(ctx SpecContext) {
+		setupClient(clusterName+"-3", true)
+		Expect(newTestSwitchover(target, false).run(ctx)).To(
+			MatchError(ContainSubstring("already in progress")))
+		Expect(promoted).To(BeFalse())
+	})
+
+	It("refuses to promote an instance that is not ready", func(ctx SpecContext) {
+		setupClient(clusterName+"-1", false)
+		Expect(newTestSwitchover(target, false).run(ctx)).To(MatchError(ContainSubstring("not ready")))
+		Expect(promoted).To(BeFalse())
+	})
+
+	It("refuses to promote an instance that doesn't exist", func(ctx SpecContext) {
+		setupClient(clusterName+"-1", true)
+		Expect(newTestSwitchover(clusterName+"-9", false).run(ctx)).To(MatchError(ContainSubstring("not found")))
+		Expect(promoted).To(BeFalse())
+	})
+
+	It("refuses to promote an instance that is not streaming, even when forced", func(ctx SpecContext) {
+		setupClient(clusterName+"-1", true)
+		replicationInfo[0].State = "catchup"
+		Expect(newTestSwitchover(target, true).run(ctx)).To(MatchError(ContainSubstring("not streaming")))
+
+		replicationInfo = nil
+		Expect(newTestSwitchover(target, true).run(ctx)).To(MatchError(ContainSubstring("not streaming")))
+		Expect(promoted).To(BeFalse())
+	})
+
+	It("refuses to promote a lagging instance unless forced", func(ctx SpecContext) {
+		setupClient(clusterName+"-1", true)
+		replicationInfo[0].ReplayLagBytes = 4096
+		Expect(newTestSwitchover(target, false).run(ctx)).To(MatchError(ContainSubstring("--force")))
+		Expect(promoted).To(BeFalse())
+
+		Expect(newTestSwitchover(target, true).run(ctx)).To(Succeed())
+		Expect(promoted).To(BeTrue())
+	})
+})
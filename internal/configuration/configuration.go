@@ -24,8 +24,10 @@ import (
 	"time"
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
+	"k8s.io/apimachinery/pkg/util/wait"
 
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/configparser"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
 )
 
@@ -40,6 +42,14 @@ const (
 
 	// ExpiringCheckThreshold is the default threshold to consider a certificate as expiring
 	ExpiringCheckThreshold = 7
+
+	// DefaultClockSkewThreshold is the default maximum clock skew (in
+	// milliseconds) tolerated between an instance and the operator
+	DefaultClockSkewThreshold = 1000
+
+	// DefaultExecTimeout is the default timeout (in seconds) of the
+	// commands executed by the operator inside the instance pods
+	DefaultExecTimeout = 30
 )
 
 // DefaultPluginSocketDir is the default directory where the plugin sockets are located.
@@ -125,6 +135,26 @@ type Data struct {
 	// IncludePlugins is a comma-separated list of plugins to always be
 	// included in the Cluster reconciliation
 	IncludePlugins string `json:"includePlugins" env:"INCLUDE_PLUGINS"`
+
+	// The maximum difference (in milliseconds) tolerated between the clock
+	// of an instance and the one of the operator before reporting a clock
+	// skew. The default value is 1000, while 0 disables the detection.
+	ClockSkewThreshold int `json:"clockSkewThreshold" env:"CLOCK_SKEW_THRESHOLD"`
+
+	// The minimum PostgreSQL major version allowed when creating a new
+	// cluster. The default value is 0, meaning no restriction.
+	MinPostgresMajor int `json:"minPostgresMajor" env:"MIN_POSTGRES_MAJOR"`
+
+	// The timeout (in seconds) of every command executed by the operator
+	// inside the instance pods. The default value is 30, while 0 disables
+	// the timeout.
+	ExecTimeout int `json:"execTimeout" env:"EXEC_TIMEOUT"`
+
+	// The number of times a command executed by the operator inside the
+	// instance pods is retried, with an exponential backoff, after a
+	// transient failure. Commands having side effects are only retried
+	// when they surely haven't been started. The default value is 3.
+	ExecRetries int `json:"execRetries" env:"EXEC_RETRIES"`
 }
 
 // Current is the configuration used by the operator
@@ -140,6 +170,9 @@ func newDefaultConfig() *Data {
 		CreateAnyService:       false,
 		CertificateDuration:    CertificateDuration,
 		ExpiringCheckThreshold: ExpiringCheckThreshold,
+		ClockSkewThreshold:     DefaultClockSkewThreshold,
+		ExecTimeout:            DefaultExecTimeout,
+		ExecRetries:            utils.DefaultExecRetries,
 	}
 }
 
@@ -179,6 +212,29 @@ func (config *Data) GetInstancesRolloutDelay() time.Duration {
 	return time.Duration(config.InstancesRolloutDelay) * time.Second
 }
 
+// GetClockSkewThreshold gets the maximum tolerated clock skew between
+// an instance and the operator, where zero disables the detection
+func (config *Data) GetClockSkewThreshold() time.Duration {
+	return time.Duration(config.ClockSkewThreshold) * time.Millisecond
+}
+
+// GetExecTimeout gets the timeout of the commands executed inside the
+// instance pods, where nil means no timeout
+func (config *Data) GetExecTimeout() *time.Duration {
+	if config.ExecTimeout <= 0 {
+		return nil
+	}
+
+	timeout := time.Duration(config.ExecTimeout) * time.Second
+	return &timeout
+}
+
+// GetExecBackoff gets the backoff used to retry the commands executed
+// inside the instance pods
+func (config *Data) GetExecBackoff() wait.Backoff {
+	return utils.NewExecBackoff(config.ExecRetries)
+}
+
 // WatchedNamespaces get the list of additional watched namespaces.
 // The result is a list of namespaces specified in the WATCHED_NAMESPACE where
 // each namespace is separated by comma
@@ -167,4 +167,20 @@ var _ = Describe("Annotation and label inheritance", func() {
 		config := Data{}
 		Expect(config.GetInstancesRolloutDelay()).To(BeZero())
 	})
+
+	It("returns the timeout of the commands executed inside the pods", func() {
+		config := Data{ExecTimeout: 45}
+		Expect(config.GetExecTimeout()).To(HaveValue(Equal(45 * time.Second)))
+	})
+
+	It("disables the timeout of the commands executed inside the pods when set to zero", func() {
+		config := Data{}
+		Expect(config.GetExecTimeout()).To(BeNil())
+	})
+
+	It("uses the configured number of retries for the commands executed inside the pods", func() {
+		Expect(newDefaultConfig().GetExecBackoff().Steps).To(Equal(4))
+		config := Data{ExecRetries: 0}
+		Expect(config.GetExecBackoff().Steps).To(Equal(1))
+	})
 })
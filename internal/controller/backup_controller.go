@@ -44,6 +44,7 @@ import (
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	cnpgiClient "github.com/cloudnative-pg/cloudnative-pg/internal/cnpi/plugin/client"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cnpi/plugin/repository"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
@@ -62,6 +63,10 @@ const backupPhase = ".status.phase"
 // where the name of the cluster is written
 const clusterName = ".spec.cluster.name"
 
+// errNoStandbyAvailable is raised when a backup must be taken on a standby
+// instance, but none of them is ready
+var errNoStandbyAvailable = errors.New("no ready standby instance available for the backup")
+
 // BackupReconciler reconciles a Backup object
 type BackupReconciler struct {
 	client.Client
@@ -212,6 +217,9 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	case apiv1.BackupMethodBarmanObjectStore, apiv1.BackupMethodPlugin:
 		// If no good running backups are found we elect a pod for the backup
 		pod, err := r.getBackupTargetPod(ctx, &cluster, &backup)
+		if errors.Is(err, errNoStandbyAvailable) {
+			return r.deferBackupWithoutStandby(ctx, &backup)
+		}
 		if apierrs.IsNotFound(err) {
 			r.Recorder.Eventf(&backup, "Warning", "FindingPod",
 				"Couldn't find target pod %s, will retry in 30 seconds", cluster.Status.TargetPrimary)
@@ -314,6 +322,8 @@ func (r *BackupReconciler) isValidBackupRunning(
 	case apiv1.BackupTargetStandby, "":
 		// we don't really care for this type
 		isCorrectPodElected = true
+	case apiv1.BackupTargetStandbyOnly:
+		isCorrectPodElected = backup.Status.InstanceID.PodName != cluster.Status.TargetPrimary
 	default:
 		return false, fmt.Errorf("unknown.spec.target received: %s", backup.Spec.Target)
 	}
@@ -352,6 +362,10 @@ func (r *BackupReconciler) reconcileSnapshotBackup(
 	contextLogger := log.FromContext(ctx)
 
 	targetPod, err := r.getSnapshotTargetPod(ctx, cluster, backup)
+	if errors.Is(err, errNoStandbyAvailable) {
+		res, err := r.deferBackupWithoutStandby(ctx, backup)
+		return &res, err
+	}
 	if apierrs.IsNotFound(err) {
 		r.Recorder.Eventf(
 			backup,
@@ -571,7 +585,7 @@ func (r *BackupReconciler) getBackupTargetPod(ctx context.Context,
 					"instance", item.Pod.Name)
 				return item.Pod, nil
 			}
-		case apiv1.BackupTargetStandby, "":
+		case apiv1.BackupTargetStandby, apiv1.BackupTargetStandbyOnly, "":
 			if !item.IsPrimary {
 				contextLogger.Debug("Standby Instance is elected as backup target",
 					"instance", item.Pod.Name)
@@ -580,6 +594,10 @@ func (r *BackupReconciler) getBackupTargetPod(ctx context.Context,
 		}
 	}
 
+	if backupTarget == apiv1.BackupTargetStandbyOnly {
+		return nil, errNoStandbyAvailable
+	}
+
 	contextLogger.Debug("No ready instances found as target for backup, defaulting to primary")
 
 	var pod corev1.Pod
@@ -591,6 +609,23 @@ func (r *BackupReconciler) getBackupTargetPod(ctx context.Context,
 	return &pod, err
 }
 
+// deferBackupWithoutStandby marks as pending a backup that must be taken on
+// a standby instance while none of them is ready, to retry it later
+func (r *BackupReconciler) deferBackupWithoutStandby(
+	ctx context.Context,
+	backup *apiv1.Backup,
+) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	contextLogger.Info("No ready standby instance for the backup, will retry in 30 seconds")
+	r.Recorder.Event(backup, "Warning", "NoStandbyAvailable",
+		"No ready standby instance for the backup, will retry in 30 seconds")
+
+	origBackup := backup.DeepCopy()
+	backup.Status.Phase = apiv1.BackupPhasePending
+	return ctrl.Result{RequeueAfter: 30 * time.Second}, r.Status().Patch(ctx, backup, client.MergeFrom(origBackup))
+}
+
 // startInstanceManagerBackup request a backup in a Pod and marks the backup started
 // or failed if needed
 func startInstanceManagerBackup(
@@ -612,14 +647,16 @@ func startInstanceManagerBackup(
 
 	var err error
 	var stdout, stderr string
-	err = retry.OnError(retry.DefaultBackoff, func(error) bool { return true }, func() error {
+	// Requesting a backup is not idempotent, so we only retry when the
+	// API server refused to run the command
+	err = retry.OnError(configuration.Current.GetExecBackoff(), utils.IsExecErrorBeforeStart, func() error {
 		stdout, stderr, err = utils.ExecCommand(
 			ctx,
 			clientInterface,
 			config,
 			*pod,
 			specs.PostgresContainerName,
-			nil,
+			configuration.Current.GetExecTimeout(),
 			"/controller/manager",
 			"backup",
 			backup.GetName(),
@@ -28,6 +28,8 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources/instance"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -150,6 +152,13 @@ var _ = Describe("backup_controller barmanObjectStore unit tests", func() {
 			Expect(res).To(BeFalse())
 		})
 
+		It("returning false when a standby backup is running on the primary", func(ctx context.Context) {
+			backup.Spec.Target = apiv1.BackupTargetStandbyOnly
+			res, err := env.backupReconciler.isValidBackupRunning(ctx, backup, cluster)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(BeFalse())
+		})
+
 		It("returning an error when the backup target is wrong", func(ctx context.Context) {
 			backup.Spec.Target = "fakeTarget"
 			res, err := env.backupReconciler.isValidBackupRunning(ctx, backup, cluster)
@@ -159,6 +168,112 @@ var _ = Describe("backup_controller barmanObjectStore unit tests", func() {
 	})
 })
 
+// fakeInstanceStatusClient is an instance client returning a fixed
+// status for the instances
+type fakeInstanceStatusClient struct {
+	instance.Client
+	statusList postgres.PostgresqlStatusList
+}
+
+func (f fakeInstanceStatusClient) GetStatusFromInstances(
+	_ context.Context,
+	_ corev1.PodList,
+) postgres.PostgresqlStatusList {
+	return f.statusList
+}
+
+var _ = Describe("backup target election", func() {
+	var (
+		env              *testingEnvironment
+		backupReconciler *BackupReconciler
+		cluster          *apiv1.Cluster
+		backup           *apiv1.Backup
+	)
+
+	instanceStatus := func(name string, isPrimary bool) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: cluster.Namespace,
+			}},
+			IsPrimary:  isPrimary,
+			IsPodReady: true,
+		}
+	}
+
+	BeforeEach(func(ctx context.Context) {
+		env = buildTestEnvironment()
+		cluster = newFakeCNPGCluster(env.client, newFakeNamespace(env.client), func(cluster *apiv1.Cluster) {
+			cluster.Status.TargetPrimary = cluster.Name + "-1"
+		})
+		generateFakeClusterPods(env.client, cluster, true)
+		backup = &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cluster.Name,
+				Namespace: cluster.Namespace,
+			},
+			Spec: apiv1.BackupSpec{
+				Cluster: apiv1.LocalObjectReference{Name: cluster.Name},
+				Target:  apiv1.BackupTargetStandbyOnly,
+			},
+		}
+		Expect(env.client.Create(ctx, backup)).To(Succeed())
+		backupReconciler = &BackupReconciler{
+			Client:   fakeClientWithIndexAdapter{Client: env.client},
+			Scheme:   env.scheme,
+			Recorder: env.backupReconciler.Recorder,
+		}
+	})
+
+	It("elects the most updated standby for a standby backup", func(ctx context.Context) {
+		backupReconciler.instanceStatusClient = fakeInstanceStatusClient{
+			statusList: postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+				instanceStatus(cluster.Name+"-1", true),
+				instanceStatus(cluster.Name+"-3", false),
+				instanceStatus(cluster.Name+"-2", false),
+			}},
+		}
+
+		pod, err := backupReconciler.getBackupTargetPod(ctx, cluster, backup)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Name).To(Equal(cluster.Name + "-3"))
+	})
+
+	It("doesn't fall back to the primary for a standby backup", func(ctx context.Context) {
+		backupReconciler.instanceStatusClient = fakeInstanceStatusClient{
+			statusList: postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+				instanceStatus(cluster.Name+"-1", true),
+			}},
+		}
+
+		_, err := backupReconciler.getBackupTargetPod(ctx, cluster, backup)
+		Expect(err).To(MatchError(errNoStandbyAvailable))
+	})
+
+	It("falls back to the primary when a standby is only preferred", func(ctx context.Context) {
+		backup.Spec.Target = apiv1.BackupTargetStandby
+		backupReconciler.instanceStatusClient = fakeInstanceStatusClient{
+			statusList: postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+				instanceStatus(cluster.Name+"-1", true),
+			}},
+		}
+
+		pod, err := backupReconciler.getBackupTargetPod(ctx, cluster, backup)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pod.Name).To(Equal(cluster.Name + "-1"))
+	})
+
+	It("defers a standby backup until a standby is available", func(ctx context.Context) {
+		res, err := backupReconciler.deferBackupWithoutStandby(ctx, backup)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.RequeueAfter).To(Equal(30 * time.Second))
+
+		var updatedBackup apiv1.Backup
+		Expect(env.client.Get(ctx, client.ObjectKeyFromObject(backup), &updatedBackup)).To(Succeed())
+		Expect(updatedBackup.Status.Phase).To(BeEquivalentTo(apiv1.BackupPhasePending))
+	})
+})
+
 var _ = Describe("backup_controller volumeSnapshot unit tests", func() {
 	When("there's a running backup", func() {
 		It("prevents concurrent backups", func() {
@@ -0,0 +1,120 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// errSwitchoverBlockedByBackup is raised when a switchover has been
+// deferred because a backup is being taken
+var errSwitchoverBlockedByBackup = errors.New("switchover deferred until the running backups complete")
+
+// getRunningBackups gets the backups of the cluster that are being taken
+func (r *ClusterReconciler) getRunningBackups(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) (apiv1.BackupList, error) {
+	var backupList apiv1.BackupList
+	if err := r.List(ctx, &backupList,
+		client.MatchingFields{clusterName: cluster.Name},
+		client.InNamespace(cluster.Namespace),
+	); err != nil {
+		return apiv1.BackupList{}, fmt.Errorf("while getting the backups of the cluster: %w", err)
+	}
+
+	runningBackups := backupList.Items[:0]
+	for _, backup := range backupList.Items {
+		if backup.Status.IsRunning() {
+			runningBackups = append(runningBackups, backup)
+		}
+	}
+	backupList.Items = runningBackups
+	return backupList, nil
+}
+
+// isSwitchoverBlockedByBackup checks whether a voluntary switchover
+// to the passed instance needs to be deferred because a backup is
+// being taken, raising an event explaining the decision
+func (r *ClusterReconciler) isSwitchoverBlockedByBackup(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	targetPrimary string,
+) (bool, error) {
+	if !cluster.IsSwitchoverBlockedByBackups() {
+		return false, nil
+	}
+
+	runningBackups, err := r.getRunningBackups(ctx, cluster)
+	if err != nil {
+		return false, err
+	}
+
+	backupNames := runningBackups.GetRunningBackupNames()
+	if len(backupNames) == 0 {
+		return false, nil
+	}
+
+	log.FromContext(ctx).Info("Deferring the switchover until the running backups complete",
+		"targetPrimary", targetPrimary, "backups", backupNames)
+	r.Recorder.Eventf(cluster, "Normal", "SwitchoverDeferred",
+		"Deferring the switchover to %s until the running backups complete: %s",
+		targetPrimary, strings.Join(backupNames, ", "))
+	return true, nil
+}
+
+// abortRunningBackups marks the running backups as failed, as the failover
+// of the primary invalidates them
+func (r *ClusterReconciler) abortRunningBackups(ctx context.Context, cluster *apiv1.Cluster) error {
+	if !cluster.IsSwitchoverBlockedByBackups() {
+		return nil
+	}
+
+	runningBackups, err := r.getRunningBackups(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	for idx := range runningBackups.Items {
+		backup := &runningBackups.Items[idx]
+		log.FromContext(ctx).Warning("Aborting the running backup because of a failover",
+			"backup", backup.Name)
+
+		backup.Status.SetAsFailed(fmt.Errorf("backup aborted because of a failover from %s",
+			cluster.Status.CurrentPrimary))
+		if err := postgres.PatchBackupStatusAndRetry(ctx, r.Client, backup); err != nil {
+			return fmt.Errorf("while aborting backup %s: %w", backup.Name, err)
+		}
+
+		r.Recorder.Eventf(cluster, "Warning", "BackupAborted",
+			"Backup %s has been aborted because of a failover from %s",
+			backup.Name, cluster.Status.CurrentPrimary)
+		r.Recorder.Eventf(backup, "Warning", "BackupAborted",
+			"Backup aborted because of a failover from %s", cluster.Status.CurrentPrimary)
+	}
+
+	return nil
+}
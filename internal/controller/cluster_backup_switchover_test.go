@@ -0,0 +1,140 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("switchover blocked by running backups", func() {
+	var (
+		r        *ClusterReconciler
+		cluster  *apiv1.Cluster
+		backup   *apiv1.Backup
+		recorder *record.FakeRecorder
+	)
+
+	podList := func() *postgres.PostgresqlStatusList {
+		return &postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{
+					Pod:       &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: cluster.Name + "-1"}},
+					IsPrimary: true,
+				},
+				{
+					Pod:                 &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: cluster.Name + "-2"}},
+					IsWalReceiverActive: true,
+				},
+			},
+		}
+	}
+
+	getUpdatedCluster := func(ctx SpecContext) *apiv1.Cluster {
+		var updatedCluster apiv1.Cluster
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		return &updatedCluster
+	}
+
+	getUpdatedBackup := func(ctx SpecContext) *apiv1.Backup {
+		var updatedBackup apiv1.Backup
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(backup), &updatedBackup)).To(Succeed())
+		return &updatedBackup
+	}
+
+	BeforeEach(func(ctx SpecContext) {
+		k8sClient := fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithStatusSubresource(&apiv1.Cluster{}, &apiv1.Backup{}).
+			WithIndex(&apiv1.Backup{}, clusterName, func(rawObj client.Object) []string {
+				return []string{rawObj.(*apiv1.Backup).Spec.Cluster.Name}
+			}).
+			Build()
+		recorder = record.NewFakeRecorder(120)
+		r = &ClusterReconciler{Client: k8sClient, Recorder: recorder}
+
+		cluster = newFakeCNPGCluster(k8sClient, newFakeNamespace(k8sClient), func(cluster *apiv1.Cluster) {
+			cluster.Spec.PrimaryUpdateMethod = apiv1.PrimaryUpdateMethodSwitchover
+			cluster.Spec.Backup = &apiv1.BackupConfiguration{BlockSwitchover: true}
+		})
+		cluster.Status.Instances = 2
+		cluster.Status.CurrentPrimary = cluster.Name + "-1"
+		cluster.Status.TargetPrimary = cluster.Name + "-1"
+		Expect(k8sClient.Status().Update(ctx, cluster)).To(Succeed())
+
+		backup = &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: cluster.Namespace},
+			Spec:       apiv1.BackupSpec{Cluster: apiv1.LocalObjectReference{Name: cluster.Name}},
+		}
+		Expect(k8sClient.Create(ctx, backup)).To(Succeed())
+		backup.Status.Phase = apiv1.BackupPhaseRunning
+		Expect(k8sClient.Status().Update(ctx, backup)).To(Succeed())
+	})
+
+	It("defers the switchover requested by a rolling update until the backup completes", func(ctx SpecContext) {
+		primaryPod := *podList().Items[0].Pod
+
+		By("deferring the switchover while the backup is running", func() {
+			done, err := r.updatePrimaryPod(ctx, cluster, podList(), primaryPod, false, false, "test")
+			Expect(err).To(MatchError(errSwitchoverBlockedByBackup))
+			Expect(done).To(BeFalse())
+			Expect(getUpdatedCluster(ctx).Status.TargetPrimary).To(Equal(cluster.Name + "-1"))
+			Expect(recorder.Events).To(Receive(And(
+				ContainSubstring("SwitchoverDeferred"),
+				ContainSubstring("backup"),
+			)))
+		})
+
+		By("switching over once the backup completed", func() {
+			backup = getUpdatedBackup(ctx)
+			backup.Status.SetAsCompleted()
+			Expect(r.Status().Update(ctx, backup)).To(Succeed())
+
+			done, err := r.updatePrimaryPod(ctx, cluster, podList(), primaryPod, false, false, "test")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(done).To(BeTrue())
+			Expect(getUpdatedCluster(ctx).Status.TargetPrimary).To(Equal(cluster.Name + "-2"))
+		})
+	})
+
+	It("doesn't defer the switchover when not configured to", func(ctx SpecContext) {
+		cluster.Spec.Backup.BlockSwitchover = false
+
+		blocked, err := r.isSwitchoverBlockedByBackup(ctx, cluster, cluster.Name+"-2")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(blocked).To(BeFalse())
+	})
+
+	It("aborts the running backups on failover", func(ctx SpecContext) {
+		Expect(r.abortRunningBackups(ctx, cluster)).To(Succeed())
+
+		updatedBackup := getUpdatedBackup(ctx)
+		Expect(updatedBackup.Status.Phase).To(BeEquivalentTo(apiv1.BackupPhaseFailed))
+		Expect(updatedBackup.Status.Error).To(ContainSubstring("aborted because of a failover"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("BackupAborted")))
+	})
+})
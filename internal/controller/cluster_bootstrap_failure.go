@@ -0,0 +1,219 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	batchv1 "k8s.io/api/batch/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// reconcileBootstrapFailure detects the failure of the job bootstrapping
+// the first instance of the cluster and reports it in the BootstrapFailed
+// condition. When `.spec.bootstrap.cleanupOnFailure` is set, the job and
+// the PVCs of the failed bootstrap are removed, and the bootstrap is
+// retried as soon as the spec of the cluster changes
+func (r *ClusterReconciler) reconcileBootstrapFailure(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+) (*ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	if isClusterBootstrapped(cluster, resources) {
+		return nil, nil
+	}
+
+	if failedJob := getFailedBootstrapJob(cluster, resources.jobs); failedJob != nil {
+		message, err := r.reportBootstrapFailure(ctx, cluster, failedJob)
+		if err != nil {
+			return nil, err
+		}
+
+		if cluster.Spec.Bootstrap == nil || !cluster.Spec.Bootstrap.CleanupOnFailure {
+			if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseUnrecoverable,
+				message+". Remove the job and the PVCs of the instance to retry"); err != nil {
+				return nil, err
+			}
+			return &ctrl.Result{}, ErrNextLoop
+		}
+
+		contextLogger.Info("Removing the resources of the failed bootstrap",
+			"job", failedJob.Name, "instance", cluster.Status.TargetPrimary)
+		r.Recorder.Eventf(cluster, "Normal", "BootstrapCleanup",
+			"Removing the job and the PVCs of the failed bootstrap of instance %s",
+			cluster.Status.TargetPrimary)
+		if err := r.cleanupFailedBootstrap(ctx, cluster, failedJob); err != nil {
+			return nil, err
+		}
+		return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+	}
+
+	failedCondition := meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionBootstrapFailed))
+	if failedCondition == nil || failedCondition.Status != metav1.ConditionTrue {
+		return nil, nil
+	}
+
+	// The failed job is gone: wait for the removal of the resources of the
+	// failed bootstrap and for a corrected spec before retrying
+	if len(resources.jobs.Items) > 0 || len(resources.pvcs.Items) > 0 {
+		contextLogger.Debug("Waiting for the removal of the resources of the failed bootstrap")
+		return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+	}
+
+	if failedCondition.ObservedGeneration == cluster.Generation {
+		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseUnrecoverable,
+			failedCondition.Message+". Update the cluster spec to retry"); err != nil {
+			return nil, err
+		}
+		return &ctrl.Result{}, ErrNextLoop
+	}
+
+	contextLogger.Info("Retrying the bootstrap of the cluster with the updated spec",
+		"generation", cluster.Generation)
+	r.Recorder.Eventf(cluster, "Normal", string(apiv1.ConditionReasonBootstrapRetrying),
+		"Retrying the bootstrap of the cluster with generation %d", cluster.Generation)
+
+	// Start again from the first node serial, as it happens when the
+	// cluster is created
+	origCluster := cluster.DeepCopy()
+	cluster.Status.LatestGeneratedNode = 0
+	cluster.Status.TargetPrimary = ""
+	cluster.Status.TargetPrimaryTimestamp = ""
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:               string(apiv1.ConditionBootstrapFailed),
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: cluster.Generation,
+		Reason:             string(apiv1.ConditionReasonBootstrapRetrying),
+		Message:            fmt.Sprintf("Retrying the bootstrap with generation %d", cluster.Generation),
+	})
+	return nil, r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// isClusterBootstrapped checks if the first instance of the cluster has
+// ever been created, in which case the resources of the cluster must
+// never be removed because of a failed job
+func isClusterBootstrapped(cluster *apiv1.Cluster, resources *managedResources) bool {
+	if cluster.Status.CurrentPrimary != "" || len(resources.instances.Items) > 0 {
+		return true
+	}
+
+	if meta.IsStatusConditionTrue(cluster.Status.Conditions, string(apiv1.ConditionClusterReady)) {
+		return true
+	}
+
+	for _, pvc := range resources.pvcs.Items {
+		if pvc.Annotations[utils.PVCStatusAnnotationName] == persistentvolumeclaim.StatusReady {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getFailedBootstrapJob returns the job bootstrapping the first instance
+// of the cluster, if it failed
+func getFailedBootstrapJob(cluster *apiv1.Cluster, jobs batchv1.JobList) *batchv1.Job {
+	if cluster.Status.TargetPrimary == "" {
+		return nil
+	}
+
+	for idx := range jobs.Items {
+		job := &jobs.Items[idx]
+		if job.Labels[utils.InstanceNameLabelName] != cluster.Status.TargetPrimary ||
+			!job.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		if utils.GetJobFailedCondition(*job) != nil {
+			return job
+		}
+	}
+
+	return nil
+}
+
+// reportBootstrapFailure sets the BootstrapFailed condition, recording the
+// generation of the cluster that failed, and returns its message
+func (r *ClusterReconciler) reportBootstrapFailure(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	failedJob *batchv1.Job,
+) (string, error) {
+	jobCondition := utils.GetJobFailedCondition(*failedJob)
+	reason := jobCondition.Reason
+	if reason == "" {
+		reason = string(apiv1.ConditionReasonBootstrapJobFailed)
+	}
+	message := fmt.Sprintf("The bootstrap job %s failed", failedJob.Name)
+	if jobCondition.Message != "" {
+		message = fmt.Sprintf("%s: %s", message, jobCondition.Message)
+	}
+
+	observedGeneration := cluster.Generation
+	if previous := meta.FindStatusCondition(
+		cluster.Status.Conditions, string(apiv1.ConditionBootstrapFailed),
+	); previous != nil && previous.Status == metav1.ConditionTrue {
+		observedGeneration = previous.ObservedGeneration
+	} else {
+		r.Recorder.Event(cluster, "Warning", "BootstrapFailed", message)
+	}
+
+	return message, conditions.Patch(ctx, r.Client, cluster, &metav1.Condition{
+		Type:               string(apiv1.ConditionBootstrapFailed),
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: observedGeneration,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// cleanupFailedBootstrap removes the failed bootstrap job, together with
+// its pods, and the PVCs of the instance it was creating
+func (r *ClusterReconciler) cleanupFailedBootstrap(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	failedJob *batchv1.Job,
+) error {
+	foreground := metav1.DeletePropagationForeground
+	if err := r.Delete(ctx, failedJob, &client.DeleteOptions{
+		PropagationPolicy: &foreground,
+	}); err != nil && !apierrs.IsNotFound(err) {
+		return fmt.Errorf("while deleting the failed bootstrap job %s: %w", failedJob.Name, err)
+	}
+
+	return persistentvolumeclaim.EnsureInstancePVCGroupIsDeleted(
+		ctx,
+		r.Client,
+		cluster,
+		cluster.Status.TargetPrimary,
+		cluster.Namespace,
+	)
+}
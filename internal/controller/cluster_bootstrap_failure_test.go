@@ -0,0 +1,176 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Bootstrap failure", func() {
+	var (
+		env       *testingEnvironment
+		namespace string
+		ctx       context.Context
+	)
+
+	newBootstrappingCluster := func(cleanupOnFailure bool) *apiv1.Cluster {
+		return newFakeCNPGCluster(env.client, namespace, func(cluster *apiv1.Cluster) {
+			cluster.Spec.Instances = 1
+			cluster.Spec.Bootstrap = &apiv1.BootstrapConfiguration{
+				InitDB:           &apiv1.BootstrapInitDB{Database: "app", Owner: "app"},
+				CleanupOnFailure: cleanupOnFailure,
+			}
+			cluster.Status.Instances = 0
+			cluster.Status.LatestGeneratedNode = 1
+			cluster.Status.TargetPrimary = cluster.Name + "-1"
+		})
+	}
+
+	createFailedJob := func(cluster *apiv1.Cluster) batchv1.Job {
+		job := specs.CreatePrimaryJobViaInitdb(*cluster, 1)
+		cluster.SetInheritedDataAndOwnership(&job.ObjectMeta)
+		job.Status = batchv1.JobStatus{
+			Failed: 7,
+			Conditions: []batchv1.JobCondition{
+				{
+					Type:    batchv1.JobFailed,
+					Status:  corev1.ConditionTrue,
+					Reason:  batchv1.JobReasonBackoffLimitExceeded,
+					Message: "Job has reached the specified backoff limit",
+				},
+			},
+		}
+		Expect(env.client.Create(ctx, job)).To(Succeed())
+		return *job
+	}
+
+	getResources := func(cluster *apiv1.Cluster) *managedResources {
+		var jobs batchv1.JobList
+		Expect(env.client.List(ctx, &jobs, client.InNamespace(namespace))).To(Succeed())
+		var pvcs corev1.PersistentVolumeClaimList
+		Expect(env.client.List(ctx, &pvcs, client.InNamespace(namespace))).To(Succeed())
+		var pods corev1.PodList
+		Expect(env.client.List(ctx, &pods, client.InNamespace(namespace))).To(Succeed())
+		return &managedResources{jobs: jobs, pvcs: pvcs, instances: pods}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		env = buildTestEnvironment()
+		namespace = newFakeNamespace(env.client)
+	})
+
+	It("reports the failure and keeps the resources when the cleanup is not requested", func() {
+		cluster := newBootstrappingCluster(false)
+		job := createFailedJob(cluster)
+		newFakePVC(env.client, cluster, 1, persistentvolumeclaim.StatusInitializing)
+
+		res, err := env.clusterReconciler.reconcileBootstrapFailure(ctx, cluster, getResources(cluster))
+		Expect(err).To(MatchError(ErrNextLoop))
+		Expect(res).ToNot(BeNil())
+
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionBootstrapFailed))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(batchv1.JobReasonBackoffLimitExceeded))
+		Expect(condition.Message).To(ContainSubstring(job.Name))
+		Expect(cluster.Status.Phase).To(Equal(apiv1.PhaseUnrecoverable))
+
+		expectResourceExists(env.client, job.Name, namespace, &batchv1.Job{})
+		expectResourceExists(env.client, cluster.Name+"-1", namespace, &corev1.PersistentVolumeClaim{})
+	})
+
+	It("removes the resources of the failed bootstrap and retries with a corrected spec", func() {
+		cluster := newBootstrappingCluster(true)
+		job := createFailedJob(cluster)
+		newFakePVC(env.client, cluster, 1, persistentvolumeclaim.StatusInitializing)
+
+		By("removing the failed job and the PVCs", func() {
+			res, err := env.clusterReconciler.reconcileBootstrapFailure(ctx, cluster, getResources(cluster))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).ToNot(BeNil())
+			Expect(meta.IsStatusConditionTrue(cluster.Status.Conditions, string(apiv1.ConditionBootstrapFailed))).
+				To(BeTrue())
+			expectResourceDoesntExist(env.client, job.Name, namespace, &batchv1.Job{})
+			expectResourceDoesntExist(env.client, cluster.Name+"-1", namespace, &corev1.PersistentVolumeClaim{})
+		})
+
+		By("waiting for the spec to be corrected", func() {
+			res, err := env.clusterReconciler.reconcileBootstrapFailure(ctx, cluster, getResources(cluster))
+			Expect(err).To(MatchError(ErrNextLoop))
+			Expect(res).ToNot(BeNil())
+			Expect(cluster.Status.LatestGeneratedNode).To(Equal(1))
+			Expect(cluster.Status.PhaseReason).To(ContainSubstring("Update the cluster spec to retry"))
+		})
+
+		By("resetting the status once the spec changes", func() {
+			cluster.Generation++
+			res, err := env.clusterReconciler.reconcileBootstrapFailure(ctx, cluster, getResources(cluster))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(BeNil())
+			Expect(cluster.Status.LatestGeneratedNode).To(BeZero())
+			Expect(cluster.Status.TargetPrimary).To(BeEmpty())
+
+			condition := meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionBootstrapFailed))
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonBootstrapRetrying)))
+		})
+
+		By("bootstrapping the first instance again", func() {
+			nodeSerial, err := env.clusterReconciler.generateNodeSerial(ctx, cluster)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nodeSerial).To(Equal(1))
+			Expect(persistentvolumeclaim.CreateInstancePVCs(ctx, env.client, cluster, nil, nodeSerial)).
+				To(Succeed())
+			Expect(env.client.Create(ctx, specs.CreatePrimaryJobViaInitdb(*cluster, nodeSerial))).To(Succeed())
+			Expect(env.clusterReconciler.setPrimaryInstance(ctx, cluster, cluster.Name+"-1")).To(Succeed())
+
+			res, err := env.clusterReconciler.reconcileBootstrapFailure(ctx, cluster, getResources(cluster))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(BeNil())
+		})
+	})
+
+	It("never removes the resources of a bootstrapped cluster", func() {
+		cluster := newBootstrappingCluster(true)
+		cluster.Status.CurrentPrimary = cluster.Name + "-1"
+		job := createFailedJob(cluster)
+		newFakePVC(env.client, cluster, 1, persistentvolumeclaim.StatusReady)
+
+		res, err := env.clusterReconciler.reconcileBootstrapFailure(ctx, cluster, getResources(cluster))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(BeNil())
+		Expect(meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionBootstrapFailed))).
+			To(BeNil())
+		expectResourceExists(env.client, job.Name, namespace, &batchv1.Job{})
+		expectResourceExists(env.client, cluster.Name+"-1", namespace, &corev1.PersistentVolumeClaim{})
+	})
+})
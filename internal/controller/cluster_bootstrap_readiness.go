@@ -0,0 +1,177 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/cloudnative-pg/machinery/pkg/types"
+	"k8s.io/apimachinery/pkg/api/meta"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// reconcileBootstrapReadiness delays the first transition of a freshly
+// bootstrapped cluster to the healthy phase until the managed roles and
+// databases have been created on the primary and every replica has
+// replayed the WAL containing them
+func (r *ClusterReconciler) reconcileBootstrapReadiness(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) (*ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	if cluster.Status.BootstrapReadiness != nil && cluster.Status.BootstrapReadiness.Completed {
+		return nil, nil
+	}
+
+	// Clusters that have already been ready, like the ones created before
+	// the introduction of this check, and replica clusters, which don't
+	// reconcile the managed objects, are not subject to the ordering
+	if cluster.GetBootstrapReadiness() == apiv1.BootstrapReadinessInstances ||
+		cluster.IsReplica() ||
+		meta.IsStatusConditionTrue(cluster.Status.Conditions, string(apiv1.ConditionClusterReady)) {
+		return nil, r.setBootstrapReadinessCompleted(ctx, cluster)
+	}
+
+	if cluster.Status.BootstrapReadiness == nil || cluster.Status.BootstrapReadiness.ManagedObjectsLSN == "" {
+		pendingObjects, err := r.getPendingManagedObjects(ctx, cluster)
+		if err != nil {
+			return nil, err
+		}
+		if len(pendingObjects) > 0 {
+			contextLogger.Info("Waiting for the managed objects to be created",
+				"pendingObjects", pendingObjects)
+			return &ctrl.Result{RequeueAfter: 1 * time.Second}, r.RegisterPhase(ctx, cluster,
+				apiv1.PhaseWaitingForManagedObjects,
+				fmt.Sprintf("Waiting for the managed objects to be created: %s",
+					strings.Join(pendingObjects, ", ")))
+		}
+
+		primaryLSN := getPrimaryCurrentLSN(instancesStatus)
+		if primaryLSN == "" {
+			return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+		}
+
+		origCluster := cluster.DeepCopy()
+		cluster.Status.BootstrapReadiness = &apiv1.BootstrapReadinessStatus{
+			ManagedObjectsLSN: string(primaryLSN),
+		}
+		if err := r.Client.Status().Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+			return nil, err
+		}
+	}
+
+	managedObjectsLSN := cluster.Status.BootstrapReadiness.ManagedObjectsLSN
+	laggingReplicas := getReplicasBehindLSN(instancesStatus, managedObjectsLSN)
+	if len(laggingReplicas) > 0 {
+		contextLogger.Info("Waiting for the replicas to receive the managed objects",
+			"managedObjectsLSN", managedObjectsLSN,
+			"laggingReplicas", laggingReplicas)
+		return &ctrl.Result{RequeueAfter: 1 * time.Second}, r.RegisterPhase(ctx, cluster,
+			apiv1.PhaseWaitingForManagedObjects,
+			fmt.Sprintf("Waiting for the replicas to replay LSN %s: %s",
+				managedObjectsLSN, strings.Join(laggingReplicas, ", ")))
+	}
+
+	return nil, r.setBootstrapReadinessCompleted(ctx, cluster)
+}
+
+// setBootstrapReadinessCompleted marks the ordering of the initial
+// bootstrap as completed
+func (r *ClusterReconciler) setBootstrapReadinessCompleted(ctx context.Context, cluster *apiv1.Cluster) error {
+	origCluster := cluster.DeepCopy()
+	if cluster.Status.BootstrapReadiness == nil {
+		cluster.Status.BootstrapReadiness = &apiv1.BootstrapReadinessStatus{}
+	}
+	cluster.Status.BootstrapReadiness.Completed = true
+	return r.Client.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// getPendingManagedObjects returns the managed roles and the databases
+// that have not been created yet on the primary
+func (r *ClusterReconciler) getPendingManagedObjects(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) ([]string, error) {
+	var pendingObjects []string
+
+	if cluster.Spec.Managed != nil {
+		reconciledRoles := cluster.Status.ManagedRolesStatus.ByStatus[apiv1.RoleStatusReconciled]
+		for _, role := range cluster.Spec.Managed.Roles {
+			if role.Ensure == apiv1.EnsureAbsent {
+				continue
+			}
+			if !slices.Contains(reconciledRoles, role.Name) {
+				pendingObjects = append(pendingObjects, fmt.Sprintf("role %s", role.Name))
+			}
+		}
+	}
+
+	var databases apiv1.DatabaseList
+	if err := r.List(ctx, &databases, client.InNamespace(cluster.Namespace)); err != nil {
+		return nil, err
+	}
+	for _, database := range databases.Items {
+		if database.Spec.ClusterRef.Name != cluster.Name {
+			continue
+		}
+		if database.Status.Applied == nil || !*database.Status.Applied ||
+			database.Status.ObservedGeneration != database.Generation {
+			pendingObjects = append(pendingObjects, fmt.Sprintf("database %s", database.Name))
+		}
+	}
+
+	return pendingObjects, nil
+}
+
+// getPrimaryCurrentLSN returns the current LSN of the primary instance,
+// or an empty string if it is not known
+func getPrimaryCurrentLSN(instancesStatus postgres.PostgresqlStatusList) types.LSN {
+	for _, instance := range instancesStatus.Items {
+		if instance.IsPrimary {
+			return instance.CurrentLsn
+		}
+	}
+
+	return ""
+}
+
+// getReplicasBehindLSN returns the names of the replicas that have not
+// yet replayed the passed LSN
+func getReplicasBehindLSN(instancesStatus postgres.PostgresqlStatusList, lsn string) []string {
+	var laggingReplicas []string
+	for _, instance := range instancesStatus.Items {
+		if instance.IsPrimary || instance.Pod == nil {
+			continue
+		}
+		if instance.ReplayLsn == "" || instance.ReplayLsn.Less(types.LSN(lsn)) {
+			laggingReplicas = append(laggingReplicas, instance.Pod.Name)
+		}
+	}
+
+	return laggingReplicas
+}
@@ -0,0 +1,169 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/cloudnative-pg/machinery/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Bootstrap readiness ordering", func() {
+	var (
+		env       *testingEnvironment
+		namespace string
+		ctx       context.Context
+	)
+
+	instanceStatus := func(name string, isPrimary bool, lsn types.LSN) postgres.PostgresqlStatus {
+		status := postgres.PostgresqlStatus{
+			IsPrimary: isPrimary,
+			Pod:       &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}},
+		}
+		if isPrimary {
+			status.CurrentLsn = lsn
+		} else {
+			status.ReplayLsn = lsn
+		}
+		return status
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		env = buildTestEnvironment()
+		namespace = newFakeNamespace(env.client)
+	})
+
+	It("waits for the managed roles to be reconciled", func() {
+		cluster := newFakeCNPGCluster(env.client, namespace, func(cluster *apiv1.Cluster) {
+			cluster.Spec.Managed = &apiv1.ManagedConfiguration{
+				Roles: []apiv1.RoleConfiguration{{Name: "app_reader"}},
+			}
+		})
+		instancesStatus := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			instanceStatus(cluster.Name+"-1", true, "0/3000000"),
+			instanceStatus(cluster.Name+"-2", false, "0/3000000"),
+		}}
+
+		res, err := env.clusterReconciler.reconcileBootstrapReadiness(ctx, cluster, instancesStatus)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).ToNot(BeNil())
+		Expect(cluster.Status.Phase).To(Equal(apiv1.PhaseWaitingForManagedObjects))
+		Expect(cluster.Status.PhaseReason).To(ContainSubstring("role app_reader"))
+		Expect(meta.IsStatusConditionTrue(cluster.Status.Conditions, string(apiv1.ConditionClusterReady))).
+			To(BeFalse())
+	})
+
+	It("waits for the databases to be applied", func() {
+		cluster := newFakeCNPGCluster(env.client, namespace)
+		Expect(env.client.Create(ctx, &apiv1.Database{
+			ObjectMeta: metav1.ObjectMeta{Name: "app-db", Namespace: namespace},
+			Spec: apiv1.DatabaseSpec{
+				ClusterRef: corev1.LocalObjectReference{Name: cluster.Name},
+				Name:       "app",
+				Owner:      "app",
+			},
+		})).To(Succeed())
+		instancesStatus := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			instanceStatus(cluster.Name+"-1", true, "0/3000000"),
+		}}
+
+		res, err := env.clusterReconciler.reconcileBootstrapReadiness(ctx, cluster, instancesStatus)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).ToNot(BeNil())
+		Expect(cluster.Status.PhaseReason).To(ContainSubstring("database app-db"))
+	})
+
+	It("waits for the replicas to replay the managed objects", func() {
+		cluster := newFakeCNPGCluster(env.client, namespace, func(cluster *apiv1.Cluster) {
+			cluster.Spec.Managed = &apiv1.ManagedConfiguration{
+				Roles: []apiv1.RoleConfiguration{{Name: "app_reader"}},
+			}
+			cluster.Status.ManagedRolesStatus.ByStatus = map[apiv1.RoleStatus][]string{
+				apiv1.RoleStatusReconciled: {"app_reader"},
+			}
+		})
+		instancesStatus := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			instanceStatus(cluster.Name+"-1", true, "0/3000060"),
+			instanceStatus(cluster.Name+"-2", false, "0/3000060"),
+			instanceStatus(cluster.Name+"-3", false, "0/3000000"),
+		}}
+
+		By("recording the LSN of the primary and waiting for the lagging replica", func() {
+			res, err := env.clusterReconciler.reconcileBootstrapReadiness(ctx, cluster, instancesStatus)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).ToNot(BeNil())
+			Expect(cluster.Status.BootstrapReadiness).ToNot(BeNil())
+			Expect(cluster.Status.BootstrapReadiness.ManagedObjectsLSN).To(Equal("0/3000060"))
+			Expect(cluster.Status.BootstrapReadiness.Completed).To(BeFalse())
+			Expect(cluster.Status.PhaseReason).To(ContainSubstring(cluster.Name + "-3"))
+			Expect(cluster.Status.PhaseReason).ToNot(ContainSubstring(cluster.Name + "-2"))
+		})
+
+		By("completing the ordering once every replica replayed the LSN", func() {
+			instancesStatus.Items[0].CurrentLsn = "0/3000100"
+			instancesStatus.Items[2].ReplayLsn = "0/3000060"
+			res, err := env.clusterReconciler.reconcileBootstrapReadiness(ctx, cluster, instancesStatus)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(BeNil())
+			Expect(cluster.Status.BootstrapReadiness.ManagedObjectsLSN).To(Equal("0/3000060"))
+			Expect(cluster.Status.BootstrapReadiness.Completed).To(BeTrue())
+		})
+	})
+
+	It("doesn't wait when the policy is Instances", func() {
+		cluster := newFakeCNPGCluster(env.client, namespace, func(cluster *apiv1.Cluster) {
+			cluster.Spec.BootstrapReadiness = apiv1.BootstrapReadinessInstances
+			cluster.Spec.Managed = &apiv1.ManagedConfiguration{
+				Roles: []apiv1.RoleConfiguration{{Name: "app_reader"}},
+			}
+		})
+
+		res, err := env.clusterReconciler.reconcileBootstrapReadiness(ctx, cluster, postgres.PostgresqlStatusList{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(BeNil())
+		Expect(cluster.Status.BootstrapReadiness.Completed).To(BeTrue())
+	})
+
+	It("doesn't wait for a cluster that has already been ready", func() {
+		cluster := newFakeCNPGCluster(env.client, namespace, func(cluster *apiv1.Cluster) {
+			cluster.Spec.Managed = &apiv1.ManagedConfiguration{
+				Roles: []apiv1.RoleConfiguration{{Name: "app_reader", Ensure: apiv1.EnsurePresent}},
+			}
+			cluster.Status.Conditions = []metav1.Condition{{
+				Type:   string(apiv1.ConditionClusterReady),
+				Status: metav1.ConditionTrue,
+				Reason: string(apiv1.ClusterReady),
+			}}
+		})
+
+		res, err := env.clusterReconciler.reconcileBootstrapReadiness(ctx, cluster, postgres.PostgresqlStatusList{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(BeNil())
+		Expect(cluster.Status.BootstrapReadiness.Completed).To(BeTrue())
+		Expect(cluster.Status.Phase).ToNot(Equal(apiv1.PhaseWaitingForManagedObjects))
+	})
+})
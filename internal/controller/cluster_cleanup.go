@@ -18,24 +18,38 @@ package controller
 
 import (
 	"context"
+	"sort"
+	"time"
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
 	batchv1 "k8s.io/api/batch/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
-// cleanupCompletedJobs remove all the Jobs which are completed
+// cleanupCompletedJobs remove all the Jobs which are completed, honoring
+// the Job retention policy of the cluster. It returns the time after
+// which the next retained Job is expected to be removed, if any
 func (r *ClusterReconciler) cleanupCompletedJobs(
 	ctx context.Context,
+	cluster *apiv1.Cluster,
 	jobs batchv1.JobList,
-) {
+) time.Duration {
 	contextLogger := log.FromContext(ctx)
 
-	foreground := metav1.DeletePropagationForeground
+	ttl, keepLast := cluster.GetJobRetention()
+
+	// Most recently completed Jobs first
 	completedJobs := utils.FilterJobsWithOneCompletion(jobs.Items)
+	sort.SliceStable(completedJobs, func(i, j int) bool {
+		return getJobCompletionTime(&completedJobs[j]).Before(getJobCompletionTime(&completedJobs[i]))
+	})
+
+	var requeueAfter time.Duration
+	foreground := metav1.DeletePropagationForeground
 	for idx := range completedJobs {
 		job := &completedJobs[idx]
 		if !job.DeletionTimestamp.IsZero() {
@@ -44,6 +58,21 @@ func (r *ClusterReconciler) cleanupCompletedJobs(
 			continue
 		}
 
+		if idx < keepLast {
+			contextLogger.Debug("keeping job because it is one of the most recently completed",
+				"job", job.Name, "keepLast", keepLast)
+			continue
+		}
+
+		if remaining := ttl - time.Since(getJobCompletionTime(job)); remaining > 0 {
+			contextLogger.Debug("keeping job until its retention expires",
+				"job", job.Name, "remaining", remaining)
+			if requeueAfter == 0 || remaining < requeueAfter {
+				requeueAfter = remaining
+			}
+			continue
+		}
+
 		contextLogger.Debug("Removing job", "job", job.Name)
 		if err := r.Delete(ctx, job, &client.DeleteOptions{
 			PropagationPolicy: &foreground,
@@ -52,4 +81,16 @@ func (r *ClusterReconciler) cleanupCompletedJobs(
 			continue
 		}
 	}
+
+	return requeueAfter
+}
+
+// getJobCompletionTime gets the time when the Job has been completed,
+// falling back to its creation time when not available
+func getJobCompletionTime(job *batchv1.Job) time.Time {
+	if job.Status.CompletionTime != nil {
+		return job.Status.CompletionTime.Time
+	}
+
+	return job.CreationTimestamp.Time
 }
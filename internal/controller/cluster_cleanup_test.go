@@ -17,6 +17,8 @@ limitations under the License.
 package controller
 
 import (
+	"time"
+
 	batchv1 "k8s.io/api/batch/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -25,6 +27,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -67,7 +70,7 @@ var _ = Describe("cluster_cleanup", func() {
 		}}
 		cli := fake.NewClientBuilder().WithScheme(scheme).WithLists(jobList).Build()
 		r.Client = cli
-		r.cleanupCompletedJobs(ctx, *jobList)
+		Expect(r.cleanupCompletedJobs(ctx, &apiv1.Cluster{}, *jobList)).To(BeZero())
 
 		err := cli.Get(ctx, client.ObjectKeyFromObject(&jobList.Items[0]), &batchv1.Job{})
 		Expect(apierrors.IsNotFound(err)).To(BeTrue())
@@ -75,4 +78,47 @@ var _ = Describe("cluster_cleanup", func() {
 		err = cli.Get(ctx, client.ObjectKeyFromObject(&jobList.Items[1]), &batchv1.Job{})
 		Expect(err).ToNot(HaveOccurred())
 	})
+
+	It("should delete completed jobs according to the retention policy", func(ctx SpecContext) {
+		completedJob := func(name string, completedAgo time.Duration) batchv1.Job {
+			return batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+				Spec: batchv1.JobSpec{
+					Completions: ptr.To(int32(1)),
+				},
+				Status: batchv1.JobStatus{
+					Succeeded:      1,
+					CompletionTime: ptr.To(metav1.NewTime(time.Now().Add(-completedAgo))),
+				},
+			}
+		}
+		jobList := &batchv1.JobList{Items: []batchv1.Job{
+			completedJob("expired-oldest", 3*time.Hour),
+			completedJob("recent", 10*time.Minute),
+			completedJob("expired", 2*time.Hour),
+			completedJob("latest", 2*time.Minute),
+		}}
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				JobRetention: &apiv1.JobRetentionConfiguration{
+					TTL:      &metav1.Duration{Duration: time.Hour},
+					KeepLast: 1,
+				},
+			},
+		}
+		cli := fake.NewClientBuilder().WithScheme(scheme).WithLists(jobList).Build()
+		r.Client = cli
+
+		requeueAfter := r.cleanupCompletedJobs(ctx, cluster, *jobList)
+		Expect(requeueAfter).To(BeNumerically("~", 50*time.Minute, time.Minute))
+
+		for _, name := range []string{"latest", "recent"} {
+			err := cli.Get(ctx, client.ObjectKey{Namespace: "test", Name: name}, &batchv1.Job{})
+			Expect(err).ToNot(HaveOccurred())
+		}
+		for _, name := range []string{"expired", "expired-oldest"} {
+			err := cli.Get(ctx, client.ObjectKey{Namespace: "test", Name: name}, &batchv1.Job{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		}
+	})
 })
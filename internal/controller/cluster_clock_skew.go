@@ -0,0 +1,154 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+var instanceClockSkew = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "cnpg",
+	Name:      "instance_clock_skew_seconds",
+	Help: "Difference between the clock of the instance and the one of the operator, " +
+		"zero when within the duration of the status request",
+}, []string{"namespace", "cluster", "pod"})
+
+func init() {
+	metrics.Registry.MustRegister(instanceClockSkew)
+}
+
+// reconcileClockSkew updates the ClockSkewDetected condition and the clock
+// skew metric, using the clock skew measured while requesting the status
+// of the instances. Replication and failover rely on timeouts and lag
+// measurements, which become misleading when the clocks aren't synchronized.
+func (r *ClusterReconciler) reconcileClockSkew(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	for _, status := range instancesStatus.Items {
+		if status.Error != nil || status.CurrentTime == nil || status.Pod == nil {
+			continue
+		}
+		instanceClockSkew.WithLabelValues(cluster.Namespace, cluster.Name, status.Pod.Name).
+			Set(status.ClockSkew.Seconds())
+	}
+
+	condition := getClockSkewCondition(instancesStatus, configuration.Current.GetClockSkewThreshold())
+	if condition == nil {
+		return nil
+	}
+
+	if condition.Status == metav1.ConditionTrue &&
+		!meta.IsStatusConditionTrue(cluster.Status.Conditions, condition.Type) {
+		contextLogger.Warning("Clock skew detected",
+			"message", condition.Message,
+			"clockSkew", getClockSkewDetails(instancesStatus))
+		r.Recorder.Event(cluster, "Warning", condition.Reason, condition.Message)
+	}
+
+	return conditions.Patch(ctx, r.Client, cluster, condition)
+}
+
+// getClockSkewCondition computes the ClockSkewDetected condition from the
+// clock skew measured for each instance, returning nil when the detection
+// is disabled or no instance reported its clock
+func getClockSkewCondition(
+	instancesStatus postgres.PostgresqlStatusList,
+	threshold time.Duration,
+) *metav1.Condition {
+	if threshold <= 0 {
+		return nil
+	}
+
+	var skewedInstances []string
+	measured := false
+	for _, status := range instancesStatus.Items {
+		if status.Error != nil || status.CurrentTime == nil || status.Pod == nil {
+			continue
+		}
+
+		measured = true
+		if status.ClockSkew.Abs() > threshold {
+			skewedInstances = append(skewedInstances, status.Pod.Name)
+		}
+	}
+
+	if !measured {
+		return nil
+	}
+
+	if len(skewedInstances) > 0 {
+		return &metav1.Condition{
+			Type:   string(apiv1.ConditionClockSkewDetected),
+			Status: metav1.ConditionTrue,
+			Reason: string(apiv1.ConditionReasonClockSkewAboveThreshold),
+			Message: fmt.Sprintf(
+				"The clock of %s differs from the one of the operator by more than %s. "+
+					"Please check the time synchronization of the nodes",
+				strings.Join(skewedInstances, ", "), threshold),
+		}
+	}
+
+	return &metav1.Condition{
+		Type:    string(apiv1.ConditionClockSkewDetected),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionReasonClocksSynchronized),
+		Message: fmt.Sprintf("The clock of every instance is within %s of the operator", threshold),
+	}
+}
+
+// getClockSkewDetails returns the clock skew measured for
+// each instance, to be logged
+func getClockSkewDetails(instancesStatus postgres.PostgresqlStatusList) map[string]string {
+	result := make(map[string]string, len(instancesStatus.Items))
+	for _, status := range instancesStatus.Items {
+		if status.Error != nil || status.CurrentTime == nil || status.Pod == nil {
+			continue
+		}
+
+		result[status.Pod.Name] = status.ClockSkew.Round(time.Millisecond).String()
+	}
+
+	return result
+}
+
+// deleteClockSkewMetrics removes the clock skew metrics
+// of a cluster that doesn't exist anymore
+func deleteClockSkewMetrics(cluster types.NamespacedName) {
+	instanceClockSkew.DeletePartialMatch(prometheus.Labels{
+		"namespace": cluster.Namespace,
+		"cluster":   cluster.Name,
+	})
+}
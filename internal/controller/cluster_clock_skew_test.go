@@ -0,0 +1,130 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("clock skew detection", func() {
+	var (
+		env     *testingEnvironment
+		cluster *apiv1.Cluster
+	)
+
+	BeforeEach(func() {
+		env = buildTestEnvironment()
+		cluster = newFakeCNPGCluster(env.client, newFakeNamespace(env.client))
+		configuration.Current.ClockSkewThreshold = configuration.DefaultClockSkewThreshold
+	})
+
+	AfterEach(func() {
+		deleteClockSkewMetrics(client.ObjectKeyFromObject(cluster))
+		configuration.Current.ClockSkewThreshold = configuration.DefaultClockSkewThreshold
+	})
+
+	instancesStatus := func(skews ...time.Duration) postgres.PostgresqlStatusList {
+		var result postgres.PostgresqlStatusList
+		now := time.Now()
+		for idx, skew := range skews {
+			result.Items = append(result.Items, postgres.PostgresqlStatus{
+				Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+					Name: fmt.Sprintf("%s-%d", cluster.Name, idx+1),
+				}},
+				CurrentTime: &now,
+				ClockSkew:   skew,
+			})
+		}
+		return result
+	}
+
+	getCondition := func(ctx SpecContext) *metav1.Condition {
+		var updatedCluster apiv1.Cluster
+		Expect(env.client.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		return meta.FindStatusCondition(updatedCluster.Status.Conditions,
+			string(apiv1.ConditionClockSkewDetected))
+	}
+
+	It("raises the condition and a warning when an instance clock is skewed", func(ctx SpecContext) {
+		status := instancesStatus(0, -3*time.Second)
+		Expect(env.clusterReconciler.reconcileClockSkew(ctx, cluster, status)).To(Succeed())
+
+		condition := getCondition(ctx)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonClockSkewAboveThreshold)))
+		Expect(condition.Message).To(ContainSubstring("The clock of " + cluster.Name + "-2 differs"))
+		Expect(condition.Message).ToNot(ContainSubstring(cluster.Name + "-1"))
+
+		recorder := env.clusterReconciler.Recorder.(*record.FakeRecorder)
+		Expect(recorder.Events).To(Receive(ContainSubstring(string(apiv1.ConditionReasonClockSkewAboveThreshold))))
+
+		Expect(testutil.ToFloat64(instanceClockSkew.WithLabelValues(
+			cluster.Namespace, cluster.Name, cluster.Name+"-2"))).To(BeEquivalentTo(-3))
+	})
+
+	It("keeps the condition stable while the skew changes", func(ctx SpecContext) {
+		Expect(env.clusterReconciler.reconcileClockSkew(ctx, cluster, instancesStatus(0, -3*time.Second))).
+			To(Succeed())
+		condition := getCondition(ctx)
+
+		Expect(env.clusterReconciler.reconcileClockSkew(ctx, cluster, instancesStatus(0, -4*time.Second))).
+			To(Succeed())
+		updatedCondition := getCondition(ctx)
+		Expect(updatedCondition.Message).To(Equal(condition.Message))
+		Expect(updatedCondition.LastTransitionTime).To(Equal(condition.LastTransitionTime))
+
+		Expect(testutil.ToFloat64(instanceClockSkew.WithLabelValues(
+			cluster.Namespace, cluster.Name, cluster.Name+"-2"))).To(BeEquivalentTo(-4))
+	})
+
+	It("tolerates a skew below the threshold", func(ctx SpecContext) {
+		Expect(env.clusterReconciler.reconcileClockSkew(ctx, cluster, instancesStatus(0, 20*time.Millisecond))).
+			To(Succeed())
+
+		condition := getCondition(ctx)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonClocksSynchronized)))
+	})
+
+	It("leaves the condition untouched when the detection is disabled", func(ctx SpecContext) {
+		configuration.Current.ClockSkewThreshold = 0
+		Expect(env.clusterReconciler.reconcileClockSkew(ctx, cluster, instancesStatus(time.Hour))).To(Succeed())
+		Expect(getCondition(ctx)).To(BeNil())
+	})
+
+	It("leaves the condition untouched when no instance reported its clock", func(ctx SpecContext) {
+		status := instancesStatus(time.Hour)
+		status.Items[0].CurrentTime = nil
+		Expect(env.clusterReconciler.reconcileClockSkew(ctx, cluster, status)).To(Succeed())
+		Expect(getCondition(ctx)).To(BeNil())
+	})
+})
@@ -0,0 +1,114 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// reconcileConnectionsExhausted updates the ConnectionsExhausted condition.
+// The instance manager connects to PostgreSQL as a superuser, using the
+// slots reserved by `superuser_reserved_connections`, so the cluster is
+// still managed when the client connections are exhausted.
+func (r *ClusterReconciler) reconcileConnectionsExhausted(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	condition := getConnectionsExhaustedCondition(instancesStatus)
+	if condition == nil {
+		return nil
+	}
+
+	if condition.Status == metav1.ConditionTrue &&
+		!meta.IsStatusConditionTrue(cluster.Status.Conditions, condition.Type) {
+		contextLogger.Warning("Client connections exhausted",
+			"message", condition.Message,
+			"clientConnections", getClientConnectionsDetails(instancesStatus))
+		r.Recorder.Event(cluster, "Warning", condition.Reason, condition.Message)
+	}
+
+	return conditions.Patch(ctx, r.Client, cluster, condition)
+}
+
+// getConnectionsExhaustedCondition computes the ConnectionsExhausted
+// condition from the status reported by the instances, returning nil
+// when no instance reported its status
+func getConnectionsExhaustedCondition(instancesStatus postgres.PostgresqlStatusList) *metav1.Condition {
+	var exhausted []string
+	reported := false
+	for _, status := range instancesStatus.Items {
+		if status.Error != nil || status.MaxClientConnections == 0 {
+			continue
+		}
+
+		reported = true
+		if status.AreConnectionsExhausted() {
+			exhausted = append(exhausted, status.Pod.Name)
+		}
+	}
+
+	if !reported {
+		return nil
+	}
+
+	if len(exhausted) > 0 {
+		return &metav1.Condition{
+			Type:   string(apiv1.ConditionConnectionsExhausted),
+			Status: metav1.ConditionTrue,
+			Reason: string(apiv1.ConditionReasonConnectionsExhausted),
+			Message: fmt.Sprintf(
+				"Client connections exhausted on %s. New connections from non-superuser "+
+					"roles are refused until the existing ones are closed or `max_connections` is raised",
+				strings.Join(exhausted, ", ")),
+		}
+	}
+
+	return &metav1.Condition{
+		Type:    string(apiv1.ConditionConnectionsExhausted),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionReasonConnectionsAvailable),
+		Message: "Every instance can accept new client connections",
+	}
+}
+
+// getClientConnectionsDetails returns the client connections in use on
+// each instance, out of the ones available, to be logged
+func getClientConnectionsDetails(instancesStatus postgres.PostgresqlStatusList) map[string]string {
+	result := make(map[string]string, len(instancesStatus.Items))
+	for _, status := range instancesStatus.Items {
+		if status.Error != nil || status.MaxClientConnections == 0 || status.Pod == nil {
+			continue
+		}
+
+		result[status.Pod.Name] = fmt.Sprintf("%d/%d", status.ClientConnections, status.MaxClientConnections)
+	}
+
+	return result
+}
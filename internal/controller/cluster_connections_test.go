@@ -0,0 +1,116 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("exhausted client connections", func() {
+	var (
+		env     *testingEnvironment
+		cluster *apiv1.Cluster
+	)
+
+	BeforeEach(func() {
+		env = buildTestEnvironment()
+		cluster = newFakeCNPGCluster(env.client, newFakeNamespace(env.client))
+	})
+
+	instancesStatus := func(primaryConnections int) postgres.PostgresqlStatusList {
+		return postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{
+					Pod:                  &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: cluster.Name + "-1"}},
+					IsPrimary:            true,
+					ClientConnections:    primaryConnections,
+					MaxClientConnections: 97,
+				},
+				{
+					Pod:                  &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: cluster.Name + "-2"}},
+					ClientConnections:    3,
+					MaxClientConnections: 97,
+				},
+			},
+		}
+	}
+
+	getCondition := func(ctx SpecContext) *metav1.Condition {
+		var updatedCluster apiv1.Cluster
+		Expect(env.client.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		return meta.FindStatusCondition(updatedCluster.Status.Conditions,
+			string(apiv1.ConditionConnectionsExhausted))
+	}
+
+	It("keeps reconciling and raises the condition when the connections are saturated", func(ctx SpecContext) {
+		Expect(env.clusterReconciler.reconcileConnectionsExhausted(ctx, cluster, instancesStatus(97))).
+			To(Succeed())
+
+		condition := getCondition(ctx)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonConnectionsExhausted)))
+		Expect(condition.Message).To(ContainSubstring("Client connections exhausted on " + cluster.Name + "-1."))
+		Expect(condition.Message).ToNot(ContainSubstring(cluster.Name + "-2"))
+
+		recorder := env.clusterReconciler.Recorder.(*record.FakeRecorder)
+		Expect(recorder.Events).To(Receive(ContainSubstring(string(apiv1.ConditionReasonConnectionsExhausted))))
+	})
+
+	It("keeps the condition stable while the connections change", func(ctx SpecContext) {
+		Expect(env.clusterReconciler.reconcileConnectionsExhausted(ctx, cluster, instancesStatus(97))).
+			To(Succeed())
+		condition := getCondition(ctx)
+
+		Expect(env.clusterReconciler.reconcileConnectionsExhausted(ctx, cluster, instancesStatus(98))).
+			To(Succeed())
+		updatedCondition := getCondition(ctx)
+		Expect(updatedCondition.Message).To(Equal(condition.Message))
+		Expect(updatedCondition.LastTransitionTime).To(Equal(condition.LastTransitionTime))
+	})
+
+	It("clears the condition once the connections are released", func(ctx SpecContext) {
+		Expect(env.clusterReconciler.reconcileConnectionsExhausted(ctx, cluster, instancesStatus(97))).
+			To(Succeed())
+		Expect(env.clusterReconciler.reconcileConnectionsExhausted(ctx, cluster, instancesStatus(50))).
+			To(Succeed())
+
+		condition := getCondition(ctx)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonConnectionsAvailable)))
+	})
+
+	It("leaves the condition untouched when no instance reported its status", func(ctx SpecContext) {
+		status := instancesStatus(97)
+		for idx := range status.Items {
+			status.Items[idx].Error = errors.New("unreachable")
+		}
+		Expect(env.clusterReconciler.reconcileConnectionsExhausted(ctx, cluster, status)).To(Succeed())
+		Expect(getCondition(ctx)).To(BeNil())
+	})
+})
@@ -134,6 +134,7 @@ var ErrNextLoop = utils.ErrNextLoop
 // +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=create;patch;update;list;watch;get
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;create;delete;update;patch;list;watch
 // +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;create;watch;list;patch
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=imagecatalogs,verbs=get;watch;list
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusterimagecatalogs,verbs=get;watch;list
 
@@ -152,6 +153,10 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	}
 
 	if cluster == nil {
+		deleteHighAvailabilityMetrics(req.NamespacedName)
+		deleteClockSkewMetrics(req.NamespacedName)
+		deletePromotableReplicasMetrics(req.NamespacedName)
+		deleteReplicationLagMetrics(req.NamespacedName)
 		if err := r.deleteDanglingMonitoringQueries(ctx, req.Namespace); err != nil {
 			contextLogger.Error(
 				err,
@@ -171,6 +176,10 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return ctrl.Result{}, err
 	}
 
+	// From now on, the logs of this reconciliation honor
+	// the log level requested for the cluster, if any
+	contextLogger, ctx = setupObjectLogLevel(ctx, cluster)
+
 	ctx = cluster.SetInContext(ctx)
 
 	// Load the plugins required to bootstrap and reconcile this cluster
@@ -201,10 +210,20 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 
 	ctx = setPluginClientInContext(ctx, pluginClient)
 
+	// Lift the fencing when it expired, making sure to be requeued
+	// in time to lift it otherwise. The fencing is left untouched in
+	// safe mode
+	var fencingExpiry time.Duration
+	if !utils.IsSafeModeEnabled(&cluster.ObjectMeta) {
+		if fencingExpiry, err = r.reconcileFencingExpiry(ctx, cluster); err != nil {
+			return ctrl.Result{}, fmt.Errorf("cannot lift the expired fencing: %w", err)
+		}
+	}
+
 	// Run the inner reconcile loop. Translate any ErrNextLoop to an errorless return
 	result, err := r.reconcile(ctx, cluster)
 	if errors.Is(err, ErrNextLoop) {
-		return result, nil
+		return requeueBefore(result, fencingExpiry), nil
 	}
 	if errors.Is(err, utils.ErrTerminateLoop) {
 		return ctrl.Result{}, nil
@@ -212,7 +231,7 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	if err != nil {
 		return ctrl.Result{}, err
 	}
-	return result, nil
+	return requeueBefore(result, fencingExpiry), nil
 }
 
 // Inner reconcile loop. Anything inside can require the reconciliation loop to stop by returning ErrNextLoop
@@ -239,6 +258,13 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return ctrl.Result{}, err
 	}
 
+	// In safe mode, the status of the cluster is updated as usual, but
+	// every step that could change the instances or their PVCs is skipped
+	safeMode := utils.IsSafeModeEnabled(&cluster.ObjectMeta)
+	if err := r.reconcileSafeMode(ctx, cluster); err != nil {
+		return ctrl.Result{}, fmt.Errorf("cannot update the safe mode condition: %w", err)
+	}
+
 	// Make sure default values are populated.
 	err = r.setDefaults(ctx, cluster)
 	if err != nil {
@@ -259,24 +285,26 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return ctrl.Result{}, fmt.Errorf("cannot reconcile required plugins: %w", err)
 	}
 
-	// Ensure we reconcile the orphan resources if present when we reconcile for the first time a cluster
-	if res, err := r.reconcileRestoredCluster(ctx, cluster); res != nil || err != nil {
-		if res != nil {
-			return *res, nil
+	if !safeMode {
+		// Ensure we reconcile the orphan resources if present when we reconcile for the first time a cluster
+		if res, err := r.reconcileRestoredCluster(ctx, cluster); res != nil || err != nil {
+			if res != nil {
+				return *res, nil
+			}
+			return ctrl.Result{}, fmt.Errorf("cannot reconcile restored Cluster: %w", err)
 		}
-		return ctrl.Result{}, fmt.Errorf("cannot reconcile restored Cluster: %w", err)
-	}
 
-	// Ensure we have the required global objects
-	if err := r.createPostgresClusterObjects(ctx, cluster); err != nil {
-		if errors.Is(err, ErrNextLoop) {
-			return ctrl.Result{}, err
-		}
-		contextLogger.Error(err, "while reconciling postgres cluster objects")
-		if regErr := r.RegisterPhase(ctx, cluster, apiv1.PhaseCannotCreateClusterObjects, err.Error()); regErr != nil {
-			contextLogger.Error(regErr, "unable to register phase", "outerErr", err.Error())
+		// Ensure we have the required global objects
+		if err := r.createPostgresClusterObjects(ctx, cluster); err != nil {
+			if errors.Is(err, ErrNextLoop) {
+				return ctrl.Result{}, err
+			}
+			contextLogger.Error(err, "while reconciling postgres cluster objects")
+			if regErr := r.RegisterPhase(ctx, cluster, apiv1.PhaseCannotCreateClusterObjects, err.Error()); regErr != nil {
+				contextLogger.Error(regErr, "unable to register phase", "outerErr", err.Error())
+			}
+			return ctrl.Result{}, fmt.Errorf("cannot create Cluster auxiliary objects: %w", err)
 		}
-		return ctrl.Result{}, fmt.Errorf("cannot create Cluster auxiliary objects: %w", err)
 	}
 
 	// Update the status of this resource
@@ -298,11 +326,53 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return ctrl.Result{}, fmt.Errorf("cannot update the resource status: %w", err)
 	}
 
-	// Calls pre-reconcile hooks
-	if hookResult := preReconcilePluginHooks(ctx, cluster, cluster); hookResult.StopReconciliation {
-		contextLogger.Info("Pre-reconcile hook stopped the reconciliation loop",
-			"hookResult", hookResult)
-		return hookResult.Result, hookResult.Err
+	if !safeMode {
+		if res, err := r.reconcileMajorUpgrade(ctx, cluster, resources); res != nil || err != nil {
+			if res != nil {
+				return *res, nil
+			}
+			if errors.Is(err, ErrNextLoop) {
+				return ctrl.Result{}, nil
+			}
+			if apierrs.IsConflict(err) {
+				contextLogger.Debug("Conflict error while reconciling the major upgrade", "error", err)
+				return ctrl.Result{Requeue: true}, nil
+			}
+			return ctrl.Result{}, fmt.Errorf("cannot reconcile the major upgrade: %w", err)
+		}
+	}
+
+	if err := r.reconcileReplicationSlotsCollision(ctx, cluster); err != nil {
+		if apierrs.IsConflict(err) {
+			contextLogger.Debug("Conflict error while reconciling replication slots collision", "error", err)
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("cannot detect replication slot collisions: %w", err)
+	}
+
+	if err := r.reconcileHighAvailability(ctx, cluster); err != nil {
+		if apierrs.IsConflict(err) {
+			contextLogger.Debug("Conflict error while reconciling high availability condition", "error", err)
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("cannot update the high availability condition: %w", err)
+	}
+
+	if err := r.reconcileRecoveryWindow(ctx, cluster); err != nil {
+		if apierrs.IsConflict(err) {
+			contextLogger.Debug("Conflict error while reconciling recovery window condition", "error", err)
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("cannot update the recovery window condition: %w", err)
+	}
+
+	if !safeMode {
+		// Calls pre-reconcile hooks
+		if hookResult := preReconcilePluginHooks(ctx, cluster, cluster); hookResult.StopReconciliation {
+			contextLogger.Info("Pre-reconcile hook stopped the reconciliation loop",
+				"hookResult", hookResult)
+			return hookResult.Result, hookResult.Err
+		}
 	}
 
 	if cluster.Status.CurrentPrimary != "" &&
@@ -338,6 +408,7 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 
 	// Get the replication status
 	instancesStatus := r.InstanceClient.GetStatusFromInstances(ctx, resources.instances)
+	updateReplicationLagMetrics(client.ObjectKeyFromObject(cluster), instancesStatus)
 
 	// we update all the cluster status fields that require the instances status
 	if err := r.updateClusterStatusThatRequiresInstancesState(ctx, cluster, instancesStatus); err != nil {
@@ -370,32 +441,34 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		}, nil
 	}
 
-	if err := persistentvolumeclaim.ReconcileMetadata(
-		ctx,
-		r.Client,
-		cluster,
-		resources.pvcs.Items,
-	); err != nil {
-		return ctrl.Result{}, err
-	}
+	if !safeMode {
+		if err := persistentvolumeclaim.ReconcileMetadata(
+			ctx,
+			r.Client,
+			cluster,
+			resources.pvcs.Items,
+		); err != nil {
+			return ctrl.Result{}, err
+		}
 
-	if err := instanceReconciler.ReconcileMetadata(
-		ctx,
-		r.Client,
-		cluster,
-		resources.instances.Items,
-	); err != nil {
-		return ctrl.Result{}, err
-	}
+		if err := instanceReconciler.ReconcileMetadata(
+			ctx,
+			r.Client,
+			cluster,
+			resources.instances.Items,
+		); err != nil {
+			return ctrl.Result{}, err
+		}
 
-	if err := persistentvolumeclaim.ReconcileSerialAnnotation(
-		ctx,
-		r.Client,
-		cluster,
-		resources.instances.Items,
-		resources.pvcs.Items,
-	); err != nil {
-		return ctrl.Result{}, err
+		if err := persistentvolumeclaim.ReconcileSerialAnnotation(
+			ctx,
+			r.Client,
+			cluster,
+			resources.instances.Items,
+			resources.pvcs.Items,
+		); err != nil {
+			return ctrl.Result{}, err
+		}
 	}
 
 	if instancesStatus.AllReadyInstancesStatusUnreachable() {
@@ -413,6 +486,88 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return ctrl.Result{RequeueAfter: 10 * time.Second}, registerPhaseErr
 	}
 
+	if err := r.reconcilePreparedTransactions(ctx, cluster, instancesStatus); err != nil {
+		if apierrs.IsConflict(err) {
+			contextLogger.Debug("Conflict error while reconciling prepared transactions condition", "error", err)
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("cannot update the prepared transactions condition: %w", err)
+	}
+
+	if err := r.reconcileWraparoundRisk(ctx, cluster, instancesStatus); err != nil {
+		if apierrs.IsConflict(err) {
+			contextLogger.Debug("Conflict error while reconciling wraparound risk condition", "error", err)
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("cannot update the wraparound risk condition: %w", err)
+	}
+
+	if err := r.reconcileConnectionsExhausted(ctx, cluster, instancesStatus); err != nil {
+		if apierrs.IsConflict(err) {
+			contextLogger.Debug("Conflict error while reconciling connections exhausted condition", "error", err)
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("cannot update the connections exhausted condition: %w", err)
+	}
+
+	if err := r.reconcileDiskPressure(ctx, cluster, resources, instancesStatus); err != nil {
+		if apierrs.IsConflict(err) {
+			contextLogger.Debug("Conflict error while reconciling disk pressure", "error", err)
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("cannot reconcile disk pressure: %w", err)
+	}
+
+	if err := r.reconcileClockSkew(ctx, cluster, instancesStatus); err != nil {
+		if apierrs.IsConflict(err) {
+			contextLogger.Debug("Conflict error while reconciling clock skew", "error", err)
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("cannot update the clock skew condition: %w", err)
+	}
+
+	if err := r.reconcilePromotableReplicas(ctx, cluster, instancesStatus); err != nil {
+		if apierrs.IsConflict(err) {
+			contextLogger.Debug("Conflict error while reconciling promotable replicas", "error", err)
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("cannot update the promotable replica condition: %w", err)
+	}
+
+	if err := r.reconcileReplicationLimits(ctx, cluster); err != nil {
+		if apierrs.IsConflict(err) {
+			contextLogger.Debug("Conflict error while reconciling replication limits", "error", err)
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("cannot update the replication limits condition: %w", err)
+	}
+
+	return r.reconcileInstances(ctx, cluster, resources, instancesStatus)
+}
+
+// reconcileInstances runs the part of the reconciliation loop that can
+// change the instances or their PVCs, which is skipped in safe mode
+func (r *ClusterReconciler) reconcileInstances(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+	instancesStatus postgres.PostgresqlStatusList,
+) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	if utils.IsSafeModeEnabled(&cluster.ObjectMeta) {
+		return ctrl.Result{RequeueAfter: safeModeRequeueInterval}, nil
+	}
+
+	syncStandbyLagRequeue, err := r.reconcileSynchronousStandbyLag(ctx, cluster, instancesStatus)
+	if err != nil {
+		if apierrs.IsConflict(err) {
+			contextLogger.Debug("Conflict error while reconciling lagging synchronous standbys", "error", err)
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("cannot reconcile lagging synchronous standbys: %w", err)
+	}
+
 	if res, err := r.ensureNoFailoverOnFullDisk(ctx, cluster, instancesStatus); err != nil || !res.IsZero() {
 		return res, err
 	}
@@ -503,6 +658,17 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return res, err
 	}
 
+	// Switch the primary back to its preferred node, once it is healthy again
+	switchbackTarget, switchbackRequeue, err := r.reconcileSwitchback(ctx, cluster, instancesStatus)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("cannot switch back to the preferred node of the primary: %w", err)
+	}
+	if switchbackTarget != "" {
+		contextLogger.Info("Waiting for the new primary to notice the promotion request",
+			"newPrimary", switchbackTarget)
+		return ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+	}
+
 	// Calls post-reconcile hooks
 	if hookResult := postReconcilePluginHooks(ctx, cluster, cluster); hookResult.Err != nil ||
 		!hookResult.Result.IsZero() {
@@ -511,7 +677,18 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return hookResult.Result, hookResult.Err
 	}
 
-	return setStatusPluginHook(ctx, r.Client, getPluginClientFromContext(ctx), cluster)
+	res, err = setStatusPluginHook(ctx, r.Client, getPluginClientFromContext(ctx), cluster)
+	if err != nil || !res.IsZero() {
+		return res, err
+	}
+
+	// Check again the lagging synchronous standbys once their delay expires,
+	// and the deferred switchback
+	requeueAfter := syncStandbyLagRequeue
+	if switchbackRequeue > 0 && (requeueAfter == 0 || switchbackRequeue < requeueAfter) {
+		requeueAfter = switchbackRequeue
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (r *ClusterReconciler) ensureNoFailoverOnFullDisk(
@@ -586,6 +763,14 @@ func (r *ClusterReconciler) handleSwitchover(
 			contextLogger.Info("Waiting for all WAL receivers to be down to elect a new primary")
 			return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
 		}
+		if errors.Is(err, errSwitchoverCandidateLagging) {
+			contextLogger.Info("Waiting for the new primary to catch up before switching over")
+			return &ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+		}
+		if errors.Is(err, errSwitchoverCatchUpTimedOut) {
+			contextLogger.Warning("Switchover aborted, the new primary didn't catch up in time")
+			return &ctrl.Result{RequeueAfter: 1 * time.Minute}, nil
+		}
 		contextLogger.Info("Cannot update target primary: operation cannot be fulfilled. "+
 			"An immediate retry will be scheduled",
 			"error", err)
@@ -653,6 +838,15 @@ func (r *ClusterReconciler) reconcileResources(
 	resources *managedResources, instancesStatus postgres.PostgresqlStatusList,
 ) (ctrl.Result, error) {
 	contextLogger := log.FromContext(ctx)
+
+	// A failed bootstrap job would be waited for indefinitely
+	if res, err := r.reconcileBootstrapFailure(ctx, cluster, resources); res != nil || err != nil {
+		if res == nil {
+			return ctrl.Result{}, err
+		}
+		return *res, err
+	}
+
 	runningJobs := resources.runningJobNames()
 
 	// Act on Pods and PVCs only if there is nothing that is currently being created or deleted
@@ -738,14 +932,23 @@ func (r *ClusterReconciler) reconcileResources(
 		return ctrl.Result{RequeueAfter: 1 * time.Second}, ErrNextLoop
 	}
 
+	// During the initial bootstrap, wait for the managed objects
+	// to be created and replicated before declaring the cluster ready
+	if res, err := r.reconcileBootstrapReadiness(ctx, cluster, instancesStatus); res != nil || err != nil {
+		if res == nil {
+			return ctrl.Result{}, err
+		}
+		return *res, err
+	}
+
 	// When everything is reconciled, update the status
 	if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseHealthy, ""); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	r.cleanupCompletedJobs(ctx, resources.jobs)
+	requeueAfter := r.cleanupCompletedJobs(ctx, cluster, resources.jobs)
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 // deleteTerminatedPods will delete the Pods that are terminated
@@ -895,6 +1098,9 @@ func (r *ClusterReconciler) reconcilePods(
 	// Are there missing nodes? Let's create one
 	if cluster.Status.Instances < cluster.Spec.Instances &&
 		instancesStatus.InstancesReportingStatus() == cluster.Status.Instances {
+		if err := r.ensureReplicaCloneFitsDataVolume(ctx, cluster, instancesStatus); err != nil {
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, err
+		}
 		newNodeSerial, err := r.generateNodeSerial(ctx, cluster)
 		if err != nil {
 			return ctrl.Result{}, fmt.Errorf("cannot generate node serial: %w", err)
@@ -971,6 +1177,30 @@ func (r *ClusterReconciler) handleRollingUpdate(
 				"not connected via streaming replication, waiting for 5 seconds",
 		)
 		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	case errors.Is(err, errReplicasApplyingConfiguration):
+		contextLogger.Info(
+			"The primary needs to be restarted to apply a configuration change, " +
+				"waiting for the replicas to apply it first",
+		)
+		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	case errors.Is(err, errSwitchoverBlockedByBackup):
+		contextLogger.Info(
+			"The primary needs to be restarted, but the switchover is deferred " +
+				"until the running backups complete",
+		)
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	case errors.Is(err, errSwitchoverCandidateLagging):
+		contextLogger.Info(
+			"The primary needs to be restarted, but the switchover is deferred " +
+				"until the new primary catches up",
+		)
+		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	case errors.Is(err, errSwitchoverCatchUpTimedOut):
+		contextLogger.Warning(
+			"The primary needs to be restarted, but the switchover has been aborted " +
+				"as the new primary didn't catch up in time, retrying later",
+		)
+		return ctrl.Result{RequeueAfter: 1 * time.Minute}, nil
 	case errors.Is(err, errRolloutDelayed):
 		contextLogger.Warning(
 			"A Pod need to be rolled out, but the rollout is being delayed",
@@ -1035,6 +1265,11 @@ func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manag
 		Owns(&corev1.Service{}).
 		Owns(&corev1.PersistentVolumeClaim{}).
 		Owns(&policyv1.PodDisruptionBudget{}).
+		Watches(
+			&corev1.Pod{},
+			handler.EnqueueRequestsFromMapFunc(r.mapJobPodsToClusters()),
+			builder.WithPredicates(jobPodsPredicate),
+		).
 		Watches(
 			&corev1.ConfigMap{},
 			handler.EnqueueRequestsFromMapFunc(r.mapConfigMapsToClusters()),
@@ -1049,6 +1284,11 @@ func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manag
 			&apiv1.Pooler{},
 			handler.EnqueueRequestsFromMapFunc(r.mapPoolersToClusters()),
 		).
+		Watches(
+			&apiv1.Database{},
+			handler.EnqueueRequestsFromMapFunc(r.mapDatabasesToClusters()),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
 		Watches(
 			&corev1.Node{},
 			handler.EnqueueRequestsFromMapFunc(r.mapNodeToClusters()),
@@ -1218,6 +1458,26 @@ func (r *ClusterReconciler) mapSecretsToClusters() handler.MapFunc {
 	}
 }
 
+// mapDatabasesToClusters returns a function mapping Databases to the
+// Cluster they belong to
+func (r *ClusterReconciler) mapDatabasesToClusters() handler.MapFunc {
+	return func(_ context.Context, obj client.Object) []reconcile.Request {
+		database, ok := obj.(*apiv1.Database)
+		if !ok || database.Spec.ClusterRef.Name == "" {
+			return nil
+		}
+
+		return []reconcile.Request{
+			{
+				NamespacedName: types.NamespacedName{
+					Namespace: database.Namespace,
+					Name:      database.Spec.ClusterRef.Name,
+				},
+			},
+		}
+	}
+}
+
 func (r *ClusterReconciler) getClustersForSecretsOrConfigMapsToClustersMapper(
 	ctx context.Context,
 	object metav1.Object,
@@ -158,6 +158,11 @@ func (r *ClusterReconciler) reconcilePostgresSecrets(ctx context.Context, cluste
 		return err
 	}
 
+	err = r.reconcileDatabaseSecrets(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -301,22 +306,35 @@ func (r *ClusterReconciler) reconcilePostgresServices(ctx context.Context, clust
 		return err
 	}
 
+	headlessService := specs.CreateClusterHeadlessService(*cluster)
+	cluster.SetInheritedDataAndOwnership(&headlessService.ObjectMeta)
+
+	if err := r.serviceReconciler(ctx, cluster, headlessService, cluster.IsHeadlessServiceEnabled()); err != nil {
+		return err
+	}
+
 	readService := specs.CreateClusterReadService(*cluster)
-	cluster.SetInheritedDataAndOwnership(&readService.ObjectMeta)
+	cluster.SetDefaultServiceMetadataAndOwnership(&readService.ObjectMeta)
 
 	if err := r.serviceReconciler(ctx, cluster, readService, cluster.IsReadServiceEnabled()); err != nil {
 		return err
 	}
 
 	readOnlyService := specs.CreateClusterReadOnlyService(*cluster)
-	cluster.SetInheritedDataAndOwnership(&readOnlyService.ObjectMeta)
+	if cluster.ShouldReadOnlyServiceFallbackToPrimary() {
+		log.FromContext(ctx).Debug("No ready replica, the read-only service is falling back to the primary",
+			"serviceName", readOnlyService.Name,
+			"primary", cluster.Status.CurrentPrimary)
+		readOnlyService.Spec.Selector = readService.Spec.Selector
+	}
+	cluster.SetDefaultServiceMetadataAndOwnership(&readOnlyService.ObjectMeta)
 
 	if err := r.serviceReconciler(ctx, cluster, readOnlyService, cluster.IsReadOnlyServiceEnabled()); err != nil {
 		return err
 	}
 
 	readWriteService := specs.CreateClusterReadWriteService(*cluster)
-	cluster.SetInheritedDataAndOwnership(&readWriteService.ObjectMeta)
+	cluster.SetDefaultServiceMetadataAndOwnership(&readWriteService.ObjectMeta)
 
 	if err := r.serviceReconciler(ctx, cluster, readWriteService, cluster.IsReadWriteServiceEnabled()); err != nil {
 		return err
@@ -366,6 +384,31 @@ func (r *ClusterReconciler) reconcileManagedServices(ctx context.Context, cluste
 	return nil
 }
 
+// removeStaleDefaultServicesMetadata removes from the living service the
+// labels and annotations that were set from the default services metadata
+// and are not proposed anymore, returning true when any was removed
+func removeStaleDefaultServicesMetadata(living *metav1.ObjectMeta, proposed metav1.ObjectMeta) bool {
+	var changed bool
+	removeStale := func(living, proposed map[string]string, keys []string) {
+		for _, key := range keys {
+			if _, isProposed := proposed[key]; isProposed {
+				continue
+			}
+			if _, isLiving := living[key]; isLiving {
+				delete(living, key)
+				changed = true
+			}
+		}
+	}
+
+	keys := utils.GetDefaultServicesMetadataKeys(*living)
+	removeStale(living.Labels, proposed.Labels, keys.Labels)
+	removeStale(living.Annotations, proposed.Annotations,
+		append(keys.Annotations, utils.DefaultServicesMetadataAnnotationName))
+
+	return changed
+}
+
 func (r *ClusterReconciler) serviceReconciler(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
@@ -425,6 +468,12 @@ func (r *ClusterReconciler) serviceReconciler(
 		livingService.Annotations = make(map[string]string)
 	}
 
+	// we remove the labels/annotations that are not requested anymore in
+	// the default services metadata
+	if removeStaleDefaultServicesMetadata(&livingService.ObjectMeta, proposed.ObjectMeta) {
+		shouldUpdate = true
+	}
+
 	// we preserve existing labels/annotation that could be added by third parties
 	if !utils.IsMapSubset(livingService.Labels, proposed.Labels) {
 		utils.MergeMap(livingService.Labels, proposed.Labels)
@@ -1229,8 +1278,12 @@ func (r *ClusterReconciler) joinReplicaInstance(
 
 	job := specs.JoinReplicaInstance(*cluster, nodeSerial)
 
-	// If we can bootstrap this replica from a pre-existing source, we do it
-	storageSource := persistentvolumeclaim.GetCandidateStorageSourceForReplica(ctx, cluster, backupList)
+	// If we can bootstrap this replica from a pre-existing source, we do it,
+	// unless the user requested new replicas to be always cloned from the primary
+	var storageSource *persistentvolumeclaim.StorageSource
+	if cluster.Spec.Replication.GetCreateReplicaMethod() == apiv1.CreateReplicaMethodFromBackup {
+		storageSource = persistentvolumeclaim.GetCandidateStorageSourceForReplica(ctx, cluster, backupList)
+	}
 	if storageSource != nil {
 		job = specs.RestoreReplicaInstance(*cluster, nodeSerial)
 	}
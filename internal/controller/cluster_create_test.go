@@ -171,6 +171,32 @@ var _ = Describe("cluster_create unit tests", func() {
 			})
 		})
 
+	It("should make sure that reconcilePostgresServices creates the headless service only if enabled",
+		func(ctx SpecContext) {
+			namespace := newFakeNamespace(env.client)
+			cluster := newFakeCNPGCluster(env.client, namespace)
+
+			By("executing reconcilePostgresServices with the headless service disabled", func() {
+				err := env.clusterReconciler.reconcilePostgresServices(ctx, cluster)
+				Expect(err).ToNot(HaveOccurred())
+				expectResourceDoesntExist(env.client, cluster.GetServiceHeadlessName(), namespace, &corev1.Service{})
+			})
+
+			By("executing reconcilePostgresServices with the headless service enabled", func() {
+				cluster.Spec.Managed = &apiv1.ManagedConfiguration{
+					Services: &apiv1.ManagedServices{
+						Headless: &apiv1.HeadlessServiceConfiguration{Enabled: true},
+					},
+				}
+				err := env.clusterReconciler.reconcilePostgresServices(ctx, cluster)
+				Expect(err).ToNot(HaveOccurred())
+
+				var service corev1.Service
+				expectResourceExists(env.client, cluster.GetServiceHeadlessName(), namespace, &service)
+				Expect(service.Spec.ClusterIP).To(Equal(corev1.ClusterIPNone))
+			})
+		})
+
 	It("should make sure that reconcilePostgresServices can update the selectors on existing services",
 		func(ctx SpecContext) {
 			namespace := newFakeNamespace(env.client)
@@ -1285,6 +1311,48 @@ var _ = Describe("Service Reconciling", func() {
 				Expect(updatedService.Labels).To(HaveKeyWithValue("custom-label", "value"))
 				Expect(updatedService.Annotations).To(HaveKeyWithValue("custom-annotation", "value"))
 			})
+
+			It("should remove the labels and annotations not requested anymore in the default metadata", func() {
+				cluster.Spec.Managed.Services.DefaultServicesMetadata = &apiv1.EmbeddedObjectMetadata{
+					Labels:      map[string]string{"team": "dba", "tier": "gold"},
+					Annotations: map[string]string{"load-balancer": "internal"},
+				}
+				requested := proposedService.DeepCopy()
+				cluster.SetDefaultServiceMetadataAndOwnership(&requested.ObjectMeta)
+				Expect(reconciler.serviceReconciler(ctx, &cluster, requested, true)).To(Succeed())
+
+				var existingService corev1.Service
+				Expect(serviceClient.Get(ctx, k8client.ObjectKeyFromObject(proposedService), &existingService)).
+					To(Succeed())
+				existingService.Annotations["custom-annotation"] = "value"
+				Expect(serviceClient.Update(ctx, &existingService)).To(Succeed())
+
+				cluster.Spec.Managed.Services.DefaultServicesMetadata = &apiv1.EmbeddedObjectMetadata{
+					Labels: map[string]string{"team": "dba"},
+				}
+				requested = proposedService.DeepCopy()
+				cluster.SetDefaultServiceMetadataAndOwnership(&requested.ObjectMeta)
+				Expect(reconciler.serviceReconciler(ctx, &cluster, requested, true)).To(Succeed())
+
+				var updatedService corev1.Service
+				Expect(serviceClient.Get(ctx, k8client.ObjectKeyFromObject(proposedService), &updatedService)).
+					To(Succeed())
+				Expect(updatedService.Labels).To(HaveKeyWithValue("team", "dba"))
+				Expect(updatedService.Labels).ToNot(HaveKey("tier"))
+				Expect(updatedService.Annotations).ToNot(HaveKey("load-balancer"))
+				Expect(updatedService.Annotations).To(HaveKeyWithValue("custom-annotation", "value"))
+
+				cluster.Spec.Managed.Services.DefaultServicesMetadata = nil
+				requested = proposedService.DeepCopy()
+				cluster.SetDefaultServiceMetadataAndOwnership(&requested.ObjectMeta)
+				Expect(reconciler.serviceReconciler(ctx, &cluster, requested, true)).To(Succeed())
+
+				Expect(serviceClient.Get(ctx, k8client.ObjectKeyFromObject(proposedService), &updatedService)).
+					To(Succeed())
+				Expect(updatedService.Labels).ToNot(HaveKey("team"))
+				Expect(updatedService.Annotations).ToNot(HaveKey(utils.DefaultServicesMetadataAnnotationName))
+				Expect(updatedService.Annotations).To(HaveKeyWithValue("custom-annotation", "value"))
+			})
 		})
 	})
 
@@ -1339,5 +1407,116 @@ var _ = Describe("Service Reconciling", func() {
 			)
 			Expect(apierrs.IsNotFound(err)).To(BeTrue())
 		})
+
+		It("should make the read-only service fall back to the primary when all the replicas are down", func() {
+			cluster.Spec.Managed.Services.RO = &apiv1.ReadOnlyServiceConfiguration{FallbackToPrimary: true}
+			cluster.Status.CurrentPrimary = "test-cluster-1"
+			cluster.Status.InstancesStatus = map[apiv1.PodStatus][]string{
+				apiv1.PodHealthy: {"test-cluster-1", "test-cluster-2", "test-cluster-3"},
+			}
+
+			getReadOnlyServiceSelector := func() map[string]string {
+				var service corev1.Service
+				err := reconciler.Client.Get(
+					ctx,
+					types.NamespacedName{Name: cluster.GetServiceReadOnlyName(), Namespace: cluster.Namespace},
+					&service,
+				)
+				Expect(err).ToNot(HaveOccurred())
+				return service.Spec.Selector
+			}
+
+			By("selecting only the replicas while they are ready", func() {
+				Expect(reconciler.reconcilePostgresServices(ctx, &cluster)).To(Succeed())
+				Expect(getReadOnlyServiceSelector()).To(Equal(
+					specs.CreateClusterReadOnlyService(cluster).Spec.Selector))
+			})
+
+			By("including the primary when all the replicas are down", func() {
+				cluster.Status.InstancesStatus = map[apiv1.PodStatus][]string{
+					apiv1.PodHealthy: {"test-cluster-1"},
+					apiv1.PodFailed:  {"test-cluster-2", "test-cluster-3"},
+				}
+				Expect(reconciler.reconcilePostgresServices(ctx, &cluster)).To(Succeed())
+				Expect(getReadOnlyServiceSelector()).To(Equal(map[string]string{
+					utils.ClusterLabelName: cluster.Name,
+					utils.PodRoleLabelName: string(utils.PodRoleInstance),
+				}))
+			})
+
+			By("reverting to the replicas once one of them is ready again", func() {
+				cluster.Status.InstancesStatus = map[apiv1.PodStatus][]string{
+					apiv1.PodHealthy: {"test-cluster-1", "test-cluster-2"},
+					apiv1.PodFailed:  {"test-cluster-3"},
+				}
+				Expect(reconciler.reconcilePostgresServices(ctx, &cluster)).To(Succeed())
+				Expect(getReadOnlyServiceSelector()).To(Equal(
+					specs.CreateClusterReadOnlyService(cluster).Spec.Selector))
+			})
+		})
+
+		It("should publish the external DNS host name on the read-write service following the primary", func() {
+			cluster.Spec.Managed.Services.RW = &apiv1.ReadWriteServiceConfiguration{
+				ExternalDNSHostname: "db.example.com",
+			}
+
+			createInstancePod := func(name string, role string) {
+				Expect(serviceClient.Create(ctx, &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: cluster.Namespace,
+						Labels: map[string]string{
+							utils.ClusterLabelName:             cluster.Name,
+							utils.ClusterInstanceRoleLabelName: role,
+						},
+					},
+				})).To(Succeed())
+			}
+
+			setInstanceRole := func(name string, role string) {
+				var pod corev1.Pod
+				Expect(serviceClient.Get(ctx, types.NamespacedName{Name: name, Namespace: cluster.Namespace}, &pod)).
+					To(Succeed())
+				pod.Labels[utils.ClusterInstanceRoleLabelName] = role
+				Expect(serviceClient.Update(ctx, &pod)).To(Succeed())
+			}
+
+			// expectReadWriteServiceTarget checks that the read-write service
+			// carries the host name and selects only the passed instance
+			expectReadWriteServiceTarget := func(podName string) {
+				var service corev1.Service
+				Expect(serviceClient.Get(
+					ctx,
+					types.NamespacedName{Name: cluster.GetServiceReadWriteName(), Namespace: cluster.Namespace},
+					&service,
+				)).To(Succeed())
+				Expect(service.Annotations).To(
+					HaveKeyWithValue(utils.ExternalDNSHostnameAnnotationName, "db.example.com"))
+
+				var pods corev1.PodList
+				Expect(serviceClient.List(ctx, &pods, k8client.InNamespace(cluster.Namespace),
+					k8client.MatchingLabels(service.Spec.Selector))).To(Succeed())
+				Expect(pods.Items).To(HaveLen(1))
+				Expect(pods.Items[0].Name).To(Equal(podName))
+			}
+
+			createInstancePod("test-cluster-1", specs.ClusterRoleLabelPrimary)
+			createInstancePod("test-cluster-2", specs.ClusterRoleLabelReplica)
+
+			By("selecting the primary", func() {
+				Expect(reconciler.reconcilePostgresServices(ctx, &cluster)).To(Succeed())
+				expectReadWriteServiceTarget("test-cluster-1")
+			})
+
+			By("selecting the new primary after a switchover", func() {
+				setInstanceRole("test-cluster-1", specs.ClusterRoleLabelReplica)
+				setInstanceRole("test-cluster-2", specs.ClusterRoleLabelPrimary)
+				cluster.Status.CurrentPrimary = "test-cluster-2"
+				cluster.Status.TargetPrimary = "test-cluster-2"
+
+				Expect(reconciler.reconcilePostgresServices(ctx, &cluster)).To(Succeed())
+				expectReadWriteServiceTarget("test-cluster-2")
+			})
+		})
 	})
 })
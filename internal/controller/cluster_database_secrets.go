@@ -0,0 +1,167 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// reconcileDatabaseSecrets creates and updates the connection secrets
+// of the databases of the cluster requesting them
+func (r *ClusterReconciler) reconcileDatabaseSecrets(ctx context.Context, cluster *apiv1.Cluster) error {
+	var databases apiv1.DatabaseList
+	if err := r.List(ctx, &databases, client.InNamespace(cluster.Namespace)); err != nil {
+		return err
+	}
+
+	for idx := range databases.Items {
+		database := &databases.Items[idx]
+		if database.Spec.ClusterRef.Name != cluster.Name {
+			continue
+		}
+		if err := r.reconcileDatabaseSecret(ctx, cluster, database); err != nil {
+			return fmt.Errorf("while reconciling the connection secret of database %s: %w", database.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// reconcileDatabaseSecret creates, updates or deletes the connection
+// secret of a database
+func (r *ClusterReconciler) reconcileDatabaseSecret(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	database *apiv1.Database,
+) error {
+	contextLogger := log.FromContext(ctx).WithValues("database", database.Name)
+	secretName := database.GetConnectionSecretName()
+
+	var currentSecret corev1.Secret
+	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: secretName}, &currentSecret)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+	found := err == nil
+
+	// We never touch a secret we don't own, as its name could be colliding
+	// with the one of another object
+	if found && !metav1.IsControlledBy(&currentSecret, database) {
+		contextLogger.Warning("The connection secret name is already used by another object",
+			"secretName", secretName)
+		r.Recorder.Eventf(database, "Warning", "ConnectionSecretNameCollision",
+			"Secret %s already exists and is not owned by this database", secretName)
+		return nil
+	}
+
+	if !database.Spec.ConnectionSecret || database.Spec.Ensure == apiv1.EnsureAbsent {
+		if !found {
+			return nil
+		}
+		contextLogger.Info("Deleting the connection secret", "secretName", secretName)
+		return client.IgnoreNotFound(r.Delete(ctx, &currentSecret))
+	}
+
+	ownerPassword, err := r.getRolePassword(ctx, cluster, database.Spec.Owner)
+	if err != nil {
+		return err
+	}
+	if ownerPassword == "" {
+		contextLogger.Debug("The password of the database owner is not known, skipping the connection secret",
+			"owner", database.Spec.Owner)
+		return nil
+	}
+
+	proposedSecret := specs.CreateSecret(
+		secretName,
+		cluster.Namespace,
+		cluster.GetServiceReadWriteName(),
+		database.Spec.Name,
+		database.Spec.Owner,
+		ownerPassword)
+	cluster.SetInheritedData(&proposedSecret.ObjectMeta)
+	utils.SetAsOwnedBy(&proposedSecret.ObjectMeta, database.ObjectMeta, metav1.TypeMeta{
+		APIVersion: apiv1.GroupVersion.String(),
+		Kind:       apiv1.DatabaseKind,
+	})
+
+	// Differently from the application user secret, the content of this
+	// secret is deterministic and is kept in sync with the role password
+	proposedSecret.Data = make(map[string][]byte, len(proposedSecret.StringData))
+	for key, value := range proposedSecret.StringData {
+		proposedSecret.Data[key] = []byte(value)
+	}
+	proposedSecret.StringData = nil
+
+	if !found {
+		contextLogger.Info("Creating the connection secret", "secretName", secretName)
+		return r.Create(ctx, proposedSecret)
+	}
+
+	patchedSecret := currentSecret.DeepCopy()
+	utils.MergeObjectsMetadata(patchedSecret, proposedSecret)
+	patchedSecret.Data = proposedSecret.Data
+	if reflect.DeepEqual(patchedSecret, &currentSecret) {
+		return nil
+	}
+
+	contextLogger.Info("Updating the connection secret", "secretName", secretName)
+	return r.Patch(ctx, patchedSecret, client.MergeFrom(&currentSecret))
+}
+
+// getRolePassword gets the password of a role from the application user
+// secret or from the password secret of the corresponding managed role,
+// returning an empty string if the password is not known to the operator
+func (r *ClusterReconciler) getRolePassword(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	roleName string,
+) (string, error) {
+	var secretName string
+	if roleName == cluster.GetApplicationDatabaseOwner() {
+		secretName = cluster.GetApplicationSecretName()
+	}
+	if cluster.Spec.Managed != nil {
+		for _, role := range cluster.Spec.Managed.Roles {
+			if role.Name == roleName && role.PasswordSecret != nil {
+				secretName = role.PasswordSecret.Name
+			}
+		}
+	}
+	if secretName == "" {
+		return "", nil
+	}
+
+	var secret corev1.Secret
+	if err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: secretName}, &secret); err != nil {
+		return "", client.IgnoreNotFound(err)
+	}
+
+	return string(secret.Data[corev1.BasicAuthPasswordKey]), nil
+}
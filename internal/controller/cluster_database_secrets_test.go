@@ -0,0 +1,148 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("database connection secrets", func() {
+	var (
+		env     *testingEnvironment
+		cluster *apiv1.Cluster
+	)
+
+	createPasswordSecret := func(ctx SpecContext, name, username, password string) {
+		Expect(env.client.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: cluster.Namespace},
+			Type:       corev1.SecretTypeBasicAuth,
+			Data: map[string][]byte{
+				corev1.BasicAuthUsernameKey: []byte(username),
+				corev1.BasicAuthPasswordKey: []byte(password),
+			},
+		})).To(Succeed())
+	}
+
+	createDatabase := func(ctx SpecContext, name, dbname, owner string) *apiv1.Database {
+		database := &apiv1.Database{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: cluster.Namespace},
+			Spec: apiv1.DatabaseSpec{
+				ClusterRef:       corev1.LocalObjectReference{Name: cluster.Name},
+				Name:             dbname,
+				Owner:            owner,
+				ConnectionSecret: true,
+			},
+		}
+		Expect(env.client.Create(ctx, database)).To(Succeed())
+		return database
+	}
+
+	getSecret := func(ctx SpecContext, database *apiv1.Database) *corev1.Secret {
+		var secret corev1.Secret
+		Expect(env.client.Get(
+			ctx,
+			client.ObjectKey{Namespace: cluster.Namespace, Name: database.GetConnectionSecretName()},
+			&secret,
+		)).To(Succeed())
+		return &secret
+	}
+
+	BeforeEach(func(ctx SpecContext) {
+		env = buildTestEnvironment()
+		cluster = newFakeCNPGCluster(env.client, newFakeNamespace(env.client), func(cluster *apiv1.Cluster) {
+			cluster.Spec.Managed = &apiv1.ManagedConfiguration{
+				Roles: []apiv1.RoleConfiguration{
+					{Name: "alice", Login: true, PasswordSecret: &apiv1.LocalObjectReference{Name: "alice-password"}},
+					{Name: "bob", Login: true, PasswordSecret: &apiv1.LocalObjectReference{Name: "bob-password"}},
+				},
+			}
+		})
+		createPasswordSecret(ctx, cluster.GetApplicationSecretName(), "app", "app-password")
+		createPasswordSecret(ctx, "alice-password", "alice", "alice-password")
+		createPasswordSecret(ctx, "bob-password", "bob", "bob-password")
+	})
+
+	It("creates a correctly populated secret for every database", func(ctx SpecContext) {
+		databases := []*apiv1.Database{
+			createDatabase(ctx, "db-app", "app", "app"),
+			createDatabase(ctx, "db-alice", "alice_db", "alice"),
+			createDatabase(ctx, "db-bob", "bob_db", "bob"),
+		}
+		Expect(env.clusterReconciler.reconcileDatabaseSecrets(ctx, cluster)).To(Succeed())
+
+		for _, database := range databases {
+			secret := getSecret(ctx, database)
+			Expect(secret.Name).To(Equal(cluster.Name + "-" + database.Name + "-app"))
+			Expect(metav1.IsControlledBy(secret, database)).To(BeTrue())
+			Expect(string(secret.Data["dbname"])).To(Equal(database.Spec.Name))
+			Expect(string(secret.Data["username"])).To(Equal(database.Spec.Owner))
+			Expect(string(secret.Data["password"])).To(Equal(database.Spec.Owner + "-password"))
+			Expect(string(secret.Data["host"])).To(Equal(cluster.GetServiceReadWriteName()))
+		}
+	})
+
+	It("keeps the secret in sync with the password of the owner", func(ctx SpecContext) {
+		database := createDatabase(ctx, "db-alice", "alice_db", "alice")
+		Expect(env.clusterReconciler.reconcileDatabaseSecrets(ctx, cluster)).To(Succeed())
+
+		var passwordSecret corev1.Secret
+		Expect(env.client.Get(
+			ctx,
+			client.ObjectKey{Namespace: cluster.Namespace, Name: "alice-password"},
+			&passwordSecret,
+		)).To(Succeed())
+		passwordSecret.Data[corev1.BasicAuthPasswordKey] = []byte("new-password")
+		Expect(env.client.Update(ctx, &passwordSecret)).To(Succeed())
+
+		Expect(env.clusterReconciler.reconcileDatabaseSecrets(ctx, cluster)).To(Succeed())
+		Expect(string(getSecret(ctx, database).Data["password"])).To(Equal("new-password"))
+	})
+
+	It("doesn't touch a secret with a colliding name", func(ctx SpecContext) {
+		database := createDatabase(ctx, "db-alice", "alice_db", "alice")
+		createPasswordSecret(ctx, database.GetConnectionSecretName(), "someone", "else")
+
+		Expect(env.clusterReconciler.reconcileDatabaseSecrets(ctx, cluster)).To(Succeed())
+		Expect(string(getSecret(ctx, database).Data["username"])).To(Equal("someone"))
+	})
+
+	It("deletes the secret when it is not requested anymore", func(ctx SpecContext) {
+		database := createDatabase(ctx, "db-alice", "alice_db", "alice")
+		Expect(env.clusterReconciler.reconcileDatabaseSecrets(ctx, cluster)).To(Succeed())
+		getSecret(ctx, database)
+
+		database.Spec.ConnectionSecret = false
+		Expect(env.client.Update(ctx, database)).To(Succeed())
+		Expect(env.clusterReconciler.reconcileDatabaseSecrets(ctx, cluster)).To(Succeed())
+
+		var secret corev1.Secret
+		err := env.client.Get(
+			ctx,
+			client.ObjectKey{Namespace: cluster.Namespace, Name: database.GetConnectionSecretName()},
+			&secret,
+		)
+		Expect(client.IgnoreNotFound(err)).To(Succeed())
+		Expect(err).To(HaveOccurred())
+	})
+})
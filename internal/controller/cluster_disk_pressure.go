@@ -0,0 +1,322 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// dataVolumeSizeAlignment is the granularity used when computing the new
+// size of an automatically expanded data volume
+const dataVolumeSizeAlignment = 1024 * 1024
+
+// reconcileDiskPressure updates the DiskPressure condition using the data
+// volume usage reported by the instances and, when requested, expands the
+// data volumes before PostgreSQL runs out of space
+func (r *ClusterReconciler) reconcileDiskPressure(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+	instancesStatus postgres.PostgresqlStatusList,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	condition := getDiskPressureCondition(cluster, instancesStatus)
+	if condition == nil {
+		return nil
+	}
+
+	if condition.Status == metav1.ConditionTrue &&
+		!meta.IsStatusConditionTrue(cluster.Status.Conditions, condition.Type) {
+		contextLogger.Warning("Data volume running out of space",
+			"message", condition.Message,
+			"dataVolumeUsage", getDataVolumeUsageDetails(instancesStatus))
+		r.Recorder.Event(cluster, "Warning", condition.Reason, condition.Message)
+	}
+
+	if err := conditions.Patch(ctx, r.Client, cluster, condition); err != nil {
+		return err
+	}
+
+	if condition.Status != metav1.ConditionTrue || !cluster.Spec.DiskPressure.ShouldAutoExpand() ||
+		utils.IsSafeModeEnabled(&cluster.ObjectMeta) {
+		return nil
+	}
+
+	return r.expandDataVolumes(ctx, cluster, resources, instancesStatus)
+}
+
+// getDiskPressureCondition computes the DiskPressure condition from the
+// data volume usage reported by the instances, returning nil when no
+// instance reported it
+func getDiskPressureCondition(
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) *metav1.Condition {
+	threshold := cluster.Spec.DiskPressure.GetThreshold()
+
+	reported := false
+	var instancesUnderPressure []string
+	for _, status := range instancesStatus.Items {
+		if status.DataVolumeUsage == nil || status.Pod == nil {
+			continue
+		}
+
+		reported = true
+		usedPercentage := status.DataVolumeUsage.UsedPercentage()
+		if usedPercentage >= float64(threshold) {
+			instancesUnderPressure = append(instancesUnderPressure, status.Pod.Name)
+		}
+	}
+
+	if !reported {
+		return nil
+	}
+
+	if len(instancesUnderPressure) > 0 {
+		return &metav1.Condition{
+			Type:   string(apiv1.ConditionDiskPressure),
+			Status: metav1.ConditionTrue,
+			Reason: string(apiv1.ConditionReasonDataVolumeAlmostFull),
+			Message: fmt.Sprintf(
+				"The data volume is used above %d%% of its capacity on: %s. "+
+					"PostgreSQL will shut down when the volume is full",
+				threshold, strings.Join(instancesUnderPressure, ", ")),
+		}
+	}
+
+	return &metav1.Condition{
+		Type:    string(apiv1.ConditionDiskPressure),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionReasonDataVolumeUsageBelowThreshold),
+		Message: fmt.Sprintf("The data volumes are used below %d%% of their capacity", threshold),
+	}
+}
+
+// getDataVolumeUsageDetails returns the data volume usage percentage
+// reported by every instance, to be logged
+func getDataVolumeUsageDetails(instancesStatus postgres.PostgresqlStatusList) map[string]string {
+	result := make(map[string]string, len(instancesStatus.Items))
+	for _, status := range instancesStatus.Items {
+		if status.DataVolumeUsage == nil || status.Pod == nil {
+			continue
+		}
+
+		result[status.Pod.Name] = fmt.Sprintf("%.1f%%", status.DataVolumeUsage.UsedPercentage())
+	}
+
+	return result
+}
+
+// expandDataVolumes increases the requested size of the data PVC of every
+// instance under disk pressure. The PVCs are patched directly, leaving
+// the storage configuration of the cluster untouched
+func (r *ClusterReconciler) expandDataVolumes(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+	instancesStatus postgres.PostgresqlStatusList,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	maxSize, err := resource.ParseQuantity(cluster.Spec.DiskPressure.MaxSize)
+	if err != nil {
+		contextLogger.Warning("Cannot automatically expand the data volumes: invalid maximum size",
+			"maxSize", cluster.Spec.DiskPressure.MaxSize)
+		return nil
+	}
+
+	threshold := cluster.Spec.DiskPressure.GetThreshold()
+	for _, status := range instancesStatus.Items {
+		if status.DataVolumeUsage == nil || status.Pod == nil ||
+			status.DataVolumeUsage.UsedPercentage() < float64(threshold) {
+			continue
+		}
+
+		pvc := getDataPVC(resources.pvcs.Items, status.Pod.Name)
+		if pvc == nil {
+			continue
+		}
+
+		if err := r.expandDataVolume(ctx, cluster, pvc, maxSize); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// expandDataVolume increases the requested size of a data PVC by the
+// configured percentage, without going beyond the maximum size
+func (r *ClusterReconciler) expandDataVolume(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	pvc *corev1.PersistentVolumeClaim,
+	maxSize resource.Quantity,
+) error {
+	contextLogger := log.FromContext(ctx).WithValues("pvcName", pvc.Name)
+
+	currentSize := pvc.Spec.Resources.Requests.Storage().DeepCopy()
+	if pvc.Status.Capacity.Storage().Cmp(currentSize) < 0 {
+		contextLogger.Debug("Waiting for the data volume to be expanded", "size", currentSize.String())
+		return nil
+	}
+
+	newSize, ok := getExpandedDataVolumeSize(
+		currentSize,
+		cluster.Spec.DiskPressure.GetExpansionPercentage(),
+		maxSize,
+	)
+	if !ok {
+		contextLogger.Warning("Cannot automatically expand the data volume: maximum size reached",
+			"size", currentSize.String(),
+			"maxSize", maxSize.String())
+		return nil
+	}
+
+	allowed, err := r.isVolumeExpansionAllowed(ctx, pvc)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		contextLogger.Warning("Cannot automatically expand the data volume: " +
+			"the storage class doesn't allow volume expansion")
+		return nil
+	}
+
+	origPVC := pvc.DeepCopy()
+	pvc.Spec.Resources.Requests[corev1.ResourceStorage] = newSize
+	if err := r.Patch(ctx, pvc, client.MergeFrom(origPVC)); err != nil {
+		return err
+	}
+
+	contextLogger.Info("Expanding the data volume",
+		"fromSize", currentSize.String(),
+		"toSize", newSize.String())
+	r.Recorder.Eventf(cluster, "Normal", "ExpandingDataVolume",
+		"Expanding the data volume %s from %s to %s", pvc.Name, currentSize.String(), newSize.String())
+
+	return nil
+}
+
+// getDataPVC finds the PVC containing the PGDATA of an instance
+func getDataPVC(pvcs []corev1.PersistentVolumeClaim, instanceName string) *corev1.PersistentVolumeClaim {
+	pvcName := persistentvolumeclaim.NewPgDataCalculator().GetName(instanceName)
+	for idx := range pvcs {
+		if pvcs[idx].Name == pvcName {
+			return &pvcs[idx]
+		}
+	}
+
+	return nil
+}
+
+// isVolumeExpansionAllowed checks if the storage class of a PVC allows
+// volume expansion
+func (r *ClusterReconciler) isVolumeExpansionAllowed(
+	ctx context.Context,
+	pvc *corev1.PersistentVolumeClaim,
+) (bool, error) {
+	if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		return false, nil
+	}
+
+	var storageClass storagev1.StorageClass
+	err := r.Get(ctx, client.ObjectKey{Name: *pvc.Spec.StorageClassName}, &storageClass)
+	if apierrs.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("while getting storage class %s: %w", *pvc.Spec.StorageClassName, err)
+	}
+
+	return storageClass.AllowVolumeExpansion != nil && *storageClass.AllowVolumeExpansion, nil
+}
+
+// getExpandedDataVolumeSize computes the size of a data volume after an
+// automatic expansion, capped to the maximum size. The second return value
+// is false when the volume cannot be expanded anymore
+func getExpandedDataVolumeSize(
+	currentSize resource.Quantity,
+	expansionPercentage int,
+	maxSize resource.Quantity,
+) (resource.Quantity, bool) {
+	current := currentSize.Value()
+	expanded := current + current*int64(expansionPercentage)/100
+	expanded = (expanded + dataVolumeSizeAlignment - 1) / dataVolumeSizeAlignment * dataVolumeSizeAlignment
+	expanded = min(expanded, maxSize.Value())
+
+	if expanded <= current {
+		return currentSize, false
+	}
+
+	return *resource.NewQuantity(expanded, resource.BinarySI), true
+}
+
+// ensureReplicaCloneFitsDataVolume refuses to clone a new replica when
+// the data currently stored by the primary would fill the data volume of
+// the new instance above the disk pressure threshold
+func (r *ClusterReconciler) ensureReplicaCloneFitsDataVolume(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) error {
+	size := cluster.Spec.StorageConfiguration.GetSizeOrNil()
+	if size == nil || size.IsZero() {
+		return nil
+	}
+
+	threshold := cluster.Spec.DiskPressure.GetThreshold()
+	for _, status := range instancesStatus.Items {
+		if !status.IsPrimary || status.DataVolumeUsage == nil {
+			continue
+		}
+
+		usedBytes := status.DataVolumeUsage.UsedBytes()
+		if float64(usedBytes) < float64(size.Value())*float64(threshold)/100 {
+			return nil
+		}
+
+		used := resource.NewQuantity(int64(usedBytes), resource.BinarySI) //nolint:gosec
+		message := fmt.Sprintf(
+			"Refusing to clone a new replica: the %s used on the primary data volume "+
+				"would fill the %s data volume of the new instance above %d%% of its capacity",
+			used, size, threshold)
+		log.FromContext(ctx).Warning(message)
+		r.Recorder.Event(cluster, "Warning", "ReplicaCloneWouldFillVolume", message)
+		return ErrNextLoop
+	}
+
+	return nil
+}
@@ -0,0 +1,264 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("disk pressure", func() {
+	const gi = 1024 * 1024 * 1024
+
+	var (
+		env       *testingEnvironment
+		cluster   *apiv1.Cluster
+		resources *managedResources
+	)
+
+	createStorageClass := func(ctx SpecContext, name string, allowVolumeExpansion bool) {
+		storageClass := &storagev1.StorageClass{
+			ObjectMeta:           metav1.ObjectMeta{Name: name},
+			Provisioner:          "csi.example.com",
+			AllowVolumeExpansion: ptr.To(allowVolumeExpansion),
+		}
+		Expect(env.client.Create(ctx, storageClass)).To(Succeed())
+	}
+
+	createDataPVC := func(ctx SpecContext, storageClassName string, request string, capacity string) {
+		pvc := corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cluster.Name + "-1",
+				Namespace: cluster.Namespace,
+				Labels: map[string]string{
+					utils.PvcRoleLabelName: string(utils.PVCRolePgData),
+				},
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				StorageClassName: ptr.To(storageClassName),
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(request)},
+				},
+			},
+			Status: corev1.PersistentVolumeClaimStatus{
+				Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(capacity)},
+			},
+		}
+		Expect(env.client.Create(ctx, &pvc)).To(Succeed())
+		resources.pvcs.Items = []corev1.PersistentVolumeClaim{pvc}
+	}
+
+	getDataPVCRequest := func(ctx SpecContext) string {
+		var pvc corev1.PersistentVolumeClaim
+		Expect(env.client.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.Name + "-1"}, &pvc)).
+			To(Succeed())
+		return pvc.Spec.Resources.Requests.Storage().String()
+	}
+
+	enableAutoExpand := func(ctx SpecContext, maxSize string) {
+		cluster.Spec.DiskPressure = &apiv1.DiskPressureConfiguration{AutoExpand: true, MaxSize: maxSize}
+		Expect(env.client.Update(ctx, cluster)).To(Succeed())
+	}
+
+	instancesStatus := func(availableBytes uint64) postgres.PostgresqlStatusList {
+		return postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{
+					Pod:       &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: cluster.Name + "-1"}},
+					IsPrimary: true,
+					DataVolumeUsage: &postgres.DiskUsage{
+						TotalBytes:     10 * gi,
+						AvailableBytes: availableBytes,
+					},
+				},
+			},
+		}
+	}
+
+	getUpdatedCluster := func(ctx SpecContext) *apiv1.Cluster {
+		var updatedCluster apiv1.Cluster
+		Expect(env.client.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		return &updatedCluster
+	}
+
+	BeforeEach(func(ctx SpecContext) {
+		env = buildTestEnvironment()
+		cluster = newFakeCNPGCluster(env.client, newFakeNamespace(env.client), func(cluster *apiv1.Cluster) {
+			cluster.Spec.StorageConfiguration.Size = "10Gi"
+		})
+		resources = &managedResources{}
+		createStorageClass(ctx, "expandable", true)
+		createStorageClass(ctx, "fixed", false)
+	})
+
+	It("raises the condition when the data volume is almost full", func(ctx SpecContext) {
+		Expect(env.clusterReconciler.reconcileDiskPressure(ctx, cluster, resources, instancesStatus(gi/2))).To(Succeed())
+
+		updatedCluster := getUpdatedCluster(ctx)
+		condition := meta.FindStatusCondition(updatedCluster.Status.Conditions, string(apiv1.ConditionDiskPressure))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonDataVolumeAlmostFull)))
+		Expect(condition.Message).To(ContainSubstring("above 90% of its capacity on: " + cluster.Name + "-1."))
+		Expect(updatedCluster.Spec.StorageConfiguration.Size).To(Equal("10Gi"))
+
+		recorder := env.clusterReconciler.Recorder.(*record.FakeRecorder)
+		Expect(recorder.Events).To(Receive(ContainSubstring(string(apiv1.ConditionReasonDataVolumeAlmostFull))))
+	})
+
+	It("keeps the condition stable while the usage changes", func(ctx SpecContext) {
+		Expect(env.clusterReconciler.reconcileDiskPressure(ctx, cluster, resources, instancesStatus(gi/2))).To(Succeed())
+		condition := meta.FindStatusCondition(getUpdatedCluster(ctx).Status.Conditions,
+			string(apiv1.ConditionDiskPressure))
+		Expect(condition).ToNot(BeNil())
+
+		Expect(env.clusterReconciler.reconcileDiskPressure(ctx, cluster, resources, instancesStatus(gi/4))).To(Succeed())
+		updatedCondition := meta.FindStatusCondition(getUpdatedCluster(ctx).Status.Conditions,
+			string(apiv1.ConditionDiskPressure))
+		Expect(updatedCondition).ToNot(BeNil())
+		Expect(updatedCondition.Message).To(Equal(condition.Message))
+		Expect(updatedCondition.LastTransitionTime).To(Equal(condition.LastTransitionTime))
+
+		recorder := env.clusterReconciler.Recorder.(*record.FakeRecorder)
+		Expect(recorder.Events).To(HaveLen(1))
+	})
+
+	It("clears the condition when the data volume usage is below the threshold", func(ctx SpecContext) {
+		Expect(env.clusterReconciler.reconcileDiskPressure(ctx, cluster, resources, instancesStatus(5*gi))).To(Succeed())
+
+		updatedCluster := getUpdatedCluster(ctx)
+		condition := meta.FindStatusCondition(updatedCluster.Status.Conditions, string(apiv1.ConditionDiskPressure))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonDataVolumeUsageBelowThreshold)))
+	})
+
+	It("doesn't expand the data volumes unless requested", func(ctx SpecContext) {
+		createDataPVC(ctx, "expandable", "10Gi", "10Gi")
+
+		Expect(env.clusterReconciler.reconcileDiskPressure(ctx, cluster, resources, instancesStatus(gi/2))).
+			To(Succeed())
+		Expect(getDataPVCRequest(ctx)).To(Equal("10Gi"))
+	})
+
+	It("expands the data volume of the instance under pressure", func(ctx SpecContext) {
+		enableAutoExpand(ctx, "50Gi")
+		createDataPVC(ctx, "expandable", "10Gi", "10Gi")
+
+		Expect(env.clusterReconciler.reconcileDiskPressure(ctx, cluster, resources, instancesStatus(gi/2))).
+			To(Succeed())
+		Expect(getDataPVCRequest(ctx)).To(Equal("12Gi"))
+		Expect(getUpdatedCluster(ctx).Spec.StorageConfiguration.Size).To(Equal("10Gi"))
+
+		recorder := env.clusterReconciler.Recorder.(*record.FakeRecorder)
+		Expect(recorder.Events).To(Receive(ContainSubstring(string(apiv1.ConditionReasonDataVolumeAlmostFull))))
+		Expect(recorder.Events).To(Receive(ContainSubstring(
+			"Expanding the data volume " + cluster.Name + "-1 from 10Gi to 12Gi")))
+	})
+
+	It("doesn't expand the data volume beyond the maximum size", func(ctx SpecContext) {
+		enableAutoExpand(ctx, "11Gi")
+		createDataPVC(ctx, "expandable", "10Gi", "10Gi")
+
+		Expect(env.clusterReconciler.reconcileDiskPressure(ctx, cluster, resources, instancesStatus(gi/2))).
+			To(Succeed())
+		Expect(getDataPVCRequest(ctx)).To(Equal("11Gi"))
+	})
+
+	It("stops expanding the data volume once the maximum size is reached", func(ctx SpecContext) {
+		enableAutoExpand(ctx, "10Gi")
+		createDataPVC(ctx, "expandable", "10Gi", "10Gi")
+
+		Expect(env.clusterReconciler.reconcileDiskPressure(ctx, cluster, resources, instancesStatus(gi/2))).
+			To(Succeed())
+		Expect(getDataPVCRequest(ctx)).To(Equal("10Gi"))
+	})
+
+	It("waits for the previous expansion to complete", func(ctx SpecContext) {
+		enableAutoExpand(ctx, "50Gi")
+		createDataPVC(ctx, "expandable", "12Gi", "10Gi")
+
+		Expect(env.clusterReconciler.reconcileDiskPressure(ctx, cluster, resources, instancesStatus(gi/2))).
+			To(Succeed())
+		Expect(getDataPVCRequest(ctx)).To(Equal("12Gi"))
+	})
+
+	It("doesn't expand the data volume when the storage class doesn't allow it", func(ctx SpecContext) {
+		enableAutoExpand(ctx, "50Gi")
+		createDataPVC(ctx, "fixed", "10Gi", "10Gi")
+
+		Expect(env.clusterReconciler.reconcileDiskPressure(ctx, cluster, resources, instancesStatus(gi/2))).
+			To(Succeed())
+		Expect(meta.IsStatusConditionTrue(getUpdatedCluster(ctx).Status.Conditions,
+			string(apiv1.ConditionDiskPressure))).To(BeTrue())
+		Expect(getDataPVCRequest(ctx)).To(Equal("10Gi"))
+	})
+
+	It("doesn't expand the data volume when the cluster is in safe mode", func(ctx SpecContext) {
+		cluster.Annotations = map[string]string{utils.SafeModeAnnotationName: "true"}
+		enableAutoExpand(ctx, "50Gi")
+		createDataPVC(ctx, "expandable", "10Gi", "10Gi")
+
+		Expect(env.clusterReconciler.reconcileDiskPressure(ctx, cluster, resources, instancesStatus(gi/2))).
+			To(Succeed())
+		Expect(getDataPVCRequest(ctx)).To(Equal("10Gi"))
+	})
+
+	It("doesn't expand the data volume of the instances below the threshold", func(ctx SpecContext) {
+		enableAutoExpand(ctx, "50Gi")
+		createDataPVC(ctx, "expandable", "10Gi", "10Gi")
+
+		Expect(env.clusterReconciler.reconcileDiskPressure(ctx, cluster, resources, instancesStatus(5*gi))).
+			To(Succeed())
+		Expect(getDataPVCRequest(ctx)).To(Equal("10Gi"))
+	})
+
+	It("refuses to clone a replica that would fill its data volume", func(ctx SpecContext) {
+		Expect(env.clusterReconciler.ensureReplicaCloneFitsDataVolume(ctx, cluster, instancesStatus(gi/2))).
+			To(MatchError(ErrNextLoop))
+		Expect(env.clusterReconciler.ensureReplicaCloneFitsDataVolume(ctx, cluster, instancesStatus(5*gi))).
+			To(Succeed())
+
+		recorder := env.clusterReconciler.Recorder.(*record.FakeRecorder)
+		Expect(recorder.Events).To(Receive(ContainSubstring("ReplicaCloneWouldFillVolume")))
+	})
+})
+
+var _ = DescribeTable("getExpandedDataVolumeSize",
+	func(currentSize string, percentage int, maxSize string, expectedSize string, expectedOK bool) {
+		size, ok := getExpandedDataVolumeSize(resource.MustParse(currentSize), percentage, resource.MustParse(maxSize))
+		Expect(ok).To(Equal(expectedOK))
+		Expect(size.String()).To(Equal(expectedSize))
+	},
+	Entry("expands by the requested percentage", "10Gi", 20, "50Gi", "12Gi", true),
+	Entry("rounds up to the next mebibyte", "1G", 10, "50Gi", "1050Mi", true),
+	Entry("caps to the maximum size", "10Gi", 50, "12Gi", "12Gi", true),
+	Entry("refuses to expand beyond the maximum size", "12Gi", 20, "12Gi", "12Gi", false),
+)
@@ -0,0 +1,120 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/cloudnative-pg/machinery/pkg/stringset"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// reconcileFencingExpiry lifts the fencing of the instances once the time
+// set in the fencedUntil annotation has passed. It returns how long to
+// wait before the fencing expires, or zero when there's nothing to wait for
+func (r *ClusterReconciler) reconcileFencingExpiry(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) (time.Duration, error) {
+	contextLogger := log.FromContext(ctx)
+
+	fencedUntil, err := utils.GetFencedUntil(cluster.Annotations)
+	if err != nil {
+		contextLogger.Warning("Ignoring the fencing expiry time",
+			"annotation", utils.FencedUntilAnnotation,
+			"value", cluster.Annotations[utils.FencedUntilAnnotation])
+		return 0, nil
+	}
+	if fencedUntil == nil {
+		return 0, nil
+	}
+
+	fencedInstances, err := utils.GetFencedInstances(cluster.Annotations)
+	if err != nil || fencedInstances.Len() == 0 {
+		return 0, nil
+	}
+
+	if remaining := time.Until(*fencedUntil); remaining > 0 {
+		return remaining, nil
+	}
+
+	origCluster := cluster.DeepCopy()
+	delete(cluster.Annotations, utils.FencedInstanceAnnotation)
+	delete(cluster.Annotations, utils.FencedUntilAnnotation)
+	if err := r.Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+		return 0, err
+	}
+
+	fencedInstancesList := fencedInstances.ToSortedList()
+	contextLogger.Info("Fencing expired, lifting it",
+		"fencedInstances", fencedInstancesList,
+		"fencedUntil", fencedUntil)
+	r.Recorder.Eventf(cluster, "Normal", "FencingExpired",
+		"Lifted the fencing of %s as it expired at %s",
+		strings.Join(fencedInstancesList, ", "), fencedUntil.Format(time.RFC3339))
+
+	return 0, nil
+}
+
+// updateFencedInstancesStatus resolves the instances that are currently
+// fenced and stores them in the status of the cluster, recording an event
+// for each instance that has been fenced or unfenced since the last time
+// the status was updated
+func (r *ClusterReconciler) updateFencedInstancesStatus(cluster *apiv1.Cluster) {
+	var fencedInstances []string
+	for _, name := range cluster.Status.InstanceNames {
+		if cluster.IsInstanceFenced(name) {
+			fencedInstances = append(fencedInstances, name)
+		}
+	}
+
+	previouslyFenced := stringset.From(cluster.Status.FencedInstances)
+	currentlyFenced := stringset.From(fencedInstances)
+	for _, name := range fencedInstances {
+		if !previouslyFenced.Has(name) {
+			r.Recorder.Eventf(cluster, "Normal", "FencingOn", "Instance %s has been fenced", name)
+		}
+	}
+	for _, name := range cluster.Status.FencedInstances {
+		if !currentlyFenced.Has(name) {
+			r.Recorder.Eventf(cluster, "Normal", "FencingOff", "Instance %s has been unfenced", name)
+		}
+	}
+
+	cluster.Status.FencedInstances = fencedInstances
+}
+
+// requeueBefore makes sure the reconciliation loop is requeued no later
+// than the passed delay, when it is not zero
+func requeueBefore(result ctrl.Result, delay time.Duration) ctrl.Result {
+	if delay <= 0 {
+		return result
+	}
+
+	if result.RequeueAfter == 0 || result.RequeueAfter > delay {
+		result.RequeueAfter = delay
+	}
+
+	return result
+}
@@ -0,0 +1,176 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("fencing expiry", func() {
+	var (
+		r        *ClusterReconciler
+		recorder *record.FakeRecorder
+	)
+
+	newFencedCluster := func(fencedUntil time.Time) *apiv1.Cluster {
+		return newFakeCNPGCluster(r.Client, newFakeNamespace(r.Client), func(cluster *apiv1.Cluster) {
+			cluster.Annotations[utils.FencedInstanceAnnotation] = `["` + cluster.Name + `-1"]`
+			cluster.Annotations[utils.FencedUntilAnnotation] = fencedUntil.Format(time.RFC3339)
+		})
+	}
+
+	getUpdatedCluster := func(ctx SpecContext, cluster *apiv1.Cluster) *apiv1.Cluster {
+		var updatedCluster apiv1.Cluster
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		return &updatedCluster
+	}
+
+	BeforeEach(func() {
+		k8sClient := fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithStatusSubresource(&apiv1.Cluster{}).
+			Build()
+		recorder = record.NewFakeRecorder(120)
+		r = &ClusterReconciler{Client: k8sClient, Recorder: recorder}
+	})
+
+	It("lifts the fencing once the expiry time has passed", func(ctx SpecContext) {
+		cluster := newFencedCluster(time.Now().Add(-time.Minute))
+
+		remaining, err := r.reconcileFencingExpiry(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(remaining).To(BeZero())
+
+		updatedCluster := getUpdatedCluster(ctx, cluster)
+		Expect(updatedCluster.Annotations).ToNot(HaveKey(utils.FencedInstanceAnnotation))
+		Expect(updatedCluster.Annotations).ToNot(HaveKey(utils.FencedUntilAnnotation))
+		Expect(recorder.Events).To(Receive(ContainSubstring("FencingExpired")))
+	})
+
+	It("waits for the expiry time before lifting the fencing", func(ctx SpecContext) {
+		cluster := newFencedCluster(time.Now().Add(time.Hour))
+
+		remaining, err := r.reconcileFencingExpiry(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(remaining).To(BeNumerically("~", time.Hour, time.Minute))
+
+		updatedCluster := getUpdatedCluster(ctx, cluster)
+		Expect(updatedCluster.Annotations).To(HaveKey(utils.FencedInstanceAnnotation))
+		Expect(recorder.Events).ToNot(Receive())
+	})
+
+	It("ignores the expiry time when no instance is fenced", func(ctx SpecContext) {
+		cluster := newFakeCNPGCluster(r.Client, newFakeNamespace(r.Client), func(cluster *apiv1.Cluster) {
+			cluster.Annotations[utils.FencedUntilAnnotation] = time.Now().Add(time.Hour).Format(time.RFC3339)
+		})
+
+		remaining, err := r.reconcileFencingExpiry(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(remaining).To(BeZero())
+	})
+
+	It("ignores an invalid expiry time", func(ctx SpecContext) {
+		cluster := newFakeCNPGCluster(r.Client, newFakeNamespace(r.Client), func(cluster *apiv1.Cluster) {
+			cluster.Annotations[utils.FencedInstanceAnnotation] = `["*"]`
+			cluster.Annotations[utils.FencedUntilAnnotation] = "tomorrow"
+		})
+
+		remaining, err := r.reconcileFencingExpiry(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(remaining).To(BeZero())
+		Expect(getUpdatedCluster(ctx, cluster).Annotations).To(HaveKey(utils.FencedInstanceAnnotation))
+	})
+})
+
+var _ = Describe("fencing events", func() {
+	var (
+		r        *ClusterReconciler
+		recorder *record.FakeRecorder
+		cluster  *apiv1.Cluster
+	)
+
+	BeforeEach(func() {
+		recorder = record.NewFakeRecorder(120)
+		r = &ClusterReconciler{Recorder: recorder}
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "cluster-example",
+				Annotations: map[string]string{},
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				InstanceNames:  []string{"cluster-example-1", "cluster-example-2", "cluster-example-3"},
+			},
+		}
+	})
+
+	It("records an event when an instance is fenced", func() {
+		cluster.Annotations[utils.FencedInstanceAnnotation] = `["cluster-example-2"]`
+
+		r.updateFencedInstancesStatus(cluster)
+		Expect(cluster.Status.FencedInstances).To(Equal([]string{"cluster-example-2"}))
+		Expect(recorder.Events).To(Receive(Equal("Normal FencingOn Instance cluster-example-2 has been fenced")))
+		Expect(recorder.Events).ToNot(Receive())
+	})
+
+	It("records an event when an instance is unfenced", func() {
+		cluster.Annotations[utils.FencedInstanceAnnotation] = `["cluster-example-3"]`
+		cluster.Status.FencedInstances = []string{"cluster-example-2", "cluster-example-3"}
+
+		r.updateFencedInstancesStatus(cluster)
+		Expect(cluster.Status.FencedInstances).To(Equal([]string{"cluster-example-3"}))
+		Expect(recorder.Events).To(Receive(Equal("Normal FencingOff Instance cluster-example-2 has been unfenced")))
+		Expect(recorder.Events).ToNot(Receive())
+	})
+
+	It("records nothing when the fenced instances didn't change", func() {
+		cluster.Annotations[utils.FencedInstanceAnnotation] = `["role=replica"]`
+		cluster.Status.FencedInstances = []string{"cluster-example-2", "cluster-example-3"}
+
+		r.updateFencedInstancesStatus(cluster)
+		r.updateFencedInstancesStatus(cluster)
+		Expect(cluster.Status.FencedInstances).To(Equal([]string{"cluster-example-2", "cluster-example-3"}))
+		Expect(recorder.Events).ToNot(Receive())
+	})
+})
+
+var _ = DescribeTable("requeueBefore",
+	func(result ctrl.Result, delay time.Duration, expected ctrl.Result) {
+		Expect(requeueBefore(result, delay)).To(Equal(expected))
+	},
+	Entry("no delay", ctrl.Result{RequeueAfter: time.Second}, time.Duration(0),
+		ctrl.Result{RequeueAfter: time.Second}),
+	Entry("delay shorter than the requeue", ctrl.Result{RequeueAfter: time.Minute}, time.Second,
+		ctrl.Result{RequeueAfter: time.Second}),
+	Entry("delay longer than the requeue", ctrl.Result{RequeueAfter: time.Second}, time.Minute,
+		ctrl.Result{RequeueAfter: time.Second}),
+	Entry("no requeue", ctrl.Result{}, time.Minute,
+		ctrl.Result{RequeueAfter: time.Minute}),
+)
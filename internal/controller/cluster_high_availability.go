@@ -0,0 +1,120 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+var (
+	instancesAvailable = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "cnpg",
+		Name:      "instances_available",
+		Help:      "Number of ready instances of the cluster",
+	}, []string{"namespace", "cluster"})
+
+	instancesRequested = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "cnpg",
+		Name:      "instances_requested",
+		Help:      "Number of instances requested in the cluster specification",
+	}, []string{"namespace", "cluster"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(instancesAvailable, instancesRequested)
+}
+
+// reconcileHighAvailability updates the HighAvailabilityDegraded condition
+// and the instances metrics, comparing the number of ready instances with the
+// requested ones
+func (r *ClusterReconciler) reconcileHighAvailability(ctx context.Context, cluster *apiv1.Cluster) error {
+	contextLogger := log.FromContext(ctx)
+
+	instancesAvailable.WithLabelValues(cluster.Namespace, cluster.Name).Set(float64(cluster.Status.ReadyInstances))
+	instancesRequested.WithLabelValues(cluster.Namespace, cluster.Name).Set(float64(cluster.Spec.Instances))
+
+	// Wait for the first primary to be created before reporting anything
+	if cluster.Status.CurrentPrimary == "" {
+		return nil
+	}
+
+	condition := getHighAvailabilityCondition(cluster)
+	if condition.Status == metav1.ConditionTrue &&
+		!meta.IsStatusConditionTrue(cluster.Status.Conditions, condition.Type) {
+		contextLogger.Warning("High availability degraded",
+			"readyInstances", cluster.Status.ReadyInstances,
+			"instances", cluster.Spec.Instances)
+		r.Recorder.Event(cluster, "Warning", condition.Reason, condition.Message)
+	}
+
+	return conditions.Patch(ctx, r.Client, cluster, condition)
+}
+
+// getHighAvailabilityCondition computes the HighAvailabilityDegraded condition,
+// distinguishing clusters intentionally running a single instance from the
+// ones that lost instances due to failures
+func getHighAvailabilityCondition(cluster *apiv1.Cluster) *metav1.Condition {
+	condition := &metav1.Condition{
+		Type:    string(apiv1.ConditionHighAvailabilityDegraded),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionReasonAllInstancesAvailable),
+		Message: "All the requested instances are ready",
+	}
+
+	switch {
+	case cluster.Annotations[utils.HibernationAnnotationName] == string(utils.HibernationAnnotationValueOn):
+		condition.Reason = string(apiv1.ConditionReasonClusterHibernated)
+		condition.Message = "The cluster is hibernated"
+
+	case cluster.Spec.Instances < 2:
+		condition.Reason = string(apiv1.ConditionReasonSingleInstanceCluster)
+		condition.Message = "The cluster has been requested to run a single instance"
+
+	case cluster.Status.ReadyInstances < 2:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = string(apiv1.ConditionReasonSingleInstanceRemaining)
+		condition.Message = fmt.Sprintf("Only %d of %d instances are ready, high availability is lost",
+			cluster.Status.ReadyInstances, cluster.Spec.Instances)
+
+	case cluster.Status.ReadyInstances < cluster.Spec.Instances:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = string(apiv1.ConditionReasonInstancesUnavailable)
+		condition.Message = fmt.Sprintf("Only %d of %d instances are ready",
+			cluster.Status.ReadyInstances, cluster.Spec.Instances)
+	}
+
+	return condition
+}
+
+// deleteHighAvailabilityMetrics removes the instances metrics of a
+// cluster that doesn't exist anymore
+func deleteHighAvailabilityMetrics(cluster types.NamespacedName) {
+	instancesAvailable.DeleteLabelValues(cluster.Namespace, cluster.Name)
+	instancesRequested.DeleteLabelValues(cluster.Namespace, cluster.Name)
+}
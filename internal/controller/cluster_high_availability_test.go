@@ -0,0 +1,113 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("high availability degradation", func() {
+	var (
+		env     *testingEnvironment
+		cluster *apiv1.Cluster
+	)
+
+	BeforeEach(func(ctx SpecContext) {
+		env = buildTestEnvironment()
+		cluster = newFakeCNPGCluster(env.client, newFakeNamespace(env.client))
+		cluster.Status.CurrentPrimary = cluster.Name + "-1"
+		cluster.Status.ReadyInstances = cluster.Spec.Instances
+		Expect(env.client.Status().Update(ctx, cluster)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		deleteHighAvailabilityMetrics(client.ObjectKeyFromObject(cluster))
+	})
+
+	getCondition := func(ctx SpecContext) *metav1.Condition {
+		var updatedCluster apiv1.Cluster
+		Expect(env.client.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		return meta.FindStatusCondition(updatedCluster.Status.Conditions,
+			string(apiv1.ConditionHighAvailabilityDegraded))
+	}
+
+	setReadyInstances := func(ctx SpecContext, readyInstances int) {
+		cluster.Status.ReadyInstances = readyInstances
+		Expect(env.clusterReconciler.reconcileHighAvailability(ctx, cluster)).To(Succeed())
+	}
+
+	It("raises the condition when replicas are lost and clears it on recovery", func(ctx SpecContext) {
+		setReadyInstances(ctx, 3)
+		Expect(getCondition(ctx).Status).To(Equal(metav1.ConditionFalse))
+		Expect(testutil.ToFloat64(instancesAvailable.WithLabelValues(cluster.Namespace, cluster.Name))).
+			To(BeEquivalentTo(3))
+
+		By("killing a replica", func() {
+			setReadyInstances(ctx, 2)
+			condition := getCondition(ctx)
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonInstancesUnavailable)))
+		})
+
+		By("killing the other replica", func() {
+			setReadyInstances(ctx, 1)
+			condition := getCondition(ctx)
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonSingleInstanceRemaining)))
+			Expect(testutil.ToFloat64(instancesAvailable.WithLabelValues(cluster.Namespace, cluster.Name))).
+				To(BeEquivalentTo(1))
+		})
+
+		By("recovering the replicas", func() {
+			setReadyInstances(ctx, 3)
+			condition := getCondition(ctx)
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonAllInstancesAvailable)))
+			Expect(testutil.ToFloat64(instancesAvailable.WithLabelValues(cluster.Namespace, cluster.Name))).
+				To(BeEquivalentTo(3))
+		})
+	})
+
+	It("doesn't raise the condition for a cluster intentionally scaled to one instance", func(ctx SpecContext) {
+		cluster.Spec.Instances = 1
+		setReadyInstances(ctx, 1)
+
+		condition := getCondition(ctx)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonSingleInstanceCluster)))
+	})
+
+	It("doesn't raise the condition for a hibernated cluster", func(ctx SpecContext) {
+		cluster.Annotations = map[string]string{
+			utils.HibernationAnnotationName: string(utils.HibernationAnnotationValueOn),
+		}
+		setReadyInstances(ctx, 0)
+
+		condition := getCondition(ctx)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonClusterHibernated)))
+	})
+})
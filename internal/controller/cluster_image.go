@@ -40,6 +40,14 @@ func (r *ClusterReconciler) reconcileImage(ctx context.Context, cluster *apiv1.C
 
 	oldCluster := cluster.DeepCopy()
 
+	// Keep track of the image the data directories have been created
+	// with, before the image in the status is changed
+	if cluster.Status.PGDataImageInfo == nil && cluster.Status.Image != "" {
+		if imageInfo, err := getImageInfo(cluster.Status.Image); err == nil {
+			cluster.Status.PGDataImageInfo = imageInfo
+		}
+	}
+
 	// If ImageName is defined and different from the current image in the status, we update the status
 	if cluster.Spec.ImageName != "" && cluster.Status.Image != cluster.Spec.ImageName {
 		cluster.Status.Image = cluster.Spec.ImageName
@@ -0,0 +1,101 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/go-logr/logr"
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// logLevels maps the values accepted by the log level
+// annotation to the corresponding logging priority
+var logLevels = map[string]zapcore.Level{
+	log.ErrorLevelString:   log.ErrorLevel,
+	log.WarningLevelString: log.WarningLevel,
+	log.InfoLevelString:    log.InfoLevel,
+	log.DebugLevelString:   log.DebugLevel,
+	log.TraceLevelString:   log.TraceLevel,
+}
+
+// zapLogSink is implemented by the log sinks backed by a zap logger
+type zapLogSink interface {
+	GetUnderlying() *zap.Logger
+}
+
+// setupObjectLogLevel returns a context whose logger honors the level
+// requested through the log level annotation of the passed object,
+// regardless of the level of the operator. The passed context is
+// returned unchanged when the annotation is not set
+func setupObjectLogLevel(ctx context.Context, object metav1.Object) (log.Logger, context.Context) {
+	contextLogger := log.FromContext(ctx)
+
+	levelName, found := object.GetAnnotations()[utils.LogLevelAnnotationName]
+	if !found {
+		return contextLogger, ctx
+	}
+
+	level, ok := logLevels[levelName]
+	if !ok {
+		contextLogger.Warning("Ignoring invalid log level annotation",
+			"annotation", utils.LogLevelAnnotationName, "value", levelName)
+		return contextLogger, ctx
+	}
+
+	sink, ok := logr.FromContextOrDiscard(ctx).GetSink().(zapLogSink)
+	if !ok {
+		return contextLogger, ctx
+	}
+
+	zapLogger := sink.GetUnderlying().WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &levelOverrideCore{Core: core, level: level}
+	}))
+	ctx = logr.NewContext(ctx, zapr.NewLogger(zapLogger))
+	return log.FromContext(ctx), ctx
+}
+
+// levelOverrideCore is a zap core writing the entries having at least
+// the given level, ignoring the level of the wrapped core
+type levelOverrideCore struct {
+	zapcore.Core
+	level zapcore.Level
+}
+
+// Enabled implements the zapcore.LevelEnabler interface
+func (c *levelOverrideCore) Enabled(level zapcore.Level) bool {
+	return level >= c.level
+}
+
+// With implements the zapcore.Core interface
+func (c *levelOverrideCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelOverrideCore{Core: c.Core.With(fields), level: c.level}
+}
+
+// Check implements the zapcore.Core interface
+func (c *levelOverrideCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
@@ -0,0 +1,104 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/go-logr/logr"
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("per-cluster log level", func() {
+	var (
+		ctx      context.Context
+		observed *observer.ObservedLogs
+	)
+
+	newCluster := func(name string, annotations map[string]string) *apiv1.Cluster {
+		return &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "default",
+				Annotations: annotations,
+			},
+		}
+	}
+
+	// reconcile emits the log lines of a reconciliation of the passed cluster
+	reconcile := func(cluster *apiv1.Cluster) {
+		contextLogger, reconcileCtx := setupObjectLogLevel(ctx, cluster)
+		contextLogger.Debug("debug line", "cluster", cluster.Name)
+		log.FromContext(reconcileCtx).Info("info line", "cluster", cluster.Name)
+	}
+
+	// messagesOf returns the messages logged for the passed cluster
+	messagesOf := func(clusterName string) []string {
+		var messages []string
+		for _, entry := range observed.FilterField(zap.String("cluster", clusterName)).All() {
+			messages = append(messages, entry.Message)
+		}
+		return messages
+	}
+
+	BeforeEach(func() {
+		var core zapcore.Core
+		core, observed = observer.New(log.InfoLevel)
+		ctx = logr.NewContext(context.Background(), zapr.NewLogger(zap.New(core)))
+	})
+
+	It("emits debug lines only for the annotated cluster", func() {
+		reconcile(newCluster("debugged", map[string]string{utils.LogLevelAnnotationName: "debug"}))
+		reconcile(newCluster("default", nil))
+
+		Expect(messagesOf("debugged")).To(ConsistOf("debug line", "info line"))
+		Expect(messagesOf("default")).To(ConsistOf("info line"))
+	})
+
+	It("reverts to the default level when the annotation is removed", func() {
+		cluster := newCluster("debugged", map[string]string{utils.LogLevelAnnotationName: "trace"})
+		reconcile(cluster)
+		Expect(messagesOf("debugged")).To(ConsistOf("debug line", "info line"))
+
+		observed.TakeAll()
+		delete(cluster.Annotations, utils.LogLevelAnnotationName)
+		reconcile(cluster)
+		Expect(messagesOf("debugged")).To(ConsistOf("info line"))
+	})
+
+	It("can lower the verbosity of a single cluster", func() {
+		reconcile(newCluster("quiet", map[string]string{utils.LogLevelAnnotationName: "error"}))
+		Expect(messagesOf("quiet")).To(BeEmpty())
+	})
+
+	It("ignores invalid log levels", func() {
+		reconcile(newCluster("invalid", map[string]string{utils.LogLevelAnnotationName: "verbose"}))
+		Expect(messagesOf("invalid")).To(ConsistOf("info line"))
+		Expect(observed.FilterMessage("Ignoring invalid log level annotation").Len()).To(Equal(1))
+	})
+})
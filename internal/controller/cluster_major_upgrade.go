@@ -0,0 +1,308 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/image/reference"
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/cloudnative-pg/machinery/pkg/postgres/version"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
+)
+
+// reconcileMajorUpgrade keeps track of the image the data directories
+// have been created with and, when a newer major version is requested
+// with the `pg_upgrade` strategy, upgrades the data directory of the
+// primary instance in place
+func (r *ClusterReconciler) reconcileMajorUpgrade(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+) (*ctrl.Result, error) {
+	if cluster.Status.Image == "" {
+		return nil, nil
+	}
+
+	requestedVersion, err := cluster.GetPostgresqlVersion()
+	if err != nil {
+		// The image name has already been validated by the webhook
+		return nil, nil
+	}
+	requestedImageInfo := apiv1.ImageInfo{
+		Image:        cluster.Status.Image,
+		MajorVersion: int(requestedVersion.Major()), //nolint:gosec
+	}
+
+	dataImageInfo := cluster.Status.PGDataImageInfo
+	if dataImageInfo != nil &&
+		dataImageInfo.MajorVersion < requestedImageInfo.MajorVersion &&
+		cluster.ShouldUpgradeMajorVersionInPlace() &&
+		!cluster.IsReplica() {
+		return r.upgradeMajorVersion(ctx, cluster, resources, requestedImageInfo)
+	}
+
+	// The job of an upgrade which has been rolled back, restoring the
+	// previous image, is not needed anymore
+	if job := getMajorUpgradeJob(cluster, resources); job != nil {
+		if err := r.deleteMajorUpgradeJob(ctx, job); err != nil {
+			return nil, err
+		}
+	}
+
+	if dataImageInfo != nil && *dataImageInfo == requestedImageInfo {
+		return nil, nil
+	}
+
+	// Minor version changes, and major version changes not using the
+	// pg_upgrade strategy, are applied to the existing data directories
+	// with a rolling update
+	origCluster := cluster.DeepCopy()
+	cluster.Status.PGDataImageInfo = &requestedImageInfo
+	return nil, r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// upgradeMajorVersion shuts down every instance and upgrades the data
+// directory of the primary instance with a job running pg_upgrade. The
+// replicas will be cloned again from the upgraded primary
+func (r *ClusterReconciler) upgradeMajorVersion(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+	requestedImageInfo apiv1.ImageInfo,
+) (*ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx).WithValues(
+		"fromImage", cluster.Status.PGDataImageInfo.Image,
+		"toImage", requestedImageInfo.Image)
+
+	job := getMajorUpgradeJob(cluster, resources)
+	if job != nil {
+		if utils.GetJobFailedCondition(*job) != nil {
+			contextLogger.Warning("The major upgrade job failed", "jobName", job.Name)
+			if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseMajorUpgradeFailed,
+				fmt.Sprintf("Job %s failed, restore the %s image to start the cluster again",
+					job.Name, cluster.Status.PGDataImageInfo.Image)); err != nil {
+				return nil, err
+			}
+			return nil, ErrNextLoop
+		}
+
+		if !utils.JobHasOneCompletion(*job) {
+			contextLogger.Info("Waiting for the major upgrade job to complete", "jobName", job.Name)
+			return &ctrl.Result{RequeueAfter: 10 * time.Second}, ErrNextLoop
+		}
+
+		return r.completeMajorUpgrade(ctx, cluster, job, requestedImageInfo)
+	}
+
+	var err error
+	if len(resources.instances.Items) > 0 {
+		// pg_upgrade requires the old cluster to be cleanly shut down
+		err = r.shutdownInstancesForMajorUpgrade(ctx, resources)
+	} else {
+		err = r.createMajorUpgradeJob(ctx, cluster, resources)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if cluster.Status.Phase != apiv1.PhaseMajorUpgrade {
+		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseMajorUpgrade,
+			fmt.Sprintf("Upgrading from major %d to %d",
+				cluster.Status.PGDataImageInfo.MajorVersion, requestedImageInfo.MajorVersion)); err != nil {
+			return nil, err
+		}
+	}
+
+	return &ctrl.Result{RequeueAfter: 5 * time.Second}, ErrNextLoop
+}
+
+// shutdownInstancesForMajorUpgrade deletes every instance of the cluster
+func (r *ClusterReconciler) shutdownInstancesForMajorUpgrade(
+	ctx context.Context,
+	resources *managedResources,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	for idx := range resources.instances.Items {
+		pod := &resources.instances.Items[idx]
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		contextLogger.Info("Shutting down instance for the major upgrade", "pod", pod.Name)
+		if err := r.Delete(ctx, pod); err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// createMajorUpgradeJob creates the job upgrading the data directory
+// of the current primary instance
+func (r *ClusterReconciler) createMajorUpgradeJob(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	pgDataName := persistentvolumeclaim.NewPgDataCalculator().GetName(cluster.Status.CurrentPrimary)
+	var primaryPVC *corev1.PersistentVolumeClaim
+	for idx := range resources.pvcs.Items {
+		if resources.pvcs.Items[idx].Name == pgDataName {
+			primaryPVC = &resources.pvcs.Items[idx]
+			break
+		}
+	}
+	if primaryPVC == nil {
+		return fmt.Errorf("cannot find the PVC of the primary instance %q", cluster.Status.CurrentPrimary)
+	}
+
+	nodeSerial, err := specs.GetNodeSerial(primaryPVC.ObjectMeta)
+	if err != nil {
+		return err
+	}
+
+	job := specs.CreateMajorUpgradeJob(*cluster, nodeSerial, cluster.Status.PGDataImageInfo.Image)
+	if err := ctrl.SetControllerReference(cluster, job, r.Scheme); err != nil {
+		return err
+	}
+
+	utils.SetOperatorVersion(&job.ObjectMeta, versions.Version)
+	utils.InheritAnnotations(&job.ObjectMeta, cluster.Annotations,
+		cluster.GetFixedInheritedAnnotations(), configuration.Current)
+	utils.InheritAnnotations(&job.Spec.Template.ObjectMeta, cluster.Annotations,
+		cluster.GetFixedInheritedAnnotations(), configuration.Current)
+	utils.InheritLabels(&job.ObjectMeta, cluster.Labels,
+		cluster.GetFixedInheritedLabels(), configuration.Current)
+	utils.InheritLabels(&job.Spec.Template.ObjectMeta, cluster.Labels,
+		cluster.GetFixedInheritedLabels(), configuration.Current)
+
+	contextLogger.Info("Creating the major upgrade job", "name", job.Name)
+	r.Recorder.Eventf(cluster, "Normal", "MajorUpgrade",
+		"Upgrading the data directory of %s", cluster.Status.CurrentPrimary)
+	if err := r.Create(ctx, job); err != nil {
+		if apierrs.IsAlreadyExists(err) {
+			// This job was already created, maybe the cache is stale
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+// completeMajorUpgrade removes the replicas, which will be cloned again
+// from the upgraded primary, and records the new image of the data
+// directories
+func (r *ClusterReconciler) completeMajorUpgrade(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	job *batchv1.Job,
+	requestedImageInfo apiv1.ImageInfo,
+) (*ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	for _, instanceName := range cluster.Status.InstanceNames {
+		if instanceName == cluster.Status.CurrentPrimary {
+			continue
+		}
+
+		contextLogger.Info("Removing replica not upgraded to the new major version", "instance", instanceName)
+		if err := persistentvolumeclaim.EnsureInstancePVCGroupIsDeleted(
+			ctx,
+			r.Client,
+			cluster,
+			instanceName,
+			cluster.Namespace,
+		); err != nil {
+			return nil, err
+		}
+		if err := r.ensureInstanceJobAreDeleted(ctx, cluster, instanceName); err != nil {
+			return nil, err
+		}
+	}
+
+	origCluster := cluster.DeepCopy()
+	cluster.Status.PGDataImageInfo = &requestedImageInfo
+	if err := r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+		return nil, err
+	}
+
+	if err := r.deleteMajorUpgradeJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	r.Recorder.Eventf(cluster, "Normal", "MajorUpgrade",
+		"Data directory upgraded to major version %d", requestedImageInfo.MajorVersion)
+	return &ctrl.Result{RequeueAfter: time.Second}, ErrNextLoop
+}
+
+// deleteMajorUpgradeJob deletes the passed major upgrade job, together
+// with its pods
+func (r *ClusterReconciler) deleteMajorUpgradeJob(ctx context.Context, job *batchv1.Job) error {
+	foreground := metav1.DeletePropagationForeground
+	err := r.Delete(ctx, job, &client.DeleteOptions{PropagationPolicy: &foreground})
+	if err != nil && !apierrs.IsNotFound(err) {
+		return fmt.Errorf("while deleting the major upgrade job %s: %w", job.Name, err)
+	}
+	return nil
+}
+
+// getMajorUpgradeJob returns the job upgrading the data directory of
+// the current primary instance, or nil if there's none
+func getMajorUpgradeJob(cluster *apiv1.Cluster, resources *managedResources) *batchv1.Job {
+	if cluster.Status.CurrentPrimary == "" {
+		return nil
+	}
+
+	jobName := specs.GetMajorUpgradeJobName(cluster.Status.CurrentPrimary)
+	for idx := range resources.jobs.Items {
+		if resources.jobs.Items[idx].Name == jobName {
+			return &resources.jobs.Items[idx]
+		}
+	}
+	return nil
+}
+
+// getImageInfo returns the information about the passed image, deducing
+// the major version from its tag
+func getImageInfo(image string) (*apiv1.ImageInfo, error) {
+	imageVersion, err := version.FromTag(reference.New(image).Tag)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiv1.ImageInfo{
+		Image:        image,
+		MajorVersion: int(imageVersion.Major()), //nolint:gosec
+	}, nil
+}
@@ -0,0 +1,209 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("major version upgrades", func() {
+	const (
+		oldImage = "ghcr.io/cloudnative-pg/postgresql:16.4"
+		newImage = "ghcr.io/cloudnative-pg/postgresql:17.0"
+	)
+
+	var (
+		env      *testingEnvironment
+		r        *ClusterReconciler
+		recorder *record.FakeRecorder
+		cluster  *apiv1.Cluster
+	)
+
+	BeforeEach(func() {
+		env = buildTestEnvironment()
+		r = env.clusterReconciler
+		recorder = r.Recorder.(*record.FakeRecorder)
+		cluster = newFakeCNPGCluster(env.client, newFakeNamespace(env.client), func(cluster *apiv1.Cluster) {
+			cluster.Spec.ImageName = newImage
+			cluster.Spec.PostgresConfiguration.MajorUpgrade = &apiv1.MajorUpgradeConfiguration{
+				Strategy: apiv1.MajorUpgradeStrategyPgUpgrade,
+			}
+			cluster.Status.Image = newImage
+			cluster.Status.PGDataImageInfo = &apiv1.ImageInfo{Image: oldImage, MajorVersion: 16}
+			cluster.Status.CurrentPrimary = cluster.Name + "-1"
+			cluster.Status.TargetPrimary = cluster.Name + "-1"
+			cluster.Status.InstanceNames = []string{cluster.Name + "-1", cluster.Name + "-2", cluster.Name + "-3"}
+		})
+	})
+
+	getUpdatedCluster := func(ctx SpecContext) *apiv1.Cluster {
+		var updatedCluster apiv1.Cluster
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		return &updatedCluster
+	}
+
+	getUpgradeJob := func(ctx SpecContext) (*batchv1.Job, error) {
+		var job batchv1.Job
+		err := r.Get(ctx, client.ObjectKey{
+			Namespace: cluster.Namespace,
+			Name:      specs.GetMajorUpgradeJobName(cluster.Status.CurrentPrimary),
+		}, &job)
+		return &job, err
+	}
+
+	createUpgradeJob := func(ctx SpecContext, status batchv1.JobStatus) batchv1.Job {
+		job := specs.CreateMajorUpgradeJob(*cluster, 1, oldImage)
+		cluster.SetInheritedDataAndOwnership(&job.ObjectMeta)
+		Expect(r.Create(ctx, job)).To(Succeed())
+		job.Status = status
+		return *job
+	}
+
+	It("records the image of the data directories", func(ctx SpecContext) {
+		cluster.Status.PGDataImageInfo = nil
+
+		res, err := r.reconcileMajorUpgrade(ctx, cluster, &managedResources{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(BeNil())
+		Expect(getUpdatedCluster(ctx).Status.PGDataImageInfo).To(Equal(
+			&apiv1.ImageInfo{Image: newImage, MajorVersion: 17}))
+	})
+
+	It("doesn't upgrade the data directories without the pg_upgrade strategy", func(ctx SpecContext) {
+		cluster.Spec.PostgresConfiguration.MajorUpgrade = nil
+		pods := generateFakeClusterPods(env.client, cluster, true)
+
+		res, err := r.reconcileMajorUpgrade(ctx, cluster, &managedResources{
+			instances: corev1.PodList{Items: pods},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(BeNil())
+		Expect(getUpdatedCluster(ctx).Status.PGDataImageInfo.MajorVersion).To(Equal(17))
+
+		var pod corev1.Pod
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(&pods[0]), &pod)).To(Succeed())
+	})
+
+	It("shuts down every instance before upgrading", func(ctx SpecContext) {
+		pods := generateFakeClusterPods(env.client, cluster, true)
+
+		res, err := r.reconcileMajorUpgrade(ctx, cluster, &managedResources{
+			instances: corev1.PodList{Items: pods},
+		})
+		Expect(err).To(MatchError(ErrNextLoop))
+		Expect(res).ToNot(BeNil())
+		Expect(getUpdatedCluster(ctx).Status.Phase).To(Equal(apiv1.PhaseMajorUpgrade))
+
+		for idx := range pods {
+			var pod corev1.Pod
+			err := r.Get(ctx, client.ObjectKeyFromObject(&pods[idx]), &pod)
+			Expect(apierrs.IsNotFound(err)).To(BeTrue())
+		}
+
+		_, err = getUpgradeJob(ctx)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("upgrades the data directory of the primary once the instances are down", func(ctx SpecContext) {
+		pvcs := generateClusterPVC(env.client, cluster, persistentvolumeclaim.StatusReady)
+
+		res, err := r.reconcileMajorUpgrade(ctx, cluster, &managedResources{
+			pvcs: corev1.PersistentVolumeClaimList{Items: pvcs},
+		})
+		Expect(err).To(MatchError(ErrNextLoop))
+		Expect(res).ToNot(BeNil())
+
+		job, err := getUpgradeJob(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(job.OwnerReferences).To(HaveLen(1))
+		Expect(job.Spec.Template.Spec.InitContainers).To(ContainElement(And(
+			HaveField("Name", specs.MajorUpgradePrepareContainerName),
+			HaveField("Image", oldImage))))
+		Expect(recorder.Events).To(Receive(ContainSubstring("MajorUpgrade")))
+	})
+
+	It("reports the failure of the upgrade job", func(ctx SpecContext) {
+		job := createUpgradeJob(ctx, batchv1.JobStatus{
+			Conditions: []batchv1.JobCondition{
+				{Type: batchv1.JobFailed, Status: corev1.ConditionTrue},
+			},
+		})
+
+		_, err := r.reconcileMajorUpgrade(ctx, cluster, &managedResources{
+			jobs: batchv1.JobList{Items: []batchv1.Job{job}},
+		})
+		Expect(err).To(MatchError(ErrNextLoop))
+
+		updatedCluster := getUpdatedCluster(ctx)
+		Expect(updatedCluster.Status.Phase).To(Equal(apiv1.PhaseMajorUpgradeFailed))
+		Expect(updatedCluster.Status.PhaseReason).To(ContainSubstring(oldImage))
+		Expect(updatedCluster.Status.PGDataImageInfo.MajorVersion).To(Equal(16))
+	})
+
+	It("removes the replicas once the upgrade is done", func(ctx SpecContext) {
+		pvcs := generateClusterPVC(env.client, cluster, persistentvolumeclaim.StatusReady)
+		job := createUpgradeJob(ctx, batchv1.JobStatus{Succeeded: 1})
+
+		_, err := r.reconcileMajorUpgrade(ctx, cluster, &managedResources{
+			pvcs: corev1.PersistentVolumeClaimList{Items: pvcs},
+			jobs: batchv1.JobList{Items: []batchv1.Job{job}},
+		})
+		Expect(err).To(MatchError(ErrNextLoop))
+		Expect(getUpdatedCluster(ctx).Status.PGDataImageInfo).To(Equal(
+			&apiv1.ImageInfo{Image: newImage, MajorVersion: 17}))
+
+		var pvc corev1.PersistentVolumeClaim
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(&pvcs[0]), &pvc)).To(Succeed())
+		for _, replicaPVC := range pvcs[1:] {
+			err := r.Get(ctx, client.ObjectKeyFromObject(&replicaPVC), &pvc)
+			Expect(apierrs.IsNotFound(err)).To(BeTrue())
+		}
+
+		_, err = getUpgradeJob(ctx)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("removes the upgrade job when the previous image is restored", func(ctx SpecContext) {
+		job := createUpgradeJob(ctx, batchv1.JobStatus{
+			Conditions: []batchv1.JobCondition{
+				{Type: batchv1.JobFailed, Status: corev1.ConditionTrue},
+			},
+		})
+		cluster.Spec.ImageName = oldImage
+		cluster.Status.Image = oldImage
+
+		res, err := r.reconcileMajorUpgrade(ctx, cluster, &managedResources{
+			jobs: batchv1.JobList{Items: []batchv1.Job{job}},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(BeNil())
+
+		_, err = getUpgradeJob(ctx)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+})
@@ -20,10 +20,13 @@ import (
 	"context"
 	"crypto/x509"
 	"fmt"
+	"time"
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
+	pgTime "github.com/cloudnative-pg/machinery/pkg/postgres/time"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -176,6 +179,10 @@ func (r *ClusterReconciler) ensureCASecret(ctx context.Context, cluster *apiv1.C
 			return nil, err
 		}
 
+		if err := r.ensureSecretInheritedLabels(ctx, cluster, &secret); err != nil {
+			return nil, err
+		}
+
 		return &secret, nil
 	} else if !apierrors.IsNotFound(err) {
 		return nil, err
@@ -187,7 +194,7 @@ func (r *ClusterReconciler) ensureCASecret(ctx context.Context, cluster *apiv1.C
 	}
 
 	derivedCaSecret := caPair.GenerateCASecret(cluster.Namespace, secretName)
-	utils.SetAsOwnedBy(&derivedCaSecret.ObjectMeta, cluster.ObjectMeta, cluster.TypeMeta)
+	cluster.SetInheritedDataAndOwnership(&derivedCaSecret.ObjectMeta)
 	err = r.Create(ctx, derivedCaSecret)
 
 	return derivedCaSecret, err
@@ -272,7 +279,7 @@ func (r *ClusterReconciler) ensureReplicationClientLeafCertificate(
 
 	// If not specified generate/renew
 	if cluster.Spec.Certificates == nil || cluster.Spec.Certificates.ReplicationTLSSecret == "" {
-		return r.ensureLeafCertificate(
+		if err := r.ensureLeafCertificate(
 			ctx,
 			cluster,
 			replicationSecretName,
@@ -281,7 +288,11 @@ func (r *ClusterReconciler) ensureReplicationClientLeafCertificate(
 			certs.CertTypeClient,
 			nil,
 			nil,
-		)
+		); err != nil {
+			return err
+		}
+
+		return r.reconcileReplicationCredentialsRotation(ctx, cluster, caSecret, replicationSecretName)
 	}
 
 	var replicationClientSecret v1.Secret
@@ -296,6 +307,86 @@ func (r *ClusterReconciler) ensureReplicationClientLeafCertificate(
 	return validateLeafCertificate(caSecret, &replicationClientSecret, opts)
 }
 
+// reconcileReplicationCredentialsRotation replaces the TLS client certificate
+// of the streaming_replica user with a new one when a rotation has been
+// requested or is due. The new certificate is signed by the same client CA,
+// so the replicas can keep streaming while they pick it up.
+func (r *ClusterReconciler) reconcileReplicationCredentialsRotation(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	caSecret *v1.Secret,
+	replicationSecretName client.ObjectKey,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	var secret v1.Secret
+	if err := r.Get(ctx, replicationSecretName, &secret); err != nil {
+		return err
+	}
+
+	reason := getReplicationCredentialsRotationReason(cluster, &secret, time.Now())
+	if reason == "" {
+		return nil
+	}
+
+	contextLogger.Info("Rotating the streaming replication credentials",
+		"secret", secret.Name, "reason", reason)
+
+	newSecret, err := generateCertificateFromCA(
+		caSecret,
+		apiv1.StreamingReplicationUser,
+		certs.CertTypeClient,
+		nil,
+		replicationSecretName,
+	)
+	if err != nil {
+		return err
+	}
+
+	origSecret := secret.DeepCopy()
+	secret.Data = newSecret.Data
+	if err := r.Patch(ctx, &secret, client.MergeFrom(origSecret)); err != nil {
+		return err
+	}
+
+	origCluster := cluster.DeepCopy()
+	cluster.Status.Certificates.ReplicationCredentialsLastRotation = pgTime.GetCurrentTimestamp()
+	if err := r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+		return err
+	}
+
+	r.Recorder.Eventf(cluster, "Normal", "ReplicationCredentialsRotated",
+		"Rotated the streaming replication credentials (%s)", reason)
+	return nil
+}
+
+// getReplicationCredentialsRotationReason returns the reason why the
+// streaming replication credentials need to be rotated, or an empty
+// string if no rotation is needed
+func getReplicationCredentialsRotationReason(cluster *apiv1.Cluster, secret *v1.Secret, now time.Time) string {
+	lastRotation := secret.CreationTimestamp.Time
+	if cluster.Status.Certificates.ReplicationCredentialsLastRotation != "" {
+		parsed, err := time.Parse(metav1.RFC3339Micro, cluster.Status.Certificates.ReplicationCredentialsLastRotation)
+		if err == nil {
+			lastRotation = parsed
+		}
+	}
+
+	if requestedAt, ok := cluster.Annotations[utils.ReplicationCredentialsRotationAnnotationName]; ok {
+		parsed, err := time.Parse(metav1.RFC3339Micro, requestedAt)
+		if err == nil && parsed.After(lastRotation) {
+			return "requested by the user"
+		}
+	}
+
+	if period := cluster.Spec.Replication.GetCredentialsRotationPeriod(); period > 0 &&
+		now.Sub(lastRotation) >= period {
+		return "rotation period expired"
+	}
+
+	return ""
+}
+
 func validateLeafCertificate(caSecret *v1.Secret, serverSecret *v1.Secret, opts *x509.VerifyOptions) error {
 	publicKey, ok := caSecret.Data[certs.CACertKey]
 	if !ok {
@@ -327,6 +418,9 @@ func (r *ClusterReconciler) ensureLeafCertificate(
 	err := r.Get(ctx, secretName, &secret)
 	switch {
 	case err == nil:
+		if err := r.ensureSecretInheritedLabels(ctx, cluster, &secret); err != nil {
+			return err
+		}
 		return r.renewAndUpdateCertificate(ctx, caSecret, &secret, altDNSNames)
 	case apierrors.IsNotFound(err):
 		serverSecret, err := generateCertificateFromCA(caSecret, commonName, usage, altDNSNames, secretName)
@@ -334,7 +428,7 @@ func (r *ClusterReconciler) ensureLeafCertificate(
 			return err
 		}
 
-		utils.SetAsOwnedBy(&serverSecret.ObjectMeta, cluster.ObjectMeta, cluster.TypeMeta)
+		cluster.SetInheritedDataAndOwnership(&serverSecret.ObjectMeta)
 		for k, v := range additionalLabels {
 			if serverSecret.Labels == nil {
 				serverSecret.Labels = make(map[string]string)
@@ -347,6 +441,32 @@ func (r *ClusterReconciler) ensureLeafCertificate(
 	}
 }
 
+// ensureSecretInheritedLabels adds the labels required by `.spec.inheritedMetadata`
+// to a certificate secret previously generated by the operator. Secrets
+// provided by the user are left untouched
+func (r *ClusterReconciler) ensureSecretInheritedLabels(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	secret *v1.Secret,
+) error {
+	if owner, ok := IsOwnedByCluster(secret); !ok || owner != cluster.Name {
+		return nil
+	}
+
+	labels := cluster.GetFixedInheritedLabels()
+	if utils.IsMapSubset(secret.Labels, labels) {
+		return nil
+	}
+
+	origSecret := secret.DeepCopy()
+	if secret.Labels == nil {
+		secret.Labels = make(map[string]string, len(labels))
+	}
+	utils.MergeMap(secret.Labels, labels)
+
+	return r.Patch(ctx, secret, client.MergeFrom(origSecret))
+}
+
 // generateCertificateFromCA create a certificate secret using the provided CA secret
 func generateCertificateFromCA(
 	caSecret *v1.Secret,
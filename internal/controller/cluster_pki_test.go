@@ -0,0 +1,128 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"crypto/x509"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("streaming replication credentials rotation", func() {
+	var (
+		env      *testingEnvironment
+		cluster  *apiv1.Cluster
+		caSecret *corev1.Secret
+	)
+
+	BeforeEach(func(ctx SpecContext) {
+		env = buildTestEnvironment()
+		namespace := newFakeNamespace(env.client)
+		cluster = newFakeCNPGCluster(env.client, namespace)
+
+		ca, err := certs.CreateRootCA(cluster.Name, namespace)
+		Expect(err).ToNot(HaveOccurred())
+		caSecret = ca.GenerateCASecret(namespace, cluster.GetClientCASecretName())
+		Expect(env.client.Create(ctx, caSecret)).To(Succeed())
+
+		Expect(env.clusterReconciler.ensureReplicationClientLeafCertificate(ctx, cluster, caSecret)).
+			To(Succeed())
+	})
+
+	getReplicationSecret := func(ctx SpecContext) *corev1.Secret {
+		var secret corev1.Secret
+		Expect(env.client.Get(ctx,
+			client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.GetReplicationSecretName()},
+			&secret)).To(Succeed())
+		return &secret
+	}
+
+	// replicationKeepsWorking checks that the given secret can still be used by
+	// the replicas to authenticate against the primary
+	replicationKeepsWorking := func(secret *corev1.Secret) {
+		opts := &x509.VerifyOptions{KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}
+		Expect(validateLeafCertificate(caSecret, secret, opts)).To(Succeed())
+
+		pair, err := certs.ParseServerSecret(secret)
+		Expect(err).ToNot(HaveOccurred())
+		certificate, err := pair.ParseCertificate()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(certificate.Subject.CommonName).To(Equal(apiv1.StreamingReplicationUser))
+	}
+
+	It("doesn't rotate the credentials when not requested", func(ctx SpecContext) {
+		before := getReplicationSecret(ctx)
+
+		Expect(env.clusterReconciler.ensureReplicationClientLeafCertificate(ctx, cluster, caSecret)).
+			To(Succeed())
+
+		Expect(getReplicationSecret(ctx).Data).To(Equal(before.Data))
+		Expect(cluster.Status.Certificates.ReplicationCredentialsLastRotation).To(BeEmpty())
+	})
+
+	It("rotates the credentials on request, keeping replication working", func(ctx SpecContext) {
+		before := getReplicationSecret(ctx)
+		replicationKeepsWorking(before)
+
+		cluster.Annotations = map[string]string{
+			utils.ReplicationCredentialsRotationAnnotationName: time.Now().Add(time.Second).Format(metav1.RFC3339Micro),
+		}
+		Expect(env.clusterReconciler.ensureReplicationClientLeafCertificate(ctx, cluster, caSecret)).
+			To(Succeed())
+
+		after := getReplicationSecret(ctx)
+		Expect(after.Data[corev1.TLSPrivateKeyKey]).ToNot(Equal(before.Data[corev1.TLSPrivateKeyKey]))
+		Expect(after.Data[corev1.TLSCertKey]).ToNot(Equal(before.Data[corev1.TLSCertKey]))
+		replicationKeepsWorking(after)
+
+		var updatedCluster apiv1.Cluster
+		Expect(env.client.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		Expect(updatedCluster.Status.Certificates.ReplicationCredentialsLastRotation).ToNot(BeEmpty())
+	})
+
+	It("rotates the credentials when the rotation period expires", func(ctx SpecContext) {
+		before := getReplicationSecret(ctx)
+
+		cluster.Spec.Replication = &apiv1.ReplicationConfiguration{
+			CredentialsRotationPeriod: &metav1.Duration{Duration: time.Hour},
+		}
+		cluster.Status.Certificates.ReplicationCredentialsLastRotation =
+			time.Now().Add(-2 * time.Hour).Format(metav1.RFC3339Micro)
+		Expect(env.clusterReconciler.ensureReplicationClientLeafCertificate(ctx, cluster, caSecret)).
+			To(Succeed())
+
+		after := getReplicationSecret(ctx)
+		Expect(after.Data[corev1.TLSPrivateKeyKey]).ToNot(Equal(before.Data[corev1.TLSPrivateKeyKey]))
+		replicationKeepsWorking(after)
+
+		By("not rotating them again before the next period", func() {
+			Expect(env.clusterReconciler.ensureReplicationClientLeafCertificate(ctx, cluster, caSecret)).
+				To(Succeed())
+			Expect(getReplicationSecret(ctx).Data).To(Equal(after.Data))
+		})
+	})
+})
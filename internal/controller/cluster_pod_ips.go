@@ -0,0 +1,115 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// jobPodsPredicate filters the events of the pods created by the jobs
+// of a cluster, keeping only the ones changing their IP address
+var jobPodsPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldPod, oldOk := e.ObjectOld.(*corev1.Pod)
+		newPod, newOk := e.ObjectNew.(*corev1.Pod)
+		return oldOk && newOk && isJobPod(newPod) && oldPod.Status.PodIP != newPod.Status.PodIP
+	},
+	CreateFunc: func(_ event.CreateEvent) bool {
+		return false
+	},
+	DeleteFunc: func(_ event.DeleteEvent) bool {
+		return false
+	},
+	GenericFunc: func(_ event.GenericEvent) bool {
+		return false
+	},
+}
+
+// isJobPod checks if the passed pod has been created by one of
+// the jobs of a cluster
+func isJobPod(pod *corev1.Pod) bool {
+	_, hasJobRole := pod.Labels[utils.JobRoleLabelName]
+	_, hasCluster := pod.Labels[utils.ClusterLabelName]
+	return hasJobRole && hasCluster
+}
+
+// mapJobPodsToClusters returns a function mapping the pods created by
+// the jobs of a cluster to the cluster itself
+func (r *ClusterReconciler) mapJobPodsToClusters() handler.MapFunc {
+	return func(_ context.Context, obj client.Object) []reconcile.Request {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok || !isJobPod(pod) {
+			return nil
+		}
+
+		return []reconcile.Request{
+			{
+				NamespacedName: types.NamespacedName{
+					Name:      pod.Labels[utils.ClusterLabelName],
+					Namespace: pod.Namespace,
+				},
+			},
+		}
+	}
+}
+
+// getPodIPs returns the sorted IP addresses of the instances of the
+// cluster and of the pods of its running jobs, which the instances
+// accept replication connections from when the replication `hbaScope`
+// is `instances`. Nil is returned for any other scope.
+func (r *ClusterReconciler) getPodIPs(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instances []corev1.Pod,
+) ([]string, error) {
+	if cluster.Spec.Replication.GetHBAScope() != apiv1.ReplicationHBAScopeInstances {
+		return nil, nil
+	}
+
+	var jobPods corev1.PodList
+	if err := r.List(ctx, &jobPods,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{utils.ClusterLabelName: cluster.Name},
+		client.HasLabels{utils.JobRoleLabelName},
+	); err != nil {
+		return nil, err
+	}
+
+	podIPs := make([]string, 0, len(instances)+len(jobPods.Items))
+	for _, pod := range append(slices.Clone(instances), jobPods.Items...) {
+		if pod.Status.PodIP == "" ||
+			pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		podIPs = append(podIPs, pod.Status.PodIP)
+	}
+
+	slices.Sort(podIPs)
+	return slices.Compact(podIPs), nil
+}
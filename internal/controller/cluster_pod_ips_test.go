@@ -0,0 +1,103 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pod IPs tracking", func() {
+	var (
+		env       *testingEnvironment
+		namespace string
+		cluster   *apiv1.Cluster
+	)
+
+	newJobPod := func(name, podIP string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels: map[string]string{
+					utils.ClusterLabelName: cluster.Name,
+					utils.JobRoleLabelName: "join",
+				},
+			},
+			Status: corev1.PodStatus{PodIP: podIP, Phase: phase},
+		}
+	}
+
+	BeforeEach(func() {
+		env = buildTestEnvironment()
+		namespace = newFakeNamespace(env.client)
+		cluster = newFakeCNPGCluster(env.client, namespace, func(cluster *apiv1.Cluster) {
+			cluster.Spec.Replication = &apiv1.ReplicationConfiguration{
+				HBAScope: apiv1.ReplicationHBAScopeInstances,
+			}
+		})
+	})
+
+	It("is disabled unless the replication rules are scoped to the instances", func(ctx SpecContext) {
+		cluster.Spec.Replication = nil
+		instances := generateFakeClusterPods(env.client, cluster, true)
+		Expect(env.clusterReconciler.getPodIPs(ctx, cluster, instances)).To(BeNil())
+	})
+
+	It("collects the IPs of the instances and of the running jobs", func(ctx SpecContext) {
+		instances := generateFakeClusterPods(env.client, cluster, true)
+		instances[0].Status.PodIP = "10.244.0.12"
+		instances[1].Status.PodIP = "10.244.0.10"
+
+		Expect(env.client.Create(ctx, newJobPod("running-job", "10.244.0.20", corev1.PodRunning))).To(Succeed())
+		Expect(env.client.Create(ctx, newJobPod("failed-job", "10.244.0.21", corev1.PodFailed))).To(Succeed())
+
+		podIPs, err := env.clusterReconciler.getPodIPs(ctx, cluster, instances)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podIPs).To(Equal([]string{"10.244.0.10", "10.244.0.12", "10.244.0.20"}))
+
+		By("following an instance rescheduled with a new IP", func() {
+			instances[0].Status.PodIP = "10.244.3.4"
+			podIPs, err := env.clusterReconciler.getPodIPs(ctx, cluster, instances)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(podIPs).To(Equal([]string{"10.244.0.10", "10.244.0.20", "10.244.3.4"}))
+		})
+	})
+
+	It("reconciles the cluster when the IP of a job pod changes", func() {
+		oldPod := newJobPod("join-job", "", corev1.PodPending)
+		newPod := oldPod.DeepCopy()
+		newPod.Status.PodIP = "10.244.0.20"
+
+		Expect(jobPodsPredicate.Update(event.UpdateEvent{ObjectOld: oldPod, ObjectNew: newPod})).To(BeTrue())
+		Expect(jobPodsPredicate.Update(event.UpdateEvent{ObjectOld: newPod, ObjectNew: newPod})).To(BeFalse())
+
+		requests := env.clusterReconciler.mapJobPodsToClusters()(context.Background(), newPod)
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].Name).To(Equal(cluster.Name))
+		Expect(requests[0].Namespace).To(Equal(namespace))
+	})
+})
@@ -0,0 +1,116 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// reconcilePreparedTransactions updates the StalePreparedTransactions
+// condition. Stale prepared transactions hold back the xmin horizon,
+// preventing vacuum from doing its job, and the operator can't know if they
+// are still needed by the transaction manager. For this reason, they are
+// only reported and never rolled back.
+func (r *ClusterReconciler) reconcilePreparedTransactions(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	threshold := cluster.Spec.Monitoring.GetStalePreparedTransactionsAge()
+	condition := getPreparedTransactionsCondition(instancesStatus, threshold)
+	if condition == nil {
+		return nil
+	}
+
+	if condition.Status == metav1.ConditionTrue &&
+		!meta.IsStatusConditionTrue(cluster.Status.Conditions, condition.Type) {
+		contextLogger.Warning("Stale prepared transactions detected",
+			"message", condition.Message,
+			"preparedTransactions", getPreparedTransactionsDetails(instancesStatus))
+		r.Recorder.Event(cluster, "Warning", condition.Reason, condition.Message)
+	}
+
+	return conditions.Patch(ctx, r.Client, cluster, condition)
+}
+
+// getPreparedTransactionsCondition computes the StalePreparedTransactions
+// condition from the status reported by the primary instance, returning nil
+// when the primary didn't report its status. The message doesn't contain
+// the number and the age of the prepared transactions, which change at
+// every status request, to avoid updating the condition continuously
+func getPreparedTransactionsCondition(
+	instancesStatus postgres.PostgresqlStatusList,
+	threshold time.Duration,
+) *metav1.Condition {
+	for _, status := range instancesStatus.Items {
+		if !status.IsPrimary || status.Error != nil {
+			continue
+		}
+
+		oldestAge := time.Duration(status.OldestPreparedTransactionAge) * time.Second
+		if status.PreparedTransactions > 0 && oldestAge > threshold {
+			return &metav1.Condition{
+				Type:   string(apiv1.ConditionStalePreparedTransactions),
+				Status: metav1.ConditionTrue,
+				Reason: string(apiv1.ConditionReasonPreparedTransactionsTooOld),
+				Message: fmt.Sprintf(
+					"Prepared transactions older than %s found on %s. "+
+						"Prepared transactions prevent vacuum from removing dead rows and "+
+						"need to be committed or rolled back by the transaction manager",
+					threshold, status.Pod.Name),
+			}
+		}
+
+		return &metav1.Condition{
+			Type:    string(apiv1.ConditionStalePreparedTransactions),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(apiv1.ConditionReasonNoStalePreparedTransactions),
+			Message: fmt.Sprintf("No prepared transaction older than %s", threshold),
+		}
+	}
+
+	return nil
+}
+
+// getPreparedTransactionsDetails returns the number of prepared transactions
+// on the primary and the age of the oldest one, to be logged
+func getPreparedTransactionsDetails(instancesStatus postgres.PostgresqlStatusList) map[string]interface{} {
+	for _, status := range instancesStatus.Items {
+		if !status.IsPrimary || status.Error != nil {
+			continue
+		}
+
+		return map[string]interface{}{
+			"count":     status.PreparedTransactions,
+			"oldestAge": (time.Duration(status.OldestPreparedTransactionAge) * time.Second).String(),
+		}
+	}
+
+	return nil
+}
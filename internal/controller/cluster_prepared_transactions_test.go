@@ -0,0 +1,118 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("stale prepared transactions", func() {
+	var (
+		env     *testingEnvironment
+		cluster *apiv1.Cluster
+	)
+
+	BeforeEach(func() {
+		env = buildTestEnvironment()
+		cluster = newFakeCNPGCluster(env.client, newFakeNamespace(env.client))
+	})
+
+	primaryStatus := func(preparedTransactions int, oldestAge int64) postgres.PostgresqlStatusList {
+		return postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{
+					Pod:                          &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: cluster.Name + "-1"}},
+					IsPrimary:                    true,
+					PreparedTransactions:         preparedTransactions,
+					OldestPreparedTransactionAge: oldestAge,
+				},
+				{
+					Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: cluster.Name + "-2"}},
+				},
+			},
+		}
+	}
+
+	getCondition := func(ctx SpecContext) *metav1.Condition {
+		var updatedCluster apiv1.Cluster
+		Expect(env.client.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		return meta.FindStatusCondition(updatedCluster.Status.Conditions,
+			string(apiv1.ConditionStalePreparedTransactions))
+	}
+
+	It("raises the condition and a warning when a prepared transaction is old", func(ctx SpecContext) {
+		Expect(env.clusterReconciler.reconcilePreparedTransactions(ctx, cluster, primaryStatus(1, 3600))).
+			To(Succeed())
+
+		condition := getCondition(ctx)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonPreparedTransactionsTooOld)))
+
+		recorder := env.clusterReconciler.Recorder.(*record.FakeRecorder)
+		Expect(recorder.Events).To(Receive(ContainSubstring(string(apiv1.ConditionReasonPreparedTransactionsTooOld))))
+	})
+
+	It("keeps the same condition while the prepared transactions age", func(ctx SpecContext) {
+		Expect(env.clusterReconciler.reconcilePreparedTransactions(ctx, cluster, primaryStatus(1, 3600))).
+			To(Succeed())
+		firstCondition := getCondition(ctx)
+
+		recorder := env.clusterReconciler.Recorder.(*record.FakeRecorder)
+		Expect(recorder.Events).To(Receive())
+
+		Expect(env.clusterReconciler.reconcilePreparedTransactions(ctx, cluster, primaryStatus(2, 3700))).
+			To(Succeed())
+		Expect(getCondition(ctx)).To(Equal(firstCondition))
+		Expect(recorder.Events).ToNot(Receive())
+	})
+
+	It("uses the configured age threshold", func(ctx SpecContext) {
+		cluster.Spec.Monitoring = &apiv1.MonitoringConfiguration{StalePreparedTransactionsAge: 60}
+		Expect(env.clusterReconciler.reconcilePreparedTransactions(ctx, cluster, primaryStatus(1, 120))).
+			To(Succeed())
+
+		condition := getCondition(ctx)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Message).To(ContainSubstring("older than 1m0s"))
+	})
+
+	It("doesn't raise the condition for recent prepared transactions", func(ctx SpecContext) {
+		Expect(env.clusterReconciler.reconcilePreparedTransactions(ctx, cluster, primaryStatus(1, 10))).
+			To(Succeed())
+
+		condition := getCondition(ctx)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonNoStalePreparedTransactions)))
+	})
+
+	It("leaves the condition untouched when the primary didn't report its status", func(ctx SpecContext) {
+		status := primaryStatus(1, 3600)
+		status.Items[0].IsPrimary = false
+		Expect(env.clusterReconciler.reconcilePreparedTransactions(ctx, cluster, status)).To(Succeed())
+		Expect(getCondition(ctx)).To(BeNil())
+	})
+})
@@ -0,0 +1,189 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+var promotableReplicas = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "cnpg",
+	Name:      "promotable_replicas",
+	Help: "Number of replicas that are healthy, streaming and within " +
+		"the configured lag from the primary, and could be promoted",
+}, []string{"namespace", "cluster"})
+
+func init() {
+	metrics.Registry.MustRegister(promotableReplicas)
+}
+
+// reconcilePromotableReplicas updates the NoPromotableReplica condition and
+// the promotable replicas metric. A replica being just streaming is not
+// enough for a failover to succeed: this is a leading indicator of a
+// failover that would fail or lose data.
+func (r *ClusterReconciler) reconcilePromotableReplicas(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	if cluster.Spec.PromotableReplicaMaxLagBytes == nil {
+		deletePromotableReplicasMetrics(client.ObjectKeyFromObject(cluster))
+		return removePromotableReplicaCondition(ctx, r.Client, cluster)
+	}
+
+	primary := getPrimaryStatus(instancesStatus)
+	if primary == nil {
+		// Without the WAL position of the primary we can't
+		// tell if the replicas are caught up
+		return nil
+	}
+
+	promotable, blocked := getPromotableReplicas(instancesStatus, primary, *cluster.Spec.PromotableReplicaMaxLagBytes)
+	promotableReplicas.WithLabelValues(cluster.Namespace, cluster.Name).Set(float64(len(promotable)))
+
+	condition := getPromotableReplicaCondition(cluster, promotable, blocked)
+	if condition.Status == metav1.ConditionTrue &&
+		!meta.IsStatusConditionTrue(cluster.Status.Conditions, condition.Type) {
+		contextLogger.Warning("No replica can be promoted",
+			"message", condition.Message)
+		r.Recorder.Event(cluster, "Warning", condition.Reason, condition.Message)
+	}
+
+	return conditions.Patch(ctx, r.Client, cluster, condition)
+}
+
+// getPromotableReplicas divides the replicas between the ones that could
+// be promoted and the ones that couldn't, for which the reason is returned
+func getPromotableReplicas(
+	instancesStatus postgres.PostgresqlStatusList,
+	primary *postgres.PostgresqlStatus,
+	maxLagBytes int64,
+) (promotable []string, blocked []string) {
+	for idx := range instancesStatus.Items {
+		item := &instancesStatus.Items[idx]
+		if item.Pod == nil || item.IsPrimary {
+			continue
+		}
+
+		reason := getNotPromotableReason(primary, item, maxLagBytes)
+		if reason == "" {
+			promotable = append(promotable, item.Pod.Name)
+		} else {
+			blocked = append(blocked, fmt.Sprintf("%s (%s)", item.Pod.Name, reason))
+		}
+	}
+
+	return promotable, blocked
+}
+
+// getNotPromotableReason explains why a replica couldn't be promoted,
+// returning an empty string when the replica is promotable
+func getNotPromotableReason(
+	primary *postgres.PostgresqlStatus,
+	replica *postgres.PostgresqlStatus,
+	maxLagBytes int64,
+) string {
+	switch {
+	case replica.Error != nil:
+		return "not reporting its status"
+	case !replica.IsPodReady:
+		return "not ready"
+	case replica.IsPgRewindRunning:
+		return "running pg_rewind"
+	case !replica.IsWalReceiverActive:
+		return "not streaming"
+	case replica.ReplayPaused:
+		return "replay paused"
+	}
+
+	lag, err := getSwitchoverCandidateLag(primary, replica)
+	if err != nil {
+		return "replay position unknown"
+	}
+	if lag > maxLagBytes {
+		return "lagging more than the promotableReplicaMaxLagBytes limit"
+	}
+
+	return ""
+}
+
+// getPromotableReplicaCondition computes the NoPromotableReplica condition,
+// that is raised when a highly available cluster has no promotable replica
+func getPromotableReplicaCondition(
+	cluster *apiv1.Cluster,
+	promotable []string,
+	blocked []string,
+) *metav1.Condition {
+	condition := &metav1.Condition{
+		Type:   string(apiv1.ConditionNoPromotableReplica),
+		Status: metav1.ConditionFalse,
+		Reason: string(apiv1.ConditionReasonPromotableReplicasAvailable),
+	}
+
+	switch {
+	case cluster.Spec.Instances < 2:
+		condition.Reason = string(apiv1.ConditionReasonSingleInstanceCluster)
+		condition.Message = "The cluster has been requested to run a single instance"
+
+	case len(promotable) == 0:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = string(apiv1.ConditionReasonNoReplicaPromotable)
+		condition.Message = "No replica could be promoted in case of failover"
+		if len(blocked) > 0 {
+			condition.Message += ": " + strings.Join(blocked, ", ")
+		}
+
+	default:
+		condition.Message = fmt.Sprintf("Promotable replicas: %s", strings.Join(promotable, ", "))
+	}
+
+	return condition
+}
+
+// removePromotableReplicaCondition removes the NoPromotableReplica
+// condition from a cluster where the check is not requested
+func removePromotableReplicaCondition(ctx context.Context, c client.Client, cluster *apiv1.Cluster) error {
+	if meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionNoPromotableReplica)) == nil {
+		return nil
+	}
+
+	origCluster := cluster.DeepCopy()
+	meta.RemoveStatusCondition(&cluster.Status.Conditions, string(apiv1.ConditionNoPromotableReplica))
+	return c.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// deletePromotableReplicasMetrics removes the promotable replicas
+// metric of a cluster
+func deletePromotableReplicasMetrics(cluster types.NamespacedName) {
+	promotableReplicas.DeleteLabelValues(cluster.Namespace, cluster.Name)
+}
@@ -0,0 +1,172 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/cloudnative-pg/machinery/pkg/types"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("promotable replicas check", func() {
+	var (
+		env     *testingEnvironment
+		cluster *apiv1.Cluster
+	)
+
+	BeforeEach(func() {
+		env = buildTestEnvironment()
+		cluster = newFakeCNPGCluster(env.client, newFakeNamespace(env.client), func(cluster *apiv1.Cluster) {
+			cluster.Spec.PromotableReplicaMaxLagBytes = ptr.To(int64(1024))
+		})
+	})
+
+	AfterEach(func() {
+		deletePromotableReplicasMetrics(client.ObjectKeyFromObject(cluster))
+	})
+
+	instanceStatus := func(name string, replayLSN types.LSN) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod:                 &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}},
+			IsPodReady:          true,
+			IsWalReceiverActive: true,
+			ReplayLsn:           replayLSN,
+		}
+	}
+
+	// instancesStatus builds the status of a primary at the 0/3000000 WAL
+	// position and of two replicas at the passed replay positions
+	instancesStatus := func(replayLSNs ...types.LSN) postgres.PostgresqlStatusList {
+		primary := postgres.PostgresqlStatus{
+			Pod:        &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: cluster.Name + "-1"}},
+			IsPrimary:  true,
+			IsPodReady: true,
+			CurrentLsn: "0/3000000",
+		}
+		return postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			primary,
+			instanceStatus(cluster.Name+"-2", replayLSNs[0]),
+			instanceStatus(cluster.Name+"-3", replayLSNs[1]),
+		}}
+	}
+
+	getCondition := func(ctx SpecContext) *metav1.Condition {
+		var updatedCluster apiv1.Cluster
+		Expect(env.client.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		return meta.FindStatusCondition(updatedCluster.Status.Conditions,
+			string(apiv1.ConditionNoPromotableReplica))
+	}
+
+	It("counts the replicas caught up with the primary", func(ctx SpecContext) {
+		status := instancesStatus("0/3000000", "0/2000000")
+		Expect(env.clusterReconciler.reconcilePromotableReplicas(ctx, cluster, status)).To(Succeed())
+
+		condition := getCondition(ctx)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonPromotableReplicasAvailable)))
+		Expect(condition.Message).To(ContainSubstring(cluster.Name + "-2"))
+		Expect(condition.Message).ToNot(ContainSubstring(cluster.Name + "-3"))
+
+		Expect(testutil.ToFloat64(promotableReplicas.WithLabelValues(
+			cluster.Namespace, cluster.Name))).To(BeEquivalentTo(1))
+	})
+
+	It("raises the condition when every replica lags beyond the threshold", func(ctx SpecContext) {
+		status := instancesStatus("0/2000000", "0/2FFF000")
+		Expect(env.clusterReconciler.reconcilePromotableReplicas(ctx, cluster, status)).To(Succeed())
+
+		condition := getCondition(ctx)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonNoReplicaPromotable)))
+		Expect(condition.Message).To(ContainSubstring(
+			cluster.Name + "-2 (lagging more than the promotableReplicaMaxLagBytes limit)"))
+		Expect(condition.Message).To(ContainSubstring(
+			cluster.Name + "-3 (lagging more than the promotableReplicaMaxLagBytes limit)"))
+
+		recorder := env.clusterReconciler.Recorder.(*record.FakeRecorder)
+		Expect(recorder.Events).To(Receive(ContainSubstring(string(apiv1.ConditionReasonNoReplicaPromotable))))
+
+		Expect(testutil.ToFloat64(promotableReplicas.WithLabelValues(
+			cluster.Namespace, cluster.Name))).To(BeEquivalentTo(0))
+	})
+
+	It("keeps the condition stable while the lag changes", func(ctx SpecContext) {
+		Expect(env.clusterReconciler.reconcilePromotableReplicas(ctx, cluster,
+			instancesStatus("0/2000000", "0/2FFF000"))).To(Succeed())
+		condition := getCondition(ctx)
+
+		Expect(env.clusterReconciler.reconcilePromotableReplicas(ctx, cluster,
+			instancesStatus("0/1000000", "0/2FF0000"))).To(Succeed())
+		updatedCondition := getCondition(ctx)
+		Expect(updatedCondition.Message).To(Equal(condition.Message))
+		Expect(updatedCondition.LastTransitionTime).To(Equal(condition.LastTransitionTime))
+	})
+
+	It("doesn't consider promotable the replicas that are not streaming or paused", func(ctx SpecContext) {
+		status := instancesStatus("0/3000000", "0/3000000")
+		status.Items[1].IsWalReceiverActive = false
+		status.Items[2].ReplayPaused = true
+		Expect(env.clusterReconciler.reconcilePromotableReplicas(ctx, cluster, status)).To(Succeed())
+
+		condition := getCondition(ctx)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Message).To(ContainSubstring(cluster.Name + "-2 (not streaming)"))
+		Expect(condition.Message).To(ContainSubstring(cluster.Name + "-3 (replay paused)"))
+	})
+
+	It("doesn't raise the condition in a single instance cluster", func(ctx SpecContext) {
+		cluster.Spec.Instances = 1
+		status := instancesStatus("0/3000000", "0/3000000")
+		status.Items = status.Items[:1]
+		Expect(env.clusterReconciler.reconcilePromotableReplicas(ctx, cluster, status)).To(Succeed())
+
+		condition := getCondition(ctx)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonSingleInstanceCluster)))
+	})
+
+	It("leaves the condition untouched when the primary didn't report its status", func(ctx SpecContext) {
+		status := instancesStatus("0/2000000", "0/2000000")
+		status.Items[0].CurrentLsn = ""
+		Expect(env.clusterReconciler.reconcilePromotableReplicas(ctx, cluster, status)).To(Succeed())
+		Expect(getCondition(ctx)).To(BeNil())
+	})
+
+	It("removes the condition when the check is disabled", func(ctx SpecContext) {
+		Expect(env.clusterReconciler.reconcilePromotableReplicas(ctx, cluster,
+			instancesStatus("0/2000000", "0/2000000"))).To(Succeed())
+		Expect(getCondition(ctx)).ToNot(BeNil())
+
+		var updatedCluster apiv1.Cluster
+		Expect(env.client.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		updatedCluster.Spec.PromotableReplicaMaxLagBytes = nil
+		Expect(env.clusterReconciler.reconcilePromotableReplicas(ctx, &updatedCluster,
+			instancesStatus("0/2000000", "0/2000000"))).To(Succeed())
+		Expect(getCondition(ctx)).To(BeNil())
+	})
+})
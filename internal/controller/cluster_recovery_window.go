@@ -0,0 +1,114 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+)
+
+// reconcileRecoveryWindow updates the RecoveryWindowTooShort condition,
+// comparing the recovery window of the cluster with the requested minimum
+func (r *ClusterReconciler) reconcileRecoveryWindow(ctx context.Context, cluster *apiv1.Cluster) error {
+	contextLogger := log.FromContext(ctx)
+
+	minRecoveryWindow := cluster.Spec.Backup.GetMinRecoveryWindow()
+	if minRecoveryWindow == 0 {
+		return removeRecoveryWindowCondition(ctx, r.Client, cluster)
+	}
+
+	condition := getRecoveryWindowCondition(cluster, minRecoveryWindow, time.Now())
+	if condition.Status == metav1.ConditionTrue &&
+		!meta.IsStatusConditionTrue(cluster.Status.Conditions, condition.Type) {
+		contextLogger.Warning("Recovery window shorter than requested",
+			"firstRecoverabilityPoint", cluster.Status.FirstRecoverabilityPoint,
+			"recoveryWindow", getRecoveryWindow(cluster, time.Now()),
+			"minRecoveryWindow", cluster.Spec.Backup.MinRecoveryWindow)
+		r.Recorder.Event(cluster, "Warning", condition.Reason, condition.Message)
+	}
+
+	return conditions.Patch(ctx, r.Client, cluster, condition)
+}
+
+// getRecoveryWindowCondition computes the RecoveryWindowTooShort condition
+// given the requested minimum recovery window and the current time
+func getRecoveryWindowCondition(
+	cluster *apiv1.Cluster,
+	minRecoveryWindow time.Duration,
+	now time.Time,
+) *metav1.Condition {
+	condition := &metav1.Condition{
+		Type:   string(apiv1.ConditionRecoveryWindowTooShort),
+		Status: metav1.ConditionFalse,
+		Reason: string(apiv1.ConditionReasonRecoveryWindowSufficient),
+	}
+
+	// A cluster without backups has no recovery window to be measured
+	firstRecoverabilityPoint, err := time.Parse(time.RFC3339, cluster.Status.FirstRecoverabilityPoint)
+	if err != nil {
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = string(apiv1.ConditionReasonNoRecoverabilityPoint)
+		condition.Message = "No backup is available yet"
+		return condition
+	}
+
+	// The message doesn't include the current recovery window, which
+	// changes at every reconciliation loop and is exported as a metric
+	if now.Sub(firstRecoverabilityPoint) < minRecoveryWindow {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = string(apiv1.ConditionReasonRecoveryWindowBelowMinimum)
+		condition.Message = fmt.Sprintf("The recovery window is shorter than the requested %s",
+			cluster.Spec.Backup.MinRecoveryWindow)
+		return condition
+	}
+
+	condition.Message = fmt.Sprintf("The recovery window is not shorter than the requested %s",
+		cluster.Spec.Backup.MinRecoveryWindow)
+	return condition
+}
+
+// getRecoveryWindow returns the current recovery window of the cluster,
+// truncated to the second, or zero when no backup is available yet
+func getRecoveryWindow(cluster *apiv1.Cluster, now time.Time) time.Duration {
+	firstRecoverabilityPoint, err := time.Parse(time.RFC3339, cluster.Status.FirstRecoverabilityPoint)
+	if err != nil {
+		return 0
+	}
+
+	return now.Sub(firstRecoverabilityPoint).Truncate(time.Second)
+}
+
+// removeRecoveryWindowCondition removes the RecoveryWindowTooShort
+// condition from a cluster where no minimum recovery window is requested
+func removeRecoveryWindowCondition(ctx context.Context, c client.Client, cluster *apiv1.Cluster) error {
+	if meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionRecoveryWindowTooShort)) == nil {
+		return nil
+	}
+
+	origCluster := cluster.DeepCopy()
+	meta.RemoveStatusCondition(&cluster.Status.Conditions, string(apiv1.ConditionRecoveryWindowTooShort))
+	return c.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
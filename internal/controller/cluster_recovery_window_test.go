@@ -0,0 +1,102 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("recovery window", func() {
+	var (
+		env     *testingEnvironment
+		cluster *apiv1.Cluster
+	)
+
+	BeforeEach(func(ctx SpecContext) {
+		env = buildTestEnvironment()
+		cluster = newFakeCNPGCluster(env.client, newFakeNamespace(env.client))
+		cluster.Spec.Backup = &apiv1.BackupConfiguration{
+			MinRecoveryWindow: "7d",
+		}
+		Expect(env.client.Update(ctx, cluster)).To(Succeed())
+	})
+
+	getCondition := func(ctx SpecContext) *metav1.Condition {
+		var updatedCluster apiv1.Cluster
+		Expect(env.client.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		return meta.FindStatusCondition(updatedCluster.Status.Conditions,
+			string(apiv1.ConditionRecoveryWindowTooShort))
+	}
+
+	setFirstRecoverabilityPoint := func(ctx SpecContext, firstRecoverabilityPoint string) {
+		cluster.Status.FirstRecoverabilityPoint = firstRecoverabilityPoint
+		Expect(env.clusterReconciler.reconcileRecoveryWindow(ctx, cluster)).To(Succeed())
+	}
+
+	It("reports an unknown status for a cluster without backups", func(ctx SpecContext) {
+		setFirstRecoverabilityPoint(ctx, "")
+
+		condition := getCondition(ctx)
+		Expect(condition.Status).To(Equal(metav1.ConditionUnknown))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonNoRecoverabilityPoint)))
+	})
+
+	It("raises the condition when the recovery window is too short and clears it later", func(ctx SpecContext) {
+		By("having a recovery window of one day", func() {
+			setFirstRecoverabilityPoint(ctx, time.Now().Add(-24*time.Hour).Format(time.RFC3339))
+			condition := getCondition(ctx)
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonRecoveryWindowBelowMinimum)))
+		})
+
+		By("having a recovery window of eight days", func() {
+			setFirstRecoverabilityPoint(ctx, time.Now().Add(-8*24*time.Hour).Format(time.RFC3339))
+			condition := getCondition(ctx)
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonRecoveryWindowSufficient)))
+		})
+	})
+
+	It("reports the same condition as the recovery window grows", func() {
+		cluster.Status.FirstRecoverabilityPoint = time.Now().Add(-24 * time.Hour).Format(time.RFC3339)
+		now := time.Now()
+
+		condition := getRecoveryWindowCondition(cluster, 7*24*time.Hour, now)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Message).To(Equal("The recovery window is shorter than the requested 7d"))
+		Expect(getRecoveryWindowCondition(cluster, 7*24*time.Hour, now.Add(time.Second))).To(Equal(condition))
+	})
+
+	It("removes the condition when no minimum recovery window is requested", func(ctx SpecContext) {
+		setFirstRecoverabilityPoint(ctx, time.Now().Add(-24*time.Hour).Format(time.RFC3339))
+		Expect(getCondition(ctx)).ToNot(BeNil())
+
+		cluster.Spec.Backup.MinRecoveryWindow = ""
+		Expect(env.client.Update(ctx, cluster)).To(Succeed())
+		Expect(env.clusterReconciler.reconcileRecoveryWindow(ctx, cluster)).To(Succeed())
+		Expect(getCondition(ctx)).To(BeNil())
+	})
+})
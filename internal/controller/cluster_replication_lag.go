@@ -0,0 +1,77 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+var (
+	standbyWriteLag = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "cnpg",
+		Name:      "instance_write_lag_bytes",
+		Help: "Amount of WAL generated by the primary and not yet written by the standby, " +
+			"as reported by pg_stat_replication on the primary",
+	}, []string{"namespace", "cluster", "pod"})
+
+	standbyReplayLag = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "cnpg",
+		Name:      "instance_replay_lag_bytes",
+		Help: "Amount of WAL generated by the primary and not yet replayed by the standby, " +
+			"as reported by pg_stat_replication on the primary",
+	}, []string{"namespace", "cluster", "pod"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(standbyWriteLag, standbyReplayLag)
+}
+
+// updateReplicationLagMetrics exposes the replication lag of the standbys
+// streaming from the primary. The lag changes continuously, so it is kept
+// out of the status of the cluster, whose updates would trigger a new
+// reconciliation every time
+func updateReplicationLagMetrics(cluster types.NamespacedName, statuses postgres.PostgresqlStatusList) {
+	deleteReplicationLagMetrics(cluster)
+
+	for _, item := range statuses.Items {
+		if !item.IsPrimary || item.Error != nil {
+			continue
+		}
+
+		for _, replication := range item.ReplicationInfo {
+			standbyWriteLag.WithLabelValues(cluster.Namespace, cluster.Name, replication.ApplicationName).
+				Set(float64(replication.WriteLagBytes))
+			standbyReplayLag.WithLabelValues(cluster.Namespace, cluster.Name, replication.ApplicationName).
+				Set(float64(replication.ReplayLagBytes))
+		}
+	}
+}
+
+// deleteReplicationLagMetrics removes the replication lag metrics
+// of a cluster
+func deleteReplicationLagMetrics(cluster types.NamespacedName) {
+	labels := prometheus.Labels{
+		"namespace": cluster.Namespace,
+		"cluster":   cluster.Name,
+	}
+	standbyWriteLag.DeletePartialMatch(labels)
+	standbyReplayLag.DeletePartialMatch(labels)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("replication lag metrics", func() {
+	cluster := types.NamespacedName{Namespace: "default", Name: "cluster-example"}
+
+	pod := func(name string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+
+	statuses := func(replicationInfo ...postgres.PgStatReplication) postgres.PostgresqlStatusList {
+		return postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{
+					Pod:             pod("cluster-example-1"),
+					IsPrimary:       true,
+					ReplicationInfo: replicationInfo,
+				},
+				{Pod: pod("cluster-example-2")},
+				// a standby which is still starting up and isn't
+				// streaming from the primary yet
+				{Pod: pod("cluster-example-3")},
+			},
+		}
+	}
+
+	AfterEach(func() {
+		deleteReplicationLagMetrics(cluster)
+	})
+
+	It("reports the lag of the standbys streaming from the primary", func() {
+		updateReplicationLagMetrics(cluster, statuses(postgres.PgStatReplication{
+			ApplicationName: "cluster-example-2",
+			WriteLagBytes:   1024,
+			ReplayLagBytes:  4096,
+		}))
+
+		Expect(testutil.ToFloat64(standbyWriteLag.WithLabelValues(
+			cluster.Namespace, cluster.Name, "cluster-example-2"))).To(BeEquivalentTo(1024))
+		Expect(testutil.ToFloat64(standbyReplayLag.WithLabelValues(
+			cluster.Namespace, cluster.Name, "cluster-example-2"))).To(BeEquivalentTo(4096))
+		Expect(testutil.CollectAndCount(standbyReplayLag)).To(Equal(1))
+	})
+
+	It("doesn't report any lag when the primary has no receivers", func() {
+		updateReplicationLagMetrics(cluster, statuses())
+
+		Expect(testutil.CollectAndCount(standbyWriteLag)).To(BeZero())
+		Expect(testutil.CollectAndCount(standbyReplayLag)).To(BeZero())
+	})
+
+	It("doesn't report any lag when the primary status is not available", func() {
+		instancesStatus := statuses(postgres.PgStatReplication{
+			ApplicationName: "cluster-example-2",
+			ReplayLagBytes:  4096,
+		})
+		instancesStatus.Items[0].Error = errors.New("connection refused")
+		updateReplicationLagMetrics(cluster, instancesStatus)
+
+		Expect(testutil.CollectAndCount(standbyReplayLag)).To(BeZero())
+	})
+
+	It("forgets the standbys that stopped streaming", func() {
+		updateReplicationLagMetrics(cluster, statuses(postgres.PgStatReplication{
+			ApplicationName: "cluster-example-2",
+			ReplayLagBytes:  4096,
+		}))
+		updateReplicationLagMetrics(cluster, statuses())
+
+		Expect(testutil.CollectAndCount(standbyWriteLag)).To(BeZero())
+		Expect(testutil.CollectAndCount(standbyReplayLag)).To(BeZero())
+	})
+})
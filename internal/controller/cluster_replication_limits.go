@@ -0,0 +1,109 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// reconcileReplicationLimits updates the ReplicationLimitsExceeded condition.
+// When `max_wal_senders` or `max_replication_slots` are explicitly set too
+// low for the number of instances, the replicas are unable to connect to the
+// primary, and PostgreSQL only reports it in the logs of the instances.
+func (r *ClusterReconciler) reconcileReplicationLimits(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	condition := getReplicationLimitsCondition(cluster)
+	if condition.Status == metav1.ConditionTrue &&
+		!meta.IsStatusConditionTrue(cluster.Status.Conditions, condition.Type) {
+		contextLogger.Warning("Replication limits exceeded",
+			"message", condition.Message)
+		r.Recorder.Event(cluster, "Warning", condition.Reason, condition.Message)
+	}
+
+	return conditions.Patch(ctx, r.Client, cluster, condition)
+}
+
+// getReplicationLimitsCondition computes the ReplicationLimitsExceeded
+// condition, comparing the replication limits set by the user with the
+// number of instances. When not set by the user, the limits are raised
+// automatically to fit the topology of the cluster
+func getReplicationLimitsCondition(cluster *apiv1.Cluster) *metav1.Condition {
+	limits := []struct {
+		parameter string
+		required  int
+		reason    apiv1.ConditionReason
+	}{
+		{
+			postgres.ParameterMaxWalSenders,
+			cluster.GetRequiredWalSenders(),
+			apiv1.ConditionReasonNotEnoughWalSenders,
+		},
+		{
+			postgres.ParameterMaxReplicationSlots,
+			cluster.GetRequiredReplicationSlots(),
+			apiv1.ConditionReasonNotEnoughReplicationSlots,
+		},
+	}
+
+	var reason apiv1.ConditionReason
+	var messages []string
+	for _, limit := range limits {
+		value, err := strconv.Atoi(cluster.Spec.PostgresConfiguration.Parameters[limit.parameter])
+		if err != nil || value >= limit.required {
+			continue
+		}
+
+		if reason == "" {
+			reason = limit.reason
+		}
+		messages = append(messages, fmt.Sprintf(
+			"`%s` is set to %d, while %d are needed by the replicas of the cluster",
+			limit.parameter, value, limit.required))
+	}
+
+	if len(messages) > 0 {
+		return &metav1.Condition{
+			Type:   string(apiv1.ConditionReplicationLimitsExceeded),
+			Status: metav1.ConditionTrue,
+			Reason: string(reason),
+			Message: strings.Join(messages, "; ") +
+				". Some replicas won't be able to stream from the primary",
+		}
+	}
+
+	return &metav1.Condition{
+		Type:    string(apiv1.ConditionReplicationLimitsExceeded),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionReasonReplicationLimitsSufficient),
+		Message: "The replication limits are enough for the replicas of the cluster",
+	}
+}
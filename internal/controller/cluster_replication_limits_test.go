@@ -0,0 +1,96 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("replication limits", func() {
+	var (
+		env     *testingEnvironment
+		cluster *apiv1.Cluster
+	)
+
+	BeforeEach(func() {
+		env = buildTestEnvironment()
+		cluster = newFakeCNPGCluster(env.client, newFakeNamespace(env.client))
+	})
+
+	updateCluster := func(ctx SpecContext, mutate func()) {
+		mutate()
+		Expect(env.client.Update(ctx, cluster)).To(Succeed())
+	}
+
+	getCondition := func(ctx SpecContext) *metav1.Condition {
+		var updatedCluster apiv1.Cluster
+		Expect(env.client.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		return meta.FindStatusCondition(updatedCluster.Status.Conditions,
+			string(apiv1.ConditionReplicationLimitsExceeded))
+	}
+
+	It("raises the condition when the cluster is scaled beyond the WAL senders", func(ctx SpecContext) {
+		By("fitting the replicas within the limits", func() {
+			updateCluster(ctx, func() {
+				cluster.Spec.PostgresConfiguration.Parameters["max_wal_senders"] = "4"
+			})
+			Expect(env.clusterReconciler.reconcileReplicationLimits(ctx, cluster)).To(Succeed())
+
+			condition := getCondition(ctx)
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonReplicationLimitsSufficient)))
+		})
+
+		By("scaling the cluster beyond max_wal_senders", func() {
+			updateCluster(ctx, func() {
+				cluster.Spec.Instances = 6
+			})
+			Expect(env.clusterReconciler.reconcileReplicationLimits(ctx, cluster)).To(Succeed())
+
+			condition := getCondition(ctx)
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonNotEnoughWalSenders)))
+			Expect(condition.Message).To(ContainSubstring("`max_wal_senders` is set to 4, while 5 are needed"))
+
+			recorder := env.clusterReconciler.Recorder.(*record.FakeRecorder)
+			Expect(recorder.Events).To(Receive(ContainSubstring(string(apiv1.ConditionReasonNotEnoughWalSenders))))
+		})
+	})
+
+	It("considers the replication slots only when used for high availability", func(ctx SpecContext) {
+		updateCluster(ctx, func() {
+			cluster.Spec.PostgresConfiguration.Parameters["max_replication_slots"] = "1"
+		})
+		Expect(env.clusterReconciler.reconcileReplicationLimits(ctx, cluster)).To(Succeed())
+		Expect(getCondition(ctx).Reason).To(Equal(string(apiv1.ConditionReasonNotEnoughReplicationSlots)))
+
+		updateCluster(ctx, func() {
+			cluster.Spec.ReplicationSlots.HighAvailability.Enabled = ptr.To(false)
+		})
+		Expect(env.clusterReconciler.reconcileReplicationLimits(ctx, cluster)).To(Succeed())
+		Expect(getCondition(ctx).Status).To(Equal(metav1.ConditionFalse))
+	})
+})
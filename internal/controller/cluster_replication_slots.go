@@ -0,0 +1,115 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+)
+
+// reconcileReplicationSlotsCollision detects whether the replication slot used by
+// the designated primary to stream from the source is also used by another
+// cluster replicating from the same source, and reports it as a condition
+func (r *ClusterReconciler) reconcileReplicationSlotsCollision(ctx context.Context, cluster *apiv1.Cluster) error {
+	contextLogger := log.FromContext(ctx)
+
+	condition := metav1.Condition{
+		Type:    string(apiv1.ConditionReplicationSlotsCollision),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionReasonNoReplicationSlotsCollision),
+		Message: "No replication slot collision detected",
+	}
+
+	if cluster.GetSourceSlotName() == "" {
+		if meta.FindStatusCondition(cluster.Status.Conditions, condition.Type) == nil {
+			return nil
+		}
+		return conditions.Patch(ctx, r.Client, cluster, &condition)
+	}
+
+	var clusterList apiv1.ClusterList
+	if err := r.List(ctx, &clusterList); err != nil {
+		return fmt.Errorf("while listing clusters to detect replication slot collisions: %w", err)
+	}
+
+	if collisions := findSourceSlotCollisions(cluster, clusterList.Items); len(collisions) > 0 {
+		contextLogger.Warning("Replication slot used to stream from the source is shared with other clusters",
+			"slotName", cluster.GetSourceSlotName(), "clusters", collisions)
+		if !meta.IsStatusConditionTrue(cluster.Status.Conditions, condition.Type) {
+			r.Recorder.Eventf(cluster, "Warning", "ReplicationSlotsCollision",
+				"Replication slot %q is also used by %s", cluster.GetSourceSlotName(), strings.Join(collisions, ", "))
+		}
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = string(apiv1.ConditionReasonReplicationSlotsCollision)
+		condition.Message = fmt.Sprintf(
+			"Replication slot %q is also used by %s, set a different .spec.replicationSlots.prefix",
+			cluster.GetSourceSlotName(), strings.Join(collisions, ", "))
+	}
+
+	return conditions.Patch(ctx, r.Client, cluster, &condition)
+}
+
+// findSourceSlotCollisions returns the names of the clusters, among the passed
+// ones, streaming from the same source of the given cluster through a
+// replication slot having the same name
+func findSourceSlotCollisions(cluster *apiv1.Cluster, clusters []apiv1.Cluster) []string {
+	slotName := cluster.GetSourceSlotName()
+	if slotName == "" {
+		return nil
+	}
+
+	sourceHost, sourcePort := getSourceHostAndPort(cluster)
+
+	var collisions []string
+	for idx := range clusters {
+		other := &clusters[idx]
+		if other.Namespace == cluster.Namespace && other.Name == cluster.Name {
+			continue
+		}
+
+		if other.GetSourceSlotName() != slotName {
+			continue
+		}
+
+		if otherHost, otherPort := getSourceHostAndPort(other); otherHost != sourceHost || otherPort != sourcePort {
+			continue
+		}
+
+		collisions = append(collisions, fmt.Sprintf("%s/%s", other.Namespace, other.Name))
+	}
+
+	return collisions
+}
+
+// getSourceHostAndPort returns the host and the port of the external
+// cluster a replica cluster is streaming from
+func getSourceHostAndPort(cluster *apiv1.Cluster) (string, string) {
+	source, _ := cluster.ExternalCluster(cluster.Spec.ReplicaCluster.Source)
+	port := source.ConnectionParameters["port"]
+	if port == "" {
+		port = "5432"
+	}
+	return source.ConnectionParameters["host"], port
+}
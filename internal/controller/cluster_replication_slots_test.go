@@ -0,0 +1,90 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("replication slot collision detection", func() {
+	newReplicaCluster := func(namespace, name, prefix, host string) apiv1.Cluster {
+		return apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      name,
+			},
+			Spec: apiv1.ClusterSpec{
+				ReplicaCluster: &apiv1.ReplicaClusterConfiguration{
+					Enabled: ptr.To(true),
+					Source:  "source",
+				},
+				ReplicationSlots: &apiv1.ReplicationSlotsConfiguration{
+					Prefix: prefix,
+				},
+				ExternalClusters: []apiv1.ExternalCluster{
+					{
+						Name: "source",
+						ConnectionParameters: map[string]string{
+							"host": host,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	It("detects two clusters sharing a source with the same prefix", func() {
+		first := newReplicaCluster("one", "pg", "_dr_", "source.example.com")
+		second := newReplicaCluster("two", "pg", "_dr_", "source.example.com")
+
+		Expect(findSourceSlotCollisions(&first, []apiv1.Cluster{first, second})).
+			To(ConsistOf("two/pg"))
+		Expect(findSourceSlotCollisions(&second, []apiv1.Cluster{first, second})).
+			To(ConsistOf("one/pg"))
+	})
+
+	It("doesn't report collisions when clusters sharing a source use distinct prefixes", func() {
+		first := newReplicaCluster("one", "pg", "_one_", "source.example.com")
+		second := newReplicaCluster("two", "pg", "_two_", "source.example.com")
+
+		Expect(first.GetSourceSlotName()).To(Equal("_one_pg"))
+		Expect(second.GetSourceSlotName()).To(Equal("_two_pg"))
+		Expect(findSourceSlotCollisions(&first, []apiv1.Cluster{first, second})).To(BeEmpty())
+		Expect(findSourceSlotCollisions(&second, []apiv1.Cluster{first, second})).To(BeEmpty())
+	})
+
+	It("doesn't report collisions between clusters replicating from different sources", func() {
+		first := newReplicaCluster("one", "pg", "_dr_", "source-a.example.com")
+		second := newReplicaCluster("two", "pg", "_dr_", "source-b.example.com")
+
+		Expect(findSourceSlotCollisions(&first, []apiv1.Cluster{first, second})).To(BeEmpty())
+	})
+
+	It("ignores clusters not using a replication slot on the source", func() {
+		first := newReplicaCluster("one", "pg", "", "source.example.com")
+		second := newReplicaCluster("two", "pg", "", "source.example.com")
+
+		Expect(first.GetSourceSlotName()).To(BeEmpty())
+		Expect(findSourceSlotCollisions(&first, []apiv1.Cluster{first, second})).To(BeEmpty())
+	})
+})
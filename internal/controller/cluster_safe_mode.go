@@ -0,0 +1,74 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// safeModeRequeueInterval is how often a cluster in safe mode is
+// reconciled, to keep its status up to date
+const safeModeRequeueInterval = 30 * time.Second
+
+// reconcileSafeMode updates the SafeMode condition. In safe mode, set with
+// the `cnpg.io/safeMode` annotation, the operator keeps updating the status
+// of the cluster, but doesn't create, delete, restart, promote or fence any
+// instance, and doesn't touch the PVCs
+func (r *ClusterReconciler) reconcileSafeMode(ctx context.Context, cluster *apiv1.Cluster) error {
+	contextLogger := log.FromContext(ctx)
+
+	if !utils.IsSafeModeEnabled(&cluster.ObjectMeta) {
+		if !meta.IsStatusConditionTrue(cluster.Status.Conditions, string(apiv1.ConditionSafeMode)) {
+			return nil
+		}
+
+		contextLogger.Info("Safe mode lifted, resuming the reconciliation of the instances")
+		r.Recorder.Event(cluster, "Normal", string(apiv1.ConditionReasonSafeModeDisabled),
+			"Safe mode lifted, resuming the reconciliation of the instances")
+		return conditions.Patch(ctx, r.Client, cluster, &metav1.Condition{
+			Type:    string(apiv1.ConditionSafeMode),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(apiv1.ConditionReasonSafeModeDisabled),
+			Message: "The operator reconciles the instances of the cluster",
+		})
+	}
+
+	const message = "The cluster is in safe mode: the operator updates its status, but doesn't " +
+		"create, delete, restart, promote or fence any instance. " +
+		"Remove the " + utils.SafeModeAnnotationName + " annotation to resume the reconciliation"
+
+	contextLogger.Warning("Safe mode enabled, only the status of the cluster will be updated")
+	if !meta.IsStatusConditionTrue(cluster.Status.Conditions, string(apiv1.ConditionSafeMode)) {
+		r.Recorder.Event(cluster, "Warning", string(apiv1.ConditionReasonSafeModeEnabled), message)
+	}
+
+	return conditions.Patch(ctx, r.Client, cluster, &metav1.Condition{
+		Type:    string(apiv1.ConditionSafeMode),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(apiv1.ConditionReasonSafeModeEnabled),
+		Message: message,
+	})
+}
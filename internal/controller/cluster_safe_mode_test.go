@@ -0,0 +1,119 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("safe mode", func() {
+	var (
+		env     *testingEnvironment
+		cluster *apiv1.Cluster
+	)
+
+	BeforeEach(func() {
+		env = buildTestEnvironment()
+		cluster = newFakeCNPGCluster(env.client, newFakeNamespace(env.client), func(cluster *apiv1.Cluster) {
+			cluster.Annotations[utils.SafeModeAnnotationName] = "true"
+		})
+	})
+
+	getCondition := func(ctx SpecContext) *metav1.Condition {
+		var updatedCluster apiv1.Cluster
+		Expect(env.client.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		return meta.FindStatusCondition(updatedCluster.Status.Conditions, string(apiv1.ConditionSafeMode))
+	}
+
+	It("raises the condition and emits a warning only once", func(ctx SpecContext) {
+		Expect(env.clusterReconciler.reconcileSafeMode(ctx, cluster)).To(Succeed())
+
+		condition := getCondition(ctx)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonSafeModeEnabled)))
+		Expect(condition.Message).To(ContainSubstring(utils.SafeModeAnnotationName))
+
+		recorder := env.clusterReconciler.Recorder.(*record.FakeRecorder)
+		Expect(recorder.Events).To(Receive(ContainSubstring(string(apiv1.ConditionReasonSafeModeEnabled))))
+
+		Expect(env.clusterReconciler.reconcileSafeMode(ctx, cluster)).To(Succeed())
+		Expect(recorder.Events).ToNot(Receive())
+	})
+
+	It("lowers the condition when the safe mode is lifted", func(ctx SpecContext) {
+		Expect(env.clusterReconciler.reconcileSafeMode(ctx, cluster)).To(Succeed())
+		Expect(getCondition(ctx).Status).To(Equal(metav1.ConditionTrue))
+
+		delete(cluster.Annotations, utils.SafeModeAnnotationName)
+		Expect(env.clusterReconciler.reconcileSafeMode(ctx, cluster)).To(Succeed())
+
+		condition := getCondition(ctx)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonSafeModeDisabled)))
+	})
+
+	It("doesn't act on a deleted pod", func(ctx SpecContext) {
+		pvcs := generateClusterPVC(env.client, cluster, persistentvolumeclaim.StatusReady)
+		pods := generateFakeClusterPods(env.client, cluster, true)
+		deletedPod := pods[len(pods)-1]
+		Expect(env.client.Delete(ctx, &deletedPod)).To(Succeed())
+
+		// The deleted pod was a deprioritized synchronous standby,
+		// which would get its priority back outside of safe mode
+		cluster.Status.InstanceNames = []string{pods[0].Name, pods[1].Name}
+		cluster.Status.SynchronousStandbyRotation = &apiv1.SynchronousStandbyRotationStatus{
+			Deprioritized: []string{deletedPod.Name},
+		}
+		Expect(env.client.Status().Update(ctx, cluster)).To(Succeed())
+
+		resources := &managedResources{
+			instances: corev1.PodList{Items: pods[:len(pods)-1]},
+			pvcs:      corev1.PersistentVolumeClaimList{Items: pvcs},
+		}
+		result, err := env.clusterReconciler.reconcileInstances(ctx, cluster, resources, postgres.PostgresqlStatusList{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(safeModeRequeueInterval))
+
+		err = env.client.Get(ctx, client.ObjectKeyFromObject(&deletedPod), &corev1.Pod{})
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+
+		var updatedCluster apiv1.Cluster
+		Expect(env.client.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		Expect(updatedCluster.Status.SynchronousStandbyRotation).ToNot(BeNil())
+		Expect(updatedCluster.Status.SynchronousStandbyRotation.Deprioritized).To(ConsistOf(deletedPod.Name))
+	})
+
+	It("doesn't add the condition to clusters never put in safe mode", func(ctx SpecContext) {
+		delete(cluster.Annotations, utils.SafeModeAnnotationName)
+		Expect(env.clusterReconciler.reconcileSafeMode(ctx, cluster)).To(Succeed())
+		Expect(getCondition(ctx)).To(BeNil())
+	})
+})
@@ -261,6 +261,9 @@ func (r *ClusterReconciler) updateResourceStatus(
 		resources.instances.Items,
 	)
 
+	// Record which instances got fenced or unfenced
+	r.updateFencedInstancesStatus(cluster)
+
 	// Count jobs
 	newJobs := int32(len(resources.jobs.Items)) //nolint:gosec
 	cluster.Status.JobCount = newJobs
@@ -269,9 +272,15 @@ func (r *ClusterReconciler) updateResourceStatus(
 		ctx,
 		resources.instances.Items,
 		resources.nodes,
-		cluster.Spec.PostgresConfiguration.SyncReplicaElectionConstraint,
+		getTopologyLabelNames(cluster),
 	)
 
+	podIPs, err := r.getPodIPs(ctx, cluster, resources.instances.Items)
+	if err != nil {
+		return err
+	}
+	cluster.Status.PodIPs = podIPs
+
 	// Services
 	cluster.Status.WriteService = cluster.GetServiceReadWriteName()
 	cluster.Status.ReadService = cluster.GetServiceReadName()
@@ -578,6 +587,17 @@ func (r *ClusterReconciler) refreshConfigMapResourceVersions(ctx context.Context
 		}
 	}
 
+	if len(cluster.Spec.PostgresConfiguration.IncludeConfigMaps) > 0 {
+		versions.IncludedConfiguration = make(map[string]string)
+		for _, config := range cluster.Spec.PostgresConfiguration.IncludeConfigMaps {
+			version, err := r.getConfigMapResourceVersion(ctx, cluster, config.Name)
+			if err != nil {
+				return err
+			}
+			versions.IncludedConfiguration[config.Name] = version
+		}
+	}
+
 	cluster.Status.ConfigMapResourceVersion = versions
 
 	return nil
@@ -767,12 +787,25 @@ func (r *ClusterReconciler) updateClusterStatusThatRequiresInstancesState(
 	return nil
 }
 
+// getTopologyLabelNames returns the names of the node labels to be
+// extracted in the topology of the instances
+func getTopologyLabelNames(cluster *apiv1.Cluster) []string {
+	antiAffinityLabels := cluster.Spec.PostgresConfiguration.SyncReplicaElectionConstraint.NodeLabelsAntiAffinity
+	if !cluster.IsSynchronousCrossZonePreferred() || slices.Contains(antiAffinityLabels, corev1.LabelTopologyZone) {
+		return antiAffinityLabels
+	}
+
+	labelNames := make([]string, 0, len(antiAffinityLabels)+1)
+	labelNames = append(labelNames, antiAffinityLabels...)
+	return append(labelNames, corev1.LabelTopologyZone)
+}
+
 // getPodsTopology returns a map with all the information about the pods topology
 func getPodsTopology(
 	ctx context.Context,
 	pods []corev1.Pod,
 	nodes map[string]corev1.Node,
-	topology apiv1.SyncReplicaElectionConstraints,
+	labelNames []string,
 ) apiv1.Topology {
 	contextLogger := log.FromContext(ctx)
 	data := make(map[apiv1.PodName]apiv1.PodTopologyLabels)
@@ -792,7 +825,7 @@ func getPodsTopology(
 
 		nodesMap[pod.Spec.NodeName] = append(nodesMap[pod.Spec.NodeName], podName)
 
-		for _, labelName := range topology.NodeLabelsAntiAffinity {
+		for _, labelName := range labelNames {
 			data[podName][labelName] = node.Labels[labelName]
 		}
 	}
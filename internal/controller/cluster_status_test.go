@@ -170,4 +170,19 @@ var _ = Describe("cluster_status unit tests", func() {
 			}))
 		})
 	})
+
+	It("extracts the zone of the instances when cross-zone standbys are preferred", func() {
+		cluster := &v1.Cluster{}
+		cluster.Spec.PostgresConfiguration.SyncReplicaElectionConstraint.NodeLabelsAntiAffinity = []string{"rack"}
+		Expect(getTopologyLabelNames(cluster)).To(Equal([]string{"rack"}))
+
+		cluster.Spec.PostgresConfiguration.Synchronous = &v1.SynchronousReplicaConfiguration{
+			Method:          v1.SynchronousReplicaConfigurationMethodAny,
+			Number:          1,
+			PreferCrossZone: true,
+		}
+		Expect(getTopologyLabelNames(cluster)).To(Equal([]string{"rack", corev1.LabelTopologyZone}))
+		Expect(cluster.Spec.PostgresConfiguration.SyncReplicaElectionConstraint.NodeLabelsAntiAffinity).
+			To(Equal([]string{"rack"}))
+	})
 })
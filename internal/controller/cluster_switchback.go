@@ -0,0 +1,265 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// switchbackRetryInterval is the time after which a switchback deferred
+// because of the running backups is evaluated again
+const switchbackRetryInterval = 30 * time.Second
+
+// reconcileSwitchback switches the primary back to the instance running on
+// its preferred node, once the node and the instance are healthy again.
+// It returns the name of the new target primary when a switchback has been
+// started, and the time after which a deferred switchback needs to be
+// evaluated again
+func (r *ClusterReconciler) reconcileSwitchback(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	status postgres.PostgresqlStatusList,
+) (string, time.Duration, error) {
+	targetPrimary, delay, deferral, err := r.evaluateSwitchback(ctx, cluster, status)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if err := r.reconcileSwitchbackDeferral(ctx, cluster, deferral); err != nil {
+		return "", 0, err
+	}
+
+	return targetPrimary, delay, nil
+}
+
+// reconcileSwitchbackDeferral updates the SwitchbackDeferred condition,
+// emitting an event only when the switchback starts being deferred for
+// a different reason
+func (r *ClusterReconciler) reconcileSwitchbackDeferral(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	deferral *metav1.Condition,
+) error {
+	if deferral == nil {
+		if !meta.IsStatusConditionTrue(cluster.Status.Conditions, string(apiv1.ConditionSwitchbackDeferred)) {
+			return nil
+		}
+
+		return conditions.Patch(ctx, r.Client, cluster, &metav1.Condition{
+			Type:    string(apiv1.ConditionSwitchbackDeferred),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(apiv1.ConditionReasonSwitchbackNotDeferred),
+			Message: "The switchback to the preferred node is not deferred",
+		})
+	}
+
+	existing := meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionSwitchbackDeferred))
+	if existing == nil || existing.Status != metav1.ConditionTrue || existing.Message != deferral.Message {
+		r.Recorder.Event(cluster, "Normal", "SwitchbackDeferred", deferral.Message)
+	}
+
+	return conditions.Patch(ctx, r.Client, cluster, deferral)
+}
+
+// evaluateSwitchback starts the switchback when it is allowed, returning
+// the condition describing why it was deferred otherwise
+func (r *ClusterReconciler) evaluateSwitchback(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	status postgres.PostgresqlStatusList,
+) (string, time.Duration, *metav1.Condition, error) {
+	autoSwitchback := cluster.GetAutoSwitchback()
+	if autoSwitchback == nil || cluster.IsReplica() || cluster.IsNodeMaintenanceWindowInProgress() ||
+		status.Len() == 0 {
+		return "", 0, nil, nil
+	}
+
+	preferredNode := cluster.Spec.PrimaryPreference.NodeName
+	contextLogger := log.FromContext(ctx).WithValues("preferredNode", preferredNode)
+
+	// Only a healthy primary, not being switched over, is switched back
+	primary := status.Items[0]
+	if !primary.IsPrimary || primary.Pod == nil ||
+		primary.Pod.Name != cluster.Status.CurrentPrimary ||
+		cluster.Status.TargetPrimary != cluster.Status.CurrentPrimary ||
+		primary.Node == preferredNode {
+		return "", 0, nil, nil
+	}
+
+	candidate := getInstanceOnNode(status, preferredNode)
+	if candidate == nil {
+		return "", 0, nil, nil
+	}
+
+	if err := r.checkSwitchbackCandidate(ctx, cluster, candidate); err != nil {
+		contextLogger.Debug("The instance on the preferred node can't be promoted yet",
+			"instance", candidate.Pod.Name, "reason", err.Error())
+		return "", 0, nil, nil
+	}
+
+	now := time.Now()
+	if delay := getSwitchbackCooldownDelay(cluster, candidate, autoSwitchback.GetCooldown(), now); delay > 0 {
+		contextLogger.Info("Deferring the switchback until the cooldown expires",
+			"instance", candidate.Pod.Name, "delay", delay)
+		return "", delay, &metav1.Condition{
+			Type:   string(apiv1.ConditionSwitchbackDeferred),
+			Status: metav1.ConditionTrue,
+			Reason: string(apiv1.ConditionReasonSwitchbackCooldown),
+			Message: fmt.Sprintf("Deferring the switchback to %s, on the preferred node %s, "+
+				"until the cooldown expires", candidate.Pod.Name, preferredNode),
+		}, nil
+	}
+
+	if delay := autoSwitchback.GetMaintenanceWindowDelay(now); delay > 0 {
+		contextLogger.Info("Deferring the switchback until the next maintenance window",
+			"instance", candidate.Pod.Name, "delay", delay)
+		return "", delay, &metav1.Condition{
+			Type:   string(apiv1.ConditionSwitchbackDeferred),
+			Status: metav1.ConditionTrue,
+			Reason: string(apiv1.ConditionReasonOutsideMaintenanceWindow),
+			Message: fmt.Sprintf("Deferring the switchback to %s, on the preferred node %s, "+
+				"until the next maintenance window", candidate.Pod.Name, preferredNode),
+		}, nil
+	}
+
+	blocked, err := r.isSwitchoverBlockedByBackup(ctx, cluster, candidate.Pod.Name)
+	if err != nil {
+		return "", 0, nil, err
+	}
+	if blocked {
+		return "", switchbackRetryInterval, nil, nil
+	}
+
+	if err := r.checkSwitchoverCandidateCatchUp(ctx, cluster, status, candidate.Pod.Name); err != nil {
+		switch {
+		case errors.Is(err, errSwitchoverCandidateLagging):
+			return "", 5 * time.Second, nil, nil
+		case errors.Is(err, errSwitchoverCatchUpTimedOut):
+			return "", 1 * time.Minute, nil, nil
+		default:
+			return "", 0, nil, err
+		}
+	}
+
+	contextLogger.Info("The preferred node of the primary is healthy again, switching back",
+		"currentPrimary", primary.Pod.Name, "currentPrimaryNode", primary.Node,
+		"targetPrimary", candidate.Pod.Name)
+	status.LogStatus(ctx)
+	r.Recorder.Eventf(cluster, "Normal", "SwitchingBack",
+		"The preferred node %v is healthy again, switching back from %v to %v",
+		preferredNode, primary.Pod.Name, candidate.Pod.Name)
+	if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseSwitchover,
+		fmt.Sprintf("Switching back to %v, running on the preferred node %v",
+			candidate.Pod.Name, preferredNode)); err != nil {
+		return "", 0, nil, err
+	}
+	return candidate.Pod.Name, 0, nil, r.setPrimaryInstance(ctx, cluster, candidate.Pod.Name)
+}
+
+// getInstanceOnNode gets the status of the replica running on the
+// passed node, or nil if there is none
+func getInstanceOnNode(status postgres.PostgresqlStatusList, nodeName string) *postgres.PostgresqlStatus {
+	for idx := range status.Items {
+		item := &status.Items[idx]
+		if !item.IsPrimary && item.Pod != nil && item.Node == nodeName {
+			return item
+		}
+	}
+	return nil
+}
+
+// checkSwitchbackCandidate checks that the instance on the preferred node,
+// and the node itself, are healthy enough to host the primary
+func (r *ClusterReconciler) checkSwitchbackCandidate(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	candidate *postgres.PostgresqlStatus,
+) error {
+	var node corev1.Node
+	if err := r.Get(ctx, client.ObjectKey{Name: candidate.Node}, &node); err != nil {
+		return fmt.Errorf("while getting the node: %w", err)
+	}
+	if node.Spec.Unschedulable {
+		return errors.New("the node is unschedulable")
+	}
+	if !isNodeReady(node) {
+		return errors.New("the node is not ready")
+	}
+
+	switch {
+	case candidate.Pod.DeletionTimestamp != nil:
+		return errors.New("the instance is being deleted")
+	case !utils.IsPodReady(*candidate.Pod):
+		return errors.New("the instance is not ready")
+	case !candidate.HasHTTPStatus():
+		return errors.New("the instance didn't report its status")
+	case cluster.IsInstanceFenced(candidate.Pod.Name):
+		return errors.New("the instance is fenced")
+	case !candidate.IsWalReceiverActive:
+		return errors.New("the instance is not streaming from the primary")
+	}
+
+	return nil
+}
+
+// isNodeReady checks if the kubelet of the passed node reports it as ready
+func isNodeReady(node corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// getSwitchbackCooldownDelay gets the time to wait before switching back,
+// for the cooldown to elapse both since the last promotion and since the
+// instance on the preferred node became ready
+func getSwitchbackCooldownDelay(
+	cluster *apiv1.Cluster,
+	candidate *postgres.PostgresqlStatus,
+	cooldown time.Duration,
+	now time.Time,
+) time.Duration {
+	var since time.Time
+	if cluster.Status.CurrentPrimaryTimestamp != "" {
+		if promotedAt, err := time.Parse(metav1.RFC3339Micro, cluster.Status.CurrentPrimaryTimestamp); err == nil {
+			since = promotedAt
+		}
+	}
+	for _, condition := range candidate.Pod.Status.Conditions {
+		if condition.Type == corev1.ContainersReady && condition.LastTransitionTime.After(since) {
+			since = condition.LastTransitionTime.Time
+		}
+	}
+
+	return max(since.Add(cooldown).Sub(now), 0)
+}
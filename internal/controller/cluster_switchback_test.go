@@ -0,0 +1,254 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("switchback to the preferred node", func() {
+	const (
+		preferredNode = "node-1"
+		otherNode     = "node-2"
+	)
+
+	var (
+		env     *testingEnvironment
+		cluster *apiv1.Cluster
+	)
+
+	setNode := func(ctx SpecContext, name string, healthy bool) {
+		readyStatus := corev1.ConditionFalse
+		if healthy {
+			readyStatus = corev1.ConditionTrue
+		}
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       corev1.NodeSpec{Unschedulable: !healthy},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: readyStatus}},
+			},
+		}
+
+		var existing corev1.Node
+		if err := env.client.Get(ctx, client.ObjectKey{Name: name}, &existing); err != nil {
+			Expect(env.client.Create(ctx, node)).To(Succeed())
+			return
+		}
+		// The status of the node is a subresource, and is not changed by Update
+		nodeStatus := node.Status
+		node.ResourceVersion = existing.ResourceVersion
+		Expect(env.client.Update(ctx, node)).To(Succeed())
+		node.Status = nodeStatus
+		Expect(env.client.Status().Update(ctx, node)).To(Succeed())
+	}
+
+	instanceStatus := func(name string, node string, isPrimary bool, ready bool) postgres.PostgresqlStatus {
+		readyStatus := corev1.ConditionFalse
+		if ready {
+			readyStatus = corev1.ConditionTrue
+		}
+		return postgres.PostgresqlStatus{
+			Pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: cluster.Namespace},
+				Status: corev1.PodStatus{
+					Conditions: []corev1.PodCondition{
+						{
+							Type:   corev1.PodReady,
+							Status: readyStatus,
+						},
+						{
+							Type:               corev1.ContainersReady,
+							Status:             readyStatus,
+							LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+						},
+					},
+				},
+			},
+			Node:                node,
+			IsPrimary:           isPrimary,
+			IsPodReady:          ready,
+			IsWalReceiverActive: !isPrimary && ready,
+		}
+	}
+
+	statusList := func(candidateReady bool) postgres.PostgresqlStatusList {
+		return postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			instanceStatus(cluster.Name+"-2", otherNode, true, true),
+			instanceStatus(cluster.Name+"-1", preferredNode, false, candidateReady),
+		}}
+	}
+
+	getTargetPrimary := func(ctx SpecContext) string {
+		var updatedCluster apiv1.Cluster
+		Expect(env.client.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		return updatedCluster.Status.TargetPrimary
+	}
+
+	getDeferredCondition := func(ctx SpecContext) *metav1.Condition {
+		var updatedCluster apiv1.Cluster
+		Expect(env.client.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		return meta.FindStatusCondition(updatedCluster.Status.Conditions, string(apiv1.ConditionSwitchbackDeferred))
+	}
+
+	BeforeEach(func(ctx SpecContext) {
+		env = buildTestEnvironment()
+		cluster = newFakeCNPGCluster(env.client, newFakeNamespace(env.client), func(cluster *apiv1.Cluster) {
+			cluster.Spec.PrimaryPreference = &apiv1.PrimaryPreferenceConfiguration{
+				NodeName: preferredNode,
+				AutoSwitchback: &apiv1.AutoSwitchbackConfiguration{
+					Cooldown: ptr.To(int32(0)),
+				},
+			}
+			// The primary has been moved away from the preferred node
+			cluster.Status.CurrentPrimary = cluster.Name + "-2"
+			cluster.Status.TargetPrimary = cluster.Name + "-2"
+		})
+		setNode(ctx, otherNode, true)
+	})
+
+	It("switches back once the preferred node comes back", func(ctx SpecContext) {
+		By("keeping the primary away while the preferred node is down", func() {
+			setNode(ctx, preferredNode, false)
+			target, requeue, err := env.clusterReconciler.reconcileSwitchback(ctx, cluster, statusList(false))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(target).To(BeEmpty())
+			Expect(requeue).To(BeZero())
+			Expect(getTargetPrimary(ctx)).To(Equal(cluster.Name + "-2"))
+		})
+
+		By("switching back when the preferred node and its instance are healthy", func() {
+			setNode(ctx, preferredNode, true)
+			target, _, err := env.clusterReconciler.reconcileSwitchback(ctx, cluster, statusList(true))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(target).To(Equal(cluster.Name + "-1"))
+			Expect(getTargetPrimary(ctx)).To(Equal(cluster.Name + "-1"))
+
+			recorder := env.clusterReconciler.Recorder.(*record.FakeRecorder)
+			Expect(recorder.Events).To(Receive(ContainSubstring("SwitchingBack")))
+		})
+	})
+
+	It("never switches back to a node that is not ready", func(ctx SpecContext) {
+		setNode(ctx, preferredNode, true)
+		var node corev1.Node
+		Expect(env.client.Get(ctx, client.ObjectKey{Name: preferredNode}, &node)).To(Succeed())
+		node.Status.Conditions[0].Status = corev1.ConditionUnknown
+		Expect(env.client.Status().Update(ctx, &node)).To(Succeed())
+
+		target, _, err := env.clusterReconciler.reconcileSwitchback(ctx, cluster, statusList(true))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(target).To(BeEmpty())
+	})
+
+	It("never switches back to an instance that is not streaming", func(ctx SpecContext) {
+		setNode(ctx, preferredNode, true)
+		status := statusList(true)
+		status.Items[1].IsWalReceiverActive = false
+
+		target, _, err := env.clusterReconciler.reconcileSwitchback(ctx, cluster, status)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(target).To(BeEmpty())
+	})
+
+	It("waits for the cooldown to expire", func(ctx SpecContext) {
+		setNode(ctx, preferredNode, true)
+		cluster.Spec.PrimaryPreference.AutoSwitchback.Cooldown = ptr.To(int32(600))
+		Expect(env.client.Update(ctx, cluster)).To(Succeed())
+		cluster.Status.CurrentPrimaryTimestamp = time.Now().Format(metav1.RFC3339Micro)
+		Expect(env.client.Status().Update(ctx, cluster)).To(Succeed())
+
+		target, requeue, err := env.clusterReconciler.reconcileSwitchback(ctx, cluster, statusList(true))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(target).To(BeEmpty())
+		Expect(requeue).To(BeNumerically("~", 10*time.Minute, time.Minute))
+		Expect(getTargetPrimary(ctx)).To(Equal(cluster.Name + "-2"))
+
+		recorder := env.clusterReconciler.Recorder.(*record.FakeRecorder)
+		Expect(recorder.Events).To(Receive(ContainSubstring("until the cooldown expires")))
+
+		condition := getDeferredCondition(ctx)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonSwitchbackCooldown)))
+
+		By("not emitting the event again while the switchback is still deferred", func() {
+			_, _, err := env.clusterReconciler.reconcileSwitchback(ctx, cluster, statusList(true))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(recorder.Events).ToNot(Receive())
+		})
+
+		By("clearing the condition once the cooldown is over", func() {
+			cluster.Spec.PrimaryPreference.AutoSwitchback.Cooldown = ptr.To(int32(0))
+			Expect(env.client.Update(ctx, cluster)).To(Succeed())
+			target, _, err := env.clusterReconciler.reconcileSwitchback(ctx, cluster, statusList(true))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(target).To(Equal(cluster.Name + "-1"))
+
+			condition := getDeferredCondition(ctx)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		})
+	})
+
+	It("waits for the next maintenance window", func(ctx SpecContext) {
+		setNode(ctx, preferredNode, true)
+		// A window starting one hour from now
+		start := time.Now().Add(time.Hour)
+		cluster.Spec.PrimaryPreference.AutoSwitchback.MaintenanceWindows = []apiv1.SwitchbackWindow{{
+			Schedule: fmt.Sprintf("0 %d %d * * *", start.Minute(), start.Hour()),
+			Duration: metav1.Duration{Duration: time.Minute},
+		}}
+
+		target, requeue, err := env.clusterReconciler.reconcileSwitchback(ctx, cluster, statusList(true))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(target).To(BeEmpty())
+		Expect(requeue).To(BeNumerically(">", 0))
+		Expect(requeue).To(BeNumerically("<=", time.Hour))
+
+		recorder := env.clusterReconciler.Recorder.(*record.FakeRecorder)
+		Expect(recorder.Events).To(Receive(ContainSubstring("until the next maintenance window")))
+
+		condition := getDeferredCondition(ctx)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonOutsideMaintenanceWindow)))
+	})
+
+	It("doesn't switch back when the automatic switchback is not enabled", func(ctx SpecContext) {
+		setNode(ctx, preferredNode, true)
+		cluster.Spec.PrimaryPreference.AutoSwitchback = nil
+
+		target, _, err := env.clusterReconciler.reconcileSwitchback(ctx, cluster, statusList(true))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(target).To(BeEmpty())
+	})
+})
@@ -0,0 +1,145 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	pgTime "github.com/cloudnative-pg/machinery/pkg/postgres/time"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+var (
+	// errSwitchoverCandidateLagging is raised when a switchover has been
+	// deferred because the new primary is still catching up
+	errSwitchoverCandidateLagging = errors.New("switchover deferred until the new primary catches up")
+
+	// errSwitchoverCatchUpTimedOut is raised when a switchover has been
+	// aborted because the new primary didn't catch up in time
+	errSwitchoverCatchUpTimedOut = errors.New("switchover aborted, the new primary didn't catch up in time")
+)
+
+// getSwitchoverCandidateLag gets the amount of WAL, in bytes, the candidate
+// has still to replay to reach the current WAL position of the primary
+func getSwitchoverCandidateLag(primary, candidate *postgres.PostgresqlStatus) (int64, error) {
+	primaryLSN, err := primary.CurrentLsn.Parse()
+	if err != nil {
+		return 0, fmt.Errorf("while parsing the current LSN of %s: %w", primary.Pod.Name, err)
+	}
+	candidateLSN, err := candidate.ReplayLsn.Parse()
+	if err != nil {
+		return 0, fmt.Errorf("while parsing the replay LSN of %s: %w", candidate.Pod.Name, err)
+	}
+
+	return max(primaryLSN-candidateLSN, 0), nil
+}
+
+// checkSwitchoverCandidateCatchUp checks whether the new primary replayed
+// the WAL of the current one, within the configured threshold, before
+// initiating a switchover. It raises errSwitchoverCandidateLagging while
+// waiting and errSwitchoverCatchUpTimedOut when the wait has been aborted
+func (r *ClusterReconciler) checkSwitchoverCandidateCatchUp(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	status postgres.PostgresqlStatusList,
+	targetPrimary string,
+) error {
+	if cluster.Spec.SwitchoverMaxLagBytes == nil {
+		return nil
+	}
+	contextLogger := log.FromContext(ctx).WithValues("targetPrimary", targetPrimary)
+
+	var primary, candidate *postgres.PostgresqlStatus
+	for idx := range status.Items {
+		item := &status.Items[idx]
+		switch {
+		case item.IsPrimary:
+			primary = item
+		case item.Pod.Name == targetPrimary:
+			candidate = item
+		}
+	}
+
+	lag := int64(-1)
+	if primary != nil && candidate != nil {
+		var err error
+		if lag, err = getSwitchoverCandidateLag(primary, candidate); err != nil {
+			contextLogger.Info("Cannot evaluate the replay lag of the new primary", "err", err)
+			lag = -1
+		}
+	}
+
+	if lag >= 0 && lag <= *cluster.Spec.SwitchoverMaxLagBytes {
+		return r.setSwitchoverCatchUpSinceTimestamp(ctx, cluster, "")
+	}
+
+	if cluster.Status.SwitchoverCatchUpSinceTimestamp == "" {
+		contextLogger.Info("Deferring the switchover until the new primary catches up",
+			"lagBytes", lag, "maxLagBytes", *cluster.Spec.SwitchoverMaxLagBytes)
+		r.Recorder.Eventf(cluster, "Normal", "SwitchoverWaitingForCatchUp",
+			"Deferring the switchover to %s until it catches up with the primary", targetPrimary)
+		if err := r.setSwitchoverCatchUpSinceTimestamp(ctx, cluster, pgTime.GetCurrentTimestamp()); err != nil {
+			return err
+		}
+		return errSwitchoverCandidateLagging
+	}
+
+	waitingSince, err := pgTime.DifferenceBetweenTimestamps(
+		pgTime.GetCurrentTimestamp(),
+		cluster.Status.SwitchoverCatchUpSinceTimestamp,
+	)
+	if err != nil {
+		return err
+	}
+	if waitingSince < time.Duration(cluster.GetSwitchoverCatchUpTimeout())*time.Second {
+		return errSwitchoverCandidateLagging
+	}
+
+	contextLogger.Warning("Aborting the switchover, as the new primary didn't catch up in time",
+		"lagBytes", lag, "maxLagBytes", *cluster.Spec.SwitchoverMaxLagBytes)
+	r.Recorder.Eventf(cluster, "Warning", "SwitchoverAborted",
+		"Switchover to %s aborted, as it didn't catch up with the primary within %d seconds, "+
+			"keeping %s as the primary",
+		targetPrimary, cluster.GetSwitchoverCatchUpTimeout(), cluster.Status.CurrentPrimary)
+	if err := r.setSwitchoverCatchUpSinceTimestamp(ctx, cluster, ""); err != nil {
+		return err
+	}
+	return errSwitchoverCatchUpTimedOut
+}
+
+// setSwitchoverCatchUpSinceTimestamp updates the timestamp when the
+// operator started waiting for the new primary to catch up
+func (r *ClusterReconciler) setSwitchoverCatchUpSinceTimestamp(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	timestamp string,
+) error {
+	if cluster.Status.SwitchoverCatchUpSinceTimestamp == timestamp {
+		return nil
+	}
+
+	origCluster := cluster.DeepCopy()
+	cluster.Status.SwitchoverCatchUpSinceTimestamp = timestamp
+	return r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
@@ -0,0 +1,137 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("switchover waiting for the new primary to catch up", func() {
+	var (
+		r        *ClusterReconciler
+		cluster  *apiv1.Cluster
+		recorder *record.FakeRecorder
+	)
+
+	podList := func(replayLsn types.LSN) *postgres.PostgresqlStatusList {
+		return &postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{
+					Pod:        &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: cluster.Name + "-1"}},
+					IsPrimary:  true,
+					CurrentLsn: "0/3000000",
+				},
+				{
+					Pod:                 &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: cluster.Name + "-2"}},
+					IsWalReceiverActive: true,
+					ReplayLsn:           replayLsn,
+				},
+			},
+		}
+	}
+
+	getUpdatedCluster := func(ctx SpecContext) *apiv1.Cluster {
+		var updatedCluster apiv1.Cluster
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		return &updatedCluster
+	}
+
+	BeforeEach(func(ctx SpecContext) {
+		k8sClient := fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithStatusSubresource(&apiv1.Cluster{}).
+			Build()
+		recorder = record.NewFakeRecorder(120)
+		r = &ClusterReconciler{Client: k8sClient, Recorder: recorder}
+
+		cluster = newFakeCNPGCluster(k8sClient, newFakeNamespace(k8sClient), func(cluster *apiv1.Cluster) {
+			cluster.Spec.PrimaryUpdateMethod = apiv1.PrimaryUpdateMethodSwitchover
+			cluster.Spec.SwitchoverMaxLagBytes = ptr.To(int64(1024 * 1024))
+		})
+		cluster.Status.Instances = 2
+		cluster.Status.CurrentPrimary = cluster.Name + "-1"
+		cluster.Status.TargetPrimary = cluster.Name + "-1"
+		Expect(k8sClient.Status().Update(ctx, cluster)).To(Succeed())
+	})
+
+	It("computes the replay lag of the new primary", func() {
+		pods := podList("0/2000000")
+		lag, err := getSwitchoverCandidateLag(&pods.Items[0], &pods.Items[1])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lag).To(BeEquivalentTo(0x1000000))
+
+		pods = podList("0/4000000")
+		lag, err = getSwitchoverCandidateLag(&pods.Items[0], &pods.Items[1])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lag).To(BeZero())
+	})
+
+	It("defers the switchover until the new primary catches up", func(ctx SpecContext) {
+		primaryPod := *podList("").Items[0].Pod
+
+		By("deferring the switchover while the new primary is lagging", func() {
+			done, err := r.updatePrimaryPod(ctx, cluster, podList("0/2000000"), primaryPod, false, false, "test")
+			Expect(err).To(MatchError(errSwitchoverCandidateLagging))
+			Expect(done).To(BeFalse())
+			updatedCluster := getUpdatedCluster(ctx)
+			Expect(updatedCluster.Status.TargetPrimary).To(Equal(cluster.Name + "-1"))
+			Expect(updatedCluster.Status.SwitchoverCatchUpSinceTimestamp).ToNot(BeEmpty())
+			Expect(recorder.Events).To(Receive(ContainSubstring("SwitchoverWaitingForCatchUp")))
+		})
+
+		By("switching over once the new primary caught up", func() {
+			done, err := r.updatePrimaryPod(ctx, cluster, podList("0/2F00000"), primaryPod, false, false, "test")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(done).To(BeTrue())
+			updatedCluster := getUpdatedCluster(ctx)
+			Expect(updatedCluster.Status.TargetPrimary).To(Equal(cluster.Name + "-2"))
+			Expect(updatedCluster.Status.SwitchoverCatchUpSinceTimestamp).To(BeEmpty())
+		})
+	})
+
+	It("aborts the switchover when the new primary doesn't catch up in time", func(ctx SpecContext) {
+		cluster.Status.SwitchoverCatchUpSinceTimestamp = time.Now().Add(-10 * time.Minute).Format("2006-01-02T15:04:05.000000Z07:00")
+		Expect(r.Status().Update(ctx, cluster)).To(Succeed())
+
+		err := r.checkSwitchoverCandidateCatchUp(ctx, cluster, *podList("0/2000000"), cluster.Name+"-2")
+		Expect(err).To(MatchError(errSwitchoverCatchUpTimedOut))
+		updatedCluster := getUpdatedCluster(ctx)
+		Expect(updatedCluster.Status.TargetPrimary).To(Equal(cluster.Name + "-1"))
+		Expect(updatedCluster.Status.SwitchoverCatchUpSinceTimestamp).To(BeEmpty())
+		Expect(recorder.Events).To(Receive(ContainSubstring("SwitchoverAborted")))
+	})
+
+	It("doesn't wait for the new primary when not configured to", func(ctx SpecContext) {
+		cluster.Spec.SwitchoverMaxLagBytes = nil
+		Expect(r.checkSwitchoverCandidateCatchUp(ctx, cluster, *podList("0/0"), cluster.Name+"-2")).To(Succeed())
+	})
+})
@@ -0,0 +1,244 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// syncStandbyEvent is an event to be raised on the cluster while replacing
+// the lagging synchronous standbys
+type syncStandbyEvent struct {
+	eventType string
+	reason    string
+	message   string
+}
+
+// reconcileSynchronousStandbyLag replaces the synchronous standbys that have
+// been lagging behind the primary for longer than the configured delay,
+// moving them to the end of synchronous_standby_names. It returns the time
+// after which the lagging standbys need to be evaluated again, or zero
+func (r *ClusterReconciler) reconcileSynchronousStandbyLag(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) (time.Duration, error) {
+	contextLogger := log.FromContext(ctx)
+
+	var rotationStatus *apiv1.SynchronousStandbyRotationStatus
+	var requeueAfter time.Duration
+	if rotation := cluster.GetSynchronousStandbyLagRotation(); rotation != nil {
+		primary := getPrimaryStatus(instancesStatus)
+		if primary == nil {
+			// Without the point of view of the primary we can't tell
+			// anything about the lag of the standbys
+			return 0, nil
+		}
+
+		var events []syncStandbyEvent
+		rotationStatus, events, requeueAfter = evaluateSynchronousStandbyLag(
+			cluster, rotation, primary, time.Now())
+		for _, event := range events {
+			if event.eventType == "Warning" {
+				contextLogger.Warning(event.message)
+			} else {
+				contextLogger.Info(event.message)
+			}
+			r.Recorder.Event(cluster, event.eventType, event.reason, event.message)
+		}
+	}
+
+	if reflect.DeepEqual(rotationStatus, cluster.Status.SynchronousStandbyRotation) {
+		return requeueAfter, nil
+	}
+
+	origCluster := cluster.DeepCopy()
+	cluster.Status.SynchronousStandbyRotation = rotationStatus
+	return requeueAfter, r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// getPrimaryStatus returns the status reported by the primary instance,
+// or nil if the primary didn't report it
+func getPrimaryStatus(instancesStatus postgres.PostgresqlStatusList) *postgres.PostgresqlStatus {
+	for idx := range instancesStatus.Items {
+		item := &instancesStatus.Items[idx]
+		if item.IsPrimary && item.Error == nil && item.CurrentLsn != "" {
+			return item
+		}
+	}
+	return nil
+}
+
+// evaluateSynchronousStandbyLag computes the new state of the replacement of
+// the lagging synchronous standbys, from the replication status reported by
+// the primary. It returns the new state, the events to be raised and the time
+// after which the lagging standbys need to be evaluated again.
+// It has no side effects
+func evaluateSynchronousStandbyLag(
+	cluster *apiv1.Cluster,
+	rotation *apiv1.SynchronousStandbyLagRotation,
+	primary *postgres.PostgresqlStatus,
+	now time.Time,
+) (*apiv1.SynchronousStandbyRotationStatus, []syncStandbyEvent, time.Duration) {
+	maxLag := rotation.GetMaxLag()
+	delay := rotation.GetDelay()
+
+	var previousLaggingSince map[string]string
+	var deprioritized []string
+	if cluster.Status.SynchronousStandbyRotation != nil {
+		previousLaggingSince = cluster.Status.SynchronousStandbyRotation.LaggingSince
+		deprioritized = slices.Clone(cluster.Status.SynchronousStandbyRotation.Deprioritized)
+	}
+
+	currentLsn, err := primary.CurrentLsn.Parse()
+	if err != nil {
+		return cluster.Status.SynchronousStandbyRotation, nil, 0
+	}
+
+	lags := make(map[string]int64, len(primary.ReplicationInfo))
+	var syncStandbys, candidates []string
+	for _, replication := range primary.ReplicationInfo {
+		flushLsn, err := replication.FlushLsn.Parse()
+		if err != nil {
+			continue
+		}
+		name := replication.ApplicationName
+		lags[name] = max(currentLsn-flushLsn, 0)
+
+		switch replication.SyncState {
+		case "sync", "quorum":
+			syncStandbys = append(syncStandbys, name)
+		case "potential", "async":
+			candidates = append(candidates, name)
+		}
+	}
+
+	var events []syncStandbyEvent
+	formatLag := func(lag int64) string {
+		return resource.NewQuantity(lag, resource.BinarySI).String()
+	}
+
+	// The deprioritized standbys that caught up with the primary, or that
+	// have been removed from the cluster, get back their priority
+	deprioritized = slices.DeleteFunc(deprioritized, func(name string) bool {
+		if !slices.Contains(cluster.Status.InstanceNames, name) {
+			return true
+		}
+		lag, reported := lags[name]
+		if !reported || lag > maxLag {
+			return false
+		}
+		events = append(events, syncStandbyEvent{
+			eventType: "Normal",
+			reason:    "SynchronousStandbyCaughtUp",
+			message: fmt.Sprintf(
+				"Standby %s caught up with the primary and can be chosen again as a synchronous standby", name),
+		})
+		return true
+	})
+
+	// Only the healthy replicas that are not lagging can replace a
+	// synchronous standby, starting from the most caught-up one
+	candidates = slices.DeleteFunc(candidates, func(name string) bool {
+		return lags[name] > maxLag ||
+			slices.Contains(deprioritized, name) ||
+			!slices.Contains(cluster.Status.InstancesStatus[apiv1.PodHealthy], name)
+	})
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return lags[candidates[i]] < lags[candidates[j]]
+	})
+
+	laggingSince := make(map[string]string)
+	var requeueAfter time.Duration
+	sort.Strings(syncStandbys)
+	for _, name := range syncStandbys {
+		if lags[name] <= maxLag || slices.Contains(deprioritized, name) {
+			continue
+		}
+
+		since, err := time.Parse(metav1.RFC3339Micro, previousLaggingSince[name])
+		if err != nil {
+			since = now
+		}
+
+		if elapsed := now.Sub(since); elapsed < delay {
+			laggingSince[name] = since.Format(metav1.RFC3339Micro)
+			requeueAfter = minPositiveDuration(requeueAfter, delay-elapsed)
+			continue
+		}
+
+		if len(candidates) == 0 {
+			// There's no replica that can replace this standby,
+			// let's check again later
+			laggingSince[name] = since.Format(metav1.RFC3339Micro)
+			requeueAfter = minPositiveDuration(requeueAfter, delay)
+			continue
+		}
+
+		replacement := candidates[0]
+		candidates = candidates[1:]
+		deprioritized = append(deprioritized, name)
+		events = append(events, syncStandbyEvent{
+			eventType: "Warning",
+			reason:    "SynchronousStandbyReplaced",
+			message: fmt.Sprintf(
+				"Synchronous standby %s has been lagging behind the primary by %s for more than %s, "+
+					"replacing it with %s (lag %s)",
+				name, formatLag(lags[name]), delay, replacement, formatLag(lags[replacement])),
+		})
+	}
+
+	if len(laggingSince) == 0 && len(deprioritized) == 0 {
+		return nil, events, requeueAfter
+	}
+
+	status := &apiv1.SynchronousStandbyRotationStatus{}
+	if len(laggingSince) > 0 {
+		status.LaggingSince = laggingSince
+	}
+	if len(deprioritized) > 0 {
+		sort.Strings(deprioritized)
+		status.Deprioritized = deprioritized
+	}
+	return status, events, requeueAfter
+}
+
+// minPositiveDuration returns the smaller of the two durations,
+// ignoring the ones that are not positive
+func minPositiveDuration(a, b time.Duration) time.Duration {
+	switch {
+	case a <= 0:
+		return b
+	case b <= 0:
+		return a
+	default:
+		return min(a, b)
+	}
+}
@@ -0,0 +1,201 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("lagging synchronous standbys", func() {
+	const mi = 1024 * 1024
+
+	var (
+		env     *testingEnvironment
+		cluster *apiv1.Cluster
+	)
+
+	lsn := func(value int64) types.LSN {
+		return types.LSN(fmt.Sprintf("%X/%X", value>>32, value&0xFFFFFFFF))
+	}
+
+	// instancesStatus builds the status reported by the primary, given the
+	// lag and the sync state of each standby
+	instancesStatus := func(standbys map[string]struct {
+		lag       int64
+		syncState string
+	},
+	) postgres.PostgresqlStatusList {
+		const currentLsn = 1024 * mi
+		primary := postgres.PostgresqlStatus{
+			Pod:        &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: cluster.Name + "-1"}},
+			IsPrimary:  true,
+			CurrentLsn: lsn(currentLsn),
+		}
+		for name, standby := range standbys {
+			primary.ReplicationInfo = append(primary.ReplicationInfo, postgres.PgStatReplication{
+				ApplicationName: name,
+				FlushLsn:        lsn(currentLsn - standby.lag),
+				SyncState:       standby.syncState,
+			})
+		}
+		return postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{primary}}
+	}
+
+	getUpdatedCluster := func(ctx SpecContext) *apiv1.Cluster {
+		var updatedCluster apiv1.Cluster
+		Expect(env.client.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		return &updatedCluster
+	}
+
+	BeforeEach(func(ctx SpecContext) {
+		env = buildTestEnvironment()
+		cluster = newFakeCNPGCluster(env.client, newFakeNamespace(env.client), func(cluster *apiv1.Cluster) {
+			cluster.Spec.PostgresConfiguration.Synchronous = &apiv1.SynchronousReplicaConfiguration{
+				Method: apiv1.SynchronousReplicaConfigurationMethodFirst,
+				Number: 1,
+				LagRotation: &apiv1.SynchronousStandbyLagRotation{
+					MaxLag: "16Mi",
+					Delay:  ptr.To(int32(60)),
+				},
+			}
+		})
+		Expect(env.client.Update(ctx, cluster)).To(Succeed())
+
+		cluster.Status.InstanceNames = []string{cluster.Name + "-1", cluster.Name + "-2", cluster.Name + "-3"}
+		cluster.Status.InstancesStatus = map[apiv1.PodStatus][]string{
+			apiv1.PodHealthy: cluster.Status.InstanceNames,
+		}
+		Expect(env.client.Status().Update(ctx, cluster)).To(Succeed())
+	})
+
+	It("replaces a lagging synchronous standby with a more caught-up replica", func(ctx SpecContext) {
+		standbys := map[string]struct {
+			lag       int64
+			syncState string
+		}{
+			cluster.Name + "-2": {lag: 128 * mi, syncState: "sync"},
+			cluster.Name + "-3": {lag: mi, syncState: "potential"},
+		}
+
+		By("recording when the synchronous standby started lagging", func() {
+			requeueAfter, err := env.clusterReconciler.reconcileSynchronousStandbyLag(
+				ctx, cluster, instancesStatus(standbys))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(requeueAfter).To(BeNumerically("~", time.Minute, time.Second))
+
+			rotation := getUpdatedCluster(ctx).Status.SynchronousStandbyRotation
+			Expect(rotation).ToNot(BeNil())
+			Expect(rotation.LaggingSince).To(HaveKey(cluster.Name + "-2"))
+			Expect(rotation.Deprioritized).To(BeEmpty())
+		})
+
+		By("replacing it once the delay expired", func() {
+			cluster = getUpdatedCluster(ctx)
+			cluster.Status.SynchronousStandbyRotation.LaggingSince[cluster.Name+"-2"] =
+				time.Now().Add(-2 * time.Minute).Format(metav1.RFC3339Micro)
+			Expect(env.client.Status().Update(ctx, cluster)).To(Succeed())
+
+			requeueAfter, err := env.clusterReconciler.reconcileSynchronousStandbyLag(
+				ctx, cluster, instancesStatus(standbys))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(requeueAfter).To(BeZero())
+
+			updatedCluster := getUpdatedCluster(ctx)
+			Expect(updatedCluster.Status.SynchronousStandbyRotation).ToNot(BeNil())
+			Expect(updatedCluster.Status.SynchronousStandbyRotation.LaggingSince).To(BeEmpty())
+			Expect(updatedCluster.GetDeprioritizedSynchronousStandbys()).To(ConsistOf(cluster.Name + "-2"))
+
+			recorder := env.clusterReconciler.Recorder.(*record.FakeRecorder)
+			Expect(recorder.Events).To(Receive(And(
+				ContainSubstring("SynchronousStandbyReplaced"),
+				ContainSubstring("replacing it with "+cluster.Name+"-3"),
+			)))
+		})
+
+		By("restoring its priority once it caught up", func() {
+			cluster = getUpdatedCluster(ctx)
+			standbys[cluster.Name+"-2"] = struct {
+				lag       int64
+				syncState string
+			}{lag: 0, syncState: "potential"}
+			standbys[cluster.Name+"-3"] = struct {
+				lag       int64
+				syncState string
+			}{lag: 0, syncState: "sync"}
+
+			_, err := env.clusterReconciler.reconcileSynchronousStandbyLag(
+				ctx, cluster, instancesStatus(standbys))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(getUpdatedCluster(ctx).Status.SynchronousStandbyRotation).To(BeNil())
+
+			recorder := env.clusterReconciler.Recorder.(*record.FakeRecorder)
+			Expect(recorder.Events).To(Receive(ContainSubstring("SynchronousStandbyCaughtUp")))
+		})
+	})
+
+	It("keeps the lagging synchronous standby when no replica can replace it", func(ctx SpecContext) {
+		standbys := map[string]struct {
+			lag       int64
+			syncState string
+		}{
+			cluster.Name + "-2": {lag: 128 * mi, syncState: "sync"},
+			cluster.Name + "-3": {lag: 256 * mi, syncState: "potential"},
+		}
+		cluster.Status.SynchronousStandbyRotation = &apiv1.SynchronousStandbyRotationStatus{
+			LaggingSince: map[string]string{
+				cluster.Name + "-2": time.Now().Add(-2 * time.Minute).Format(metav1.RFC3339Micro),
+			},
+		}
+		Expect(env.client.Status().Update(ctx, cluster)).To(Succeed())
+
+		requeueAfter, err := env.clusterReconciler.reconcileSynchronousStandbyLag(
+			ctx, cluster, instancesStatus(standbys))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requeueAfter).To(Equal(time.Minute))
+
+		rotation := getUpdatedCluster(ctx).Status.SynchronousStandbyRotation
+		Expect(rotation).ToNot(BeNil())
+		Expect(rotation.LaggingSince).To(HaveKey(cluster.Name + "-2"))
+		Expect(rotation.Deprioritized).To(BeEmpty())
+	})
+
+	It("clears the status when the lag rotation is disabled", func(ctx SpecContext) {
+		cluster.Status.SynchronousStandbyRotation = &apiv1.SynchronousStandbyRotationStatus{
+			Deprioritized: []string{cluster.Name + "-2"},
+		}
+		Expect(env.client.Status().Update(ctx, cluster)).To(Succeed())
+		cluster.Spec.PostgresConfiguration.Synchronous.LagRotation = nil
+
+		_, err := env.clusterReconciler.reconcileSynchronousStandbyLag(ctx, cluster, postgres.PostgresqlStatusList{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getUpdatedCluster(ctx).Status.SynchronousStandbyRotation).To(BeNil())
+	})
+})
@@ -42,6 +42,11 @@ import (
 // instance is not connected via streaming replication
 var errLogShippingReplicaElected = errors.New("log shipping replica elected as a new post-switchover primary")
 
+// errReplicasApplyingConfiguration is raised when the primary needs to be
+// restarted to apply a configuration change, but some replicas have not
+// applied it yet
+var errReplicasApplyingConfiguration = errors.New("replicas still applying the new configuration")
+
 // errRolloutDelayed is raised the a pod rollout have been delayed because
 // of the operator configuration
 var errRolloutDelayed = errors.New("pod rollout delayed")
@@ -122,6 +127,15 @@ func (r *ClusterReconciler) rolloutRequiredInstances(
 		return false, nil
 	}
 
+	// if the primary needs to be restarted to apply a configuration change,
+	// such as a change of shared_preload_libraries, the replicas must be
+	// restarted first, so that the new primary selected by the switchover
+	// is already running with the new configuration
+	if podRollout.needsConfigurationRestart &&
+		areReplicasApplyingConfiguration(cluster, podList, primaryPostgresqlStatus) {
+		return false, errReplicasApplyingConfiguration
+	}
+
 	managerResult := r.rolloutManager.CoordinateRollout(
 		client.ObjectKeyFromObject(cluster),
 		primaryPostgresqlStatus.Pod.Name)
@@ -141,6 +155,38 @@ func (r *ClusterReconciler) rolloutRequiredInstances(
 		podRollout.canBeInPlace, podRollout.primaryForceRecreate, podRollout.reason)
 }
 
+// areReplicasApplyingConfiguration checks whether any of the replicas has
+// still to load, or to be restarted to apply, the configuration the primary
+// is waiting to be restarted for
+func areReplicasApplyingConfiguration(
+	cluster *apiv1.Cluster,
+	podList *postgres.PostgresqlStatusList,
+	primaryStatus *postgres.PostgresqlStatus,
+) bool {
+	for _, item := range podList.Items {
+		if item.Pod.Name == primaryStatus.Pod.Name || cluster.IsInstanceFenced(item.Pod.Name) {
+			continue
+		}
+
+		if !item.IsPodReady || item.Error != nil {
+			continue
+		}
+
+		if item.PendingRestart {
+			return true
+		}
+
+		// Instance managers not reporting the configuration hash
+		// are not taken into account
+		if item.LoadedConfigurationHash != "" && primaryStatus.LoadedConfigurationHash != "" &&
+			item.LoadedConfigurationHash != primaryStatus.LoadedConfigurationHash {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (r *ClusterReconciler) updatePrimaryPod(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
@@ -218,6 +264,18 @@ func (r *ClusterReconciler) updatePrimaryPod(
 			return false, errLogShippingReplicaElected
 		}
 
+		blocked, err := r.isSwitchoverBlockedByBackup(ctx, cluster, targetInstance.Pod.Name)
+		if err != nil {
+			return false, err
+		}
+		if blocked {
+			return false, errSwitchoverBlockedByBackup
+		}
+
+		if err := r.checkSwitchoverCandidateCatchUp(ctx, cluster, *podList, targetInstance.Pod.Name); err != nil {
+			return false, err
+		}
+
 		contextLogger.Info("The primary needs to be restarted, we'll trigger a switchover to do that",
 			"reason", reason,
 			"currentPrimary", primaryPod.Name,
@@ -269,8 +327,9 @@ type rollout struct {
 	canBeInPlace         bool
 	primaryForceRecreate bool
 
-	needsChangeOperatorImage bool
-	needsChangeOperandImage  bool
+	needsChangeOperatorImage  bool
+	needsChangeOperandImage   bool
+	needsConfigurationRestart bool
 
 	reason string
 }
@@ -306,9 +365,10 @@ func isInstanceNeedingRollout(
 
 	if status.PendingRestart {
 		return rollout{
-			required:     true,
-			reason:       "Postgres needs a restart to apply some configuration changes",
-			canBeInPlace: true,
+			required:                  true,
+			reason:                    "Postgres needs a restart to apply some configuration changes",
+			canBeInPlace:              true,
+			needsConfigurationRestart: true,
 		}
 	}
 
@@ -20,11 +20,14 @@ import (
 	"encoding/json"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	rolloutManager "github.com/cloudnative-pg/cloudnative-pg/internal/controller/rollout"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
@@ -772,3 +775,104 @@ var _ = Describe("Cluster upgrade with podSpec reconciliation disabled", func()
 		Expect(rollout.reason).To(BeEmpty())
 	})
 })
+
+var _ = Describe("Rollout of a shared_preload_libraries change", func() {
+	const (
+		oldConfigurationHash = "old-configuration"
+		newConfigurationHash = "new-configuration"
+	)
+
+	var (
+		env     *testingEnvironment
+		cluster *apiv1.Cluster
+		pods    []corev1.Pod
+	)
+
+	BeforeEach(func() {
+		configuration.Current = configuration.NewConfiguration()
+		env = buildTestEnvironment()
+		env.clusterReconciler.rolloutManager = rolloutManager.New(0, 0)
+		cluster = newFakeCNPGCluster(env.client, newFakeNamespace(env.client), func(cluster *apiv1.Cluster) {
+			cluster.Spec.PrimaryUpdateStrategy = apiv1.PrimaryUpdateStrategyUnsupervised
+			cluster.Spec.PrimaryUpdateMethod = apiv1.PrimaryUpdateMethodSwitchover
+			cluster.Spec.PostgresConfiguration.Parameters = map[string]string{
+				"shared_preload_libraries": "pg_cron",
+			}
+			cluster.Status.Instances = cluster.Spec.Instances
+			cluster.Status.CurrentPrimary = cluster.Name + "-1"
+			cluster.Status.TargetPrimary = cluster.Name + "-1"
+		})
+		pods = generateFakeClusterPods(env.client, cluster, true)
+	})
+
+	// getStatusList builds the status of the instances, as reported after the
+	// primary loaded the configuration with the new preload libraries
+	getStatusList := func(replicasHash string, replicasPendingRestart bool) *postgres.PostgresqlStatusList {
+		statusList := &postgres.PostgresqlStatusList{}
+		for idx := range pods {
+			isPrimary := idx == 0
+			status := postgres.PostgresqlStatus{
+				Pod:                     &pods[idx],
+				IsPrimary:               isPrimary,
+				IsPodReady:              true,
+				IsWalReceiverActive:     !isPrimary,
+				ExecutableHash:          "test_hash",
+				PendingRestart:          true,
+				LoadedConfigurationHash: newConfigurationHash,
+			}
+			if !isPrimary {
+				status.PendingRestart = replicasPendingRestart
+				status.LoadedConfigurationHash = replicasHash
+			}
+			statusList.Items = append(statusList.Items, status)
+		}
+		return statusList
+	}
+
+	podExists := func(ctx SpecContext, pod corev1.Pod) bool {
+		err := env.client.Get(ctx, client.ObjectKeyFromObject(&pod), &corev1.Pod{})
+		if apierrs.IsNotFound(err) {
+			return false
+		}
+		Expect(err).ToNot(HaveOccurred())
+		return true
+	}
+
+	getTargetPrimary := func(ctx SpecContext) string {
+		var updatedCluster apiv1.Cluster
+		Expect(env.client.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		return updatedCluster.Status.TargetPrimary
+	}
+
+	It("doesn't touch the primary while the replicas haven't loaded the new configuration",
+		func(ctx SpecContext) {
+			done, err := env.clusterReconciler.rolloutRequiredInstances(ctx, cluster,
+				getStatusList(oldConfigurationHash, false))
+			Expect(err).To(MatchError(errReplicasApplyingConfiguration))
+			Expect(done).To(BeFalse())
+			Expect(podExists(ctx, pods[0])).To(BeTrue())
+			Expect(getTargetPrimary(ctx)).To(Equal(pods[0].Name))
+		})
+
+	It("restarts the replicas one at a time", func(ctx SpecContext) {
+		done, err := env.clusterReconciler.rolloutRequiredInstances(ctx, cluster,
+			getStatusList(newConfigurationHash, true))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(done).To(BeTrue())
+
+		Expect(podExists(ctx, pods[0])).To(BeTrue())
+		Expect(podExists(ctx, pods[1])).To(BeTrue())
+		Expect(podExists(ctx, pods[2])).To(BeFalse())
+	})
+
+	It("switches the primary over instead of restarting it in place", func(ctx SpecContext) {
+		done, err := env.clusterReconciler.rolloutRequiredInstances(ctx, cluster,
+			getStatusList(newConfigurationHash, false))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(done).To(BeTrue())
+
+		Expect(podExists(ctx, pods[0])).To(BeTrue())
+		Expect(pods[0].Annotations).ToNot(HaveKey(utils.ClusterRestartAnnotationName))
+		Expect(getTargetPrimary(ctx)).To(Equal(pods[1].Name))
+	})
+})
@@ -0,0 +1,157 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// reconcileWraparoundRisk updates the WraparoundRisk condition. When the
+// age of a database gets too close to the wraparound, PostgreSQL refuses
+// to assign new transaction IDs until the database is vacuumed, so this
+// needs to be known well in advance.
+func (r *ClusterReconciler) reconcileWraparoundRisk(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	condition := getWraparoundRiskCondition(cluster, instancesStatus)
+	if condition == nil {
+		return nil
+	}
+
+	if condition.Status == metav1.ConditionTrue &&
+		!meta.IsStatusConditionTrue(cluster.Status.Conditions, condition.Type) {
+		contextLogger.Warning("Wraparound risk detected",
+			"message", condition.Message,
+			"ages", getWraparoundAgesDetails(instancesStatus))
+		r.Recorder.Event(cluster, "Warning", condition.Reason, condition.Message)
+	}
+
+	return conditions.Patch(ctx, r.Client, cluster, condition)
+}
+
+// getWraparoundRiskCondition computes the WraparoundRisk condition from the
+// status reported by the primary instance, returning nil when the primary
+// didn't report its status
+func getWraparoundRiskCondition(
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) *metav1.Condition {
+	threshold := int64(cluster.Spec.Monitoring.GetWraparoundRiskThreshold())
+
+	for _, status := range instancesStatus.Items {
+		if !status.IsPrimary || status.Error != nil {
+			continue
+		}
+
+		ages := []struct {
+			name      string
+			age       int64
+			database  string
+			maxAge    int64
+			parameter string
+			reason    apiv1.ConditionReason
+		}{
+			{
+				"transaction ID",
+				status.OldestXIDAge,
+				status.OldestXIDAgeDatabase,
+				status.AutovacuumFreezeMaxAge,
+				"autovacuum_freeze_max_age",
+				apiv1.ConditionReasonTransactionIDAgeTooHigh,
+			},
+			{
+				"multixact ID",
+				status.OldestMXIDAge,
+				status.OldestMXIDAgeDatabase,
+				status.AutovacuumMultixactFreezeMaxAge,
+				"autovacuum_multixact_freeze_max_age",
+				apiv1.ConditionReasonMultixactIDAgeTooHigh,
+			},
+		}
+
+		var reason apiv1.ConditionReason
+		var messages []string
+		for _, age := range ages {
+			if age.maxAge <= 0 || age.age*100 <= age.maxAge*threshold {
+				continue
+			}
+
+			if reason == "" {
+				reason = age.reason
+			}
+			messages = append(messages, fmt.Sprintf(
+				"the oldest %s age of database %q on %s is above %d%% of %s",
+				age.name, age.database, status.Pod.Name, threshold, age.parameter))
+		}
+
+		if reason != "" {
+			return &metav1.Condition{
+				Type:   string(apiv1.ConditionWraparoundRisk),
+				Status: metav1.ConditionTrue,
+				Reason: string(reason),
+				Message: strings.Join(messages, "; ") +
+					". Vacuum the oldest databases to prevent the wraparound, " +
+					"checking for long-running transactions, stale prepared transactions " +
+					"and inactive replication slots holding back the xmin horizon",
+			}
+		}
+
+		return &metav1.Condition{
+			Type:   string(apiv1.ConditionWraparoundRisk),
+			Status: metav1.ConditionFalse,
+			Reason: string(apiv1.ConditionReasonNoWraparoundRisk),
+			Message: fmt.Sprintf(
+				"The transaction ID and multixact ID ages are below %d%% of the freeze max age",
+				threshold),
+		}
+	}
+
+	return nil
+}
+
+// getWraparoundAgesDetails returns the ages of the oldest transaction ID and
+// multixact ID reported by the primary, to be logged
+func getWraparoundAgesDetails(instancesStatus postgres.PostgresqlStatusList) map[string]interface{} {
+	for _, status := range instancesStatus.Items {
+		if !status.IsPrimary || status.Error != nil {
+			continue
+		}
+
+		return map[string]interface{}{
+			"oldestXIDAge":                    status.OldestXIDAge,
+			"autovacuumFreezeMaxAge":          status.AutovacuumFreezeMaxAge,
+			"oldestMXIDAge":                   status.OldestMXIDAge,
+			"autovacuumMultixactFreezeMaxAge": status.AutovacuumMultixactFreezeMaxAge,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("wraparound risk", func() {
+	const freezeMaxAge = 200000000
+
+	var (
+		env     *testingEnvironment
+		cluster *apiv1.Cluster
+	)
+
+	BeforeEach(func() {
+		env = buildTestEnvironment()
+		cluster = newFakeCNPGCluster(env.client, newFakeNamespace(env.client))
+	})
+
+	primaryStatus := func(xidAge int64, mxidAge int64) postgres.PostgresqlStatusList {
+		return postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{
+					Pod:                             &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: cluster.Name + "-1"}},
+					IsPrimary:                       true,
+					OldestXIDAge:                    xidAge,
+					OldestXIDAgeDatabase:            "app",
+					OldestMXIDAge:                   mxidAge,
+					OldestMXIDAgeDatabase:           "postgres",
+					AutovacuumFreezeMaxAge:          freezeMaxAge,
+					AutovacuumMultixactFreezeMaxAge: 2 * freezeMaxAge,
+				},
+				{
+					Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: cluster.Name + "-2"}},
+				},
+			},
+		}
+	}
+
+	getCondition := func(ctx SpecContext) *metav1.Condition {
+		var updatedCluster apiv1.Cluster
+		Expect(env.client.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		return meta.FindStatusCondition(updatedCluster.Status.Conditions,
+			string(apiv1.ConditionWraparoundRisk))
+	}
+
+	It("raises the condition and a warning when the transaction ID age is high", func(ctx SpecContext) {
+		Expect(env.clusterReconciler.reconcileWraparoundRisk(ctx, cluster, primaryStatus(400000000, 1000))).
+			To(Succeed())
+
+		condition := getCondition(ctx)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonTransactionIDAgeTooHigh)))
+		Expect(condition.Message).To(ContainSubstring(
+			`the oldest transaction ID age of database "app" on ` + cluster.Name +
+				"-1 is above 150% of autovacuum_freeze_max_age"))
+
+		recorder := env.clusterReconciler.Recorder.(*record.FakeRecorder)
+		Expect(recorder.Events).To(Receive(ContainSubstring(string(apiv1.ConditionReasonTransactionIDAgeTooHigh))))
+	})
+
+	It("keeps the condition stable while the age grows", func(ctx SpecContext) {
+		Expect(env.clusterReconciler.reconcileWraparoundRisk(ctx, cluster, primaryStatus(400000000, 1000))).
+			To(Succeed())
+		condition := getCondition(ctx)
+
+		Expect(env.clusterReconciler.reconcileWraparoundRisk(ctx, cluster, primaryStatus(410000000, 1000))).
+			To(Succeed())
+		updatedCondition := getCondition(ctx)
+		Expect(updatedCondition.Message).To(Equal(condition.Message))
+		Expect(updatedCondition.LastTransitionTime).To(Equal(condition.LastTransitionTime))
+	})
+
+	It("raises the condition when the multixact ID age is high", func(ctx SpecContext) {
+		Expect(env.clusterReconciler.reconcileWraparoundRisk(ctx, cluster, primaryStatus(1000, 800000000))).
+			To(Succeed())
+
+		condition := getCondition(ctx)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonMultixactIDAgeTooHigh)))
+	})
+
+	It("doesn't raise the condition while the ages are below the threshold", func(ctx SpecContext) {
+		Expect(env.clusterReconciler.reconcileWraparoundRisk(ctx, cluster, primaryStatus(250000000, 1000))).
+			To(Succeed())
+
+		condition := getCondition(ctx)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonNoWraparoundRisk)))
+	})
+
+	It("honors the configured threshold", func(ctx SpecContext) {
+		cluster.Spec.Monitoring = &apiv1.MonitoringConfiguration{WraparoundRiskThreshold: 100}
+		Expect(env.clusterReconciler.reconcileWraparoundRisk(ctx, cluster, primaryStatus(250000000, 1000))).
+			To(Succeed())
+
+		condition := getCondition(ctx)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonTransactionIDAgeTooHigh)))
+	})
+
+	It("leaves the condition untouched when the primary didn't report its status", func(ctx SpecContext) {
+		status := primaryStatus(400000000, 1000)
+		status.Items[0].IsPrimary = false
+		Expect(env.clusterReconciler.reconcileWraparoundRisk(ctx, cluster, status)).To(Succeed())
+		Expect(getCondition(ctx)).To(BeNil())
+	})
+})
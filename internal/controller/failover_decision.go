@@ -0,0 +1,127 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	pgTime "github.com/cloudnative-pg/machinery/pkg/postgres/time"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// recordFailoverDecision stores in the cluster status the context in
+// which the new primary has been selected, for post-incident review
+func (r *ClusterReconciler) recordFailoverDecision(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	decision *apiv1.FailoverDecision,
+) error {
+	origCluster := cluster.DeepCopy()
+	cluster.Status.LastFailoverDecision = decision
+	return r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// buildFailoverDecision describes the selection of the failover candidate,
+// reporting the status of every instance that has been considered
+func buildFailoverDecision(
+	cluster *apiv1.Cluster,
+	status postgres.PostgresqlStatusList,
+	candidate failoverCandidate,
+	resources *managedResources,
+) *apiv1.FailoverDecision {
+	mostAdvancedInstance := status.Items[0]
+	mostAdvancedLSN, _ := mostAdvancedInstance.ReceivedLsn.Parse()
+
+	decision := &apiv1.FailoverDecision{
+		DecisionTimestamp: pgTime.GetCurrentTimestamp(),
+		FormerPrimary:     cluster.Status.CurrentPrimary,
+		SelectedPrimary:   candidate.instance.Pod.Name,
+		Reason:            candidate.reason,
+		TopologyKey:       candidate.topologyKey,
+		Candidates:        make([]apiv1.FailoverCandidateStatus, 0, len(status.Items)),
+	}
+
+	for _, item := range status.Items {
+		candidateStatus := apiv1.FailoverCandidateStatus{
+			Name:        item.Pod.Name,
+			Ready:       item.IsPodReady,
+			ReceivedLSN: string(item.ReceivedLsn),
+			ReplayLSN:   string(item.ReplayLsn),
+		}
+		if receivedLSN, err := item.ReceivedLsn.Parse(); err == nil && mostAdvancedLSN > receivedLSN {
+			candidateStatus.LagBytes = mostAdvancedLSN - receivedLSN
+		}
+		if candidate.topologyKey != "" {
+			candidateStatus.FailureDomain, _ = getInstanceFailureDomain(
+				resources, candidate.topologyKey, item.Pod.Name)
+		}
+		candidateStatus.NotSelectedReason = getNotSelectedReason(
+			cluster, item, mostAdvancedInstance, candidate, candidateStatus.FailureDomain)
+
+		decision.Candidates = append(decision.Candidates, candidateStatus)
+	}
+
+	return decision
+}
+
+// getNotSelectedReason explains why an instance has not been selected
+// as the new primary, following the rules of getFailoverCandidate.
+// Returns an empty string for the selected instance.
+func getNotSelectedReason(
+	cluster *apiv1.Cluster,
+	item postgres.PostgresqlStatus,
+	mostAdvancedInstance postgres.PostgresqlStatus,
+	candidate failoverCandidate,
+	failureDomain string,
+) string {
+	isLocalCandidate := candidate.topologyKey != "" &&
+		candidate.primaryFailureDomain != "" &&
+		candidate.failureDomain == candidate.primaryFailureDomain
+
+	switch {
+	case item.Pod.Name == candidate.instance.Pod.Name:
+		return ""
+
+	case item.Pod.Name == cluster.Status.CurrentPrimary:
+		return "failing primary"
+
+	case item.Error != nil:
+		return fmt.Sprintf("not reporting its status: %v", item.Error)
+
+	case item.ReceivedLsn != mostAdvancedInstance.ReceivedLsn ||
+		item.ReplayLsn != mostAdvancedInstance.ReplayLsn:
+		return "behind the most advanced instance"
+
+	case candidate.reason == failoverReasonKeptTarget:
+		return "the target primary already selected has been kept"
+
+	case isLocalCandidate && failureDomain != candidate.primaryFailureDomain:
+		return fmt.Sprintf("running in the %q failure domain, while the former primary was in %q",
+			failureDomain, candidate.primaryFailureDomain)
+
+	case candidate.primaryFailureDomain != "" && failureDomain == candidate.primaryFailureDomain &&
+		(!item.IsPodReady || !item.HasHTTPStatus()):
+		return "not ready"
+
+	default:
+		return "same WAL position as the selected instance, which comes first by name"
+	}
+}
@@ -0,0 +1,218 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/instance"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs/pgbouncer"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("inherited labels", func() {
+	const (
+		costCenterLabel = "cost-center"
+		costCenterValue = "finance"
+	)
+
+	var (
+		env       *testingEnvironment
+		namespace string
+		cluster   *apiv1.Cluster
+	)
+
+	haveInheritedLabel := HaveKeyWithValue(costCenterLabel, costCenterValue)
+
+	BeforeEach(func() {
+		env = buildTestEnvironment()
+		namespace = newFakeNamespace(env.client)
+		cluster = newFakeCNPGCluster(env.client, namespace, func(cluster *apiv1.Cluster) {
+			cluster.Spec.Certificates = nil
+			cluster.Spec.InheritedMetadata = &apiv1.EmbeddedObjectMetadata{
+				Labels: map[string]string{costCenterLabel: costCenterValue},
+			}
+		})
+	})
+
+	It("are set on every resource generated for the cluster", func(ctx SpecContext) {
+		Expect(env.clusterReconciler.createPostgresClusterObjects(ctx, cluster)).To(Succeed())
+
+		By("checking the secrets", func() {
+			var secrets corev1.SecretList
+			Expect(env.client.List(ctx, &secrets, client.InNamespace(namespace))).To(Succeed())
+			Expect(secrets.Items).ToNot(BeEmpty())
+			for _, secret := range secrets.Items {
+				Expect(secret.Labels).To(haveInheritedLabel, "secret %s", secret.Name)
+			}
+		})
+
+		By("checking the services", func() {
+			var services corev1.ServiceList
+			Expect(env.client.List(ctx, &services, client.InNamespace(namespace))).To(Succeed())
+			Expect(services.Items).ToNot(BeEmpty())
+			for _, service := range services.Items {
+				Expect(service.Labels).To(haveInheritedLabel, "service %s", service.Name)
+			}
+		})
+
+		By("checking the pod disruption budgets", func() {
+			var pdbs policyv1.PodDisruptionBudgetList
+			Expect(env.client.List(ctx, &pdbs, client.InNamespace(namespace))).To(Succeed())
+			Expect(pdbs.Items).ToNot(BeEmpty())
+			for _, pdb := range pdbs.Items {
+				Expect(pdb.Labels).To(haveInheritedLabel, "pdb %s", pdb.Name)
+			}
+		})
+
+		By("checking the RBAC resources", func() {
+			key := client.ObjectKey{Namespace: namespace, Name: cluster.Name}
+
+			var serviceAccount corev1.ServiceAccount
+			Expect(env.client.Get(ctx, key, &serviceAccount)).To(Succeed())
+			Expect(serviceAccount.Labels).To(haveInheritedLabel)
+
+			var role rbacv1.Role
+			Expect(env.client.Get(ctx, key, &role)).To(Succeed())
+			Expect(role.Labels).To(haveInheritedLabel)
+
+			var roleBinding rbacv1.RoleBinding
+			Expect(env.client.Get(ctx, key, &roleBinding)).To(Succeed())
+			Expect(roleBinding.Labels).To(haveInheritedLabel)
+		})
+
+		By("checking the pod monitor", func() {
+			podMonitor := specs.NewClusterPodMonitorManager(cluster).BuildPodMonitor()
+			Expect(podMonitor.Labels).To(haveInheritedLabel)
+		})
+
+		By("checking the jobs", func() {
+			job := specs.CreatePrimaryJobViaInitdb(*cluster, 1)
+			Expect(job.Labels).To(haveInheritedLabel)
+		})
+
+		By("checking the PVCs", func() {
+			pvc, err := persistentvolumeclaim.Build(cluster, &persistentvolumeclaim.CreateConfiguration{
+				Status:     persistentvolumeclaim.StatusReady,
+				NodeSerial: 1,
+				Calculator: persistentvolumeclaim.NewPgDataCalculator(),
+				Storage:    cluster.Spec.StorageConfiguration,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pvc.Labels).To(haveInheritedLabel)
+		})
+
+		By("checking the pods", func() {
+			pod := specs.PodWithExistingStorage(*cluster, 1)
+			Expect(env.client.Create(ctx, pod)).To(Succeed())
+			Expect(instance.ReconcileMetadata(ctx, env.client, cluster, []corev1.Pod{*pod})).To(Succeed())
+
+			var updatedPod corev1.Pod
+			Expect(env.client.Get(ctx, client.ObjectKeyFromObject(pod), &updatedPod)).To(Succeed())
+			Expect(updatedPod.Labels).To(haveInheritedLabel)
+		})
+	})
+
+	It("are set on every resource generated for a pooler", func(ctx SpecContext) {
+		pooler := newFakePooler(env.client, cluster)
+		res := &poolerManagedResources{Cluster: cluster}
+		Expect(env.poolerReconciler.updateOwnedObjects(ctx, pooler, res)).To(Succeed())
+
+		assertPoolerLabels(ctx, env.client, pooler, costCenterLabel, costCenterValue)
+
+		podMonitor := pgbouncer.NewPoolerPodMonitorManager(pooler, cluster).BuildPodMonitor()
+		Expect(podMonitor.Labels).To(haveInheritedLabel)
+		Expect(podMonitor.Spec.Selector.MatchLabels).To(Equal(map[string]string{
+			utils.PgbouncerNameLabel: pooler.Name,
+		}))
+	})
+
+	It("are added to the resources that already exist", func(ctx SpecContext) {
+		pooler := newFakePooler(env.client, cluster)
+		Expect(env.clusterReconciler.setupPostgresPKI(ctx, cluster)).To(Succeed())
+		Expect(env.poolerReconciler.updateOwnedObjects(ctx, pooler,
+			&poolerManagedResources{Cluster: cluster})).To(Succeed())
+
+		cluster.Spec.InheritedMetadata.Labels["team"] = "payments"
+
+		By("reconciling the certificates", func() {
+			Expect(env.clusterReconciler.setupPostgresPKI(ctx, cluster)).To(Succeed())
+
+			var secrets corev1.SecretList
+			Expect(env.client.List(ctx, &secrets, client.InNamespace(namespace))).To(Succeed())
+			Expect(secrets.Items).ToNot(BeEmpty())
+			for _, secret := range secrets.Items {
+				if _, owned := IsOwnedByCluster(&secret); !owned {
+					continue
+				}
+				Expect(secret.Labels).To(HaveKeyWithValue("team", "payments"), "secret %s", secret.Name)
+			}
+		})
+
+		By("reconciling the pooler resources", func() {
+			res, err := env.poolerReconciler.getManagedResources(ctx, pooler)
+			Expect(err).ToNot(HaveOccurred())
+			res.Cluster = cluster
+			Expect(env.poolerReconciler.updateOwnedObjects(ctx, pooler, res)).To(Succeed())
+
+			assertPoolerLabels(ctx, env.client, pooler, "team", "payments")
+		})
+	})
+})
+
+func assertPoolerLabels(
+	ctx context.Context,
+	cli client.Client,
+	pooler *apiv1.Pooler,
+	key, value string,
+) {
+	objectKey := client.ObjectKeyFromObject(pooler)
+
+	var deployment appsv1.Deployment
+	Expect(cli.Get(ctx, objectKey, &deployment)).To(Succeed())
+	Expect(deployment.Labels).To(HaveKeyWithValue(key, value))
+	Expect(deployment.Spec.Template.Labels).To(HaveKeyWithValue(key, value))
+	Expect(deployment.Spec.Selector.MatchLabels).ToNot(HaveKey(key))
+
+	var service corev1.Service
+	Expect(cli.Get(ctx, objectKey, &service)).To(Succeed())
+	Expect(service.Labels).To(HaveKeyWithValue(key, value))
+
+	var serviceAccount corev1.ServiceAccount
+	Expect(cli.Get(ctx, objectKey, &serviceAccount)).To(Succeed())
+	Expect(serviceAccount.Labels).To(HaveKeyWithValue(key, value))
+
+	var role rbacv1.Role
+	Expect(cli.Get(ctx, objectKey, &role)).To(Succeed())
+	Expect(role.Labels).To(HaveKeyWithValue(key, value))
+
+	var roleBinding rbacv1.RoleBinding
+	Expect(cli.Get(ctx, objectKey, &roleBinding)).To(Succeed())
+	Expect(roleBinding.Labels).To(HaveKeyWithValue(key, value))
+}
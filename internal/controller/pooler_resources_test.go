@@ -165,7 +165,7 @@ var _ = Describe("pooler_resources unit tests", func() {
 		})
 
 		By("creating the role", func() {
-			role := pgbouncer.Role(pooler)
+			role := pgbouncer.Role(pooler, cluster)
 			err := env.poolerReconciler.Create(ctx, role)
 			Expect(err).ToNot(HaveOccurred())
 		})
@@ -191,7 +191,7 @@ var _ = Describe("pooler_resources unit tests", func() {
 		})
 
 		By("creating the roleBinding", func() {
-			roleBinding := pgbouncer.RoleBinding(pooler)
+			roleBinding := pgbouncer.RoleBinding(pooler, cluster)
 			err := env.poolerReconciler.Create(ctx, &roleBinding)
 			Expect(err).ToNot(HaveOccurred())
 		})
@@ -218,7 +218,7 @@ var _ = Describe("pooler_resources unit tests", func() {
 		})
 
 		By("creating the SA", func() {
-			serviceAccount := pgbouncer.ServiceAccount(pooler)
+			serviceAccount := pgbouncer.ServiceAccount(pooler, cluster)
 			err := env.poolerReconciler.Create(ctx, serviceAccount)
 			Expect(err).ToNot(HaveOccurred())
 		})
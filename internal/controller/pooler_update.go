@@ -22,6 +22,7 @@ import (
 	"reflect"
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -56,7 +57,7 @@ func (r *PoolerReconciler) updateOwnedObjects(
 		return err
 	}
 
-	return createOrPatchPodMonitor(ctx, r.Client, r.DiscoveryClient, pgbouncer.NewPoolerPodMonitorManager(pooler))
+	return createOrPatchPodMonitor(ctx, r.Client, r.DiscoveryClient, pgbouncer.NewPoolerPodMonitorManager(pooler, resources.Cluster))
 }
 
 // updateDeployment update the deployment or create it when needed
@@ -92,7 +93,7 @@ func (r *PoolerReconciler) updateDeployment(
 	case resources.Deployment != nil:
 		currentVersion := resources.Deployment.Annotations[utils.PoolerSpecHashAnnotationName]
 		updatedVersion := generatedDeployment.Annotations[utils.PoolerSpecHashAnnotationName]
-		if currentVersion == updatedVersion {
+		if currentVersion == updatedVersion && deploymentHasLabels(resources.Deployment, generatedDeployment) {
 			// Everything fine, the two deployments are using the
 			// same specifications
 			return nil
@@ -121,6 +122,13 @@ func (r *PoolerReconciler) updateDeployment(
 	return nil
 }
 
+// deploymentHasLabels checks if the current deployment, and its pod template,
+// already carry every label of the generated one
+func deploymentHasLabels(current, generated *appsv1.Deployment) bool {
+	return utils.IsMapSubset(current.Labels, generated.Labels) &&
+		utils.IsMapSubset(current.Spec.Template.Labels, generated.Spec.Template.Labels)
+}
+
 // reconcileService update or create the pgbouncer service as needed
 func (r *PoolerReconciler) reconcileService(
 	ctx context.Context,
@@ -168,7 +176,7 @@ func (r *PoolerReconciler) updateRBAC(
 ) error {
 	contextLog := log.FromContext(ctx)
 
-	role := pgbouncer.Role(pooler)
+	role := pgbouncer.Role(pooler, resources.Cluster)
 	if resources.Role == nil {
 		if err := ctrl.SetControllerReference(pooler, role, r.Scheme); err != nil {
 			return err
@@ -180,15 +188,17 @@ func (r *PoolerReconciler) updateRBAC(
 			return err
 		}
 		resources.Role = role
-	} else if !reflect.DeepEqual(role.Rules, resources.Role.Rules) {
+	} else if !reflect.DeepEqual(role.Rules, resources.Role.Rules) ||
+		!utils.IsMapSubset(resources.Role.Labels, role.Labels) {
 		contextLog.Info("Updating role")
 		resources.Role.Rules = role.Rules
+		utils.MergeObjectsMetadata(resources.Role, role)
 		if err := r.Update(ctx, resources.Role); err != nil {
 			return err
 		}
 	}
 
-	roleBinding := pgbouncer.RoleBinding(pooler)
+	roleBinding := pgbouncer.RoleBinding(pooler, resources.Cluster)
 	if resources.RoleBinding == nil {
 		if err := ctrl.SetControllerReference(pooler, &roleBinding, r.Scheme); err != nil {
 			return err
@@ -200,9 +210,11 @@ func (r *PoolerReconciler) updateRBAC(
 		}
 		resources.RoleBinding = &roleBinding
 	} else if !reflect.DeepEqual(roleBinding.Subjects, resources.RoleBinding.Subjects) ||
-		!reflect.DeepEqual(roleBinding.RoleRef, resources.RoleBinding.RoleRef) {
+		!reflect.DeepEqual(roleBinding.RoleRef, resources.RoleBinding.RoleRef) ||
+		!utils.IsMapSubset(resources.RoleBinding.Labels, roleBinding.Labels) {
 		resources.RoleBinding.RoleRef = roleBinding.RoleRef
 		resources.RoleBinding.Subjects = roleBinding.Subjects
+		utils.MergeObjectsMetadata(resources.RoleBinding, &roleBinding)
 		if err := r.Update(ctx, resources.RoleBinding); err != nil {
 			return err
 		}
@@ -216,6 +228,7 @@ func (r *PoolerReconciler) updateRBAC(
 //
 //   - the ServiceAccount exits
 //   - it contains the ImagePullSecret if required
+//   - it carries the labels inherited from the cluster
 //
 // Any other property of the ServiceAccount is preserved
 func (r *PoolerReconciler) updateServiceAccount(
@@ -230,8 +243,8 @@ func (r *PoolerReconciler) updateServiceAccount(
 		return err
 	}
 
+	serviceAccount := pgbouncer.ServiceAccount(pooler, resources.Cluster)
 	if resources.ServiceAccount == nil {
-		serviceAccount := pgbouncer.ServiceAccount(pooler)
 		ensureServiceAccountHaveImagePullSecret(resources.ServiceAccount, pullSecretName)
 		contextLog.Info("Creating service account")
 		if err := ctrl.SetControllerReference(pooler, serviceAccount, r.Scheme); err != nil {
@@ -246,6 +259,9 @@ func (r *PoolerReconciler) updateServiceAccount(
 
 	origServiceAccount := resources.ServiceAccount.DeepCopy()
 	ensureServiceAccountHaveImagePullSecret(resources.ServiceAccount, pullSecretName)
+	if !utils.IsMapSubset(resources.ServiceAccount.Labels, serviceAccount.Labels) {
+		utils.MergeObjectsMetadata(resources.ServiceAccount, serviceAccount)
+	}
 	if !reflect.DeepEqual(origServiceAccount, resources.ServiceAccount) {
 		contextLog.Info("Updating service account")
 		if err := r.Patch(ctx, resources.ServiceAccount, client.MergeFrom(origServiceAccount)); err != nil {
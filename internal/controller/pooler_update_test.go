@@ -207,14 +207,14 @@ var _ = Describe("unit test of pooler_update reconciliation logic", func() {
 			err := env.poolerReconciler.updateRBAC(ctx, pooler, res)
 			Expect(err).ToNot(HaveOccurred())
 
-			expectedRole := pgbouncer.Role(pooler)
+			expectedRole := pgbouncer.Role(pooler, cluster)
 			role := &rbacv1.Role{}
 			err = env.client.Get(ctx, types.NamespacedName{Name: expectedRole.Name, Namespace: expectedRole.Namespace}, role)
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(expectedRole.Rules).To(Equal(role.Rules))
 
-			expectedRb := pgbouncer.RoleBinding(pooler)
+			expectedRb := pgbouncer.RoleBinding(pooler, cluster)
 			roleBinding := &rbacv1.RoleBinding{}
 			err = env.client.Get(ctx, types.NamespacedName{Name: expectedRb.Name, Namespace: expectedRb.Namespace}, roleBinding)
 			Expect(err).ToNot(HaveOccurred())
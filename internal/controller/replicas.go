@@ -94,7 +94,8 @@ func (r *ClusterReconciler) reconcileTargetPrimaryForNonReplicaCluster(
 	contextLogger := log.FromContext(ctx)
 
 	mostAdvancedInstance := status.Items[0]
-	if cluster.Status.TargetPrimary == mostAdvancedInstance.Pod.Name {
+	candidate := getFailoverCandidate(cluster, status, resources)
+	if cluster.Status.TargetPrimary == candidate.instance.Pod.Name {
 		return "", nil
 	}
 
@@ -122,6 +123,10 @@ func (r *ClusterReconciler) reconcileTargetPrimaryForNonReplicaCluster(
 			fmt.Sprintf("Initiating a failover from %v", cluster.Status.CurrentPrimary)); err != nil {
 			return "", err
 		}
+		if err := r.abortRunningBackups(ctx, cluster); err != nil {
+			// The failover must not wait for the backups
+			contextLogger.Error(err, "while aborting the running backups")
+		}
 		err := r.setPrimaryInstance(ctx, cluster, apiv1.PendingFailoverMarker)
 		if err != nil {
 			return "", err
@@ -137,33 +142,156 @@ func (r *ClusterReconciler) reconcileTargetPrimaryForNonReplicaCluster(
 	// This may be tha last step of a failover if target primary is set to apiv1.PendingFailoverMarker
 	// or change the target primary if the current one is not valid anymore.
 	if cluster.Status.TargetPrimary == apiv1.PendingFailoverMarker {
-		contextLogger.Info("Failing over", "newPrimary", mostAdvancedInstance.Pod.Name)
+		contextLogger.Info("Failing over", "newPrimary", candidate.instance.Pod.Name)
 		status.LogStatus(ctx)
 		contextLogger.Debug("Cluster status before failover", "instances", resources.instances)
 		r.Recorder.Eventf(cluster, "Normal", "FailoverTarget",
 			"Failing over from %v to %v",
-			cluster.Status.CurrentPrimary, mostAdvancedInstance.Pod.Name)
+			cluster.Status.CurrentPrimary, candidate.instance.Pod.Name)
 		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseFailOver,
-			fmt.Sprintf("Failing over from %v to %v", cluster.Status.CurrentPrimary, mostAdvancedInstance.Pod.Name),
+			fmt.Sprintf("Failing over from %v to %v", cluster.Status.CurrentPrimary, candidate.instance.Pod.Name),
+		); err != nil {
+			return "", err
+		}
+		if err := r.recordFailoverDecision(
+			ctx, cluster, buildFailoverDecision(cluster, status, candidate, resources),
 		); err != nil {
 			return "", err
 		}
 	} else {
 		contextLogger.Info("Target primary isn't healthy, switching target",
-			"newPrimary", mostAdvancedInstance.Pod.Name)
+			"newPrimary", candidate.instance.Pod.Name)
 		status.LogStatus(ctx)
 		contextLogger.Debug("Cluster status before switching target", "instances", resources.instances)
 		r.Recorder.Eventf(cluster, "Normal", "FailingOver",
 			"Target primary isn't healthy, switching target from %v to %v",
-			cluster.Status.TargetPrimary, mostAdvancedInstance.Pod.Name)
+			cluster.Status.TargetPrimary, candidate.instance.Pod.Name)
 		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseSwitchover,
-			fmt.Sprintf("Switching over to %v", mostAdvancedInstance.Pod.Name)); err != nil {
+			fmt.Sprintf("Switching over to %v", candidate.instance.Pod.Name)); err != nil {
 			return "", err
 		}
 	}
 
-	// Set the first pod in the sorted list as the new targetPrimary
-	return mostAdvancedInstance.Pod.Name, r.setPrimaryInstance(ctx, cluster, mostAdvancedInstance.Pod.Name)
+	if candidate.topologyKey != "" {
+		r.Recorder.Eventf(cluster, "Normal", "FailoverCandidate",
+			"Selected %v as the new primary, running in the %v %q failure domain (former primary in %q)",
+			candidate.instance.Pod.Name, candidate.topologyKey, candidate.failureDomain,
+			candidate.primaryFailureDomain)
+	}
+
+	// Set the selected failover candidate as the new targetPrimary
+	return candidate.instance.Pod.Name, r.setPrimaryInstance(ctx, cluster, candidate.instance.Pod.Name)
+}
+
+// failoverCandidate is the instance selected to be promoted
+// during a failover
+type failoverCandidate struct {
+	instance postgres.PostgresqlStatus
+
+	// topologyKey is the node label defining the failure domain, empty
+	// when the failure domain is not taken into account
+	topologyKey string
+
+	// failureDomain is the failure domain of the candidate
+	failureDomain string
+
+	// primaryFailureDomain is the failure domain of the former primary
+	primaryFailureDomain string
+
+	// reason explains why the instance has been selected
+	reason string
+}
+
+const (
+	failoverReasonMostAdvanced = "most advanced instance"
+	failoverReasonKeptTarget   = "target primary already selected, still healthy and caught up"
+	failoverReasonSameDomain   = "most advanced healthy instance in the failure domain of the former primary"
+	failoverReasonNoSameDomain = "most advanced instance, as no healthy and caught up replica " +
+		"runs in the failure domain of the former primary"
+	failoverReasonUnknownDomain = "most advanced instance, as the failure domain of the former primary is unknown"
+)
+
+// getFailoverCandidate selects the instance to be promoted. Unless the
+// failover topology is configured, this is the most advanced instance.
+// Otherwise, a healthy replica running in the same failure domain of the
+// former primary is preferred, provided it has received and replayed the
+// same WAL of the most advanced instance
+func getFailoverCandidate(
+	cluster *apiv1.Cluster,
+	status postgres.PostgresqlStatusList,
+	resources *managedResources,
+) failoverCandidate {
+	mostAdvancedInstance := status.Items[0]
+	result := failoverCandidate{instance: mostAdvancedInstance, reason: failoverReasonMostAdvanced}
+
+	topologyKey := cluster.GetFailoverTopologyKey()
+	if topologyKey == "" || mostAdvancedInstance.IsPrimary {
+		return result
+	}
+
+	getFailureDomain := func(podName string) (string, bool) {
+		return getInstanceFailureDomain(resources, topologyKey, podName)
+	}
+
+	result.topologyKey = topologyKey
+	result.failureDomain, _ = getFailureDomain(mostAdvancedInstance.Pod.Name)
+	primaryFailureDomain, ok := getFailureDomain(cluster.Status.CurrentPrimary)
+	if !ok {
+		result.reason = failoverReasonUnknownDomain
+		return result
+	}
+	result.primaryFailureDomain = primaryFailureDomain
+
+	isEligible := func(item postgres.PostgresqlStatus) bool {
+		isHealthy := item.Error == nil && item.IsPodReady && item.HasHTTPStatus()
+		isCaughtUp := item.ReceivedLsn == mostAdvancedInstance.ReceivedLsn &&
+			item.ReplayLsn == mostAdvancedInstance.ReplayLsn
+		return item.Pod.Name != cluster.Status.CurrentPrimary && isHealthy && isCaughtUp
+	}
+
+	// A target primary that has already been selected is kept
+	// as long as it is eligible, to avoid changing it again
+	for _, item := range status.Items {
+		if item.Pod.Name == cluster.Status.TargetPrimary && isEligible(item) {
+			result.instance = item
+			result.failureDomain, _ = getFailureDomain(item.Pod.Name)
+			result.reason = failoverReasonKeptTarget
+			return result
+		}
+	}
+
+	for _, item := range status.Items {
+		if !isEligible(item) {
+			continue
+		}
+		if failureDomain, ok := getFailureDomain(item.Pod.Name); ok && failureDomain == primaryFailureDomain {
+			result.instance = item
+			result.failureDomain = failureDomain
+			result.reason = failoverReasonSameDomain
+			return result
+		}
+	}
+
+	result.reason = failoverReasonNoSameDomain
+	return result
+}
+
+// getInstanceFailureDomain gets the failure domain of an instance, i.e.
+// the value of the topology key label of the node it is running on
+func getInstanceFailureDomain(resources *managedResources, topologyKey, podName string) (string, bool) {
+	for idx := range resources.instances.Items {
+		pod := &resources.instances.Items[idx]
+		if pod.Name != podName {
+			continue
+		}
+		node, ok := resources.nodes[pod.Spec.NodeName]
+		if !ok {
+			return "", false
+		}
+		failureDomain, ok := node.Labels[topologyKey]
+		return failureDomain, ok
+	}
+	return "", false
 }
 
 // isNodeUnschedulable checks whether a node is set to unschedulable
@@ -230,6 +358,18 @@ func (r *ClusterReconciler) setPrimaryOnSchedulableNode(
 			continue
 		}
 
+		blocked, err := r.isSwitchoverBlockedByBackup(ctx, cluster, candidate.Pod.Name)
+		if err != nil {
+			return "", err
+		}
+		if blocked {
+			return "", nil
+		}
+
+		if err := r.checkSwitchoverCandidateCatchUp(ctx, cluster, status, candidate.Pod.Name); err != nil {
+			return "", err
+		}
+
 		// Set the current candidate as targetPrimary
 		contextLogger.Info("Current primary is running on unschedulable node, triggering a switchover",
 			"currentPrimary", primaryPod.Pod.Name, "currentPrimaryNode", primaryPod.Node,
@@ -17,6 +17,9 @@ limitations under the License.
 package controller
 
 import (
+	"errors"
+
+	"github.com/cloudnative-pg/machinery/pkg/types"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -151,3 +154,197 @@ var _ = Describe("Check pods not on primary node", func() {
 		Expect(GetPodsNotOnPrimaryNode(statusList2, &statusList2.Items[0]).Items).ToNot(BeEmpty())
 	})
 })
+
+var _ = Describe("Failover candidate selection", func() {
+	const zoneLabel = "topology.kubernetes.io/zone"
+
+	var (
+		cluster   *apiv1.Cluster
+		resources *managedResources
+	)
+
+	newNode := func(name, zone string) corev1.Node {
+		return corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{zoneLabel: zone},
+			},
+		}
+	}
+
+	newPod := func(name, nodeName string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       corev1.PodSpec{NodeName: nodeName},
+		}
+	}
+
+	newReplicaStatus := func(pod *corev1.Pod, lsn string) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod:         pod,
+			IsPodReady:  true,
+			ReceivedLsn: types.LSN(lsn),
+			ReplayLsn:   types.LSN(lsn),
+		}
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				FailoverTopology: &apiv1.FailoverTopologyConfiguration{TopologyKey: zoneLabel},
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-1",
+				TargetPrimary:  apiv1.PendingFailoverMarker,
+			},
+		}
+		resources = &managedResources{
+			nodes: map[string]corev1.Node{
+				"node-a1": newNode("node-a1", "zone-a"),
+				"node-a2": newNode("node-a2", "zone-a"),
+				"node-b":  newNode("node-b", "zone-b"),
+				"node-c":  newNode("node-c", "zone-c"),
+			},
+			instances: corev1.PodList{
+				Items: []corev1.Pod{
+					newPod("cluster-1", "node-a1"),
+					newPod("cluster-2", "node-b"),
+					newPod("cluster-3", "node-c"),
+					newPod("cluster-4", "node-a2"),
+				},
+			},
+		}
+	})
+
+	// statusList returns the sorted status of the cluster, with the
+	// primary being unreachable
+	statusList := func(mutators ...func(items []postgres.PostgresqlStatus)) postgres.PostgresqlStatusList {
+		pods := resources.instances.Items
+		items := []postgres.PostgresqlStatus{
+			newReplicaStatus(&pods[1], "0/6000000"),
+			newReplicaStatus(&pods[2], "0/6000000"),
+			newReplicaStatus(&pods[3], "0/6000000"),
+			{Pod: &pods[0], Error: errors.New("unreachable")},
+		}
+		for _, mutator := range mutators {
+			mutator(items)
+		}
+		return postgres.PostgresqlStatusList{Items: items}
+	}
+
+	It("selects the most advanced instance when the topology is not configured", func() {
+		cluster.Spec.FailoverTopology = nil
+		candidate := getFailoverCandidate(cluster, statusList(), resources)
+		Expect(candidate.instance.Pod.Name).To(Equal("cluster-2"))
+		Expect(candidate.topologyKey).To(BeEmpty())
+	})
+
+	It("prefers a replica in the failure domain of the former primary", func() {
+		candidate := getFailoverCandidate(cluster, statusList(), resources)
+		Expect(candidate.instance.Pod.Name).To(Equal("cluster-4"))
+		Expect(candidate.topologyKey).To(Equal(zoneLabel))
+		Expect(candidate.failureDomain).To(Equal("zone-a"))
+		Expect(candidate.primaryFailureDomain).To(Equal("zone-a"))
+	})
+
+	It("falls back to another failure domain when the local replica is not ready", func() {
+		candidate := getFailoverCandidate(cluster, statusList(func(items []postgres.PostgresqlStatus) {
+			items[2].IsPodReady = false
+		}), resources)
+		Expect(candidate.instance.Pod.Name).To(Equal("cluster-2"))
+		Expect(candidate.failureDomain).To(Equal("zone-b"))
+	})
+
+	It("falls back to another failure domain when the local replica is lagging", func() {
+		candidate := getFailoverCandidate(cluster, statusList(func(items []postgres.PostgresqlStatus) {
+			items[2].ReceivedLsn = "0/5000000"
+			items[2].ReplayLsn = "0/5000000"
+		}), resources)
+		Expect(candidate.instance.Pod.Name).To(Equal("cluster-2"))
+	})
+
+	It("keeps the target primary that has already been selected", func() {
+		cluster.Status.TargetPrimary = "cluster-3"
+		candidate := getFailoverCandidate(cluster, statusList(), resources)
+		Expect(candidate.instance.Pod.Name).To(Equal("cluster-3"))
+		Expect(candidate.failureDomain).To(Equal("zone-c"))
+	})
+
+	It("selects the primary when it is healthy", func() {
+		cluster.Status.TargetPrimary = "cluster-1"
+		pods := resources.instances.Items
+		list := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			{Pod: &pods[0], IsPrimary: true, IsPodReady: true},
+			newReplicaStatus(&pods[3], "0/6000000"),
+		}}
+		Expect(getFailoverCandidate(cluster, list, resources).instance.Pod.Name).To(Equal("cluster-1"))
+	})
+
+	It("selects the most advanced instance when the node of the former primary is unknown", func() {
+		delete(resources.nodes, "node-a1")
+		candidate := getFailoverCandidate(cluster, statusList(), resources)
+		Expect(candidate.instance.Pod.Name).To(Equal("cluster-2"))
+		Expect(candidate.reason).To(Equal(failoverReasonUnknownDomain))
+	})
+
+	It("records the rationale of the failover decision", func() {
+		status := statusList(func(items []postgres.PostgresqlStatus) {
+			items[1].ReceivedLsn = "0/5000000"
+			items[1].ReplayLsn = "0/5000000"
+		})
+		candidate := getFailoverCandidate(cluster, status, resources)
+		decision := buildFailoverDecision(cluster, status, candidate, resources)
+
+		Expect(decision.DecisionTimestamp).ToNot(BeEmpty())
+		Expect(decision.FormerPrimary).To(Equal("cluster-1"))
+		Expect(decision.SelectedPrimary).To(Equal("cluster-4"))
+		Expect(decision.Reason).To(Equal(failoverReasonSameDomain))
+		Expect(decision.TopologyKey).To(Equal(zoneLabel))
+		Expect(decision.Candidates).To(Equal([]apiv1.FailoverCandidateStatus{
+			{
+				Name:              "cluster-2",
+				Ready:             true,
+				ReceivedLSN:       "0/6000000",
+				ReplayLSN:         "0/6000000",
+				FailureDomain:     "zone-b",
+				NotSelectedReason: `running in the "zone-b" failure domain, while the former primary was in "zone-a"`,
+			},
+			{
+				Name:              "cluster-3",
+				Ready:             true,
+				ReceivedLSN:       "0/5000000",
+				ReplayLSN:         "0/5000000",
+				LagBytes:          0x1000000,
+				FailureDomain:     "zone-c",
+				NotSelectedReason: "behind the most advanced instance",
+			},
+			{
+				Name:          "cluster-4",
+				Ready:         true,
+				ReceivedLSN:   "0/6000000",
+				ReplayLSN:     "0/6000000",
+				FailureDomain: "zone-a",
+			},
+			{
+				Name:              "cluster-1",
+				FailureDomain:     "zone-a",
+				NotSelectedReason: "failing primary",
+			},
+		}))
+	})
+
+	It("records the rationale of a failover not taking the topology into account", func() {
+		cluster.Spec.FailoverTopology = nil
+		status := statusList()
+		decision := buildFailoverDecision(
+			cluster, status, getFailoverCandidate(cluster, status, resources), resources)
+
+		Expect(decision.SelectedPrimary).To(Equal("cluster-2"))
+		Expect(decision.Reason).To(Equal(failoverReasonMostAdvanced))
+		Expect(decision.TopologyKey).To(BeEmpty())
+		Expect(decision.Candidates).To(HaveLen(4))
+		Expect(decision.Candidates[1].FailureDomain).To(BeEmpty())
+		Expect(decision.Candidates[1].NotSelectedReason).To(
+			Equal("same WAL position as the selected instance, which comes first by name"))
+	})
+})
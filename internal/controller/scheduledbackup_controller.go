@@ -132,10 +132,11 @@ func ReconcileScheduledBackup(
 	contextLogger := log.FromContext(ctx)
 
 	// Let's check
-	schedule, err := cron.Parse(scheduledBackup.GetSchedule())
+	schedule, err := scheduledBackup.ParseSchedule()
 	if err != nil {
 		contextLogger.Info("Detected an invalid cron schedule",
-			"schedule", scheduledBackup.GetSchedule())
+			"schedule", scheduledBackup.GetSchedule(),
+			"timeZone", scheduledBackup.Spec.TimeZone)
 		return ctrl.Result{}, err
 	}
 
@@ -29,6 +29,9 @@ const (
 	WALArchiveKey = "wal-archive"
 	// WALRestoreKey is the key to be used to access the cached envs for wal-restore
 	WALRestoreKey = "wal-restore"
+	// BackupCatalogKey is the key to be used to access the cached envs for the
+	// object store containing the base backups
+	BackupCatalogKey = "backup-catalog"
 )
 
 var cache sync.Map
@@ -0,0 +1,248 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archiving
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"reflect"
+	"time"
+
+	barmanCommand "github.com/cloudnative-pg/barman-cloud/pkg/command"
+	barmanRestorer "github.com/cloudnative-pg/barman-cloud/pkg/restorer"
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	postgresSpec "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// lastArchivedWALQuery gets the last WAL file PostgreSQL reports as
+// successfully archived
+const lastArchivedWALQuery = "SELECT COALESCE(last_archived_wal, '') FROM pg_catalog.pg_stat_archiver"
+
+// walFetcher downloads a WAL file from the object store into the
+// passed destination path
+type walFetcher func(
+	ctx context.Context,
+	config *apiv1.BackupConfiguration,
+	clusterName, walName, destinationPath string,
+) error
+
+// A Verifier is a Kubernetes manager.Runnable that periodically checks
+// that the last WAL file archived by PostgreSQL is available in the
+// object store, when this instance is the primary, and reports the
+// outcome in the WALArchiveVerified condition of the cluster.
+// This catches the uploads that silently failed, even if the archive
+// command returned success
+//
+// c.f. https://pkg.go.dev/sigs.k8s.io/controller-runtime/pkg/manager#Runnable
+type Verifier struct {
+	instance *postgres.Instance
+	client   client.Client
+
+	// lastVerifiedWAL is the last WAL file that has been verified, which
+	// is not verified again
+	lastVerifiedWAL string
+
+	// scratchDirectory is where the WAL files are temporarily downloaded
+	scratchDirectory string
+
+	// The following functions interact with PostgreSQL and the object
+	// store, and are replaced in the tests
+	isPrimary       func() (bool, error)
+	isHealthy       func() error
+	lastArchivedWAL func(ctx context.Context) (string, error)
+	fetch           walFetcher
+}
+
+// NewVerifier creates a new Verifier
+func NewVerifier(instance *postgres.Instance, client client.Client) *Verifier {
+	verifier := &Verifier{
+		instance:         instance,
+		client:           client,
+		scratchDirectory: postgresSpec.ScratchDataDirectory,
+		isPrimary:        instance.IsPrimary,
+		isHealthy:        instance.IsServerHealthy,
+		fetch:            fetchWAL,
+	}
+	verifier.lastArchivedWAL = func(ctx context.Context) (string, error) {
+		db, err := instance.GetSuperUserDB()
+		if err != nil {
+			return "", err
+		}
+
+		var walName string
+		err = db.QueryRowContext(ctx, lastArchivedWALQuery).Scan(&walName)
+		return walName, err
+	}
+	return verifier
+}
+
+// Start starts running the Verifier
+func (v *Verifier) Start(ctx context.Context) error {
+	contextLog := log.FromContext(ctx).WithName("archive_verifier")
+	go func() {
+		var config *apiv1.BackupConfiguration
+		var next <-chan time.Time
+
+		defer func() {
+			contextLog.Info("Terminated archive verifier loop")
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case newConfig := <-v.instance.ArchiveVerifierChan():
+				if reflect.DeepEqual(newConfig, config) {
+					continue
+				}
+
+				config, next = newConfig, nil
+				v.lastVerifiedWAL = ""
+				if config == nil {
+					continue
+				}
+
+				next = time.After(config.ArchiveVerification.GetInterval())
+				continue
+
+			case <-next:
+			}
+
+			if err := v.verify(ctx, config); err != nil {
+				contextLog.Warning("verifying the WAL archive", "err", err)
+			}
+			next = time.After(config.ArchiveVerification.GetInterval())
+		}
+	}()
+	<-ctx.Done()
+	return nil
+}
+
+// verify checks that the last WAL file archived by PostgreSQL is in the
+// object store, provided that this instance is a healthy primary and that
+// a WAL file has been archived since the last verification
+func (v *Verifier) verify(ctx context.Context, config *apiv1.BackupConfiguration) error {
+	contextLog := log.FromContext(ctx).WithName("archive_verifier")
+
+	if isPrimary, err := v.isPrimary(); err != nil || !isPrimary {
+		return err
+	}
+
+	if err := v.isHealthy(); err != nil {
+		contextLog.Info("WAL archive verification skipped: the instance is not healthy", "err", err)
+		return nil
+	}
+
+	walName, err := v.lastArchivedWAL(ctx)
+	if err != nil {
+		return fmt.Errorf("while getting the last archived WAL file: %w", err)
+	}
+	if walName == "" || walName == v.lastVerifiedWAL {
+		return nil
+	}
+
+	scratchDirectory, err := os.MkdirTemp(v.scratchDirectory, "archive-verification")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.RemoveAll(scratchDirectory)
+	}()
+
+	var condition *metav1.Condition
+	err = v.fetch(ctx, config, v.instance.GetClusterName(), walName, path.Join(scratchDirectory, walName))
+	switch {
+	case errors.Is(err, barmanRestorer.ErrWALNotFound):
+		contextLog.Warning("The last archived WAL file is missing from the object store", "walName", walName)
+		condition = &metav1.Condition{
+			Type:   string(apiv1.ConditionWALArchiveVerified),
+			Status: metav1.ConditionFalse,
+			Reason: string(apiv1.ConditionReasonArchivedWALMissing),
+			Message: fmt.Sprintf("WAL file %s has been archived by PostgreSQL, "+
+				"but it is missing from the object store", walName),
+		}
+
+	case err != nil:
+		return fmt.Errorf("while fetching WAL file %s from the object store: %w", walName, err)
+
+	default:
+		contextLog.Debug("The last archived WAL file is in the object store", "walName", walName)
+		condition = &metav1.Condition{
+			Type:    string(apiv1.ConditionWALArchiveVerified),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(apiv1.ConditionReasonArchivedWALFound),
+			Message: fmt.Sprintf("WAL file %s, the last one archived by PostgreSQL, is in the object store", walName),
+		}
+	}
+
+	if err := v.updateCondition(ctx, condition); err != nil {
+		return err
+	}
+
+	v.lastVerifiedWAL = walName
+	return nil
+}
+
+// updateCondition records the outcome of the verification in the cluster status
+func (v *Verifier) updateCondition(ctx context.Context, condition *metav1.Condition) error {
+	var cluster apiv1.Cluster
+	if err := v.client.Get(ctx, types.NamespacedName{
+		Name:      v.instance.GetClusterName(),
+		Namespace: v.instance.GetNamespaceName(),
+	}, &cluster); err != nil {
+		return err
+	}
+
+	return conditions.Patch(ctx, v.client, &cluster, condition)
+}
+
+// fetchWAL downloads a WAL file from the object store with
+// barman-cloud-wal-restore
+func fetchWAL(
+	ctx context.Context,
+	config *apiv1.BackupConfiguration,
+	clusterName, walName, destinationPath string,
+) error {
+	env, err := cache.LoadEnv(cache.WALArchiveKey)
+	if err != nil {
+		return fmt.Errorf("while getting the environment of the object store: %w", err)
+	}
+
+	options, err := barmanCommand.CloudWalRestoreOptions(ctx, config.GetWALObjectStore(), clusterName)
+	if err != nil {
+		return fmt.Errorf("while getting barman-cloud-wal-restore options: %w", err)
+	}
+
+	walRestorer, err := barmanRestorer.New(ctx, env, postgresSpec.SpoolDirectory)
+	if err != nil {
+		return fmt.Errorf("while creating the restorer: %w", err)
+	}
+
+	return walRestorer.Restore(walName, destinationPath, options)
+}
@@ -0,0 +1,171 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archiving
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	barmanRestorer "github.com/cloudnative-pg/barman-cloud/pkg/restorer"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WAL archive verification", func() {
+	var (
+		fakeClient  client.Client
+		verifier    *Verifier
+		config      *apiv1.BackupConfiguration
+		archived    string
+		objectStore map[string]bool
+		fetched     []string
+	)
+
+	BeforeEach(func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+		}
+		fakeClient = fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(cluster).
+			WithStatusSubresource(&apiv1.Cluster{}).
+			Build()
+
+		instance := postgres.NewInstance().
+			WithNamespace("default").
+			WithClusterName("cluster-example").
+			WithPodName("cluster-example-1")
+
+		config = &apiv1.BackupConfiguration{
+			BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{DestinationPath: "s3://backups/"},
+			ArchiveVerification: &apiv1.ArchiveVerificationConfiguration{
+				Interval: &metav1.Duration{Duration: 10 * time.Millisecond},
+			},
+		}
+		archived = "000000010000000000000004"
+		objectStore = map[string]bool{
+			"000000010000000000000003": true,
+			"000000010000000000000004": true,
+		}
+		fetched = nil
+
+		verifier = NewVerifier(instance, fakeClient)
+		verifier.scratchDirectory = GinkgoT().TempDir()
+		verifier.isPrimary = func() (bool, error) { return true, nil }
+		verifier.isHealthy = func() error { return nil }
+		verifier.lastArchivedWAL = func(context.Context) (string, error) { return archived, nil }
+		verifier.fetch = func(
+			_ context.Context,
+			_ *apiv1.BackupConfiguration,
+			clusterName, walName, destinationPath string,
+		) error {
+			Expect(clusterName).To(Equal("cluster-example"))
+			fetched = append(fetched, walName)
+			if !objectStore[walName] {
+				return barmanRestorer.ErrWALNotFound
+			}
+			return os.WriteFile(destinationPath, []byte("WAL"), 0o600)
+		}
+	})
+
+	getCondition := func(ctx context.Context) *metav1.Condition {
+		var cluster apiv1.Cluster
+		Expect(fakeClient.Get(ctx, types.NamespacedName{
+			Name:      "cluster-example",
+			Namespace: "default",
+		}, &cluster)).To(Succeed())
+		return meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionWALArchiveVerified))
+	}
+
+	It("reports that the last archived WAL file is in the object store", func(ctx SpecContext) {
+		Expect(verifier.verify(ctx, config)).To(Succeed())
+
+		condition := getCondition(ctx)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonArchivedWALFound)))
+
+		entries, err := os.ReadDir(verifier.scratchDirectory)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entries).To(BeEmpty())
+	})
+
+	It("flags a WAL file reported as archived that is missing from the object store", func(ctx SpecContext) {
+		Expect(verifier.verify(ctx, config)).To(Succeed())
+		Expect(getCondition(ctx).Status).To(Equal(metav1.ConditionTrue))
+
+		archived = "000000010000000000000005"
+		Expect(verifier.verify(ctx, config)).To(Succeed())
+
+		condition := getCondition(ctx)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonArchivedWALMissing)))
+		Expect(condition.Message).To(ContainSubstring("000000010000000000000005"))
+	})
+
+	It("doesn't verify the same WAL file twice", func(ctx SpecContext) {
+		Expect(verifier.verify(ctx, config)).To(Succeed())
+		Expect(verifier.verify(ctx, config)).To(Succeed())
+		Expect(fetched).To(Equal([]string{"000000010000000000000004"}))
+	})
+
+	It("doesn't change the condition when the object store can't be reached", func(ctx SpecContext) {
+		verifier.fetch = func(context.Context, *apiv1.BackupConfiguration, string, string, string) error {
+			return errors.New("connection refused")
+		}
+		Expect(verifier.verify(ctx, config)).To(MatchError(ContainSubstring("connection refused")))
+		Expect(getCondition(ctx)).To(BeNil())
+	})
+
+	It("verifies nothing on a replica", func(ctx SpecContext) {
+		verifier.isPrimary = func() (bool, error) { return false, nil }
+		Expect(verifier.verify(ctx, config)).To(Succeed())
+		Expect(fetched).To(BeEmpty())
+	})
+
+	It("runs periodically once configured", func(ctx SpecContext) {
+		runCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go func() {
+			defer GinkgoRecover()
+			Expect(verifier.Start(runCtx)).To(Succeed())
+		}()
+
+		delete(objectStore, archived)
+		verifier.instance.ConfigureArchiveVerifier(config)
+
+		Eventually(func(g Gomega) {
+			condition := getCondition(ctx)
+			g.Expect(condition).ToNot(BeNil())
+			g.Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonArchivedWALMissing)))
+		}).WithTimeout(5 * time.Second).Should(Succeed())
+	})
+})
@@ -74,6 +74,8 @@ func (r *InstanceReconciler) updateWALRestoreSettingsCache(ctx context.Context,
 }
 
 // shouldUpdateWALArchiveSettingsCache updates the cache with the backup credentials
+// of the object store receiving the WAL files and of the one containing the
+// base backups, which are different when a separate WAL object store is used
 //
 // returns true if and only if the update should run again, because:
 // the backup credentials exist but don't have permission,
@@ -85,15 +87,17 @@ func (r *InstanceReconciler) shouldUpdateWALArchiveSettingsCache(
 
 	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
 		cache.Delete(cache.WALArchiveKey)
+		cache.Delete(cache.BackupCatalogKey)
 		return false
 	}
 
 	// Populate the cache with the backup configuration
+	backupConfiguration := cluster.GetBackupConfiguration()
 	envArchive, err := barmanCredentials.EnvSetBackupCloudCredentials(
 		ctx,
 		r.GetClient(),
 		cluster.Namespace,
-		cluster.Spec.Backup.BarmanObjectStore,
+		backupConfiguration.GetWALObjectStore(),
 		os.Environ())
 	if apierrors.IsForbidden(err) {
 		contextLogger.Info("backup credentials don't yet have access permissions. Will retry reconciliation loop")
@@ -106,5 +110,28 @@ func (r *InstanceReconciler) shouldUpdateWALArchiveSettingsCache(
 	}
 
 	cache.Store(cache.WALArchiveKey, envArchive)
+
+	if backupConfiguration.WALObjectStore == nil {
+		cache.Store(cache.BackupCatalogKey, envArchive)
+		return false
+	}
+
+	envBackup, err := barmanCredentials.EnvSetBackupCloudCredentials(
+		ctx,
+		r.GetClient(),
+		cluster.Namespace,
+		backupConfiguration.BarmanObjectStore,
+		os.Environ())
+	if apierrors.IsForbidden(err) {
+		contextLogger.Info("backup credentials don't yet have access permissions. Will retry reconciliation loop")
+		return true
+	}
+
+	if err != nil {
+		contextLogger.Error(err, "while getting backup credentials")
+		return false
+	}
+
+	cache.Store(cache.BackupCatalogKey, envBackup)
 	return false
 }
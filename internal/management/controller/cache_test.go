@@ -0,0 +1,126 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("shouldUpdateWALArchiveSettingsCache", func() {
+	newSecret := func(name, accessKeyID string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Data: map[string][]byte{
+				"ACCESS_KEY_ID":     []byte(accessKeyID),
+				"ACCESS_SECRET_KEY": []byte("secret"),
+			},
+		}
+	}
+
+	newObjectStore := func(destinationPath, secretName string) *apiv1.BarmanObjectStoreConfiguration {
+		return &apiv1.BarmanObjectStoreConfiguration{
+			DestinationPath: destinationPath,
+			BarmanCredentials: apiv1.BarmanCredentials{
+				AWS: &apiv1.S3Credentials{
+					AccessKeyIDReference: &apiv1.SecretKeySelector{
+						LocalObjectReference: apiv1.LocalObjectReference{Name: secretName},
+						Key:                  "ACCESS_KEY_ID",
+					},
+					SecretAccessKeyReference: &apiv1.SecretKeySelector{
+						LocalObjectReference: apiv1.LocalObjectReference{Name: secretName},
+						Key:                  "ACCESS_SECRET_KEY",
+					},
+				},
+			},
+		}
+	}
+
+	var (
+		r       *InstanceReconciler
+		cluster *apiv1.Cluster
+	)
+
+	BeforeEach(func() {
+		r = &InstanceReconciler{
+			client: fake.NewClientBuilder().
+				WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(newSecret("data-creds", "data-key"), newSecret("wal-creds", "wal-key")).
+				Build(),
+		}
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{
+					BarmanObjectStore: newObjectStore("s3://data/", "data-creds"),
+				},
+			},
+		}
+		DeferCleanup(func() {
+			cache.Delete(cache.WALArchiveKey)
+			cache.Delete(cache.BackupCatalogKey)
+		})
+	})
+
+	It("uses the same credentials when the WAL files are archived with the base backups", func(ctx SpecContext) {
+		Expect(r.shouldUpdateWALArchiveSettingsCache(ctx, cluster)).To(BeFalse())
+
+		envArchive, err := cache.LoadEnv(cache.WALArchiveKey)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(envArchive).To(ContainElement("AWS_ACCESS_KEY_ID=data-key"))
+
+		envBackup, err := cache.LoadEnv(cache.BackupCatalogKey)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(envBackup).To(ContainElement("AWS_ACCESS_KEY_ID=data-key"))
+	})
+
+	It("uses the credentials of each object store when the WAL files are archived separately",
+		func(ctx SpecContext) {
+			cluster.Spec.Backup.WALObjectStore = newObjectStore("s3://wal/", "wal-creds")
+			Expect(r.shouldUpdateWALArchiveSettingsCache(ctx, cluster)).To(BeFalse())
+
+			envArchive, err := cache.LoadEnv(cache.WALArchiveKey)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(envArchive).To(ContainElement("AWS_ACCESS_KEY_ID=wal-key"))
+			Expect(envArchive).ToNot(ContainElement("AWS_ACCESS_KEY_ID=data-key"))
+
+			envBackup, err := cache.LoadEnv(cache.BackupCatalogKey)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(envBackup).To(ContainElement("AWS_ACCESS_KEY_ID=data-key"))
+			Expect(envBackup).ToNot(ContainElement("AWS_ACCESS_KEY_ID=wal-key"))
+		})
+
+	It("removes the cached credentials when no object store is configured", func(ctx SpecContext) {
+		Expect(r.shouldUpdateWALArchiveSettingsCache(ctx, cluster)).To(BeFalse())
+
+		cluster.Spec.Backup = nil
+		Expect(r.shouldUpdateWALArchiveSettingsCache(ctx, cluster)).To(BeFalse())
+
+		_, err := cache.LoadEnv(cache.WALArchiveKey)
+		Expect(err).To(MatchError(cache.ErrCacheMiss))
+		_, err = cache.LoadEnv(cache.BackupCatalogKey)
+		Expect(err).To(MatchError(cache.ErrCacheMiss))
+	})
+})
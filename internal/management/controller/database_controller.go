@@ -49,6 +49,7 @@ type DatabaseReconciler struct {
 
 type instanceInterface interface {
 	GetSuperUserDB() (*sql.DB, error)
+	GetNamedDB(name string) (*sql.DB, error)
 	GetClusterName() string
 	GetPodName() string
 	GetNamespaceName() string
@@ -173,6 +174,17 @@ func (r *DatabaseReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		)
 	}
 
+	if err := r.reconcileExtensions(
+		ctx,
+		&database,
+	); err != nil {
+		return r.failedReconciliation(
+			ctx,
+			&database,
+			err,
+		)
+	}
+
 	return r.succeededReconciliation(
 		ctx,
 		&database,
@@ -0,0 +1,193 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/jackc/pgx/v5"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// detectExtensionQuery reads the version and the schema of an extension
+const detectExtensionQuery = `SELECT e.extversion, n.nspname
+FROM pg_catalog.pg_extension e
+JOIN pg_catalog.pg_namespace n ON e.extnamespace = n.oid
+WHERE e.extname = $1`
+
+// installedExtension is an extension existing in a database
+type installedExtension struct {
+	version string
+	schema  string
+}
+
+// reconcileExtensions aligns the extensions of the database to the spec,
+// and stores the outcome for each of them in the status. The failure of
+// an extension, e.g. because its shared library is not available in the
+// image, doesn't prevent the other ones from being reconciled, and is
+// reported as an error so that the reconciliation is retried later
+func (r *DatabaseReconciler) reconcileExtensions(ctx context.Context, obj *apiv1.Database) error {
+	if obj.Spec.Ensure == apiv1.EnsureAbsent {
+		return nil
+	}
+
+	if len(obj.Spec.Extensions) == 0 && len(obj.Status.Extensions) == 0 {
+		return nil
+	}
+
+	db, err := r.instance.GetNamedDB(obj.Spec.Name)
+	if err != nil {
+		return fmt.Errorf("while connecting to the database %q: %w", obj.Spec.Name, err)
+	}
+
+	extensionsStatus := make([]apiv1.ExtensionStatus, len(obj.Spec.Extensions))
+	var failedExtensions []string
+	for idx, extension := range obj.Spec.Extensions {
+		extensionsStatus[idx] = apiv1.ExtensionStatus{Name: extension.Name, Applied: true}
+		if err := reconcileExtension(ctx, db, extension); err != nil {
+			extensionsStatus[idx].Applied = false
+			extensionsStatus[idx].Message = err.Error()
+			failedExtensions = append(failedExtensions, err.Error())
+		}
+	}
+
+	oldDatabase := obj.DeepCopy()
+	obj.Status.Extensions = extensionsStatus
+	if err := r.Client.Status().Patch(ctx, obj, client.MergeFrom(oldDatabase)); err != nil {
+		return err
+	}
+
+	if len(failedExtensions) > 0 {
+		return errors.New(strings.Join(failedExtensions, "; "))
+	}
+	return nil
+}
+
+// reconcileExtension creates, updates or drops an extension as requested
+func reconcileExtension(ctx context.Context, db *sql.DB, extension apiv1.ExtensionSpec) error {
+	installed, err := getInstalledExtension(ctx, db, extension.Name)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case extension.Ensure == apiv1.EnsureAbsent:
+		if installed == nil {
+			return nil
+		}
+		return dropExtension(ctx, db, extension)
+
+	case installed == nil:
+		return createExtension(ctx, db, extension)
+
+	default:
+		return updateExtension(ctx, db, extension, *installed)
+	}
+}
+
+// getInstalledExtension returns the version and the schema of an
+// extension, or nil if it is not installed in the database
+func getInstalledExtension(ctx context.Context, db *sql.DB, name string) (*installedExtension, error) {
+	row := db.QueryRowContext(ctx, detectExtensionQuery, name)
+
+	var result installedExtension
+	if err := row.Scan(&result.version, &result.schema); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("while checking if extension %q exists: %w", name, err)
+	}
+
+	return &result, nil
+}
+
+func createExtension(ctx context.Context, db *sql.DB, extension apiv1.ExtensionSpec) error {
+	contextLogger := log.FromContext(ctx)
+
+	var sqlCreateExtension strings.Builder
+	sqlCreateExtension.WriteString(fmt.Sprintf("CREATE EXTENSION IF NOT EXISTS %s",
+		pgx.Identifier{extension.Name}.Sanitize()))
+	if extension.Schema != "" {
+		sqlCreateExtension.WriteString(fmt.Sprintf(" SCHEMA %s", pgx.Identifier{extension.Schema}.Sanitize()))
+	}
+	if extension.Version != "" {
+		sqlCreateExtension.WriteString(fmt.Sprintf(" VERSION %s", pgx.Identifier{extension.Version}.Sanitize()))
+	}
+
+	if _, err := db.ExecContext(ctx, sqlCreateExtension.String()); err != nil {
+		contextLogger.Error(err, "while creating extension", "query", sqlCreateExtension.String())
+		return fmt.Errorf("while creating extension %q: %w", extension.Name, err)
+	}
+
+	return nil
+}
+
+func updateExtension(
+	ctx context.Context,
+	db *sql.DB,
+	extension apiv1.ExtensionSpec,
+	installed installedExtension,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	if extension.Version != "" && extension.Version != installed.version {
+		updateVersionSQL := fmt.Sprintf(
+			"ALTER EXTENSION %s UPDATE TO %s",
+			pgx.Identifier{extension.Name}.Sanitize(),
+			pgx.Identifier{extension.Version}.Sanitize())
+
+		if _, err := db.ExecContext(ctx, updateVersionSQL); err != nil {
+			contextLogger.Error(err, "while updating extension", "query", updateVersionSQL)
+			return fmt.Errorf("while updating extension %q from version %s to %s: %w",
+				extension.Name, installed.version, extension.Version, err)
+		}
+	}
+
+	if extension.Schema != "" && extension.Schema != installed.schema {
+		changeSchemaSQL := fmt.Sprintf(
+			"ALTER EXTENSION %s SET SCHEMA %s",
+			pgx.Identifier{extension.Name}.Sanitize(),
+			pgx.Identifier{extension.Schema}.Sanitize())
+
+		if _, err := db.ExecContext(ctx, changeSchemaSQL); err != nil {
+			contextLogger.Error(err, "while updating extension", "query", changeSchemaSQL)
+			return fmt.Errorf("while moving extension %q to schema %s: %w",
+				extension.Name, extension.Schema, err)
+		}
+	}
+
+	return nil
+}
+
+func dropExtension(ctx context.Context, db *sql.DB, extension apiv1.ExtensionSpec) error {
+	contextLogger := log.FromContext(ctx)
+
+	query := fmt.Sprintf("DROP EXTENSION IF EXISTS %s", pgx.Identifier{extension.Name}.Sanitize())
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		contextLogger.Error(err, "while dropping extension", "query", query)
+		return fmt.Errorf("while dropping extension %q: %w", extension.Name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Managed Database extensions SQL", func() {
+	var (
+		dbMock sqlmock.Sqlmock
+		db     *sql.DB
+		err    error
+	)
+
+	BeforeEach(func() {
+		db, dbMock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	noExtension := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"extversion", "nspname"})
+	}
+
+	installedExtension := func(version, schema string) *sqlmock.Rows {
+		return noExtension().AddRow(version, schema)
+	}
+
+	It("creates a missing extension in the requested schema and version", func(ctx SpecContext) {
+		dbMock.ExpectQuery(detectExtensionQuery).WithArgs("postgis").WillReturnRows(noExtension())
+		dbMock.ExpectExec(`CREATE EXTENSION IF NOT EXISTS "postgis" SCHEMA "gis" VERSION "3.4.2"`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		Expect(reconcileExtension(ctx, db, apiv1.ExtensionSpec{
+			Name:    "postgis",
+			Ensure:  apiv1.EnsurePresent,
+			Version: "3.4.2",
+			Schema:  "gis",
+		})).To(Succeed())
+	})
+
+	It("doesn't touch an extension matching the spec", func(ctx SpecContext) {
+		dbMock.ExpectQuery(detectExtensionQuery).WithArgs("postgis").
+			WillReturnRows(installedExtension("3.4.2", "public"))
+
+		Expect(reconcileExtension(ctx, db, apiv1.ExtensionSpec{
+			Name:    "postgis",
+			Ensure:  apiv1.EnsurePresent,
+			Version: "3.4.2",
+		})).To(Succeed())
+	})
+
+	It("updates an extension when the requested version changes", func(ctx SpecContext) {
+		dbMock.ExpectQuery(detectExtensionQuery).WithArgs("postgis").
+			WillReturnRows(installedExtension("3.4.1", "public"))
+		dbMock.ExpectExec(`ALTER EXTENSION "postgis" UPDATE TO "3.4.2"`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		Expect(reconcileExtension(ctx, db, apiv1.ExtensionSpec{
+			Name:    "postgis",
+			Ensure:  apiv1.EnsurePresent,
+			Version: "3.4.2",
+		})).To(Succeed())
+	})
+
+	It("moves an extension when the requested schema changes", func(ctx SpecContext) {
+		dbMock.ExpectQuery(detectExtensionQuery).WithArgs("postgis").
+			WillReturnRows(installedExtension("3.4.2", "public"))
+		dbMock.ExpectExec(`ALTER EXTENSION "postgis" SET SCHEMA "gis"`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		Expect(reconcileExtension(ctx, db, apiv1.ExtensionSpec{
+			Name:   "postgis",
+			Ensure: apiv1.EnsurePresent,
+			Schema: "gis",
+		})).To(Succeed())
+	})
+
+	It("drops an extension marked as absent", func(ctx SpecContext) {
+		dbMock.ExpectQuery(detectExtensionQuery).WithArgs("postgis").
+			WillReturnRows(installedExtension("3.4.2", "public"))
+		dbMock.ExpectExec(`DROP EXTENSION IF EXISTS "postgis"`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		Expect(reconcileExtension(ctx, db, apiv1.ExtensionSpec{
+			Name:   "postgis",
+			Ensure: apiv1.EnsureAbsent,
+		})).To(Succeed())
+	})
+
+	It("does nothing when an extension marked as absent is missing", func(ctx SpecContext) {
+		dbMock.ExpectQuery(detectExtensionQuery).WithArgs("postgis").WillReturnRows(noExtension())
+
+		Expect(reconcileExtension(ctx, db, apiv1.ExtensionSpec{
+			Name:   "postgis",
+			Ensure: apiv1.EnsureAbsent,
+		})).To(Succeed())
+	})
+
+	It("reports the extensions that can't be created", func(ctx SpecContext) {
+		dbMock.ExpectQuery(detectExtensionQuery).WithArgs("vector").WillReturnRows(noExtension())
+		dbMock.ExpectExec(`CREATE EXTENSION IF NOT EXISTS "vector"`).
+			WillReturnError(fmt.Errorf("could not open extension control file"))
+
+		err := reconcileExtension(ctx, db, apiv1.ExtensionSpec{
+			Name:   "vector",
+			Ensure: apiv1.EnsurePresent,
+		})
+		Expect(err).To(MatchError(ContainSubstring(`while creating extension "vector"`)))
+	})
+})
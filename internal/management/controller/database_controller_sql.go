@@ -121,6 +121,34 @@ func createDatabase(
 	return nil
 }
 
+// databaseConnectionSettings are the settings of a database
+// controlling the connections it accepts
+type databaseConnectionSettings struct {
+	allowConnections bool
+	connectionLimit  int
+}
+
+func getDatabaseConnectionSettings(
+	ctx context.Context,
+	db *sql.DB,
+	obj *apiv1.Database,
+) (databaseConnectionSettings, error) {
+	var settings databaseConnectionSettings
+	row := db.QueryRowContext(
+		ctx,
+		`
+		SELECT datallowconn, datconnlimit
+		FROM pg_database
+		WHERE datname = $1
+		`,
+		obj.Spec.Name)
+	if err := row.Scan(&settings.allowConnections, &settings.connectionLimit); err != nil {
+		return settings, fmt.Errorf("while reading the connection settings of database %q: %w", obj.Spec.Name, err)
+	}
+
+	return settings, nil
+}
+
 func updateDatabase(
 	ctx context.Context,
 	db *sql.DB,
@@ -128,7 +156,15 @@ func updateDatabase(
 ) error {
 	contextLogger := log.FromContext(ctx)
 
-	if obj.Spec.AllowConnections != nil {
+	var currentSettings databaseConnectionSettings
+	if obj.Spec.AllowConnections != nil || obj.Spec.ConnectionLimit != nil {
+		var err error
+		if currentSettings, err = getDatabaseConnectionSettings(ctx, db, obj); err != nil {
+			return err
+		}
+	}
+
+	if obj.Spec.AllowConnections != nil && *obj.Spec.AllowConnections != currentSettings.allowConnections {
 		changeAllowConnectionsSQL := fmt.Sprintf(
 			"ALTER DATABASE %s WITH ALLOW_CONNECTIONS %v",
 			pgx.Identifier{obj.Spec.Name}.Sanitize(),
@@ -141,7 +177,7 @@ func updateDatabase(
 		}
 	}
 
-	if obj.Spec.ConnectionLimit != nil {
+	if obj.Spec.ConnectionLimit != nil && *obj.Spec.ConnectionLimit != currentSettings.connectionLimit {
 		changeConnectionsLimitSQL := fmt.Sprintf(
 			"ALTER DATABASE %s WITH CONNECTION LIMIT %v",
 			pgx.Identifier{obj.Spec.Name}.Sanitize(),
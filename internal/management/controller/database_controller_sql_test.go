@@ -164,6 +164,12 @@ var _ = Describe("Managed Database SQL", func() {
 
 			expectedValue := sqlmock.NewResult(0, 1)
 
+			// Mock the current connection settings
+			dbMock.ExpectQuery(`SELECT datallowconn, datconnlimit
+				FROM pg_database
+				WHERE datname = $1`).WithArgs(database.Spec.Name).
+				WillReturnRows(sqlmock.NewRows([]string{"datallowconn", "datconnlimit"}).AddRow(false, 10))
+
 			// Mock AllowConnections DDL
 			allowConnectionsExpectedQuery := fmt.Sprintf(
 				"ALTER DATABASE %s WITH ALLOW_CONNECTIONS %v",
@@ -209,6 +215,63 @@ var _ = Describe("Managed Database SQL", func() {
 		})
 	})
 
+	Context("updateDatabase connection settings", func() {
+		BeforeEach(func() {
+			database.Spec.Owner = ""
+		})
+
+		expectConnectionSettings := func(allowConnections bool, connectionLimit int) {
+			dbMock.ExpectQuery(`SELECT datallowconn, datconnlimit
+				FROM pg_database
+				WHERE datname = $1`).WithArgs(database.Spec.Name).
+				WillReturnRows(sqlmock.NewRows([]string{"datallowconn", "datconnlimit"}).
+					AddRow(allowConnections, connectionLimit))
+		}
+
+		It("doesn't alter the Database when the settings are already applied", func(ctx SpecContext) {
+			database.Spec.AllowConnections = ptr.To(true)
+			database.Spec.ConnectionLimit = ptr.To(20)
+			expectConnectionSettings(true, 20)
+
+			Expect(updateDatabase(ctx, db, database)).To(Succeed())
+		})
+
+		It("blocks and then permits the new connections when toggling allowConnections", func(ctx SpecContext) {
+			By("blocking the new connections", func() {
+				database.Spec.AllowConnections = ptr.To(false)
+				expectConnectionSettings(true, -1)
+				dbMock.ExpectExec(fmt.Sprintf(
+					"ALTER DATABASE %s WITH ALLOW_CONNECTIONS false",
+					pgx.Identifier{database.Spec.Name}.Sanitize(),
+				)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				Expect(updateDatabase(ctx, db, database)).To(Succeed())
+			})
+
+			By("permitting the new connections again", func() {
+				database.Spec.AllowConnections = ptr.To(true)
+				expectConnectionSettings(false, -1)
+				dbMock.ExpectExec(fmt.Sprintf(
+					"ALTER DATABASE %s WITH ALLOW_CONNECTIONS true",
+					pgx.Identifier{database.Spec.Name}.Sanitize(),
+				)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				Expect(updateDatabase(ctx, db, database)).To(Succeed())
+			})
+		})
+
+		It("changes the connection limit", func(ctx SpecContext) {
+			database.Spec.ConnectionLimit = ptr.To(5)
+			expectConnectionSettings(true, -1)
+			dbMock.ExpectExec(fmt.Sprintf(
+				"ALTER DATABASE %s WITH CONNECTION LIMIT 5",
+				pgx.Identifier{database.Spec.Name}.Sanitize(),
+			)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+			Expect(updateDatabase(ctx, db, database)).To(Succeed())
+		})
+	})
+
 	Context("dropDatabase", func() {
 		It("should drop an existing Database", func(ctx SpecContext) {
 			expectedValue := sqlmock.NewResult(0, 1)
@@ -48,6 +48,10 @@ func (f *fakeInstanceData) GetSuperUserDB() (*sql.DB, error) {
 	return f.db, nil
 }
 
+func (f *fakeInstanceData) GetNamedDB(string) (*sql.DB, error) {
+	return f.db, nil
+}
+
 var _ = Describe("Managed Database status", func() {
 	var (
 		dbMock     sqlmock.Sqlmock
@@ -151,6 +155,63 @@ var _ = Describe("Managed Database status", func() {
 		Expect(updatedDatabase.Finalizers).NotTo(BeEmpty())
 	})
 
+	It("reports the extensions that can't be reconciled", func(ctx SpecContext) {
+		database.Spec.Extensions = []apiv1.ExtensionSpec{
+			{Name: "pg_stat_statements", Ensure: apiv1.EnsurePresent},
+			{Name: "vector", Ensure: apiv1.EnsurePresent},
+		}
+		Expect(fakeClient.Update(ctx, database)).To(Succeed())
+
+		// Mocking DetectDB
+		expectedValue := sqlmock.NewRows([]string{""}).AddRow("1")
+		dbMock.ExpectQuery(`SELECT count(*)
+		FROM pg_database
+		WHERE datname = $1`).WithArgs(database.Spec.Name).WillReturnRows(expectedValue)
+
+		// Mocking Alter Database
+		expectedQuery := fmt.Sprintf("ALTER DATABASE %s OWNER TO %s",
+			pgx.Identifier{database.Spec.Name}.Sanitize(),
+			pgx.Identifier{database.Spec.Owner}.Sanitize(),
+		)
+		dbMock.ExpectExec(expectedQuery).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		// The first extension is created, the second one is not available
+		dbMock.ExpectQuery(detectExtensionQuery).WithArgs("pg_stat_statements").
+			WillReturnRows(sqlmock.NewRows([]string{"extversion", "nspname"}))
+		dbMock.ExpectExec(`CREATE EXTENSION IF NOT EXISTS "pg_stat_statements"`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectQuery(detectExtensionQuery).WithArgs("vector").
+			WillReturnRows(sqlmock.NewRows([]string{"extversion", "nspname"}))
+		dbMock.ExpectExec(`CREATE EXTENSION IF NOT EXISTS "vector"`).
+			WillReturnError(fmt.Errorf("extension \"vector\" is not available"))
+
+		// Reconcile and get the updated object
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{
+			Namespace: database.Namespace,
+			Name:      database.Name,
+		}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+
+		var updatedDatabase apiv1.Database
+		err = fakeClient.Get(ctx, client.ObjectKey{
+			Namespace: database.Namespace,
+			Name:      database.Name,
+		}, &updatedDatabase)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(updatedDatabase.Status.Applied).Should(HaveValue(BeFalse()))
+		Expect(updatedDatabase.Status.Message).Should(ContainSubstring("is not available"))
+		Expect(updatedDatabase.Status.Extensions).To(HaveLen(2))
+		Expect(updatedDatabase.Status.Extensions[0]).To(Equal(apiv1.ExtensionStatus{
+			Name:    "pg_stat_statements",
+			Applied: true,
+		}))
+		Expect(updatedDatabase.Status.Extensions[1].Name).To(Equal("vector"))
+		Expect(updatedDatabase.Status.Extensions[1].Applied).To(BeFalse())
+		Expect(updatedDatabase.Status.Extensions[1].Message).To(ContainSubstring("is not available"))
+	})
+
 	It("database object inherits error after patching", func(ctx SpecContext) {
 		// Mocking DetectDB
 		expectedValue := sqlmock.NewRows([]string{""}).AddRow("1")
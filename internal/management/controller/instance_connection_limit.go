@@ -0,0 +1,104 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/jackc/pgx/v5"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// reconcileApplicationDatabaseConnectionLimit applies the connection limit
+// requested in the initdb bootstrap section to the application database.
+// This is done on the primary only, and is a no-op when the limit is
+// not set or already applied
+func (r *InstanceReconciler) reconcileApplicationDatabaseConnectionLimit(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) error {
+	connectionLimit := cluster.GetApplicationDatabaseConnectionLimit()
+	if connectionLimit == nil {
+		return nil
+	}
+
+	databaseName := cluster.GetApplicationDatabaseName()
+	if databaseName == "" {
+		return nil
+	}
+
+	primary, err := r.instance.IsPrimary()
+	if err != nil {
+		return err
+	}
+	if !primary {
+		return nil
+	}
+
+	db, err := r.instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+
+	return setDatabaseConnectionLimit(ctx, db, databaseName, *connectionLimit)
+}
+
+// setDatabaseConnectionLimit alters the connection limit of the passed
+// database when it differs from the requested one. A database that
+// doesn't exist yet is ignored
+func setDatabaseConnectionLimit(
+	ctx context.Context,
+	db *sql.DB,
+	databaseName string,
+	connectionLimit int,
+) error {
+	var currentConnectionLimit int
+	row := db.QueryRowContext(
+		ctx,
+		"SELECT datconnlimit FROM pg_catalog.pg_database WHERE datname = $1",
+		databaseName)
+	if err := row.Scan(&currentConnectionLimit); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("while reading the connection limit of database %q: %w", databaseName, err)
+	}
+
+	if currentConnectionLimit == connectionLimit {
+		return nil
+	}
+
+	query := fmt.Sprintf(
+		"ALTER DATABASE %s WITH CONNECTION LIMIT %d",
+		pgx.Identifier{databaseName}.Sanitize(),
+		connectionLimit)
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("while altering the connection limit of database %q: %w", databaseName, err)
+	}
+
+	log.FromContext(ctx).Info("Updated the application database connection limit",
+		"database", databaseName,
+		"previousConnectionLimit", currentConnectionLimit,
+		"connectionLimit", connectionLimit)
+
+	return nil
+}
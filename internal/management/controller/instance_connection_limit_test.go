@@ -0,0 +1,77 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("setDatabaseConnectionLimit", func() {
+	const selectQuery = "SELECT datconnlimit FROM pg_catalog.pg_database WHERE datname = $1"
+
+	var (
+		db     *sql.DB
+		dbMock sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, dbMock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("alters the database when the connection limit differs", func(ctx SpecContext) {
+		dbMock.ExpectQuery(selectQuery).WithArgs("app").
+			WillReturnRows(sqlmock.NewRows([]string{"datconnlimit"}).AddRow(-1))
+		dbMock.ExpectExec(`ALTER DATABASE "app" WITH CONNECTION LIMIT 50`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		Expect(setDatabaseConnectionLimit(ctx, db, "app", 50)).To(Succeed())
+	})
+
+	It("doesn't alter the database when the connection limit is already applied", func(ctx SpecContext) {
+		dbMock.ExpectQuery(selectQuery).WithArgs("app").
+			WillReturnRows(sqlmock.NewRows([]string{"datconnlimit"}).AddRow(50))
+
+		Expect(setDatabaseConnectionLimit(ctx, db, "app", 50)).To(Succeed())
+	})
+
+	It("ignores a database that doesn't exist yet", func(ctx SpecContext) {
+		dbMock.ExpectQuery(selectQuery).WithArgs("app").WillReturnError(sql.ErrNoRows)
+
+		Expect(setDatabaseConnectionLimit(ctx, db, "app", 50)).To(Succeed())
+	})
+
+	It("reports the errors raised while altering the database", func(ctx SpecContext) {
+		dbMock.ExpectQuery(selectQuery).WithArgs("app").
+			WillReturnRows(sqlmock.NewRows([]string{"datconnlimit"}).AddRow(10))
+		dbMock.ExpectExec(`ALTER DATABASE "app" WITH CONNECTION LIMIT -1`).
+			WillReturnError(errors.New("boom"))
+
+		Expect(setDatabaseConnectionLimit(ctx, db, "app", -1)).To(MatchError(ContainSubstring("boom")))
+	})
+})
@@ -127,6 +127,9 @@ func (r *InstanceReconciler) Reconcile(
 	// Reconcile monitoring section
 	r.reconcileMetrics(cluster)
 	r.reconcileMonitoringQueries(ctx, cluster)
+	r.configureIntegrityChecker(cluster)
+	r.configureRepacker(cluster)
+	r.configureArchiveVerifier(cluster)
 
 	// Verify that the promotion token is usable before changing the archive mode and triggering restarts
 	if err := r.verifyPromotionToken(cluster); err != nil {
@@ -231,6 +234,10 @@ func (r *InstanceReconciler) Reconcile(
 		}
 	}
 
+	if err := r.reconcileSourceReplicationSlot(ctx, cluster); err != nil {
+		contextLogger.Error(err, "while reconciling the replication slot on the source")
+	}
+
 	// IMPORTANT
 	// From now on, the database can be assumed as running. Every operation
 	// needing the database to be up should be put below this line.
@@ -246,6 +253,7 @@ func (r *InstanceReconciler) Reconcile(
 		r.instance.GetPodName(),
 		postgresDB,
 		cluster,
+		r.orphanedSlots,
 	); err != nil || !result.IsZero() {
 		return result, err
 	}
@@ -261,6 +269,10 @@ func (r *InstanceReconciler) Reconcile(
 		return reconcile.Result{}, fmt.Errorf("while updating database owner password: %w", err)
 	}
 
+	if err = r.reconcileApplicationDatabaseConnectionLimit(ctx, cluster); err != nil {
+		return reconcile.Result{}, fmt.Errorf("while updating the application database connection limit: %w", err)
+	}
+
 	if res, err := r.dropStaleReplicationConnections(ctx, cluster); err != nil || !res.IsZero() {
 		if err != nil {
 			return reconcile.Result{}, fmt.Errorf("while dropping stale replica connections: %w", err)
@@ -296,7 +308,9 @@ func (r *InstanceReconciler) Reconcile(
 		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
 	}
 
-	return reconcile.Result{}, nil
+	// Come back when the grace period of the orphaned replication slots
+	// expires, to drop them
+	return reconcile.Result{RequeueAfter: r.orphanedSlots.GetRemainingGracePeriod()}, nil
 }
 
 func (r *InstanceReconciler) configureSlotReplicator(cluster *apiv1.Cluster) {
@@ -308,6 +322,36 @@ func (r *InstanceReconciler) configureSlotReplicator(cluster *apiv1.Cluster) {
 	}
 }
 
+func (r *InstanceReconciler) configureIntegrityChecker(cluster *apiv1.Cluster) {
+	if cluster.GetIntegrityCheckInstance() != r.instance.GetPodName() {
+		r.instance.ConfigureIntegrityChecker(nil)
+		return
+	}
+
+	r.instance.ConfigureIntegrityChecker(cluster.Spec.Managed.IntegrityCheck)
+}
+
+func (r *InstanceReconciler) configureRepacker(cluster *apiv1.Cluster) {
+	if cluster.Status.CurrentPrimary != r.instance.GetPodName() {
+		r.instance.ConfigureRepacker(nil)
+		return
+	}
+
+	r.instance.ConfigureRepacker(cluster.GetRepackConfiguration())
+}
+
+func (r *InstanceReconciler) configureArchiveVerifier(cluster *apiv1.Cluster) {
+	if cluster.Status.CurrentPrimary != r.instance.GetPodName() ||
+		cluster.Spec.Backup == nil ||
+		cluster.Spec.Backup.ArchiveVerification == nil ||
+		cluster.Spec.Backup.BarmanObjectStore == nil {
+		r.instance.ConfigureArchiveVerifier(nil)
+		return
+	}
+
+	r.instance.ConfigureArchiveVerifier(cluster.GetBackupConfiguration())
+}
+
 func (r *InstanceReconciler) restartPrimaryInplaceIfRequested(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
@@ -347,7 +391,7 @@ func (r *InstanceReconciler) refreshConfigurationFiles(
 		return false, err
 	}
 
-	reloadIdent, err := r.instance.RefreshPGIdent(ctx, cluster.Spec.PostgresConfiguration.PgIdent)
+	reloadIdent, err := r.instance.RefreshPGIdent(ctx, cluster)
 	if err != nil {
 		return false, err
 	}
@@ -355,7 +399,14 @@ func (r *InstanceReconciler) refreshConfigurationFiles(
 
 	// Reconcile PostgreSQL configuration
 	// This doesn't need the PG connection, but it needs to reload it in case of changes
-	reloadConfig, err := r.instance.RefreshConfigurationFilesFromCluster(ctx, cluster, false)
+	includedConfiguration, skippedConfiguration, err := getIncludedConfiguration(ctx, r.client, cluster)
+	if err != nil {
+		return false, err
+	}
+	if err := reconcileIncludedConfigurationCondition(ctx, r.client, cluster, skippedConfiguration); err != nil {
+		return false, err
+	}
+	reloadConfig, err := r.instance.RefreshConfigurationFilesFromCluster(ctx, cluster, false, includedConfiguration)
 	if err != nil {
 		return false, err
 	}
@@ -976,6 +1027,7 @@ func (r *InstanceReconciler) reconcileInstance(cluster *apiv1.Cluster) {
 	r.instance.MaxSwitchoverDelay = cluster.GetMaxSwitchoverDelay()
 	r.instance.MaxStopDelay = cluster.GetMaxStopDelay()
 	r.instance.SmartStopDelay = cluster.GetSmartShutdownTimeout()
+	r.instance.FencingGracePeriod = cluster.Spec.FencingGracePeriod
 	r.instance.RequiresDesignatedPrimaryTransition = detectRequiresDesignatedPrimaryTransition()
 }
 
@@ -1224,10 +1276,21 @@ func (r *InstanceReconciler) reconcilePrimary(ctx context.Context, cluster *apiv
 
 	// if the currentPrimary doesn't match the PodName we set the correct value.
 	if cluster.Status.CurrentPrimary != r.instance.GetPodName() {
-		cluster.Status.CurrentPrimary = r.instance.GetPodName()
-		cluster.Status.CurrentPrimaryTimestamp = pgTime.GetCurrentTimestamp()
+		db, err := r.instance.GetSuperUserDB()
+		if err != nil {
+			return err
+		}
 
-		if err := r.client.Status().Patch(ctx, cluster, client.MergeFrom(oldCluster)); err != nil {
+		// Wait for PostgreSQL to accept writes before moving the role
+		// label and the -rw service to this instance
+		if err := setCurrentPrimary(
+			ctx,
+			r.client,
+			cluster,
+			r.instance.GetPodName(),
+			db,
+			cluster.GetPromotionRecoveryTimeout(),
+		); err != nil {
 			return err
 		}
 
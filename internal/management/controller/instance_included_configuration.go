@@ -0,0 +1,126 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// getIncludedConfiguration reads the PostgreSQL configuration files to be
+// included from the config maps referenced by the cluster. The files that
+// can't be found, and the ones setting parameters reserved to the operator,
+// are skipped and returned together with the reason
+func getIncludedConfiguration(
+	ctx context.Context,
+	cli client.Client,
+	cluster *apiv1.Cluster,
+) ([]postgres.IncludedConfiguration, []string, error) {
+	contextLogger := log.FromContext(ctx)
+
+	references := cluster.Spec.PostgresConfiguration.IncludeConfigMaps
+	result := make([]postgres.IncludedConfiguration, 0, len(references))
+	var skipped []string
+	skip := func(reference apiv1.ConfigMapKeySelector, reason string) {
+		contextLogger.Warning("Skipping the included configuration",
+			"reference", reference,
+			"reason", reason)
+		skipped = append(skipped, fmt.Sprintf("%s/%s (%s)", reference.Name, reference.Key, reason))
+	}
+
+	for idx, reference := range references {
+		var configMap corev1.ConfigMap
+		err := cli.Get(
+			ctx,
+			client.ObjectKey{Namespace: cluster.Namespace, Name: reference.Name},
+			&configMap,
+		)
+		if apierrors.IsNotFound(err) {
+			skip(reference, "config map not found")
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("while getting the configuration to be included from %v: %w",
+				reference.Name, err)
+		}
+
+		content, ok := configMap.Data[reference.Key]
+		if !ok {
+			skip(reference, "key not found")
+			continue
+		}
+
+		if err := postgres.ValidateIncludedConfiguration(content); err != nil {
+			skip(reference, err.Error())
+			continue
+		}
+
+		result = append(result, postgres.IncludedConfiguration{
+			FileName: filepath.Join(
+				constants.PostgresqlIncludedConfigurationDirectory,
+				fmt.Sprintf("%d-%s.conf", idx, reference.Name)),
+			Content: content,
+		})
+	}
+
+	return result, skipped, nil
+}
+
+// reconcileIncludedConfigurationCondition sets the IncludedConfigurationSkipped
+// condition listing the configuration files that were skipped, and resets it
+// once every file is applied
+func reconcileIncludedConfigurationCondition(
+	ctx context.Context,
+	cli client.Client,
+	cluster *apiv1.Cluster,
+	skipped []string,
+) error {
+	if len(skipped) == 0 {
+		if !meta.IsStatusConditionTrue(cluster.Status.Conditions,
+			string(apiv1.ConditionIncludedConfigurationSkipped)) {
+			return nil
+		}
+
+		return conditions.Patch(ctx, cli, cluster, &metav1.Condition{
+			Type:    string(apiv1.ConditionIncludedConfigurationSkipped),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(apiv1.ConditionReasonIncludedConfigurationApplied),
+			Message: "Every included configuration file has been applied",
+		})
+	}
+
+	return conditions.Patch(ctx, cli, cluster, &metav1.Condition{
+		Type:    string(apiv1.ConditionIncludedConfigurationSkipped),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(apiv1.ConditionReasonIncludedConfigurationInvalid),
+		Message: "Skipped included configuration files: " + strings.Join(skipped, ", "),
+	})
+}
@@ -0,0 +1,137 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("getIncludedConfiguration", func() {
+	var (
+		cluster    *apiv1.Cluster
+		fakeClient client.Client
+	)
+
+	reference := func(name, key string) apiv1.ConfigMapKeySelector {
+		return apiv1.ConfigMapKeySelector{LocalObjectReference: apiv1.LocalObjectReference{Name: name}, Key: key}
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		}
+		fakeClient = fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "default"},
+					Data: map[string]string{
+						"memory.conf": "work_mem = '8MB'\n",
+						"port.conf":   "port = 5433\n",
+					},
+				},
+			).
+			Build()
+	})
+
+	It("reads the configuration files from the config maps, in order", func(ctx SpecContext) {
+		cluster.Spec.PostgresConfiguration.IncludeConfigMaps = []apiv1.ConfigMapKeySelector{
+			reference("shared", "memory.conf"),
+		}
+
+		included, skipped, err := getIncludedConfiguration(ctx, fakeClient, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(skipped).To(BeEmpty())
+		Expect(included).To(HaveLen(1))
+		Expect(included[0].FileName).To(Equal("included.conf.d/0-shared.conf"))
+		Expect(included[0].Content).To(Equal("work_mem = '8MB'\n"))
+	})
+
+	It("skips the missing or invalid files", func(ctx SpecContext) {
+		cluster.Spec.PostgresConfiguration.IncludeConfigMaps = []apiv1.ConfigMapKeySelector{
+			reference("shared", "port.conf"),
+			reference("shared", "missing.conf"),
+			reference("not-existing", "memory.conf"),
+			reference("shared", "memory.conf"),
+		}
+
+		included, skipped, err := getIncludedConfiguration(ctx, fakeClient, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(included).To(HaveLen(1))
+		Expect(included[0].FileName).To(Equal("included.conf.d/3-shared.conf"))
+		Expect(skipped).To(HaveLen(3))
+		Expect(skipped[0]).To(HavePrefix("shared/port.conf ("))
+		Expect(skipped[1]).To(Equal("shared/missing.conf (key not found)"))
+		Expect(skipped[2]).To(Equal("not-existing/memory.conf (config map not found)"))
+	})
+})
+
+var _ = Describe("reconcileIncludedConfigurationCondition", func() {
+	var (
+		cluster    *apiv1.Cluster
+		fakeClient client.Client
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		}
+		fakeClient = fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(cluster).
+			WithStatusSubresource(cluster).
+			Build()
+	})
+
+	getCondition := func(ctx SpecContext) *metav1.Condition {
+		var updated apiv1.Cluster
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(cluster), &updated)).To(Succeed())
+		return meta.FindStatusCondition(updated.Status.Conditions,
+			string(apiv1.ConditionIncludedConfigurationSkipped))
+	}
+
+	It("doesn't set the condition when nothing was skipped", func(ctx SpecContext) {
+		Expect(reconcileIncludedConfigurationCondition(ctx, fakeClient, cluster, nil)).To(Succeed())
+		Expect(getCondition(ctx)).To(BeNil())
+	})
+
+	It("lists the skipped files and resets the condition once they are applied", func(ctx SpecContext) {
+		skipped := []string{"shared/missing.conf (key not found)"}
+		Expect(reconcileIncludedConfigurationCondition(ctx, fakeClient, cluster, skipped)).To(Succeed())
+
+		condition := getCondition(ctx)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonIncludedConfigurationInvalid)))
+		Expect(condition.Message).To(ContainSubstring("shared/missing.conf (key not found)"))
+
+		Expect(reconcileIncludedConfigurationCondition(ctx, fakeClient, cluster, nil)).To(Succeed())
+
+		condition = getCondition(ctx)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonIncludedConfigurationApplied)))
+	})
+})
@@ -0,0 +1,117 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	pgTime "github.com/cloudnative-pg/machinery/pkg/postgres/time"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+)
+
+// promotionRecoveryCheckInterval is the time between two checks of
+// the recovery status of a promoted instance
+var promotionRecoveryCheckInterval = time.Second
+
+// errPromotionStuck is raised when a promoted instance didn't end the
+// recovery within the promotion recovery timeout
+var errPromotionStuck = errors.New("the promoted instance is still in recovery")
+
+// setCurrentPrimary marks the instance as the current primary of the cluster,
+// which moves the role label and the -rw service to it. This only happens
+// once PostgreSQL has ended the recovery following the promotion, and the
+// PromotionStuck condition is raised when that doesn't happen in time
+func setCurrentPrimary(
+	ctx context.Context,
+	cli client.Client,
+	cluster *apiv1.Cluster,
+	podName string,
+	db *sql.DB,
+	timeout time.Duration,
+) error {
+	if err := waitForRecoveryEnd(ctx, db, timeout); err != nil {
+		if !errors.Is(err, errPromotionStuck) {
+			return err
+		}
+
+		condition := &metav1.Condition{
+			Type:   string(apiv1.ConditionPromotionStuck),
+			Status: metav1.ConditionTrue,
+			Reason: string(apiv1.ConditionReasonRecoveryNotEnded),
+			Message: fmt.Sprintf("Instance %s is still in recovery %s after having been promoted",
+				podName, timeout),
+		}
+		if errCond := conditions.Patch(ctx, cli, cluster, condition); errCond != nil {
+			return errCond
+		}
+		return err
+	}
+
+	oldCluster := cluster.DeepCopy()
+	cluster.Status.CurrentPrimary = podName
+	cluster.Status.CurrentPrimaryTimestamp = pgTime.GetCurrentTimestamp()
+	if meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionPromotionStuck)) != nil {
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:    string(apiv1.ConditionPromotionStuck),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(apiv1.ConditionReasonRecoveryEnded),
+			Message: fmt.Sprintf("Instance %s ended the recovery and is the current primary", podName),
+		})
+	}
+
+	return cli.Status().Patch(ctx, cluster, client.MergeFrom(oldCluster))
+}
+
+// waitForRecoveryEnd polls `pg_is_in_recovery()` until it returns false,
+// raising errPromotionStuck when it's still true after the timeout
+func waitForRecoveryEnd(ctx context.Context, db *sql.DB, timeout time.Duration) error {
+	contextLogger := log.FromContext(ctx)
+	deadline := time.Now().Add(timeout)
+
+	for {
+		var inRecovery bool
+		row := db.QueryRowContext(ctx, "SELECT pg_catalog.pg_is_in_recovery()")
+		if err := row.Scan(&inRecovery); err != nil {
+			return fmt.Errorf("while checking if the promoted instance is in recovery: %w", err)
+		}
+
+		if !inRecovery {
+			return nil
+		}
+
+		if !time.Now().Before(deadline) {
+			return errPromotionStuck
+		}
+
+		contextLogger.Info("The promoted instance is still in recovery, waiting")
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(promotionRecoveryCheckInterval):
+		}
+	}
+}
@@ -0,0 +1,170 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/instance"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("setCurrentPrimary", func() {
+	const recoveryQuery = "SELECT pg_catalog.pg_is_in_recovery()"
+
+	var (
+		db         *sql.DB
+		dbMock     sqlmock.Sqlmock
+		cluster    *apiv1.Cluster
+		pods       []corev1.Pod
+		fakeClient client.Client
+	)
+
+	newPod := func(name, role string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					utils.ClusterRoleLabelName:         role,
+					utils.ClusterInstanceRoleLabelName: role,
+				},
+			},
+		}
+	}
+
+	recoveryRows := func(inRecovery bool) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"pg_is_in_recovery"}).AddRow(inRecovery)
+	}
+
+	// getInstanceRoles reconciles the metadata of the pods, as the operator
+	// does, and returns the role label of each of them
+	getInstanceRoles := func(ctx SpecContext) map[string]string {
+		var livingCluster apiv1.Cluster
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(cluster), &livingCluster)).To(Succeed())
+		Expect(instance.ReconcileMetadata(ctx, fakeClient, &livingCluster, pods)).To(Succeed())
+
+		result := make(map[string]string, len(pods))
+		for _, pod := range pods {
+			var livingPod corev1.Pod
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(&pod), &livingPod)).To(Succeed())
+			result[pod.Name] = livingPod.Labels[utils.ClusterInstanceRoleLabelName]
+		}
+		return result
+	}
+
+	BeforeEach(func() {
+		var err error
+		db, dbMock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		promotionRecoveryCheckInterval = 10 * time.Millisecond
+		DeferCleanup(func() {
+			promotionRecoveryCheckInterval = time.Second
+		})
+
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-2",
+			},
+		}
+		formerPrimary := newPod("cluster-example-1", specs.ClusterRoleLabelPrimary)
+		promotedInstance := newPod("cluster-example-2", specs.ClusterRoleLabelReplica)
+		pods = []corev1.Pod{*formerPrimary, *promotedInstance}
+
+		fakeClient = fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(cluster, formerPrimary, promotedInstance).
+			WithStatusSubresource(&apiv1.Cluster{}).
+			Build()
+	})
+
+	AfterEach(func() {
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("flips the role label only after the recovery ended", func(ctx SpecContext) {
+		dbMock.ExpectQuery(recoveryQuery).WillReturnRows(recoveryRows(true))
+		dbMock.ExpectQuery(recoveryQuery).WillReturnRows(recoveryRows(true))
+		dbMock.ExpectQuery(recoveryQuery).WillReturnRows(recoveryRows(false))
+
+		Expect(getInstanceRoles(ctx)).To(Equal(map[string]string{
+			"cluster-example-1": specs.ClusterRoleLabelPrimary,
+			"cluster-example-2": specs.ClusterRoleLabelReplica,
+		}))
+
+		Expect(setCurrentPrimary(ctx, fakeClient, cluster, "cluster-example-2", db, time.Minute)).To(Succeed())
+		Expect(cluster.Status.CurrentPrimary).To(Equal("cluster-example-2"))
+		Expect(getInstanceRoles(ctx)).To(Equal(map[string]string{
+			"cluster-example-1": specs.ClusterRoleLabelReplica,
+			"cluster-example-2": specs.ClusterRoleLabelPrimary,
+		}))
+	})
+
+	It("raises the PromotionStuck condition when the recovery doesn't end in time", func(ctx SpecContext) {
+		dbMock.ExpectQuery(recoveryQuery).WillReturnRows(recoveryRows(true))
+
+		err := setCurrentPrimary(ctx, fakeClient, cluster, "cluster-example-2", db, 0)
+		Expect(err).To(MatchError(errPromotionStuck))
+
+		var livingCluster apiv1.Cluster
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(cluster), &livingCluster)).To(Succeed())
+		Expect(livingCluster.Status.CurrentPrimary).To(Equal("cluster-example-1"))
+		condition := meta.FindStatusCondition(livingCluster.Status.Conditions, string(apiv1.ConditionPromotionStuck))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonRecoveryNotEnded)))
+
+		Expect(getInstanceRoles(ctx)).To(Equal(map[string]string{
+			"cluster-example-1": specs.ClusterRoleLabelPrimary,
+			"cluster-example-2": specs.ClusterRoleLabelReplica,
+		}))
+	})
+
+	It("clears the PromotionStuck condition once the recovery ended", func(ctx SpecContext) {
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:   string(apiv1.ConditionPromotionStuck),
+			Status: metav1.ConditionTrue,
+			Reason: string(apiv1.ConditionReasonRecoveryNotEnded),
+		})
+		Expect(fakeClient.Status().Update(ctx, cluster)).To(Succeed())
+		dbMock.ExpectQuery(recoveryQuery).WillReturnRows(recoveryRows(false))
+
+		Expect(setCurrentPrimary(ctx, fakeClient, cluster, "cluster-example-2", db, time.Minute)).To(Succeed())
+
+		var livingCluster apiv1.Cluster
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(cluster), &livingCluster)).To(Succeed())
+		Expect(livingCluster.Status.CurrentPrimary).To(Equal("cluster-example-2"))
+		Expect(meta.IsStatusConditionFalse(livingCluster.Status.Conditions,
+			string(apiv1.ConditionPromotionStuck))).To(BeTrue())
+	})
+})
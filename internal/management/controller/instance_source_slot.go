@@ -0,0 +1,109 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/external"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// dropSourceReplicationSlotFunc drops a replication slot from the source
+// of a replica cluster. It is a variable to be replaced in the unit tests
+var dropSourceReplicationSlotFunc = postgres.DropSourceReplicationSlot
+
+// reconcileSourceReplicationSlot keeps track, in the status of the cluster,
+// of the replication slot the designated primary created on the source.
+// When the replica cluster is promoted or starts streaming from a different
+// source, the slot is dropped from the server it has been created on, so
+// that it doesn't retain the WAL files there forever
+func (r *InstanceReconciler) reconcileSourceReplicationSlot(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) error {
+	if cluster.Status.CurrentPrimary != r.instance.GetPodName() {
+		return nil
+	}
+
+	currentSlot := cluster.Status.SourceReplicationSlot
+	desiredSlot := getDesiredSourceReplicationSlot(cluster)
+	if reflect.DeepEqual(currentSlot, desiredSlot) {
+		return nil
+	}
+
+	if currentSlot != nil {
+		if err := r.dropSourceReplicationSlot(ctx, cluster, currentSlot); err != nil {
+			return err
+		}
+	}
+
+	origCluster := cluster.DeepCopy()
+	cluster.Status.SourceReplicationSlot = desiredSlot
+	return r.client.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// getDesiredSourceReplicationSlot returns the replication slot the designated
+// primary needs on the source, or nil if it doesn't need one
+func getDesiredSourceReplicationSlot(cluster *apiv1.Cluster) *apiv1.SourceReplicationSlotStatus {
+	slotName := cluster.GetSourceSlotName()
+	if slotName == "" {
+		return nil
+	}
+
+	return &apiv1.SourceReplicationSlotStatus{
+		SlotName: slotName,
+		Source:   cluster.Spec.ReplicaCluster.Source,
+	}
+}
+
+// dropSourceReplicationSlot drops the passed replication slot from the
+// external cluster it has been created on. If that external cluster has been
+// removed from the definition, the slot can't be reached and needs to be
+// dropped manually
+func (r *InstanceReconciler) dropSourceReplicationSlot(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	slot *apiv1.SourceReplicationSlotStatus,
+) error {
+	contextLogger := log.FromContext(ctx).WithValues("slotName", slot.SlotName, "source", slot.Source)
+
+	server, ok := cluster.ExternalCluster(slot.Source)
+	if !ok {
+		contextLogger.Warning("Cannot drop the replication slot from a source that is not " +
+			"defined anymore, it needs to be dropped manually")
+		return nil
+	}
+
+	connectionString, err := external.ConfigureConnectionToServer(
+		ctx, r.client, r.instance.GetNamespaceName(), &server)
+	if err != nil {
+		return err
+	}
+
+	if err := dropSourceReplicationSlotFunc(ctx, connectionString, slot.SlotName); err != nil {
+		return err
+	}
+
+	contextLogger.Info("Dropped the replication slot from the former source")
+	return nil
+}
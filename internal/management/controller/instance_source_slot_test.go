@@ -0,0 +1,175 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("reconcileSourceReplicationSlot", func() {
+	var (
+		cluster      *apiv1.Cluster
+		droppedSlots []string
+		dropError    error
+	)
+
+	BeforeEach(func() {
+		droppedSlots = nil
+		dropError = nil
+		dropSourceReplicationSlotFunc = func(_ context.Context, connectionString, slotName string) error {
+			droppedSlots = append(droppedSlots, connectionString+" "+slotName)
+			return dropError
+		}
+		DeferCleanup(func() {
+			dropSourceReplicationSlotFunc = postgres.DropSourceReplicationSlot
+		})
+
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-dr",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				ReplicaCluster: &apiv1.ReplicaClusterConfiguration{
+					Enabled: ptr.To(true),
+					Source:  "cluster-example",
+				},
+				ReplicationSlots: &apiv1.ReplicationSlotsConfiguration{
+					Prefix: "_dr_",
+				},
+				ExternalClusters: []apiv1.ExternalCluster{
+					{
+						Name:                 "cluster-example",
+						ConnectionParameters: map[string]string{"host": "cluster-example-rw"},
+					},
+					{
+						Name:                 "cluster-other",
+						ConnectionParameters: map[string]string{"host": "cluster-other-rw"},
+					},
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-dr-1",
+				TargetPrimary:  "cluster-dr-1",
+			},
+		}
+	})
+
+	reconcile := func(ctx context.Context) (*apiv1.Cluster, error) {
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(cluster).
+			WithStatusSubresource(cluster).
+			Build()
+		r := &InstanceReconciler{
+			client:   fakeClient,
+			instance: postgres.NewInstance().WithPodName("cluster-dr-1").WithNamespace("default"),
+		}
+		err := r.reconcileSourceReplicationSlot(ctx, cluster)
+
+		var livingCluster apiv1.Cluster
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(cluster), &livingCluster)).To(Succeed())
+		return &livingCluster, err
+	}
+
+	It("records the replication slot used by the designated primary", func(ctx SpecContext) {
+		livingCluster, err := reconcile(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(livingCluster.Status.SourceReplicationSlot).To(Equal(&apiv1.SourceReplicationSlotStatus{
+			SlotName: "_dr_cluster_dr",
+			Source:   "cluster-example",
+		}))
+		Expect(droppedSlots).To(BeEmpty())
+	})
+
+	It("drops the replication slot from the source when the cluster is promoted", func(ctx SpecContext) {
+		cluster.Spec.ReplicaCluster.Enabled = ptr.To(false)
+		cluster.Status.SourceReplicationSlot = &apiv1.SourceReplicationSlotStatus{
+			SlotName: "_dr_cluster_dr",
+			Source:   "cluster-example",
+		}
+
+		livingCluster, err := reconcile(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(livingCluster.Status.SourceReplicationSlot).To(BeNil())
+		Expect(droppedSlots).To(ConsistOf("host='cluster-example-rw' _dr_cluster_dr"))
+	})
+
+	It("drops the replication slot from the former source when the source changes", func(ctx SpecContext) {
+		cluster.Spec.ReplicaCluster.Source = "cluster-other"
+		cluster.Status.SourceReplicationSlot = &apiv1.SourceReplicationSlotStatus{
+			SlotName: "_dr_cluster_dr",
+			Source:   "cluster-example",
+		}
+
+		livingCluster, err := reconcile(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(livingCluster.Status.SourceReplicationSlot).To(Equal(&apiv1.SourceReplicationSlotStatus{
+			SlotName: "_dr_cluster_dr",
+			Source:   "cluster-other",
+		}))
+		Expect(droppedSlots).To(ConsistOf("host='cluster-example-rw' _dr_cluster_dr"))
+	})
+
+	It("keeps tracking the replication slot when it can't be dropped", func(ctx SpecContext) {
+		cluster.Spec.ReplicaCluster.Enabled = ptr.To(false)
+		cluster.Status.SourceReplicationSlot = &apiv1.SourceReplicationSlotStatus{
+			SlotName: "_dr_cluster_dr",
+			Source:   "cluster-example",
+		}
+		dropError = errors.New("replication slot is active")
+
+		livingCluster, err := reconcile(ctx)
+		Expect(err).To(MatchError(dropError))
+		Expect(livingCluster.Status.SourceReplicationSlot).ToNot(BeNil())
+	})
+
+	It("forgets the replication slot when the source is not defined anymore", func(ctx SpecContext) {
+		cluster.Spec.ReplicaCluster.Enabled = ptr.To(false)
+		cluster.Spec.ExternalClusters = nil
+		cluster.Status.SourceReplicationSlot = &apiv1.SourceReplicationSlotStatus{
+			SlotName: "_dr_cluster_dr",
+			Source:   "cluster-example",
+		}
+
+		livingCluster, err := reconcile(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(livingCluster.Status.SourceReplicationSlot).To(BeNil())
+		Expect(droppedSlots).To(BeEmpty())
+	})
+
+	It("doesn't do anything on the replicas", func(ctx SpecContext) {
+		cluster.Status.CurrentPrimary = "cluster-dr-2"
+
+		livingCluster, err := reconcile(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(livingCluster.Status.SourceReplicationSlot).To(BeNil())
+	})
+})
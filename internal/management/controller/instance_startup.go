@@ -283,11 +283,43 @@ func (r *InstanceReconciler) verifyPgDataCoherenceForPrimary(ctx context.Context
 			}
 		}
 
+		if cluster.ShouldCleanupDivergedWALOnRejoin() {
+			r.cleanupDivergedWAL(ctx, cluster)
+		}
+
 		// Now I can demote myself
 		return r.instance.Demote(ctx, cluster)
 	}
 }
 
+// cleanupDivergedWAL removes the WAL files belonging to the timeline this
+// former primary diverged on, and records the outcome in the cluster status.
+// Errors are logged but not returned, as they don't prevent the instance
+// from following the new primary.
+func (r *InstanceReconciler) cleanupDivergedWAL(ctx context.Context, cluster *apiv1.Cluster) {
+	contextLogger := log.FromContext(ctx)
+
+	timeline, removedFiles, err := r.instance.CleanupDivergedWAL(ctx)
+	if err != nil {
+		contextLogger.Error(err, "Error while removing the WAL files of the diverged timeline, skipped")
+		return
+	}
+	if len(removedFiles) == 0 {
+		return
+	}
+
+	oldCluster := cluster.DeepCopy()
+	cluster.Status.LastRejoinWALCleanup = &apiv1.RejoinWALCleanupStatus{
+		InstanceName: r.instance.GetPodName(),
+		Timeline:     timeline,
+		RemovedFiles: len(removedFiles),
+		Timestamp:    pgTime.GetCurrentTimestamp(),
+	}
+	if err := r.client.Status().Patch(ctx, cluster, client.MergeFrom(oldCluster)); err != nil {
+		contextLogger.Error(err, "Error while recording the removal of the diverged WAL files, skipped")
+	}
+}
+
 // ReconcileWalStorage moves the files from PGDATA/pg_wal to the volume attached, if exists, and
 // creates a symlink for it
 func (r *InstanceReconciler) ReconcileWalStorage(ctx context.Context) error {
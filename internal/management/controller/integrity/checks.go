@@ -0,0 +1,207 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integrity
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// methodChecksums is the method verifying the data page checksums
+	// of a stopped instance with pg_checksums
+	methodChecksums = "checksums"
+
+	// methodAmcheck is the method verifying the data files of a running
+	// instance with the amcheck extension
+	methodAmcheck = "amcheck"
+
+	pgChecksumsName = "pg_checksums"
+)
+
+const (
+	databasesQuery = `SELECT datname FROM pg_catalog.pg_database
+WHERE datallowconn AND NOT datistemplate ORDER BY datname`
+
+	amcheckSchemaQuery = `SELECT n.nspname FROM pg_catalog.pg_extension e
+JOIN pg_catalog.pg_namespace n ON n.oid = e.extnamespace
+WHERE e.extname = 'amcheck'`
+
+	heapCheckAvailableQuery = `SELECT pg_catalog.to_regproc($1) IS NOT NULL`
+
+	// Unlogged and temporary relations can't be accessed on a replica
+	indexesQuery = `SELECT c.oid::regclass::text FROM pg_catalog.pg_index i
+JOIN pg_catalog.pg_class c ON c.oid = i.indexrelid
+JOIN pg_catalog.pg_am am ON am.oid = c.relam
+WHERE am.amname = 'btree' AND c.relpersistence = 'p' AND i.indisready AND i.indisvalid
+ORDER BY 1`
+
+	tablesQuery = `SELECT c.oid::regclass::text FROM pg_catalog.pg_class c
+WHERE c.relkind IN ('r', 'm', 't') AND c.relpersistence = 'p'
+ORDER BY 1`
+)
+
+var (
+	badChecksumsRegex  = regexp.MustCompile(`(?m)^Bad checksums:\s+(\d+)`)
+	checksumErrorRegex = regexp.MustCompile(`(?m)^.*checksum verification failed.*$`)
+
+	// errAmcheckNotInstalled is raised when the amcheck extension is not
+	// available in a database
+	errAmcheckNotInstalled = errors.New("amcheck extension not installed")
+)
+
+// checkResult is the outcome of an integrity check
+type checkResult struct {
+	method  string
+	errors  int
+	message string
+}
+
+// addErrors records the detected corruption errors, keeping the
+// message of the first one
+func (result *checkResult) addErrors(count int, message string) {
+	result.errors += count
+	if result.message == "" {
+		result.message = message
+	}
+}
+
+// runChecksums verifies the data page checksums of the stopped instance
+// having the passed data directory
+func runChecksums(ctx context.Context, pgData string) (*checkResult, error) {
+	cmd := exec.CommandContext(ctx, pgChecksumsName, "--check", "-D", pgData) // #nosec
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	runErr := cmd.Run()
+
+	result, err := parseChecksumsOutput(output.String())
+	if err != nil {
+		if runErr != nil {
+			return nil, fmt.Errorf("while executing %s: %w: %s", pgChecksumsName, runErr, output.String())
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// parseChecksumsOutput parses the output of pg_checksums --check
+func parseChecksumsOutput(output string) (*checkResult, error) {
+	matches := badChecksumsRegex.FindStringSubmatch(output)
+	if len(matches) != 2 {
+		return nil, fmt.Errorf("unexpected %s output: %q", pgChecksumsName, output)
+	}
+
+	badChecksums, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return nil, err
+	}
+
+	result := &checkResult{method: methodChecksums}
+	if badChecksums > 0 {
+		result.addErrors(badChecksums, checksumErrorRegex.FindString(output))
+	}
+
+	return result, nil
+}
+
+// runAmcheck verifies the B-tree indexes and, when available, the tables
+// of the passed database using the amcheck extension. Only functions
+// acquiring an AccessShareLock are used, so that the check doesn't block
+// the workload and can be executed on a replica
+func runAmcheck(ctx context.Context, db *sql.DB, dbname string, result *checkResult) error {
+	var schema string
+	err := db.QueryRowContext(ctx, amcheckSchemaQuery).Scan(&schema)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errAmcheckNotInstalled
+	}
+	if err != nil {
+		return err
+	}
+	schema = pgx.Identifier{schema}.Sanitize()
+
+	indexes, err := listRelations(ctx, db, indexesQuery)
+	if err != nil {
+		return err
+	}
+	for _, index := range indexes {
+		query := fmt.Sprintf("SELECT %s.bt_index_check($1::regclass)", schema)
+		if _, err := db.ExecContext(ctx, query, index); err != nil {
+			result.addErrors(1, fmt.Sprintf("database %s, index %s: %s", dbname, index, err.Error()))
+		}
+	}
+
+	// verify_heapam is available since PostgreSQL 14
+	var heapCheckAvailable bool
+	if err := db.QueryRowContext(ctx, heapCheckAvailableQuery, schema+".verify_heapam").
+		Scan(&heapCheckAvailable); err != nil {
+		return err
+	}
+	if !heapCheckAvailable {
+		return nil
+	}
+
+	tables, err := listRelations(ctx, db, tablesQuery)
+	if err != nil {
+		return err
+	}
+	for _, table := range tables {
+		var count int
+		var message sql.NullString
+		query := fmt.Sprintf("SELECT count(*), min(msg) FROM %s.verify_heapam($1::regclass)", schema)
+		if err := db.QueryRowContext(ctx, query, table).Scan(&count, &message); err != nil {
+			result.addErrors(1, fmt.Sprintf("database %s, table %s: %s", dbname, table, err.Error()))
+			continue
+		}
+		if count > 0 {
+			result.addErrors(count, fmt.Sprintf("database %s, table %s: %s", dbname, table, message.String))
+		}
+	}
+
+	return nil
+}
+
+// listRelations returns the names of the relations selected by the passed query
+func listRelations(ctx context.Context, db *sql.DB, query string) ([]string, error) {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+
+	return names, rows.Err()
+}
@@ -0,0 +1,132 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integrity
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("parseChecksumsOutput", func() {
+	It("reports no errors when the data files are healthy", func() {
+		output := `Checksum operation completed
+Files scanned:   1240
+Blocks scanned:  3519
+Bad checksums:  0
+Data checksum version: 1
+`
+		result, err := parseChecksumsOutput(output)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.method).To(Equal(methodChecksums))
+		Expect(result.errors).To(BeZero())
+		Expect(result.message).To(BeEmpty())
+	})
+
+	It("detects a corrupted page", func() {
+		output := `pg_checksums: error: checksum verification failed in file "/var/lib/postgresql/data/pgdata/base/5/16384", ` +
+			`block 0: calculated checksum 5C1B but block contains 9D2F
+Checksum operation completed
+Files scanned:   1240
+Blocks scanned:  3519
+Bad checksums:  1
+Data checksum version: 1
+`
+		result, err := parseChecksumsOutput(output)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.errors).To(Equal(1))
+		Expect(result.message).To(ContainSubstring("base/5/16384"))
+	})
+
+	It("fails when the output can't be parsed", func() {
+		_, err := parseChecksumsOutput("pg_checksums: error: cluster must be shut down")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("runAmcheck", func() {
+	var (
+		db   *sql.DB
+		mock sqlmock.Sqlmock
+		ctx  context.Context
+	)
+
+	BeforeEach(func() {
+		var err error
+		ctx = context.Background()
+		db, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("returns an error when amcheck is not installed", func() {
+		mock.ExpectQuery(amcheckSchemaQuery).WillReturnRows(sqlmock.NewRows([]string{"nspname"}))
+
+		err := runAmcheck(ctx, db, "app", &checkResult{method: methodAmcheck})
+		Expect(err).To(MatchError(errAmcheckNotInstalled))
+	})
+
+	It("detects a corrupted index and a corrupted table", func() {
+		mock.ExpectQuery(amcheckSchemaQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"nspname"}).AddRow("public"))
+		mock.ExpectQuery(indexesQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"oid"}).AddRow("test_pkey").AddRow("test_idx"))
+		mock.ExpectExec(`SELECT "public".bt_index_check($1::regclass)`).
+			WithArgs("test_pkey").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`SELECT "public".bt_index_check($1::regclass)`).
+			WithArgs("test_idx").
+			WillReturnError(errors.New(`index "test_idx" contains corrupted page at block 1`))
+		mock.ExpectQuery(heapCheckAvailableQuery).
+			WithArgs(`"public".verify_heapam`).
+			WillReturnRows(sqlmock.NewRows([]string{"available"}).AddRow(true))
+		mock.ExpectQuery(tablesQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"oid"}).AddRow("test"))
+		mock.ExpectQuery(`SELECT count(*), min(msg) FROM "public".verify_heapam($1::regclass)`).
+			WithArgs("test").
+			WillReturnRows(sqlmock.NewRows([]string{"count", "min"}).
+				AddRow(2, "line pointer to page offset 21 is not maximally aligned"))
+
+		result := &checkResult{method: methodAmcheck}
+		Expect(runAmcheck(ctx, db, "app", result)).To(Succeed())
+		Expect(result.errors).To(Equal(3))
+		Expect(result.message).To(Equal(
+			`database app, index test_idx: index "test_idx" contains corrupted page at block 1`))
+	})
+
+	It("skips the table verification when verify_heapam is not available", func() {
+		mock.ExpectQuery(amcheckSchemaQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"nspname"}).AddRow("public"))
+		mock.ExpectQuery(indexesQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"oid"}))
+		mock.ExpectQuery(heapCheckAvailableQuery).
+			WithArgs(`"public".verify_heapam`).
+			WillReturnRows(sqlmock.NewRows([]string{"available"}).AddRow(false))
+
+		result := &checkResult{method: methodAmcheck}
+		Expect(runAmcheck(ctx, db, "app", result)).To(Succeed())
+		Expect(result.errors).To(BeZero())
+	})
+})
@@ -0,0 +1,218 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integrity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	pgTime "github.com/cloudnative-pg/machinery/pkg/postgres/time"
+	"github.com/robfig/cron"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// A Checker is a Kubernetes manager.Runnable that periodically verifies
+// the integrity of the data files of this instance, when it is
+// designated to do so, and reports the outcome in the cluster status
+//
+// c.f. https://pkg.go.dev/sigs.k8s.io/controller-runtime/pkg/manager#Runnable
+type Checker struct {
+	instance *postgres.Instance
+	client   client.Client
+}
+
+// NewChecker creates a new integrity Checker
+func NewChecker(instance *postgres.Instance, client client.Client) *Checker {
+	return &Checker{
+		instance: instance,
+		client:   client,
+	}
+}
+
+// Start starts running the integrity Checker
+func (c *Checker) Start(ctx context.Context) error {
+	contextLog := log.FromContext(ctx).WithName("integrity_checker")
+	go func() {
+		var config *apiv1.IntegrityCheckConfiguration
+		var schedule cron.Schedule
+		var next <-chan time.Time
+
+		defer func() {
+			contextLog.Info("Terminated integrity checker loop")
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case newConfig := <-c.instance.IntegrityCheckerChan():
+				if reflect.DeepEqual(newConfig, config) {
+					continue
+				}
+
+				config, next = newConfig, nil
+				if config == nil {
+					continue
+				}
+
+				var err error
+				if schedule, err = cron.Parse(config.Schedule); err != nil {
+					contextLog.Warning("invalid integrity check schedule", "schedule", config.Schedule, "err", err)
+					continue
+				}
+				next = time.After(time.Until(schedule.Next(time.Now())))
+				continue
+
+			case <-next:
+			}
+
+			if err := c.check(ctx); err != nil {
+				contextLog.Warning("verifying the integrity of the data files", "err", err)
+			}
+			next = time.After(time.Until(schedule.Next(time.Now())))
+		}
+	}()
+	<-ctx.Done()
+	return nil
+}
+
+// check executes the integrity check and records its outcome in the
+// cluster status. A fenced instance, which has PostgreSQL stopped, is verified
+// with pg_checksums, while a running one is verified with amcheck
+func (c *Checker) check(ctx context.Context) error {
+	contextLog := log.FromContext(ctx).WithName("integrity_checker")
+
+	var result *checkResult
+	if c.instance.IsFenced() {
+		checksumsEnabled, err := c.dataChecksumsEnabled()
+		if err != nil {
+			return err
+		}
+		if !checksumsEnabled {
+			contextLog.Info("Integrity check skipped: the instance is fenced and data checksums are disabled")
+			return nil
+		}
+
+		contextLog.Info("Starting the integrity check", "method", methodChecksums)
+		if result, err = runChecksums(ctx, c.instance.PgData); err != nil {
+			return err
+		}
+	} else {
+		if isPrimary, err := c.instance.IsPrimary(); err != nil || isPrimary {
+			return err
+		}
+		if err := c.instance.IsServerHealthy(); err != nil {
+			contextLog.Info("Integrity check skipped: the instance is not healthy", "err", err)
+			return nil
+		}
+
+		contextLog.Info("Starting the integrity check", "method", methodAmcheck)
+		var err error
+		if result, err = c.runAmcheck(ctx); err != nil {
+			return err
+		}
+	}
+
+	contextLog.Info("Integrity check completed",
+		"method", result.method,
+		"errors", result.errors,
+		"message", result.message)
+
+	return c.updateStatus(ctx, result)
+}
+
+// runAmcheck verifies every database of the instance with amcheck
+func (c *Checker) runAmcheck(ctx context.Context) (*checkResult, error) {
+	superUserDB, err := c.instance.GetSuperUserDB()
+	if err != nil {
+		return nil, err
+	}
+
+	databases, err := listRelations(ctx, superUserDB, databasesQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &checkResult{method: methodAmcheck}
+	var skippedDatabases []string
+	for _, dbname := range databases {
+		db, err := c.instance.ConnectionPool().Connection(dbname)
+		if err != nil {
+			return nil, err
+		}
+
+		err = runAmcheck(ctx, db, dbname, result)
+		if errors.Is(err, errAmcheckNotInstalled) {
+			skippedDatabases = append(skippedDatabases, dbname)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("while verifying database %s: %w", dbname, err)
+		}
+	}
+
+	if result.message == "" && len(skippedDatabases) > 0 {
+		result.message = fmt.Sprintf("%s, skipped databases: %s",
+			errAmcheckNotInstalled.Error(), strings.Join(skippedDatabases, ", "))
+	}
+
+	return result, nil
+}
+
+// dataChecksumsEnabled checks whether the data page checksums are enabled
+func (c *Checker) dataChecksumsEnabled() (bool, error) {
+	pgControlData, err := c.instance.GetPgControldata()
+	if err != nil {
+		return false, err
+	}
+
+	version, ok := utils.ParsePgControldataOutput(pgControlData)["Data page checksum version"]
+	return ok && version != "0", nil
+}
+
+// updateStatus records the outcome of the integrity check in the cluster status
+func (c *Checker) updateStatus(ctx context.Context, result *checkResult) error {
+	var cluster apiv1.Cluster
+	if err := c.client.Get(ctx, types.NamespacedName{
+		Name:      c.instance.GetClusterName(),
+		Namespace: c.instance.GetNamespaceName(),
+	}, &cluster); err != nil {
+		return err
+	}
+
+	origCluster := cluster.DeepCopy()
+	cluster.Status.IntegrityCheck = &apiv1.IntegrityCheckStatus{
+		InstanceName:  c.instance.GetPodName(),
+		Method:        result.method,
+		Errors:        result.errors,
+		Message:       result.message,
+		LastCheckTime: pgTime.GetCurrentTimestamp(),
+	}
+
+	return c.client.Status().Patch(ctx, &cluster, client.MergeFrom(origCluster))
+}
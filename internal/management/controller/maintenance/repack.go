@@ -0,0 +1,187 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenance
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+const (
+	pgRepackName = "pg_repack"
+
+	// superUserName is the user pg_repack connects as
+	superUserName = "postgres"
+)
+
+const (
+	extensionAvailableQuery = `SELECT count(*) > 0 FROM pg_catalog.pg_available_extensions
+WHERE name = 'pg_repack'`
+
+	extensionInstalledQuery = `SELECT count(*) > 0 FROM pg_catalog.pg_extension
+WHERE extname = 'pg_repack'`
+
+	replicationLagQuery = `SELECT application_name,
+COALESCE(pg_catalog.pg_wal_lsn_diff(pg_catalog.pg_current_wal_lsn(), replay_lsn), 0)::bigint
+FROM pg_catalog.pg_stat_replication
+ORDER BY application_name`
+
+	relationSizeQuery = `SELECT pg_catalog.pg_total_relation_size($1::regclass)`
+)
+
+// repackExecutor executes pg_repack with the passed arguments,
+// returning its output
+type repackExecutor func(ctx context.Context, args []string) (string, error)
+
+// databaseConnector returns a connection to the passed database
+type databaseConnector func(dbname string) (*sql.DB, error)
+
+// executeRepack executes the pg_repack binary available in the image
+func executeRepack(ctx context.Context, args []string) (string, error) {
+	cmd := exec.CommandContext(ctx, pgRepackName, args...) // #nosec
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	err := cmd.Run()
+	return output.String(), err
+}
+
+// buildRepackArgs builds the arguments of pg_repack to repack the passed
+// table. Unless explicitly requested, pg_repack is instructed to skip the table
+// instead of cancelling the backends holding conflicting locks
+func buildRepackArgs(
+	config *apiv1.RepackConfiguration,
+	table apiv1.RepackTable,
+	socketDirectory string,
+) []string {
+	args := []string{
+		"--host", socketDirectory,
+		"--port", strconv.Itoa(postgres.ServerPort),
+		"--username", superUserName,
+		"--dbname", table.Database,
+		"--table", table.Table,
+		"--jobs", strconv.Itoa(config.GetJobs()),
+		"--wait-timeout", strconv.Itoa(config.GetLockWaitTimeout()),
+	}
+	if !config.TerminateConflictingBackends {
+		args = append(args, "--no-kill-backend")
+	}
+
+	return args
+}
+
+// getLaggingReplicas returns the replicas, as seen by the primary, whose
+// replication lag is greater than the passed one
+func getLaggingReplicas(ctx context.Context, db *sql.DB, maxReplicationLag int64) ([]string, error) {
+	rows, err := db.QueryContext(ctx, replicationLagQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var laggingReplicas []string
+	for rows.Next() {
+		var name string
+		var lag int64
+		if err := rows.Scan(&name, &lag); err != nil {
+			return nil, err
+		}
+		if lag > maxReplicationLag {
+			laggingReplicas = append(laggingReplicas,
+				fmt.Sprintf("%s (%s)", name, resource.NewQuantity(lag, resource.BinarySI)))
+		}
+	}
+
+	return laggingReplicas, rows.Err()
+}
+
+// repackTables repacks the configured tables one after the other, carrying
+// on after a failure, and reports the outcome
+func repackTables(
+	ctx context.Context,
+	config *apiv1.RepackConfiguration,
+	socketDirectory string,
+	connect databaseConnector,
+	execute repackExecutor,
+) *apiv1.RepackStatus {
+	status := &apiv1.RepackStatus{Result: apiv1.RepackResultSucceeded}
+	var failures []string
+	installed := make(map[string]bool)
+
+	for _, table := range config.Tables {
+		name := fmt.Sprintf("%s.%s", table.Database, table.Table)
+
+		db, err := connect(table.Database)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", name, err.Error()))
+			continue
+		}
+
+		if _, checked := installed[table.Database]; !checked {
+			var isInstalled bool
+			if err := db.QueryRowContext(ctx, extensionInstalledQuery).Scan(&isInstalled); err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %s", name, err.Error()))
+				continue
+			}
+			installed[table.Database] = isInstalled
+		}
+		if !installed[table.Database] {
+			failures = append(failures, fmt.Sprintf("%s: the pg_repack extension is not installed in database %s",
+				name, table.Database))
+			continue
+		}
+
+		var sizeBefore int64
+		if err := db.QueryRowContext(ctx, relationSizeQuery, table.Table).Scan(&sizeBefore); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", name, err.Error()))
+			continue
+		}
+
+		if output, err := execute(ctx, buildRepackArgs(config, table, socketDirectory)); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s: %s", name, err.Error(), strings.TrimSpace(output)))
+			continue
+		}
+		status.RepackedTables++
+
+		var sizeAfter int64
+		if err := db.QueryRowContext(ctx, relationSizeQuery, table.Table).Scan(&sizeAfter); err != nil {
+			continue
+		}
+		if sizeBefore > sizeAfter {
+			status.ReclaimedBytes += sizeBefore - sizeAfter
+		}
+	}
+
+	if len(failures) > 0 {
+		status.Result = apiv1.RepackResultFailed
+		status.Message = strings.Join(failures, "; ")
+	}
+
+	return status
+}
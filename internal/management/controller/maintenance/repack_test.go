@@ -0,0 +1,283 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenance
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("buildRepackArgs", func() {
+	table := apiv1.RepackTable{Database: "app", Table: "public.events"}
+
+	It("prevents pg_repack from cancelling the conflicting backends by default", func() {
+		args := buildRepackArgs(&apiv1.RepackConfiguration{}, table, "/controller/run")
+		Expect(args).To(Equal([]string{
+			"--host", "/controller/run",
+			"--port", "5432",
+			"--username", "postgres",
+			"--dbname", "app",
+			"--table", "public.events",
+			"--jobs", "1",
+			"--wait-timeout", "60",
+			"--no-kill-backend",
+		}))
+	})
+
+	It("lets pg_repack cancel the conflicting backends when explicitly requested", func() {
+		args := buildRepackArgs(&apiv1.RepackConfiguration{
+			Jobs:                         4,
+			LockWaitTimeout:              10,
+			TerminateConflictingBackends: true,
+		}, table, "/controller/run")
+		Expect(args).To(ContainElements("--jobs", "4", "--wait-timeout", "10"))
+		Expect(args).ToNot(ContainElement("--no-kill-backend"))
+	})
+})
+
+var _ = Describe("Repacker", func() {
+	var (
+		ctx        context.Context
+		primaryDB  *sql.DB
+		primary    sqlmock.Sqlmock
+		appDB      *sql.DB
+		app        sqlmock.Sqlmock
+		executions [][]string
+		repacker   *Repacker
+		config     *apiv1.RepackConfiguration
+	)
+
+	BeforeEach(func() {
+		var err error
+		ctx = context.Background()
+		primaryDB, primary, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		appDB, app, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		executions = nil
+		instance := postgres.NewInstance()
+		instance.SocketDirectory = "/controller/run"
+		repacker = &Repacker{
+			instance:    instance,
+			isPrimary:   func() (bool, error) { return true, nil },
+			isHealthy:   func() error { return nil },
+			superUserDB: func() (*sql.DB, error) { return primaryDB, nil },
+			connect: func(dbname string) (*sql.DB, error) {
+				Expect(dbname).To(Equal("app"))
+				return appDB, nil
+			},
+			execute: func(_ context.Context, args []string) (string, error) {
+				executions = append(executions, args)
+				return "", nil
+			},
+		}
+		config = &apiv1.RepackConfiguration{
+			Schedule: "0 0 3 * * *",
+			Tables: []apiv1.RepackTable{
+				{Database: "app", Table: "public.events"},
+				{Database: "app", Table: "public.sessions"},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		Expect(primary.ExpectationsWereMet()).To(Succeed())
+		Expect(app.ExpectationsWereMet()).To(Succeed())
+	})
+
+	expectExtensionAvailable := func(available bool) {
+		primary.ExpectQuery(extensionAvailableQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"available"}).AddRow(available))
+	}
+
+	expectReplicationLag := func(lag int64) {
+		primary.ExpectQuery(replicationLagQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"application_name", "lag"}).
+				AddRow("cluster-example-2", 0).
+				AddRow("cluster-example-3", lag))
+	}
+
+	expectTableSize := func(table string, size int64) {
+		app.ExpectQuery(relationSizeQuery).
+			WithArgs(table).
+			WillReturnRows(sqlmock.NewRows([]string{"size"}).AddRow(size))
+	}
+
+	It("does nothing on a replica", func() {
+		repacker.isPrimary = func() (bool, error) { return false, nil }
+
+		status, err := repacker.repack(ctx, config)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status).To(BeNil())
+		Expect(executions).To(BeEmpty())
+	})
+
+	It("skips the execution when the instance is not healthy", func() {
+		repacker.isHealthy = func() error { return errors.New("connection refused") }
+
+		status, err := repacker.repack(ctx, config)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.Result).To(Equal(apiv1.RepackResultSkipped))
+		Expect(status.Message).To(ContainSubstring("connection refused"))
+		Expect(executions).To(BeEmpty())
+	})
+
+	It("skips the execution when pg_repack is not available in the image", func() {
+		expectExtensionAvailable(false)
+
+		status, err := repacker.repack(ctx, config)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.Result).To(Equal(apiv1.RepackResultSkipped))
+		Expect(status.Message).To(ContainSubstring("not available"))
+		Expect(executions).To(BeEmpty())
+	})
+
+	It("skips the execution when a replica is lagging behind", func() {
+		config.MaxReplicationLag = "16Mi"
+		expectExtensionAvailable(true)
+		expectReplicationLag(32 * 1024 * 1024)
+
+		status, err := repacker.repack(ctx, config)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.Result).To(Equal(apiv1.RepackResultSkipped))
+		Expect(status.Message).To(ContainSubstring("cluster-example-3 (32Mi)"))
+		Expect(status.Message).ToNot(ContainSubstring("cluster-example-2"))
+		Expect(executions).To(BeEmpty())
+	})
+
+	It("repacks the configured tables and reports the reclaimed space", func() {
+		expectExtensionAvailable(true)
+		expectReplicationLag(1024)
+		app.ExpectQuery(extensionInstalledQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"installed"}).AddRow(true))
+		expectTableSize("public.events", 8192*100)
+		expectTableSize("public.events", 8192*40)
+		expectTableSize("public.sessions", 8192*10)
+		expectTableSize("public.sessions", 8192*10)
+
+		status, err := repacker.repack(ctx, config)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.Result).To(Equal(apiv1.RepackResultSucceeded))
+		Expect(status.RepackedTables).To(Equal(2))
+		Expect(status.ReclaimedBytes).To(BeEquivalentTo(8192 * 60))
+		Expect(executions).To(HaveLen(2))
+		Expect(executions[0]).To(ContainElements("--table", "public.events", "--no-kill-backend"))
+		Expect(executions[1]).To(ContainElements("--table", "public.sessions", "--no-kill-backend"))
+	})
+
+	It("carries on after a failed table and reports the failure", func() {
+		repacker.execute = func(_ context.Context, args []string) (string, error) {
+			executions = append(executions, args)
+			if len(executions) == 1 {
+				return "WARNING: timed out, do not cancel conflicting backends", errors.New("exit status 1")
+			}
+			return "", nil
+		}
+		expectExtensionAvailable(true)
+		expectReplicationLag(0)
+		app.ExpectQuery(extensionInstalledQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"installed"}).AddRow(true))
+		expectTableSize("public.events", 8192*100)
+		expectTableSize("public.sessions", 8192*10)
+		expectTableSize("public.sessions", 8192*5)
+
+		status, err := repacker.repack(ctx, config)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.Result).To(Equal(apiv1.RepackResultFailed))
+		Expect(status.RepackedTables).To(Equal(1))
+		Expect(status.ReclaimedBytes).To(BeEquivalentTo(8192 * 5))
+		Expect(status.Message).To(HavePrefix("app.public.events: exit status 1: WARNING: timed out"))
+		Expect(executions).To(HaveLen(2))
+	})
+
+	It("fails when the extension is not installed in the database", func() {
+		expectExtensionAvailable(true)
+		expectReplicationLag(0)
+		app.ExpectQuery(extensionInstalledQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"installed"}).AddRow(false))
+
+		status, err := repacker.repack(ctx, config)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.Result).To(Equal(apiv1.RepackResultFailed))
+		Expect(status.RepackedTables).To(BeZero())
+		Expect(status.Message).To(ContainSubstring("not installed in database app"))
+		Expect(executions).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Repacker scheduling", func() {
+	It("runs according to the schedule and records the outcome in the cluster status", func(ctx SpecContext) {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+		}
+		fakeClient := fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(cluster).
+			WithStatusSubresource(&apiv1.Cluster{}).
+			Build()
+
+		instance := postgres.NewInstance().
+			WithNamespace("default").
+			WithClusterName("cluster-example").
+			WithPodName("cluster-example-1")
+		repacker := NewRepacker(instance, fakeClient)
+		repacker.isPrimary = func() (bool, error) { return true, nil }
+		repacker.isHealthy = func() error { return errors.New("not ready yet") }
+
+		runCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go func() {
+			defer GinkgoRecover()
+			Expect(repacker.Start(runCtx)).To(Succeed())
+		}()
+
+		instance.ConfigureRepacker(&apiv1.RepackConfiguration{
+			Schedule: "* * * * * *",
+			Tables:   []apiv1.RepackTable{{Database: "app", Table: "public.events"}},
+		})
+
+		Eventually(func(g Gomega) {
+			var updated apiv1.Cluster
+			g.Expect(fakeClient.Get(ctx, types.NamespacedName{
+				Name:      "cluster-example",
+				Namespace: "default",
+			}, &updated)).To(Succeed())
+			g.Expect(updated.Status.Repack).ToNot(BeNil())
+			g.Expect(updated.Status.Repack.InstanceName).To(Equal("cluster-example-1"))
+			g.Expect(updated.Status.Repack.Result).To(Equal(apiv1.RepackResultSkipped))
+			g.Expect(updated.Status.Repack.Message).To(ContainSubstring("not ready yet"))
+			g.Expect(updated.Status.Repack.LastRunTime).ToNot(BeEmpty())
+		}).WithTimeout(5 * time.Second).Should(Succeed())
+	})
+})
@@ -0,0 +1,196 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenance
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	pgTime "github.com/cloudnative-pg/machinery/pkg/postgres/time"
+	"github.com/robfig/cron"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// A Repacker is a Kubernetes manager.Runnable that periodically removes the
+// bloat of the configured tables with pg_repack, when this instance is the
+// primary, and reports the outcome in the cluster status
+//
+// c.f. https://pkg.go.dev/sigs.k8s.io/controller-runtime/pkg/manager#Runnable
+type Repacker struct {
+	instance *postgres.Instance
+	client   client.Client
+
+	// The following functions interact with PostgreSQL and pg_repack,
+	// and are replaced in the tests
+	isPrimary   func() (bool, error)
+	isHealthy   func() error
+	superUserDB func() (*sql.DB, error)
+	connect     databaseConnector
+	execute     repackExecutor
+}
+
+// NewRepacker creates a new Repacker
+func NewRepacker(instance *postgres.Instance, client client.Client) *Repacker {
+	return &Repacker{
+		instance:    instance,
+		client:      client,
+		isPrimary:   instance.IsPrimary,
+		isHealthy:   instance.IsServerHealthy,
+		superUserDB: instance.GetSuperUserDB,
+		connect: func(dbname string) (*sql.DB, error) {
+			return instance.ConnectionPool().Connection(dbname)
+		},
+		execute: executeRepack,
+	}
+}
+
+// Start starts running the Repacker
+func (r *Repacker) Start(ctx context.Context) error {
+	contextLog := log.FromContext(ctx).WithName("repacker")
+	go func() {
+		var config *apiv1.RepackConfiguration
+		var schedule cron.Schedule
+		var next <-chan time.Time
+
+		defer func() {
+			contextLog.Info("Terminated repacker loop")
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case newConfig := <-r.instance.RepackerChan():
+				if reflect.DeepEqual(newConfig, config) {
+					continue
+				}
+
+				config, next = newConfig, nil
+				if config == nil {
+					continue
+				}
+
+				var err error
+				if schedule, err = cron.Parse(config.Schedule); err != nil {
+					contextLog.Warning("invalid pg_repack schedule", "schedule", config.Schedule, "err", err)
+					continue
+				}
+				next = time.After(time.Until(schedule.Next(time.Now())))
+				continue
+
+			case <-next:
+			}
+
+			if err := r.run(ctx, config); err != nil {
+				contextLog.Warning("repacking the tables", "err", err)
+			}
+			next = time.After(time.Until(schedule.Next(time.Now())))
+		}
+	}()
+	<-ctx.Done()
+	return nil
+}
+
+// run executes pg_repack and records its outcome in the cluster status
+func (r *Repacker) run(ctx context.Context, config *apiv1.RepackConfiguration) error {
+	contextLog := log.FromContext(ctx).WithName("repacker")
+
+	status, err := r.repack(ctx, config)
+	if err != nil || status == nil {
+		return err
+	}
+
+	contextLog.Info("pg_repack execution completed",
+		"result", status.Result,
+		"repackedTables", status.RepackedTables,
+		"reclaimedBytes", status.ReclaimedBytes,
+		"message", status.Message)
+
+	return r.updateStatus(ctx, status)
+}
+
+// repack repacks the configured tables, provided that this instance is a
+// healthy primary, that pg_repack is available and that no replica is lagging
+// behind. It returns nil when this instance is not the primary
+func (r *Repacker) repack(ctx context.Context, config *apiv1.RepackConfiguration) (*apiv1.RepackStatus, error) {
+	if isPrimary, err := r.isPrimary(); err != nil || !isPrimary {
+		return nil, err
+	}
+
+	if err := r.isHealthy(); err != nil {
+		return skipped(fmt.Sprintf("the instance is not healthy: %s", err.Error())), nil
+	}
+
+	db, err := r.superUserDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var available bool
+	if err := db.QueryRowContext(ctx, extensionAvailableQuery).Scan(&available); err != nil {
+		return nil, err
+	}
+	if !available {
+		return skipped("the pg_repack extension is not available in the image"), nil
+	}
+
+	laggingReplicas, err := getLaggingReplicas(ctx, db, config.GetMaxReplicationLag())
+	if err != nil {
+		return nil, err
+	}
+	if len(laggingReplicas) > 0 {
+		return skipped(fmt.Sprintf("replicas lagging behind: %s", strings.Join(laggingReplicas, ", "))), nil
+	}
+
+	return repackTables(ctx, config, r.instance.SocketDirectory, r.connect, r.execute), nil
+}
+
+// skipped creates the status of a skipped execution
+func skipped(reason string) *apiv1.RepackStatus {
+	return &apiv1.RepackStatus{
+		Result:  apiv1.RepackResultSkipped,
+		Message: reason,
+	}
+}
+
+// updateStatus records the outcome of the execution in the cluster status
+func (r *Repacker) updateStatus(ctx context.Context, status *apiv1.RepackStatus) error {
+	var cluster apiv1.Cluster
+	if err := r.client.Get(ctx, types.NamespacedName{
+		Name:      r.instance.GetClusterName(),
+		Namespace: r.instance.GetNamespaceName(),
+	}, &cluster); err != nil {
+		return err
+	}
+
+	origCluster := cluster.DeepCopy()
+	status.InstanceName = r.instance.GetPodName()
+	status.LastRunTime = pgTime.GetCurrentTimestamp()
+	cluster.Status.Repack = status
+
+	return r.client.Status().Patch(ctx, &cluster, client.MergeFrom(origCluster))
+}
@@ -19,7 +19,6 @@ package roles
 import (
 	"database/sql"
 	"reflect"
-	"sort"
 
 	"github.com/jackc/pgx/v5/pgtype"
 
@@ -43,8 +42,11 @@ type DatabaseRole struct {
 	ConnectionLimit int64            `json:"connectionLimit,omitempty"` // default is -1
 	ValidUntil      pgtype.Timestamp `json:"validUntil,omitempty"`
 	InRoles         []string         `json:"inRoles,omitempty"`
-	password        sql.NullString   `json:"-"`
-	transactionID   int64            `json:"-"`
+	// InRolesWithAdminOption is the subset of InRoles where the
+	// membership is granted WITH ADMIN OPTION
+	InRolesWithAdminOption []string       `json:"inRolesWithAdminOption,omitempty"`
+	password               sql.NullString `json:"-"`
+	transactionID          int64          `json:"-"`
 }
 
 // passwordNeedsUpdating evaluates whether a DatabaseRole needs to be updated
@@ -61,17 +63,23 @@ func (d *DatabaseRole) hasSameCommentAs(inSpec apiv1.RoleConfiguration) bool {
 }
 
 func (d *DatabaseRole) isInSameRolesAs(inSpec apiv1.RoleConfiguration) bool {
-	if len(d.InRoles) == 0 && len(inSpec.InRoles) == 0 {
-		return true
-	}
+	return hasSameElements(d.InRoles, inSpec.GetMemberships()) &&
+		hasSameElements(d.InRolesWithAdminOption, inSpec.InRolesWithAdminOption)
+}
 
-	if len(d.InRoles) != len(inSpec.InRoles) {
-		return false
+// hasSameElements checks whether the two lists contain the same elements,
+// ignoring their order and duplicates. Since PostgreSQL 16 the same membership
+// can be granted by different grantors, and is listed once per grantor
+func hasSameElements(a, b []string) bool {
+	setA := make(map[string]bool, len(a))
+	for _, element := range a {
+		setA[element] = true
 	}
-
-	sort.Strings(d.InRoles)
-	sort.Strings(inSpec.InRoles)
-	return reflect.DeepEqual(d.InRoles, inSpec.InRoles)
+	setB := make(map[string]bool, len(b))
+	for _, element := range b {
+		setB[element] = true
+	}
+	return reflect.DeepEqual(setA, setB)
 }
 
 func (d *DatabaseRole) hasSameValidUntilAs(inSpec apiv1.RoleConfiguration) bool {
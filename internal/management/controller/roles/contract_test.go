@@ -102,6 +102,23 @@ var _ = Describe("DatabaseRole implementation test", func() {
 		Expect(res).To(BeFalse())
 	})
 
+	It("should return false when the admin option of a membership differs", func() {
+		role := DatabaseRole{
+			Name:                   "abc",
+			InRoles:                []string{"role1", "role2", "role2"},
+			InRolesWithAdminOption: []string{"role2"},
+		}
+		Expect(role.isInSameRolesAs(apiv1.RoleConfiguration{
+			Name:                   "abc",
+			InRoles:                []string{"role1"},
+			InRolesWithAdminOption: []string{"role2"},
+		})).To(BeTrue())
+		Expect(role.isInSameRolesAs(apiv1.RoleConfiguration{
+			Name:    "abc",
+			InRoles: []string{"role1", "role2"},
+		})).To(BeFalse())
+	})
+
 	It("Detects that spec and db role have the same ValidUntil", func() {
 		role := DatabaseRole{
 			Name:       "abc",
@@ -21,6 +21,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
@@ -41,10 +42,11 @@ func List(ctx context.Context, db *sql.DB) ([]DatabaseRole, error) {
 		`SELECT rolname, rolsuper, rolinherit, rolcreaterole, rolcreatedb, 
        			rolcanlogin, rolreplication, rolconnlimit, rolpassword, rolvaliduntil, rolbypassrls,
 				pg_catalog.shobj_description(auth.oid, 'pg_authid') as comment, auth.xmin,
-				mem.inroles
+				mem.inroles, mem.adminroles
 		FROM pg_catalog.pg_authid as auth
 		LEFT JOIN (
-			SELECT array_agg(pg_get_userbyid(roleid)) as inroles, member
+			SELECT array_agg(pg_get_userbyid(roleid)) as inroles,
+				array_agg(pg_get_userbyid(roleid)) FILTER (WHERE admin_option) as adminroles, member
 			FROM pg_auth_members GROUP BY member
 		) mem ON member = oid
 		WHERE rolname not like 'pg\_%'`)
@@ -61,7 +63,7 @@ func List(ctx context.Context, db *sql.DB) ([]DatabaseRole, error) {
 	for rows.Next() {
 		var comment sql.NullString
 		var role DatabaseRole
-		var inRoles pq.StringArray
+		var inRoles, adminRoles pq.StringArray
 		err := rows.Scan(
 			&role.Name,
 			&role.Superuser,
@@ -77,6 +79,7 @@ func List(ctx context.Context, db *sql.DB) ([]DatabaseRole, error) {
 			&comment,
 			&role.transactionID,
 			&inRoles,
+			&adminRoles,
 		)
 		if err != nil {
 			return nil, wrapErr(err)
@@ -86,6 +89,7 @@ func List(ctx context.Context, db *sql.DB) ([]DatabaseRole, error) {
 		}
 
 		role.InRoles = inRoles
+		role.InRolesWithAdminOption = adminRoles
 
 		roles = append(roles, role)
 	}
@@ -143,6 +147,17 @@ func Create(ctx context.Context, db *sql.DB, role DatabaseRole) error {
 		return wrapErr(err)
 	}
 
+	// IN ROLE cannot grant a membership WITH ADMIN OPTION
+	for _, r := range role.InRolesWithAdminOption {
+		query.Reset()
+		query.WriteString(fmt.Sprintf("GRANT %s TO %s WITH ADMIN OPTION",
+			pgx.Identifier{r}.Sanitize(), pgx.Identifier{role.Name}.Sanitize()))
+
+		if _, err := db.ExecContext(ctx, query.String()); err != nil {
+			return wrapErr(err)
+		}
+	}
+
 	if len(role.Comment) > 0 {
 		query.Reset()
 		query.WriteString(fmt.Sprintf("COMMENT ON ROLE %s IS %s",
@@ -216,7 +231,10 @@ func UpdateComment(ctx context.Context, db *sql.DB, role DatabaseRole) error {
 	return nil
 }
 
-// UpdateMembership of the role
+// UpdateMembership of the role. The memberships in rolesToGrant are granted
+// WITH ADMIN OPTION when listed in the InRolesWithAdminOption of the role,
+// while adminOptionsToRevoke lists the memberships that have to be kept
+// without the admin option
 //
 // IMPORTANT: the various REVOKE and GRANT commands that may be required to
 // reconcile the role will be done in a single transaction. So, if any one
@@ -227,19 +245,29 @@ func UpdateMembership(
 	role DatabaseRole,
 	rolesToGrant []string,
 	rolesToRevoke []string,
+	adminOptionsToRevoke []string,
 ) error {
 	contextLog := log.FromContext(ctx).WithName("roles_reconciler")
 	contextLog.Trace("Invoked", "role", role)
 	wrapErr := func(err error) error {
 		return fmt.Errorf("while updating memberships for role %s with role reconciler: %w", role.Name, err)
 	}
-	if len(rolesToRevoke)+len(rolesToGrant) == 0 {
+	if len(rolesToRevoke)+len(rolesToGrant)+len(adminOptionsToRevoke) == 0 {
 		contextLog.Debug("No membership change query to execute for role")
 		return nil
 	}
-	queries := make([]string, 0, len(rolesToRevoke)+len(rolesToGrant))
+	queries := make([]string, 0, len(rolesToRevoke)+len(rolesToGrant)+len(adminOptionsToRevoke))
 	for _, r := range rolesToGrant {
-		queries = append(queries, fmt.Sprintf(`GRANT %s TO %s`,
+		query := fmt.Sprintf(`GRANT %s TO %s`,
+			pgx.Identifier{r}.Sanitize(),
+			pgx.Identifier{role.Name}.Sanitize())
+		if slices.Contains(role.InRolesWithAdminOption, r) {
+			query += " WITH ADMIN OPTION"
+		}
+		queries = append(queries, query)
+	}
+	for _, r := range adminOptionsToRevoke {
+		queries = append(queries, fmt.Sprintf(`REVOKE ADMIN OPTION FOR %s FROM %s`,
 			pgx.Identifier{r}.Sanitize(),
 			pgx.Identifier{role.Name}.Sanitize()),
 		)
@@ -272,31 +300,33 @@ func UpdateMembership(
 	return tx.Commit()
 }
 
-// GetParentRoles get the in roles of this role
-func GetParentRoles(ctx context.Context, db *sql.DB, role DatabaseRole) ([]string, error) {
+// GetParentRoles get the in roles of this role, and the subset of them
+// where the membership has been granted WITH ADMIN OPTION
+func GetParentRoles(ctx context.Context, db *sql.DB, role DatabaseRole) ([]string, []string, error) {
 	contextLog := log.FromContext(ctx).WithName("roles_reconciler")
 	contextLog.Trace("Invoked", "role", role)
 	wrapErr := func(err error) error {
 		return fmt.Errorf("while getting parents for role %s with role reconciler: %w", role.Name, err)
 	}
-	query := `SELECT mem.inroles 
+	query := `SELECT mem.inroles, mem.adminroles
 		FROM pg_catalog.pg_authid as auth
 		LEFT JOIN (
-			SELECT array_agg(pg_get_userbyid(roleid)) as inroles, member
+			SELECT array_agg(pg_get_userbyid(roleid)) as inroles,
+				array_agg(pg_get_userbyid(roleid)) FILTER (WHERE admin_option) as adminroles, member
 			FROM pg_auth_members GROUP BY member
 		) mem ON member = oid
 		WHERE rolname = $1`
 	contextLog.Debug("get parent role", "query", query)
-	var parentRoles pq.StringArray
-	err := db.QueryRowContext(ctx, query, role.Name).Scan(&parentRoles)
+	var parentRoles, adminRoles pq.StringArray
+	err := db.QueryRowContext(ctx, query, role.Name).Scan(&parentRoles, &adminRoles)
 	if errors.Is(err, sql.ErrNoRows) {
-		return nil, wrapErr(err)
+		return nil, nil, wrapErr(err)
 	}
 	if err != nil {
-		return nil, wrapErr(err)
+		return nil, nil, wrapErr(err)
 	}
 
-	return parentRoles, nil
+	return parentRoles, adminRoles, nil
 }
 
 func appendInRoleOptions(role DatabaseRole, query *strings.Builder) {
@@ -145,22 +145,22 @@ var _ = Describe("Postgres RoleManager implementation test", func() {
 		rows := sqlmock.NewRows([]string{
 			"rolname", "rolsuper", "rolinherit", "rolcreaterole", "rolcreatedb",
 			"rolcanlogin", "rolreplication", "rolconnlimit", "rolpassword", "rolvaliduntil", "rolbypassrls", "comment",
-			"xmin", "inroles",
+			"xmin", "inroles", "adminroles",
 		}).
 			AddRow("postgres", true, false, true, true, true, false, -1, []byte("12345"),
-				nil, false, []byte("This is postgres user"), 11, []byte("{}")).
+				nil, false, []byte("This is postgres user"), 11, []byte("{}"), nil).
 			AddRow("streaming_replica", false, false, true, true, false, true, 10, []byte("54321"),
 				pgtype.Timestamp{
 					Valid:            true,
 					Time:             testDate,
 					InfinityModifier: pgtype.Finite,
-				}, false, []byte("This is streaming_replica user"), 22, []byte(`{"role1","role2"}`)).
+				}, false, []byte("This is streaming_replica user"), 22, []byte(`{"role1","role2"}`), nil).
 			AddRow("future_man", false, false, true, true, false, true, 10, []byte("54321"),
 				pgtype.Timestamp{
 					Valid:            true,
 					Time:             time.Time{},
 					InfinityModifier: pgtype.Infinity,
-				}, false, []byte("This is streaming_replica user"), 22, []byte(`{"role1","role2"}`))
+				}, false, []byte("This is streaming_replica user"), 22, []byte(`{"role1","role2"}`), nil)
 		mock.ExpectQuery(expectedSelStmt).WillReturnRows(rows)
 		mock.ExpectExec("CREATE ROLE foo").WillReturnResult(sqlmock.NewResult(11, 1))
 		roles, err := List(ctx, db)
@@ -379,15 +379,16 @@ var _ = Describe("Postgres RoleManager implementation test", func() {
 		Expect(err).ToNot(HaveOccurred())
 
 		rows := sqlmock.NewRows([]string{
-			"inroles",
+			"inroles", "adminroles",
 		}).
-			AddRow([]byte(`{"role1","role2"}`))
+			AddRow([]byte(`{"role1","role2"}`), []byte(`{"role2"}`))
 		mock.ExpectQuery(expectedMembershipStmt).WithArgs("foo").WillReturnRows(rows)
 
-		roles, err := GetParentRoles(ctx, db, DatabaseRole{Name: "foo"})
+		roles, adminRoles, err := GetParentRoles(ctx, db, DatabaseRole{Name: "foo"})
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(roles).To(HaveLen(2))
 		Expect(roles).To(ConsistOf("role1", "role2"))
+		Expect(adminRoles).To(ConsistOf("role2"))
 	})
 
 	It("GetParentRoles will error if there is a problem querying the database", func(ctx context.Context) {
@@ -395,7 +396,7 @@ var _ = Describe("Postgres RoleManager implementation test", func() {
 		Expect(err).ToNot(HaveOccurred())
 
 		mock.ExpectQuery(expectedMembershipStmt).WithArgs("foo").WillReturnError(fmt.Errorf("kaboom"))
-		roles, err := GetParentRoles(ctx, db, DatabaseRole{Name: "foo"})
+		roles, _, err := GetParentRoles(ctx, db, DatabaseRole{Name: "foo"})
 		Expect(err).Should(HaveOccurred())
 		Expect(roles).To(BeEmpty())
 	})
@@ -419,10 +420,39 @@ var _ = Describe("Postgres RoleManager implementation test", func() {
 
 		mock.ExpectCommit()
 
-		err = UpdateMembership(ctx, db, DatabaseRole{Name: "foo"}, []string{"pg_monitor", "quux"}, []string{"bar"})
+		err = UpdateMembership(ctx, db, DatabaseRole{Name: "foo"}, []string{"pg_monitor", "quux"}, []string{"bar"}, nil)
 		Expect(err).ShouldNot(HaveOccurred())
 	})
 
+	It("UpdateMembership will add and remove the admin option", func(ctx context.Context) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		expectedMembershipExecs := []string{
+			`GRANT "app_owner" TO "foo" WITH ADMIN OPTION`,
+			`GRANT "quux" TO "foo"`,
+			`REVOKE ADMIN OPTION FOR "app_reader" FROM "foo"`,
+		}
+
+		mock.ExpectBegin()
+
+		for _, ex := range expectedMembershipExecs {
+			mock.ExpectExec(ex).
+				WillReturnResult(sqlmock.NewResult(2, 3))
+		}
+
+		mock.ExpectCommit()
+
+		role := DatabaseRole{
+			Name:                   "foo",
+			InRoles:                []string{"app_owner", "app_reader", "quux"},
+			InRolesWithAdminOption: []string{"app_owner"},
+		}
+		err = UpdateMembership(ctx, db, role, []string{"app_owner", "quux"}, nil, []string{"app_reader"})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
 	It("UpdateMembership will roll back if there is an error in the DB", func(ctx context.Context) {
 		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
 		Expect(err).ToNot(HaveOccurred())
@@ -438,7 +468,7 @@ var _ = Describe("Postgres RoleManager implementation test", func() {
 
 		mock.ExpectRollback()
 
-		err = UpdateMembership(ctx, db, DatabaseRole{Name: "foo"}, []string{"pg_monitor", "quux"}, []string{"bar"})
+		err = UpdateMembership(ctx, db, DatabaseRole{Name: "foo"}, []string{"pg_monitor", "quux"}, []string{"bar"}, nil)
 		Expect(err).Should(HaveOccurred())
 	})
 
@@ -82,5 +82,6 @@ func Reconcile(
 
 	updatedCluster := cluster.DeepCopy()
 	updatedCluster.Status.ManagedRolesStatus.ByStatus = roleNamesByStatus
+	updatedCluster.Status.ManagedRolesStatus.ConnectionLimits = getConnectionLimits(cluster.Spec.Managed, rolesInDB)
 	return reconcile.Result{}, c.Status().Patch(ctx, updatedCluster, client.MergeFrom(cluster))
 }
@@ -19,6 +19,7 @@ package roles
 import (
 	"context"
 	"database/sql"
+	"slices"
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -55,17 +56,18 @@ func roleAdapterFromName(name string) roleConfigurationAdapter {
 // provide a PasswordSecret or explicitly set DisablePassword, is to IGNORE the password
 func (role roleConfigurationAdapter) toDatabaseRole() DatabaseRole {
 	dbRole := DatabaseRole{
-		Name:            role.Name,
-		Comment:         role.Comment,
-		Superuser:       role.Superuser,
-		CreateDB:        role.CreateDB,
-		CreateRole:      role.CreateRole,
-		Inherit:         role.GetRoleInherit(),
-		Login:           role.Login,
-		Replication:     role.Replication,
-		BypassRLS:       role.BypassRLS,
-		ConnectionLimit: role.ConnectionLimit,
-		InRoles:         role.InRoles,
+		Name:                   role.Name,
+		Comment:                role.Comment,
+		Superuser:              role.Superuser,
+		CreateDB:               role.CreateDB,
+		CreateRole:             role.CreateRole,
+		Inherit:                role.GetRoleInherit(),
+		Login:                  role.Login,
+		Replication:            role.Replication,
+		BypassRLS:              role.BypassRLS,
+		ConnectionLimit:        role.ConnectionLimit,
+		InRoles:                role.GetMemberships(),
+		InRolesWithAdminOption: role.InRolesWithAdminOption,
 	}
 	switch {
 	case role.ValidUntil != nil:
@@ -111,6 +113,27 @@ func (r rolesByAction) convertToRolesByStatus() rolesByStatus {
 	return rolesByStatus
 }
 
+// getConnectionLimits gets the connection limit applied in the DB to each
+// managed role that exists in the DB
+func getConnectionLimits(
+	config *apiv1.ManagedConfiguration,
+	rolesInDB []DatabaseRole,
+) map[string]int64 {
+	managedRoles := make(map[string]bool, len(config.Roles))
+	for _, role := range config.Roles {
+		managedRoles[role.Name] = true
+	}
+
+	connectionLimits := make(map[string]int64)
+	for _, role := range rolesInDB {
+		if managedRoles[role.Name] {
+			connectionLimits[role.Name] = role.ConnectionLimit
+		}
+	}
+
+	return connectionLimits
+}
+
 // evaluateNextRoleActions evaluates the action needed for each role in the DB and/or the Spec.
 // It has no side effects
 func evaluateNextRoleActions(
@@ -186,5 +209,49 @@ func evaluateNextRoleActions(
 		}
 	}
 
+	if len(rolesByAction[roleCreate]) > 1 {
+		rolesByAction[roleCreate] = sortByMembershipDependencies(rolesByAction[roleCreate])
+	}
+
 	return rolesByAction
 }
+
+// sortByMembershipDependencies sorts the roles to be created so that every
+// role is created after the roles it is a member of, keeping the original
+// order otherwise. Roles in a circular membership, which is refused by the
+// validation webhook, are left at the end
+func sortByMembershipDependencies(roles []roleConfigurationAdapter) []roleConfigurationAdapter {
+	pending := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		pending[role.Name] = true
+	}
+
+	sorted := make([]roleConfigurationAdapter, 0, len(roles))
+	for len(sorted) < len(roles) {
+		progress := false
+		for _, role := range roles {
+			if !pending[role.Name] {
+				continue
+			}
+			if slices.ContainsFunc(role.GetMemberships(), func(parent string) bool {
+				return parent != role.Name && pending[parent]
+			}) {
+				continue
+			}
+			pending[role.Name] = false
+			sorted = append(sorted, role)
+			progress = true
+		}
+
+		if !progress {
+			for _, role := range roles {
+				if pending[role.Name] {
+					sorted = append(sorted, role)
+				}
+			}
+			break
+		}
+	}
+
+	return sorted
+}
@@ -20,11 +20,14 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/strings/slices"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -169,9 +172,43 @@ func (sr *RoleSynchronizer) reconcile(ctx context.Context, config *apiv1.Managed
 	updatedCluster := remoteCluster.DeepCopy()
 	updatedCluster.Status.ManagedRolesStatus.PasswordStatus = appliedState
 	updatedCluster.Status.ManagedRolesStatus.CannotReconcile = irreconcilableRoles
+	meta.SetStatusCondition(&updatedCluster.Status.Conditions, buildManagedRolesCondition(irreconcilableRoles))
 	return sr.client.Status().Patch(ctx, updatedCluster, client.MergeFrom(&remoteCluster))
 }
 
+// buildManagedRolesCondition builds the condition reporting whether every
+// managed role has been reconciled, listing the errors PostgreSQL raised
+// for the ones that couldn't
+func buildManagedRolesCondition(irreconcilableRoles map[string][]string) metav1.Condition {
+	if len(irreconcilableRoles) == 0 {
+		return metav1.Condition{
+			Type:    string(apiv1.ConditionManagedRolesReconciled),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(apiv1.ConditionReasonManagedRolesReconciled),
+			Message: "All the managed roles are reconciled",
+		}
+	}
+
+	roleNames := make([]string, 0, len(irreconcilableRoles))
+	for roleName := range irreconcilableRoles {
+		roleNames = append(roleNames, roleName)
+	}
+	sort.Strings(roleNames)
+
+	var roleErrors []string
+	for _, roleName := range roleNames {
+		roleErrors = append(roleErrors, irreconcilableRoles[roleName]...)
+	}
+
+	return metav1.Condition{
+		Type:   string(apiv1.ConditionManagedRolesReconciled),
+		Status: metav1.ConditionFalse,
+		Reason: string(apiv1.ConditionReasonManagedRolesCannotReconcile),
+		Message: fmt.Sprintf("Cannot reconcile the managed roles %s: %s",
+			strings.Join(roleNames, ", "), strings.Join(roleErrors, "; ")),
+	}
+}
+
 func getRoleNames(roles []roleConfigurationAdapter) []string {
 	names := make([]string, len(roles))
 	for i, role := range roles {
@@ -273,12 +310,12 @@ func (sr *RoleSynchronizer) applyRoleActions(
 	for _, role := range rolesByAction[roleUpdateMemberships] {
 		// NOTE: revoking / granting to a role does not alter its TransactionID
 		dbRole := role.toDatabaseRole()
-		grants, revokes, err := getRoleMembershipDiff(ctx, db, role, dbRole)
+		grants, revokes, adminRevokes, err := getRoleMembershipDiff(ctx, db, role, dbRole)
 		if unhandledErr := handleRoleError(err, role.Name, roleUpdateMemberships); unhandledErr != nil {
 			return nil, nil, unhandledErr
 		}
 
-		err = UpdateMembership(ctx, db, dbRole, grants, revokes)
+		err = UpdateMembership(ctx, db, dbRole, grants, revokes, adminRevokes)
 		if unhandledErr := handleRoleError(err, role.Name, roleUpdateMemberships); unhandledErr != nil {
 			return nil, nil, unhandledErr
 		}
@@ -294,19 +331,39 @@ func (sr *RoleSynchronizer) applyRoleActions(
 	return appliedChanges, irreconcilableRoles, nil
 }
 
+// getRoleMembershipDiff returns the memberships to be granted, the ones to be
+// revoked and the ones to be kept without the admin option, in order to align
+// the role in the database to the spec
 func getRoleMembershipDiff(
 	ctx context.Context,
 	db *sql.DB,
 	role roleConfigurationAdapter,
 	dbRole DatabaseRole,
-) ([]string, []string, error) {
-	inRoleInDB, err := GetParentRoles(ctx, db, dbRole)
+) ([]string, []string, []string, error) {
+	inRoleInDB, adminInRoleInDB, err := GetParentRoles(ctx, db, dbRole)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
+	}
+	inRoleInSpec := role.GetMemberships()
+	rolesToGrant := getRolesToGrant(inRoleInDB, inRoleInSpec)
+	rolesToRevoke := getRolesToRevoke(inRoleInDB, inRoleInSpec)
+
+	// Granting again an existing membership WITH ADMIN OPTION adds the
+	// admin option to it
+	for _, r := range getRolesToGrant(adminInRoleInDB, role.InRolesWithAdminOption) {
+		if !slices.Contains(rolesToGrant, r) {
+			rolesToGrant = append(rolesToGrant, r)
+		}
 	}
-	rolesToGrant := getRolesToGrant(inRoleInDB, role.InRoles)
-	rolesToRevoke := getRolesToRevoke(inRoleInDB, role.InRoles)
-	return rolesToGrant, rolesToRevoke, nil
+
+	var adminOptionsToRevoke []string
+	for _, r := range getRolesToRevoke(adminInRoleInDB, role.InRolesWithAdminOption) {
+		if slices.Contains(inRoleInSpec, r) {
+			adminOptionsToRevoke = append(adminOptionsToRevoke, r)
+		}
+	}
+
+	return rolesToGrant, rolesToRevoke, adminOptionsToRevoke, nil
 }
 
 // applyRoleCreateUpdate creates/updates a role, getting the password from Kubernetes
@@ -68,22 +68,22 @@ var _ = Describe("Role synchronizer tests", func() {
 		rowsInMockDatabase := sqlmock.NewRows([]string{
 			"rolname", "rolsuper", "rolinherit", "rolcreaterole", "rolcreatedb",
 			"rolcanlogin", "rolreplication", "rolconnlimit", "rolpassword", "rolvaliduntil", "rolbypassrls", "comment",
-			"xmin", "inroles",
+			"xmin", "inroles", "adminroles",
 		}).
 			AddRow("postgres", true, false, true, true, true, false, -1, []byte("12345"),
-				nil, false, []byte("This is postgres user"), 11, []byte("{}")).
+				nil, false, []byte("This is postgres user"), 11, []byte("{}"), nil).
 			AddRow("streaming_replica", false, false, true, true, false, true, 10, []byte("54321"),
 				pgtype.Timestamp{
 					Valid:            true,
 					Time:             testDate,
 					InfinityModifier: pgtype.Finite,
-				}, false, []byte("This is streaming_replica user"), 22, []byte(`{"role1","role2"}`)).
+				}, false, []byte("This is streaming_replica user"), 22, []byte(`{"role1","role2"}`), nil).
 			AddRow("role_to_ignore", true, false, true, true, true, false, -1, []byte("12345"),
-				nil, false, []byte("This is a custom role in the DB"), 11, []byte("{}")).
+				nil, false, []byte("This is a custom role in the DB"), 11, []byte("{}"), nil).
 			AddRow("role_to_test1", true, true, false, false, false, false, -1, []byte("12345"),
-				nil, false, []byte("This is a role to test with"), 11, []byte("{}")).
+				nil, false, []byte("This is a role to test with"), 11, []byte("{}"), nil).
 			AddRow("role_to_test2", true, true, false, false, false, false, -1, []byte("12345"),
-				nil, false, []byte("This is a role to test with"), 11, []byte("{inrole}"))
+				nil, false, []byte("This is a role to test with"), 11, []byte("{inrole}"), nil)
 		mock.ExpectQuery(expectedSelStmt).WillReturnRows(rowsInMockDatabase)
 
 		roleSynchronizer = RoleSynchronizer{
@@ -152,7 +152,7 @@ var _ = Describe("Role synchronizer tests", func() {
 					},
 				},
 			}
-			noParents := sqlmock.NewRows([]string{"inroles"}).AddRow([]byte(`{}`))
+			noParents := sqlmock.NewRows([]string{"inroles", "adminroles"}).AddRow([]byte(`{}`), nil)
 			mock.ExpectQuery(expectedMembershipStmt).WithArgs("role_to_test1").WillReturnRows(noParents)
 			mock.ExpectBegin()
 			expectedMembershipExecs := []string{
@@ -190,9 +190,9 @@ var _ = Describe("Role synchronizer tests", func() {
 				},
 			}
 			rows := sqlmock.NewRows([]string{
-				"inroles",
+				"inroles", "adminroles",
 			}).
-				AddRow([]byte(`{"foo"}`))
+				AddRow([]byte(`{"foo"}`), nil)
 			mock.ExpectQuery(expectedMembershipStmt).WithArgs("role_to_test2").WillReturnRows(rows)
 			mock.ExpectBegin()
 
@@ -334,7 +334,7 @@ var _ = Describe("Role synchronizer tests", func() {
 				},
 			}
 
-			noParents := sqlmock.NewRows([]string{"inroles"}).AddRow([]byte(`{}`))
+			noParents := sqlmock.NewRows([]string{"inroles", "adminroles"}).AddRow([]byte(`{}`), nil)
 			mock.ExpectQuery(expectedMembershipStmt).WithArgs("role_to_test1").WillReturnRows(noParents)
 			mock.ExpectBegin()
 
@@ -376,6 +376,28 @@ var _ = Describe("Role synchronizer tests", func() {
 	})
 })
 
+var _ = Describe("Managed roles condition", func() {
+	It("reports that the roles are reconciled when there are no errors", func() {
+		condition := buildManagedRolesCondition(nil)
+		Expect(condition.Type).To(Equal(string(apiv1.ConditionManagedRolesReconciled)))
+		Expect(condition.Status).To(Equal(v1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonManagedRolesReconciled)))
+	})
+
+	It("lists the roles that can't be reconciled with their errors", func() {
+		condition := buildManagedRolesCondition(map[string][]string{
+			"role_to_test2": {"could not perform DELETE on role role_to_test2: owner of database app"},
+			"role_to_test1": {"could not perform UPDATE_MEMBERSHIPS on role role_to_test1: unknown role 'role2'"},
+		})
+		Expect(condition.Type).To(Equal(string(apiv1.ConditionManagedRolesReconciled)))
+		Expect(condition.Status).To(Equal(v1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonManagedRolesCannotReconcile)))
+		Expect(condition.Message).To(Equal("Cannot reconcile the managed roles role_to_test1, role_to_test2: " +
+			"could not perform UPDATE_MEMBERSHIPS on role role_to_test1: unknown role 'role2'; " +
+			"could not perform DELETE on role role_to_test2: owner of database app"))
+	})
+})
+
 var _ = DescribeTable("Role status tests",
 	func(spec *apiv1.ManagedConfiguration, roles []DatabaseRole, expected map[string]apiv1.RoleStatus) {
 		ctx := context.TODO()
@@ -566,6 +588,28 @@ var _ = DescribeTable("Role status tests",
 	),
 )
 
+var _ = Describe("Role connection limits", func() {
+	It("reports the connection limit of the managed roles existing in the database", func() {
+		config := &apiv1.ManagedConfiguration{
+			Roles: []apiv1.RoleConfiguration{
+				{Name: "limited", ConnectionLimit: 5},
+				{Name: "unlimited", ConnectionLimit: -1},
+				{Name: "missing", ConnectionLimit: 10},
+			},
+		}
+		rolesInDB := []DatabaseRole{
+			{Name: "postgres", ConnectionLimit: -1},
+			{Name: "limited", ConnectionLimit: 5},
+			{Name: "unlimited", ConnectionLimit: -1},
+		}
+
+		Expect(getConnectionLimits(config, rolesInDB)).To(Equal(map[string]int64{
+			"limited":   5,
+			"unlimited": -1,
+		}))
+	})
+})
+
 const (
 	namespace          = "vinci-namespace"
 	secretName         = "vinci-secret-name"
@@ -690,3 +734,147 @@ var _ = DescribeTable("role secrets test",
 		true,
 	),
 )
+
+var _ = Describe("Role membership reconciliation", func() {
+	var (
+		db               *sql.DB
+		mock             sqlmock.Sqlmock
+		roleSynchronizer RoleSynchronizer
+	)
+
+	listColumns := []string{
+		"rolname", "rolsuper", "rolinherit", "rolcreaterole", "rolcreatedb",
+		"rolcanlogin", "rolreplication", "rolconnlimit", "rolpassword", "rolvaliduntil", "rolbypassrls", "comment",
+		"xmin", "inroles", "adminroles",
+	}
+	createStmt := func(name string) string {
+		return fmt.Sprintf("CREATE ROLE \"%s\" NOBYPASSRLS NOCREATEDB NOCREATEROLE INHERIT "+
+			"NOLOGIN NOREPLICATION NOSUPERUSER CONNECTION LIMIT -1", name)
+	}
+	lastTransactionQuery := "SELECT xmin FROM pg_catalog.pg_authid WHERE rolname = $1"
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+
+		roleSynchronizer = RoleSynchronizer{
+			instance: &fakeInstanceData{
+				Instance: postgres.NewInstance().WithNamespace("default"),
+				db:       db,
+			},
+		}
+	})
+
+	It("creates a role hierarchy in dependency order and then revokes a removed membership",
+		func(ctx context.Context) {
+			managedConf := apiv1.ManagedConfiguration{
+				Roles: []apiv1.RoleConfiguration{
+					{
+						Name:                   "app_user",
+						InRoles:                []string{"app_readonly"},
+						InRolesWithAdminOption: []string{"app_readwrite"},
+						ConnectionLimit:        -1,
+						Ensure:                 apiv1.EnsurePresent,
+					},
+					{
+						Name:            "app_readwrite",
+						InRoles:         []string{"app_readonly"},
+						ConnectionLimit: -1,
+						Ensure:          apiv1.EnsurePresent,
+					},
+					{
+						Name:            "app_readonly",
+						ConnectionLimit: -1,
+						Ensure:          apiv1.EnsurePresent,
+					},
+				},
+			}
+
+			By("creating the parent roles before their members", func() {
+				mock.ExpectQuery(expectedSelStmt).WillReturnRows(sqlmock.NewRows(listColumns).
+					AddRow("postgres", true, false, true, true, true, false, -1, []byte("12345"),
+						nil, false, nil, 11, []byte("{}"), nil))
+
+				mock.ExpectExec(createStmt("app_readonly")).WillReturnResult(sqlmock.NewResult(11, 1))
+				mock.ExpectQuery(lastTransactionQuery).WithArgs("app_readonly").
+					WillReturnRows(sqlmock.NewRows([]string{"xmin"}).AddRow("12"))
+				mock.ExpectExec(createStmt("app_readwrite") + " IN ROLE app_readonly").
+					WillReturnResult(sqlmock.NewResult(11, 1))
+				mock.ExpectQuery(lastTransactionQuery).WithArgs("app_readwrite").
+					WillReturnRows(sqlmock.NewRows([]string{"xmin"}).AddRow("13"))
+				mock.ExpectExec(createStmt("app_user") + " IN ROLE app_readonly,app_readwrite").
+					WillReturnResult(sqlmock.NewResult(11, 1))
+				mock.ExpectExec(`GRANT "app_readwrite" TO "app_user" WITH ADMIN OPTION`).
+					WillReturnResult(sqlmock.NewResult(11, 1))
+				mock.ExpectQuery(lastTransactionQuery).WithArgs("app_user").
+					WillReturnRows(sqlmock.NewRows([]string{"xmin"}).AddRow("14"))
+
+				_, rolesWithErrors, err := roleSynchronizer.synchronizeRoles(ctx, db, &managedConf,
+					map[string]apiv1.PasswordState{})
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(rolesWithErrors).To(BeEmpty())
+			})
+
+			By("revoking the membership removed from the spec", func() {
+				managedConf.Roles[0].InRolesWithAdminOption = nil
+
+				mock.ExpectQuery(expectedSelStmt).WillReturnRows(sqlmock.NewRows(listColumns).
+					AddRow("app_readonly", false, true, false, false, false, false, -1, nil,
+						nil, false, nil, 12, nil, nil).
+					AddRow("app_readwrite", false, true, false, false, false, false, -1, nil,
+						nil, false, nil, 13, []byte(`{"app_readonly"}`), nil).
+					AddRow("app_user", false, true, false, false, false, false, -1, nil,
+						nil, false, nil, 14, []byte(`{"app_readonly","app_readwrite"}`), []byte(`{"app_readwrite"}`)))
+
+				mock.ExpectQuery(expectedMembershipStmt).WithArgs("app_user").
+					WillReturnRows(sqlmock.NewRows([]string{"inroles", "adminroles"}).
+						AddRow([]byte(`{"app_readonly","app_readwrite"}`), []byte(`{"app_readwrite"}`)))
+				mock.ExpectBegin()
+				mock.ExpectExec(`REVOKE "app_readwrite" FROM "app_user"`).
+					WillReturnResult(sqlmock.NewResult(2, 3))
+				mock.ExpectCommit()
+
+				_, rolesWithErrors, err := roleSynchronizer.synchronizeRoles(ctx, db, &managedConf,
+					map[string]apiv1.PasswordState{
+						"app_readonly":  {TransactionID: 12},
+						"app_readwrite": {TransactionID: 13},
+						"app_user":      {TransactionID: 14},
+					})
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(rolesWithErrors).To(BeEmpty())
+			})
+		})
+
+	It("keeps a membership while removing its admin option", func(ctx context.Context) {
+		managedConf := apiv1.ManagedConfiguration{
+			Roles: []apiv1.RoleConfiguration{
+				{
+					Name:            "app_user",
+					InRoles:         []string{"app_readwrite"},
+					ConnectionLimit: -1,
+				},
+			},
+		}
+
+		mock.ExpectQuery(expectedSelStmt).WillReturnRows(sqlmock.NewRows(listColumns).
+			AddRow("app_user", false, true, false, false, false, false, -1, nil,
+				nil, false, nil, 14, []byte(`{"app_readwrite"}`), []byte(`{"app_readwrite"}`)))
+
+		mock.ExpectQuery(expectedMembershipStmt).WithArgs("app_user").
+			WillReturnRows(sqlmock.NewRows([]string{"inroles", "adminroles"}).
+				AddRow([]byte(`{"app_readwrite"}`), []byte(`{"app_readwrite"}`)))
+		mock.ExpectBegin()
+		mock.ExpectExec(`REVOKE ADMIN OPTION FOR "app_readwrite" FROM "app_user"`).
+			WillReturnResult(sqlmock.NewResult(2, 3))
+		mock.ExpectCommit()
+
+		_, rolesWithErrors, err := roleSynchronizer.synchronizeRoles(ctx, db, &managedConf,
+			map[string]apiv1.PasswordState{"app_user": {TransactionID: 14}})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(rolesWithErrors).To(BeEmpty())
+	})
+})
@@ -27,18 +27,20 @@ const (
 	expectedSelStmt = `SELECT rolname, rolsuper, rolinherit, rolcreaterole, rolcreatedb, 
 		rolcanlogin, rolreplication, rolconnlimit, rolpassword, rolvaliduntil, rolbypassrls,
 		pg_catalog.shobj_description(auth.oid, 'pg_authid') as comment, auth.xmin,
-		mem.inroles
+		mem.inroles, mem.adminroles
 	FROM pg_catalog.pg_authid as auth
 	LEFT JOIN (
-		SELECT array_agg(pg_get_userbyid(roleid)) as inroles, member
+		SELECT array_agg(pg_get_userbyid(roleid)) as inroles,
+			array_agg(pg_get_userbyid(roleid)) FILTER (WHERE admin_option) as adminroles, member
 		FROM pg_auth_members GROUP BY member
 	) mem ON member = oid
 	WHERE rolname not like 'pg\_%'`
 
-	expectedMembershipStmt = `SELECT mem.inroles 
+	expectedMembershipStmt = `SELECT mem.inroles, mem.adminroles
 	FROM pg_catalog.pg_authid as auth
 	LEFT JOIN (
-		SELECT array_agg(pg_get_userbyid(roleid)) as inroles, member
+		SELECT array_agg(pg_get_userbyid(roleid)) as inroles,
+			array_agg(pg_get_userbyid(roleid)) FILTER (WHERE admin_option) as adminroles, member
 		FROM pg_auth_members GROUP BY member
 	) mem ON member = oid
 	WHERE rolname = $1`
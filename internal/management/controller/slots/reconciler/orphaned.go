@@ -0,0 +1,107 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"time"
+
+	"k8s.io/client-go/tools/record"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// OrphanedSlots keeps track of the HA replication slots, on the primary,
+// that don't correspond to any instance of the cluster anymore, and
+// decides when they can be dropped
+type OrphanedSlots struct {
+	recorder  record.EventRecorder
+	expiresAt map[string]time.Time
+}
+
+// NewOrphanedSlots creates a new tracker of the orphaned replication
+// slots, reporting the dropped ones with the passed event recorder
+func NewOrphanedSlots(recorder record.EventRecorder) *OrphanedSlots {
+	return &OrphanedSlots{
+		recorder:  recorder,
+		expiresAt: make(map[string]time.Time),
+	}
+}
+
+// canDrop checks if the grace period of the passed orphaned slot
+// expired, starting it when the slot is found for the first time.
+// A nil tracker allows every slot to be dropped immediately
+func (o *OrphanedSlots) canDrop(slotName string, gracePeriod time.Duration) bool {
+	if o == nil || gracePeriod <= 0 {
+		return true
+	}
+
+	expiresAt, ok := o.expiresAt[slotName]
+	if !ok {
+		expiresAt = time.Now().Add(gracePeriod)
+		o.expiresAt[slotName] = expiresAt
+	}
+
+	return !time.Now().Before(expiresAt)
+}
+
+// setDropped records that the passed orphaned slot has been dropped
+func (o *OrphanedSlots) setDropped(cluster *apiv1.Cluster, slotName string) {
+	if o == nil {
+		return
+	}
+
+	delete(o.expiresAt, slotName)
+	if o.recorder != nil {
+		o.recorder.Eventf(cluster, "Normal", "DroppedReplicationSlot",
+			"Dropped the replication slot %s, not corresponding to any instance", slotName)
+	}
+}
+
+// retain forgets the slots that are not orphaned anymore, either because
+// they correspond again to an instance or because they have been removed
+func (o *OrphanedSlots) retain(orphanedSlotNames map[string]bool) {
+	if o == nil {
+		return
+	}
+
+	for slotName := range o.expiresAt {
+		if !orphanedSlotNames[slotName] {
+			delete(o.expiresAt, slotName)
+		}
+	}
+}
+
+// GetRemainingGracePeriod returns how long to wait before the grace period
+// of the first orphaned slot expires, or zero if there are none
+func (o *OrphanedSlots) GetRemainingGracePeriod() time.Duration {
+	if o == nil {
+		return 0
+	}
+
+	var result time.Duration
+	for _, expiresAt := range o.expiresAt {
+		remaining := time.Until(expiresAt)
+		if remaining <= 0 {
+			remaining = time.Second
+		}
+		if result == 0 || remaining < result {
+			result = remaining
+		}
+	}
+
+	return result
+}
@@ -29,12 +29,16 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/slots/infrastructure"
 )
 
-// ReconcileReplicationSlots reconciles the replication slots of a given instance
+// ReconcileReplicationSlots reconciles the replication slots of a given instance.
+// The HA replication slots not corresponding to any instance are dropped from
+// the primary once the grace period tracked by orphanedSlots expires, or
+// immediately when orphanedSlots is nil
 func ReconcileReplicationSlots(
 	ctx context.Context,
 	instanceName string,
 	db *sql.DB,
 	cluster *apiv1.Cluster,
+	orphanedSlots *OrphanedSlots,
 ) (reconcile.Result, error) {
 	if cluster.Spec.ReplicationSlots == nil ||
 		cluster.Spec.ReplicationSlots.HighAvailability == nil {
@@ -53,7 +57,7 @@ func ReconcileReplicationSlots(
 	}
 
 	if isPrimary {
-		return reconcilePrimaryHAReplicationSlots(ctx, db, cluster)
+		return reconcilePrimaryHAReplicationSlots(ctx, db, cluster, orphanedSlots)
 	}
 
 	return reconcile.Result{}, nil
@@ -64,6 +68,7 @@ func reconcilePrimaryHAReplicationSlots(
 	ctx context.Context,
 	db *sql.DB,
 	cluster *apiv1.Cluster,
+	orphanedSlots *OrphanedSlots,
 ) (reconcile.Result, error) {
 	contextLogger := log.FromContext(ctx)
 	contextLogger.Debug("Updating primary HA replication slots")
@@ -98,7 +103,12 @@ func reconcilePrimaryHAReplicationSlots(
 		"currentSlots", currentSlots,
 		"expectedSlots", expectedSlots)
 
-	// Delete any HA replication slots in the instance that is not from an existing cluster instance
+	// Delete any HA replication slots in the instance that is not from an existing cluster instance,
+	// once its grace period expires
+	gracePeriod := cluster.Spec.ReplicationSlots.HighAvailability.GetOrphanedSlotsGracePeriod()
+	orphanedSlotNames := make(map[string]bool)
+	defer orphanedSlots.retain(orphanedSlotNames)
+
 	needToReschedule := false
 	for _, slot := range currentSlots.Items {
 		if !slot.IsHA {
@@ -106,6 +116,13 @@ func reconcilePrimaryHAReplicationSlots(
 			continue
 		}
 		if !expectedSlots[slot.SlotName] {
+			orphanedSlotNames[slot.SlotName] = true
+			if !orphanedSlots.canDrop(slot.SlotName, gracePeriod) {
+				contextLogger.Trace("Skipping deletion of replication slot because of the grace period",
+					"slot", slot)
+				continue
+			}
+
 			// Avoid deleting active slots.
 			// It would trow an error on Postgres side.
 			if slot.Active {
@@ -119,6 +136,9 @@ func reconcilePrimaryHAReplicationSlots(
 			if err := infrastructure.Delete(ctx, db, slot); err != nil {
 				return reconcile.Result{}, fmt.Errorf("failure deleting replication slot %q: %w", slot.SlotName, err)
 			}
+			contextLogger.Info("Dropped orphaned HA replication slot", "slotName", slot.SlotName)
+			delete(orphanedSlotNames, slot.SlotName)
+			orphanedSlots.setDropped(cluster, slot.SlotName)
 		}
 	}
 
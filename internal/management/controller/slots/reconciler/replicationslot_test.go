@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -87,7 +88,7 @@ var _ = Describe("HA Replication Slots reconciliation in Primary", func() {
 
 		cluster := makeClusterWithInstanceNames([]string{"instance1", "instance2", "instance3"}, "instance1")
 
-		_, err := ReconcileReplicationSlots(ctx, "instance1", db, &cluster)
+		_, err := ReconcileReplicationSlots(ctx, "instance1", db, &cluster, nil)
 		Expect(err).ShouldNot(HaveOccurred())
 	})
 
@@ -105,7 +106,7 @@ var _ = Describe("HA Replication Slots reconciliation in Primary", func() {
 
 		cluster := makeClusterWithInstanceNames([]string{"instance1", "instance2"}, "instance1")
 
-		_, err := ReconcileReplicationSlots(ctx, "instance1", db, &cluster)
+		_, err := ReconcileReplicationSlots(ctx, "instance1", db, &cluster, nil)
 		Expect(err).ShouldNot(HaveOccurred())
 	})
 
@@ -120,11 +121,76 @@ var _ = Describe("HA Replication Slots reconciliation in Primary", func() {
 
 		cluster := makeClusterWithInstanceNames([]string{"instance1", "instance2"}, "instance1")
 
-		_, err := ReconcileReplicationSlots(ctx, "instance1", db, &cluster)
+		_, err := ReconcileReplicationSlots(ctx, "instance1", db, &cluster, nil)
 		Expect(err).ShouldNot(HaveOccurred())
 	})
 })
 
+var _ = Describe("Orphaned HA replication slots in Primary", func() {
+	var (
+		db       *sql.DB
+		mock     sqlmock.Sqlmock
+		recorder *record.FakeRecorder
+		tracker  *OrphanedSlots
+		cluster  apiv1.Cluster
+	)
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New()
+		Expect(err).NotTo(HaveOccurred())
+
+		recorder = record.NewFakeRecorder(10)
+		tracker = NewOrphanedSlots(recorder)
+		cluster = makeClusterWithInstanceNames([]string{"instance1", "instance2"}, "instance1")
+		cluster.Spec.ReplicationSlots.HighAvailability.OrphanedSlotsGracePeriod = 300
+	})
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	expectSlots := func() {
+		mock.ExpectQuery("^SELECT (.+) FROM pg_replication_slots").
+			WillReturnRows(sqlmock.NewRows(repSlotColumns).
+				AddRow(newRepSlot("instance2", true, "lsn2")...).
+				AddRow(newRepSlot("instance3", false, "lsn3")...).
+				AddRow("custom_slot", string(infrastructure.SlotTypePhysical), false, "lsn4", false))
+	}
+
+	It("eventually drops the slot of a removed replica", func(ctx SpecContext) {
+		expectSlots()
+		result, err := ReconcileReplicationSlots(ctx, "instance1", db, &cluster, tracker)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(result.IsZero()).To(BeTrue())
+		Expect(tracker.GetRemainingGracePeriod()).To(BeNumerically("~", 300*time.Second, time.Minute))
+		Expect(recorder.Events).ToNot(Receive())
+
+		// The grace period expires
+		tracker.expiresAt[slotPrefix+"instance3"] = time.Now().Add(-time.Second)
+
+		expectSlots()
+		mock.ExpectExec("SELECT pg_drop_replication_slot").WithArgs(slotPrefix + "instance3").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		_, err = ReconcileReplicationSlots(ctx, "instance1", db, &cluster, tracker)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(recorder.Events).To(Receive(ContainSubstring("DroppedReplicationSlot")))
+		Expect(tracker.GetRemainingGracePeriod()).To(BeZero())
+	})
+
+	It("forgets the slots that correspond again to an instance", func(ctx SpecContext) {
+		expectSlots()
+		_, err := ReconcileReplicationSlots(ctx, "instance1", db, &cluster, tracker)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(tracker.expiresAt).To(HaveKey(slotPrefix + "instance3"))
+
+		cluster.Status.InstanceNames = append(cluster.Status.InstanceNames, "instance3")
+		expectSlots()
+		_, err = ReconcileReplicationSlots(ctx, "instance1", db, &cluster, tracker)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(tracker.expiresAt).To(BeEmpty())
+		Expect(recorder.Events).ToNot(Receive())
+	})
+})
+
 var _ = Describe("dropReplicationSlots", func() {
 	const selectPgRepSlot = "^SELECT (.+) FROM pg_replication_slots"
 
@@ -17,6 +17,7 @@ limitations under the License.
 package controller
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -35,6 +36,7 @@ type PgBouncerInstanceInterface interface {
 	Pause() error
 	Resume() error
 	Reload() error
+	Drain(ctx context.Context) error
 }
 
 // NewPgBouncerInstance initializes a new pgBouncerInstance
@@ -143,3 +145,31 @@ func (p *pgBouncerInstance) Reload() error {
 
 	return nil
 }
+
+// Drain pauses the instance, letting the running transactions complete,
+// and then waits for all the server connections to be closed.
+// The passed context limits the time spent waiting
+func (p *pgBouncerInstance) Drain(ctx context.Context) error {
+	// First step: connect to the pgbouncer administrative database
+	db, err := p.pool.Connection("pgbouncer")
+	if err != nil {
+		return fmt.Errorf("while connecting to pgbouncer database locally: %w", err)
+	}
+
+	// Second step: pause pgbouncer, waiting for the running
+	// transactions to complete
+	if _, err = db.ExecContext(ctx, "PAUSE"); err != nil {
+		return fmt.Errorf("while pausing instance: %w", err)
+	}
+
+	p.mu.Lock()
+	p.paused = true
+	p.mu.Unlock()
+
+	// Third step: wait for the server connections to be closed
+	if _, err = db.ExecContext(ctx, "WAIT_CLOSE"); err != nil {
+		return fmt.Errorf("while waiting for the server connections to be closed: %w", err)
+	}
+
+	return nil
+}
@@ -17,8 +17,10 @@ limitations under the License.
 package controller
 
 import (
+	"context"
 	"database/sql"
 	"sync"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 
@@ -88,6 +90,58 @@ var _ = Describe("PgBouncerInstance", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 	})
+
+	Context("when the instance is drained", func() {
+		It("pauses the instance and waits for the server connections to be closed", func() {
+			mock.ExpectExec("PAUSE").WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectExec("WAIT_CLOSE").WillReturnResult(sqlmock.NewResult(1, 1))
+
+			pgBouncerInstance := &pgBouncerInstance{
+				mu:     &sync.RWMutex{},
+				paused: false,
+				pool:   &fakePooler{DB: db},
+			}
+
+			err := pgBouncerInstance.Drain(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pgBouncerInstance.Paused()).To(BeTrue())
+		})
+
+		It("waits for the running transactions to complete", func(ctx SpecContext) {
+			mock.ExpectExec("PAUSE").
+				WillDelayFor(200 * time.Millisecond).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectExec("WAIT_CLOSE").WillReturnResult(sqlmock.NewResult(1, 1))
+
+			pgBouncerInstance := &pgBouncerInstance{
+				mu:     &sync.RWMutex{},
+				paused: false,
+				pool:   &fakePooler{DB: db},
+			}
+
+			start := time.Now()
+			Expect(pgBouncerInstance.Drain(ctx)).To(Succeed())
+			Expect(time.Since(start)).To(BeNumerically(">=", 200*time.Millisecond))
+		})
+
+		It("gives up when the timeout expires", func() {
+			mock.ExpectExec("PAUSE").
+				WillDelayFor(time.Second).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+
+			pgBouncerInstance := &pgBouncerInstance{
+				mu:     &sync.RWMutex{},
+				paused: false,
+				pool:   &fakePooler{DB: db},
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			err := pgBouncerInstance.Drain(ctx)
+			Expect(err).To(HaveOccurred())
+			Expect(pgBouncerInstance.Paused()).To(BeFalse())
+		})
+	})
 })
 
 type fakePooler struct {
@@ -133,13 +133,14 @@ func IsInstanceRunning(
 	contextLogger := log.FromContext(ctx).WithName("plugin.IsInstanceRunning")
 	timeout := time.Second * 10
 	clientInterface := kubernetes.NewForConfigOrDie(plugin.Config)
-	stdout, stderr, err := utils.ExecCommand(
+	stdout, stderr, err := utils.ExecCommandWithRetry(
 		ctx,
 		clientInterface,
 		plugin.Config,
 		pod,
 		specs.PostgresContainerName,
 		&timeout,
+		utils.DefaultExecBackoff,
 		"pg_ctl", "status")
 	if err == nil {
 		return true, nil
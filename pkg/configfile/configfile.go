@@ -148,6 +148,28 @@ func ReadLinesFromConfigurationContents(content []string, options ...string) []s
 	return result
 }
 
+// ReadOptionNamesFromConfigurationContents returns the names of the options
+// set in the provided configuration content, ignoring comments and includes
+func ReadOptionNamesFromConfigurationContents(lines []string) []string {
+	result := make([]string, 0, len(lines))
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		kv := strings.SplitN(line, "=", 2)
+		fields := strings.Fields(kv[0])
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "include") {
+			continue
+		}
+
+		result = append(result, fields[0])
+	}
+
+	return result
+}
+
 // EnsureIncludes makes sure the passed PostgreSQL configuration file has an include directive
 // to every filesToInclude.
 func EnsureIncludes(fileName string, filesToInclude ...string) (changed bool, err error) {
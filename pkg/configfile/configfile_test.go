@@ -285,3 +285,23 @@ var _ = Describe("Remove configuration files option", func() {
 		Expect(updatedContent).To(Equal(wantedContent))
 	})
 })
+
+var _ = Describe("Read configuration option names", func() {
+	It("returns the names of the options, skipping comments and includes", func() {
+		content := []string{
+			"# Do not edit this file manually!",
+			"",
+			"include 'custom.conf'",
+			"include_if_exists = 'override.conf'",
+			"work_mem = '8MB'",
+			"  shared_buffers='128MB'",
+			"log_line_prefix '%m '",
+		}
+
+		Expect(ReadOptionNamesFromConfigurationContents(content)).To(Equal([]string{
+			"work_mem",
+			"shared_buffers",
+			"log_line_prefix",
+		}))
+	})
+})
@@ -71,7 +71,8 @@ const (
 
 	pgBouncerIniTemplateString = `
 [databases]
-* = host={{.Pooler.Spec.Cluster.Name}}-{{.Pooler.Spec.Type}}
+{{ .Databases -}}
+* = host={{ .Host }}
 
 [pgbouncer]
 pool_mode = {{ .Pooler.Spec.PgBouncer.PoolMode }}
@@ -182,6 +183,7 @@ func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (Configurat
 		parameters["auth_file"] = authFilePath
 	}
 
+	host := fmt.Sprintf("%s-%s", pooler.Spec.Cluster.Name, pooler.Spec.Type)
 	templateData := struct {
 		Pooler            *apiv1.Pooler
 		AuthQuery         string
@@ -189,6 +191,8 @@ func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (Configurat
 		AuthQueryPassword string
 		Parameters        string
 		PgHba             []string
+		Host              string
+		Databases         string
 	}{
 		Pooler:            pooler,
 		AuthQuery:         pooler.GetAuthQuery(),
@@ -203,6 +207,10 @@ func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (Configurat
 		// to be stable.
 		Parameters: stringifyPgBouncerParameters(parameters),
 		PgHba:      pooler.Spec.PgBouncer.PgHBA,
+		Host:       host,
+		// The databases with specific pool settings are listed in the order
+		// chosen by the user, before the fallback entry
+		Databases: stringifyPgBouncerDatabases(host, pooler.Spec.PgBouncer.Databases),
 	}
 
 	err = pgBouncerIniTemplate.Execute(&pgbouncerIni, templateData)
@@ -21,6 +21,8 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 )
 
 // stringifyPgBouncerParameters will take map of PgBouncer parameters and emit
@@ -39,6 +41,32 @@ func stringifyPgBouncerParameters(parameters map[string]string) (paramsString st
 	return paramsString
 }
 
+// stringifyPgBouncerDatabases emits the entries of the `[databases]` section
+// for the databases having specific pool settings, all of them pointing to
+// the passed host
+func stringifyPgBouncerDatabases(
+	host string,
+	databases []apiv1.PgBouncerDatabaseConfiguration,
+) (databasesString string) {
+	for _, database := range databases {
+		options := []string{"host=" + host}
+		if database.PoolMode != "" {
+			options = append(options, fmt.Sprintf("pool_mode=%s", database.PoolMode))
+		}
+		if database.PoolSize != nil {
+			options = append(options, fmt.Sprintf("pool_size=%d", *database.PoolSize))
+		}
+		if database.MinPoolSize != nil {
+			options = append(options, fmt.Sprintf("min_pool_size=%d", *database.MinPoolSize))
+		}
+		if database.MaxDBConnections != nil {
+			options = append(options, fmt.Sprintf("max_db_connections=%d", *database.MaxDBConnections))
+		}
+		databasesString += fmt.Sprintf("%s = %s\n", database.Name, strings.Join(options, " "))
+	}
+	return databasesString
+}
+
 // buildPgBouncerParameters will build a PgBouncer configuration applying any
 // default parameters and forcing any required parameter needed for the
 // controller to work correctly
@@ -17,6 +17,10 @@ limitations under the License.
 package config
 
 import (
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -45,3 +49,32 @@ var _ = Describe("PgBouncer configuration", func() {
 		Expect(params).NotTo(MatchRegexp("^pid_file.*"))
 	})
 })
+
+var _ = Describe("PgBouncer databases section", func() {
+	It("is empty when there are no databases with specific settings", func() {
+		Expect(stringifyPgBouncerDatabases("cluster-example-rw", nil)).To(BeEmpty())
+	})
+
+	It("lists the settings of the databases in the requested order", func() {
+		databases := []apiv1.PgBouncerDatabaseConfiguration{
+			{
+				Name:     "oltp",
+				PoolMode: apiv1.PgBouncerPoolModeTransaction,
+				PoolSize: ptr.To(int32(20)),
+			},
+			{
+				Name:             "analytics",
+				PoolMode:         apiv1.PgBouncerPoolModeSession,
+				MinPoolSize:      ptr.To(int32(2)),
+				MaxDBConnections: ptr.To(int32(10)),
+			},
+			{
+				Name: "app",
+			},
+		}
+		Expect(stringifyPgBouncerDatabases("cluster-example-rw", databases)).To(Equal(
+			"oltp = host=cluster-example-rw pool_mode=transaction pool_size=20\n" +
+				"analytics = host=cluster-example-rw pool_mode=session min_pool_size=2 max_db_connections=10\n" +
+				"app = host=cluster-example-rw\n"))
+	})
+})
@@ -0,0 +1,206 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsserver
+
+import (
+	"database/sql"
+	"strconv"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ShowDatabasesMetrics contains all the SHOW DATABASES Metrics
+type ShowDatabasesMetrics struct {
+	PoolSize,
+	MinPoolSize,
+	ReservePool,
+	MaxConnections,
+	CurrentConnections,
+	Paused,
+	Disabled *prometheus.GaugeVec
+}
+
+// Describe produces the description for all the contained Metrics
+func (r *ShowDatabasesMetrics) Describe(ch chan<- *prometheus.Desc) {
+	r.PoolSize.Describe(ch)
+	r.MinPoolSize.Describe(ch)
+	r.ReservePool.Describe(ch)
+	r.MaxConnections.Describe(ch)
+	r.CurrentConnections.Describe(ch)
+	r.Paused.Describe(ch)
+	r.Disabled.Describe(ch)
+}
+
+// Reset resets all the contained Metrics
+func (r *ShowDatabasesMetrics) Reset() {
+	r.PoolSize.Reset()
+	r.MinPoolSize.Reset()
+	r.ReservePool.Reset()
+	r.MaxConnections.Reset()
+	r.CurrentConnections.Reset()
+	r.Paused.Reset()
+	r.Disabled.Reset()
+}
+
+// Collect sends all the contained Metrics to the passed channel
+func (r *ShowDatabasesMetrics) Collect(ch chan<- prometheus.Metric) {
+	r.PoolSize.Collect(ch)
+	r.MinPoolSize.Collect(ch)
+	r.ReservePool.Collect(ch)
+	r.MaxConnections.Collect(ch)
+	r.CurrentConnections.Collect(ch)
+	r.Paused.Collect(ch)
+	r.Disabled.Collect(ch)
+}
+
+// NewShowDatabasesMetrics returns all the metrics of the SHOW DATABASES command
+func NewShowDatabasesMetrics(subsystem string) *ShowDatabasesMetrics {
+	subsystem += "_databases"
+	return &ShowDatabasesMetrics{
+		PoolSize: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "pool_size",
+			Help:      "Maximum number of server connections of each pool of the database.",
+		}, []string{"database"}),
+		MinPoolSize: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "min_pool_size",
+			Help:      "Minimum number of server connections of each pool of the database.",
+		}, []string{"database"}),
+		ReservePool: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "reserve_pool",
+			Help:      "Maximum number of additional connections of each pool of the database.",
+		}, []string{"database"}),
+		MaxConnections: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "max_connections",
+			Help:      "Maximum number of allowed server connections to the database, 0 if unlimited.",
+		}, []string{"database"}),
+		CurrentConnections: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "current_connections",
+			Help:      "Current number of server connections to the database.",
+		}, []string{"database"}),
+		Paused: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "paused",
+			Help:      "1 if the database is paused, 0 otherwise.",
+		}, []string{"database"}),
+		Disabled: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "disabled",
+			Help:      "1 if the database is disabled, 0 otherwise.",
+		}, []string{"database"}),
+	}
+}
+
+func (e *Exporter) collectShowDatabases(ch chan<- prometheus.Metric, db *sql.DB) {
+	contextLogger := log.FromContext(e.ctx)
+
+	e.Metrics.ShowDatabases.Reset()
+	// First, let's check the connection. No need to proceed if this fails.
+	rows, err := db.Query("SHOW DATABASES;")
+	if err != nil {
+		contextLogger.Error(err, "Error while executing SHOW DATABASES")
+		e.Metrics.PgbouncerUp.Set(0)
+		e.Metrics.Error.Set(1)
+		return
+	}
+
+	e.Metrics.PgbouncerUp.Set(1)
+	e.Metrics.Error.Set(0)
+	defer func() {
+		err = rows.Close()
+		if err != nil {
+			contextLogger.Error(err, "while closing rows for SHOW DATABASES")
+		}
+	}()
+
+	// The columns of SHOW DATABASES change between PgBouncer versions,
+	// so we read them by name
+	cols, err := rows.Columns()
+	if err != nil {
+		contextLogger.Error(err, "Error while getting number of columns")
+		e.Metrics.PgbouncerUp.Set(0)
+		e.Metrics.Error.Set(1)
+		return
+	}
+
+	values := make([]sql.NullString, len(cols))
+	dest := make([]any, len(cols))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	gauges := map[string]*prometheus.GaugeVec{
+		"pool_size":           e.Metrics.ShowDatabases.PoolSize,
+		"min_pool_size":       e.Metrics.ShowDatabases.MinPoolSize,
+		"reserve_pool":        e.Metrics.ShowDatabases.ReservePool,
+		"reserve_pool_size":   e.Metrics.ShowDatabases.ReservePool,
+		"max_connections":     e.Metrics.ShowDatabases.MaxConnections,
+		"current_connections": e.Metrics.ShowDatabases.CurrentConnections,
+		"paused":              e.Metrics.ShowDatabases.Paused,
+		"disabled":            e.Metrics.ShowDatabases.Disabled,
+	}
+
+	for rows.Next() {
+		if err = rows.Scan(dest...); err != nil {
+			contextLogger.Error(err, "Error while executing SHOW DATABASES")
+			e.Metrics.Error.Set(1)
+			e.Metrics.PgCollectionErrors.WithLabelValues(err.Error()).Inc()
+			continue
+		}
+
+		var database string
+		for i, col := range cols {
+			if col == "name" {
+				database = values[i].String
+			}
+		}
+
+		for i, col := range cols {
+			gauge, ok := gauges[col]
+			if !ok || !values[i].Valid {
+				continue
+			}
+			value, err := strconv.ParseFloat(values[i].String, 64)
+			if err != nil {
+				contextLogger.Error(err, "Error while parsing SHOW DATABASES", "column", col)
+				e.Metrics.Error.Set(1)
+				e.Metrics.PgCollectionErrors.WithLabelValues(err.Error()).Inc()
+				continue
+			}
+			gauge.WithLabelValues(database).Set(value)
+		}
+	}
+
+	e.Metrics.ShowDatabases.Collect(ch)
+
+	if err = rows.Err(); err != nil {
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues(err.Error()).Inc()
+	}
+}
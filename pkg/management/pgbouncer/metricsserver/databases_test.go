@@ -0,0 +1,121 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsserver
+
+import (
+	"database/sql"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Exporter", func() {
+	var (
+		registry *prometheus.Registry
+		db       *sql.DB
+		mock     sqlmock.Sqlmock
+		exp      *Exporter
+		ch       chan prometheus.Metric
+		columns  = []string{
+			"name",
+			"host",
+			"port",
+			"database",
+			"force_user",
+			"pool_size",
+			"min_pool_size",
+			"reserve_pool",
+			"server_lifetime",
+			"pool_mode",
+			"max_connections",
+			"current_connections",
+			"paused",
+			"disabled",
+		}
+	)
+
+	BeforeEach(func(ctx SpecContext) {
+		var err error
+		db, mock, err = sqlmock.New()
+		Expect(err).ShouldNot(HaveOccurred())
+
+		exp = &Exporter{
+			Metrics: newMetrics(),
+			pool:    fakePooler{db: db},
+			ctx:     ctx,
+		}
+
+		registry = prometheus.NewRegistry()
+		registry.MustRegister(exp.Metrics.PgbouncerUp)
+		registry.MustRegister(exp.Metrics.Error)
+		registry.MustRegister(exp.Metrics.ShowDatabases.PoolSize)
+		registry.MustRegister(exp.Metrics.ShowDatabases.CurrentConnections)
+
+		ch = make(chan prometheus.Metric, 1000)
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	Context("collectShowDatabases", func() {
+		It("should react properly if the admin console is unreachable", func() {
+			mock.ExpectQuery("SHOW DATABASES;").WillReturnError(sql.ErrConnDone)
+			exp.collectShowDatabases(ch, db)
+
+			metrics, err := registry.Gather()
+			Expect(err).ToNot(HaveOccurred())
+
+			pgBouncerUpValue := getMetric(metrics, pgBouncerUpKey).GetMetric()[0].GetGauge().GetValue()
+			Expect(pgBouncerUpValue).Should(BeEquivalentTo(0))
+
+			errorValue := getMetric(metrics, lastCollectionErrorKey).GetMetric()[0].GetGauge().GetValue()
+			Expect(errorValue).To(BeEquivalentTo(1))
+
+			Expect(getMetric(metrics, "cnpg_pgbouncer_databases_pool_size")).To(BeNil())
+		})
+
+		It("should export the settings and the connections of every database", func() {
+			mock.ExpectQuery("SHOW DATABASES;").
+				WillReturnRows(sqlmock.NewRows(columns).
+					AddRow("app", "cluster-example-rw", 5432, "app", nil, 20, 0, 0, 0, "transaction", 0, 7, 0, 0).
+					AddRow("pgbouncer", nil, 6432, "pgbouncer", "pgbouncer", 2, 0, 0, 0, "statement", 0, 0, 0, 0))
+
+			exp.collectShowDatabases(ch, db)
+
+			metrics, err := registry.Gather()
+			Expect(err).ToNot(HaveOccurred())
+
+			pgBouncerUpValue := getMetric(metrics, pgBouncerUpKey).GetMetric()[0].GetGauge().GetValue()
+			Expect(pgBouncerUpValue).Should(BeEquivalentTo(1))
+
+			errorValue := getMetric(metrics, lastCollectionErrorKey).GetMetric()[0].GetGauge().GetValue()
+			Expect(errorValue).To(BeEquivalentTo(0))
+
+			poolSize := getMetric(metrics, "cnpg_pgbouncer_databases_pool_size").GetMetric()
+			Expect(poolSize).To(HaveLen(2))
+			Expect(poolSize[0].GetLabel()[0].GetValue()).To(Equal("app"))
+			Expect(poolSize[0].GetGauge().GetValue()).To(BeEquivalentTo(20))
+
+			currentConnections := getMetric(metrics, "cnpg_pgbouncer_databases_current_connections").GetMetric()
+			Expect(currentConnections[0].GetGauge().GetValue()).To(BeEquivalentTo(7))
+		})
+	})
+})
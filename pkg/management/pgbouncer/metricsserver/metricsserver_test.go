@@ -45,6 +45,11 @@ var _ = Describe("MetricsServer", func() {
 			Expect(exporter.Metrics.ShowLists).NotTo(BeNil())
 			Expect(exporter.Metrics.ShowPools).NotTo(BeNil())
 			Expect(exporter.Metrics.ShowStats).NotTo(BeNil())
+			Expect(exporter.Metrics.ShowDatabases).NotTo(BeNil())
+
+			By("exporting whether PgBouncer is up", func() {
+				Expect(getMetric(mfs, pgBouncerUpKey)).NotTo(BeNil())
+			})
 		})
 	})
 })
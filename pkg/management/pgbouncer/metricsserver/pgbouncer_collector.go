@@ -52,6 +52,7 @@ type metrics struct {
 	ShowLists          ShowListsMetrics
 	ShowPools          *ShowPoolsMetrics
 	ShowStats          *ShowStatsMetrics
+	ShowDatabases      *ShowDatabasesMetrics
 }
 
 // NewExporter creates an exporter
@@ -96,9 +97,10 @@ func newMetrics() *metrics {
 			Name:      "collection_duration_seconds",
 			Help:      "Collection time duration in seconds",
 		}, []string{"collector"}),
-		ShowLists: NewShowListsMetrics(subsystem),
-		ShowPools: NewShowPoolsMetrics(subsystem),
-		ShowStats: NewShowStatsMetrics(subsystem),
+		ShowLists:     NewShowListsMetrics(subsystem),
+		ShowPools:     NewShowPoolsMetrics(subsystem),
+		ShowStats:     NewShowStatsMetrics(subsystem),
+		ShowDatabases: NewShowDatabasesMetrics(subsystem),
 	}
 }
 
@@ -106,11 +108,13 @@ func newMetrics() *metrics {
 func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
 	ch <- e.Metrics.CollectionsTotal.Desc()
 	ch <- e.Metrics.Error.Desc()
+	ch <- e.Metrics.PgbouncerUp.Desc()
 	e.Metrics.PgCollectionErrors.Describe(ch)
 	e.Metrics.CollectionDuration.Describe(ch)
 	e.Metrics.ShowLists.Describe(ch)
 	e.Metrics.ShowPools.Describe(ch)
 	e.Metrics.ShowStats.Describe(ch)
+	e.Metrics.ShowDatabases.Describe(ch)
 }
 
 // Collect implements prometheus.Collector, collecting the Metrics values to
@@ -120,6 +124,7 @@ func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
 
 	ch <- e.Metrics.CollectionsTotal
 	ch <- e.Metrics.Error
+	ch <- e.Metrics.PgbouncerUp
 	e.Metrics.PgCollectionErrors.Collect(ch)
 	e.Metrics.CollectionDuration.Collect(ch)
 }
@@ -135,6 +140,7 @@ func (e *Exporter) collectPgBouncerMetrics(ch chan<- prometheus.Metric) {
 	db, err := e.GetPgBouncerDB()
 	if err != nil {
 		contextLogger.Error(err, "Error opening connection to PostgreSQL")
+		e.Metrics.PgbouncerUp.Set(0)
 		e.Metrics.Error.Set(1)
 		return
 	}
@@ -142,6 +148,7 @@ func (e *Exporter) collectPgBouncerMetrics(ch chan<- prometheus.Metric) {
 	e.collectShowLists(ch, db)
 	e.collectShowPools(ch, db)
 	e.collectShowStats(ch, db)
+	e.collectShowDatabases(ch, db)
 }
 
 // GetPgBouncerDB gets a connection to the admin user db "pgbouncer" on this instance
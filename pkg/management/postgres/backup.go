@@ -18,8 +18,10 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
 	"slices"
 	"time"
@@ -38,6 +40,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -92,7 +95,7 @@ func NewBarmanBackupCommand(
 		Instance:     instance,
 		Log:          log,
 		Capabilities: capabilities,
-		barmanBackup: barmanBackup.NewBackupCommand(cluster.Spec.Backup.BarmanObjectStore, capabilities),
+		barmanBackup: barmanBackup.NewBackupCommand(cluster.GetBackupConfiguration().BarmanObjectStore, capabilities),
 	}, nil
 }
 
@@ -129,7 +132,7 @@ func (b *BackupCommand) Start(ctx context.Context) error {
 		ctx,
 		b.Client,
 		b.Cluster.Namespace,
-		b.Cluster.Spec.Backup.BarmanObjectStore,
+		b.Cluster.GetBackupConfiguration().BarmanObjectStore,
 		b.Env)
 	if err != nil {
 		return fmt.Errorf("cannot recover backup credentials: %w", err)
@@ -222,7 +225,23 @@ func (b *BackupCommand) takeBackup(ctx context.Context) error {
 		return err
 	}
 
-	err := b.barmanBackup.Take(
+	// The global objects are exported right before the base backup
+	b.exportGlobals(ctx)
+
+	// barman-cloud-backup doesn't report its progress, which is
+	// estimated from the amount of data it reads while running
+	totalSize, err := b.Instance.getDataSize()
+	if err != nil {
+		b.Log.Warning("Can't get the size of the databases, the backup progress won't be estimated",
+			"err", err)
+	}
+	progressCtx, stopProgress := context.WithCancel(ctx)
+	progressResult := make(chan backupProgress, 1)
+	go func() {
+		progressResult <- b.trackProgress(progressCtx, totalSize, bytesReadByBarmanCloudBackup)
+	}()
+
+	err = b.barmanBackup.Take(
 		ctx,
 		b.Backup.Status.BackupName,
 		backupStatus.ServerName,
@@ -230,6 +249,12 @@ func (b *BackupCommand) takeBackup(ctx context.Context) error {
 		b.Cluster,
 		postgres.BackupTemporaryDirectory,
 	)
+
+	stopProgress()
+	progress := <-progressResult
+	b.Backup.Status.BackupProgress = progress.percentage
+	b.Backup.Status.BytesTransferred = progress.bytesTransferred
+
 	if err != nil {
 		b.Log.Error(err, "Error while taking barman backup", "err", err)
 		return err
@@ -240,6 +265,7 @@ func (b *BackupCommand) takeBackup(ctx context.Context) error {
 
 	// Set the status to completed
 	b.Backup.Status.SetAsCompleted()
+	b.Backup.Status.BackupProgress = ptr.To(int32(100))
 
 	barmanBackup, err := b.barmanBackup.GetExecutedBackupInfo(
 		ctx, b.Backup.Status.BackupName, backupStatus.ServerName, b.Cluster, b.Env)
@@ -264,6 +290,56 @@ func (b *BackupCommand) takeBackup(ctx context.Context) error {
 	return nil
 }
 
+// exportGlobals exports the global objects of the instance taking the
+// backup, when requested, and uploads them to the object store. A failure
+// doesn't prevent the backup from being taken, and is reported in its status
+func (b *BackupCommand) exportGlobals(ctx context.Context) {
+	if !b.Cluster.Spec.Backup.ExportGlobals {
+		return
+	}
+
+	status := &apiv1.GlobalsExportStatus{}
+	location, err := b.uploadGlobals(ctx)
+	if err != nil {
+		b.Log.Error(err, "Error while exporting the global objects")
+		b.Recorder.Event(b.Backup, "Warning", "GlobalsExportFailed", err.Error())
+		status.Error = err.Error()
+	} else {
+		status.ExportedAt = ptr.To(metav1.Now())
+		status.Path = location
+	}
+
+	b.Backup.Status.GlobalsExport = status
+	if err := PatchBackupStatusAndRetry(ctx, b.Client, b.Backup); err != nil {
+		b.Log.Error(err, "Can't set the status of the export of the global objects")
+	}
+}
+
+// uploadGlobals exports the global objects in the backup temporary
+// directory, outside of the data directory, and uploads them to the
+// object store. The local export is removed afterwards
+func (b *BackupCommand) uploadGlobals(ctx context.Context) (string, error) {
+	localFile := filepath.Join(postgres.BackupTemporaryDirectory, GlobalsExportFile)
+	defer func() {
+		if err := os.Remove(localFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+			b.Log.Error(err, "Can't remove the local export of the global objects")
+		}
+	}()
+
+	if err := b.Instance.ExportGlobals(localFile); err != nil {
+		return "", err
+	}
+
+	return UploadGlobals(
+		ctx,
+		b.Cluster.Spec.Backup.BarmanObjectStore,
+		b.Backup.Status.ServerName,
+		b.Backup.Name,
+		localFile,
+		b.Env,
+	)
+}
+
 func (b *BackupCommand) backupMaintenance(ctx context.Context) {
 	// Delete backups per policy
 	if b.Cluster.Spec.Backup.RetentionPolicy != "" {
@@ -272,7 +348,7 @@ func (b *BackupCommand) backupMaintenance(ctx context.Context) {
 			"retentionPolicy", b.Cluster.Spec.Backup.RetentionPolicy)
 		if err := barmanCommand.DeleteBackupsByPolicy(
 			ctx,
-			b.Cluster.Spec.Backup.BarmanObjectStore,
+			b.Cluster.GetBackupConfiguration().BarmanObjectStore,
 			b.Backup.Status.ServerName,
 			b.Env,
 			b.Cluster.Spec.Backup.RetentionPolicy,
@@ -338,7 +414,7 @@ func PatchBackupStatusAndRetry(
 
 // setupBackupStatus configures the backup's status from the provided configuration and instance
 func (b *BackupCommand) setupBackupStatus() {
-	barmanConfiguration := b.Cluster.Spec.Backup.BarmanObjectStore
+	barmanConfiguration := b.Cluster.GetBackupConfiguration().BarmanObjectStore
 	backupStatus := b.Backup.GetStatus()
 
 	if b.Capabilities.ShouldExecuteBackupWithName(b.Cluster) {
@@ -442,7 +518,7 @@ func useSameBackupLocation(backup *apiv1.BackupStatus, cluster *apiv1.Cluster) b
 	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
 		return false
 	}
-	configuration := cluster.Spec.Backup.BarmanObjectStore
+	configuration := cluster.GetBackupConfiguration().BarmanObjectStore
 	return backup.EndpointURL == configuration.EndpointURL &&
 		backup.DestinationPath == configuration.DestinationPath &&
 		(backup.ServerName == configuration.ServerName ||
@@ -464,7 +540,7 @@ func (b *BackupCommand) getBackupData(ctx context.Context) (backupDataGetter, er
 	// Extracting the latest backup using barman-cloud-backup-list
 	return barmanCommand.GetBackupList(
 		ctx,
-		b.Cluster.Spec.Backup.BarmanObjectStore,
+		b.Cluster.GetBackupConfiguration().BarmanObjectStore,
 		b.Backup.Status.ServerName,
 		b.Env,
 	)
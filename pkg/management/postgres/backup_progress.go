@@ -0,0 +1,219 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	barmanCapabilities "github.com/cloudnative-pg/barman-cloud/pkg/capabilities"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// backupProgressInterval is the time between two consecutive updates
+// of the progress of a running backup
+var backupProgressInterval = 30 * time.Second
+
+// procPath is where the proc filesystem is mounted
+var procPath = "/proc"
+
+// backupProgress is the estimated progress of a running backup.
+// Every field is nil when the corresponding value is unknown
+type backupProgress struct {
+	percentage       *int32
+	bytesTransferred *int64
+}
+
+// estimateBackupProgress computes the progress of a backup from the
+// amount of data already read and the expected size of the backup
+func estimateBackupProgress(bytesRead int64, totalSize int64) backupProgress {
+	result := backupProgress{
+		bytesTransferred: ptr.To(bytesRead),
+	}
+	if totalSize <= 0 {
+		return result
+	}
+
+	// The size of the data directory is just an estimation, and the
+	// backup is not done until barman-cloud-backup terminates
+	percentage := bytesRead * 100 / totalSize
+	result.percentage = ptr.To(int32(min(percentage, 99))) // #nosec G115
+	return result
+}
+
+// getDataSize returns the size of the databases of this instance,
+// which is used as the expected size of a base backup
+func (instance *Instance) getDataSize() (int64, error) {
+	db, err := instance.GetSuperUserDB()
+	if err != nil {
+		return 0, err
+	}
+
+	var size int64
+	row := db.QueryRow(
+		"SELECT pg_catalog.sum(pg_catalog.pg_database_size(oid))::bigint FROM pg_catalog.pg_database")
+	if err := row.Scan(&size); err != nil {
+		return 0, err
+	}
+	return size, nil
+}
+
+// bytesReadByBarmanCloudBackup returns how many bytes have been read
+// so far by the barman-cloud-backup process started by this instance
+// manager, and false if the process can't be found or its I/O
+// statistics are not available
+func bytesReadByBarmanCloudBackup() (int64, bool) {
+	entries, err := os.ReadDir(procPath)
+	if err != nil {
+		return 0, false
+	}
+
+	for _, entry := range entries {
+		if _, err := strconv.Atoi(entry.Name()); err != nil || !entry.IsDir() {
+			continue
+		}
+
+		processPath := filepath.Join(procPath, entry.Name())
+		if !isBarmanCloudBackupProcess(processPath) {
+			continue
+		}
+
+		return readProcessIOCounter(processPath, "rchar")
+	}
+
+	return 0, false
+}
+
+// isBarmanCloudBackupProcess checks if the process in the passed directory
+// is a barman-cloud-backup started by this instance manager. Processes
+// forked by barman-cloud-backup itself are not considered, as they
+// only read the temporary files being uploaded
+func isBarmanCloudBackupProcess(processPath string) bool {
+	ppid, ok := readProcessStatusField(processPath, "PPid")
+	if !ok || ppid != strconv.Itoa(os.Getpid()) {
+		return false
+	}
+
+	cmdline, err := os.ReadFile(filepath.Join(processPath, "cmdline")) // #nosec
+	if err != nil {
+		return false
+	}
+
+	// barman-cloud-backup is a Python script, so it can either be the
+	// executable or the first argument of the interpreter
+	arguments := bytes.SplitN(cmdline, []byte{0}, 3)
+	for _, argument := range arguments[:min(len(arguments), 2)] {
+		if filepath.Base(string(argument)) == barmanCapabilities.BarmanCloudBackup {
+			return true
+		}
+	}
+	return false
+}
+
+// readProcessStatusField reads a field from the status file of a process
+func readProcessStatusField(processPath string, field string) (string, bool) {
+	return readProcessFileField(filepath.Join(processPath, "status"), field)
+}
+
+// readProcessIOCounter reads a counter from the I/O statistics of a process
+func readProcessIOCounter(processPath string, counter string) (int64, bool) {
+	value, ok := readProcessFileField(filepath.Join(processPath, "io"), counter)
+	if !ok {
+		return 0, false
+	}
+
+	result, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return result, true
+}
+
+// readProcessFileField reads the value of a field from a file in the
+// "key: value" format used in the proc filesystem
+func readProcessFileField(fileName string, field string) (string, bool) {
+	file, err := os.Open(fileName) // #nosec
+	if err != nil {
+		return "", false
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), ":")
+		if found && key == field {
+			return strings.TrimSpace(value), true
+		}
+	}
+	return "", false
+}
+
+// trackProgress periodically stores the progress of the running backup
+// in the status of the Backup object, until the context is cancelled.
+// It returns the last known progress
+func (b *BackupCommand) trackProgress(
+	ctx context.Context,
+	totalSize int64,
+	bytesRead func() (int64, bool),
+) backupProgress {
+	var progress backupProgress
+
+	ticker := time.NewTicker(backupProgressInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return progress
+		case <-ticker.C:
+		}
+
+		read, ok := bytesRead()
+		if !ok {
+			continue
+		}
+
+		progress = estimateBackupProgress(read, totalSize)
+		if err := b.patchProgress(ctx, progress); err != nil {
+			b.Log.Debug("Can't update the backup progress", "err", err)
+		}
+	}
+}
+
+// patchProgress updates the progress in the status of the Backup object
+func (b *BackupCommand) patchProgress(ctx context.Context, progress backupProgress) error {
+	var backup apiv1.Backup
+	if err := b.Client.Get(ctx, client.ObjectKeyFromObject(b.Backup), &backup); err != nil {
+		return err
+	}
+
+	origBackup := backup.DeepCopy()
+	backup.Status.BackupProgress = progress.percentage
+	backup.Status.BytesTransferred = progress.bytesTransferred
+	return b.Client.Status().Patch(ctx, &backup, client.MergeFrom(origBackup))
+}
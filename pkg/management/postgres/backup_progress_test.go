@@ -0,0 +1,167 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("estimating the backup progress", func() {
+	It("reports the amount of data read and the percentage", func() {
+		progress := estimateBackupProgress(250, 1000)
+		Expect(progress.bytesTransferred).To(HaveValue(BeEquivalentTo(250)))
+		Expect(progress.percentage).To(HaveValue(BeEquivalentTo(25)))
+	})
+
+	It("never reports a running backup as complete", func() {
+		progress := estimateBackupProgress(1500, 1000)
+		Expect(progress.percentage).To(HaveValue(BeEquivalentTo(99)))
+	})
+
+	It("doesn't report the percentage when the size is unknown", func() {
+		progress := estimateBackupProgress(250, 0)
+		Expect(progress.bytesTransferred).To(HaveValue(BeEquivalentTo(250)))
+		Expect(progress.percentage).To(BeNil())
+	})
+})
+
+var _ = Describe("reading the data read by barman-cloud-backup", func() {
+	addProcess := func(pid int, ppid int, cmdline string, rchar int64) {
+		processPath := filepath.Join(procPath, fmt.Sprint(pid))
+		Expect(os.MkdirAll(processPath, 0o700)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(processPath, "status"),
+			[]byte(fmt.Sprintf("Name:\tpython3\nPid:\t%d\nPPid:\t%d\n", pid, ppid)), 0o600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(processPath, "cmdline"),
+			[]byte(cmdline), 0o600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(processPath, "io"),
+			[]byte(fmt.Sprintf("rchar: %d\nwchar: 42\n", rchar)), 0o600)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		procPath = GinkgoT().TempDir()
+		DeferCleanup(func() {
+			procPath = "/proc"
+		})
+	})
+
+	It("reads the counter of the barman-cloud-backup started by the instance manager", func() {
+		addProcess(10, os.Getpid(), "postgres\x00-D\x00/var/lib/postgresql/data/pgdata\x00", 1)
+		addProcess(20, os.Getpid(), "/usr/bin/python3\x00/usr/local/bin/barman-cloud-backup\x00--name\x00", 4096)
+		// a worker process forked by barman-cloud-backup
+		addProcess(30, 20, "/usr/bin/python3\x00/usr/local/bin/barman-cloud-backup\x00--name\x00", 8192)
+
+		bytesRead, ok := bytesReadByBarmanCloudBackup()
+		Expect(ok).To(BeTrue())
+		Expect(bytesRead).To(BeEquivalentTo(4096))
+	})
+
+	It("reports that the progress is unknown when barman-cloud-backup is not running", func() {
+		addProcess(10, os.Getpid(), "postgres\x00-D\x00/var/lib/postgresql/data/pgdata\x00", 1)
+
+		_, ok := bytesReadByBarmanCloudBackup()
+		Expect(ok).To(BeFalse())
+	})
+})
+
+var _ = Describe("tracking the backup progress", func() {
+	var (
+		backupCommand *BackupCommand
+		backup        *apiv1.Backup
+	)
+
+	BeforeEach(func() {
+		backupProgressInterval = 10 * time.Millisecond
+		DeferCleanup(func() {
+			backupProgressInterval = 30 * time.Second
+		})
+
+		backup = &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-backup",
+				Namespace: "default",
+			},
+			Status: apiv1.BackupStatus{
+				Phase: apiv1.BackupPhaseRunning,
+			},
+		}
+		backupCommand = &BackupCommand{
+			Backup: backup,
+			Client: fake.NewClientBuilder().
+				WithScheme(scheme.BuildWithAllKnownScheme()).
+				WithObjects(backup).
+				WithStatusSubresource(backup).
+				Build(),
+			Log: log.FromContext(context.Background()),
+		}
+	})
+
+	getStatus := func(ctx context.Context) apiv1.BackupStatus {
+		var current apiv1.Backup
+		Expect(backupCommand.Client.Get(ctx, client.ObjectKeyFromObject(backup), &current)).To(Succeed())
+		return current.Status
+	}
+
+	It("stores the progress in the status of the backup", func(ctx SpecContext) {
+		trackCtx, cancel := context.WithCancel(ctx)
+		result := make(chan backupProgress, 1)
+		go func() {
+			result <- backupCommand.trackProgress(trackCtx, 1000, func() (int64, bool) {
+				return 400, true
+			})
+		}()
+
+		Eventually(func(g Gomega) {
+			status := getStatus(ctx)
+			g.Expect(status.BackupProgress).To(HaveValue(BeEquivalentTo(40)))
+			g.Expect(status.BytesTransferred).To(HaveValue(BeEquivalentTo(400)))
+		}).Should(Succeed())
+
+		cancel()
+		progress := <-result
+		Expect(progress.percentage).To(HaveValue(BeEquivalentTo(40)))
+	})
+
+	It("leaves the progress unset when it is not known", func(ctx SpecContext) {
+		trackCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+
+		progress := backupCommand.trackProgress(trackCtx, 1000, func() (int64, bool) {
+			return 0, false
+		})
+		Expect(progress.percentage).To(BeNil())
+		Expect(progress.bytesTransferred).To(BeNil())
+
+		status := getStatus(ctx)
+		Expect(status.BackupProgress).To(BeNil())
+		Expect(status.BytesTransferred).To(BeNil())
+	})
+})
@@ -19,6 +19,7 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"net"
 	"os"
 	"path"
 	"path/filepath"
@@ -27,6 +28,7 @@ import (
 
 	"github.com/cloudnative-pg/machinery/pkg/fileutils"
 	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/cloudnative-pg/machinery/pkg/stringset"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/configfile"
@@ -58,34 +60,155 @@ func InstallPgDataFileContent(ctx context.Context, pgdata, contents, destination
 }
 
 // RefreshConfigurationFilesFromCluster receives a cluster object, then generates the
-// PostgreSQL configuration and rewrites the file in the PGDATA if needed. This
+// PostgreSQL configuration and rewrites the file in the PGDATA if needed, together
+// with the passed configuration files to be included. This
 // function will return "true" if the configuration has been really changed.
 func (instance *Instance) RefreshConfigurationFilesFromCluster(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
 	preserveUserSettings bool,
+	includedConfiguration []postgres.IncludedConfiguration,
 ) (bool, error) {
-	postgresConfiguration, sha256, err := createPostgresqlConfiguration(cluster, preserveUserSettings)
+	postgresConfiguration, sha256, err := createPostgresqlConfiguration(
+		cluster, preserveUserSettings, includedConfiguration)
 	if err != nil {
 		return false, err
 	}
 
+	// The included files need to be in place before PostgreSQL reads
+	// the configuration including them
+	includedConfigurationChanged, err := instance.installIncludedConfiguration(ctx, includedConfiguration)
+	if err != nil {
+		return includedConfigurationChanged, fmt.Errorf(
+			"installing the included postgresql configuration: %w",
+			err)
+	}
+
+	previousConfiguration, err := fileutils.ReadFileLines(
+		filepath.Join(instance.PgData, constants.PostgresqlCustomConfigurationFile))
+	if err != nil {
+		return false, fmt.Errorf("reading previous postgresql configuration: %w", err)
+	}
+
 	postgresConfigurationChanged, err := InstallPgDataFileContent(
 		ctx,
 		instance.PgData,
 		postgresConfiguration,
 		constants.PostgresqlCustomConfigurationFile)
 	if err != nil {
-		return postgresConfigurationChanged, fmt.Errorf(
+		return postgresConfigurationChanged || includedConfigurationChanged, fmt.Errorf(
 			"installing postgresql configuration: %w",
 			err)
 	}
 
-	if sha256 != "" && postgresConfigurationChanged {
+	if sha256 != "" && (postgresConfigurationChanged || includedConfigurationChanged) {
 		instance.ConfigSha256 = sha256
 	}
 
-	return postgresConfigurationChanged, nil
+	if postgresConfigurationChanged {
+		removedParameters := getRemovedParameters(
+			previousConfiguration,
+			strings.Split(postgresConfiguration, "\n"))
+		if _, err := instance.resetAutoConfParameters(ctx, removedParameters); err != nil {
+			return postgresConfigurationChanged, err
+		}
+	}
+
+	return postgresConfigurationChanged || includedConfigurationChanged, nil
+}
+
+// installIncludedConfiguration writes the configuration files to be included
+// in the PostgreSQL configuration, removing the ones not included anymore.
+// This function will return "true" if any file has been changed
+func (instance *Instance) installIncludedConfiguration(
+	ctx context.Context,
+	includedConfiguration []postgres.IncludedConfiguration,
+) (bool, error) {
+	changed := false
+	installedFiles := stringset.New()
+	for _, included := range includedConfiguration {
+		fileChanged, err := InstallPgDataFileContent(ctx, instance.PgData, included.Content, included.FileName)
+		if err != nil {
+			return changed, err
+		}
+		changed = changed || fileChanged
+		installedFiles.Put(filepath.Base(included.FileName))
+	}
+
+	includedConfigurationDirectory := filepath.Join(instance.PgData, constants.PostgresqlIncludedConfigurationDirectory)
+	files, err := fileutils.GetDirectoryContent(includedConfigurationDirectory)
+	if os.IsNotExist(err) {
+		return changed, nil
+	}
+	if err != nil {
+		return changed, err
+	}
+
+	for _, file := range files {
+		if installedFiles.Has(file) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(includedConfigurationDirectory, file)); err != nil {
+			return changed, err
+		}
+		changed = true
+	}
+
+	return changed, nil
+}
+
+// getRemovedParameters returns the parameters which were set in the previous
+// configuration and are not set anymore in the current one
+func getRemovedParameters(previousConfiguration, currentConfiguration []string) []string {
+	currentParameters := stringset.From(
+		configfile.ReadOptionNamesFromConfigurationContents(currentConfiguration))
+
+	var result []string
+	for _, parameter := range configfile.ReadOptionNamesFromConfigurationContents(previousConfiguration) {
+		if !currentParameters.Has(parameter) {
+			result = append(result, parameter)
+		}
+	}
+
+	return result
+}
+
+// resetAutoConfParameters removes the passed parameters from the
+// `postgresql.auto.conf` file, just like `ALTER SYSTEM RESET` does.
+// A parameter that has been removed from the configuration would
+// otherwise keep the stale value that has been set with `ALTER SYSTEM`,
+// as `postgresql.auto.conf` is read after the other configuration files.
+// Returns a boolean indicating if any changes were done and any errors encountered
+func (instance *Instance) resetAutoConfParameters(ctx context.Context, parameters []string) (bool, error) {
+	if len(parameters) == 0 {
+		return false, nil
+	}
+
+	autoConfFile := filepath.Join(instance.PgData, "postgresql.auto.conf")
+	autoConfContent, err := fileutils.ReadFileLines(autoConfFile)
+	if err != nil {
+		return false, fmt.Errorf("error while reading postgresql.auto.conf file: %w", err)
+	}
+
+	staleParameters := configfile.ReadOptionNamesFromConfigurationContents(
+		configfile.ReadLinesFromConfigurationContents(autoConfContent, parameters...))
+	if len(staleParameters) == 0 {
+		return false, nil
+	}
+
+	if _, err := fileutils.WriteLinesToFile(autoConfFile,
+		configfile.RemoveOptionsFromConfigurationContents(
+			autoConfContent, staleParameters...),
+	); err != nil {
+		return false, fmt.Errorf("resetting removed parameters in postgresql.auto.conf file: %w", err)
+	}
+
+	log.FromContext(ctx).Info("Reset the parameters removed from the configuration",
+		"filename", "postgresql.auto.conf",
+		"parameters", staleParameters,
+	)
+
+	return true, nil
 }
 
 // GeneratePostgresqlHBA generates the pg_hba.conf content with the LDAP configuration if configured.
@@ -108,11 +231,74 @@ func (instance *Instance) GeneratePostgresqlHBA(cluster *apiv1.Cluster, ldapBind
 	}
 
 	return postgres.CreateHBARules(
-		cluster.Spec.PostgresConfiguration.PgHBA,
+		cluster.GetPgHBA(),
+		cluster.GetPgHBAPrepend(),
+		buildClientCertificateHBARules(cluster),
+		getReplicationAddresses(cluster),
 		defaultAuthenticationMethod,
 		buildLDAPConfigString(cluster, ldapBindPassword))
 }
 
+// clientCertificateIdentMap is the name of the pg_ident.conf map used
+// to authenticate the roles with their client certificates
+const clientCertificateIdentMap = "cnpg-client-certificates"
+
+// buildClientCertificateHBARules creates the pg_hba.conf rules allowing
+// the roles in the client certificate mappings to authenticate with
+// their certificate, one rule per role
+func buildClientCertificateHBARules(cluster *apiv1.Cluster) []string {
+	roles := stringset.New()
+	for _, mapping := range cluster.Spec.PostgresConfiguration.ClientCertificateMappings {
+		roles.Put(mapping.Role)
+	}
+
+	rules := make([]string, 0, roles.Len())
+	for _, role := range roles.ToSortedList() {
+		rules = append(rules, fmt.Sprintf("hostssl all %s all cert map=%s",
+			quoteHbaLiteral(role), clientCertificateIdentMap))
+	}
+	return rules
+}
+
+// buildClientCertificateIdentRules creates the pg_ident.conf lines mapping
+// the common names of the client certificates to PostgreSQL roles
+func buildClientCertificateIdentRules(cluster *apiv1.Cluster) []string {
+	if cluster == nil {
+		return nil
+	}
+
+	mappings := cluster.Spec.PostgresConfiguration.ClientCertificateMappings
+	rules := make([]string, 0, len(mappings))
+	for _, mapping := range mappings {
+		rules = append(rules, fmt.Sprintf("%s %s %s",
+			clientCertificateIdentMap, quoteHbaLiteral(mapping.CommonName), quoteHbaLiteral(mapping.Role)))
+	}
+	return rules
+}
+
+// getReplicationAddresses returns the addresses, in CIDR notation, from which
+// the streaming_replica user is allowed to connect. Nil means any address.
+func getReplicationAddresses(cluster *apiv1.Cluster) []string {
+	if cluster.Spec.Replication.GetHBAScope() != apiv1.ReplicationHBAScopeInstances {
+		return nil
+	}
+
+	addresses := make([]string, 0, len(cluster.Status.PodIPs))
+	for _, podIP := range cluster.Status.PodIPs {
+		ip := net.ParseIP(podIP)
+		switch {
+		case ip == nil:
+			continue
+		case ip.To4() != nil:
+			addresses = append(addresses, ip.String()+"/32")
+		default:
+			addresses = append(addresses, ip.String()+"/128")
+		}
+	}
+
+	return addresses
+}
+
 // RefreshPGHBA generates and writes down the pg_hba.conf file
 func (instance *Instance) RefreshPGHBA(ctx context.Context, cluster *apiv1.Cluster, ldapBindPassword string) (
 	postgresHBAChanged bool,
@@ -205,24 +391,30 @@ func quoteHbaLiteral(literal string) string {
 }
 
 // generatePostgresqlIdent generates the pg_ident.conf content given
-// a set of additional pg_ident lines that is usually taken from the
-// Cluster configuration
-func (instance *Instance) generatePostgresqlIdent(additionalLines []string) (string, error) {
+// the Cluster configuration. When the cluster is nil, only the
+// local map is generated
+func (instance *Instance) generatePostgresqlIdent(cluster *apiv1.Cluster) (string, error) {
+	var additionalLines []string
+	if cluster != nil {
+		additionalLines = cluster.Spec.PostgresConfiguration.PgIdent
+	}
+
 	return postgres.CreateIdentRules(
 		additionalLines,
+		buildClientCertificateIdentRules(cluster),
 		getCurrentUserOrDefaultToInsecureMapping(),
 	)
 }
 
 // RefreshPGIdent generates and writes down the pg_ident.conf file given
-// a set of additional pg_ident lines that is usually taken from the
-// Cluster configuration
+// the Cluster configuration. When the cluster is nil, only the local
+// map is generated
 func (instance *Instance) RefreshPGIdent(
 	ctx context.Context,
-	additionalLines []string,
+	cluster *apiv1.Cluster,
 ) (postgresIdentChanged bool, err error) {
 	// Generate pg_ident.conf file
-	pgIdentContent, err := instance.generatePostgresqlIdent(additionalLines)
+	pgIdentContent, err := instance.generatePostgresqlIdent(cluster)
 	if err != nil {
 		return false, nil
 	}
@@ -371,7 +563,11 @@ func (instance *Instance) migratePostgresAutoConfFile(ctx context.Context) (bool
 
 // createPostgresqlConfiguration creates the PostgreSQL configuration to be
 // used for this cluster and return it and its sha256 checksum
-func createPostgresqlConfiguration(cluster *apiv1.Cluster, preserveUserSettings bool) (string, string, error) {
+func createPostgresqlConfiguration(
+	cluster *apiv1.Cluster,
+	preserveUserSettings bool,
+	includedConfiguration []postgres.IncludedConfiguration,
+) (string, string, error) {
 	// Extract the PostgreSQL major version
 	fromVersion, err := cluster.GetPostgresqlVersion()
 	if err != nil {
@@ -388,6 +584,11 @@ func createPostgresqlConfiguration(cluster *apiv1.Cluster, preserveUserSettings
 		IsWalArchivingDisabled:           utils.IsWalArchivingDisabled(&cluster.ObjectMeta),
 		IsAlterSystemEnabled:             cluster.Spec.PostgresConfiguration.EnableAlterSystem,
 		SynchronousStandbyNames:          replication.GetSynchronousStandbyNames(cluster),
+		MinWalSenders:                    cluster.GetMinWalSenders(),
+		MinReplicationSlots:              cluster.GetMinReplicationSlots(),
+		SizeTierSettings:                 cluster.GetSizeTierParameters(),
+		WorkloadProfile:                  string(cluster.Spec.PostgresConfiguration.Profile),
+		IncludedConfiguration:            includedConfiguration,
 	}
 
 	if preserveUserSettings {
@@ -18,14 +18,20 @@ package postgres
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/cloudnative-pg/machinery/pkg/fileutils"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -166,19 +172,19 @@ var _ = Describe("Test building of the list of temporary tablespaces", func() {
 	}
 
 	It("doesn't set temp_tablespaces if there are no declared tablespaces", func() {
-		config, _, err := createPostgresqlConfiguration(&clusterWithoutTablespaces, true)
+		config, _, err := createPostgresqlConfiguration(&clusterWithoutTablespaces, true, nil)
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(config).ToNot(ContainSubstring("temp_tablespaces"))
 	})
 
 	It("doesn't set temp_tablespaces if there are no temporary tablespaces", func() {
-		config, _, err := createPostgresqlConfiguration(&clusterWithoutTemporaryTablespaces, true)
+		config, _, err := createPostgresqlConfiguration(&clusterWithoutTemporaryTablespaces, true, nil)
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(config).ToNot(ContainSubstring("temp_tablespaces"))
 	})
 
 	It("sets temp_tablespaces when there are temporary tablespaces", func() {
-		config, _, err := createPostgresqlConfiguration(&clusterWithTemporaryTablespaces, true)
+		config, _, err := createPostgresqlConfiguration(&clusterWithTemporaryTablespaces, true, nil)
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(config).To(ContainSubstring("temp_tablespaces = 'other_temporary_tablespace,temporary_tablespace'"))
 	})
@@ -233,7 +239,7 @@ var _ = Describe("recovery_min_apply_delay", func() {
 	It("do not set recovery_min_apply_delay in primary clusters", func() {
 		Expect(primaryCluster.IsReplica()).To(BeFalse())
 
-		config, _, err := createPostgresqlConfiguration(&primaryCluster, true)
+		config, _, err := createPostgresqlConfiguration(&primaryCluster, true, nil)
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(config).ToNot(ContainSubstring("recovery_min_apply_delay"))
 	})
@@ -241,7 +247,7 @@ var _ = Describe("recovery_min_apply_delay", func() {
 	It("set recovery_min_apply_delay in replica clusters when set", func() {
 		Expect(replicaCluster.IsReplica()).To(BeTrue())
 
-		config, _, err := createPostgresqlConfiguration(&replicaCluster, true)
+		config, _, err := createPostgresqlConfiguration(&replicaCluster, true, nil)
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(config).To(ContainSubstring("recovery_min_apply_delay = '3600s'"))
 	})
@@ -249,8 +255,268 @@ var _ = Describe("recovery_min_apply_delay", func() {
 	It("do not set recovery_min_apply_delay in replica clusters when not set", func() {
 		Expect(replicaClusterWithNoDelay.IsReplica()).To(BeTrue())
 
-		config, _, err := createPostgresqlConfiguration(&replicaClusterWithNoDelay, true)
+		config, _, err := createPostgresqlConfiguration(&replicaClusterWithNoDelay, true, nil)
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(config).ToNot(ContainSubstring("recovery_min_apply_delay"))
 	})
 })
+
+var _ = Describe("replication pg_hba rules scope", func() {
+	var cluster *apiv1.Cluster
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:16.4",
+				Replication: &apiv1.ReplicationConfiguration{
+					HBAScope: apiv1.ReplicationHBAScopeInstances,
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				PodIPs: []string{"10.244.0.10", "10.244.0.11", "fd00::12"},
+			},
+		}
+	})
+
+	It("allows any address when not scoped", func() {
+		cluster.Spec.Replication = nil
+		hba, err := (&Instance{}).GeneratePostgresqlHBA(cluster, "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hba).To(ContainSubstring("hostssl replication streaming_replica all cert\n"))
+	})
+
+	It("only allows the addresses of the pods of the cluster", func() {
+		hba, err := (&Instance{}).GeneratePostgresqlHBA(cluster, "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hba).ToNot(ContainSubstring("streaming_replica all"))
+		Expect(hba).To(ContainSubstring("hostssl replication streaming_replica 10.244.0.10/32 cert\n"))
+		Expect(hba).To(ContainSubstring("hostssl replication streaming_replica 10.244.0.11/32 cert\n"))
+		Expect(hba).To(ContainSubstring("hostssl replication streaming_replica fd00::12/128 cert\n"))
+	})
+
+	It("keeps streaming allowed when a pod is rescheduled with a new IP", func() {
+		By("moving the replica with IP 10.244.0.11 to a new IP", func() {
+			cluster.Status.PodIPs = []string{"10.244.0.10", "10.244.1.7", "fd00::12"}
+		})
+
+		hba, err := (&Instance{}).GeneratePostgresqlHBA(cluster, "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hba).To(ContainSubstring("hostssl replication streaming_replica 10.244.1.7/32 cert\n"))
+		Expect(hba).To(ContainSubstring("hostssl postgres streaming_replica 10.244.1.7/32 cert\n"))
+		Expect(hba).ToNot(ContainSubstring("10.244.0.11"))
+	})
+
+	It("ignores malformed addresses", func() {
+		cluster.Status.PodIPs = []string{"not-an-ip", "10.244.0.10"}
+		Expect(getReplicationAddresses(cluster)).To(Equal([]string{"10.244.0.10/32"}))
+	})
+})
+
+var _ = Describe("client certificate authentication", func() {
+	var cluster *apiv1.Cluster
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:16.4",
+				PostgresConfiguration: apiv1.PostgresConfiguration{
+					PgHBA: []string{"host all all 10.0.0.0/8 scram-sha-256"},
+					ClientCertificateMappings: []apiv1.ClientCertificateMapping{
+						{CommonName: "orders.svc", Role: "orders"},
+						{CommonName: "billing.svc", Role: "billing"},
+						{CommonName: "orders-batch.svc", Role: "orders"},
+					},
+				},
+			},
+		}
+	})
+
+	It("allows the mapped roles to authenticate with their certificate before the user rules", func() {
+		hba, err := (&Instance{}).GeneratePostgresqlHBA(cluster, "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hba).To(ContainSubstring(
+			"\nhostssl all \"billing\" all cert map=cnpg-client-certificates\n" +
+				"hostssl all \"orders\" all cert map=cnpg-client-certificates\n"))
+		Expect(strings.Index(hba, "map=cnpg-client-certificates")).To(
+			BeNumerically("<", strings.Index(hba, "host all all 10.0.0.0/8 scram-sha-256")))
+	})
+
+	It("maps the common names of the certificates to the roles", func() {
+		ident, err := (&Instance{}).generatePostgresqlIdent(cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ident).To(ContainSubstring("\ncnpg-client-certificates \"orders.svc\" \"orders\"\n"))
+		Expect(ident).To(ContainSubstring("\ncnpg-client-certificates \"billing.svc\" \"billing\"\n"))
+		Expect(ident).To(ContainSubstring("\ncnpg-client-certificates \"orders-batch.svc\" \"orders\"\n"))
+	})
+
+	It("doesn't add any rule without mappings", func() {
+		cluster.Spec.PostgresConfiguration.ClientCertificateMappings = nil
+
+		hba, err := (&Instance{}).GeneratePostgresqlHBA(cluster, "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hba).ToNot(ContainSubstring("CLIENT CERTIFICATE"))
+
+		ident, err := (&Instance{}).generatePostgresqlIdent(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ident).ToNot(ContainSubstring("CLIENT CERTIFICATE"))
+	})
+})
+
+var _ = Describe("resetting the removed parameters", func() {
+	var (
+		instance     *Instance
+		cluster      *apiv1.Cluster
+		autoConfFile string
+	)
+
+	BeforeEach(func() {
+		instance = &Instance{PgData: GinkgoT().TempDir()}
+		autoConfFile = filepath.Join(instance.PgData, "postgresql.auto.conf")
+		cluster = &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				PostgresConfiguration: apiv1.PostgresConfiguration{
+					Parameters: map[string]string{
+						"work_mem":                            "16MB",
+						"maintenance_work_mem":                "128MB",
+						"idle_in_transaction_session_timeout": "60s",
+					},
+				},
+			},
+		}
+	})
+
+	It("removes the stale values of the removed parameters from postgresql.auto.conf", func(ctx SpecContext) {
+		_, err := instance.RefreshConfigurationFilesFromCluster(ctx, cluster, false, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = fileutils.WriteLinesToFile(autoConfFile, []string{
+			"# Do not edit this file manually!",
+			"# It will be overwritten by the ALTER SYSTEM command.",
+			"work_mem = '64MB'",
+			"maintenance_work_mem = '256MB'",
+			"statement_timeout = '30s'",
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		delete(cluster.Spec.PostgresConfiguration.Parameters, "work_mem")
+		delete(cluster.Spec.PostgresConfiguration.Parameters, "idle_in_transaction_session_timeout")
+		changed, err := instance.RefreshConfigurationFilesFromCluster(ctx, cluster, false, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+
+		autoConfContent, err := fileutils.ReadFileLines(autoConfFile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(autoConfContent).To(Equal([]string{
+			"# Do not edit this file manually!",
+			"# It will be overwritten by the ALTER SYSTEM command.",
+			"maintenance_work_mem = '256MB'",
+			"statement_timeout = '30s'",
+		}))
+	})
+
+	It("doesn't touch postgresql.auto.conf when no parameter has been removed", func(ctx SpecContext) {
+		_, err := instance.RefreshConfigurationFilesFromCluster(ctx, cluster, false, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = fileutils.WriteLinesToFile(autoConfFile, []string{"work_mem = '64MB'"})
+		Expect(err).ToNot(HaveOccurred())
+		before, err := os.Stat(autoConfFile)
+		Expect(err).ToNot(HaveOccurred())
+
+		cluster.Spec.PostgresConfiguration.Parameters["work_mem"] = "32MB"
+		changed, err := instance.RefreshConfigurationFilesFromCluster(ctx, cluster, false, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+
+		after, err := os.Stat(autoConfFile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.SameFile(before, after)).To(BeTrue())
+	})
+})
+
+var _ = Describe("including configuration files", func() {
+	var (
+		instance *Instance
+		cluster  *apiv1.Cluster
+	)
+
+	// getEffectiveValue reads the value of a parameter like PostgreSQL does,
+	// following the include directives, with the last occurrence winning
+	var getEffectiveValue func(fileName string, parameter string) string
+	getEffectiveValue = func(fileName string, parameter string) string {
+		lines, err := fileutils.ReadFileLines(filepath.Join(instance.PgData, fileName))
+		Expect(err).ToNot(HaveOccurred())
+
+		var result string
+		for _, line := range lines {
+			if includedFile, ok := strings.CutPrefix(line, "include "); ok {
+				if value := getEffectiveValue(strings.Trim(includedFile, "'"), parameter); value != "" {
+					result = value
+				}
+				continue
+			}
+			if name, value, ok := strings.Cut(line, "="); ok && strings.TrimSpace(name) == parameter {
+				result = strings.Trim(strings.TrimSpace(value), "'")
+			}
+		}
+		return result
+	}
+
+	BeforeEach(func() {
+		instance = &Instance{PgData: GinkgoT().TempDir()}
+		cluster = &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				PostgresConfiguration: apiv1.PostgresConfiguration{
+					Parameters: map[string]string{
+						"work_mem": "16MB",
+					},
+				},
+			},
+		}
+	})
+
+	included := []postgres.IncludedConfiguration{
+		{
+			FileName: filepath.Join(constants.PostgresqlIncludedConfigurationDirectory, "0-shared.conf"),
+			Content:  "max_connections = 500\nwal_level = 'replica'\nwork_mem = '8MB'\n",
+		},
+	}
+
+	It("applies the settings of the included files", func(ctx SpecContext) {
+		changed, err := instance.RefreshConfigurationFilesFromCluster(ctx, cluster, false, included)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+
+		customConf := constants.PostgresqlCustomConfigurationFile
+		Expect(getEffectiveValue(customConf, "max_connections")).To(Equal("500"))
+		Expect(getEffectiveValue(customConf, "wal_level")).To(Equal("replica"))
+		// The parameters set in the cluster take precedence
+		Expect(getEffectiveValue(customConf, "work_mem")).To(Equal("16MB"))
+	})
+
+	It("reloads the configuration when the included files change", func(ctx SpecContext) {
+		_, err := instance.RefreshConfigurationFilesFromCluster(ctx, cluster, false, included)
+		Expect(err).ToNot(HaveOccurred())
+		sha256 := instance.ConfigSha256
+
+		changedIncluded := []postgres.IncludedConfiguration{
+			{FileName: included[0].FileName, Content: "max_connections = 200\n"},
+		}
+		changed, err := instance.RefreshConfigurationFilesFromCluster(ctx, cluster, false, changedIncluded)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(instance.ConfigSha256).ToNot(Equal(sha256))
+		Expect(getEffectiveValue(constants.PostgresqlCustomConfigurationFile, "max_connections")).To(Equal("200"))
+	})
+
+	It("removes the files not included anymore", func(ctx SpecContext) {
+		_, err := instance.RefreshConfigurationFilesFromCluster(ctx, cluster, false, included)
+		Expect(err).ToNot(HaveOccurred())
+
+		changed, err := instance.RefreshConfigurationFilesFromCluster(ctx, cluster, false, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(filepath.Join(instance.PgData, included[0].FileName)).ToNot(BeAnExistingFile())
+		Expect(getEffectiveValue(constants.PostgresqlCustomConfigurationFile, "max_connections")).To(BeEmpty())
+	})
+})
@@ -29,6 +29,11 @@ const (
 	// contain HA and DR settings)
 	PostgresqlOverrideConfigurationFile = "override.conf"
 
+	// PostgresqlIncludedConfigurationDirectory is the name of the directory
+	// containing the PostgreSQL configuration files provided by the user
+	// and included in the one managed by the operator
+	PostgresqlIncludedConfigurationDirectory = "included.conf.d"
+
 	// PostgresqlHBARulesFile is the name of the file which contains
 	// the host-based access rules
 	PostgresqlHBARulesFile = "pg_hba.conf"
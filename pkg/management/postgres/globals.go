@@ -0,0 +1,144 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+
+	barmanCommand "github.com/cloudnative-pg/barman-cloud/pkg/command"
+	"github.com/cloudnative-pg/machinery/pkg/execlog"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// GlobalsExportFile is the name of the file, in the backup temporary
+// directory, where the global objects are exported before being uploaded
+const GlobalsExportFile = "cnpg_globals.sql"
+
+// globalsObjectDirectory is the directory, inside the one of the server
+// in the object store, where the exports of the global objects are stored
+const globalsObjectDirectory = "globals"
+
+// uploadGlobalsScript uploads a file to the object store with the cloud
+// interface of barman-cloud. It's invoked with the key of the object
+// followed by the options of barman-cloud-wal-archive, so that the
+// credentials and the encryption are handled in the same way
+const uploadGlobalsScript = `
+import os
+import sys
+from contextlib import closing
+
+from barman.clients.cloud_walarchive import parse_arguments
+from barman.cloud_providers import get_cloud_interface
+
+key = sys.argv[1]
+config = parse_arguments(sys.argv[2:])
+cloud_interface = get_cloud_interface(config)
+with closing(cloud_interface), open(config.wal_path, "rb") as source:
+    cloud_interface.upload_fileobj(source, os.path.join(cloud_interface.path, config.server_name, key))
+`
+
+var (
+	// pgDumpAllName is the name of the executable used to export the
+	// global objects
+	pgDumpAllName = "pg_dumpall"
+
+	// pythonName is the name of the Python interpreter barman-cloud
+	// is installed with
+	pythonName = "python3"
+)
+
+// ExportGlobals exports the global objects of this instance, i.e. the roles,
+// the tablespaces and the grants on them, in the passed file using
+// `pg_dumpall --globals-only`. As the export contains the password hashes
+// of the roles, the file is only readable by the owner
+func (instance *Instance) ExportGlobals(destination string) error {
+	// A previous export is removed, not truncated, to never reuse its mode
+	if err := os.Remove(destination); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("while removing the previous export of the global objects: %w", err)
+	}
+
+	// #nosec G304
+	output, err := os.OpenFile(destination, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("while creating the export of the global objects: %w", err)
+	}
+	defer func() {
+		_ = output.Close()
+	}()
+
+	options := []string{
+		"--globals-only",
+		"--no-password",
+		"--dbname", fmt.Sprintf(
+			"host=%s port=%v user=postgres sslmode=disable application_name=cnpg-globals-export",
+			GetSocketDir(),
+			GetServerPort()),
+	}
+
+	var stderr bytes.Buffer
+	pgDumpAllCmd := exec.Command(pgDumpAllName, options...) // #nosec
+	pgDumpAllCmd.Stdout = output
+	pgDumpAllCmd.Stderr = &stderr
+	if err := pgDumpAllCmd.Run(); err != nil {
+		return fmt.Errorf("while exporting the global objects: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return output.Sync()
+}
+
+// UploadGlobals uploads the passed export of the global objects to the
+// object store, in its own object named after the backup, returning the
+// location of the uploaded object
+func UploadGlobals(
+	ctx context.Context,
+	objectStore *apiv1.BarmanObjectStoreConfiguration,
+	serverName string,
+	backupName string,
+	source string,
+	env []string,
+) (string, error) {
+	key := path.Join(globalsObjectDirectory, backupName+".sql")
+
+	options := []string{key}
+	if objectStore.Data != nil && objectStore.Data.Encryption != "" {
+		options = append(options, "-e", string(objectStore.Data.Encryption))
+	}
+	if objectStore.EndpointURL != "" {
+		options = append(options, "--endpoint-url", objectStore.EndpointURL)
+	}
+	options, err := barmanCommand.AppendCloudProviderOptionsFromConfiguration(ctx, options, objectStore)
+	if err != nil {
+		return "", err
+	}
+	options = append(options, objectStore.DestinationPath, serverName, source)
+
+	uploadCmd := exec.Command(pythonName, append([]string{"-c", uploadGlobalsScript}, options...)...) // #nosec
+	uploadCmd.Env = env
+	if err := execlog.RunStreaming(uploadCmd, "upload-globals"); err != nil {
+		return "", fmt.Errorf("while uploading the export of the global objects: %w", err)
+	}
+
+	return strings.TrimSuffix(objectStore.DestinationPath, "/") + "/" + path.Join(serverName, key), nil
+}
@@ -0,0 +1,166 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	barmanApi "github.com/cloudnative-pg/barman-cloud/pkg/api"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakePgDumpAll is a pg_dumpall replacement writing the global objects
+// in the file passed with `--file`, and failing if `--globals-only`
+// is not requested
+const fakePgDumpAll = `#!/bin/sh
+for arg; do
+  [ "$arg" = "--globals-only" ] && globals=1
+done
+[ -n "$globals" ] || exit 1
+cat <<END
+CREATE ROLE app;
+ALTER ROLE app WITH NOSUPERUSER INHERIT NOCREATEROLE NOCREATEDB LOGIN NOREPLICATION NOBYPASSRLS;
+CREATE TABLESPACE analytics OWNER app LOCATION '/var/lib/postgresql/tablespaces/analytics/data';
+END
+`
+
+// fakePython is a python3 replacement recording the arguments passed to
+// the upload script, and copying the uploaded file, passed last, next to
+// the recorded arguments
+const fakePython = `#!/bin/sh
+dir=$(dirname "$0")
+shift 2
+printf '%s\n' "$@" > "$dir/arguments"
+for file; do :; done
+cp "$file" "$dir/uploaded"
+`
+
+var _ = Describe("exporting the global objects", func() {
+	var (
+		instance    *Instance
+		destination string
+	)
+
+	setPgDumpAll := func(script string) {
+		executable := filepath.Join(GinkgoT().TempDir(), "pg_dumpall")
+		Expect(os.WriteFile(executable, []byte(script), 0o700)).To(Succeed()) // #nosec
+		pgDumpAllName = executable
+	}
+
+	BeforeEach(func() {
+		instance = &Instance{PgData: GinkgoT().TempDir()}
+		destination = filepath.Join(GinkgoT().TempDir(), GlobalsExportFile)
+		DeferCleanup(func() {
+			pgDumpAllName = "pg_dumpall"
+		})
+	})
+
+	It("produces the dump of the global objects outside of the data directory", func() {
+		setPgDumpAll(fakePgDumpAll)
+		Expect(instance.ExportGlobals(destination)).To(Succeed())
+
+		content, err := os.ReadFile(destination) // #nosec
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring("CREATE ROLE app;"))
+		Expect(string(content)).To(ContainSubstring("ALTER ROLE app WITH NOSUPERUSER"))
+		Expect(string(content)).To(ContainSubstring("CREATE TABLESPACE analytics OWNER app"))
+
+		entries, err := os.ReadDir(instance.PgData)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entries).To(BeEmpty())
+	})
+
+	It("makes the dump readable only by its owner", func() {
+		Expect(os.WriteFile(destination, []byte("CREATE ROLE old;"), 0o644)).To(Succeed()) // #nosec
+
+		setPgDumpAll(fakePgDumpAll)
+		Expect(instance.ExportGlobals(destination)).To(Succeed())
+
+		info, err := os.Stat(destination)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0o600)))
+
+		content, err := os.ReadFile(destination) // #nosec
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).ToNot(ContainSubstring("CREATE ROLE old;"))
+	})
+
+	It("reports the errors of pg_dumpall", func() {
+		setPgDumpAll("#!/bin/sh\necho 'connection refused' >&2\nexit 1\n")
+		err := instance.ExportGlobals(destination)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("connection refused"))
+	})
+})
+
+var _ = Describe("uploading the global objects", func() {
+	var binDir string
+
+	BeforeEach(func() {
+		binDir = GinkgoT().TempDir()
+		executable := filepath.Join(binDir, "python3")
+		Expect(os.WriteFile(executable, []byte(fakePython), 0o700)).To(Succeed()) // #nosec
+		pythonName = executable
+		DeferCleanup(func() {
+			pythonName = "python3"
+		})
+	})
+
+	It("uploads the export in its own object named after the backup", func(ctx SpecContext) {
+		source := filepath.Join(GinkgoT().TempDir(), GlobalsExportFile)
+		Expect(os.WriteFile(source, []byte("CREATE ROLE app;"), 0o600)).To(Succeed())
+
+		objectStore := &apiv1.BarmanObjectStoreConfiguration{
+			DestinationPath: "s3://backups/",
+			EndpointURL:     "https://minio:9000",
+			Data: &barmanApi.DataBackupConfiguration{
+				Encryption: barmanApi.EncryptionTypeAES256,
+			},
+		}
+		location, err := UploadGlobals(ctx, objectStore, "cluster-example", "backup-example", source, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(location).To(Equal("s3://backups/cluster-example/globals/backup-example.sql"))
+
+		arguments, err := os.ReadFile(filepath.Join(binDir, "arguments")) // #nosec
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strings.Split(strings.TrimSpace(string(arguments)), "\n")).To(Equal([]string{
+			"globals/backup-example.sql",
+			"-e", "AES256",
+			"--endpoint-url", "https://minio:9000",
+			"s3://backups/",
+			"cluster-example",
+			source,
+		}))
+
+		uploaded, err := os.ReadFile(filepath.Join(binDir, "uploaded")) // #nosec
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(uploaded)).To(Equal("CREATE ROLE app;"))
+	})
+
+	It("reports the failed uploads", func(ctx SpecContext) {
+		Expect(os.WriteFile(pythonName, []byte("#!/bin/sh\nexit 1\n"), 0o700)).To(Succeed()) // #nosec
+		_, err := UploadGlobals(ctx, &apiv1.BarmanObjectStoreConfiguration{DestinationPath: "s3://backups"},
+			"cluster-example", "backup-example", GlobalsExportFile, nil)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -169,6 +169,11 @@ type Instance struct {
 	// InstanceManagerIsUpgrading tells if there is an instance manager upgrade in process
 	InstanceManagerIsUpgrading atomic.Bool
 
+	// FastShutdownRequested tells that the smart shutdown must be skipped
+	// when PostgreSQL is stopped, i.e. because the primary is being stopped
+	// with no replica to switch over to
+	FastShutdownRequested atomic.Bool
+
 	// PgRewindIsRunning tells if there is a `pg_rewind` process running
 	PgRewindIsRunning bool
 
@@ -178,6 +183,10 @@ type Instance struct {
 	// SmartStopDelay is used to control PostgreSQL smart shutdown timeout
 	SmartStopDelay int32
 
+	// FencingGracePeriod is the timeout of the smart shutdown requested
+	// when the instance is fenced, zero meaning a fast shutdown
+	FencingGracePeriod int32
+
 	// RequiresDesignatedPrimaryTransition indicates if this instance is a primary that needs to become
 	// a designatedPrimary
 	RequiresDesignatedPrimaryTransition bool
@@ -203,6 +212,15 @@ type Instance struct {
 	// tablespaceSynchronizerChan is used to send tablespace configuration to the tablespace synchronizer
 	tablespaceSynchronizerChan chan map[string]apiv1.TablespaceConfiguration
 
+	// integrityCheckerChan is used to send the integrity check configuration to the integrity checker
+	integrityCheckerChan chan *apiv1.IntegrityCheckConfiguration
+
+	// repackerChan is used to send the pg_repack configuration to the repacker
+	repackerChan chan *apiv1.RepackConfiguration
+
+	// archiveVerifierChan is used to send the backup configuration to the archive verifier
+	archiveVerifierChan chan *apiv1.BackupConfiguration
+
 	// StatusPortTLS enables TLS on the status port used to communicate with the operator
 	StatusPortTLS bool
 
@@ -307,6 +325,42 @@ func (instance *Instance) RoleSynchronizerChan() <-chan *apiv1.ManagedConfigurat
 	return instance.roleSynchronizerChan
 }
 
+// ConfigureIntegrityChecker sends the configuration to the integrity checker
+func (instance *Instance) ConfigureIntegrityChecker(config *apiv1.IntegrityCheckConfiguration) {
+	go func() {
+		instance.integrityCheckerChan <- config
+	}()
+}
+
+// IntegrityCheckerChan returns the communication channel to the integrity checker
+func (instance *Instance) IntegrityCheckerChan() <-chan *apiv1.IntegrityCheckConfiguration {
+	return instance.integrityCheckerChan
+}
+
+// ConfigureRepacker sends the configuration to the repacker
+func (instance *Instance) ConfigureRepacker(config *apiv1.RepackConfiguration) {
+	go func() {
+		instance.repackerChan <- config
+	}()
+}
+
+// RepackerChan returns the communication channel to the repacker
+func (instance *Instance) RepackerChan() <-chan *apiv1.RepackConfiguration {
+	return instance.repackerChan
+}
+
+// ConfigureArchiveVerifier sends the backup configuration to the archive verifier
+func (instance *Instance) ConfigureArchiveVerifier(config *apiv1.BackupConfiguration) {
+	go func() {
+		instance.archiveVerifierChan <- config
+	}()
+}
+
+// ArchiveVerifierChan returns the communication channel to the archive verifier
+func (instance *Instance) ArchiveVerifierChan() <-chan *apiv1.BackupConfiguration {
+	return instance.archiveVerifierChan
+}
+
 // TriggerTablespaceSynchronizer sends the configuration to the tablespace synchronizer
 func (instance *Instance) TriggerTablespaceSynchronizer(config map[string]apiv1.TablespaceConfiguration) {
 	go func() {
@@ -364,6 +418,9 @@ func NewInstance() *Instance {
 		slotsReplicatorChan:        make(chan *apiv1.ReplicationSlotsConfiguration),
 		roleSynchronizerChan:       make(chan *apiv1.ManagedConfiguration),
 		tablespaceSynchronizerChan: make(chan map[string]apiv1.TablespaceConfiguration),
+		integrityCheckerChan:       make(chan *apiv1.IntegrityCheckConfiguration),
+		repackerChan:               make(chan *apiv1.RepackConfiguration),
+		archiveVerifierChan:        make(chan *apiv1.BackupConfiguration),
 	}
 }
 
@@ -534,6 +591,9 @@ func (instance *Instance) TryShuttingDownSmartFast(ctx context.Context) error {
 			"maxStopDelay", instance.MaxStopDelay,
 		)
 		smartTimeout = 0
+	} else if instance.FastShutdownRequested.Load() {
+		contextLogger.Info("Skipping the smart shutdown, a fast shutdown has been requested")
+		smartTimeout = 0
 	}
 
 	if smartTimeout > 0 {
@@ -599,6 +659,34 @@ func (instance *Instance) TryShuttingDownFastImmediate(ctx context.Context) erro
 	return err
 }
 
+// tryShuttingDownForFencing shuts down the instance being fenced. When a
+// fencing grace period is configured, a smart shutdown lets the active
+// sessions complete their work within that period, after which the
+// instance is stopped with TryShuttingDownFastImmediate.
+func (instance *Instance) tryShuttingDownForFencing(ctx context.Context) error {
+	contextLogger := log.FromContext(ctx)
+
+	if gracePeriod := instance.FencingGracePeriod; gracePeriod > 0 {
+		contextLogger.Info("Requesting smart shutdown of the PostgreSQL instance to fence it",
+			"fencingGracePeriod", gracePeriod)
+		err := instance.Shutdown(
+			ctx,
+			shutdownOptions{
+				Mode:    shutdownModeSmart,
+				Wait:    true,
+				Timeout: &gracePeriod,
+			},
+		)
+		if err == nil {
+			return nil
+		}
+		contextLogger.Info("The active sessions didn't end within the fencing grace period",
+			"fencingGracePeriod", gracePeriod, "err", err)
+	}
+
+	return instance.TryShuttingDownFastImmediate(ctx)
+}
+
 // isStatusRunning checks the status of a running server using pg_ctl status
 func (instance *Instance) isStatusRunning() bool {
 	options := []string{
@@ -724,6 +812,11 @@ func (instance *Instance) GetSuperUserDB() (*sql.DB, error) {
 	return instance.ConnectionPool().Connection("postgres")
 }
 
+// GetNamedDB gets a connection to the passed database on this instance
+func (instance *Instance) GetNamedDB(name string) (*sql.DB, error) {
+	return instance.ConnectionPool().Connection(name)
+}
+
 // GetTemplateDB gets a connection to the "template1" database on this instance
 func (instance *Instance) GetTemplateDB() (*sql.DB, error) {
 	return instance.ConnectionPool().Connection("template1")
@@ -1308,7 +1401,7 @@ func (instance *Instance) HandleInstanceCommandRequests(
 	case fenceOn:
 		contextLogger.Info("Fencing request received, will proceed shutting down the instance")
 		instance.SetFencing(true)
-		if err := instance.TryShuttingDownFastImmediate(ctx); err != nil {
+		if err := instance.tryShuttingDownForFencing(ctx); err != nil {
 			return false, fmt.Errorf("while shutting down the instance to fence it: %w", err)
 		}
 		return false, nil
@@ -24,6 +24,7 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/external"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
 )
 
 // RefreshReplicaConfiguration writes the PostgreSQL correct
@@ -80,5 +81,58 @@ func (instance *Instance) writeReplicaConfigurationForDesignatedPrimary(
 		return false, err
 	}
 
-	return UpdateReplicaConfiguration(instance.PgData, connectionString, "")
+	slotName := cluster.GetSourceSlotName()
+	if slotName != "" {
+		if err := ensureSourceReplicationSlot(ctx, connectionString, slotName); err != nil {
+			return false, err
+		}
+	}
+
+	return UpdateReplicaConfiguration(instance.PgData, connectionString, slotName)
+}
+
+// ensureSourceReplicationSlot creates the physical replication slot used by the
+// designated primary on the source server, if it doesn't exist yet
+func ensureSourceReplicationSlot(ctx context.Context, connectionString, slotName string) error {
+	db, err := pool.NewDBConnection(connectionString, pool.ConnectionProfilePostgresql)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	if _, err := db.ExecContext(
+		ctx,
+		`SELECT pg_catalog.pg_create_physical_replication_slot($1, true)
+		WHERE NOT EXISTS (SELECT 1 FROM pg_catalog.pg_replication_slots WHERE slot_name = $1)`,
+		slotName,
+	); err != nil {
+		return fmt.Errorf("while creating the replication slot %q on the source: %w", slotName, err)
+	}
+
+	return nil
+}
+
+// DropSourceReplicationSlot drops the physical replication slot used by the
+// designated primary from the source server, if it exists
+func DropSourceReplicationSlot(ctx context.Context, connectionString, slotName string) error {
+	db, err := pool.NewDBConnection(connectionString, pool.ConnectionProfilePostgresql)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	if _, err := db.ExecContext(
+		ctx,
+		`SELECT pg_catalog.pg_drop_replication_slot(slot_name)
+		FROM pg_catalog.pg_replication_slots WHERE slot_name = $1`,
+		slotName,
+	); err != nil {
+		return fmt.Errorf("while dropping the replication slot %q from the source: %w", slotName, err)
+	}
+
+	return nil
 }
@@ -0,0 +1,119 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("replica cluster cascading replication", func() {
+	const clusterName = "cluster-replica"
+
+	var (
+		cluster   *apiv1.Cluster
+		instances map[string]*Instance
+	)
+
+	readPrimaryConnInfo := func(instance *Instance) string {
+		content, err := os.ReadFile(filepath.Join(instance.PgData, constants.PostgresqlOverrideConfigurationFile))
+		Expect(err).ToNot(HaveOccurred())
+		for _, line := range strings.Split(string(content), "\n") {
+			if value, found := strings.CutPrefix(line, "primary_conninfo = "); found {
+				return value
+			}
+		}
+		return ""
+	}
+
+	refreshAll := func(ctx SpecContext) {
+		for _, instance := range instances {
+			_, err := instance.RefreshReplicaConfiguration(ctx, cluster, nil)
+			Expect(err).ToNot(HaveOccurred())
+		}
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: clusterName, Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				Instances: 3,
+				ReplicaCluster: &apiv1.ReplicaClusterConfiguration{
+					Enabled: ptr.To(true),
+					Source:  "origin",
+				},
+				ExternalClusters: []apiv1.ExternalCluster{
+					{
+						Name: "origin",
+						ConnectionParameters: map[string]string{
+							"host":   "origin-rw",
+							"user":   "streaming_replica",
+							"dbname": "postgres",
+						},
+					},
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: clusterName + "-1",
+				TargetPrimary:  clusterName + "-1",
+			},
+		}
+
+		// Every instance of a replica cluster is in continuous recovery
+		instances = make(map[string]*Instance)
+		for _, podName := range []string{clusterName + "-1", clusterName + "-2", clusterName + "-3"} {
+			instance := NewInstance().WithClusterName(clusterName).WithNamespace("default").WithPodName(podName)
+			instance.PgData = GinkgoT().TempDir()
+			Expect(createStandbySignal(instance.PgData)).To(Succeed())
+			instances[podName] = instance
+		}
+	})
+
+	It("connects only the designated primary to the source", func(ctx SpecContext) {
+		refreshAll(ctx)
+
+		Expect(readPrimaryConnInfo(instances[clusterName+"-1"])).To(ContainSubstring("origin-rw"))
+		for _, podName := range []string{clusterName + "-2", clusterName + "-3"} {
+			connInfo := readPrimaryConnInfo(instances[podName])
+			Expect(connInfo).To(ContainSubstring("host=" + clusterName + "-rw"))
+			Expect(connInfo).To(ContainSubstring("application_name=" + podName))
+			Expect(connInfo).ToNot(ContainSubstring("origin-rw"))
+		}
+	})
+
+	It("reconfigures the cascading replicas when the designated primary changes", func(ctx SpecContext) {
+		refreshAll(ctx)
+
+		cluster.Status.TargetPrimary = clusterName + "-2"
+		refreshAll(ctx)
+
+		Expect(readPrimaryConnInfo(instances[clusterName+"-2"])).To(ContainSubstring("origin-rw"))
+		for _, podName := range []string{clusterName + "-1", clusterName + "-3"} {
+			Expect(readPrimaryConnInfo(instances[podName])).To(ContainSubstring("host=" + clusterName + "-rw"))
+		}
+	})
+})
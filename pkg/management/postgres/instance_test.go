@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/cloudnative-pg/machinery/pkg/fileutils"
 
@@ -263,3 +264,77 @@ var _ = Describe("ALTER SYSTEM enable and disable in PostgreSQL <17", func() {
 		Expect(info.Mode()).To(BeEquivalentTo(0o400))
 	})
 })
+
+// fakePgCtl is a pg_ctl replacement logging the requested shutdown modes.
+// A smart shutdown waits, up to the requested timeout, for the
+// `active-transaction` file in the data directory to be removed
+const fakePgCtl = `#!/bin/sh
+while [ $# -gt 0 ]; do
+  case "$1" in
+    -D) shift; pgdata="$1" ;;
+    -m) shift; mode="$1" ;;
+    -t) shift; timeout="$1" ;;
+    status) exit 0 ;;
+  esac
+  shift
+done
+echo "$mode" >> "$pgdata/pg_ctl.log"
+if [ "$mode" = smart ]; then
+  waited=0
+  while [ -e "$pgdata/active-transaction" ]; do
+    [ "$waited" -ge "$timeout" ] && exit 1
+    sleep 1
+    waited=$((waited + 1))
+  done
+fi
+exit 0
+`
+
+var _ = Describe("shutting down a fenced instance", func() {
+	var (
+		instance              *Instance
+		activeTransactionFile string
+	)
+
+	BeforeEach(func() {
+		binDir := GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(binDir, "pg_ctl"), []byte(fakePgCtl), 0o700)).To(Succeed()) // #nosec
+		GinkgoT().Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+		instance = &Instance{PgData: GinkgoT().TempDir(), MaxSwitchoverDelay: 10}
+		activeTransactionFile = filepath.Join(instance.PgData, "active-transaction")
+		Expect(os.WriteFile(activeTransactionFile, nil, 0o600)).To(Succeed())
+	})
+
+	shutdownModes := func() string {
+		content, err := os.ReadFile(filepath.Join(instance.PgData, "pg_ctl.log")) // #nosec
+		Expect(err).ToNot(HaveOccurred())
+		return string(content)
+	}
+
+	It("lets an active transaction complete within the grace period", func(ctx SpecContext) {
+		instance.FencingGracePeriod = 10
+		go func() {
+			defer GinkgoRecover()
+			time.Sleep(time.Second)
+			Expect(os.Remove(activeTransactionFile)).To(Succeed())
+		}()
+
+		Expect(instance.tryShuttingDownForFencing(ctx)).To(Succeed())
+		Expect(activeTransactionFile).ToNot(BeAnExistingFile())
+		Expect(shutdownModes()).To(Equal("smart\n"))
+	})
+
+	It("stops the instance with a fast shutdown when the grace period expires", func(ctx SpecContext) {
+		instance.FencingGracePeriod = 1
+
+		Expect(instance.tryShuttingDownForFencing(ctx)).To(Succeed())
+		Expect(activeTransactionFile).To(BeAnExistingFile())
+		Expect(shutdownModes()).To(Equal("smart\nfast\n"))
+	})
+
+	It("requests a fast shutdown without a grace period", func(ctx SpecContext) {
+		Expect(instance.tryShuttingDownForFencing(ctx)).To(Succeed())
+		Expect(shutdownModes()).To(Equal("fast\n"))
+	})
+})
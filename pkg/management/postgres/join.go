@@ -33,8 +33,12 @@ import (
 )
 
 // ClonePgData clones an existing server, given its connection string,
-// to a certain data directory
-func ClonePgData(ctx context.Context, connectionString, targetPgData, walDir string) error {
+// to a certain data directory, passing any additional option to pg_basebackup
+func ClonePgData(
+	ctx context.Context,
+	connectionString, targetPgData, walDir string,
+	extraOptions ...string,
+) error {
 	log.Info("Waiting for server to be available", "connectionString", connectionString)
 
 	db, err := pool.NewDBConnection(connectionString, pool.ConnectionProfilePostgresqlPhysicalReplication)
@@ -60,6 +64,7 @@ func ClonePgData(ctx context.Context, connectionString, targetPgData, walDir str
 	if walDir != "" {
 		options = append(options, "--waldir", walDir)
 	}
+	options = append(options, extraOptions...)
 
 	pgBaseBackupCmd := exec.Command(pgBaseBackupName, options...) // #nosec
 	err = execlog.RunStreaming(pgBaseBackupCmd, pgBaseBackupName)
@@ -92,7 +97,13 @@ func (info InitInfo) Join(ctx context.Context, cluster *apiv1.Cluster) error {
 		return err
 	}
 
-	if err = ClonePgData(ctx, primaryConnInfo, info.PgData, info.PgWal); err != nil {
+	if err = ClonePgData(
+		ctx,
+		primaryConnInfo,
+		info.PgData,
+		info.PgWal,
+		getReplicaPgBaseBackupOptions(cluster)...,
+	); err != nil {
 		return err
 	}
 
@@ -100,3 +111,22 @@ func (info InitInfo) Join(ctx context.Context, cluster *apiv1.Cluster) error {
 	_, err = UpdateReplicaConfiguration(info.PgData, info.GetPrimaryConnInfo(), slotName)
 	return err
 }
+
+// getReplicaPgBaseBackupOptions returns the pg_basebackup options
+// requested by the user to clone a new replica from the primary
+func getReplicaPgBaseBackupOptions(cluster *apiv1.Cluster) []string {
+	if cluster.Spec.Replication == nil || cluster.Spec.Replication.PgBaseBackup == nil {
+		return nil
+	}
+
+	var options []string
+	pgBaseBackupOptions := cluster.Spec.Replication.PgBaseBackup
+	if pgBaseBackupOptions.Checkpoint != "" {
+		options = append(options, "--checkpoint", string(pgBaseBackupOptions.Checkpoint))
+	}
+	if pgBaseBackupOptions.MaxRate != "" {
+		options = append(options, "--max-rate", pgBaseBackupOptions.MaxRate)
+	}
+
+	return options
+}
@@ -0,0 +1,63 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("replica pg_basebackup options", func() {
+	It("doesn't add any option when no replication configuration is set", func() {
+		cluster := &apiv1.Cluster{}
+		Expect(getReplicaPgBaseBackupOptions(cluster)).To(BeEmpty())
+	})
+
+	It("passes the checkpoint mode and the maximum transfer rate", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Replication: &apiv1.ReplicationConfiguration{
+					PgBaseBackup: &apiv1.PgBaseBackupOptions{
+						Checkpoint: apiv1.CheckpointModeFast,
+						MaxRate:    "100M",
+					},
+				},
+			},
+		}
+		Expect(getReplicaPgBaseBackupOptions(cluster)).To(Equal([]string{
+			"--checkpoint", "fast",
+			"--max-rate", "100M",
+		}))
+	})
+
+	It("only passes the options that have been set", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Replication: &apiv1.ReplicationConfiguration{
+					PgBaseBackup: &apiv1.PgBaseBackupOptions{
+						MaxRate: "512k",
+					},
+				},
+			},
+		}
+		Expect(getReplicaPgBaseBackupOptions(cluster)).To(Equal([]string{
+			"--max-rate", "512k",
+		}))
+	})
+})
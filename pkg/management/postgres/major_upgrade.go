@@ -0,0 +1,458 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/cloudnative-pg/machinery/pkg/execlog"
+	"github.com/cloudnative-pg/machinery/pkg/fileutils"
+	"github.com/cloudnative-pg/machinery/pkg/log"
+
+	postgresutils "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/utils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	// oldBinDirFileName is the name of the file, in the directory where
+	// the previous PostgreSQL installation is copied, containing the
+	// location of its binaries
+	oldBinDirFileName = "bindir"
+
+	// newDataDirectorySuffix is appended to the name of the data and WAL
+	// directories to get the ones created by the major upgrade
+	newDataDirectorySuffix = "-new"
+
+	// oldDataDirectorySuffix is appended to the name of the data and WAL
+	// directories being replaced by the upgraded ones
+	oldDataDirectorySuffix = "-old"
+
+	// swapMarkerFileSuffix is appended to the name of the data directory
+	// to get the file marking that the upgraded directories have been
+	// validated and are being moved in place of the existing ones
+	swapMarkerFileSuffix = "-upgraded"
+)
+
+// pgUpgradeName is the name of the executable used to upgrade the data
+// directory to a newer major version
+var pgUpgradeName = "pg_upgrade"
+
+// pgConfigName is the name of the executable reporting the location of
+// the PostgreSQL installation
+var pgConfigName = "pg_config"
+
+// CopyPostgresInstallation copies the binaries, the libraries and the shared
+// files of the PostgreSQL installation of the running image inside the
+// destination directory. The original paths are preserved, as PostgreSQL
+// locates its files relatively to the binaries, and the location of the
+// copied binaries is recorded for MajorUpgradeInfo.Upgrade
+func CopyPostgresInstallation(ctx context.Context, destination string) error {
+	contextLogger := log.FromContext(ctx)
+
+	out, err := exec.Command(pgConfigName, "--bindir", "--pkglibdir", "--sharedir").Output() // #nosec
+	if err != nil {
+		return fmt.Errorf("while detecting the PostgreSQL installation: %w", err)
+	}
+
+	directories := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(directories) != 3 {
+		return fmt.Errorf("unexpected output from %s: %q", pgConfigName, string(out))
+	}
+
+	for _, directory := range directories {
+		contextLogger.Info("Copying the PostgreSQL installation", "directory", directory)
+		if err := copyDirectory(directory, filepath.Join(destination, directory)); err != nil {
+			return fmt.Errorf("while copying %s: %w", directory, err)
+		}
+	}
+
+	_, err = fileutils.WriteStringToFile(
+		filepath.Join(destination, oldBinDirFileName),
+		filepath.Join(destination, directories[0]))
+	return err
+}
+
+// copyDirectory recursively copies a directory, preserving the
+// permissions of the files and the symbolic links
+func copyDirectory(source, destination string) error {
+	return filepath.WalkDir(source, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(destination, relativePath)
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case entry.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0o700)
+
+		case entry.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+
+		default:
+			if err := fileutils.CopyFile(path, target); err != nil {
+				return err
+			}
+			return os.Chmod(target, info.Mode().Perm())
+		}
+	})
+}
+
+// MajorUpgradeInfo contains the information needed to upgrade the data
+// directory of an instance to a newer major version of PostgreSQL
+type MajorUpgradeInfo struct {
+	// The data directory to be upgraded
+	PgData string
+
+	// The directory containing the WAL files, when they are stored
+	// in a separate volume
+	PgWal string
+
+	// The directory where the PostgreSQL installation of the previous
+	// major version has been copied by CopyPostgresInstallation
+	OldInstallationDirectory string
+
+	// The options passed to initdb to create the new data directory
+	InitDBOptions []string
+}
+
+// Upgrade creates a new data directory with the PostgreSQL installation
+// of the running image and upgrades the existing one into it, using
+// `pg_upgrade --link`. The upgraded data directory replaces the existing
+// one only when pg_upgrade succeeds: in every other case the existing data
+// directory is left usable by the previous major version.
+// An interrupted upgrade can be run again: it restarts from scratch when
+// it was interrupted before the upgraded data directory was validated,
+// and it completes the replacement of the data directory otherwise
+func (info MajorUpgradeInfo) Upgrade(ctx context.Context) error {
+	contextLogger := log.FromContext(ctx)
+
+	newPgData := info.PgData + newDataDirectorySuffix
+	newPgWal := ""
+	if info.PgWal != "" {
+		newPgWal = info.PgWal + newDataDirectorySuffix
+	}
+
+	swapInProgress, err := fileutils.FileExists(info.PgData + swapMarkerFileSuffix)
+	if err != nil {
+		return err
+	}
+	if swapInProgress {
+		contextLogger.Info("Resuming the replacement of the data directory with the upgraded one",
+			"pgdata", info.PgData)
+		return info.replaceDataDirectory(newPgData, newPgWal)
+	}
+
+	// Start from scratch if a previous attempt was interrupted
+	if err := info.restoreOldDataDirectory(newPgData, newPgWal); err != nil {
+		return err
+	}
+
+	oldMajorVersion, err := postgresutils.GetMajorVersion(info.PgData)
+	if err != nil {
+		return fmt.Errorf("while reading the major version of the data directory: %w", err)
+	}
+
+	targetMajorVersion, err := getInstalledMajorVersion()
+	if err != nil {
+		return err
+	}
+	if oldMajorVersion >= targetMajorVersion {
+		contextLogger.Info("The data directory has already been upgraded",
+			"pgdata", info.PgData,
+			"majorVersion", oldMajorVersion)
+		return nil
+	}
+
+	content, err := fileutils.ReadFile(filepath.Join(info.OldInstallationDirectory, oldBinDirFileName))
+	if err != nil {
+		return fmt.Errorf("while reading the location of the previous PostgreSQL installation: %w", err)
+	}
+	oldBinDir := strings.TrimSpace(string(content))
+
+	controlData, err := getOldControlData(oldBinDir, info.PgData)
+	if err != nil {
+		return err
+	}
+
+	// pg_upgrade requires the previous primary to be shut down cleanly
+	if state := controlData[utils.PgControlDataDatabaseClusterStateKey]; state != "shut down" {
+		return fmt.Errorf("the data directory has not been shut down cleanly, its state is %q", state)
+	}
+
+	initInfo := InitInfo{
+		PgData:        newPgData,
+		PgWal:         newPgWal,
+		InitDBOptions: slices.Concat(info.InitDBOptions, getInitDBOptionsFromControlData(controlData)),
+	}
+	if err := initInfo.CreateDataDirectory(); err != nil {
+		return errors.Join(err, info.removeNewDirectories(newPgData, newPgWal))
+	}
+
+	contextLogger.Info("Upgrading the data directory",
+		"pgdata", info.PgData,
+		"oldMajorVersion", oldMajorVersion,
+		"oldBinDir", oldBinDir)
+	if err := info.runPgUpgrade(oldBinDir, newPgData); err != nil {
+		return errors.Join(err, info.restoreOldDataDirectory(newPgData, newPgWal))
+	}
+
+	newMajorVersion, err := postgresutils.GetMajorVersion(newPgData)
+	if err == nil && newMajorVersion != targetMajorVersion {
+		err = fmt.Errorf("the upgraded data directory has major version %d, expected %d",
+			newMajorVersion, targetMajorVersion)
+	}
+	if err != nil {
+		return errors.Join(err, info.restoreOldDataDirectory(newPgData, newPgWal))
+	}
+
+	// pg_upgrade doesn't carry over the settings changed with ALTER SYSTEM
+	autoConfFile := filepath.Join(info.PgData, "postgresql.auto.conf")
+	if err := fileutils.CopyFile(autoConfFile, filepath.Join(newPgData, "postgresql.auto.conf")); err != nil &&
+		!errors.Is(err, os.ErrNotExist) {
+		return errors.Join(err, info.restoreOldDataDirectory(newPgData, newPgWal))
+	}
+
+	// From now on, the upgraded data directory is the one to be used,
+	// and an interrupted upgrade can only be completed
+	if err := info.removeOldDirectories(); err != nil {
+		return errors.Join(err, info.restoreOldDataDirectory(newPgData, newPgWal))
+	}
+	if _, err := fileutils.WriteStringToFile(info.PgData+swapMarkerFileSuffix, info.PgData); err != nil {
+		return errors.Join(err, info.restoreOldDataDirectory(newPgData, newPgWal))
+	}
+
+	contextLogger.Info("Replacing the data directory with the upgraded one",
+		"pgdata", info.PgData,
+		"newMajorVersion", newMajorVersion)
+	return info.replaceDataDirectory(newPgData, newPgWal)
+}
+
+// getInstalledMajorVersion returns the major version of the PostgreSQL
+// installation of the running image
+func getInstalledMajorVersion() (int, error) {
+	out, err := exec.Command(pgConfigName, "--version").Output() // #nosec
+	if err != nil {
+		return 0, fmt.Errorf("while detecting the version of the PostgreSQL installation: %w", err)
+	}
+
+	// The output is like "PostgreSQL 17.2 (Debian 17.2-1.pgdg120+1)",
+	// or "PostgreSQL 18beta1" for the beta versions
+	fields := strings.Fields(string(out))
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("unexpected output from %s: %q", pgConfigName, string(out))
+	}
+	majorVersion := fields[1]
+	if idx := strings.IndexFunc(majorVersion, func(r rune) bool { return r < '0' || r > '9' }); idx >= 0 {
+		majorVersion = majorVersion[:idx]
+	}
+
+	result, err := strconv.Atoi(majorVersion)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected output from %s: %q", pgConfigName, string(out))
+	}
+	return result, nil
+}
+
+// getOldControlData reads the control data of the data directory to be
+// upgraded, using the pg_controldata of the previous installation
+func getOldControlData(oldBinDir, pgData string) (map[string]string, error) {
+	pgControlDataCmd := exec.Command(filepath.Join(oldBinDir, pgControlDataName)) // #nosec
+	pgControlDataCmd.Env = append(os.Environ(), "PGDATA="+pgData)
+	out, err := pgControlDataCmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("while executing pg_controldata: %w", err)
+	}
+
+	return utils.ParsePgControldataOutput(string(out)), nil
+}
+
+// getInitDBOptionsFromControlData returns the initdb options needed for
+// the new data directory to be compatible with the existing one
+func getInitDBOptionsFromControlData(controlData map[string]string) []string {
+	var options []string
+
+	if checksumVersion := controlData["Data page checksum version"]; checksumVersion != "" &&
+		checksumVersion != "0" {
+		options = append(options, "--data-checksums")
+	}
+
+	if walSegmentSize, err := strconv.Atoi(controlData["Bytes per WAL segment"]); err == nil &&
+		walSegmentSize > 0 {
+		options = append(options, fmt.Sprintf("--wal-segsize=%d", walSegmentSize/(1024*1024)))
+	}
+
+	return options
+}
+
+// runPgUpgrade upgrades the data directory into the new one, linking the
+// data files instead of copying them
+func (info MajorUpgradeInfo) runPgUpgrade(oldBinDir, newPgData string) error {
+	// pg_upgrade writes its logs and the Unix sockets of the
+	// servers in the current directory
+	workingDirectory, err := os.MkdirTemp("", "pg_upgrade_")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.RemoveAll(workingDirectory)
+	}()
+
+	options := []string{
+		"--link",
+		"--username", "postgres",
+		"--old-bindir", oldBinDir,
+		"--old-datadir", info.PgData,
+		"--new-datadir", newPgData,
+		"--socketdir", workingDirectory,
+		// The configuration of the instance refers to the certificates and
+		// to the WAL archive, which are not available in the upgrade job
+		"--old-options", "-c ssl=off -c archive_mode=off",
+	}
+
+	pgUpgradeCmd := exec.Command(pgUpgradeName, options...) // #nosec
+	pgUpgradeCmd.Dir = workingDirectory
+	if err := execlog.RunStreaming(pgUpgradeCmd, pgUpgradeName); err != nil {
+		return fmt.Errorf("while upgrading the data directory: %w", err)
+	}
+
+	return nil
+}
+
+// restoreOldDataDirectory makes the existing data directory usable again
+// after a failed upgrade. pg_upgrade renames its control file, to prevent
+// it from being started, as soon as the data files are linked in the new
+// data directory, which is never started before being validated
+func (info MajorUpgradeInfo) restoreOldDataDirectory(newPgData, newPgWal string) error {
+	controlFile := filepath.Join(info.PgData, "global", "pg_control")
+	if _, err := os.Stat(controlFile); errors.Is(err, os.ErrNotExist) {
+		if err := os.Rename(controlFile+".old", controlFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("while restoring the control file of the data directory: %w", err)
+		}
+	}
+
+	return info.removeNewDirectories(newPgData, newPgWal)
+}
+
+// removeNewDirectories removes the data and WAL directories created
+// by the upgrade
+func (info MajorUpgradeInfo) removeNewDirectories(newPgData, newPgWal string) error {
+	if err := os.RemoveAll(newPgData); err != nil {
+		return err
+	}
+
+	if newPgWal != "" {
+		return os.RemoveAll(newPgWal)
+	}
+
+	return nil
+}
+
+// removeOldDirectories removes the data and WAL directories replaced
+// by a previous upgrade
+func (info MajorUpgradeInfo) removeOldDirectories() error {
+	if err := os.RemoveAll(info.PgData + oldDataDirectorySuffix); err != nil {
+		return err
+	}
+
+	if info.PgWal != "" {
+		return os.RemoveAll(info.PgWal + oldDataDirectorySuffix)
+	}
+
+	return nil
+}
+
+// replaceDataDirectory moves the upgraded data and WAL directories in
+// place of the existing ones, which are removed at the end together with
+// the swap marker file. Every step can be run again, so that an
+// interrupted replacement can be resumed
+func (info MajorUpgradeInfo) replaceDataDirectory(newPgData, newPgWal string) error {
+	if err := swapDirectory(info.PgData, newPgData); err != nil {
+		return err
+	}
+
+	if info.PgWal != "" {
+		if err := swapDirectory(info.PgWal, newPgWal); err != nil {
+			return err
+		}
+
+		// The WAL link of the upgraded data directory points to the
+		// directory it was created in
+		walLink := filepath.Join(info.PgData, "pg_wal")
+		if target, err := os.Readlink(walLink); err != nil || target != info.PgWal {
+			if err := os.Remove(walLink); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+
+			if err := os.Symlink(info.PgWal, walLink); err != nil {
+				return err
+			}
+		}
+	}
+
+	// The data files are shared with the upgraded data directory, so
+	// removing the previous one only frees the space of its catalog
+	if err := info.removeOldDirectories(); err != nil {
+		return err
+	}
+
+	return os.Remove(info.PgData + swapMarkerFileSuffix)
+}
+
+// swapDirectory moves the passed directory to its "-old" counterpart,
+// and the new one in its place. Nothing is done when the new directory
+// has already been moved
+func swapDirectory(directory, newDirectory string) error {
+	newExists, err := fileutils.FileExists(newDirectory)
+	if err != nil || !newExists {
+		return err
+	}
+
+	exists, err := fileutils.FileExists(directory)
+	if err != nil {
+		return err
+	}
+	if exists {
+		if err := os.Rename(directory, directory+oldDataDirectorySuffix); err != nil {
+			return err
+		}
+	}
+
+	return os.Rename(newDirectory, directory)
+}
@@ -0,0 +1,329 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeInitdb is an initdb replacement creating a data directory of
+// major version 17, recording the options it received
+const fakeInitdb = `#!/bin/sh
+echo "$@" > "$(dirname "$0")/initdb.args"
+while [ $# -gt 0 ]; do
+  case "$1" in
+    -D) shift; pgdata="$1" ;;
+    --waldir) shift; pgwal="$1" ;;
+  esac
+  shift
+done
+mkdir -p "$pgdata/global"
+echo 17 > "$pgdata/PG_VERSION"
+touch "$pgdata/postgresql.conf"
+if [ -n "$pgwal" ]; then
+  mkdir -p "$pgwal"
+  ln -s "$pgwal" "$pgdata/pg_wal"
+fi
+`
+
+// fakePgUpgradeTemplate is a pg_upgrade replacement disabling the old data
+// directory, like pg_upgrade --link does, and exiting with the given code
+const fakePgUpgradeTemplate = `#!/bin/sh
+while [ $# -gt 0 ]; do
+  case "$1" in
+    --old-datadir) shift; old="$1" ;;
+    --new-datadir) shift; new="$1" ;;
+  esac
+  shift
+done
+mv "$old/global/pg_control" "$old/global/pg_control.old"
+touch "$new/global/pg_control"
+exit %s
+`
+
+// fakeOldPgControlData is a pg_controldata replacement reporting
+// the state of the data directory in the STATE file
+const fakeOldPgControlData = `#!/bin/sh
+echo "pg_control version number:            1300"
+echo "Database cluster state:               $(cat "$(dirname "$0")/STATE")"
+echo "Bytes per WAL segment:                33554432"
+echo "Data page checksum version:           1"
+`
+
+var _ = Describe("copying the PostgreSQL installation", func() {
+	It("copies the binaries, the libraries and the shared files preserving their paths", func(ctx SpecContext) {
+		installation := GinkgoT().TempDir()
+		binDir := filepath.Join(installation, "lib", "postgresql", "16", "bin")
+		libDir := filepath.Join(installation, "lib", "postgresql", "16", "lib")
+		shareDir := filepath.Join(installation, "share", "postgresql", "16")
+		for _, directory := range []string{binDir, libDir, shareDir} {
+			Expect(os.MkdirAll(directory, 0o700)).To(Succeed())
+		}
+		Expect(os.WriteFile(filepath.Join(binDir, "postgres"), []byte("binary"), 0o700)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(libDir, "plpgsql.so"), []byte("library"), 0o600)).To(Succeed())
+		Expect(os.Symlink("plpgsql.so", filepath.Join(libDir, "plpgsql-link.so"))).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(shareDir, "postgres.bki"), []byte("catalog"), 0o600)).To(Succeed())
+
+		pgConfig := filepath.Join(GinkgoT().TempDir(), "pg_config")
+		Expect(os.WriteFile(pgConfig,
+			[]byte("#!/bin/sh\necho "+binDir+"\necho "+libDir+"\necho "+shareDir+"\n"), 0o700)).To(Succeed())
+		pgConfigName = pgConfig
+		DeferCleanup(func() {
+			pgConfigName = "pg_config"
+		})
+
+		destination := GinkgoT().TempDir()
+		Expect(CopyPostgresInstallation(ctx, destination)).To(Succeed())
+
+		copiedBinary := filepath.Join(destination, binDir, "postgres")
+		info, err := os.Stat(copiedBinary)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Mode().Perm()).To(BeEquivalentTo(0o700))
+		Expect(filepath.Join(destination, libDir, "plpgsql.so")).To(BeAnExistingFile())
+		Expect(os.Readlink(filepath.Join(destination, libDir, "plpgsql-link.so"))).To(Equal("plpgsql.so"))
+		Expect(filepath.Join(destination, shareDir, "postgres.bki")).To(BeAnExistingFile())
+
+		recordedBinDir, err := os.ReadFile(filepath.Join(destination, oldBinDirFileName)) // #nosec
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(recordedBinDir)).To(Equal(filepath.Join(destination, binDir)))
+	})
+})
+
+var _ = Describe("upgrading the data directory", func() {
+	var (
+		info      MajorUpgradeInfo
+		binDir    string
+		oldBinDir string
+	)
+
+	setPgUpgradeExitCode := func(exitCode string) {
+		executable := filepath.Join(binDir, "pg_upgrade")
+		Expect(os.WriteFile(executable,
+			[]byte(fmt.Sprintf(fakePgUpgradeTemplate, exitCode)), 0o700)).To(Succeed()) // #nosec
+		pgUpgradeName = executable
+	}
+
+	setOldState := func(state string) {
+		Expect(os.WriteFile(filepath.Join(oldBinDir, "STATE"), []byte(state), 0o600)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		binDir = GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(binDir, "initdb"), []byte(fakeInitdb), 0o700)).To(Succeed()) // #nosec
+		GinkgoT().Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+		pgConfigName = filepath.Join(binDir, "pg_config")
+		Expect(os.WriteFile(pgConfigName,
+			[]byte("#!/bin/sh\necho 'PostgreSQL 17.0 (Debian 17.0-1.pgdg120+1)'\n"), 0o700)).To(Succeed())
+		DeferCleanup(func() {
+			pgUpgradeName = "pg_upgrade"
+			pgConfigName = "pg_config"
+		})
+
+		oldInstallation := GinkgoT().TempDir()
+		oldBinDir = filepath.Join(oldInstallation, "usr", "lib", "postgresql", "16", "bin")
+		Expect(os.MkdirAll(oldBinDir, 0o700)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(oldBinDir, "pg_controldata"),
+			[]byte(fakeOldPgControlData), 0o700)).To(Succeed()) // #nosec
+		Expect(os.WriteFile(filepath.Join(oldInstallation, oldBinDirFileName),
+			[]byte(oldBinDir), 0o600)).To(Succeed())
+		setOldState("shut down")
+
+		pgData := filepath.Join(GinkgoT().TempDir(), "pgdata")
+		Expect(os.MkdirAll(filepath.Join(pgData, "global"), 0o700)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(pgData, "PG_VERSION"), []byte("16\n"), 0o600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(pgData, "global", "pg_control"), []byte{}, 0o600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(pgData, "postgresql.auto.conf"),
+			[]byte("work_mem = '8MB'\n"), 0o600)).To(Succeed())
+
+		info = MajorUpgradeInfo{
+			PgData:                   pgData,
+			OldInstallationDirectory: oldInstallation,
+			InitDBOptions:            []string{"--encoding=UTF8"},
+		}
+	})
+
+	It("replaces the data directory with the upgraded one", func(ctx SpecContext) {
+		setPgUpgradeExitCode("0")
+		Expect(info.Upgrade(ctx)).To(Succeed())
+
+		Expect(os.ReadFile(filepath.Join(info.PgData, "PG_VERSION"))).To(BeEquivalentTo("17\n"))
+		Expect(os.ReadFile(filepath.Join(info.PgData, "postgresql.auto.conf"))).
+			To(BeEquivalentTo("work_mem = '8MB'\n"))
+		Expect(info.PgData + newDataDirectorySuffix).ToNot(BeADirectory())
+		Expect(info.PgData + oldDataDirectorySuffix).ToNot(BeADirectory())
+		Expect(info.PgData + swapMarkerFileSuffix).ToNot(BeAnExistingFile())
+
+		initdbArgs, err := os.ReadFile(filepath.Join(binDir, "initdb.args")) // #nosec
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(initdbArgs)).To(ContainSubstring("--encoding=UTF8 --data-checksums --wal-segsize=32"))
+	})
+
+	It("moves the upgraded WAL directory in place of the existing one", func(ctx SpecContext) {
+		info.PgWal = filepath.Join(GinkgoT().TempDir(), "pg_wal")
+		Expect(os.MkdirAll(info.PgWal, 0o700)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(info.PgWal, "000000010000000000000001"), []byte{}, 0o600)).To(Succeed())
+		Expect(os.Symlink(info.PgWal, filepath.Join(info.PgData, "pg_wal"))).To(Succeed())
+
+		setPgUpgradeExitCode("0")
+		Expect(info.Upgrade(ctx)).To(Succeed())
+
+		Expect(os.Readlink(filepath.Join(info.PgData, "pg_wal"))).To(Equal(info.PgWal))
+		Expect(filepath.Join(info.PgWal, "000000010000000000000001")).ToNot(BeAnExistingFile())
+		Expect(info.PgWal + newDataDirectorySuffix).ToNot(BeADirectory())
+	})
+
+	It("leaves the existing data directory usable when pg_upgrade fails", func(ctx SpecContext) {
+		setPgUpgradeExitCode("1")
+		Expect(info.Upgrade(ctx)).ToNot(Succeed())
+
+		Expect(os.ReadFile(filepath.Join(info.PgData, "PG_VERSION"))).To(BeEquivalentTo("16\n"))
+		Expect(filepath.Join(info.PgData, "global", "pg_control")).To(BeAnExistingFile())
+		Expect(filepath.Join(info.PgData, "global", "pg_control.old")).ToNot(BeAnExistingFile())
+		Expect(info.PgData + newDataDirectorySuffix).ToNot(BeADirectory())
+	})
+
+	It("refuses to upgrade a data directory that was not shut down cleanly", func(ctx SpecContext) {
+		setOldState("in production")
+		setPgUpgradeExitCode("0")
+		Expect(info.Upgrade(ctx)).To(MatchError(ContainSubstring("in production")))
+
+		Expect(os.ReadFile(filepath.Join(info.PgData, "PG_VERSION"))).To(BeEquivalentTo("16\n"))
+		Expect(info.PgData + newDataDirectorySuffix).ToNot(BeADirectory())
+	})
+
+	Context("when a previous upgrade was interrupted", func() {
+		// createUpgradedDataDirectory creates a data directory like
+		// the one upgraded by pg_upgrade
+		createUpgradedDataDirectory := func(pgData string) {
+			Expect(os.MkdirAll(filepath.Join(pgData, "global"), 0o700)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(pgData, "PG_VERSION"), []byte("17\n"), 0o600)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(pgData, "global", "pg_control"), []byte{}, 0o600)).To(Succeed())
+		}
+
+		markSwapInProgress := func() {
+			Expect(os.WriteFile(info.PgData+swapMarkerFileSuffix, []byte(info.PgData), 0o600)).To(Succeed())
+		}
+
+		expectUpgraded := func() {
+			Expect(os.ReadFile(filepath.Join(info.PgData, "PG_VERSION"))).To(BeEquivalentTo("17\n"))
+			Expect(info.PgData + newDataDirectorySuffix).ToNot(BeADirectory())
+			Expect(info.PgData + oldDataDirectorySuffix).ToNot(BeADirectory())
+			Expect(info.PgData + swapMarkerFileSuffix).ToNot(BeAnExistingFile())
+		}
+
+		BeforeEach(func() {
+			// pg_upgrade must not run when the replacement is resumed
+			setPgUpgradeExitCode("1")
+		})
+
+		It("restores the control file renamed by pg_upgrade and upgrades again", func(ctx SpecContext) {
+			controlFile := filepath.Join(info.PgData, "global", "pg_control")
+			Expect(os.Rename(controlFile, controlFile+".old")).To(Succeed())
+			createUpgradedDataDirectory(info.PgData + newDataDirectorySuffix)
+
+			setPgUpgradeExitCode("0")
+			Expect(info.Upgrade(ctx)).To(Succeed())
+			expectUpgraded()
+		})
+
+		It("restores the control file renamed by pg_upgrade when the upgrade fails again", func(ctx SpecContext) {
+			controlFile := filepath.Join(info.PgData, "global", "pg_control")
+			Expect(os.Rename(controlFile, controlFile+".old")).To(Succeed())
+
+			Expect(info.Upgrade(ctx)).ToNot(Succeed())
+			Expect(os.ReadFile(filepath.Join(info.PgData, "PG_VERSION"))).To(BeEquivalentTo("16\n"))
+			Expect(controlFile).To(BeAnExistingFile())
+			Expect(controlFile + ".old").ToNot(BeAnExistingFile())
+		})
+
+		It("doesn't upgrade a data directory which has already been upgraded", func(ctx SpecContext) {
+			Expect(os.RemoveAll(info.PgData)).To(Succeed())
+			createUpgradedDataDirectory(info.PgData)
+
+			Expect(info.Upgrade(ctx)).To(Succeed())
+			expectUpgraded()
+		})
+
+		It("resumes the replacement before the data directory was moved", func(ctx SpecContext) {
+			createUpgradedDataDirectory(info.PgData + newDataDirectorySuffix)
+			markSwapInProgress()
+
+			Expect(info.Upgrade(ctx)).To(Succeed())
+			expectUpgraded()
+		})
+
+		It("resumes the replacement after the data directory was moved", func(ctx SpecContext) {
+			Expect(os.Rename(info.PgData, info.PgData+oldDataDirectorySuffix)).To(Succeed())
+			createUpgradedDataDirectory(info.PgData + newDataDirectorySuffix)
+			markSwapInProgress()
+
+			Expect(info.Upgrade(ctx)).To(Succeed())
+			expectUpgraded()
+		})
+
+		It("resumes the replacement after the upgraded data directory was moved", func(ctx SpecContext) {
+			Expect(os.Rename(info.PgData, info.PgData+oldDataDirectorySuffix)).To(Succeed())
+			createUpgradedDataDirectory(info.PgData)
+			markSwapInProgress()
+
+			Expect(info.Upgrade(ctx)).To(Succeed())
+			expectUpgraded()
+		})
+
+		It("resumes the replacement of the WAL directory", func(ctx SpecContext) {
+			info.PgWal = filepath.Join(GinkgoT().TempDir(), "pg_wal")
+			newPgWal := info.PgWal + newDataDirectorySuffix
+			Expect(os.MkdirAll(info.PgWal+oldDataDirectorySuffix, 0o700)).To(Succeed())
+			Expect(os.MkdirAll(newPgWal, 0o700)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(newPgWal, "000000010000000000000001"), []byte{}, 0o600)).
+				To(Succeed())
+
+			Expect(os.Rename(info.PgData, info.PgData+oldDataDirectorySuffix)).To(Succeed())
+			createUpgradedDataDirectory(info.PgData)
+			Expect(os.Symlink(newPgWal, filepath.Join(info.PgData, "pg_wal"))).To(Succeed())
+			markSwapInProgress()
+
+			Expect(info.Upgrade(ctx)).To(Succeed())
+			expectUpgraded()
+			Expect(os.Readlink(filepath.Join(info.PgData, "pg_wal"))).To(Equal(info.PgWal))
+			Expect(filepath.Join(info.PgWal, "000000010000000000000001")).To(BeAnExistingFile())
+			Expect(newPgWal).ToNot(BeADirectory())
+			Expect(info.PgWal + oldDataDirectorySuffix).ToNot(BeADirectory())
+		})
+	})
+})
+
+var _ = Describe("detecting the major version of the PostgreSQL installation", func() {
+	DescribeTable("parses the output of pg_config",
+		func(output string, expected int) {
+			pgConfigName = filepath.Join(GinkgoT().TempDir(), "pg_config")
+			Expect(os.WriteFile(pgConfigName, []byte("#!/bin/sh\necho '"+output+"'\n"), 0o700)).To(Succeed())
+			DeferCleanup(func() {
+				pgConfigName = "pg_config"
+			})
+
+			Expect(getInstalledMajorVersion()).To(Equal(expected))
+		},
+		Entry("with a release", "PostgreSQL 17.2 (Debian 17.2-1.pgdg120+1)", 17),
+		Entry("with a beta version", "PostgreSQL 18beta1", 18),
+	)
+})
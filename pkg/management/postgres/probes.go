@@ -33,6 +33,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/executablehash"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/system/compatibility"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
 )
 
@@ -75,6 +76,10 @@ func (instance *Instance) GetStatus() (result *postgres.PostgresqlStatus, err er
 		}
 	}()
 
+	// The disk usage is reported even when PostgreSQL is not able to
+	// accept connections, as a full volume is a common cause of that
+	result.DataVolumeUsage = instance.getDataVolumeUsage()
+
 	if instance.PgRewindIsRunning {
 		// We know that pg_rewind is running, so we exit with the proper status
 		// updated, and we can provide that information to the user.
@@ -86,14 +91,16 @@ func (instance *Instance) GetStatus() (result *postgres.PostgresqlStatus, err er
 		return result, err
 	}
 
-	row := superUserDB.QueryRow(
+	row := superUserDB.QueryRow(fmt.Sprintf(
 		`SELECT
 			(pg_control_system()).system_identifier,
 			-- True if this is a primary instance
 			NOT pg_is_in_recovery() as primary,
 			-- True if at least one column requires a restart
-			EXISTS(SELECT 1 FROM pg_settings WHERE pending_restart)`)
-	err = row.Scan(&result.SystemID, &result.IsPrimary, &result.PendingRestart)
+			EXISTS(SELECT 1 FROM pg_settings WHERE pending_restart),
+			-- The hash of the configuration that has been loaded
+			COALESCE(current_setting('%s', true), '')`, postgres.CNPGConfigSha256))
+	err = row.Scan(&result.SystemID, &result.IsPrimary, &result.PendingRestart, &result.LoadedConfigurationHash)
 	if err != nil {
 		return result, err
 	}
@@ -231,6 +238,21 @@ WHERE pending_settings.name IN (
 	return decreasedSensibleValues, nil
 }
 
+// getDataVolumeUsage gets the space usage of the volume containing PGDATA,
+// returning nil when it cannot be detected
+func (instance *Instance) getDataVolumeUsage() *postgres.DiskUsage {
+	total, available, err := compatibility.GetDiskUsage(instance.PgData)
+	if err != nil {
+		log.Debug("Error while detecting the data volume usage", "pgdata", instance.PgData, "err", err)
+		return nil
+	}
+
+	return &postgres.DiskUsage{
+		TotalBytes:     total,
+		AvailableBytes: available,
+	}
+}
+
 // fillStatus extract the current instance information into the PostgresqlStatus
 // structure
 func (instance *Instance) fillStatus(result *postgres.PostgresqlStatus) error {
@@ -261,6 +283,20 @@ func (instance *Instance) fillStatus(result *postgres.PostgresqlStatus) error {
 		return err
 	}
 
+	if result.IsPrimary {
+		if err := fillPreparedTransactionsStatus(superUserDB, result); err != nil {
+			return err
+		}
+		if err := fillWraparoundStatus(superUserDB, result); err != nil {
+			return err
+		}
+	}
+
+	result.ClientConnections, result.MaxClientConnections, err = GetClientConnections(superUserDB)
+	if err != nil {
+		return err
+	}
+
 	return instance.fillWalStatus(result)
 }
 
@@ -369,6 +405,108 @@ func fillArchiverStatus(superUserDB *sql.DB, result *postgres.PostgresqlStatus)
 	)
 }
 
+// fillPreparedTransactionsStatus get information about the transactions
+// prepared for two-phase commit
+func fillPreparedTransactionsStatus(superUserDB *sql.DB, result *postgres.PostgresqlStatus) error {
+	row := superUserDB.QueryRow(
+		`
+		SELECT
+			count(*),
+			COALESCE(EXTRACT(EPOCH FROM (now() - min(prepared))), 0)::bigint
+		FROM pg_catalog.pg_prepared_xacts
+		`)
+
+	return row.Scan(&result.PreparedTransactions, &result.OldestPreparedTransactionAge)
+}
+
+// DatabaseWraparoundAge is the age of the oldest unfrozen transaction
+// ID and multixact ID of a database
+type DatabaseWraparoundAge struct {
+	Database string
+	XIDAge   int64
+	MXIDAge  int64
+}
+
+// GetWraparoundAges gets the transaction ID and multixact ID age of every
+// database of the instance
+func GetWraparoundAges(superUserDB *sql.DB) ([]DatabaseWraparoundAge, error) {
+	rows, err := superUserDB.Query(
+		`
+		SELECT
+			datname,
+			pg_catalog.age(datfrozenxid),
+			pg_catalog.mxid_age(datminmxid)
+		FROM pg_catalog.pg_database
+		`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var result []DatabaseWraparoundAge
+	for rows.Next() {
+		var age DatabaseWraparoundAge
+		if err := rows.Scan(&age.Database, &age.XIDAge, &age.MXIDAge); err != nil {
+			return nil, err
+		}
+		result = append(result, age)
+	}
+
+	return result, rows.Err()
+}
+
+// fillWraparoundStatus get the age of the oldest database and the
+// thresholds used by autovacuum to prevent the wraparound
+func fillWraparoundStatus(superUserDB *sql.DB, result *postgres.PostgresqlStatus) error {
+	ages, err := GetWraparoundAges(superUserDB)
+	if err != nil {
+		return err
+	}
+
+	result.OldestXIDAge, result.OldestMXIDAge = 0, 0
+	result.OldestXIDAgeDatabase, result.OldestMXIDAgeDatabase = "", ""
+	for _, age := range ages {
+		if age.XIDAge > result.OldestXIDAge {
+			result.OldestXIDAge = age.XIDAge
+			result.OldestXIDAgeDatabase = age.Database
+		}
+		if age.MXIDAge > result.OldestMXIDAge {
+			result.OldestMXIDAge = age.MXIDAge
+			result.OldestMXIDAgeDatabase = age.Database
+		}
+	}
+
+	row := superUserDB.QueryRow(
+		`
+		SELECT
+			pg_catalog.current_setting('autovacuum_freeze_max_age')::bigint,
+			pg_catalog.current_setting('autovacuum_multixact_freeze_max_age')::bigint
+		`)
+
+	return row.Scan(&result.AutovacuumFreezeMaxAge, &result.AutovacuumMultixactFreezeMaxAge)
+}
+
+// GetClientConnections gets the number of client connections to the
+// instance and the number of connection slots available to non-superuser
+// roles. The instance manager connects as a superuser, so it can still use
+// the slots reserved by `superuser_reserved_connections` when the other
+// ones are exhausted
+func GetClientConnections(superUserDB *sql.DB) (clientConnections int, maxClientConnections int, err error) {
+	row := superUserDB.QueryRow(
+		`
+		SELECT
+			(SELECT count(*) FROM pg_catalog.pg_stat_activity WHERE backend_type = 'client backend'),
+			current_setting('max_connections')::int
+				- current_setting('superuser_reserved_connections')::int
+				- COALESCE(current_setting('reserved_connections', true)::int, 0)
+		`)
+
+	err = row.Scan(&clientConnections, &maxClientConnections)
+	return clientConnections, maxClientConnections, err
+}
+
 // fillReplicationSlotsStatus get information about the replication slots
 func (instance *Instance) fillReplicationSlotsStatus(result *postgres.PostgresqlStatus) error {
 	if !result.IsPrimary {
@@ -465,7 +603,9 @@ func (instance *Instance) fillWalStatusFromConnection(result *postgres.Postgresq
 			coalesce(flush_lag, '0'::interval),
 			coalesce(replay_lag, '0'::interval),
 			coalesce(sync_state, ''),
-			coalesce(sync_priority, 0)
+			coalesce(sync_priority, 0),
+			coalesce(pg_catalog.pg_wal_lsn_diff(pg_catalog.pg_current_wal_lsn(), write_lsn), 0)::bigint,
+			coalesce(pg_catalog.pg_wal_lsn_diff(pg_catalog.pg_current_wal_lsn(), replay_lsn), 0)::bigint
 		FROM pg_catalog.pg_stat_replication
 		WHERE application_name ~ $1 AND usename = $2`,
 		fmt.Sprintf("%s-[0-9]+$", instance.GetClusterName()),
@@ -494,6 +634,8 @@ func (instance *Instance) fillWalStatusFromConnection(result *postgres.Postgresq
 			&pgr.ReplayLag,
 			&pgr.SyncState,
 			&pgr.SyncPriority,
+			&pgr.WriteLagBytes,
+			&pgr.ReplayLagBytes,
 		)
 		if err != nil {
 			return err
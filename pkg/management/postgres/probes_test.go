@@ -53,7 +53,9 @@ var _ = Describe("probes", func() {
 				coalesce(flush_lag, '0'::interval),
 				coalesce(replay_lag, '0'::interval),
 				coalesce(sync_state, ''),
-				coalesce(sync_priority, 0)
+				coalesce(sync_priority, 0),
+				coalesce(pg_catalog.pg_wal_lsn_diff(pg_catalog.pg_current_wal_lsn(), write_lsn), 0)::bigint,
+				coalesce(pg_catalog.pg_wal_lsn_diff(pg_catalog.pg_current_wal_lsn(), replay_lsn), 0)::bigint
 			FROM pg_catalog.pg_stat_replication
 			WHERE application_name ~ $1 AND usename = $2`),
 		).WithArgs("-[0-9]+$", "streaming_replica").WillReturnError(errFailedQuery)
@@ -62,6 +64,48 @@ var _ = Describe("probes", func() {
 		Expect(err).To(Equal(errFailedQuery))
 	})
 
+	It("fillWalStatus should collect the lag of the standbys in bytes", func() {
+		instance := &Instance{}
+		status := &postgres.PostgresqlStatus{
+			IsPrimary: true,
+		}
+
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery(`FROM pg_catalog.pg_stat_replication`).
+			WithArgs("-[0-9]+$", "streaming_replica").
+			WillReturnRows(sqlmock.NewRows([]string{
+				"application_name", "state", "sent_lsn", "write_lsn", "flush_lsn", "replay_lsn",
+				"write_lag", "flush_lag", "replay_lag", "sync_state", "sync_priority",
+				"write_lag_bytes", "replay_lag_bytes",
+			}).AddRow(
+				"cluster-example-2", "streaming", "0/5000000", "0/4000000", "0/4000000", "0/3000000",
+				"00:00:00", "00:00:00", "00:00:01", "async", "0",
+				16777216, 33554432,
+			))
+
+		// The WAL archive status directory doesn't exist
+		// in the test environment
+		_ = instance.fillWalStatusFromConnection(status, db)
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+		Expect(status.ReplicationInfo).To(HaveLen(1))
+		Expect(status.ReplicationInfo[0].WriteLagBytes).To(BeEquivalentTo(16777216))
+		Expect(status.ReplicationInfo[0].ReplayLagBytes).To(BeEquivalentTo(33554432))
+	})
+
+	It("fillWalStatus should not query the standbys on a replica", func() {
+		instance := &Instance{}
+		status := &postgres.PostgresqlStatus{}
+
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(instance.fillWalStatusFromConnection(status, db)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+		Expect(status.ReplicationInfo).To(BeEmpty())
+	})
+
 	It("fillArchiveStatus should properly handle errors", func() {
 		db, mock, err := sqlmock.New()
 		Expect(err).ToNot(HaveOccurred())
@@ -89,6 +133,67 @@ var _ = Describe("probes", func() {
 		Expect(status.IsArchivingWAL).To(BeFalse())
 	})
 
+	It("fillPreparedTransactionsStatus should report the oldest prepared transaction", func() {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery(`.*pg_prepared_xacts`).
+			WillReturnRows(sqlmock.NewRows([]string{"count", "age"}).AddRow(2, 3600))
+
+		status := &postgres.PostgresqlStatus{}
+		Expect(fillPreparedTransactionsStatus(db, status)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+
+		Expect(status.PreparedTransactions).To(Equal(2))
+		Expect(status.OldestPreparedTransactionAge).To(BeEquivalentTo(3600))
+	})
+
+	It("fillWraparoundStatus should report the age of the oldest database", func() {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery(`.*datfrozenxid.*`).
+			WillReturnRows(sqlmock.NewRows([]string{"datname", "age", "mxid_age"}).
+				AddRow("postgres", 1200, 300).
+				AddRow("app", 350000000, 45))
+		mock.ExpectQuery(`.*autovacuum_freeze_max_age.*`).
+			WillReturnRows(sqlmock.NewRows([]string{"freeze", "multixact_freeze"}).AddRow(200000000, 400000000))
+
+		status := &postgres.PostgresqlStatus{}
+		Expect(fillWraparoundStatus(db, status)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+
+		Expect(status.OldestXIDAge).To(BeEquivalentTo(350000000))
+		Expect(status.OldestXIDAgeDatabase).To(Equal("app"))
+		Expect(status.OldestMXIDAge).To(BeEquivalentTo(300))
+		Expect(status.OldestMXIDAgeDatabase).To(Equal("postgres"))
+		Expect(status.AutovacuumFreezeMaxAge).To(BeEquivalentTo(200000000))
+		Expect(status.AutovacuumMultixactFreezeMaxAge).To(BeEquivalentTo(400000000))
+	})
+
+	It("GetClientConnections should report the connection slots available to non-superusers", func() {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery(`.*superuser_reserved_connections.*`).
+			WillReturnRows(sqlmock.NewRows([]string{"count", "max"}).AddRow(97, 97))
+
+		clientConnections, maxClientConnections, err := GetClientConnections(db)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+
+		status := postgres.PostgresqlStatus{
+			ClientConnections:    clientConnections,
+			MaxClientConnections: maxClientConnections,
+		}
+		Expect(status.AreConnectionsExhausted()).To(BeTrue())
+	})
+
+	It("connects as a superuser to use the reserved connection slots", func() {
+		instance := &Instance{}
+		Expect(instance.ConnectionPool().GetDsn("postgres")).To(ContainSubstring("user=postgres "))
+	})
+
 	Context("Fill basebackup stats", func() {
 		It("does nothing in case of that major version is less than 13 ", func() {
 			instance := &Instance{
@@ -42,6 +42,7 @@ import (
 	"github.com/cloudnative-pg/machinery/pkg/fileutils"
 	"github.com/cloudnative-pg/machinery/pkg/log"
 	"github.com/cloudnative-pg/machinery/pkg/stringset"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/retry"
@@ -51,11 +52,13 @@ import (
 	pluginClient "github.com/cloudnative-pg/cloudnative-pg/internal/cnpi/plugin/client"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cnpi/plugin/repository"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/configfile"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/external"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
 	postgresSpec "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources/status"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/system"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
@@ -64,6 +67,14 @@ var (
 	// ErrInstanceInRecovery is raised while PostgreSQL is still in recovery mode
 	ErrInstanceInRecovery = fmt.Errorf("instance in recovery")
 
+	// errRecoveryNotPaused is raised while PostgreSQL has not yet
+	// paused the recovery at the recovery target
+	errRecoveryNotPaused = fmt.Errorf("recovery not paused")
+
+	// errPromotionNotRequested is raised while the promotion of a cluster
+	// paused at the recovery target has not been requested
+	errPromotionNotRequested = fmt.Errorf("promotion not requested")
+
 	// RetryUntilRecoveryDone is the default retry configuration that is used
 	// to wait for a restored cluster to promote itself
 	RetryUntilRecoveryDone = wait.Backoff{
@@ -204,11 +215,21 @@ func (info InitInfo) createBackupObjectForSnapshotRestore(
 	}
 	serverName := server.GetServerName()
 
+	// The data directory is restored from the snapshots, and
+	// we only need to fetch the WAL files from the object store
+	walObjectStore := server.GetWALObjectStore()
+	if walObjectStore == nil {
+		return nil, nil, fmt.Errorf("missing barmanObjectStore in external cluster: %v", sourceName)
+	}
+	if walObjectStore.ServerName != "" {
+		serverName = walObjectStore.ServerName
+	}
+
 	env, err := barmanCredentials.EnvSetRestoreCloudCredentials(
 		ctx,
 		typedClient,
 		cluster.Namespace,
-		server.BarmanObjectStore,
+		walObjectStore,
 		os.Environ())
 	if err != nil {
 		return nil, nil, err
@@ -221,10 +242,10 @@ func (info InitInfo) createBackupObjectForSnapshotRestore(
 			},
 		},
 		Status: apiv1.BackupStatus{
-			BarmanCredentials: server.BarmanObjectStore.BarmanCredentials,
-			EndpointCA:        server.BarmanObjectStore.EndpointCA,
-			EndpointURL:       server.BarmanObjectStore.EndpointURL,
-			DestinationPath:   server.BarmanObjectStore.DestinationPath,
+			BarmanCredentials: walObjectStore.BarmanCredentials,
+			EndpointCA:        walObjectStore.EndpointCA,
+			EndpointURL:       walObjectStore.EndpointURL,
+			DestinationPath:   walObjectStore.DestinationPath,
 			ServerName:        serverName,
 			Phase:             apiv1.BackupPhaseCompleted,
 		},
@@ -283,7 +304,13 @@ func (info InitInfo) Restore(ctx context.Context) error {
 			return err
 		}
 
-		if err := info.ensureArchiveContainsLastCheckpointRedoWAL(ctx, cluster, env, backup); err != nil {
+		// The WAL files may be stored separately from the base backup
+		walBackup, walEnv, err := info.loadWALArchiveLocation(ctx, typedClient, cluster, backup, env)
+		if err != nil {
+			return err
+		}
+
+		if err := info.ensureArchiveContainsLastCheckpointRedoWAL(ctx, cluster, walEnv, walBackup); err != nil {
 			return err
 		}
 
@@ -295,12 +322,12 @@ func (info InitInfo) Restore(ctx context.Context) error {
 			return err
 		}
 
-		conf, err := getRestoreWalConfig(ctx, backup)
+		conf, err := getRestoreWalConfig(ctx, walBackup)
 		if err != nil {
 			return err
 		}
 		config = conf
-		envs = env
+		envs = walEnv
 	}
 
 	if err := info.WriteInitialPostgresqlConf(ctx, cluster); err != nil {
@@ -603,6 +630,55 @@ func (info InitInfo) loadBackupFromReference(
 	return &backup, env, nil
 }
 
+// loadWALArchiveLocation returns a copy of the passed backup pointing to the
+// object store containing the WAL files, together with the environment
+// variables needed to access it. This is different from the backup itself
+// only when the recovery source archives the WAL files in a separate
+// object store
+func (info InitInfo) loadWALArchiveLocation(
+	ctx context.Context,
+	typedClient client.Client,
+	cluster *apiv1.Cluster,
+	backup *apiv1.Backup,
+	env []string,
+) (*apiv1.Backup, []string, error) {
+	sourceName := cluster.Spec.Bootstrap.Recovery.Source
+	if sourceName == "" {
+		return backup, env, nil
+	}
+
+	server, found := cluster.ExternalCluster(sourceName)
+	if !found || server.BarmanObjectStore == nil || server.WALObjectStore == nil {
+		return backup, env, nil
+	}
+
+	walObjectStore := server.GetWALObjectStore()
+	log.FromContext(ctx).Info("Fetching the WAL files from a separate object store",
+		"sourceName", sourceName,
+		"destinationPath", walObjectStore.DestinationPath)
+
+	walEnv, err := barmanCredentials.EnvSetRestoreCloudCredentials(
+		ctx,
+		typedClient,
+		cluster.Namespace,
+		walObjectStore,
+		os.Environ())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	walBackup := backup.DeepCopy()
+	walBackup.Status.BarmanCredentials = walObjectStore.BarmanCredentials
+	walBackup.Status.EndpointCA = walObjectStore.EndpointCA
+	walBackup.Status.EndpointURL = walObjectStore.EndpointURL
+	walBackup.Status.DestinationPath = walObjectStore.DestinationPath
+	if walObjectStore.ServerName != "" {
+		walBackup.Status.ServerName = walObjectStore.ServerName
+	}
+
+	return walBackup, walEnv, nil
+}
+
 // writeRestoreWalConfig writes a `custom.conf` allowing PostgreSQL
 // to complete the WAL recovery from the object storage and then start
 // as a new primary
@@ -845,11 +921,11 @@ func (info InitInfo) WriteInitialPostgresqlConf(ctx context.Context, cluster *ap
 	if err != nil {
 		return fmt.Errorf("while generating pg_hba.conf: %w", err)
 	}
-	_, err = temporaryInstance.RefreshPGIdent(ctx, cluster.Spec.PostgresConfiguration.PgIdent)
+	_, err = temporaryInstance.RefreshPGIdent(ctx, cluster)
 	if err != nil {
 		return fmt.Errorf("while generating pg_ident.conf: %w", err)
 	}
-	_, err = temporaryInstance.RefreshConfigurationFilesFromCluster(ctx, cluster, false)
+	_, err = temporaryInstance.RefreshConfigurationFilesFromCluster(ctx, cluster, false, nil)
 	if err != nil {
 		return fmt.Errorf("while generating Postgres configuration: %w", err)
 	}
@@ -918,6 +994,37 @@ func (info InitInfo) ConfigureInstanceAfterRestore(ctx context.Context, cluster
 		return err
 	}
 
+	// When the recovery has to be paused at the target, the cluster
+	// is started only once its promotion has been requested
+	var waitForPromotion func() error
+	if targetAction := cluster.GetRecoveryTargetAction(); targetAction != apiv1.RecoveryTargetActionPromote {
+		typedClient, err := management.NewControllerRuntimeClient()
+		if err != nil {
+			return err
+		}
+		waitForPromotion = func() error {
+			return waitForPromotionRequest(ctx, typedClient, cluster)
+		}
+
+		if targetAction == apiv1.RecoveryTargetActionShutdown {
+			// Stop the instance as soon as the target is reached. Once
+			// promoted, it will replay the WALs again up to the target
+			if err := instance.WithActiveInstance(func() error {
+				db, err := instance.GetSuperUserDB()
+				if err != nil {
+					return err
+				}
+				return waitUntilRecoveryPaused(db)
+			}); err != nil {
+				return fmt.Errorf("while waiting for PostgreSQL to reach the recovery target: %w", err)
+			}
+			if err := waitForPromotion(); err != nil {
+				return err
+			}
+			waitForPromotion = func() error { return nil }
+		}
+	}
+
 	// This will start the recovery of WALs taken during the backup
 	// and, after that, the server will start in a new timeline
 	if err := instance.WithActiveInstance(func() error {
@@ -926,13 +1033,19 @@ func (info InitInfo) ConfigureInstanceAfterRestore(ctx context.Context, cluster
 			return err
 		}
 
+		if waitForPromotion != nil {
+			if err := promoteAtRecoveryTarget(db, waitForPromotion); err != nil {
+				return fmt.Errorf("while promoting PostgreSQL at the recovery target: %w", err)
+			}
+		}
+
 		// Wait until we exit from recovery mode
 		err = waitUntilRecoveryFinishes(db)
 		if err != nil {
 			return fmt.Errorf("while waiting for PostgreSQL to stop recovery mode: %w", err)
 		}
 
-		return nil
+		return info.validateRecoveredInstance(ctx, instance, cluster, db)
 	}); err != nil {
 		return err
 	}
@@ -973,11 +1086,13 @@ func (info *InitInfo) checkBackupDestination(
 		return nil
 	}
 
+	walObjectStore := cluster.GetBackupConfiguration().GetWALObjectStore()
+
 	// Get environment from cache
 	env, err := barmanCredentials.EnvSetRestoreCloudCredentials(ctx,
 		client,
 		cluster.Namespace,
-		cluster.Spec.Backup.BarmanObjectStore,
+		walObjectStore,
 		os.Environ())
 	if err != nil {
 		return fmt.Errorf("can't get credentials for cluster %v: %w", cluster.Name, err)
@@ -1000,7 +1115,7 @@ func (info *InitInfo) checkBackupDestination(
 
 	// Get WAL archive options
 	checkWalOptions, err := walArchiver.BarmanCloudCheckWalArchiveOptions(
-		ctx, cluster.Spec.Backup.BarmanObjectStore, cluster.Name)
+		ctx, walObjectStore, cluster.Name)
 	if err != nil {
 		contextLogger.Error(err, "while getting barman-cloud-wal-archive options")
 		return err
@@ -1041,6 +1156,212 @@ func waitUntilRecoveryFinishes(db *sql.DB) error {
 	})
 }
 
+// waitUntilRecoveryPaused periodically checks the underlying
+// PostgreSQL connection and returns only when the recovery
+// has been paused at the recovery target
+func waitUntilRecoveryPaused(db *sql.DB) error {
+	errorIsRetriable := func(err error) bool {
+		return err == errRecoveryNotPaused
+	}
+
+	return retry.OnError(RetryUntilRecoveryDone, errorIsRetriable, func() error {
+		row := db.QueryRow("SELECT pg_catalog.pg_is_wal_replay_paused()")
+
+		var paused bool
+		if err := row.Scan(&paused); err != nil {
+			return fmt.Errorf("error while reading results of pg_is_wal_replay_paused: %w", err)
+		}
+
+		log.Info("Checking if the recovery has reached the target",
+			"paused", paused)
+
+		if !paused {
+			return errRecoveryNotPaused
+		}
+
+		return nil
+	})
+}
+
+// promoteAtRecoveryTarget waits for the recovery to be paused at the
+// recovery target, and then resumes it once the passed function returns,
+// making PostgreSQL end the recovery and start in a new timeline
+func promoteAtRecoveryTarget(db *sql.DB, waitForPromotion func() error) error {
+	if err := waitUntilRecoveryPaused(db); err != nil {
+		return err
+	}
+
+	if err := waitForPromotion(); err != nil {
+		return err
+	}
+
+	log.Info("Resuming the recovery paused at the recovery target")
+	_, err := db.Exec("SELECT pg_catalog.pg_wal_replay_resume()")
+	return err
+}
+
+// waitForPromotionRequest marks the recovery of the cluster as paused
+// at the recovery target, and returns only when its promotion has been
+// requested, i.e. by the `promote` command of the `cnpg` plugin
+func waitForPromotionRequest(ctx context.Context, cli client.Client, cluster *apiv1.Cluster) error {
+	contextLogger := log.FromContext(ctx)
+
+	condition := metav1.Condition{
+		Type:   string(apiv1.ConditionRecoveryPaused),
+		Status: metav1.ConditionTrue,
+		Reason: string(apiv1.ConditionReasonRecoveryTargetReached),
+		Message: fmt.Sprintf("The recovery target has been reached, "+
+			"run `kubectl cnpg promote %s` to start the cluster", cluster.Name),
+	}
+	if err := conditions.Patch(ctx, cli, cluster, &condition); err != nil {
+		return fmt.Errorf("while marking the recovery as paused: %w", err)
+	}
+
+	contextLogger.Info("Recovery target reached, waiting for the cluster to be promoted",
+		"targetAction", cluster.GetRecoveryTargetAction())
+
+	errorIsRetriable := func(err error) bool {
+		return err == errPromotionNotRequested
+	}
+
+	return retry.OnError(RetryUntilRecoveryDone, errorIsRetriable, func() error {
+		var currentCluster apiv1.Cluster
+		if err := cli.Get(ctx, client.ObjectKeyFromObject(cluster), &currentCluster); err != nil {
+			contextLogger.Warning("Error while checking if the promotion has been requested", "err", err)
+			return errPromotionNotRequested
+		}
+
+		condition := meta.FindStatusCondition(currentCluster.Status.Conditions, string(apiv1.ConditionRecoveryPaused))
+		if condition == nil || condition.Reason != string(apiv1.ConditionReasonPromotionRequested) {
+			return errPromotionNotRequested
+		}
+
+		contextLogger.Info("Promotion requested, ending the recovery")
+		return nil
+	})
+}
+
+// validateRecoveredInstance runs the validation queries of the recovered
+// data, if any, in the application database of the running instance
+func (info InitInfo) validateRecoveredInstance(
+	ctx context.Context,
+	instance *Instance,
+	cluster *apiv1.Cluster,
+	superUserDB *sql.DB,
+) error {
+	if cluster.Spec.Bootstrap == nil || cluster.Spec.Bootstrap.Recovery == nil ||
+		len(cluster.Spec.Bootstrap.Recovery.ValidationSQL) == 0 {
+		return nil
+	}
+
+	typedClient, err := management.NewControllerRuntimeClient()
+	if err != nil {
+		return err
+	}
+
+	// The application database may not be part of the recovered data,
+	// as it is created later when missing
+	dbName := "postgres"
+	if appDBName := cluster.GetApplicationDatabaseName(); appDBName != "" {
+		var exists bool
+		row := superUserDB.QueryRowContext(ctx,
+			"SELECT COUNT(*) > 0 FROM pg_catalog.pg_database WHERE datname = $1", appDBName)
+		if err := row.Scan(&exists); err != nil {
+			return fmt.Errorf("while checking for the application database: %w", err)
+		}
+		if exists {
+			dbName = appDBName
+		}
+	}
+
+	db, err := instance.ConnectionPool().Connection(dbName)
+	if err != nil {
+		return err
+	}
+
+	return validateRecovery(ctx, typedClient, cluster, db)
+}
+
+// validateRecovery runs the validation queries of the recovered data. When
+// one of them fails, the cluster is moved to the `PhaseRecoveryValidationFailed`
+// phase and the function returns only once the context is cancelled, keeping
+// the instance running for inspection
+func validateRecovery(ctx context.Context, cli client.Client, cluster *apiv1.Cluster, db *sql.DB) error {
+	contextLogger := log.FromContext(ctx)
+
+	err := runValidationQueries(ctx, db, cluster.Spec.Bootstrap.Recovery.ValidationSQL)
+	if err == nil {
+		contextLogger.Info("Recovered data successfully validated")
+		return nil
+	}
+
+	contextLogger.Error(err, "Recovered data validation failed, the cluster will not be started")
+	if err := status.RegisterPhase(
+		ctx,
+		cli,
+		cluster,
+		apiv1.PhaseRecoveryValidationFailed,
+		err.Error(),
+	); err != nil {
+		contextLogger.Error(err, "while registering the recovery validation failure")
+	}
+
+	<-ctx.Done()
+	return err
+}
+
+// runValidationQueries runs every query in its own read-only transaction,
+// failing if the query raises an error or if the first column of its
+// first row is false
+func runValidationQueries(ctx context.Context, db *sql.DB, queries []string) error {
+	for idx, query := range queries {
+		if err := runValidationQuery(ctx, db, query); err != nil {
+			return fmt.Errorf("validation query #%d failed: %w", idx+1, err)
+		}
+	}
+
+	return nil
+}
+
+func runValidationQuery(ctx context.Context, db *sql.DB, query string) error {
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// The query is run via the extended query protocol, which rejects
+	// multiple statements: the transaction cannot be made read-write
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	if rows.Next() {
+		columns, err := rows.Columns()
+		if err != nil {
+			return err
+		}
+		values := make([]any, len(columns))
+		for i := range values {
+			values[i] = new(any)
+		}
+		if err := rows.Scan(values...); err != nil {
+			return err
+		}
+		if result, ok := (*values[0].(*any)).(bool); ok && !result {
+			return fmt.Errorf("the query returned false")
+		}
+	}
+
+	return rows.Err()
+}
+
 // restoreViaPlugin tries to restore the cluster using a plugin if available and enabled.
 // Returns true if a restore plugin was found and any error encountered.
 func restoreViaPlugin(
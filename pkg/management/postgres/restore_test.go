@@ -17,14 +17,24 @@ limitations under the License.
 package postgres
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path"
+	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/cloudnative-pg/machinery/pkg/fileutils"
 	"github.com/thoas/go-funk"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/utils/strings/slices"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -204,3 +214,178 @@ var _ = Describe("testing restore InitInfo methods", func() {
 		Expect(enforcedParamsInPGData["max_connections"]).To(Equal(200))
 	})
 })
+
+var _ = Describe("recovery paused at the target", func() {
+	var (
+		cluster         *apiv1.Cluster
+		cli             client.Client
+		originalBackoff wait.Backoff
+	)
+
+	BeforeEach(func() {
+		originalBackoff = RetryUntilRecoveryDone
+		RetryUntilRecoveryDone.Duration = 10 * time.Millisecond
+
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					Recovery: &apiv1.BootstrapRecovery{
+						RecoveryTarget: &apiv1.RecoveryTarget{
+							TargetTime:   "2024-01-01 00:00:00",
+							TargetAction: apiv1.RecoveryTargetActionPause,
+						},
+					},
+				},
+			},
+		}
+		cli = fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(cluster).
+			WithStatusSubresource(cluster).
+			Build()
+	})
+
+	AfterEach(func() {
+		RetryUntilRecoveryDone = originalBackoff
+	})
+
+	It("resumes the recovery only once the promotion has been requested", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+		mock.ExpectQuery("SELECT pg_catalog.pg_is_wal_replay_paused()").
+			WillReturnRows(sqlmock.NewRows([]string{"paused"}).AddRow(false))
+		mock.ExpectQuery("SELECT pg_catalog.pg_is_wal_replay_paused()").
+			WillReturnRows(sqlmock.NewRows([]string{"paused"}).AddRow(true))
+
+		done := make(chan error, 1)
+		go func() {
+			done <- promoteAtRecoveryTarget(db, func() error {
+				return waitForPromotionRequest(ctx, cli, cluster.DeepCopy())
+			})
+		}()
+
+		By("pausing the recovery at the target", func() {
+			Eventually(func(g Gomega) {
+				var current apiv1.Cluster
+				g.Expect(cli.Get(ctx, client.ObjectKeyFromObject(cluster), &current)).To(Succeed())
+				g.Expect(current.IsRecoveryPaused()).To(BeTrue())
+			}).Should(Succeed())
+			Consistently(done, 100*time.Millisecond).ShouldNot(Receive())
+		})
+
+		By("promoting the cluster", func() {
+			mock.ExpectExec("SELECT pg_catalog.pg_wal_replay_resume()").
+				WillReturnResult(sqlmock.NewResult(0, 0))
+
+			var current apiv1.Cluster
+			Expect(cli.Get(ctx, client.ObjectKeyFromObject(cluster), &current)).To(Succeed())
+			Expect(conditions.Patch(ctx, cli, &current, &metav1.Condition{
+				Type:   string(apiv1.ConditionRecoveryPaused),
+				Status: metav1.ConditionFalse,
+				Reason: string(apiv1.ConditionReasonPromotionRequested),
+			})).To(Succeed())
+
+			Eventually(done).Should(Receive(BeNil()))
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+	})
+})
+
+var _ = Describe("recovered data validation", func() {
+	var (
+		cluster *apiv1.Cluster
+		cli     client.Client
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					Recovery: &apiv1.BootstrapRecovery{
+						ValidationSQL: []string{
+							"SELECT count(*) > 0 FROM orders",
+							"SELECT max(id) FROM orders",
+						},
+					},
+				},
+			},
+		}
+		cli = fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(cluster).
+			WithStatusSubresource(cluster).
+			Build()
+	})
+
+	It("succeeds when every query passes", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT count").
+			WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(true))
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT max").
+			WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(42))
+		mock.ExpectRollback()
+
+		Expect(validateRecovery(ctx, cli, cluster.DeepCopy(), db)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+
+		var current apiv1.Cluster
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(cluster), &current)).To(Succeed())
+		Expect(current.Status.Phase).To(BeEmpty())
+	})
+
+	It("fails when a query returns false", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT count").
+			WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(false))
+		mock.ExpectRollback()
+
+		err = runValidationQueries(ctx, db, cluster.Spec.Bootstrap.Recovery.ValidationSQL)
+		Expect(err).To(MatchError(ContainSubstring("validation query #1 failed")))
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("blocks the cluster startup when a query fails", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT count").
+			WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(true))
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT max").
+			WillReturnError(errors.New(`relation "orders" does not exist`))
+		mock.ExpectRollback()
+
+		validationCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		done := make(chan error, 1)
+		go func() {
+			done <- validateRecovery(validationCtx, cli, cluster.DeepCopy(), db)
+		}()
+
+		By("moving the cluster to the validation failed phase", func() {
+			Eventually(func(g Gomega) {
+				var current apiv1.Cluster
+				g.Expect(cli.Get(ctx, client.ObjectKeyFromObject(cluster), &current)).To(Succeed())
+				g.Expect(current.Status.Phase).To(Equal(apiv1.PhaseRecoveryValidationFailed))
+				g.Expect(current.Status.PhaseReason).To(ContainSubstring("validation query #2 failed"))
+			}).Should(Succeed())
+			Consistently(done, 100*time.Millisecond).ShouldNot(Receive())
+		})
+
+		By("not starting the cluster once the job is terminated", func() {
+			cancel()
+			Eventually(done).Should(Receive(MatchError(ContainSubstring("orders"))))
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+	})
+})
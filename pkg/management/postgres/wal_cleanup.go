@@ -0,0 +1,233 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	"github.com/cloudnative-pg/machinery/pkg/types"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	// archiveStatusDirectory is the directory, inside pg_wal, where
+	// PostgreSQL keeps track of the archiving status of the WAL files
+	archiveStatusDirectory = "archive_status"
+
+	// minRecoveryTimelineKey is the pg_controldata entry containing the
+	// timeline pg_rewind aligned the instance to
+	minRecoveryTimelineKey = "Min recovery ending loc's timeline"
+
+	// walSegmentSizeKey is the pg_controldata entry containing the
+	// size of the WAL segments
+	walSegmentSizeKey = "Bytes per WAL segment"
+)
+
+// CleanupDivergedWAL removes from the WAL directory the files that don't
+// belong to the history of the timeline this instance has been aligned to
+// by pg_rewind. It returns the timeline and the list of the removed files
+func (instance *Instance) CleanupDivergedWAL(ctx context.Context) (int, []string, error) {
+	contextLogger := log.FromContext(ctx)
+
+	pgControlDataString, err := instance.GetPgControldata()
+	if err != nil {
+		return 0, nil, err
+	}
+	pgControlData := utils.ParsePgControldataOutput(pgControlDataString)
+
+	timeline, err := strconv.Atoi(pgControlData[minRecoveryTimelineKey])
+	if err != nil {
+		return 0, nil, fmt.Errorf("wrong '%s' pg_controldata value: '%s' %w",
+			minRecoveryTimelineKey, pgControlData[minRecoveryTimelineKey], err)
+	}
+	if timeline == 0 {
+		// pg_rewind has not been executed or had nothing to do
+		return 0, nil, nil
+	}
+
+	walSegmentSize, err := strconv.ParseInt(pgControlData[walSegmentSizeKey], 10, 64)
+	if err != nil {
+		return 0, nil, fmt.Errorf("wrong '%s' pg_controldata value: '%s' %w",
+			walSegmentSizeKey, pgControlData[walSegmentSizeKey], err)
+	}
+
+	walDirectory := path.Join(instance.PgData, pgWalDirectory)
+	removedFiles, err := cleanupDivergedWAL(walDirectory, int32(timeline), walSegmentSize) //nolint:gosec
+	if err != nil {
+		return timeline, removedFiles, err
+	}
+
+	if len(removedFiles) > 0 {
+		contextLogger.Info("Removed the WAL files of the diverged timeline",
+			"timeline", timeline,
+			"removedFiles", removedFiles)
+	}
+
+	return timeline, removedFiles, nil
+}
+
+// cleanupDivergedWAL removes, from the passed WAL directory, every WAL
+// segment, partial WAL file, and timeline history file that is not part of
+// the history of the passed timeline.
+// The files that are still waiting to be archived are kept: PostgreSQL
+// archives them, as they are named after the diverged timeline, and then
+// recycles them by itself. The archive status files are never touched,
+// as they are managed by PostgreSQL only
+func cleanupDivergedWAL(walDirectory string, timeline int32, walSegmentSize int64) ([]string, error) {
+	switchPoints, err := readTimelineSwitchPoints(walDirectory, timeline)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(walDirectory)
+	if err != nil {
+		return nil, err
+	}
+
+	var removedFiles []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		name := entry.Name()
+		if !isDivergedWALFile(name, timeline, switchPoints, walSegmentSize) {
+			continue
+		}
+
+		waitingForArchiving, err := isWaitingForArchiving(walDirectory, name)
+		if err != nil {
+			return removedFiles, err
+		}
+		if waitingForArchiving {
+			continue
+		}
+
+		if err := os.Remove(path.Join(walDirectory, name)); err != nil && !os.IsNotExist(err) {
+			return removedFiles, err
+		}
+		removedFiles = append(removedFiles, name)
+	}
+
+	return removedFiles, nil
+}
+
+// isDivergedWALFile checks whether the passed file in the WAL directory
+// doesn't belong to the timeline history of the instance
+func isDivergedWALFile(
+	name string,
+	timeline int32,
+	switchPoints map[int32]int64,
+	walSegmentSize int64,
+) bool {
+	if !postgres.WALRe.MatchString(name) {
+		return false
+	}
+
+	fileTimeline, err := strconv.ParseInt(name[:8], 16, 32)
+	if err != nil {
+		return false
+	}
+	tli := int32(fileTimeline)
+
+	if tli == timeline {
+		return false
+	}
+
+	switchPoint, isAncestor := switchPoints[tli]
+	if tli > timeline || !isAncestor {
+		return true
+	}
+
+	if strings.HasSuffix(name, ".history") || strings.HasSuffix(name, ".backup") || len(name) < 24 {
+		return false
+	}
+
+	segment, err := postgres.SegmentFromName(name[:24])
+	if err != nil {
+		return false
+	}
+	segmentStart := int64(segment.Log)<<32 + int64(segment.Seg)*walSegmentSize
+
+	// A segment entirely written after the timeline switch only contains diverged WAL,
+	// while the one containing the switch point is still needed for recovery
+	return segmentStart >= switchPoint
+}
+
+// readTimelineSwitchPoints parses the history file of the passed timeline,
+// returning a map from every ancestor timeline to the LSN where the
+// history switched away from it
+func readTimelineSwitchPoints(walDirectory string, timeline int32) (map[int32]int64, error) {
+	switchPoints := make(map[int32]int64)
+	if timeline <= 1 {
+		return switchPoints, nil
+	}
+
+	historyFile, err := os.Open(path.Join(walDirectory, fmt.Sprintf("%08X.history", timeline))) // #nosec
+	if err != nil {
+		return nil, fmt.Errorf("while reading the history of timeline %d: %w", timeline, err)
+	}
+	defer func() {
+		_ = historyFile.Close()
+	}()
+
+	scanner := bufio.NewScanner(historyFile)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		tli, err := strconv.ParseInt(fields[0], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeline in history file: %q", scanner.Text())
+		}
+
+		switchPoint, err := types.LSN(fields[1]).Parse()
+		if err != nil {
+			return nil, fmt.Errorf("invalid switch point in history file: %q", scanner.Text())
+		}
+
+		switchPoints[int32(tli)] = switchPoint
+	}
+
+	return switchPoints, scanner.Err()
+}
+
+// isWaitingForArchiving checks whether PostgreSQL still needs to archive the
+// passed WAL file
+func isWaitingForArchiving(walDirectory string, name string) (bool, error) {
+	_, err := os.Stat(path.Join(walDirectory, archiveStatusDirectory, name+".ready"))
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, os.ErrNotExist):
+		return false, nil
+	default:
+		return false, err
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"os"
+	"path"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("cleanupDivergedWAL", func() {
+	var walDirectory string
+
+	createFiles := func(names ...string) {
+		for _, name := range names {
+			Expect(os.WriteFile(path.Join(walDirectory, name), []byte{}, 0o600)).To(Succeed())
+		}
+	}
+
+	BeforeEach(func() {
+		walDirectory = GinkgoT().TempDir()
+		Expect(os.Mkdir(path.Join(walDirectory, archiveStatusDirectory), 0o700)).To(Succeed())
+	})
+
+	It("removes the WAL files written after the timeline switch", func() {
+		createFiles(
+			"000000010000000000000004",
+			"000000010000000000000005",
+			"000000010000000000000006",
+			"000000010000000000000007",
+			"000000010000000000000003.00000028.backup",
+			"000000020000000000000005",
+			"00000003.history",
+			"000000030000000000000007.partial",
+			"archive_status/000000010000000000000004.done",
+			"archive_status/000000010000000000000005.ready",
+			"archive_status/000000010000000000000006.done",
+			"archive_status/000000010000000000000007.ready",
+		)
+		Expect(os.WriteFile(
+			path.Join(walDirectory, "00000002.history"),
+			[]byte("1\t0/5000060\tno recovery target specified\n"),
+			0o600)).To(Succeed())
+
+		removedFiles, err := cleanupDivergedWAL(walDirectory, 2, postgres.DefaultWALSegmentSize)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(removedFiles).To(ConsistOf(
+			"000000010000000000000006",
+			"00000003.history",
+			"000000030000000000000007.partial",
+		))
+
+		for _, name := range []string{
+			"000000010000000000000004",
+			"000000010000000000000005",
+			"000000010000000000000003.00000028.backup",
+			"000000020000000000000005",
+			"00000002.history",
+		} {
+			Expect(path.Join(walDirectory, name)).To(BeAnExistingFile())
+		}
+		for _, name := range []string{
+			"000000010000000000000006",
+			"00000003.history",
+			"000000030000000000000007.partial",
+		} {
+			Expect(path.Join(walDirectory, name)).ToNot(BeAnExistingFile())
+		}
+	})
+
+	It("keeps the diverged WAL files that are still waiting to be archived", func() {
+		createFiles(
+			"000000010000000000000007",
+			"archive_status/000000010000000000000006.done",
+			"archive_status/000000010000000000000007.ready",
+		)
+		Expect(os.WriteFile(
+			path.Join(walDirectory, "00000002.history"),
+			[]byte("1\t0/5000060\tno recovery target specified\n"),
+			0o600)).To(Succeed())
+
+		removedFiles, err := cleanupDivergedWAL(walDirectory, 2, postgres.DefaultWALSegmentSize)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(removedFiles).To(BeEmpty())
+
+		// PostgreSQL owns the archive status, which is never changed
+		for _, name := range []string{
+			"000000010000000000000007",
+			"archive_status/000000010000000000000006.done",
+			"archive_status/000000010000000000000007.ready",
+		} {
+			Expect(path.Join(walDirectory, name)).To(BeAnExistingFile())
+		}
+	})
+
+	It("removes the files of the timelines that are not ancestors", func() {
+		createFiles(
+			"000000010000000000000002",
+			"00000002.history",
+			"000000020000000000000004",
+			"000000030000000000000004",
+		)
+		Expect(os.WriteFile(
+			path.Join(walDirectory, "00000003.history"),
+			[]byte("1\t0/3000000\tno recovery target specified\n"),
+			0o600)).To(Succeed())
+
+		removedFiles, err := cleanupDivergedWAL(walDirectory, 3, postgres.DefaultWALSegmentSize)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(removedFiles).To(ConsistOf("00000002.history", "000000020000000000000004"))
+	})
+
+	It("keeps every file on the first timeline", func() {
+		createFiles("000000010000000000000001", "000000010000000000000002")
+
+		removedFiles, err := cleanupDivergedWAL(walDirectory, 1, postgres.DefaultWALSegmentSize)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(removedFiles).To(BeEmpty())
+	})
+
+	It("fails when the history file of the timeline is missing", func() {
+		_, err := cleanupDivergedWAL(walDirectory, 2, postgres.DefaultWALSegmentSize)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,169 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+	pgTime "github.com/cloudnative-pg/machinery/pkg/postgres/time"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources/status"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// requestSwitchover is invoked by the preStop hook of the instances, and
+// hands over the primary role to the most aligned healthy replica before
+// PostgreSQL is stopped. The response status is `202 Accepted` when a
+// switchover has been requested, and the body contains the name of the
+// new primary, whose promotion the caller is expected to wait for
+func (ws *localWebserverEndpoints) requestSwitchover(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := r.Context()
+	podName := ws.instance.GetPodName()
+	contextLogger := log.WithValues("podName", podName)
+
+	var cluster apiv1.Cluster
+	if err := ws.typedClient.Get(ctx, client.ObjectKey{
+		Namespace: ws.instance.GetNamespaceName(),
+		Name:      ws.instance.GetClusterName(),
+	}, &cluster); err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("error while getting cluster: %v", err.Error()),
+			http.StatusInternalServerError)
+		return
+	}
+
+	if reason := getSwitchoverOnShutdownSkipReason(&cluster, podName); reason != "" {
+		contextLogger.Info("Not requesting a switchover before shutting down", "reason", reason)
+		_, _ = fmt.Fprint(w, reason)
+		return
+	}
+
+	instanceStatus, err := ws.instance.GetStatus()
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("error while getting the instance status: %v", err.Error()),
+			http.StatusInternalServerError)
+		return
+	}
+
+	targetPrimary := selectSwitchoverTarget(&cluster, instanceStatus.ReplicationInfo)
+	if targetPrimary == "" {
+		contextLogger.Info("No healthy replica to switch over to, PostgreSQL will be stopped with a fast shutdown")
+		ws.instance.FastShutdownRequested.Store(true)
+		_, _ = fmt.Fprint(w, "no healthy replica to switch over to")
+		return
+	}
+
+	origCluster := cluster.DeepCopy()
+	cluster.Status.TargetPrimary = targetPrimary
+	cluster.Status.TargetPrimaryTimestamp = pgTime.GetCurrentTimestamp()
+	if err := status.RegisterPhaseWithOrigCluster(
+		ctx,
+		ws.typedClient,
+		&cluster,
+		origCluster,
+		apiv1.PhaseSwitchover,
+		fmt.Sprintf("Switching over to %v before stopping %v", targetPrimary, podName),
+	); err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("error while requesting the switchover: %v", err.Error()),
+			http.StatusInternalServerError)
+		return
+	}
+
+	contextLogger.Info("Switchover requested before shutting down", "targetPrimary", targetPrimary)
+	ws.eventRecorder.Eventf(&cluster, "Normal", "SwitchoverOnShutdown",
+		"Primary %v is being stopped, switching over to %v", podName, targetPrimary)
+
+	w.WriteHeader(http.StatusAccepted)
+	_, _ = fmt.Fprint(w, targetPrimary)
+}
+
+// getSwitchoverOnShutdownSkipReason returns why the instance doesn't need to
+// hand over the primary role before being stopped, or an empty string
+// if it does
+func getSwitchoverOnShutdownSkipReason(cluster *apiv1.Cluster, podName string) string {
+	switch {
+	case !cluster.IsSwitchoverOnShutdownEnabled():
+		return "switchover on shutdown is not enabled"
+
+	case !cluster.DeletionTimestamp.IsZero():
+		return "the cluster is being deleted"
+
+	case cluster.Annotations[utils.HibernationAnnotationName] == string(utils.HibernationAnnotationValueOn):
+		return "the cluster is being hibernated"
+
+	case cluster.Status.CurrentPrimary != podName || cluster.Status.TargetPrimary != podName:
+		return "the instance is not the primary"
+
+	case cluster.Status.Phase != apiv1.PhaseHealthy:
+		// The operator is already working on the cluster, i.e. during
+		// a rolling update, and is the one stopping this instance
+		return fmt.Sprintf("the cluster is not healthy (phase: %v)", cluster.Status.Phase)
+	}
+
+	return ""
+}
+
+// selectSwitchoverTarget chooses the healthy replica, streaming from the
+// primary, with the lowest replay lag. When `.spec.switchoverMaxLagBytes` is
+// set, the replicas lagging more than that are discarded. An empty string is
+// returned if there is no suitable replica
+func selectSwitchoverTarget(cluster *apiv1.Cluster, replicationInfo postgres.PgStatReplicationList) string {
+	healthyInstances := cluster.Status.InstancesStatus[apiv1.PodHealthy]
+
+	var candidates postgres.PgStatReplicationList
+	for _, replica := range replicationInfo {
+		if replica.State != "streaming" || !slices.Contains(healthyInstances, replica.ApplicationName) {
+			continue
+		}
+
+		if maxLag := cluster.Spec.SwitchoverMaxLagBytes; maxLag != nil && replica.ReplayLagBytes > *maxLag {
+			continue
+		}
+
+		candidates = append(candidates, replica)
+	}
+
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].ReplayLagBytes != candidates[j].ReplayLagBytes {
+			return candidates[i].ReplayLagBytes < candidates[j].ReplayLagBytes
+		}
+		return candidates[i].ApplicationName < candidates[j].ApplicationName
+	})
+
+	return candidates[0].ApplicationName
+}
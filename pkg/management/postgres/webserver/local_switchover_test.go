@@ -0,0 +1,111 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("switchover on shutdown", func() {
+	var cluster *apiv1.Cluster
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				SwitchoverOnShutdown: &apiv1.SwitchoverOnShutdownConfiguration{Enabled: true},
+			},
+			Status: apiv1.ClusterStatus{
+				Phase:          apiv1.PhaseHealthy,
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+				InstancesStatus: map[apiv1.PodStatus][]string{
+					apiv1.PodHealthy: {"cluster-example-1", "cluster-example-2", "cluster-example-3"},
+				},
+			},
+		}
+	})
+
+	Context("deciding whether the switchover is needed", func() {
+		It("requests it when the primary of a healthy cluster is stopped", func() {
+			Expect(getSwitchoverOnShutdownSkipReason(cluster, "cluster-example-1")).To(BeEmpty())
+		})
+
+		It("doesn't request it when the option is disabled", func() {
+			cluster.Spec.SwitchoverOnShutdown.Enabled = false
+			Expect(getSwitchoverOnShutdownSkipReason(cluster, "cluster-example-1")).ToNot(BeEmpty())
+		})
+
+		It("doesn't request it for the replicas", func() {
+			Expect(getSwitchoverOnShutdownSkipReason(cluster, "cluster-example-2")).ToNot(BeEmpty())
+		})
+
+		It("doesn't request it while a switchover is already in progress", func() {
+			cluster.Status.TargetPrimary = "cluster-example-2"
+			Expect(getSwitchoverOnShutdownSkipReason(cluster, "cluster-example-1")).ToNot(BeEmpty())
+		})
+
+		It("doesn't request it when the operator is stopping the instance", func() {
+			cluster.Status.Phase = apiv1.PhaseUpgrade
+			Expect(getSwitchoverOnShutdownSkipReason(cluster, "cluster-example-1")).ToNot(BeEmpty())
+		})
+
+		It("doesn't request it when the cluster is being hibernated", func() {
+			cluster.Annotations = map[string]string{
+				utils.HibernationAnnotationName: string(utils.HibernationAnnotationValueOn),
+			}
+			Expect(getSwitchoverOnShutdownSkipReason(cluster, "cluster-example-1")).ToNot(BeEmpty())
+		})
+	})
+
+	Context("choosing the new primary", func() {
+		It("chooses the healthy streaming replica with the lowest replay lag", func() {
+			replicationInfo := postgres.PgStatReplicationList{
+				{ApplicationName: "cluster-example-2", State: "streaming", ReplayLagBytes: 2048},
+				{ApplicationName: "cluster-example-3", State: "streaming", ReplayLagBytes: 1024},
+				{ApplicationName: "cluster-example-4", State: "streaming", ReplayLagBytes: 0},
+			}
+			Expect(selectSwitchoverTarget(cluster, replicationInfo)).To(Equal("cluster-example-3"))
+		})
+
+		It("skips the replicas which are not streaming", func() {
+			replicationInfo := postgres.PgStatReplicationList{
+				{ApplicationName: "cluster-example-2", State: "streaming", ReplayLagBytes: 2048},
+				{ApplicationName: "cluster-example-3", State: "catchup", ReplayLagBytes: 1024},
+			}
+			Expect(selectSwitchoverTarget(cluster, replicationInfo)).To(Equal("cluster-example-2"))
+		})
+
+		It("skips the replicas lagging more than the switchover max lag", func() {
+			cluster.Spec.SwitchoverMaxLagBytes = ptr.To(int64(1024))
+			replicationInfo := postgres.PgStatReplicationList{
+				{ApplicationName: "cluster-example-2", State: "streaming", ReplayLagBytes: 2048},
+			}
+			Expect(selectSwitchoverTarget(cluster, replicationInfo)).To(BeEmpty())
+		})
+
+		It("returns an empty string when there are no replicas", func() {
+			Expect(selectSwitchoverTarget(cluster, nil)).To(BeEmpty())
+		})
+	})
+})
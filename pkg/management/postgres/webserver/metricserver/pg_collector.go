@@ -68,11 +68,17 @@ type metrics struct {
 	PgWALDirectory               *prometheus.GaugeVec
 	PgVersion                    *prometheus.GaugeVec
 	FirstRecoverabilityPoint     prometheus.Gauge
+	RecoveryWindowSeconds        prometheus.Gauge
+	PreparedTransactions         prometheus.Gauge
 	LastAvailableBackupTimestamp prometheus.Gauge
 	LastFailedBackupTimestamp    prometheus.Gauge
 	FencingOn                    prometheus.Gauge
+	ConnectionsExhausted         prometheus.Gauge
 	PgStatWalMetrics             PgStatWalMetrics
 	NodesUsed                    prometheus.Gauge
+	IntegrityCheckErrors         prometheus.Gauge
+	DatabaseXIDAge               *prometheus.GaugeVec
+	DatabaseMXIDAge              *prometheus.GaugeVec
 }
 
 // PgStatWalMetrics is available from PG14+
@@ -175,6 +181,18 @@ func newMetrics() *metrics {
 			Name:      "first_recoverability_point",
 			Help:      "The first point of recoverability for the cluster as a unix timestamp",
 		}),
+		RecoveryWindowSeconds: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Name:      "recovery_window_seconds",
+			Help: "The number of seconds between the first point of recoverability and now. " +
+				"A value of '0' suggests that no point of recoverability is available yet.",
+		}),
+		PreparedTransactions: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Name:      "prepared_transactions",
+			Help: "The number of transactions prepared for two-phase commit on the primary. " +
+				"A value of '-1' suggests that the metric is not available.",
+		}),
 		LastAvailableBackupTimestamp: prometheus.NewGauge(prometheus.GaugeOpts{
 			Namespace: PrometheusNamespace,
 			Subsystem: subsystem,
@@ -193,6 +211,14 @@ func newMetrics() *metrics {
 			Name:      "fencing_on",
 			Help:      "1 if the instance is fenced, 0 otherwise",
 		}),
+		ConnectionsExhausted: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "connections_exhausted",
+			Help: "1 if the client connections reached max_connections minus the slots " +
+				"reserved to superusers, 0 otherwise. " +
+				"A value of '-1' suggests that the metric is not available.",
+		}),
 		NodesUsed: prometheus.NewGauge(prometheus.GaugeOpts{
 			Namespace: PrometheusNamespace,
 			Subsystem: subsystem,
@@ -203,6 +229,24 @@ func newMetrics() *metrics {
 				"implying the absence of High Availability (HA). Ideally this value " +
 				"should match the number of instances in the cluster.",
 		}),
+		IntegrityCheckErrors: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Name:      "integrity_check_errors",
+			Help: "The number of corruption errors detected by the last integrity check " +
+				"of the data files. A value of '-1' suggests that no integrity check has been executed.",
+		}),
+		DatabaseXIDAge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Name:      "database_xid_age",
+			Help: "The age of the oldest unfrozen transaction ID of the database. " +
+				"Only available on the primary",
+		}, []string{"datname"}),
+		DatabaseMXIDAge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Name:      "database_mxid_age",
+			Help: "The age of the oldest unfrozen multixact ID of the database. " +
+				"Only available on the primary",
+		}, []string{"datname"}),
 		PgStatWalMetrics: PgStatWalMetrics{
 			WalRecords: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 				Namespace: PrometheusNamespace,
@@ -277,10 +321,16 @@ func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
 	e.Metrics.PgWALDirectory.Describe(ch)
 	e.Metrics.PgVersion.Describe(ch)
 	e.Metrics.FirstRecoverabilityPoint.Describe(ch)
+	e.Metrics.RecoveryWindowSeconds.Describe(ch)
+	e.Metrics.PreparedTransactions.Describe(ch)
 	e.Metrics.FencingOn.Describe(ch)
+	e.Metrics.ConnectionsExhausted.Describe(ch)
 	e.Metrics.LastFailedBackupTimestamp.Describe(ch)
 	e.Metrics.LastAvailableBackupTimestamp.Describe(ch)
 	e.Metrics.NodesUsed.Describe(ch)
+	e.Metrics.IntegrityCheckErrors.Describe(ch)
+	e.Metrics.DatabaseXIDAge.Describe(ch)
+	e.Metrics.DatabaseMXIDAge.Describe(ch)
 
 	if e.queries != nil {
 		e.queries.Describe(ch)
@@ -315,10 +365,16 @@ func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
 	e.Metrics.PgWALDirectory.Collect(ch)
 	e.Metrics.PgVersion.Collect(ch)
 	e.Metrics.FirstRecoverabilityPoint.Collect(ch)
+	e.Metrics.RecoveryWindowSeconds.Collect(ch)
+	e.Metrics.PreparedTransactions.Collect(ch)
 	e.Metrics.FencingOn.Collect(ch)
+	e.Metrics.ConnectionsExhausted.Collect(ch)
 	e.Metrics.LastFailedBackupTimestamp.Collect(ch)
 	e.Metrics.LastAvailableBackupTimestamp.Collect(ch)
 	e.Metrics.NodesUsed.Collect(ch)
+	e.Metrics.IntegrityCheckErrors.Collect(ch)
+	e.Metrics.DatabaseXIDAge.Collect(ch)
+	e.Metrics.DatabaseMXIDAge.Collect(ch)
 
 	if version, _ := e.instance.GetPgVersion(); version.Major >= 14 {
 		e.Metrics.PgStatWalMetrics.WalSync.Collect(ch)
@@ -386,6 +442,8 @@ func (e *Exporter) collectPgMetrics(ch chan<- prometheus.Metric) {
 	}
 
 	e.collectNodesUsed()
+	e.collectIntegrityCheckErrors()
+	e.collectConnectionsExhausted(db)
 
 	// metrics collected only on primary server
 	if isPrimary {
@@ -395,10 +453,22 @@ func (e *Exporter) collectPgMetrics(ch chan<- prometheus.Metric) {
 		// getting the first point of recoverability
 		e.collectFromPrimaryFirstPointOnTimeRecovery()
 
+		// getting the width of the recovery window
+		e.collectFromPrimaryRecoveryWindow()
+
 		// getting the last available backup timestamp
 		e.collectFromPrimaryLastAvailableBackupTimestamp()
 
 		e.collectFromPrimaryLastFailedBackupTimestamp()
+
+		// getting the number of transactions prepared for two-phase commit
+		e.collectFromPrimaryPreparedTransactions(db)
+
+		// getting the transaction ID and multixact ID age of the databases
+		e.collectFromPrimaryWraparoundAges(db)
+	} else {
+		e.Metrics.DatabaseXIDAge.Reset()
+		e.Metrics.DatabaseMXIDAge.Reset()
 	}
 
 	if err := collectPGWalArchiveMetric(e); err != nil {
@@ -495,6 +565,26 @@ func (e *Exporter) collectNodesUsed() {
 	e.Metrics.NodesUsed.Set(float64(cluster.Status.Topology.NodesUsed))
 }
 
+func (e *Exporter) collectIntegrityCheckErrors() {
+	const notExecutedValue float64 = -1
+
+	cluster, err := e.getCluster()
+	if err != nil {
+		log.Error(err, "unable to collect metrics")
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues("Collect.IntegrityCheckErrors").Inc()
+		e.Metrics.IntegrityCheckErrors.Set(notExecutedValue)
+		return
+	}
+
+	if cluster.Status.IntegrityCheck == nil {
+		e.Metrics.IntegrityCheckErrors.Set(notExecutedValue)
+		return
+	}
+
+	e.Metrics.IntegrityCheckErrors.Set(float64(cluster.Status.IntegrityCheck.Errors))
+}
+
 func (e *Exporter) collectFromPrimaryLastFailedBackupTimestamp() {
 	const errorLabel = "Collect.LastFailedBackupTimestamp"
 	e.setTimestampMetric(e.Metrics.LastFailedBackupTimestamp, errorLabel, func(cluster *apiv1.Cluster) string {
@@ -516,6 +606,89 @@ func (e *Exporter) collectFromPrimaryFirstPointOnTimeRecovery() {
 	})
 }
 
+func (e *Exporter) collectFromPrimaryRecoveryWindow() {
+	cluster, err := e.getCluster()
+	if err != nil {
+		if !errors.Is(err, cache.ErrCacheMiss) {
+			log.Error(err, "error while retrieving cluster cache object")
+			e.Metrics.Error.Set(1)
+			e.Metrics.PgCollectionErrors.WithLabelValues("Collect.RecoveryWindow").Inc()
+		}
+		e.Metrics.RecoveryWindowSeconds.Set(0)
+		return
+	}
+
+	// a cluster without backups has no recovery window
+	if cluster.Status.FirstRecoverabilityPoint == "" {
+		e.Metrics.RecoveryWindowSeconds.Set(0)
+		return
+	}
+
+	firstRecoverabilityPoint, err := time.Parse(time.RFC3339, cluster.Status.FirstRecoverabilityPoint)
+	if err != nil {
+		log.Error(err, "while collecting the recovery window")
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues("Collect.RecoveryWindow").Inc()
+		e.Metrics.RecoveryWindowSeconds.Set(0)
+		return
+	}
+
+	e.Metrics.RecoveryWindowSeconds.Set(time.Since(firstRecoverabilityPoint).Seconds())
+}
+
+func (e *Exporter) collectFromPrimaryPreparedTransactions(db *sql.DB) {
+	var preparedTransactions int
+	if err := db.QueryRow("SELECT count(*) FROM pg_catalog.pg_prepared_xacts").
+		Scan(&preparedTransactions); err != nil {
+		log.Error(err, "unable to collect metrics")
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues("Collect.PreparedTransactions").Inc()
+		e.Metrics.PreparedTransactions.Set(-1)
+		return
+	}
+
+	e.Metrics.PreparedTransactions.Set(float64(preparedTransactions))
+}
+
+func (e *Exporter) collectFromPrimaryWraparoundAges(db *sql.DB) {
+	e.Metrics.DatabaseXIDAge.Reset()
+	e.Metrics.DatabaseMXIDAge.Reset()
+
+	ages, err := postgres.GetWraparoundAges(db)
+	if err != nil {
+		log.Error(err, "unable to collect metrics")
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues("Collect.WraparoundAges").Inc()
+		return
+	}
+
+	for _, age := range ages {
+		e.Metrics.DatabaseXIDAge.WithLabelValues(age.Database).Set(float64(age.XIDAge))
+		e.Metrics.DatabaseMXIDAge.WithLabelValues(age.Database).Set(float64(age.MXIDAge))
+	}
+}
+
+func (e *Exporter) collectConnectionsExhausted(db *sql.DB) {
+	clientConnections, maxClientConnections, err := postgres.GetClientConnections(db)
+	if err != nil {
+		log.Error(err, "unable to collect metrics")
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues("Collect.ConnectionsExhausted").Inc()
+		e.Metrics.ConnectionsExhausted.Set(-1)
+		return
+	}
+
+	status := postgresconf.PostgresqlStatus{
+		ClientConnections:    clientConnections,
+		MaxClientConnections: maxClientConnections,
+	}
+	if status.AreConnectionsExhausted() {
+		e.Metrics.ConnectionsExhausted.Set(1)
+		return
+	}
+	e.Metrics.ConnectionsExhausted.Set(0)
+}
+
 func (e *Exporter) collectFromPrimarySynchronousStandbysNumber(db *sql.DB) {
 	nStandbys, err := getRequestedSynchronousStandbysNumber(db)
 	if err != nil {
@@ -137,6 +137,74 @@ var _ = Describe("test metrics parsing", func() {
 		}
 	})
 
+	It("reports the number of prepared transactions", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		rows := sqlmock.NewRows([]string{"count"}).AddRow(3)
+		mock.ExpectQuery("SELECT count(*) FROM pg_catalog.pg_prepared_xacts").WillReturnRows(rows)
+
+		exporter.collectFromPrimaryPreparedTransactions(db)
+
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(exporter.Metrics.PreparedTransactions)
+		metrics, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+
+		preparedTransactionsMetric := getMetric(metrics, "cnpg_prepared_transactions")
+		Expect(preparedTransactionsMetric).ToNot(BeNil())
+		Expect(preparedTransactionsMetric.GetMetric()[0].GetGauge().GetValue()).To(BeEquivalentTo(3))
+	})
+
+	It("reports the transaction ID and multixact ID age of every database", func() {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		rows := sqlmock.NewRows([]string{"datname", "age", "mxid_age"}).
+			AddRow("postgres", 1200, 30).
+			AddRow("app", 1500000000, 45)
+		mock.ExpectQuery(".*datfrozenxid.*").WillReturnRows(rows)
+
+		exporter.collectFromPrimaryWraparoundAges(db)
+
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(exporter.Metrics.DatabaseXIDAge, exporter.Metrics.DatabaseMXIDAge)
+		metrics, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+
+		xidAgeMetric := getMetric(metrics, "cnpg_database_xid_age")
+		Expect(xidAgeMetric).ToNot(BeNil())
+		Expect(xidAgeMetric.GetMetric()).To(HaveLen(2))
+		for _, m := range xidAgeMetric.GetMetric() {
+			if m.GetLabel()[0].GetValue() == "app" {
+				Expect(m.GetGauge().GetValue()).To(BeEquivalentTo(1500000000))
+			}
+		}
+
+		mxidAgeMetric := getMetric(metrics, "cnpg_database_mxid_age")
+		Expect(mxidAgeMetric).ToNot(BeNil())
+		Expect(mxidAgeMetric.GetMetric()).To(HaveLen(2))
+	})
+
+	It("reports when the client connections are exhausted", func() {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		rows := sqlmock.NewRows([]string{"count", "max"}).AddRow(97, 97)
+		mock.ExpectQuery(".*superuser_reserved_connections.*").WillReturnRows(rows)
+
+		exporter.collectConnectionsExhausted(db)
+
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(exporter.Metrics.ConnectionsExhausted)
+		metrics, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+
+		connectionsExhaustedMetric := getMetric(metrics, "cnpg_collector_connections_exhausted")
+		Expect(connectionsExhaustedMetric).ToNot(BeNil())
+		Expect(connectionsExhaustedMetric.GetMetric()[0].GetGauge().GetValue()).To(BeEquivalentTo(1))
+	})
+
 	It("should return an error when encountering unexpected results", func() {
 		By("not matching the synchronous standby names regex", func() {
 			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
@@ -287,6 +355,76 @@ var _ = Describe("test metrics parsing", func() {
 			Expect(pgCollectionErrorMetric).To(BeNil())
 		})
 	})
+
+	Context("collectIntegrityCheckErrors", func() {
+		const integrityCheckErrorsName = "cnpg_integrity_check_errors"
+
+		collect := func(cluster *apiv1.Cluster) float64 {
+			exporter.getCluster = func() (*apiv1.Cluster, error) {
+				return cluster, nil
+			}
+			exporter.collectIntegrityCheckErrors()
+
+			registry := prometheus.NewRegistry()
+			registry.MustRegister(exporter.Metrics.IntegrityCheckErrors)
+			metrics, err := registry.Gather()
+			Expect(err).ToNot(HaveOccurred())
+
+			integrityCheckErrorsMetric := getMetric(metrics, integrityCheckErrorsName)
+			Expect(integrityCheckErrorsMetric).ToNot(BeNil())
+			return integrityCheckErrorsMetric.GetMetric()[0].GetGauge().GetValue()
+		}
+
+		It("should return -1 when no integrity check has been executed", func() {
+			Expect(collect(&apiv1.Cluster{})).To(BeEquivalentTo(-1))
+		})
+
+		It("should return the number of errors detected by the last integrity check", func() {
+			cluster := &apiv1.Cluster{
+				Status: apiv1.ClusterStatus{
+					IntegrityCheck: &apiv1.IntegrityCheckStatus{
+						InstanceName: "cluster-example-3",
+						Method:       "amcheck",
+						Errors:       2,
+					},
+				},
+			}
+			Expect(collect(cluster)).To(BeEquivalentTo(2))
+		})
+	})
+
+	Context("collectFromPrimaryRecoveryWindow", func() {
+		const recoveryWindowName = "cnpg_recovery_window_seconds"
+
+		collect := func(cluster *apiv1.Cluster) float64 {
+			exporter.getCluster = func() (*apiv1.Cluster, error) {
+				return cluster, nil
+			}
+			exporter.collectFromPrimaryRecoveryWindow()
+
+			registry := prometheus.NewRegistry()
+			registry.MustRegister(exporter.Metrics.RecoveryWindowSeconds)
+			metrics, err := registry.Gather()
+			Expect(err).ToNot(HaveOccurred())
+
+			recoveryWindowMetric := getMetric(metrics, recoveryWindowName)
+			Expect(recoveryWindowMetric).ToNot(BeNil())
+			return recoveryWindowMetric.GetMetric()[0].GetGauge().GetValue()
+		}
+
+		It("should return 0 when the cluster has no backups", func() {
+			Expect(collect(&apiv1.Cluster{})).To(BeEquivalentTo(0))
+		})
+
+		It("should return the seconds elapsed since the first point of recoverability", func() {
+			cluster := &apiv1.Cluster{
+				Status: apiv1.ClusterStatus{
+					FirstRecoverabilityPoint: time.Now().Add(-3 * time.Hour).Format(time.RFC3339),
+				},
+			}
+			Expect(collect(cluster)).To(BeNumerically("~", (3 * time.Hour).Seconds(), 5))
+		})
+	})
 })
 
 type nameGetter interface {
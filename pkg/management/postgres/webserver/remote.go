@@ -27,6 +27,7 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"time"
 
 	"github.com/cloudnative-pg/machinery/pkg/execlog"
 	"github.com/cloudnative-pg/machinery/pkg/fileutils"
@@ -90,6 +91,8 @@ func NewRemoteWebServer(
 	serveMux.HandleFunc(url.PathPgModeBackup, endpoints.backup)
 	serveMux.HandleFunc(url.PathHealth, endpoints.isServerHealthy)
 	serveMux.HandleFunc(url.PathReady, endpoints.isServerReady)
+	serveMux.HandleFunc(url.PathRoleHealth,
+		newRoleHealthHandler(endpoints.readinessChecker.IsServerReady, instance.IsPrimary))
 	serveMux.HandleFunc(url.PathPgStatus, endpoints.pgStatus)
 	serveMux.HandleFunc(url.PathPgArchivePartial, endpoints.pgArchivePartial)
 	serveMux.HandleFunc(url.PathPGControlData, endpoints.pgControlData)
@@ -161,6 +164,8 @@ func (ws *remoteWebserverEndpoints) pgStatus(w http.ResponseWriter, _ *http.Requ
 
 	// Marshal the status back to the operator
 	log.Trace("Instance status probe succeeding")
+	currentTime := time.Now()
+	status.CurrentTime = &currentTime
 	js, err := json.Marshal(status)
 	if err != nil {
 		log.Warning(
@@ -0,0 +1,90 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/cloudnative-pg/machinery/pkg/log"
+)
+
+// roleRequirement is the role an instance must have for the
+// role health check to succeed
+type roleRequirement string
+
+const (
+	// roleRequirementPrimary requires the instance to be the primary
+	roleRequirementPrimary roleRequirement = "primary"
+
+	// roleRequirementReplica requires the instance to be a replica
+	roleRequirementReplica roleRequirement = "replica"
+
+	// roleRequirementAny accepts every ready instance
+	roleRequirementAny roleRequirement = "any"
+)
+
+// newRoleHealthHandler creates the handler of the role health check, which
+// succeeds only when the instance is ready and has the role requested
+// via the `require` query parameter. This endpoint is meant to be used
+// by load balancers that are not aware of Kubernetes.
+func newRoleHealthHandler(
+	isReady func(ctx context.Context) error,
+	isPrimary func() (bool, error),
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requirement := roleRequirement(r.URL.Query().Get("require"))
+		if requirement == "" {
+			requirement = roleRequirementAny
+		}
+
+		switch requirement {
+		case roleRequirementPrimary, roleRequirementReplica, roleRequirementAny:
+		default:
+			http.Error(w, fmt.Sprintf("unknown role requirement: %s", requirement), http.StatusBadRequest)
+			return
+		}
+
+		if err := isReady(r.Context()); err != nil {
+			log.Debug("Role health check failing", "err", err.Error())
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+
+		primary, err := isPrimary()
+		if err != nil {
+			log.Debug("Role health check failing", "err", err.Error())
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		role := roleRequirementReplica
+		if primary {
+			role = roleRequirementPrimary
+		}
+
+		if requirement != roleRequirementAny && requirement != role {
+			log.Trace("Role health check failing", "role", role, "require", requirement)
+			http.Error(w, fmt.Sprintf("instance is a %s", role), http.StatusServiceUnavailable)
+			return
+		}
+
+		log.Trace("Role health check succeeding", "role", role, "require", requirement)
+		_, _ = fmt.Fprint(w, "OK")
+	}
+}
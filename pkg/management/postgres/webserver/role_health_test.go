@@ -0,0 +1,74 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("role health check", func() {
+	ready := func(context.Context) error { return nil }
+	notReady := func(context.Context) error { return errors.New("instance is not ready yet") }
+	primary := func() (bool, error) { return true, nil }
+	replica := func() (bool, error) { return false, nil }
+
+	check := func(
+		isReady func(context.Context) error,
+		isPrimary func() (bool, error),
+		query string,
+	) int {
+		server := httptest.NewServer(newRoleHealthHandler(isReady, isPrimary))
+		defer server.Close()
+
+		resp, err := http.Get(server.URL + url.PathRoleHealth + query)
+		Expect(err).ToNot(HaveOccurred())
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		return resp.StatusCode
+	}
+
+	DescribeTable("reports the health of an instance given its role",
+		func(isPrimary func() (bool, error), query string, expectedStatus int) {
+			Expect(check(ready, isPrimary, query)).To(Equal(expectedStatus))
+		},
+		Entry("primary, no requirement", primary, "", http.StatusOK),
+		Entry("primary, any role required", primary, "?require=any", http.StatusOK),
+		Entry("primary, primary required", primary, "?require=primary", http.StatusOK),
+		Entry("primary, replica required", primary, "?require=replica", http.StatusServiceUnavailable),
+		Entry("replica, no requirement", replica, "", http.StatusOK),
+		Entry("replica, any role required", replica, "?require=any", http.StatusOK),
+		Entry("replica, primary required", replica, "?require=primary", http.StatusServiceUnavailable),
+		Entry("replica, replica required", replica, "?require=replica", http.StatusOK),
+	)
+
+	It("fails when the instance is not ready", func() {
+		Expect(check(notReady, primary, "?require=primary")).To(Equal(http.StatusServiceUnavailable))
+	})
+
+	It("rejects unknown role requirements", func() {
+		Expect(check(ready, primary, "?require=leader")).To(Equal(http.StatusBadRequest))
+	})
+})
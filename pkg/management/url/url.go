@@ -37,6 +37,10 @@ const (
 	// PathReady is the URL oath for Ready State
 	PathReady string = "/readyz"
 
+	// PathRoleHealth is the URL path for the role-aware health check
+	// used by external load balancers
+	PathRoleHealth string = "/healthz/role"
+
 	// PathPGControlData is the URL path for PostgreSQL pg_controldata output
 	PathPGControlData string = "/pg/controldata"
 
@@ -46,6 +50,10 @@ const (
 	// PathPgBackup is the URL path for PostgreSQL Backup
 	PathPgBackup string = "/pg/backup"
 
+	// PathPgSwitchover is the URL path used by the primary to request a
+	// switchover before being stopped
+	PathPgSwitchover string = "/pg/switchover"
+
 	// PathPgModeBackup is the URL path to interact with pg_start_backup and pg_stop_backup
 	PathPgModeBackup string = "/pg/mode/backup"
 
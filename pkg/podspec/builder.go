@@ -219,6 +219,33 @@ func (builder *Builder) WithReadinessProbe(name string, readinessProbe *corev1.P
 	return builder
 }
 
+// WithContainerPreStopHook ensures that, if in the current status there is
+// a container with the passed name and the preStop hook is empty, the hook will be
+// set to the one passed.
+// If `overwrite` is true the hook is overwritten even when it's not empty
+func (builder *Builder) WithContainerPreStopHook(
+	name string,
+	handler *corev1.LifecycleHandler,
+	overwrite bool,
+) *Builder {
+	builder.WithContainer(name)
+
+	for idx, value := range builder.status.Spec.Containers {
+		if value.Name != name {
+			continue
+		}
+
+		if value.Lifecycle == nil {
+			builder.status.Spec.Containers[idx].Lifecycle = &corev1.Lifecycle{}
+		}
+		if overwrite || builder.status.Spec.Containers[idx].Lifecycle.PreStop == nil {
+			builder.status.Spec.Containers[idx].Lifecycle.PreStop = handler
+		}
+	}
+
+	return builder
+}
+
 // WithContainerCommand ensures that, if in the current status there is
 // a container with the passed name and the command is empty, the command will be
 // set to the one passed.
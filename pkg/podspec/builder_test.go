@@ -123,6 +123,32 @@ var _ = Describe("Pod template builder", func() {
 		Expect(template.Spec.Containers[0].Command).To(Equal([]string{"toast"}))
 	})
 
+	It("correctly set the container preStop hook when not set", func() {
+		template := New().
+			WithContainerPreStopHook("first", &corev1.LifecycleHandler{
+				Exec: &corev1.ExecAction{Command: []string{"test"}},
+			}, false).
+			WithContainerPreStopHook("first", &corev1.LifecycleHandler{
+				Exec: &corev1.ExecAction{Command: []string{"toast"}},
+			}, false).
+			Build()
+		Expect(template.Spec.Containers[0].Name).To(Equal("first"))
+		Expect(template.Spec.Containers[0].Lifecycle.PreStop.Exec.Command).To(Equal([]string{"test"}))
+	})
+
+	It("correctly override the container preStop hook when set", func() {
+		template := New().
+			WithContainerPreStopHook("first", &corev1.LifecycleHandler{
+				Exec: &corev1.ExecAction{Command: []string{"test"}},
+			}, false).
+			WithContainerPreStopHook("first", &corev1.LifecycleHandler{
+				Exec: &corev1.ExecAction{Command: []string{"toast"}},
+			}, true).
+			Build()
+		Expect(template.Spec.Containers[0].Name).To(Equal("first"))
+		Expect(template.Spec.Containers[0].Lifecycle.PreStop.Exec.Command).To(Equal([]string{"toast"}))
+	})
+
 	It("correctly add a volumeMount to a container", func() {
 		template := New().
 			WithContainerVolumeMount("first", &corev1.VolumeMount{
@@ -21,7 +21,9 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"math"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
@@ -39,6 +41,9 @@ const (
 	// ParameterMaxWalSenders the configuration key containing the max_wal_senders value
 	ParameterMaxWalSenders = "max_wal_senders"
 
+	// ParameterMaxReplicationSlots the configuration key containing the max_replication_slots value
+	ParameterMaxReplicationSlots = "max_replication_slots"
+
 	// ParameterArchiveMode the configuration key containing the archive_mode value
 	ParameterArchiveMode = "archive_mode"
 
@@ -49,6 +54,9 @@ const (
 	ParameterRecoveyMinApplyDelay = "recovery_min_apply_delay"
 )
 
+// defaultMaxWalSenders is the default value of max_wal_senders in PostgreSQL
+const defaultMaxWalSenders = 10
+
 // An acceptable wal_level value
 const (
 	WalLevelValueLogical WalLevelValue = "logical"
@@ -81,6 +89,14 @@ const (
 	// hbaTemplateString is the template used to generate the pg_hba.conf
 	// configuration file
 	hbaTemplateString = `
+{{- if .PrependedRules }}
+#
+# USER-DEFINED RULES PRECEDING THE FIXED ONES
+#
+{{ range $rule := .PrependedRules }}
+{{ $rule -}}
+{{ end }}
+{{ end }}
 #
 # FIXED RULES
 #
@@ -89,10 +105,24 @@ const (
 local all all peer map=local
 
 # Require client certificate authentication for the streaming_replica user
+{{- if .ScopedReplication }}
+{{- range $address := .ReplicationAddresses }}
+hostssl postgres streaming_replica {{ $address }} cert
+hostssl replication streaming_replica {{ $address }} cert
+{{- end }}
+{{- else }}
 hostssl postgres streaming_replica all cert
 hostssl replication streaming_replica all cert
+{{- end }}
 hostssl all cnpg_pooler_pgbouncer all cert
-
+{{ if .ClientCertificateRules }}
+#
+# CLIENT CERTIFICATE AUTHENTICATION
+#
+{{ range $rule := .ClientCertificateRules }}
+{{ $rule -}}
+{{ end }}
+{{ end }}
 #
 # USER-DEFINED RULES
 #
@@ -123,7 +153,14 @@ host all all all {{.DefaultAuthenticationMethod}}
 
 # Grant local access ('local' user map)
 local {{.Username}} postgres
-
+{{ if .ClientCertificateMappings }}
+#
+# CLIENT CERTIFICATE MAPPINGS
+#
+{{ range $rule := .ClientCertificateMappings }}
+{{ $rule -}}
+{{ end }}
+{{ end }}
 #
 # USER-DEFINED RULES
 #
@@ -217,6 +254,10 @@ local {{.Username}} postgres
 	// used by the PostgreSQL server
 	SocketDirectory = ScratchDataDirectory + "/run"
 
+	// MajorUpgradeOldBinariesDirectory is where the binaries of the previous
+	// major version of PostgreSQL are copied during a major upgrade
+	MajorUpgradeOldBinariesDirectory = ScratchDataDirectory + "/old-binaries"
+
 	// ServerPort is the port where the postmaster process will be listening.
 	// It's also used in the naming of the Unix socket
 	ServerPort = 5432
@@ -330,6 +371,37 @@ type ConfigurationInfo struct {
 
 	// Minimum apply delay of transaction
 	RecoveryMinApplyDelay time.Duration
+
+	// The minimum value of max_wal_senders required by the topology,
+	// applied when the user didn't set it
+	MinWalSenders int
+
+	// The minimum value of max_replication_slots required by the
+	// topology, applied when the user didn't set it
+	MinReplicationSlots int
+
+	// The settings derived from the size tier of the cluster,
+	// overridden by the ones set by the user
+	SizeTierSettings map[string]string
+
+	// The name of the workload profile whose settings are applied on top
+	// of the size tier ones, and overridden by the ones set by the user
+	WorkloadProfile string
+
+	// The configuration files to be included after the settings managed
+	// by the operator, and overridden by the ones set by the user
+	IncludedConfiguration []IncludedConfiguration
+}
+
+// IncludedConfiguration is a configuration file provided by the user
+// to be included in the PostgreSQL configuration
+type IncludedConfiguration struct {
+	// The name of the file, relative to the configuration file
+	// including it
+	FileName string
+
+	// The content of the file
+	Content string
 }
 
 // getAlterSystemEnabledValue returns a config compatible value for IsAlterSystemEnabled
@@ -528,18 +600,30 @@ var (
 )
 
 // CreateHBARules will create the content of pg_hba.conf file given
-// the rules set by the cluster spec
-func CreateHBARules(hba []string,
-	defaultAuthenticationMethod, ldapConfigString string,
+// the rules set by the cluster spec. The streaming_replica user is allowed
+// to connect only from the passed addresses, or from any address if
+// replicationAddresses is nil. The prepended rules are placed before the
+// fixed ones, while the client certificate rules are placed before the
+// user-defined ones
+func CreateHBARules(hba []string, prependedRules []string, clientCertificateRules []string,
+	replicationAddresses []string, defaultAuthenticationMethod, ldapConfigString string,
 ) (string, error) {
 	var hbaContent bytes.Buffer
 
 	templateData := struct {
+		PrependedRules              []string
 		UserRules                   []string
+		ClientCertificateRules      []string
+		ScopedReplication           bool
+		ReplicationAddresses        []string
 		LDAPConfiguration           string
 		DefaultAuthenticationMethod string
 	}{
+		PrependedRules:              prependedRules,
 		UserRules:                   hba,
+		ClientCertificateRules:      clientCertificateRules,
+		ScopedReplication:           replicationAddresses != nil,
+		ReplicationAddresses:        replicationAddresses,
 		LDAPConfiguration:           ldapConfigString,
 		DefaultAuthenticationMethod: defaultAuthenticationMethod,
 	}
@@ -552,16 +636,19 @@ func CreateHBARules(hba []string,
 }
 
 // CreateIdentRules will create the content of pg_ident.conf file given
-// the rules set by the cluster spec
-func CreateIdentRules(ident []string, username string) (string, error) {
+// the rules set by the cluster spec and the mappings of the client
+// certificates
+func CreateIdentRules(ident []string, clientCertificateMappings []string, username string) (string, error) {
 	var identContent bytes.Buffer
 
 	templateData := struct {
-		Mappings []string
-		Username string
+		Mappings                  []string
+		ClientCertificateMappings []string
+		Username                  string
 	}{
-		Mappings: ident,
-		Username: username,
+		Mappings:                  ident,
+		ClientCertificateMappings: clientCertificateMappings,
+		Username:                  username,
 	}
 
 	if err := identTemplate.Execute(&identContent, templateData); err != nil {
@@ -574,6 +661,16 @@ func CreateIdentRules(ident []string, username string) (string, error) {
 // PgConfiguration wraps configuration parameters with some checks
 type PgConfiguration struct {
 	configs map[string]string
+
+	// The configuration files to be included
+	includes []IncludedConfiguration
+
+	// Whether the settings being written need to override the ones
+	// in the included configuration files
+	overridingIncludes bool
+
+	// The settings overriding the ones in the included configuration files
+	includeOverrides map[string]bool
 }
 
 // GetConfigurationParameters returns the generated configuration parameters
@@ -589,16 +686,33 @@ func (p *PgConfiguration) OverwriteConfig(key, value string) {
 	}
 
 	p.configs[key] = value
+	p.markIncludeOverride(key)
 }
 
-// AddSharedPreloadLibrary add anew shared preloaded library to PostgreSQL configuration
+// markIncludeOverride records that the passed setting needs to override
+// the included configuration files, if it is being written after them
+func (p *PgConfiguration) markIncludeOverride(key string) {
+	if !p.overridingIncludes {
+		return
+	}
+	if p.includeOverrides == nil {
+		p.includeOverrides = make(map[string]bool)
+	}
+	p.includeOverrides[key] = true
+}
+
+// AddSharedPreloadLibrary add anew shared preloaded library to PostgreSQL configuration,
+// at the end of the list
 func (p *PgConfiguration) AddSharedPreloadLibrary(newLibrary string) {
 	if len(newLibrary) == 0 {
 		return
 	}
-	if strings.Contains(p.configs[SharedPreloadLibraries], newLibrary) {
+	// A library whose name contains the new one, like "pgaudit_ext" and
+	// "pgaudit", is a different library
+	if slices.Contains(splitSharedPreloadLibraries(p.configs[SharedPreloadLibraries]), newLibrary) {
 		return
 	}
+	p.markIncludeOverride(SharedPreloadLibraries)
 	if libraries, ok := p.configs[SharedPreloadLibraries]; ok &&
 		libraries != "" {
 		p.configs[SharedPreloadLibraries] = strings.Join([]string{libraries, newLibrary}, ",")
@@ -634,6 +748,23 @@ func CreatePostgresqlConfiguration(info ConfigurationInfo) *PgConfiguration {
 
 	// Set all the default settings
 	setDefaultConfigurations(info, configuration)
+	raiseIntegerSetting(configuration, ParameterMaxWalSenders, defaultMaxWalSenders, info.MinWalSenders)
+	raiseIntegerSetting(configuration, ParameterMaxReplicationSlots, 0, info.MinReplicationSlots)
+
+	// Apply the settings of the size tier, on top of the defaults
+	for key, value := range info.SizeTierSettings {
+		configuration.OverwriteConfig(key, value)
+	}
+
+	// Apply the settings of the workload profile, on top of the size tier
+	if profile, ok := WorkloadProfiles[info.WorkloadProfile]; ok {
+		applySettings(configuration, profile, info.Version)
+	}
+
+	// The included configuration files are read after the settings above,
+	// and before every setting below
+	configuration.includes = info.IncludedConfiguration
+	configuration.overridingIncludes = true
 
 	// Apply all the values from the user, overriding defaults,
 	// ignoring those which are fixed if ignoreFixedSettingsFromUser is true
@@ -713,18 +844,24 @@ func CreatePostgresqlConfiguration(info ConfigurationInfo) *PgConfiguration {
 // setDefaultConfigurations sets all default configurations into the configuration map
 // from the provided info
 func setDefaultConfigurations(info ConfigurationInfo, configuration *PgConfiguration) {
+	applySettings(configuration, info.Settings, info.Version)
+}
+
+// applySettings applies the global default settings and the ones relative
+// to the passed PostgreSQL version
+func applySettings(configuration *PgConfiguration, configurationSettings ConfigurationSettings, v version.Data) {
 	// start from the global default settings
-	for key, value := range info.Settings.GlobalDefaultSettings {
+	for key, value := range configurationSettings.GlobalDefaultSettings {
 		configuration.OverwriteConfig(key, value)
 	}
 
 	// apply settings relative to a certain PostgreSQL version
-	for constraints, settings := range info.Settings.DefaultSettings {
+	for constraints, settings := range configurationSettings.DefaultSettings {
 		if constraints.Min == MajorVersionRangeUnlimited ||
-			constraints.Min == info.Version ||
-			constraints.Min.Less(info.Version) {
+			constraints.Min == v ||
+			constraints.Min.Less(v) {
 			if constraints.Max == MajorVersionRangeUnlimited ||
-				info.Version.Less(constraints.Max) {
+				v.Less(constraints.Max) {
 				for key, value := range settings {
 					configuration.OverwriteConfig(key, value)
 				}
@@ -733,6 +870,19 @@ func setDefaultConfigurations(info ConfigurationInfo, configuration *PgConfigura
 	}
 }
 
+// raiseIntegerSetting raises an integer setting to the passed minimum,
+// considering the passed default value when the setting is not set
+func raiseIntegerSetting(configuration *PgConfiguration, key string, defaultValue, minimum int) {
+	current := defaultValue
+	if value, err := strconv.Atoi(configuration.GetConfig(key)); err == nil {
+		current = value
+	}
+
+	if minimum > current {
+		configuration.OverwriteConfig(key, strconv.Itoa(minimum))
+	}
+}
+
 // setManagedSharedPreloadLibraries sets all additional preloaded libraries
 func setManagedSharedPreloadLibraries(info ConfigurationInfo, configuration *PgConfiguration) {
 	for _, extension := range ManagedExtensions {
@@ -744,15 +894,55 @@ func setManagedSharedPreloadLibraries(info ConfigurationInfo, configuration *PgC
 	}
 }
 
+// orderSensitiveSharedPreloadLibraries is the list of the libraries that
+// need to be loaded before any other one, in the order they must appear
+// in shared_preload_libraries
+var orderSensitiveSharedPreloadLibraries = []string{
+	"citus",
+	"timescaledb",
+}
+
+// sortSharedPreloadLibraries moves the order sensitive libraries to the
+// beginning of the passed list, preserving the order of the other ones
+func sortSharedPreloadLibraries(libraries []string) []string {
+	result := make([]string, 0, len(libraries))
+	for _, library := range orderSensitiveSharedPreloadLibraries {
+		if slices.Contains(libraries, library) {
+			result = append(result, library)
+		}
+	}
+	for _, library := range libraries {
+		if !slices.Contains(orderSensitiveSharedPreloadLibraries, library) {
+			result = append(result, library)
+		}
+	}
+
+	return result
+}
+
+// splitSharedPreloadLibraries splits the value of shared_preload_libraries
+// in the list of the libraries, ignoring the surrounding spaces
+func splitSharedPreloadLibraries(value string) []string {
+	libraries := strings.Split(value, ",")
+	for idx := range libraries {
+		libraries[idx] = strings.TrimSpace(libraries[idx])
+	}
+	return libraries
+}
+
 // setUserSharedPreloadLibraries sets all additional preloaded libraries.
-// The resulting list will have all the user provided libraries, followed by all the ones managed
-// by the operator, removing any duplicate and keeping the first occurrence in case of duplicates.
-// Therefore the user provided order is preserved, if an overlap (with the ones already present) happens
+// The resulting list will have all the user provided libraries, in the user provided order,
+// followed by all the ones managed by the operator, in the order of ManagedExtensions,
+// removing any duplicate and keeping the first occurrence in case of duplicates.
+// Therefore the user provided order is preserved, if an overlap (with the ones already present) happens,
+// with the exception of the order sensitive libraries, which are always loaded first
 func setUserSharedPreloadLibraries(info ConfigurationInfo, configuration *PgConfiguration) {
-	oldLibraries := strings.Split(configuration.GetConfig(SharedPreloadLibraries), ",")
+	oldLibraries := splitSharedPreloadLibraries(configuration.GetConfig(SharedPreloadLibraries))
 	dedupedLibraries := make(map[string]bool, len(oldLibraries)+len(info.AdditionalSharedPreloadLibraries))
 	var libraries []string
-	for _, library := range append(info.AdditionalSharedPreloadLibraries, oldLibraries...) {
+	// The list in the cluster spec must not be changed
+	for _, library := range slices.Concat(info.AdditionalSharedPreloadLibraries, oldLibraries) {
+		library = strings.TrimSpace(library)
 		// if any, delete empty string
 		if library == "" {
 			continue
@@ -763,7 +953,7 @@ func setUserSharedPreloadLibraries(info ConfigurationInfo, configuration *PgConf
 		}
 	}
 	if len(libraries) > 0 {
-		configuration.OverwriteConfig(SharedPreloadLibraries, strings.Join(libraries, ","))
+		configuration.OverwriteConfig(SharedPreloadLibraries, strings.Join(sortSharedPreloadLibraries(libraries), ","))
 	}
 }
 
@@ -772,17 +962,38 @@ func CreatePostgresqlConfFile(configuration *PgConfiguration) (string, string) {
 	// We need to be able to compare two configurations generated
 	// by operator to know if they are different or not. To do
 	// that we sort the configuration by parameter name as order
-	// is really irrelevant for our purposes
+	// is really irrelevant for our purposes, with the exception
+	// of the included files, which are read between the settings
+	// managed by the operator and the ones they can't override
 	parameters := configuration.GetSortedList()
-	postgresConf := ""
-	for _, parameter := range parameters {
-		postgresConf += fmt.Sprintf(
-			"%v = %v\n",
-			parameter,
-			escapePostgresConfValue(configuration.configs[parameter]))
+	writeParameters := func(postgresConf string, overridingIncludes bool) string {
+		for _, parameter := range parameters {
+			if len(configuration.includes) > 0 && configuration.includeOverrides[parameter] != overridingIncludes {
+				continue
+			}
+			postgresConf += fmt.Sprintf(
+				"%v = %v\n",
+				parameter,
+				escapePostgresConfValue(configuration.configs[parameter]))
+		}
+		return postgresConf
 	}
 
-	sha256sum := fmt.Sprintf("%x", sha256.Sum256([]byte(postgresConf)))
+	postgresConf := writeParameters("", false)
+	if len(configuration.includes) > 0 {
+		for _, include := range configuration.includes {
+			postgresConf += fmt.Sprintf("include %v\n", escapePostgresConfValue(include.FileName))
+		}
+		postgresConf = writeParameters(postgresConf, true)
+	}
+
+	// The content of the included files is part of the configuration too
+	hash := sha256.New()
+	hash.Write([]byte(postgresConf))
+	for _, include := range configuration.includes {
+		hash.Write([]byte(include.Content))
+	}
+	sha256sum := fmt.Sprintf("%x", hash.Sum(nil))
 	postgresConf += fmt.Sprintf("%v = %v", CNPGConfigSha256,
 		escapePostgresConfValue(sha256sum))
 
@@ -794,3 +1005,48 @@ func CreatePostgresqlConfFile(configuration *PgConfiguration) (string, string) {
 func escapePostgresConfValue(value string) string {
 	return fmt.Sprintf("'%v'", strings.ReplaceAll(value, "'", "''"))
 }
+
+// ValidateIncludedConfiguration checks that a configuration file to be
+// included in the PostgreSQL configuration doesn't set any parameter
+// reserved to the operator, and doesn't include other files
+func ValidateIncludedConfiguration(content string) error {
+	var reserved []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, _, _ := strings.Cut(line, "=")
+		fields := strings.Fields(name)
+		if len(fields) == 0 {
+			continue
+		}
+
+		parameter := strings.ToLower(fields[0])
+		if strings.HasPrefix(parameter, "include") {
+			return fmt.Errorf("including other files is not allowed: %v", line)
+		}
+		if isReservedParameter(parameter) && !slices.Contains(reserved, parameter) {
+			reserved = append(reserved, parameter)
+		}
+	}
+
+	if len(reserved) > 0 {
+		return fmt.Errorf("the following parameters are managed by the operator and can't be set: %v",
+			strings.Join(reserved, ", "))
+	}
+	return nil
+}
+
+// isReservedParameter checks if the passed parameter is controlled by
+// the operator, and thus can't be set by the included configuration files
+func isReservedParameter(parameter string) bool {
+	if _, isFixed := FixedConfigurationParameters[parameter]; isFixed {
+		return true
+	}
+	if _, isMandatory := CnpgConfigurationSettings.MandatorySettings[parameter]; isMandatory {
+		return true
+	}
+	return parameter == "allow_alter_system" || parameter == CNPGConfigSha256
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package postgres
 
 import (
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -75,7 +77,7 @@ var _ = Describe("PostgreSQL configuration creation", func() {
 			"shared_buffers":  "128KB",
 			"log_destination": "stderr",
 		}
-		confFile, sha256 := CreatePostgresqlConfFile(&PgConfiguration{settings})
+		confFile, sha256 := CreatePostgresqlConfFile(&PgConfiguration{configs: settings})
 		Expect(sha256).NotTo(BeEmpty())
 		Expect(confFile).To(ContainSubstring("log_destination = 'stderr'\nshared_buffers = '128KB'\n"))
 	})
@@ -151,6 +153,166 @@ var _ = Describe("PostgreSQL configuration creation", func() {
 			ContainElements("some_library", "another_library"), Not(ContainElement(""))))
 	})
 
+	It("loads the order sensitive libraries first", func() {
+		info := ConfigurationInfo{
+			Settings: CnpgConfigurationSettings,
+			Version:  version.New(16, 0),
+			UserSettings: map[string]string{
+				"pg_stat_statements.something": "something",
+			},
+			IncludingMandatory:               true,
+			IncludingSharedPreloadLibraries:  true,
+			AdditionalSharedPreloadLibraries: []string{"some_library", "timescaledb", "another_library"},
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig(SharedPreloadLibraries)).To(
+			Equal("timescaledb,some_library,another_library,pg_stat_statements"))
+	})
+
+	It("sorts the order sensitive libraries following the allowlist", func() {
+		Expect(sortSharedPreloadLibraries([]string{"pgaudit", "timescaledb", "citus"})).
+			To(Equal([]string{"citus", "timescaledb", "pgaudit"}))
+		Expect(sortSharedPreloadLibraries([]string{"pgaudit", "pg_stat_statements"})).
+			To(Equal([]string{"pgaudit", "pg_stat_statements"}))
+	})
+
+	It("preserves the user order and appends the managed libraries", func() {
+		// The spare capacity allows detecting if the list in the spec is changed
+		userLibraries := make([]string, 4, 10)
+		copy(userLibraries, []string{"my_extension", " auto_explain", "pg_stat_statements_ext", "my_extension"})
+		info := ConfigurationInfo{
+			Settings: CnpgConfigurationSettings,
+			Version:  version.New(16, 0),
+			UserSettings: map[string]string{
+				"pgaudit.log":                   "all",
+				"auto_explain.log_min_duration": "1s",
+				"pg_stat_statements.max":        "10000",
+			},
+			IncludingMandatory:               true,
+			IncludingSharedPreloadLibraries:  true,
+			AdditionalSharedPreloadLibraries: userLibraries,
+		}
+
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig(SharedPreloadLibraries)).To(
+			Equal("my_extension,auto_explain,pg_stat_statements_ext,pgaudit,pg_stat_statements"))
+		Expect(userLibraries[len(userLibraries):cap(userLibraries)]).To(HaveEach(BeEmpty()))
+
+		// The rendered configuration doesn't change between runs
+		confFile, _ := CreatePostgresqlConfFile(config)
+		for range 10 {
+			otherConfFile, _ := CreatePostgresqlConfFile(CreatePostgresqlConfiguration(info))
+			Expect(otherConfFile).To(Equal(confFile))
+		}
+	})
+
+	It("raises the replication limits to the minimum required by the topology", func() {
+		info := ConfigurationInfo{
+			Settings:            CnpgConfigurationSettings,
+			Version:             version.New(16, 0),
+			IncludingMandatory:  true,
+			MinWalSenders:       15,
+			MinReplicationSlots: 40,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig(ParameterMaxWalSenders)).To(Equal("15"))
+		Expect(config.GetConfig(ParameterMaxReplicationSlots)).To(Equal("40"))
+
+		By("keeping the defaults when they are enough", func() {
+			info.MinWalSenders = 6
+			info.MinReplicationSlots = 6
+			config := CreatePostgresqlConfiguration(info)
+			Expect(config.GetConfig(ParameterMaxWalSenders)).To(BeEmpty())
+			Expect(config.GetConfig(ParameterMaxReplicationSlots)).To(Equal("32"))
+		})
+
+		By("preserving the values set by the user", func() {
+			info.MinWalSenders = 15
+			info.UserSettings = map[string]string{ParameterMaxWalSenders: "5"}
+			config := CreatePostgresqlConfiguration(info)
+			Expect(config.GetConfig(ParameterMaxWalSenders)).To(Equal("5"))
+		})
+	})
+
+	It("applies the size tier settings, overridden by the user ones", func() {
+		info := ConfigurationInfo{
+			Settings:           CnpgConfigurationSettings,
+			Version:            version.New(16, 0),
+			IncludingMandatory: true,
+			SizeTierSettings: map[string]string{
+				"shared_buffers": "2GB",
+				"work_mem":       "16MB",
+			},
+			UserSettings: map[string]string{
+				"work_mem": "64MB",
+			},
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("shared_buffers")).To(Equal("2GB"))
+		Expect(config.GetConfig("work_mem")).To(Equal("64MB"))
+	})
+
+	Context("workload profiles", func() {
+		createConfiguration := func(profile string, userSettings map[string]string) *PgConfiguration {
+			return CreatePostgresqlConfiguration(ConfigurationInfo{
+				Settings:           CnpgConfigurationSettings,
+				Version:            version.New(16, 0),
+				IncludingMandatory: true,
+				WorkloadProfile:    profile,
+				UserSettings:       userSettings,
+			})
+		}
+
+		settingAsInt := func(config *PgConfiguration, key string) int {
+			value, err := strconv.Atoi(strings.TrimSuffix(config.GetConfig(key), "MB"))
+			Expect(err).ToNot(HaveOccurred())
+			return value
+		}
+
+		It("raises parallel workers and work_mem in the OLAP profile compared to the OLTP one", func() {
+			oltp := createConfiguration(WorkloadProfileOLTP, nil)
+			olap := createConfiguration(WorkloadProfileOLAP, nil)
+
+			Expect(settingAsInt(olap, "max_parallel_workers_per_gather")).
+				To(BeNumerically(">", settingAsInt(oltp, "max_parallel_workers_per_gather")))
+			Expect(settingAsInt(olap, "work_mem")).
+				To(BeNumerically(">", settingAsInt(oltp, "work_mem")))
+		})
+
+		It("lets the explicit parameters win over the profile", func() {
+			config := createConfiguration(WorkloadProfileOLAP, map[string]string{
+				"work_mem":                        "8MB",
+				"max_parallel_workers_per_gather": "1",
+			})
+			Expect(config.GetConfig("work_mem")).To(Equal("8MB"))
+			Expect(config.GetConfig("max_parallel_workers_per_gather")).To(Equal("1"))
+			Expect(config.GetConfig("random_page_cost")).To(Equal("1.1"))
+		})
+
+		It("applies the settings depending on the PostgreSQL version", func() {
+			Expect(createConfiguration(WorkloadProfileOLAP, nil).GetConfig("hash_mem_multiplier")).To(Equal("4.0"))
+
+			config := CreatePostgresqlConfiguration(ConfigurationInfo{
+				Settings:        CnpgConfigurationSettings,
+				Version:         version.New(12, 0),
+				WorkloadProfile: WorkloadProfileOLAP,
+			})
+			Expect(config.GetConfig("hash_mem_multiplier")).To(BeEmpty())
+		})
+
+		It("doesn't override the default settings stored by the defaulting webhook", func() {
+			for name, profile := range WorkloadProfiles {
+				for key := range profile.GlobalDefaultSettings {
+					Expect(CnpgConfigurationSettings.GlobalDefaultSettings).ToNot(HaveKey(key), name)
+				}
+			}
+		})
+
+		It("applies no setting without a profile", func() {
+			Expect(createConfiguration("", nil).GetConfig("random_page_cost")).To(BeEmpty())
+		})
+	})
+
 	It("checks if PreserveFixedSettingsFromUser works properly", func() {
 		info := ConfigurationInfo{
 			Settings: CnpgConfigurationSettings,
@@ -250,19 +412,56 @@ var _ = Describe("pg_hba.conf generation", func() {
 	}
 
 	It("insert the spec configuration between an header and a footer when the version can not be parsed", func() {
-		Expect(CreateHBARules(specRules, "md5", "")).To(
+		Expect(CreateHBARules(specRules, nil, nil, nil, "md5", "")).To(
 			ContainSubstring("\ntwo\n"))
 	})
 
 	It("really use the passed default authentication method", func() {
-		Expect(CreateHBARules(specRules, "this-one", "")).To(
+		Expect(CreateHBARules(specRules, nil, nil, nil, "this-one", "")).To(
 			ContainSubstring("\nhost all all all this-one\n"))
 	})
 
 	It("really uses the ldapConfigString", func() {
-		Expect(CreateHBARules(specRules, "defaultAuthenticationMethod", "ldapConfigString")).To(
+		Expect(CreateHBARules(specRules, nil, nil, nil, "defaultAuthenticationMethod", "ldapConfigString")).To(
 			ContainSubstring("\nldapConfigString\n"))
 	})
+
+	It("allows the streaming_replica user from any address by default", func() {
+		hba, err := CreateHBARules(specRules, nil, nil, nil, "md5", "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hba).To(ContainSubstring("\nhostssl postgres streaming_replica all cert\n" +
+			"hostssl replication streaming_replica all cert\n"))
+	})
+
+	It("restricts the streaming_replica user to the passed addresses", func() {
+		hba, err := CreateHBARules(specRules, nil, nil, []string{"10.0.0.1/32", "fd00::1/128"}, "md5", "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hba).ToNot(ContainSubstring("streaming_replica all"))
+		Expect(hba).To(ContainSubstring("\nhostssl postgres streaming_replica 10.0.0.1/32 cert\n" +
+			"hostssl replication streaming_replica 10.0.0.1/32 cert\n" +
+			"hostssl postgres streaming_replica fd00::1/128 cert\n" +
+			"hostssl replication streaming_replica fd00::1/128 cert\n"))
+	})
+
+	It("doesn't allow the streaming_replica user when no address is known", func() {
+		hba, err := CreateHBARules(specRules, nil, nil, []string{}, "md5", "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hba).ToNot(ContainSubstring("hostssl postgres streaming_replica"))
+		Expect(hba).ToNot(ContainSubstring("hostssl replication streaming_replica"))
+	})
+
+	It("places the prepended rules before the fixed ones", func() {
+		hba, err := CreateHBARules(specRules, []string{"hostssl all all 10.0.0.0/8 reject"}, nil, nil, "md5", "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hba).To(HavePrefix("\n#\n# USER-DEFINED RULES PRECEDING THE FIXED ONES\n#\n\n" +
+			"hostssl all all 10.0.0.0/8 reject\n\n#\n# FIXED RULES\n"))
+	})
+
+	It("doesn't add the section of the prepended rules when there are none", func() {
+		hba, err := CreateHBARules(specRules, nil, nil, nil, "md5", "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hba).To(HavePrefix("\n#\n# FIXED RULES\n"))
+	})
 })
 
 var _ = Describe("pg_ident.conf generation", func() {
@@ -271,15 +470,27 @@ var _ = Describe("pg_ident.conf generation", func() {
 	}
 
 	It("contains the default map when no mappings are added", func() {
-		Expect(CreateIdentRules(make([]string, 0), "someone")).To(
+		Expect(CreateIdentRules(make([]string, 0), nil, "someone")).To(
 			ContainSubstring("\nlocal someone postgres\n"))
 	})
 
 	It("contains the default map and additional mappings when added", func() {
-		rules, _ := CreateIdentRules(specRules, "someone")
+		rules, _ := CreateIdentRules(specRules, nil, "someone")
 		Expect(rules).To(ContainSubstring("\nlocal someone postgres\n"))
 		Expect(rules).To(ContainSubstring("\ntest someone else\n"))
 	})
+
+	It("maps certificate identities to a differently-named role", func() {
+		hba, err := CreateHBARules([]string{"hostssl app app all cert map=certmap"}, nil, nil, nil, "scram-sha-256", "")
+		Expect(err).ToNot(HaveOccurred())
+		ident, err := CreateIdentRules([]string{"certmap app.example.com app"}, nil, "someone")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(hba).To(ContainSubstring("\nhostssl app app all cert map=certmap\n"))
+		Expect(ident).To(ContainSubstring("\ncertmap app.example.com app\n"))
+		Expect(strings.Index(hba, "hostssl app app all cert map=certmap")).To(
+			BeNumerically("<", strings.Index(hba, "host all all all scram-sha-256")))
+	})
 })
 
 var _ = Describe("pgaudit", func() {
@@ -403,3 +614,63 @@ var _ = Describe("recovery_min_apply_delay", func() {
 		Expect(config.GetConfig(ParameterRecoveyMinApplyDelay)).To(Equal("3600s"))
 	})
 })
+
+var _ = Describe("included configuration files", func() {
+	info := ConfigurationInfo{
+		Settings:           CnpgConfigurationSettings,
+		Version:            version.New(16, 0),
+		UserSettings:       map[string]string{"work_mem": "16MB"},
+		IncludingMandatory: true,
+		IncludedConfiguration: []IncludedConfiguration{
+			{FileName: "included.conf.d/0-shared.conf", Content: "work_mem = '8MB'\nmax_connections = 500\n"},
+		},
+	}
+
+	It("includes the files between the managed settings and the user ones", func() {
+		confFile, _ := CreatePostgresqlConfFile(CreatePostgresqlConfiguration(info))
+		lines := strings.Split(confFile, "\n")
+		includeIdx := slices.Index(lines, "include 'included.conf.d/0-shared.conf'")
+		Expect(includeIdx).To(BeNumerically(">", 0))
+
+		// The settings managed by the operator can be overridden
+		Expect(slices.Index(lines, "wal_level = 'logical'")).To(BeNumerically("<", includeIdx))
+		// The ones set by the user and the mandatory ones can't
+		Expect(slices.Index(lines, "work_mem = '16MB'")).To(BeNumerically(">", includeIdx))
+		Expect(slices.Index(lines, "port = '5432'")).To(BeNumerically(">", includeIdx))
+		Expect(slices.Index(lines, "archive_mode = 'on'")).To(BeNumerically(">", includeIdx))
+	})
+
+	It("changes the checksum when the content of the included files changes", func() {
+		_, sha256 := CreatePostgresqlConfFile(CreatePostgresqlConfiguration(info))
+
+		changedInfo := info
+		changedInfo.IncludedConfiguration = []IncludedConfiguration{
+			{FileName: "included.conf.d/0-shared.conf", Content: "max_connections = 200\n"},
+		}
+		_, changedSha256 := CreatePostgresqlConfFile(CreatePostgresqlConfiguration(changedInfo))
+		Expect(changedSha256).ToNot(Equal(sha256))
+	})
+
+	It("doesn't change the configuration when no file is included", func() {
+		withoutIncludes := info
+		withoutIncludes.IncludedConfiguration = nil
+		confFile, _ := CreatePostgresqlConfFile(CreatePostgresqlConfiguration(withoutIncludes))
+		Expect(confFile).ToNot(ContainSubstring("include"))
+		Expect(confFile).To(ContainSubstring("archive_mode = 'on'\narchive_timeout = '5min'\n"))
+	})
+
+	It("accepts files setting the parameters not reserved to the operator", func() {
+		Expect(ValidateIncludedConfiguration(
+			"# shared settings\nwork_mem = '8MB'\nmax_connections 500\n\nlog_min_duration_statement=1000",
+		)).To(Succeed())
+	})
+
+	It("rejects files setting the parameters reserved to the operator", func() {
+		err := ValidateIncludedConfiguration("work_mem = '8MB'\nport = 5433\nListen_Addresses = 'localhost'\n")
+		Expect(err).To(MatchError(ContainSubstring("port, listen_addresses")))
+	})
+
+	It("rejects files including other files", func() {
+		Expect(ValidateIncludedConfiguration("include_dir '/tmp'")).ToNot(Succeed())
+	})
+})
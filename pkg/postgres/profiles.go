@@ -0,0 +1,106 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"github.com/cloudnative-pg/machinery/pkg/postgres/version"
+)
+
+const (
+	// WorkloadProfileOLTP is the name of the profile tuned for
+	// transactional workloads, made of many short queries
+	WorkloadProfileOLTP = "oltp"
+
+	// WorkloadProfileOLAP is the name of the profile tuned for analytical
+	// workloads, made of few complex queries on large amounts of data
+	WorkloadProfileOLAP = "olap"
+
+	// WorkloadProfileMixed is the name of the profile tuned for workloads
+	// made of both transactional and analytical queries
+	WorkloadProfileMixed = "mixed"
+)
+
+// WorkloadProfiles contains the parameters applied by each workload
+// profile. Only GlobalDefaultSettings and DefaultSettings are used, and
+// none of the parameters should be among the ones in
+// CnpgConfigurationSettings, as the defaulting webhook stores them
+// in the cluster parameters, which override the profile.
+var WorkloadProfiles = map[string]ConfigurationSettings{
+	WorkloadProfileOLTP: {
+		GlobalDefaultSettings: SettingsCollection{
+			"work_mem":                         "4MB",
+			"random_page_cost":                 "1.1",
+			"effective_io_concurrency":         "200",
+			"max_parallel_workers_per_gather":  "1",
+			"max_parallel_maintenance_workers": "2",
+			"jit":                              "off",
+			"default_statistics_target":        "100",
+			"checkpoint_completion_target":     "0.9",
+			"wal_buffers":                      "16MB",
+			"wal_compression":                  "on",
+			"min_wal_size":                     "1GB",
+			"max_wal_size":                     "4GB",
+		},
+		DefaultSettings: map[VersionRange]SettingsCollection{
+			{version.New(13, 0), MajorVersionRangeUnlimited}: {
+				"hash_mem_multiplier": "2.0",
+			},
+		},
+	},
+	WorkloadProfileOLAP: {
+		GlobalDefaultSettings: SettingsCollection{
+			"work_mem":                         "64MB",
+			"random_page_cost":                 "1.1",
+			"effective_io_concurrency":         "200",
+			"max_parallel_workers_per_gather":  "4",
+			"max_parallel_maintenance_workers": "4",
+			"jit":                              "on",
+			"default_statistics_target":        "500",
+			"checkpoint_completion_target":     "0.9",
+			"wal_buffers":                      "64MB",
+			"wal_compression":                  "on",
+			"min_wal_size":                     "2GB",
+			"max_wal_size":                     "16GB",
+		},
+		DefaultSettings: map[VersionRange]SettingsCollection{
+			{version.New(13, 0), MajorVersionRangeUnlimited}: {
+				"hash_mem_multiplier": "4.0",
+			},
+		},
+	},
+	WorkloadProfileMixed: {
+		GlobalDefaultSettings: SettingsCollection{
+			"work_mem":                         "16MB",
+			"random_page_cost":                 "1.1",
+			"effective_io_concurrency":         "200",
+			"max_parallel_workers_per_gather":  "2",
+			"max_parallel_maintenance_workers": "2",
+			"jit":                              "off",
+			"default_statistics_target":        "200",
+			"checkpoint_completion_target":     "0.9",
+			"wal_buffers":                      "32MB",
+			"wal_compression":                  "on",
+			"min_wal_size":                     "1GB",
+			"max_wal_size":                     "8GB",
+		},
+		DefaultSettings: map[VersionRange]SettingsCollection{
+			{version.New(13, 0), MajorVersionRangeUnlimited}: {
+				"hash_mem_multiplier": "2.0",
+			},
+		},
+	},
+}
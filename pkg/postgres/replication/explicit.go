@@ -85,7 +85,7 @@ func explicitSynchronousStandbyNamesDataDurabilityPreferred(cluster *apiv1.Clust
 	config := cluster.Spec.PostgresConfiguration.Synchronous
 
 	// Create the list of healthy replicas
-	instancesList := getSortedNonPrimaryHealthyInstanceNames(cluster)
+	instancesList := preferCrossZoneStandbys(cluster, getSortedNonPrimaryHealthyInstanceNames(cluster))
 
 	// Cap the number of standby names using the configuration on the cluster
 	if config.MaxStandbyNamesFromCluster != nil && len(instancesList) > *config.MaxStandbyNamesFromCluster {
@@ -123,7 +123,9 @@ func explicitSynchronousStandbyNamesDataDurabilityPreferred(cluster *apiv1.Clust
 // The result is composed by:
 //
 //   - the list of non-primary ready instances - these are most likely the
-//     instances to be used as a potential synchronous replicas
+//     instances to be used as a potential synchronous replicas, with the
+//     ones lagging behind the primary at the end, and the ones in the zone
+//     of the primary after the cross-zone ones when requested
 //   - the list of non-primary non-ready instances
 //   - the name of the primary instance
 //
@@ -160,7 +162,8 @@ func getSortedInstanceNames(cluster *apiv1.Cluster) []string {
 	sort.Strings(nonPrimaryReadyInstances)
 	sort.Strings(otherInstances)
 	result := make([]string, 0, cluster.Spec.Instances)
-	result = append(result, nonPrimaryReadyInstances...)
+	result = append(result, preferCrossZoneStandbys(cluster,
+		deprioritizeLaggingStandbys(cluster, nonPrimaryReadyInstances))...)
 	result = append(result, otherInstances...)
 	if len(primaryInstance) > 0 {
 		result = append(result, primaryInstance)
@@ -17,6 +17,7 @@ limitations under the License.
 package replication
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/utils/ptr"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -84,6 +85,27 @@ var _ = Describe("synchronous replica configuration with the new API", func() {
 			Expect(explicitSynchronousStandbyNames(cluster)).To(Equal("FIRST 2 (\"three\")"))
 		})
 
+		It("moves the lagging synchronous standbys to the end of the list", func() {
+			cluster := createFakeCluster("example")
+			cluster.Spec.PostgresConfiguration.Synchronous = &apiv1.SynchronousReplicaConfiguration{
+				Method:      apiv1.SynchronousReplicaConfigurationMethodFirst,
+				Number:      1,
+				LagRotation: &apiv1.SynchronousStandbyLagRotation{},
+			}
+			cluster.Status = apiv1.ClusterStatus{
+				CurrentPrimary: "one",
+				InstancesStatus: map[apiv1.PodStatus][]string{
+					apiv1.PodHealthy: {"one", "two", "three", "four"},
+				},
+				SynchronousStandbyRotation: &apiv1.SynchronousStandbyRotationStatus{
+					Deprioritized: []string{"four"},
+				},
+			}
+
+			Expect(explicitSynchronousStandbyNames(cluster)).To(
+				Equal("FIRST 1 (\"three\",\"two\",\"four\",\"one\")"))
+		})
+
 		It("prepends the prefix and append the suffix", func() {
 			cluster := createFakeCluster("example")
 			cluster.Spec.PostgresConfiguration.Synchronous = &apiv1.SynchronousReplicaConfiguration{
@@ -253,4 +275,75 @@ var _ = Describe("synchronous replica configuration with the new API", func() {
 			Expect(explicitSynchronousStandbyNames(cluster)).To(Equal("FIRST 1 (\"three\")"))
 		})
 	})
+
+	When("cross-zone synchronous standbys are preferred", func() {
+		var cluster *apiv1.Cluster
+
+		zoneTopology := func(zones map[string]string) apiv1.Topology {
+			instances := make(map[apiv1.PodName]apiv1.PodTopologyLabels, len(zones))
+			for name, zone := range zones {
+				instances[apiv1.PodName(name)] = apiv1.PodTopologyLabels{corev1.LabelTopologyZone: zone}
+			}
+			return apiv1.Topology{SuccessfullyExtracted: true, Instances: instances}
+		}
+
+		BeforeEach(func() {
+			cluster = createFakeCluster("example")
+			cluster.Spec.PostgresConfiguration.Synchronous = &apiv1.SynchronousReplicaConfiguration{
+				Method:          apiv1.SynchronousReplicaConfigurationMethodFirst,
+				Number:          1,
+				PreferCrossZone: true,
+			}
+			cluster.Status = apiv1.ClusterStatus{
+				CurrentPrimary: "one",
+				InstancesStatus: map[apiv1.PodStatus][]string{
+					apiv1.PodHealthy: {"one", "two", "three", "four"},
+				},
+				Topology: zoneTopology(map[string]string{
+					"one":   "zone-a",
+					"two":   "zone-b",
+					"three": "zone-a",
+					"four":  "zone-c",
+				}),
+			}
+		})
+
+		It("lists the cross-zone replicas first with the FIRST clause", func() {
+			Expect(explicitSynchronousStandbyNames(cluster)).To(
+				Equal("FIRST 1 (\"four\",\"two\",\"three\",\"one\")"))
+		})
+
+		It("only lists the cross-zone replicas with the ANY clause when they are enough", func() {
+			cluster.Spec.PostgresConfiguration.Synchronous.Method = apiv1.SynchronousReplicaConfigurationMethodAny
+			cluster.Spec.PostgresConfiguration.Synchronous.DataDurability = apiv1.DataDurabilityLevelPreferred
+			Expect(explicitSynchronousStandbyNames(cluster)).To(Equal("ANY 1 (\"four\",\"two\")"))
+
+			cluster.Spec.PostgresConfiguration.Synchronous.Number = 3
+			Expect(explicitSynchronousStandbyNames(cluster)).To(Equal("ANY 3 (\"four\",\"two\",\"three\")"))
+		})
+
+		It("falls back to the same-zone replicas when no cross-zone replica is available", func() {
+			cluster.Spec.PostgresConfiguration.Synchronous.Method = apiv1.SynchronousReplicaConfigurationMethodAny
+			cluster.Status.Topology = zoneTopology(map[string]string{
+				"one":   "zone-a",
+				"two":   "zone-a",
+				"three": "zone-a",
+				"four":  "zone-a",
+			})
+			Expect(explicitSynchronousStandbyNames(cluster)).To(
+				Equal("ANY 1 (\"four\",\"three\",\"two\",\"one\")"))
+		})
+
+		It("follows the zone of the new primary after a failover", func() {
+			cluster.Status.CurrentPrimary = "two"
+			Expect(explicitSynchronousStandbyNames(cluster)).To(
+				Equal("FIRST 1 (\"four\",\"one\",\"three\",\"two\")"))
+		})
+
+		It("keeps the default order when the topology has not been extracted", func() {
+			cluster.Status.Topology = apiv1.Topology{}
+			Expect(explicitSynchronousStandbyNames(cluster)).To(
+				Equal("FIRST 1 (\"four\",\"three\",\"two\",\"one\")"))
+		})
+	})
 })
@@ -154,5 +154,5 @@ func getSortedNonPrimaryHealthyInstanceNames(cluster *apiv1.Cluster) []string {
 	}
 
 	sort.Strings(nonPrimaryInstances)
-	return nonPrimaryInstances
+	return deprioritizeLaggingStandbys(cluster, nonPrimaryInstances)
 }
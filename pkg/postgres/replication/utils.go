@@ -18,7 +18,12 @@ package replication
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 )
 
 // escapePostgresConfLiteral escapes a value to make its representation
@@ -26,3 +31,60 @@ import (
 func escapePostgresConfLiteral(value string) string {
 	return fmt.Sprintf("\"%v\"", strings.ReplaceAll(value, "\"", "\"\""))
 }
+
+// deprioritizeLaggingStandbys moves the instances that have been deprioritized
+// because of their replication lag to the end of the passed list, so that
+// the more caught-up replicas are chosen as synchronous standbys
+func deprioritizeLaggingStandbys(cluster *apiv1.Cluster, instances []string) []string {
+	deprioritized := cluster.GetDeprioritizedSynchronousStandbys()
+	if len(deprioritized) == 0 {
+		return instances
+	}
+
+	result := make([]string, 0, len(instances))
+	var lagging []string
+	for _, instance := range instances {
+		if slices.Contains(deprioritized, instance) {
+			lagging = append(lagging, instance)
+			continue
+		}
+		result = append(result, instance)
+	}
+
+	return append(result, lagging...)
+}
+
+// preferCrossZoneStandbys moves the instances running in the same zone of
+// the current primary after the ones running in a different zone. When the
+// quorum-based method is used, the same-zone instances are only kept if
+// there are not enough cross-zone instances to satisfy the required number
+// of synchronous standbys
+func preferCrossZoneStandbys(cluster *apiv1.Cluster, instances []string) []string {
+	if !cluster.IsSynchronousCrossZonePreferred() || !cluster.Status.Topology.SuccessfullyExtracted {
+		return instances
+	}
+
+	topology := cluster.Status.Topology.Instances
+	primaryZone := topology[apiv1.PodName(cluster.Status.CurrentPrimary)][corev1.LabelTopologyZone]
+	if primaryZone == "" {
+		return instances
+	}
+
+	crossZone := make([]string, 0, len(instances))
+	var sameZone []string
+	for _, instance := range instances {
+		zone := topology[apiv1.PodName(instance)][corev1.LabelTopologyZone]
+		if zone != "" && zone != primaryZone {
+			crossZone = append(crossZone, instance)
+			continue
+		}
+		sameZone = append(sameZone, instance)
+	}
+
+	config := cluster.Spec.PostgresConfiguration.Synchronous
+	if config.Method == apiv1.SynchronousReplicaConfigurationMethodAny && len(crossZone) >= config.Number {
+		return crossZone
+	}
+
+	return append(crossZone, sameZone...)
+}
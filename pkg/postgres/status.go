@@ -19,6 +19,7 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
 	"github.com/cloudnative-pg/machinery/pkg/types"
@@ -37,6 +38,7 @@ type PostgresqlStatus struct {
 	ReplayPaused              bool        `json:"replayPaused"`
 	PendingRestart            bool        `json:"pendingRestart"`
 	PendingRestartForDecrease bool        `json:"pendingRestartForDecrease"`
+	LoadedConfigurationHash   string      `json:"loadedConfigurationHash,omitempty"`
 	IsWalReceiverActive       bool        `json:"isWalReceiverActive"`
 	IsPgRewindRunning         bool        `json:"isPgRewindRunning"`
 	MightBeUnavailable        bool        `json:"mightBeUnavailable"`
@@ -53,6 +55,50 @@ type PostgresqlStatus struct {
 	LastFailedWAL       string `json:"lastFailedWAL,omitempty"`
 	LastFailedWALTime   string `json:"lastFailedWALTime,omitempty"`
 
+	// Prepared transactions status
+
+	// The number of prepared transactions on the primary
+	PreparedTransactions int `json:"preparedTransactions,omitempty"`
+
+	// The age in seconds of the oldest prepared transaction on the primary
+	OldestPreparedTransactionAge int64 `json:"oldestPreparedTransactionAge,omitempty"`
+
+	// Wraparound status
+
+	// The age of the oldest unfrozen transaction ID among the databases
+	// of the primary
+	OldestXIDAge int64 `json:"oldestXIDAge,omitempty"`
+
+	// The database having the oldest unfrozen transaction ID
+	OldestXIDAgeDatabase string `json:"oldestXIDAgeDatabase,omitempty"`
+
+	// The age of the oldest unfrozen multixact ID among the databases
+	// of the primary
+	OldestMXIDAge int64 `json:"oldestMXIDAge,omitempty"`
+
+	// The database having the oldest unfrozen multixact ID
+	OldestMXIDAgeDatabase string `json:"oldestMXIDAgeDatabase,omitempty"`
+
+	// The value of `autovacuum_freeze_max_age` on the primary
+	AutovacuumFreezeMaxAge int64 `json:"autovacuumFreezeMaxAge,omitempty"`
+
+	// The value of `autovacuum_multixact_freeze_max_age` on the primary
+	AutovacuumMultixactFreezeMaxAge int64 `json:"autovacuumMultixactFreezeMaxAge,omitempty"`
+
+	// Connections status
+
+	// The number of client connections to the instance
+	ClientConnections int `json:"clientConnections,omitempty"`
+
+	// The number of connection slots available to non-superuser roles, that
+	// is `max_connections` minus the slots reserved to superusers
+	MaxClientConnections int `json:"maxClientConnections,omitempty"`
+
+	// Disk status
+
+	// The usage of the volume containing PGDATA
+	DataVolumeUsage *DiskUsage `json:"dataVolumeUsage,omitempty"`
+
 	// WAL Status
 
 	CurrentWAL string `json:"currentWAL,omitempty"`
@@ -90,6 +136,41 @@ type PostgresqlStatus struct {
 	//
 	// This field is never populated in the instance manager.
 	IsPodReady bool `json:"isPodReady"`
+
+	// The clock of the instance manager when the status has been
+	// generated, used to detect the clock skew between the instances
+	CurrentTime *time.Time `json:"currentTime,omitempty"`
+
+	// The difference between the clock of the instance and the one of the
+	// operator, which is zero unless the instance clock is outside the time
+	// window of the status request.
+	//
+	// This field is only populated by the operator.
+	ClockSkew time.Duration `json:"-"`
+}
+
+// DiskUsage contains the space usage of a volume as seen by the instance manager
+type DiskUsage struct {
+	TotalBytes     uint64 `json:"totalBytes"`
+	AvailableBytes uint64 `json:"availableBytes"`
+}
+
+// UsedBytes is the amount of space, in bytes, that is not available
+// for PostgreSQL anymore
+func (usage DiskUsage) UsedBytes() uint64 {
+	if usage.AvailableBytes > usage.TotalBytes {
+		return 0
+	}
+	return usage.TotalBytes - usage.AvailableBytes
+}
+
+// UsedPercentage is the percentage of the volume that is not available
+// for PostgreSQL anymore
+func (usage DiskUsage) UsedPercentage() float64 {
+	if usage.TotalBytes == 0 {
+		return 0
+	}
+	return float64(usage.UsedBytes()) * 100 / float64(usage.TotalBytes)
 }
 
 // PgStatReplication contains the replications of replicas as reported by the primary instance
@@ -105,6 +186,12 @@ type PgStatReplication struct {
 	ReplayLag       string    `json:"replayLag,omitempty"`
 	SyncState       string    `json:"syncState,omitempty"`
 	SyncPriority    string    `json:"syncPriority,omitempty"`
+	// The amount of WAL, in bytes, generated by the primary
+	// and not yet written by the standby
+	WriteLagBytes int64 `json:"writeLagBytes,omitempty"`
+	// The amount of WAL, in bytes, generated by the primary
+	// and not yet replayed by the standby
+	ReplayLagBytes int64 `json:"replayLagBytes,omitempty"`
 }
 
 // PgStatBasebackup contains the information for progress of basebackup as reported by the primary instance
@@ -131,6 +218,12 @@ func (status *PostgresqlStatus) AddPod(pod corev1.Pod) {
 	status.Node = pod.Spec.NodeName
 }
 
+// AreConnectionsExhausted checks if the client connections reached the
+// number of connection slots available to non-superuser roles
+func (status PostgresqlStatus) AreConnectionsExhausted() bool {
+	return status.MaxClientConnections > 0 && status.ClientConnections >= status.MaxClientConnections
+}
+
 // HasHTTPStatus checks if the instance manager is reporting this
 // instance as ready.
 //
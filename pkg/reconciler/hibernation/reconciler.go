@@ -18,6 +18,7 @@ package hibernation
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/cloudnative-pg/machinery/pkg/log"
@@ -27,6 +28,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources/status"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 )
 
@@ -39,8 +41,9 @@ func Reconcile(
 ) (*ctrl.Result, error) {
 	hibernationCondition := meta.FindStatusCondition(cluster.Status.Conditions, HibernationConditionType)
 	if hibernationCondition == nil {
-		// This means that hibernation has not been requested
-		return nil, nil
+		// This means that hibernation has not been requested, or that
+		// the cluster is being rehydrated
+		return nil, reconcileRehydration(ctx, c, cluster)
 	}
 
 	switch hibernationCondition.Reason {
@@ -50,11 +53,45 @@ func Reconcile(
 	case HibernationConditionReasonWaitingPodsDeletion:
 		return &ctrl.Result{RequeueAfter: 5 * time.Second}, nil
 
+	case HibernationConditionReasonHibernated:
+		return &ctrl.Result{}, reconcileHibernated(ctx, c, cluster)
+
 	default:
 		return &ctrl.Result{}, nil
 	}
 }
 
+// reconcileHibernated sets the phase of a cluster whose Pods have all been
+// deleted. The last primary is kept in the status, and its PVCs are
+// the first ones to be reattached when the cluster is rehydrated
+func reconcileHibernated(
+	ctx context.Context,
+	c client.Client,
+	cluster *apiv1.Cluster,
+) error {
+	if cluster.Status.Phase == apiv1.PhaseHibernated {
+		return nil
+	}
+
+	return status.RegisterPhase(ctx, c, cluster, apiv1.PhaseHibernated,
+		fmt.Sprintf("Cluster has been hibernated, the last primary was %s", cluster.Status.CurrentPrimary))
+}
+
+// reconcileRehydration moves a cluster out of the hibernated phase once the
+// hibernation has been lifted, while its Pods are being recreated
+func reconcileRehydration(
+	ctx context.Context,
+	c client.Client,
+	cluster *apiv1.Cluster,
+) error {
+	if cluster.Status.Phase != apiv1.PhaseHibernated {
+		return nil
+	}
+
+	return status.RegisterPhase(ctx, c, cluster, apiv1.PhaseWaitingForInstancesToBeActive,
+		fmt.Sprintf("Cluster is being rehydrated, starting from %s", cluster.Status.CurrentPrimary))
+}
+
 func reconcileDeletePods(
 	ctx context.Context,
 	c client.Client,
@@ -25,8 +25,10 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
@@ -82,6 +84,7 @@ var _ = Describe("Reconcile resources", func() {
 				},
 			},
 			Status: apiv1.ClusterStatus{
+				Phase: apiv1.PhaseHibernated,
 				Conditions: []metav1.Condition{
 					{
 						Type:   HibernationConditionType,
@@ -181,6 +184,69 @@ var _ = Describe("Reconcile resources", func() {
 	})
 })
 
+var _ = Describe("Hibernation phase", func() {
+	var (
+		cluster *apiv1.Cluster
+		cli     client.Client
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+				Annotations: map[string]string{
+					utils.HibernationAnnotationName: HibernationOn,
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				Phase:          apiv1.PhaseHealthy,
+				CurrentPrimary: "cluster-example-2",
+				Conditions: []metav1.Condition{
+					{
+						Type:   HibernationConditionType,
+						Status: metav1.ConditionTrue,
+						Reason: HibernationConditionReasonHibernated,
+					},
+				},
+			},
+		}
+		cli = fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(cluster).
+			WithStatusSubresource(cluster).
+			Build()
+	})
+
+	getCluster := func(ctx context.Context) *apiv1.Cluster {
+		var current apiv1.Cluster
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(cluster), &current)).To(Succeed())
+		return &current
+	}
+
+	It("is set once the Pods have been deleted, recording the last primary", func(ctx SpecContext) {
+		Expect(Reconcile(ctx, cli, cluster, nil)).ToNot(BeNil())
+
+		current := getCluster(ctx)
+		Expect(current.Status.Phase).To(Equal(apiv1.PhaseHibernated))
+		Expect(current.Status.PhaseReason).To(ContainSubstring("cluster-example-2"))
+		Expect(current.Status.CurrentPrimary).To(Equal("cluster-example-2"))
+	})
+
+	It("is left when the cluster is rehydrated", func(ctx SpecContext) {
+		Expect(Reconcile(ctx, cli, cluster, nil)).ToNot(BeNil())
+
+		cluster = getCluster(ctx)
+		delete(cluster.Annotations, utils.HibernationAnnotationName)
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, HibernationConditionType)
+		Expect(Reconcile(ctx, cli, cluster, nil)).To(BeNil())
+
+		current := getCluster(ctx)
+		Expect(current.Status.Phase).To(Equal(apiv1.PhaseWaitingForInstancesToBeActive))
+		Expect(current.Status.PhaseReason).To(ContainSubstring("cluster-example-2"))
+	})
+})
+
 func fakePod(name string, role string) corev1.Pod {
 	return corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
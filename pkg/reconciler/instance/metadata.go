@@ -39,7 +39,7 @@ func ReconcileMetadata(
 ) error {
 	contextLogger := log.FromContext(ctx)
 
-	for idx := range instances {
+	for _, idx := range getMetadataReconciliationOrder(cluster, instances) {
 		origInstance := instances[idx].DeepCopy()
 		instance := &instances[idx]
 
@@ -72,6 +72,27 @@ func ReconcileMetadata(
 	return nil
 }
 
+// getMetadataReconciliationOrder returns the indexes of the instances in the
+// order their metadata should be reconciled. The current primary comes last,
+// so that during a switchover the former primary loses its role label before
+// the new one gets it, and no more than one pod is ever labelled as primary
+func getMetadataReconciliationOrder(cluster *apiv1.Cluster, instances []corev1.Pod) []int {
+	result := make([]int, 0, len(instances))
+	primaryIdx := -1
+	for idx := range instances {
+		if instances[idx].Name == cluster.Status.CurrentPrimary {
+			primaryIdx = idx
+			continue
+		}
+		result = append(result, idx)
+	}
+	if primaryIdx != -1 {
+		result = append(result, primaryIdx)
+	}
+
+	return result
+}
+
 // updateClusterAnnotations checks if there are annotations specified in the cluster that are
 // not present in the pods, and if so applies them.
 // We do not support the case of removed annotations from the cluster resource.
@@ -21,7 +21,9 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
@@ -504,6 +506,78 @@ var _ = Describe("metadata reconciliation test", func() {
 	})
 })
 
+var _ = Describe("role labels during a switchover", func() {
+	It("never labels more than one pod as primary", func(ctx SpecContext) {
+		newInstance := func(name, role string) *corev1.Pod {
+			return &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      name,
+					Labels: map[string]string{
+						utils.ClusterRoleLabelName:         role,
+						utils.ClusterInstanceRoleLabelName: role,
+						utils.PodRoleLabelName:             string(utils.PodRoleInstance),
+						utils.InstanceNameLabelName:        name,
+					},
+				},
+			}
+		}
+
+		// The new primary is listed before the former one, which is
+		// the order that would expose two primaries at the same time
+		instances := []corev1.Pod{
+			*newInstance("cluster-example-2", specs.ClusterRoleLabelReplica),
+			*newInstance("cluster-example-1", specs.ClusterRoleLabelPrimary),
+			*newInstance("cluster-example-3", specs.ClusterRoleLabelReplica),
+		}
+		cluster := &apiv1.Cluster{
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-2",
+			},
+		}
+
+		countPrimaries := func(cli client.Client) int {
+			var podList corev1.PodList
+			Expect(cli.List(ctx, &podList, client.MatchingLabels{
+				utils.ClusterInstanceRoleLabelName: specs.ClusterRoleLabelPrimary,
+			})).To(Succeed())
+			return len(podList.Items)
+		}
+
+		var patchedPods []string
+		cli := fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(&instances[0], &instances[1], &instances[2]).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Patch: func(
+					ctx context.Context,
+					cli client.WithWatch,
+					obj client.Object,
+					patch client.Patch,
+					opts ...client.PatchOption,
+				) error {
+					if err := cli.Patch(ctx, obj, patch, opts...); err != nil {
+						return err
+					}
+					patchedPods = append(patchedPods, obj.GetName())
+					Expect(countPrimaries(cli)).To(BeNumerically("<=", 1))
+					return nil
+				},
+			}).
+			Build()
+
+		Expect(countPrimaries(cli)).To(Equal(1))
+		Expect(ReconcileMetadata(ctx, cli, cluster, instances)).To(Succeed())
+		Expect(patchedPods).To(Equal([]string{"cluster-example-1", "cluster-example-2"}))
+		Expect(countPrimaries(cli)).To(Equal(1))
+
+		var primary corev1.Pod
+		Expect(cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: "cluster-example-2"}, &primary)).
+			To(Succeed())
+		Expect(primary.Labels).To(HaveKeyWithValue(utils.ClusterInstanceRoleLabelName, specs.ClusterRoleLabelPrimary))
+	})
+})
+
 var _ = Describe("metadata update functions", func() {
 	Context("Given nil labels or annotations in the pod", func() {
 		var (
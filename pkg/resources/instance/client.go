@@ -306,6 +306,7 @@ func (r *statusClient) rawInstanceStatusRequest(
 	}
 
 	r.Client.Timeout = defaultRequestTimeout
+	requestTime := time.Now()
 	resp, err := r.Client.Do(req)
 	if err != nil {
 		result.Error = err
@@ -324,6 +325,7 @@ func (r *statusClient) rawInstanceStatusRequest(
 		result.Error = err
 		return result
 	}
+	responseTime := time.Now()
 
 	if resp.StatusCode != 200 {
 		result.Error = &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
@@ -336,9 +338,29 @@ func (r *statusClient) rawInstanceStatusRequest(
 		return result
 	}
 
+	if result.CurrentTime != nil {
+		result.ClockSkew = getClockSkew(*result.CurrentTime, requestTime, responseTime)
+	}
+
 	return result
 }
 
+// getClockSkew computes the skew of the clock of an instance, given the time
+// it reported and the time window of the request as seen by the operator.
+// Since the instance has generated its status during the request, any
+// instance time inside the window is compatible with synchronized clocks,
+// making the result independent of the network latency.
+func getClockSkew(instanceTime, requestTime, responseTime time.Time) time.Duration {
+	switch {
+	case instanceTime.Before(requestTime):
+		return instanceTime.Sub(requestTime)
+	case instanceTime.After(responseTime):
+		return instanceTime.Sub(responseTime)
+	default:
+		return 0
+	}
+}
+
 // HTTPScheme identifies a valid scheme: http, https
 type HTTPScheme string
 
@@ -24,6 +24,7 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
@@ -136,6 +137,21 @@ func buildInitDBFlags(cluster apiv1.Cluster) (initCommand []string) {
 	if encoding := config.Encoding; encoding != "" {
 		options = append(options, fmt.Sprintf("--encoding=%s", encoding))
 	}
+	if locale := config.Locale; locale != "" {
+		options = append(options, fmt.Sprintf("--locale=%s", locale))
+	}
+	if localeProvider := config.LocaleProvider; localeProvider != "" {
+		options = append(options, fmt.Sprintf("--locale-provider=%s", localeProvider))
+	}
+	if icuLocale := config.IcuLocale; icuLocale != "" {
+		options = append(options, fmt.Sprintf("--icu-locale=%s", icuLocale))
+	}
+	if icuRules := config.IcuRules; icuRules != "" {
+		options = append(options, fmt.Sprintf("--icu-rules=%s", icuRules))
+	}
+	if builtinLocale := config.BuiltinLocale; builtinLocale != "" {
+		options = append(options, fmt.Sprintf("--builtin-locale=%s", builtinLocale))
+	}
 	if localeCollate := config.LocaleCollate; localeCollate != "" {
 		options = append(options, fmt.Sprintf("--lc-collate=%s", localeCollate))
 	}
@@ -268,6 +284,53 @@ func RestoreReplicaInstance(cluster apiv1.Cluster, nodeSerial int) *batchv1.Job
 	return job
 }
 
+// majorUpgradeJobBackoffLimit is the number of times the major upgrade job
+// is retried. An interrupted upgrade either restarts from the existing data
+// directory or completes its replacement with the upgraded one, so retrying
+// it is safe
+const majorUpgradeJobBackoffLimit int32 = 3
+
+// CreateMajorUpgradeJob creates a job upgrading the data directory of an
+// instance to the major version of PostgreSQL in the image of the cluster.
+// The PostgreSQL installation of the previous major version is copied from
+// its image by an init container, to be used by pg_upgrade
+func CreateMajorUpgradeJob(cluster apiv1.Cluster, nodeSerial int, oldImage string) *batchv1.Job {
+	upgradeCommand := []string{
+		"/controller/manager",
+		"instance",
+		"upgrade",
+		"execute",
+	}
+
+	if cluster.Spec.Bootstrap != nil && cluster.Spec.Bootstrap.InitDB != nil {
+		upgradeCommand = append(upgradeCommand, buildInitDBFlags(cluster)...)
+	}
+
+	upgradeCommand = append(upgradeCommand, buildCommonInitJobFlags(cluster)...)
+
+	job := createPrimaryJob(cluster, nodeSerial, jobRoleMajorUpgrade, upgradeCommand)
+	job.Spec.BackoffLimit = ptr.To(majorUpgradeJobBackoffLimit)
+
+	prepareContainer := corev1.Container{
+		Name:            MajorUpgradePrepareContainerName,
+		Image:           oldImage,
+		ImagePullPolicy: cluster.Spec.ImagePullPolicy,
+		Command: []string{
+			"/controller/manager",
+			"instance",
+			"upgrade",
+			"prepare",
+		},
+		VolumeMounts:    createPostgresVolumeMounts(cluster),
+		Resources:       cluster.GetResources(),
+		SecurityContext: CreateContainerSecurityContext(cluster.GetSeccompProfile()),
+	}
+	addManagerLoggingOptions(cluster, &prepareContainer)
+	job.Spec.Template.Spec.InitContainers = append(job.Spec.Template.Spec.InitContainers, prepareContainer)
+
+	return job
+}
+
 func buildCommonInitJobFlags(cluster apiv1.Cluster) []string {
 	var flags []string
 
@@ -288,15 +351,24 @@ const (
 	jobRoleFullRecovery     jobRole = "full-recovery"
 	jobRoleJoin             jobRole = "join"
 	jobRoleSnapshotRecovery jobRole = "snapshot-recovery"
+	jobRoleMajorUpgrade     jobRole = "major-upgrade"
 )
 
-var jobRoleList = []jobRole{jobRoleImport, jobRoleInitDB, jobRolePGBaseBackup, jobRoleFullRecovery, jobRoleJoin}
+var jobRoleList = []jobRole{
+	jobRoleImport, jobRoleInitDB, jobRolePGBaseBackup, jobRoleFullRecovery, jobRoleJoin, jobRoleMajorUpgrade,
+}
 
 // getJobName returns a string indicating the job name
 func (role jobRole) getJobName(instanceName string) string {
 	return fmt.Sprintf("%s-%s", instanceName, role)
 }
 
+// GetMajorUpgradeJobName gets the name of the job upgrading the data
+// directory of a given instance to a newer major version
+func GetMajorUpgradeJobName(instanceName string) string {
+	return jobRoleMajorUpgrade.getJobName(instanceName)
+}
+
 // GetPossibleJobNames get all the possible job names for a given instance
 func GetPossibleJobNames(instanceName string) []string {
 	res := make([]string, len(jobRoleList))
@@ -347,7 +419,7 @@ func createPrimaryJob(cluster apiv1.Cluster, nodeSerial int, role jobRole, initC
 							EnvFrom:         envConfig.EnvFrom,
 							Command:         initCommand,
 							VolumeMounts:    createPostgresVolumeMounts(cluster),
-							Resources:       cluster.Spec.Resources,
+							Resources:       cluster.GetResources(),
 							SecurityContext: CreateContainerSecurityContext(cluster.GetSeccompProfile()),
 						},
 					},
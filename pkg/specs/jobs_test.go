@@ -165,4 +165,86 @@ var _ = Describe("Job created via InitDB", func() {
 		Expect(job.Spec.Template.Spec.Containers[0].Command).Should(ContainElement(
 			postInitApplicationSQLRefsFolder.toString()))
 	})
+
+	It("passes the locale settings to initdb", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{
+						Encoding:       "UTF8",
+						Locale:         "it_IT.UTF-8",
+						LocaleProvider: "icu",
+						IcuLocale:      "it-IT",
+						IcuRules:       "&a < g",
+					},
+				},
+			},
+		}
+		flags := buildInitDBFlags(cluster)
+		Expect(flags).To(HaveLen(2))
+		Expect(flags[0]).To(Equal("--initdb-flags"))
+		Expect(flags[1]).To(Equal(
+			"--encoding=UTF8 --locale=it_IT.UTF-8 --locale-provider=icu " +
+				"--icu-locale=it-IT '--icu-rules=&a < g'"))
+	})
+
+	It("passes the WAL segment size to initdb", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{
+						WalSegmentSize: 64,
+					},
+				},
+			},
+		}
+		flags := buildInitDBFlags(cluster)
+		Expect(flags).To(HaveLen(2))
+		Expect(flags[1]).To(Equal("--wal-segsize=64"))
+	})
+})
+
+var _ = Describe("Job upgrading the major version", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-example",
+			Namespace: "default",
+		},
+		Spec: apiv1.ClusterSpec{
+			ImageName: "ghcr.io/cloudnative-pg/postgresql:17.0",
+			Bootstrap: &apiv1.BootstrapConfiguration{
+				InitDB: &apiv1.BootstrapInitDB{
+					Encoding: "UTF8",
+				},
+			},
+			WalStorage: &apiv1.StorageConfiguration{},
+		},
+	}
+
+	It("runs pg_upgrade with the new image on the PVCs of the instance", func() {
+		job := CreateMajorUpgradeJob(cluster, 1, "ghcr.io/cloudnative-pg/postgresql:16.4")
+		Expect(job.Name).To(Equal(GetMajorUpgradeJobName("cluster-example-1")))
+
+		container := job.Spec.Template.Spec.Containers[0]
+		Expect(container.Image).To(Equal("ghcr.io/cloudnative-pg/postgresql:17.0"))
+		Expect(container.Command).To(ContainElements(
+			"upgrade", "execute", "--initdb-flags", "--encoding=UTF8", "--pg-wal", PgWalVolumePgWalPath))
+		Expect(job.Spec.Template.Spec.Volumes).To(ContainElement(HaveField("Name", "pgdata")))
+	})
+
+	It("sets explicitly how many times the upgrade is retried", func() {
+		job := CreateMajorUpgradeJob(cluster, 1, "ghcr.io/cloudnative-pg/postgresql:16.4")
+		Expect(job.Spec.BackoffLimit).To(HaveValue(Equal(majorUpgradeJobBackoffLimit)))
+	})
+
+	It("copies the previous PostgreSQL installation with the old image", func() {
+		job := CreateMajorUpgradeJob(cluster, 1, "ghcr.io/cloudnative-pg/postgresql:16.4")
+
+		initContainers := job.Spec.Template.Spec.InitContainers
+		Expect(initContainers).To(HaveLen(2))
+		Expect(initContainers[0].Name).To(Equal(BootstrapControllerContainerName))
+		Expect(initContainers[1].Name).To(Equal(MajorUpgradePrepareContainerName))
+		Expect(initContainers[1].Image).To(Equal("ghcr.io/cloudnative-pg/postgresql:16.4"))
+		Expect(initContainers[1].Command).To(ContainElements("upgrade", "prepare"))
+	})
 })
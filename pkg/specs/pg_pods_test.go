@@ -43,3 +43,30 @@ var _ = Describe("Extract the used image name", func() {
 		Expect(GetBootstrapControllerImageName(*pod)).To(Equal(configuration.Current.OperatorImageName))
 	})
 })
+
+var _ = Describe("The domain of the instances", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-example",
+			Namespace: "default",
+		},
+	}
+
+	It("is not set by default", func() {
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(pod.Spec.Hostname).To(Equal("cluster-example-1"))
+		Expect(pod.Spec.Subdomain).To(BeEmpty())
+	})
+
+	It("is the headless service when enabled", func() {
+		headlessCluster := cluster.DeepCopy()
+		headlessCluster.Spec.Managed = &apiv1.ManagedConfiguration{
+			Services: &apiv1.ManagedServices{
+				Headless: &apiv1.HeadlessServiceConfiguration{Enabled: true},
+			},
+		}
+		pod := PodWithExistingStorage(*headlessCluster, 1)
+		Expect(pod.Spec.Hostname).To(Equal("cluster-example-1"))
+		Expect(pod.Spec.Subdomain).To(Equal("cluster-example-headless"))
+	})
+})
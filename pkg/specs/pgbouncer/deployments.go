@@ -19,6 +19,8 @@ limitations under the License.
 package pgbouncer
 
 import (
+	"fmt"
+
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -50,7 +52,7 @@ func Deployment(pooler *apiv1.Pooler, cluster *apiv1.Cluster) (*appsv1.Deploymen
 		return nil, err
 	}
 
-	podTemplate := podspec.NewFrom(pooler.Spec.Template).
+	podTemplateBuilder := podspec.NewFrom(pooler.Spec.Template).
 		WithLabel(utils.PgbouncerNameLabel, pooler.Name).
 		WithLabel(utils.ClusterLabelName, cluster.Name).
 		WithLabel(utils.PodRoleLabelName, string(utils.PodRolePooler)).
@@ -117,10 +119,24 @@ func Deployment(pooler *apiv1.Pooler, cluster *apiv1.Cluster) (*appsv1.Deploymen
 					Port: intstr.FromInt32(pgBouncerConfig.PgBouncerPort),
 				},
 			},
-		}, false).
-		Build()
+		}, false)
+
+	if pooler.Spec.PgBouncer != nil && pooler.Spec.PgBouncer.IsDrainEnabled() {
+		podTemplateBuilder.WithContainerPreStopHook("pgbouncer", &corev1.LifecycleHandler{
+			Exec: &corev1.ExecAction{
+				Command: []string{
+					"/controller/manager",
+					"pgbouncer",
+					"drain",
+					fmt.Sprintf("--timeout=%ds", pooler.Spec.PgBouncer.GetDrainTimeoutSeconds()),
+				},
+			},
+		}, true)
+	}
 
-	return &appsv1.Deployment{
+	podTemplate := podTemplateBuilder.Build()
+
+	deployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      pooler.Name,
 			Namespace: pooler.Namespace,
@@ -149,7 +165,30 @@ func Deployment(pooler *apiv1.Pooler, cluster *apiv1.Cluster) (*appsv1.Deploymen
 			},
 			Strategy: getDeploymentStrategy(pooler.Spec.DeploymentStrategy),
 		},
-	}, nil
+	}
+	inheritClusterLabels(&deployment.ObjectMeta, cluster)
+	inheritClusterLabels(&deployment.Spec.Template.ObjectMeta, cluster)
+
+	return deployment, nil
+}
+
+// inheritClusterLabels adds to the metadata of a pooler resource the labels
+// required by the cluster on every managed object, via `.spec.inheritedMetadata`.
+// The labels already set on the resource take precedence
+func inheritClusterLabels(object *metav1.ObjectMeta, cluster *apiv1.Cluster) {
+	labels := cluster.GetFixedInheritedLabels()
+	if len(labels) == 0 {
+		return
+	}
+
+	if object.Labels == nil {
+		object.Labels = make(map[string]string, len(labels))
+	}
+	for key, value := range labels {
+		if _, found := object.Labels[key]; !found {
+			object.Labels[key] = value
+		}
+	}
 }
 
 func computeTemplateHash(pooler *apiv1.Pooler, operatorImageName string) (string, error) {
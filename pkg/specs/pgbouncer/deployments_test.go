@@ -156,4 +156,26 @@ var _ = Describe("Deployment", func() {
 		Expect(deployment.Spec.Template.Spec.Containers[0].ReadinessProbe.TCPSocket.Port).
 			To(Equal(intstr.FromInt32(pgBouncerConfig.PgBouncerPort)))
 	})
+
+	It("doesn't set a preStop hook when the draining is disabled", func() {
+		deployment, err := Deployment(pooler, cluster)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(deployment.Spec.Template.Spec.Containers[0].Lifecycle).To(BeNil())
+	})
+
+	It("drains the connections before terminating the pod", func() {
+		pooler.Spec.PgBouncer.Drain = &apiv1.PgBouncerDrainConfiguration{
+			Enabled:        true,
+			TimeoutSeconds: 25,
+		}
+
+		deployment, err := Deployment(pooler, cluster)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(deployment.Spec.Template.Spec.Containers[0].Lifecycle.PreStop.Exec.Command).To(Equal([]string{
+			"/controller/manager",
+			"pgbouncer",
+			"drain",
+			"--timeout=25s",
+		}))
+	})
 })
@@ -26,12 +26,13 @@ import (
 
 // PoolerPodMonitorManager builds the PodMonitor for the pooler resource
 type PoolerPodMonitorManager struct {
-	pooler *apiv1.Pooler
+	pooler  *apiv1.Pooler
+	cluster *apiv1.Cluster
 }
 
 // NewPoolerPodMonitorManager returns a new instance of PoolerPodMonitorManager
-func NewPoolerPodMonitorManager(pooler *apiv1.Pooler) *PoolerPodMonitorManager {
-	return &PoolerPodMonitorManager{pooler: pooler}
+func NewPoolerPodMonitorManager(pooler *apiv1.Pooler, cluster *apiv1.Cluster) *PoolerPodMonitorManager {
+	return &PoolerPodMonitorManager{pooler: pooler, cluster: cluster}
 }
 
 // IsPodMonitorEnabled returns a boolean indicating if the PodMonitor should exists or not
@@ -49,6 +50,7 @@ func (c PoolerPodMonitorManager) BuildPodMonitor() *monitoringv1.PodMonitor {
 		},
 	}
 
+	inheritClusterLabels(&meta, c.cluster)
 	utils.SetAsOwnedBy(&meta, c.pooler.ObjectMeta, c.pooler.TypeMeta)
 
 	endpoint := monitoringv1.PodMetricsEndpoint{
@@ -62,7 +64,9 @@ func (c PoolerPodMonitorManager) BuildPodMonitor() *monitoringv1.PodMonitor {
 
 	spec := monitoringv1.PodMonitorSpec{
 		Selector: metav1.LabelSelector{
-			MatchLabels: meta.Labels,
+			MatchLabels: map[string]string{
+				utils.PgbouncerNameLabel: c.pooler.Name,
+			},
 		},
 		PodMetricsEndpoints: []monitoringv1.PodMetricsEndpoint{endpoint},
 	}
@@ -49,7 +49,7 @@ var _ = Describe("PoolerPodMonitorManager", func() {
 
 	Context("when calling IsPodMonitorEnabled", func() {
 		It("returns the correct value", func() {
-			manager := NewPoolerPodMonitorManager(pooler)
+			manager := NewPoolerPodMonitorManager(pooler, &apiv1.Cluster{})
 
 			Expect(manager.IsPodMonitorEnabled()).To(BeFalse())
 
@@ -64,7 +64,7 @@ var _ = Describe("PoolerPodMonitorManager", func() {
 		})
 
 		It("returns the correct PodMonitor object", func() {
-			manager := NewPoolerPodMonitorManager(pooler)
+			manager := NewPoolerPodMonitorManager(pooler, &apiv1.Cluster{})
 
 			podMonitor := manager.BuildPodMonitor()
 
@@ -86,7 +86,7 @@ var _ = Describe("PoolerPodMonitorManager", func() {
 	Context("when monitoring if not configured", func() {
 		It("does not panic", func() {
 			pooler := apiv1.Pooler{}
-			manager := NewPoolerPodMonitorManager(&pooler)
+			manager := NewPoolerPodMonitorManager(&pooler, &apiv1.Cluster{})
 			podMonitor := manager.BuildPodMonitor()
 			Expect(podMonitor).ToNot(BeNil())
 		})
@@ -26,14 +26,16 @@ import (
 )
 
 // ServiceAccount creates a service account for a given pooler
-func ServiceAccount(pooler *apiv1.Pooler) *corev1.ServiceAccount {
-	return &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+func ServiceAccount(pooler *apiv1.Pooler, cluster *apiv1.Cluster) *corev1.ServiceAccount {
+	serviceAccount := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
 		Name: pooler.Name, Namespace: pooler.Namespace,
 	}}
+	inheritClusterLabels(&serviceAccount.ObjectMeta, cluster)
+	return serviceAccount
 }
 
 // Role creates a role for a given pooler
-func Role(pooler *apiv1.Pooler) *v1.Role {
+func Role(pooler *apiv1.Pooler, cluster *apiv1.Cluster) *v1.Role {
 	secretNames := []string{pooler.GetAuthQuerySecretName()}
 	if pooler.Status.Secrets != nil {
 		if pooler.Status.Secrets.ServerCA.Name != "" {
@@ -49,7 +51,7 @@ func Role(pooler *apiv1.Pooler) *v1.Role {
 		}
 	}
 
-	return &v1.Role{ObjectMeta: metav1.ObjectMeta{
+	role := &v1.Role{ObjectMeta: metav1.ObjectMeta{
 		Name: pooler.Name, Namespace: pooler.Namespace,
 	}, Rules: []v1.PolicyRule{
 		{
@@ -98,9 +100,13 @@ func Role(pooler *apiv1.Pooler) *v1.Role {
 			ResourceNames: secretNames,
 		},
 	}}
+	inheritClusterLabels(&role.ObjectMeta, cluster)
+	return role
 }
 
 // RoleBinding creates a role binding for a given pooler
-func RoleBinding(pooler *apiv1.Pooler) v1.RoleBinding {
-	return specs.CreateRoleBinding(pooler.ObjectMeta)
+func RoleBinding(pooler *apiv1.Pooler, cluster *apiv1.Cluster) v1.RoleBinding {
+	roleBinding := specs.CreateRoleBinding(pooler.ObjectMeta)
+	inheritClusterLabels(&roleBinding.ObjectMeta, cluster)
+	return roleBinding
 }
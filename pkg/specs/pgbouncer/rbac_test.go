@@ -42,7 +42,7 @@ var _ = Describe("Pooler ServiceAccount, Role, and RoleBinding", func() {
 
 	Context("when creating a ServiceAccount", func() {
 		It("returns the correct ServiceAccount", func() {
-			serviceAccount := ServiceAccount(pooler)
+			serviceAccount := ServiceAccount(pooler, &apiv1.Cluster{})
 			Expect(serviceAccount.Name).To(Equal(pooler.Name))
 			Expect(serviceAccount.Namespace).To(Equal(pooler.Namespace))
 		})
@@ -50,7 +50,7 @@ var _ = Describe("Pooler ServiceAccount, Role, and RoleBinding", func() {
 
 	Context("when creating a Role", func() {
 		It("returns the correct Role", func() {
-			role := Role(pooler)
+			role := Role(pooler, &apiv1.Cluster{})
 			Expect(role.Name).To(Equal(pooler.Name))
 			Expect(role.Namespace).To(Equal(pooler.Namespace))
 			Expect(role.Rules).To(HaveLen(3))
@@ -66,7 +66,7 @@ var _ = Describe("Pooler ServiceAccount, Role, and RoleBinding", func() {
 
 	Context("when creating a RoleBinding", func() {
 		It("returns the correct RoleBinding", func() {
-			roleBinding := RoleBinding(pooler)
+			roleBinding := RoleBinding(pooler, &apiv1.Cluster{})
 			Expect(roleBinding.Name).To(Equal(pooler.Name))
 			Expect(roleBinding.Namespace).To(Equal(pooler.Namespace))
 		})
@@ -69,6 +69,11 @@ const (
 	// controller inside the Pod file system
 	BootstrapControllerContainerName = "bootstrap-controller"
 
+	// MajorUpgradePrepareContainerName is the name of the container copying
+	// the PostgreSQL installation of the previous major version inside the
+	// Pod file system, to be used by pg_upgrade
+	MajorUpgradePrepareContainerName = "prepare-major-upgrade"
+
 	// PgDataPath is the path to PGDATA variable
 	PgDataPath = "/var/lib/postgresql/data/pgdata"
 
@@ -166,7 +171,7 @@ func CreateClusterPodSpec(
 	gracePeriod int64,
 	enableHTTPS bool,
 ) corev1.PodSpec {
-	return corev1.PodSpec{
+	podSpec := corev1.PodSpec{
 		Hostname: podName,
 		InitContainers: []corev1.Container{
 			createBootstrapContainer(cluster),
@@ -185,6 +190,13 @@ func CreateClusterPodSpec(
 		TerminationGracePeriodSeconds: &gracePeriod,
 		TopologySpreadConstraints:     cluster.Spec.TopologySpreadConstraints,
 	}
+
+	// Publish the instance under the domain of the headless service
+	if cluster.IsHeadlessServiceEnabled() {
+		podSpec.Subdomain = cluster.GetServiceHeadlessName()
+	}
+
+	return podSpec
 }
 
 // createPostgresContainers create the PostgreSQL containers that are
@@ -234,7 +246,7 @@ func createPostgresContainers(cluster apiv1.Cluster, envConfig EnvConfig, enable
 				"instance",
 				"run",
 			},
-			Resources: cluster.Spec.Resources,
+			Resources: cluster.GetResources(),
 			Ports: []corev1.ContainerPort{
 				{
 					Name:          "postgresql",
@@ -267,6 +279,25 @@ func createPostgresContainers(cluster apiv1.Cluster, envConfig EnvConfig, enable
 		containers[0].Command = append(containers[0].Command, "--metrics-port-tls")
 	}
 
+	if utils.IsInstancePprofEnabled(&cluster.ObjectMeta) {
+		containers[0].Command = append(containers[0].Command, "--pprof-server")
+	}
+
+	if cluster.IsSwitchoverOnShutdownEnabled() {
+		containers[0].Lifecycle = &corev1.Lifecycle{
+			PreStop: &corev1.LifecycleHandler{
+				Exec: &corev1.ExecAction{
+					Command: []string{
+						"/controller/manager",
+						"instance",
+						"prestop",
+						fmt.Sprintf("--timeout=%ds", cluster.GetSwitchoverOnShutdownTimeout()),
+					},
+				},
+			},
+		}
+	}
+
 	addManagerLoggingOptions(cluster, &containers[0])
 
 	// if user customizes the liveness probe timeout, we need to adjust the failure threshold
@@ -343,58 +374,94 @@ func CreateAffinitySection(clusterName string, config apiv1.AffinityConfiguratio
 // CreateGeneratedAntiAffinity generates the affinity terms the operator is in charge for if enabled,
 // return nil if disabled or an error occurred, as invalid values should be validated before this method is called
 func CreateGeneratedAntiAffinity(clusterName string, config apiv1.AffinityConfiguration) *corev1.Affinity {
-	// We have no anti affinity section if the user don't have it configured
-	if config.EnablePodAntiAffinity != nil && !(*config.EnablePodAntiAffinity) {
-		return nil
-	}
-	affinity := &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{}}
 	topologyKey := config.TopologyKey
 	if len(topologyKey) == 0 {
 		topologyKey = "kubernetes.io/hostname"
 	}
 
-	podAffinityTerm := corev1.PodAffinityTerm{
-		LabelSelector: &metav1.LabelSelector{
-			MatchExpressions: []metav1.LabelSelectorRequirement{
-				{
-					Key:      utils.ClusterLabelName,
-					Operator: metav1.LabelSelectorOpIn,
-					Values: []string{
-						clusterName,
+	podAntiAffinity := &corev1.PodAntiAffinity{}
+
+	// We have no instance anti affinity terms if the user don't have it configured
+	if config.EnablePodAntiAffinity == nil || *config.EnablePodAntiAffinity {
+		podAffinityTerm := corev1.PodAffinityTerm{
+			LabelSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{
+						Key:      utils.ClusterLabelName,
+						Operator: metav1.LabelSelectorOpIn,
+						Values: []string{
+							clusterName,
+						},
+					},
+					{
+						Key:      utils.PodRoleLabelName,
+						Operator: metav1.LabelSelectorOpIn,
+						Values: []string{
+							string(utils.PodRoleInstance),
+						},
 					},
 				},
+			},
+			TopologyKey: topologyKey,
+		}
+
+		// Switch pod anti-affinity type:
+		// - if it is "required", 'RequiredDuringSchedulingIgnoredDuringExecution' will be properly set.
+		// - if it is "preferred",'PreferredDuringSchedulingIgnoredDuringExecution' will be properly set.
+		// - by default, no term is set.
+		switch config.PodAntiAffinityType {
+		case apiv1.PodAntiAffinityTypeRequired:
+			podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = []corev1.PodAffinityTerm{
+				podAffinityTerm,
+			}
+		case apiv1.PodAntiAffinityTypePreferred:
+			podAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = []corev1.WeightedPodAffinityTerm{
 				{
-					Key:      utils.PodRoleLabelName,
-					Operator: metav1.LabelSelectorOpIn,
-					Values: []string{
-						string(utils.PodRoleInstance),
+					Weight:          100,
+					PodAffinityTerm: podAffinityTerm,
+				},
+			}
+		}
+	}
+
+	// The instances avoid the topology domain of the primary. The term is
+	// always preferred, so that the instances can still be scheduled when
+	// there are not enough nodes
+	if config.EnablePrimaryReplicaAntiAffinity {
+		podAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+			podAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+			corev1.WeightedPodAffinityTerm{
+				Weight: 100,
+				PodAffinityTerm: corev1.PodAffinityTerm{
+					LabelSelector: &metav1.LabelSelector{
+						MatchExpressions: []metav1.LabelSelectorRequirement{
+							{
+								Key:      utils.ClusterLabelName,
+								Operator: metav1.LabelSelectorOpIn,
+								Values: []string{
+									clusterName,
+								},
+							},
+							{
+								Key:      utils.ClusterInstanceRoleLabelName,
+								Operator: metav1.LabelSelectorOpIn,
+								Values: []string{
+									ClusterRoleLabelPrimary,
+								},
+							},
+						},
 					},
+					TopologyKey: topologyKey,
 				},
-			},
-		},
-		TopologyKey: topologyKey,
+			})
 	}
 
-	// Switch pod anti-affinity type:
-	// - if it is "required", 'RequiredDuringSchedulingIgnoredDuringExecution' will be properly set.
-	// - if it is "preferred",'PreferredDuringSchedulingIgnoredDuringExecution' will be properly set.
-	// - by default, return nil.
-	switch config.PodAntiAffinityType {
-	case apiv1.PodAntiAffinityTypeRequired:
-		affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = []corev1.PodAffinityTerm{
-			podAffinityTerm,
-		}
-	case apiv1.PodAntiAffinityTypePreferred:
-		affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = []corev1.WeightedPodAffinityTerm{
-			{
-				Weight:          100,
-				PodAffinityTerm: podAffinityTerm,
-			},
-		}
-	default:
+	if len(podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution) == 0 &&
+		len(podAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution) == 0 {
 		return nil
 	}
-	return affinity
+
+	return &corev1.Affinity{PodAntiAffinity: podAntiAffinity}
 }
 
 // CreatePodSecurityContext defines the security context under which the containers are running
@@ -449,7 +516,7 @@ func PodWithExistingStorage(cluster apiv1.Cluster, nodeSerial int) *corev1.Pod {
 		pod.Spec.PriorityClassName = cluster.Spec.PriorityClassName
 	}
 
-	if configuration.Current.CreateAnyService {
+	if configuration.Current.CreateAnyService && pod.Spec.Subdomain == "" {
 		pod.Spec.Subdomain = cluster.GetServiceAnyName()
 	}
 
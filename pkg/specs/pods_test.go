@@ -23,9 +23,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -136,6 +138,64 @@ var _ = Describe("Create affinity section", func() {
 		Expect(affinity).To(BeNil())
 	})
 
+	When("the primary-replica anti-affinity is enabled", func() {
+		primaryTerm := func(affinity *corev1.Affinity) corev1.PodAffinityTerm {
+			GinkgoHelper()
+			Expect(affinity).NotTo(BeNil())
+			terms := affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+			Expect(terms).NotTo(BeEmpty())
+			return terms[len(terms)-1].PodAffinityTerm
+		}
+
+		It("avoids the node of the primary of the cluster", func() {
+			config := v1.AffinityConfiguration{
+				PodAntiAffinityType:              "preferred",
+				EnablePrimaryReplicaAntiAffinity: true,
+			}
+			affinity := CreateAffinitySection(clusterName, config)
+			Expect(affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution).To(HaveLen(2))
+
+			term := primaryTerm(affinity)
+			Expect(term.TopologyKey).To(Equal("kubernetes.io/hostname"))
+			Expect(term.LabelSelector.MatchExpressions).To(ContainElements(
+				metav1.LabelSelectorRequirement{
+					Key:      utils.ClusterLabelName,
+					Operator: metav1.LabelSelectorOpIn,
+					Values:   []string{clusterName},
+				},
+				metav1.LabelSelectorRequirement{
+					Key:      utils.ClusterInstanceRoleLabelName,
+					Operator: metav1.LabelSelectorOpIn,
+					Values:   []string{ClusterRoleLabelPrimary},
+				},
+			))
+		})
+
+		It("stays preferred when the instance anti-affinity is required", func() {
+			config := v1.AffinityConfiguration{
+				PodAntiAffinityType:              "required",
+				TopologyKey:                      "topology.kubernetes.io/zone",
+				EnablePrimaryReplicaAntiAffinity: true,
+			}
+			affinity := CreateAffinitySection(clusterName, config)
+			Expect(affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution).To(HaveLen(1))
+			Expect(affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution).To(HaveLen(1))
+			Expect(primaryTerm(affinity).TopologyKey).To(Equal("topology.kubernetes.io/zone"))
+		})
+
+		It("is set even when the instance anti-affinity is disabled", func() {
+			config := v1.AffinityConfiguration{
+				EnablePodAntiAffinity:            pointerToBool(false),
+				EnablePrimaryReplicaAntiAffinity: true,
+			}
+			affinity := CreateAffinitySection(clusterName, config)
+			Expect(affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution).To(BeEmpty())
+			Expect(affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution).To(HaveLen(1))
+			Expect(primaryTerm(affinity).LabelSelector.MatchExpressions).To(ContainElement(
+				HaveField("Key", utils.ClusterInstanceRoleLabelName)))
+		})
+	})
+
 	When("given additional affinity terms", func() {
 		When("generated pod anti-affinity is enabled", func() {
 			It("sets both pod affinity and anti-affinity correctly if passed and set to required", func() {
@@ -712,6 +772,20 @@ var _ = Describe("PodSpec drift detection", func() {
 		Expect(specsMatch).To(BeFalse())
 	})
 
+	It("detects a change of the subdomain", func() {
+		podSpec1 := corev1.PodSpec{
+			Containers: []corev1.Container{},
+		}
+		podSpec2 := corev1.PodSpec{
+			Subdomain:  "cluster-example-headless",
+			Containers: []corev1.Container{},
+		}
+
+		specsMatch, diff := ComparePodSpecs(podSpec1, podSpec2)
+		Expect(diff).To(ContainSubstring("subdomain"))
+		Expect(specsMatch).To(BeFalse())
+	})
+
 	It("detects missing volume mounts in postgres container", func() {
 		podSpec1 := corev1.PodSpec{
 			Containers: []corev1.Container{
@@ -934,3 +1008,80 @@ var _ = Describe("Compute liveness probe failure threshold", func() {
 		Expect(getLivenessProbeFailureThreshold(31)).To(BeNumerically("==", 4))
 	})
 })
+
+var _ = Describe("Instance manager pprof server", func() {
+	It("is not enabled by default", func() {
+		containers := createPostgresContainers(v1.Cluster{}, EnvConfig{}, false)
+		Expect(containers[0].Command).ToNot(ContainElement("--pprof-server"))
+	})
+
+	It("is enabled by the instancePprof annotation", func() {
+		cluster := v1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{utils.InstancePprofAnnotationName: "enabled"},
+			},
+		}
+		containers := createPostgresContainers(cluster, EnvConfig{}, false)
+		Expect(containers[0].Command).To(ContainElement("--pprof-server"))
+	})
+})
+
+var _ = Describe("Switchover on shutdown", func() {
+	It("doesn't add the preStop hook by default", func() {
+		containers := createPostgresContainers(v1.Cluster{}, EnvConfig{}, false)
+		Expect(containers[0].Lifecycle).To(BeNil())
+	})
+
+	It("adds the preStop hook waiting for the switchover when enabled", func() {
+		cluster := v1.Cluster{
+			Spec: v1.ClusterSpec{
+				MaxStopDelay:         600,
+				SmartShutdownTimeout: ptr.To(int32(60)),
+				SwitchoverOnShutdown: &v1.SwitchoverOnShutdownConfiguration{Enabled: true},
+			},
+		}
+		containers := createPostgresContainers(cluster, EnvConfig{}, false)
+		Expect(containers[0].Lifecycle).ToNot(BeNil())
+		Expect(containers[0].Lifecycle.PreStop.Exec.Command).To(Equal([]string{
+			"/controller/manager", "instance", "prestop", "--timeout=540s",
+		}))
+	})
+
+	It("triggers a rolling update when toggled", func() {
+		cluster := v1.Cluster{}
+		podSpec1 := corev1.PodSpec{Containers: createPostgresContainers(cluster, EnvConfig{}, false)}
+		cluster.Spec.SwitchoverOnShutdown = &v1.SwitchoverOnShutdownConfiguration{Enabled: true}
+		podSpec2 := corev1.PodSpec{Containers: createPostgresContainers(cluster, EnvConfig{}, false)}
+
+		specsMatch, diff := ComparePodSpecs(podSpec1, podSpec2)
+		Expect(diff).To(ContainSubstring("lifecycle"))
+		Expect(specsMatch).To(BeFalse())
+	})
+})
+
+var _ = Describe("Size tiers", func() {
+	DescribeTable("set the resources of the PostgreSQL container",
+		func(sizeTier v1.SizeTier, cpu, memory string) {
+			cluster := v1.Cluster{Spec: v1.ClusterSpec{SizeTier: sizeTier}}
+			containers := createPostgresContainers(cluster, EnvConfig{}, false)
+			expected := corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			}
+			Expect(containers[0].Resources.Requests).To(Equal(expected))
+			Expect(containers[0].Resources.Limits).To(Equal(expected))
+		},
+		Entry("small", v1.SizeTierSmall, "1", "2Gi"),
+		Entry("medium", v1.SizeTierMedium, "2", "8Gi"),
+		Entry("large", v1.SizeTierLarge, "4", "32Gi"),
+	)
+
+	It("are overridden by the explicit resources", func() {
+		resources := corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("3Gi")},
+		}
+		cluster := v1.Cluster{Spec: v1.ClusterSpec{SizeTier: v1.SizeTierLarge, Resources: resources}}
+		containers := createPostgresContainers(cluster, EnvConfig{}, false)
+		Expect(containers[0].Resources).To(Equal(resources))
+	})
+})
@@ -76,6 +76,9 @@ func ComparePodSpecs(
 		"hostname": func() bool {
 			return currentPodSpec.Hostname == targetPodSpec.Hostname
 		},
+		"subdomain": func() bool {
+			return currentPodSpec.Subdomain == targetPodSpec.Subdomain
+		},
 		"termination-grace-period": func() bool {
 			return currentPodSpec.TerminationGracePeriodSeconds == nil && targetPodSpec.TerminationGracePeriodSeconds == nil ||
 				*currentPodSpec.TerminationGracePeriodSeconds == *targetPodSpec.TerminationGracePeriodSeconds
@@ -189,6 +192,9 @@ func doContainersMatch(currentContainer, targetContainer corev1.Container) (bool
 		"ports": func() bool {
 			return reflect.DeepEqual(currentContainer.Ports, targetContainer.Ports)
 		},
+		"lifecycle": func() bool {
+			return reflect.DeepEqual(currentContainer.Lifecycle, targetContainer.Lifecycle)
+		},
 		"security-context": func() bool {
 			return reflect.DeepEqual(currentContainer.SecurityContext, targetContainer.SecurityContext)
 		},
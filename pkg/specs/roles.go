@@ -202,6 +202,12 @@ func getInvolvedConfigMapNames(cluster apiv1.Cluster) []string {
 		}
 	}
 
+	// The instance manager needs to read the PostgreSQL configuration
+	// files to be included
+	for _, configMapName := range cluster.Spec.PostgresConfiguration.IncludeConfigMaps {
+		involvedConfigMapNames = append(involvedConfigMapNames, configMapName.Name)
+	}
+
 	return cleanupResourceList(involvedConfigMapNames)
 }
 
@@ -234,20 +240,8 @@ func externalClusterSecrets(cluster apiv1.Cluster) []string {
 			result = append(result,
 				server.Password.Name)
 		}
-		if barmanObjStore := server.BarmanObjectStore; barmanObjStore != nil {
-			result = append(
-				result,
-				s3CredentialsSecrets(barmanObjStore.BarmanCredentials.AWS)...)
-			result = append(
-				result,
-				azureCredentialsSecrets(barmanObjStore.BarmanCredentials.Azure)...)
-			result = append(
-				result,
-				googleCredentialsSecrets(barmanObjStore.BarmanCredentials.Google)...)
-			if barmanObjStore.EndpointCA != nil {
-				result = append(result, barmanObjStore.EndpointCA.Name)
-			}
-		}
+		result = append(result, barmanObjectStoreSecrets(server.BarmanObjectStore)...)
+		result = append(result, barmanObjectStoreSecrets(server.WALObjectStore)...)
 	}
 
 	return result
@@ -269,6 +263,11 @@ func backupSecrets(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) []string {
 			googleCredentialsSecrets(cluster.Spec.Backup.BarmanObjectStore.BarmanCredentials.Google)...)
 	}
 
+	// Secrets needed to access the object store containing the WAL files, if separated
+	if cluster.Spec.Backup != nil && cluster.Spec.Backup.BarmanObjectStore != nil {
+		result = append(result, barmanObjectStoreSecrets(cluster.Spec.Backup.WALObjectStore)...)
+	}
+
 	// Secrets needed by Barman, if set
 	if cluster.Spec.Backup.IsBarmanEndpointCASet() {
 		result = append(
@@ -291,6 +290,29 @@ func backupSecrets(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) []string {
 	return result
 }
 
+// barmanObjectStoreSecrets returns the secrets needed to access the passed object store
+func barmanObjectStoreSecrets(barmanObjStore *apiv1.BarmanObjectStoreConfiguration) []string {
+	if barmanObjStore == nil {
+		return nil
+	}
+
+	var result []string
+	result = append(
+		result,
+		s3CredentialsSecrets(barmanObjStore.BarmanCredentials.AWS)...)
+	result = append(
+		result,
+		azureCredentialsSecrets(barmanObjStore.BarmanCredentials.Azure)...)
+	result = append(
+		result,
+		googleCredentialsSecrets(barmanObjStore.BarmanCredentials.Google)...)
+	if barmanObjStore.EndpointCA != nil {
+		result = append(result, barmanObjStore.EndpointCA.Name)
+	}
+
+	return result
+}
+
 func azureCredentialsSecrets(azureCredentials *apiv1.AzureCredentials) []string {
 	var result []string
 
@@ -79,6 +79,14 @@ var _ = Describe("Roles", func() {
 				},
 			},
 			PostgresConfiguration: apiv1.PostgresConfiguration{
+				IncludeConfigMaps: []apiv1.ConfigMapKeySelector{
+					{
+						LocalObjectReference: apiv1.LocalObjectReference{
+							Name: "testIncludedConfiguration",
+						},
+						Key: "postgresql.conf",
+					},
+				},
 				LDAP: &apiv1.LDAPConfig{
 					BindSearchAuth: &apiv1.LDAPBindSearchAuth{
 						BindPassword: &corev1.SecretKeySelector{
@@ -172,7 +180,7 @@ var _ = Describe("Roles", func() {
 		serviceAccount := CreateRole(cluster, &backupOrigin)
 		Expect(serviceAccount.Name).To(Equal(cluster.Name))
 		Expect(serviceAccount.Namespace).To(Equal(cluster.Namespace))
-		Expect(serviceAccount.Rules[0].ResourceNames).To(ConsistOf("thisTest", "testConfigMapKeySelector"))
+		Expect(serviceAccount.Rules[0].ResourceNames).To(ConsistOf("thisTest", "testConfigMapKeySelector", "testIncludedConfiguration"))
 		Expect(serviceAccount.Rules[1].ResourceNames).To(ConsistOf(
 			"testReplicationTLSSecret",
 			"testClientCASecret",
@@ -277,6 +285,50 @@ var _ = Describe("Secrets", func() {
 		Expect(secrets).To(ConsistOf("test-secret", "test-access", "test-region", "test-session", "test-endpoint-ca-name"))
 	})
 
+	It("doesn't need any secret when the credentials are inherited", func() {
+		cluster.Spec = apiv1.ClusterSpec{
+			Backup: &apiv1.BackupConfiguration{
+				BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+					BarmanCredentials: apiv1.BarmanCredentials{
+						AWS: &apiv1.S3Credentials{InheritFromIAMRole: true},
+					},
+				},
+				WALObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+					BarmanCredentials: apiv1.BarmanCredentials{
+						Google: &apiv1.GoogleCredentials{GKEEnvironment: true},
+					},
+				},
+			},
+		}
+		Expect(backupSecrets(cluster, nil)).To(BeEmpty())
+	})
+
+	It("includes the secrets of the separate WAL object store", func() {
+		cluster.Spec = apiv1.ClusterSpec{
+			Backup: &apiv1.BackupConfiguration{
+				BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+					BarmanCredentials: apiv1.BarmanCredentials{
+						AWS: &apiv1.S3Credentials{
+							SecretAccessKeyReference: &apiv1.SecretKeySelector{
+								LocalObjectReference: apiv1.LocalObjectReference{Name: "data-secret"},
+							},
+						},
+					},
+				},
+				WALObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+					BarmanCredentials: apiv1.BarmanCredentials{
+						Azure: &apiv1.AzureCredentials{
+							StorageKey: &apiv1.SecretKeySelector{
+								LocalObjectReference: apiv1.LocalObjectReference{Name: "wal-secret"},
+							},
+						},
+					},
+				},
+			},
+		}
+		Expect(backupSecrets(cluster, nil)).To(ConsistOf("data-secret", "wal-secret"))
+	})
+
 	It("should contain default secrets only", func() {
 		Expect(getInvolvedSecretNames(cluster, nil)).To(Equal([]string{
 			"thisTest-app",
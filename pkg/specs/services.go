@@ -59,6 +59,27 @@ func CreateClusterAnyService(cluster apiv1.Cluster) *corev1.Service {
 	}
 }
 
+// CreateClusterHeadlessService create a headless service insisting on all
+// the pods, publishing each of them under a stable DNS name
+func CreateClusterHeadlessService(cluster apiv1.Cluster) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cluster.GetServiceHeadlessName(),
+			Namespace: cluster.Namespace,
+		},
+		Spec: corev1.ServiceSpec{
+			Type:                     corev1.ServiceTypeClusterIP,
+			ClusterIP:                corev1.ClusterIPNone,
+			PublishNotReadyAddresses: true,
+			Ports:                    buildInstanceServicePorts(),
+			Selector: map[string]string{
+				utils.ClusterLabelName: cluster.Name,
+				utils.PodRoleLabelName: string(utils.PodRoleInstance),
+			},
+		},
+	}
+}
+
 // CreateClusterReadService create a service insisting on all the ready pods
 func CreateClusterReadService(cluster apiv1.Cluster) *corev1.Service {
 	return &corev1.Service{
@@ -97,7 +118,7 @@ func CreateClusterReadOnlyService(cluster apiv1.Cluster) *corev1.Service {
 
 // CreateClusterReadWriteService create a service insisting on the primary pod
 func CreateClusterReadWriteService(cluster apiv1.Cluster) *corev1.Service {
-	return &corev1.Service{
+	service := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cluster.GetServiceReadWriteName(),
 			Namespace: cluster.Namespace,
@@ -111,6 +132,14 @@ func CreateClusterReadWriteService(cluster apiv1.Cluster) *corev1.Service {
 			},
 		},
 	}
+
+	if hostname := cluster.GetReadWriteServiceExternalDNSHostname(); hostname != "" {
+		service.Annotations = map[string]string{
+			utils.ExternalDNSHostnameAnnotationName: hostname,
+		}
+	}
+
+	return service
 }
 
 // BuildManagedServices creates a list of Kubernetes Services based on the
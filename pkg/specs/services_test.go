@@ -42,6 +42,15 @@ var _ = Describe("Services specification", func() {
 		Expect(service.Spec.Selector[utils.PodRoleLabelName]).To(Equal(string(utils.PodRoleInstance)))
 	})
 
+	It("create a configured -headless service", func() {
+		service := CreateClusterHeadlessService(postgresql)
+		Expect(service.Name).To(Equal("clustername-headless"))
+		Expect(service.Spec.ClusterIP).To(Equal(corev1.ClusterIPNone))
+		Expect(service.Spec.PublishNotReadyAddresses).To(BeTrue())
+		Expect(service.Spec.Selector[utils.ClusterLabelName]).To(Equal("clustername"))
+		Expect(service.Spec.Selector[utils.PodRoleLabelName]).To(Equal(string(utils.PodRoleInstance)))
+	})
+
 	It("create a configured -r service", func() {
 		service := CreateClusterReadService(postgresql)
 		Expect(service.Name).To(Equal("clustername-r"))
@@ -64,6 +73,18 @@ var _ = Describe("Services specification", func() {
 		Expect(service.Spec.PublishNotReadyAddresses).To(BeFalse())
 		Expect(service.Spec.Selector[utils.ClusterLabelName]).To(Equal("clustername"))
 		Expect(service.Spec.Selector[utils.ClusterInstanceRoleLabelName]).To(Equal(ClusterRoleLabelPrimary))
+		Expect(service.Annotations).ToNot(HaveKey(utils.ExternalDNSHostnameAnnotationName))
+	})
+
+	It("annotates the -rw service with the external DNS host name", func() {
+		cluster := postgresql.DeepCopy()
+		cluster.Spec.Managed = &apiv1.ManagedConfiguration{
+			Services: &apiv1.ManagedServices{
+				RW: &apiv1.ReadWriteServiceConfiguration{ExternalDNSHostname: "db.example.com"},
+			},
+		}
+		service := CreateClusterReadWriteService(*cluster)
+		Expect(service.Annotations).To(HaveKeyWithValue(utils.ExternalDNSHostnameAnnotationName, "db.example.com"))
 	})
 })
 
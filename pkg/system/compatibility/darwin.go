@@ -20,7 +20,21 @@ limitations under the License.
 // Package compatibility provides a layer to cross-compile with other OS than Linux
 package compatibility
 
+import "syscall"
+
 // SetCoredumpFilter for Windows compatibility
 func SetCoredumpFilter(_ string) error {
 	return nil
 }
+
+// GetDiskUsage returns the total and the available space, in bytes,
+// of the filesystem containing the passed path
+func GetDiskUsage(path string) (total uint64, available uint64, err error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, 0, err
+	}
+
+	blockSize := uint64(stat.Bsize)
+	return stat.Blocks * blockSize, stat.Bavail * blockSize, nil
+}
@@ -22,6 +22,7 @@ package compatibility
 
 import (
 	"os"
+	"syscall"
 )
 
 // SetCoredumpFilter set the value of /proc/self/coredump_filter
@@ -29,3 +30,15 @@ func SetCoredumpFilter(coredumpFilter string) error {
 	coredumpFilterFile := "/proc/self/coredump_filter"
 	return os.WriteFile(coredumpFilterFile, []byte(coredumpFilter), 0o600)
 }
+
+// GetDiskUsage returns the total and the available space, in bytes,
+// of the filesystem containing the passed path
+func GetDiskUsage(path string) (total uint64, available uint64, err error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, 0, err
+	}
+
+	blockSize := uint64(stat.Bsize)
+	return stat.Blocks * blockSize, stat.Bavail * blockSize, nil
+}
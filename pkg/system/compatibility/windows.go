@@ -20,7 +20,14 @@ limitations under the License.
 // Package compatibility provides a layer to cross-compile with other OS than Linux
 package compatibility
 
+import "errors"
+
 // SetCoredumpFilter for Windows compatibility
 func SetCoredumpFilter(_ string) error {
 	return nil
 }
+
+// GetDiskUsage for Windows compatibility
+func GetDiskUsage(_ string) (uint64, uint64, error) {
+	return 0, 0, errors.New("disk usage detection is not supported on Windows")
+}
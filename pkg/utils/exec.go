@@ -24,20 +24,44 @@ package utils
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/util/exec"
+	"k8s.io/client-go/util/retry"
 )
 
 // ErrorContainerNotFound is raised when an Exec call is invoked against
 // a non existing container
 var ErrorContainerNotFound = fmt.Errorf("container not found")
 
+// DefaultExecRetries is the default number of times an idempotent command
+// is retried after a transient failure
+const DefaultExecRetries = 3
+
+// DefaultExecBackoff is the backoff used to retry idempotent commands
+// when no other policy has been configured
+var DefaultExecBackoff = NewExecBackoff(DefaultExecRetries)
+
+// NewExecBackoff creates the backoff used to retry a command inside a
+// pod for the passed number of times, where zero disables the retries
+func NewExecBackoff(retries int) wait.Backoff {
+	return wait.Backoff{
+		Steps:    max(retries, 0) + 1,
+		Duration: time.Second,
+		Factor:   2.0,
+		Jitter:   0.1,
+	}
+}
+
 // ExecCommand executes a command inside the pod, and returns its result
 func ExecCommand(
 	ctx context.Context,
@@ -108,3 +132,63 @@ func ExecCommand(
 
 	return stdout.String(), stderr.String(), nil
 }
+
+// ExecCommandWithRetry executes an idempotent command inside the pod,
+// retrying it with the passed backoff when it fails for a transient reason,
+// such as a timeout or an overloaded API server. The timeout is applied to
+// every attempt. Commands having side effects must not use this function,
+// as they may be executed more than once
+func ExecCommandWithRetry(
+	ctx context.Context,
+	client kubernetes.Interface,
+	config *rest.Config,
+	pod corev1.Pod,
+	containerName string,
+	timeout *time.Duration,
+	backoff wait.Backoff,
+	command ...string,
+) (string, string, error) {
+	return execWithRetry(ctx, backoff, IsExecErrorTransient, func() (string, string, error) {
+		return ExecCommand(ctx, client, config, pod, containerName, timeout, command...)
+	})
+}
+
+// execWithRetry invokes execFunc until it succeeds, it fails with an
+// error that isRetryable rejects, or the backoff is exhausted
+func execWithRetry(
+	ctx context.Context,
+	backoff wait.Backoff,
+	isRetryable func(error) bool,
+	execFunc func() (string, string, error),
+) (string, string, error) {
+	var stdout, stderr string
+	err := retry.OnError(backoff, func(err error) bool {
+		return ctx.Err() == nil && isRetryable(err)
+	}, func() error {
+		var err error
+		stdout, stderr, err = execFunc()
+		return err
+	})
+	return stdout, stderr, err
+}
+
+// IsExecErrorTransient checks if an error returned by ExecCommand may
+// disappear by running the command again. The errors raised by the command
+// itself, such as a non-zero exit code, are not considered transient
+func IsExecErrorTransient(err error) bool {
+	if err == nil || errors.Is(err, ErrorContainerNotFound) || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var exitErr exec.ExitError
+	return !errors.As(err, &exitErr)
+}
+
+// IsExecErrorBeforeStart checks if an error returned by ExecCommand was
+// raised by the API server while establishing the connection to the pod,
+// meaning that the command has not been started. This is the only case
+// where a command having side effects can be safely retried
+func IsExecErrorBeforeStart(err error) bool {
+	var statusErr apierrs.APIStatus
+	return errors.As(err, &statusErr)
+}
@@ -0,0 +1,125 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/exec"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Executing commands with retries", func() {
+	fastBackoff := wait.Backoff{
+		Steps:    4,
+		Duration: 10 * time.Millisecond,
+		Factor:   2.0,
+	}
+
+	// slowExec simulates a command taking the passed durations, one per
+	// attempt, to complete, and failing when they exceed the timeout
+	slowExec := func(ctx context.Context, timeout time.Duration, durations ...time.Duration) (func() (
+		string, string, error), *int,
+	) {
+		attempts := 0
+		return func() (string, string, error) {
+			duration := durations[min(attempts, len(durations)-1)]
+			attempts++
+
+			timedCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			select {
+			case <-time.After(duration):
+				return "done", "", nil
+			case <-timedCtx.Done():
+				return "", "", fmt.Errorf("cmd: [psql]\nerror: %w", timedCtx.Err())
+			}
+		}, &attempts
+	}
+
+	It("succeeds when a slow command completes within the timeout", func(ctx SpecContext) {
+		execFunc, attempts := slowExec(ctx, 200*time.Millisecond, 100*time.Millisecond)
+		stdout, _, err := execWithRetry(ctx, fastBackoff, IsExecErrorTransient, execFunc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stdout).To(Equal("done"))
+		Expect(*attempts).To(Equal(1))
+	})
+
+	It("succeeds when a slow command completes within the retry window", func(ctx SpecContext) {
+		execFunc, attempts := slowExec(ctx, 50*time.Millisecond,
+			100*time.Millisecond, 100*time.Millisecond, 20*time.Millisecond)
+		stdout, _, err := execWithRetry(ctx, fastBackoff, IsExecErrorTransient, execFunc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stdout).To(Equal("done"))
+		Expect(*attempts).To(Equal(3))
+	})
+
+	It("fails when the command keeps timing out", func(ctx SpecContext) {
+		execFunc, attempts := slowExec(ctx, 10*time.Millisecond, 100*time.Millisecond)
+		_, _, err := execWithRetry(ctx, fastBackoff, IsExecErrorTransient, execFunc)
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+		Expect(*attempts).To(Equal(fastBackoff.Steps))
+	})
+
+	It("doesn't retry a command that failed with an exit code", func(ctx SpecContext) {
+		attempts := 0
+		_, _, err := execWithRetry(ctx, fastBackoff, IsExecErrorTransient, func() (string, string, error) {
+			attempts++
+			return "", "", exec.CodeExitError{Err: fmt.Errorf("command failed"), Code: 1}
+		})
+		Expect(err).To(HaveOccurred())
+		Expect(attempts).To(Equal(1))
+	})
+})
+
+var _ = Describe("Classifying the errors of executed commands", func() {
+	unavailableErr := apierrs.NewServiceUnavailable("the server is busy")
+	exitErr := exec.CodeExitError{Err: fmt.Errorf("command failed"), Code: 1}
+	timeoutErr := fmt.Errorf("cmd: [psql]\nerror: %w", context.DeadlineExceeded)
+
+	It("considers transient the errors not raised by the command", func() {
+		Expect(IsExecErrorTransient(unavailableErr)).To(BeTrue())
+		Expect(IsExecErrorTransient(timeoutErr)).To(BeTrue())
+		Expect(IsExecErrorTransient(fmt.Errorf("cmd: [psql]\nerror: %w", exitErr))).To(BeFalse())
+		Expect(IsExecErrorTransient(ErrorContainerNotFound)).To(BeFalse())
+		Expect(IsExecErrorTransient(context.Canceled)).To(BeFalse())
+		Expect(IsExecErrorTransient(nil)).To(BeFalse())
+	})
+
+	It("detects the errors raised before the command was started", func() {
+		Expect(IsExecErrorBeforeStart(unavailableErr)).To(BeTrue())
+		Expect(IsExecErrorBeforeStart(apierrs.NewForbidden(
+			schema.GroupResource{Resource: "pods"}, "cluster-example-1", fmt.Errorf("denied")))).To(BeTrue())
+		Expect(IsExecErrorBeforeStart(timeoutErr)).To(BeFalse())
+		Expect(IsExecErrorBeforeStart(exitErr)).To(BeFalse())
+	})
+})
+
+var _ = Describe("Exec backoff", func() {
+	It("runs the command once more than the number of retries", func() {
+		Expect(NewExecBackoff(3).Steps).To(Equal(4))
+		Expect(NewExecBackoff(0).Steps).To(Equal(1))
+		Expect(NewExecBackoff(-1).Steps).To(Equal(1))
+	})
+})
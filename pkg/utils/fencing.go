@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"slices"
 	"sort"
+	"time"
 
 	"github.com/cloudnative-pg/machinery/pkg/stringset"
 	corev1 "k8s.io/api/core/v1"
@@ -39,14 +40,33 @@ var (
 	// ErrorSingleInstanceUnfencing is emitted when unfencing a single instance
 	// while all the cluster is fenced
 	ErrorSingleInstanceUnfencing = errors.New("unfencing an instance while the whole cluster is fenced is not supported")
+
+	// ErrorFencedUntilSyntax is emitted when the fencedUntil annotation
+	// is not a valid RFC3339 timestamp
+	ErrorFencedUntilSyntax = errors.New("fencedUntil annotation is not a valid RFC3339 timestamp")
 )
 
 const (
 	// FenceAllInstances is the wildcard that, if put inside the fenced instances list, will fence every
 	// CNPG instance
 	FenceAllInstances = "*"
+
+	// FencePrimarySelector is the selector that, if put inside the fenced instances list, will fence
+	// the current primary instance, whichever it is
+	FencePrimarySelector = "role=primary"
+
+	// FenceReplicasSelector is the selector that, if put inside the fenced instances list, will fence
+	// every instance except the current primary, including the ones joining the cluster later
+	FenceReplicasSelector = "role=replica"
 )
 
+// IsFencingSelector checks if the passed entry of the fenced instances list
+// is a role selector, which is resolved against the status of the cluster
+// instead of naming a specific instance
+func IsFencingSelector(name string) bool {
+	return name == FencePrimarySelector || name == FenceReplicasSelector
+}
+
 // GetFencedInstances gets the set of fenced servers from the annotations
 func GetFencedInstances(annotations map[string]string) (*stringset.Data, error) {
 	fencedInstances, ok := annotations[FencedInstanceAnnotation]
@@ -62,6 +82,22 @@ func GetFencedInstances(annotations map[string]string) (*stringset.Data, error)
 	return stringset.From(fencedInstancesList), nil
 }
 
+// GetFencedUntil gets the time after which the fencing should be lifted
+// from the annotations, returning nil if the fencing never expires
+func GetFencedUntil(annotations map[string]string) (*time.Time, error) {
+	fencedUntil, ok := annotations[FencedUntilAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	result, err := time.Parse(time.RFC3339, fencedUntil)
+	if err != nil {
+		return nil, ErrorFencedUntilSyntax
+	}
+
+	return &result, nil
+}
+
 // setFencedInstances sets the list of fenced servers inside the annotations
 func setFencedInstances(object metav1.Object, data *stringset.Data) error {
 	annotations := object.GetAnnotations()
@@ -70,6 +106,7 @@ func setFencedInstances(object metav1.Object, data *stringset.Data) error {
 	}()
 	if data.Len() == 0 {
 		delete(annotations, FencedInstanceAnnotation)
+		delete(annotations, FencedUntilAnnotation)
 		return nil
 	}
 
@@ -189,7 +226,7 @@ func (fb *FencingMetadataExecutor) Execute(ctx context.Context, key types.Namesp
 	}
 
 	for _, name := range fb.instanceNames {
-		if name != FenceAllInstances {
+		if name != FenceAllInstances && !IsFencingSelector(name) {
 			var pod corev1.Pod
 			if err := fb.cli.Get(ctx, client.ObjectKey{Namespace: key.Namespace, Name: name}, &pod); err != nil {
 				return fmt.Errorf("node %s not found in namespace %s: %w", name, key.Namespace, err)
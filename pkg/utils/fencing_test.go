@@ -18,6 +18,7 @@ package utils
 
 import (
 	"encoding/json"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -44,6 +45,18 @@ var _ = Describe("Fencing annotation handling", func() {
 			Expect(modified).To(BeTrue())
 			Expect(clusterMeta.Annotations).NotTo(HaveKey(FencedInstanceAnnotation))
 		})
+		It("should remove the expiry time when the last instance is unfenced", func() {
+			clusterMeta := metav1.ObjectMeta{
+				Annotations: map[string]string{
+					FencedInstanceAnnotation: jsonMarshal("cluster-example-1"),
+					FencedUntilAnnotation:    "2030-01-01T00:00:00Z",
+				},
+			}
+			modified, err := removeFencedInstance("cluster-example-1", &clusterMeta)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(modified).To(BeTrue())
+			Expect(clusterMeta.Annotations).NotTo(HaveKey(FencedUntilAnnotation))
+		})
 		It("should correctly remove only that instance when unfenced", func() {
 			clusterMeta := metav1.ObjectMeta{
 				Annotations: map[string]string{
@@ -118,4 +131,26 @@ var _ = Describe("Fencing annotation handling", func() {
 				To(HaveKeyWithValue(FencedInstanceAnnotation, jsonMarshal("cluster-example-1")))
 		})
 	})
+
+	When("An expiry time is set", func() {
+		It("should parse a valid RFC3339 timestamp", func() {
+			fencedUntil, err := GetFencedUntil(map[string]string{
+				FencedUntilAnnotation: "2030-01-01T10:00:00+02:00",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fencedUntil).NotTo(BeNil())
+			Expect(fencedUntil.Equal(time.Date(2030, 1, 1, 8, 0, 0, 0, time.UTC))).To(BeTrue())
+		})
+		It("should return nil when the annotation is missing", func() {
+			fencedUntil, err := GetFencedUntil(map[string]string{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fencedUntil).To(BeNil())
+		})
+		It("should reject an invalid timestamp", func() {
+			_, err := GetFencedUntil(map[string]string{
+				FencedUntilAnnotation: "tomorrow",
+			})
+			Expect(err).To(MatchError(ErrorFencedUntilSyntax))
+		})
+	})
 })
@@ -18,6 +18,7 @@ package utils
 
 import (
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 )
 
 // JobHasOneCompletion Completion check if a certain job is complete
@@ -39,3 +40,15 @@ func FilterJobsWithOneCompletion(jobList []batchv1.Job) []batchv1.Job {
 	}
 	return result
 }
+
+// GetJobFailedCondition returns the condition reporting the failure of a
+// job, or nil if the job didn't fail
+func GetJobFailedCondition(job batchv1.Job) *batchv1.JobCondition {
+	for idx := range job.Status.Conditions {
+		condition := &job.Status.Conditions[idx]
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return condition
+		}
+	}
+	return nil
+}
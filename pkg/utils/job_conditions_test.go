@@ -18,6 +18,7 @@ package utils
 
 import (
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -40,4 +41,24 @@ var _ = Describe("Job conditions", func() {
 		Expect(JobHasOneCompletion(nonCompleteJob)).To(BeFalse())
 		Expect(JobHasOneCompletion(completeJob)).To(BeTrue())
 	})
+
+	It("detects if a certain job has failed", func() {
+		failedJob := batchv1.Job{
+			Status: batchv1.JobStatus{
+				Failed: 7,
+				Conditions: []batchv1.JobCondition{
+					{
+						Type:   batchv1.JobFailed,
+						Status: corev1.ConditionTrue,
+						Reason: batchv1.JobReasonBackoffLimitExceeded,
+					},
+				},
+			},
+		}
+
+		Expect(GetJobFailedCondition(nonCompleteJob)).To(BeNil())
+		Expect(GetJobFailedCondition(completeJob)).To(BeNil())
+		Expect(GetJobFailedCondition(failedJob)).ToNot(BeNil())
+		Expect(GetJobFailedCondition(failedJob).Reason).To(Equal(batchv1.JobReasonBackoffLimitExceeded))
+	})
 })
@@ -17,7 +17,9 @@ limitations under the License.
 package utils
 
 import (
+	"encoding/json"
 	"reflect"
+	"slices"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -107,6 +109,10 @@ const (
 	// This is required for Azure but can be set in other environments
 	AppArmorAnnotationPrefix = "container.apparmor.security.beta.kubernetes.io"
 
+	// ExternalDNSHostnameAnnotationName is the annotation used by ExternalDNS
+	// to publish a DNS record for a service
+	ExternalDNSHostnameAnnotationName = "external-dns.alpha.kubernetes.io/hostname"
+
 	// ReconciliationLoopAnnotationName is the name of the annotation controlling
 	// the status of the reconciliation loop for the cluster
 	ReconciliationLoopAnnotationName = MetadataNamespace + "/reconciliationLoop"
@@ -156,6 +162,11 @@ const (
 	// latest reload time trigger by external
 	ClusterReloadAnnotationName = MetadataNamespace + "/reloadedAt"
 
+	// ReplicationCredentialsRotationAnnotationName is the name of the annotation
+	// containing the latest time a rotation of the streaming replication
+	// credentials has been requested
+	ReplicationCredentialsRotationAnnotationName = MetadataNamespace + "/rotateReplicationCredentialsAt"
+
 	// PVCStatusAnnotationName is the name of the annotation that shows the current status of the PVC.
 	// The status can be "initializing", "ready" or "detached"
 	PVCStatusAnnotationName = MetadataNamespace + "/pvcStatus"
@@ -181,6 +192,11 @@ const (
 	// If the list contain the "*" element, every node is fenced.
 	FencedInstanceAnnotation = MetadataNamespace + "/fencedInstances"
 
+	// FencedUntilAnnotation is the annotation holding the RFC3339 timestamp after which
+	// the operator automatically lifts the fencing of the instances listed in
+	// FencedInstanceAnnotation
+	FencedUntilAnnotation = MetadataNamespace + "/fencedUntil"
+
 	// CNPGHashAnnotationName is the name of the annotation containing the hash of the resource used by operator
 	// expect the pooler that uses PoolerSpecHashAnnotationName
 	CNPGHashAnnotationName = MetadataNamespace + "/hash"
@@ -229,6 +245,32 @@ const (
 	// PluginPortAnnotationName is the name of the annotation containing the
 	// port the plugin is listening to
 	PluginPortAnnotationName = MetadataNamespace + "/pluginPort"
+
+	// InstancePprofAnnotationName is the name of the annotation enabling the
+	// pprof server of the instance manager, bound to localhost only.
+	// The value can be "enabled" or "disabled"
+	InstancePprofAnnotationName = MetadataNamespace + "/instancePprof"
+
+	// LogLevelAnnotationName is the name of the annotation overriding the
+	// level of the logs emitted by the operator while reconciling a cluster.
+	// The value can be "error", "warning", "info", "debug" or "trace"
+	LogLevelAnnotationName = MetadataNamespace + "/logLevel"
+
+	// SafeModeAnnotationName is the name of the annotation putting a cluster
+	// in safe mode, where the operator keeps updating its status without
+	// changing its instances. The value can be "true" or "false"
+	SafeModeAnnotationName = MetadataNamespace + "/safeMode"
+
+	// AllowMajorUpgradeAnnotationName is the name of the annotation allowing
+	// the PostgreSQL image of a cluster to be changed to a newer major version.
+	// The value can be "true" or "false"
+	AllowMajorUpgradeAnnotationName = MetadataNamespace + "/allowMajorUpgrade"
+
+	// DefaultServicesMetadataAnnotationName is the name of the annotation
+	// containing, as a JSON object, the keys of the labels and annotations
+	// set on a default service from the `defaultServicesMetadata` option,
+	// used to remove them when they are removed from the option
+	DefaultServicesMetadataAnnotationName = MetadataNamespace + "/defaultServicesMetadata"
 )
 
 type annotationStatus string
@@ -433,6 +475,72 @@ func IsWalArchivingDisabled(object *metav1.ObjectMeta) bool {
 	return object.Annotations[SkipWalArchiving] == string(annotationStatusEnabled)
 }
 
+// IsInstancePprofEnabled returns a boolean indicating if the instance
+// manager should expose the pprof server
+func IsInstancePprofEnabled(object *metav1.ObjectMeta) bool {
+	return object.Annotations[InstancePprofAnnotationName] == string(annotationStatusEnabled)
+}
+
+// IsSafeModeEnabled returns a boolean indicating if the operator should
+// only update the status of the cluster, without changing its instances
+func IsSafeModeEnabled(object *metav1.ObjectMeta) bool {
+	return object.Annotations[SafeModeAnnotationName] == "true"
+}
+
+// IsMajorUpgradeAllowed returns a boolean indicating if the PostgreSQL
+// image can be changed to a newer major version
+func IsMajorUpgradeAllowed(object *metav1.ObjectMeta) bool {
+	return object.Annotations[AllowMajorUpgradeAnnotationName] == "true"
+}
+
+// DefaultServicesMetadataKeys contains the keys of the labels and
+// annotations set on a default service from the `defaultServicesMetadata`
+// option
+type DefaultServicesMetadataKeys struct {
+	Labels      []string `json:"labels,omitempty"`
+	Annotations []string `json:"annotations,omitempty"`
+}
+
+// SetDefaultServicesMetadataKeys stores the passed keys in the
+// DefaultServicesMetadataAnnotationName annotation, removing it when
+// there are none
+func SetDefaultServicesMetadataKeys(object *metav1.ObjectMeta, keys DefaultServicesMetadataKeys) {
+	if len(keys.Labels) == 0 && len(keys.Annotations) == 0 {
+		delete(object.Annotations, DefaultServicesMetadataAnnotationName)
+		return
+	}
+
+	slices.Sort(keys.Labels)
+	slices.Sort(keys.Annotations)
+	value, err := json.Marshal(keys)
+	if err != nil {
+		// This can't happen, as we are marshalling a list of strings
+		panic(err)
+	}
+
+	if object.Annotations == nil {
+		object.Annotations = make(map[string]string)
+	}
+	object.Annotations[DefaultServicesMetadataAnnotationName] = string(value)
+}
+
+// GetDefaultServicesMetadataKeys returns the keys stored in the
+// DefaultServicesMetadataAnnotationName annotation, ignoring an
+// invalid value
+func GetDefaultServicesMetadataKeys(object metav1.ObjectMeta) DefaultServicesMetadataKeys {
+	var keys DefaultServicesMetadataKeys
+	value := object.Annotations[DefaultServicesMetadataAnnotationName]
+	if value == "" {
+		return keys
+	}
+
+	if err := json.Unmarshal([]byte(value), &keys); err != nil {
+		return DefaultServicesMetadataKeys{}
+	}
+
+	return keys
+}
+
 func mergeMap(receiver, giver map[string]string) map[string]string {
 	for key, value := range giver {
 		receiver[key] = value
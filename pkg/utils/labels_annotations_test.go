@@ -177,3 +177,34 @@ var _ = Describe("Pod spec reconciliation", func() {
 		Expect(IsPodSpecReconciliationDisabled(objectMeta)).To(BeTrue())
 	})
 })
+
+var _ = Describe("Default services metadata keys", func() {
+	It("stores the keys in the annotation, sorted", func() {
+		var objectMeta metav1.ObjectMeta
+		SetDefaultServicesMetadataKeys(&objectMeta, DefaultServicesMetadataKeys{
+			Labels:      []string{"team", "app"},
+			Annotations: []string{"one"},
+		})
+		Expect(objectMeta.Annotations).To(HaveKeyWithValue(DefaultServicesMetadataAnnotationName,
+			`{"labels":["app","team"],"annotations":["one"]}`))
+		Expect(GetDefaultServicesMetadataKeys(objectMeta)).To(Equal(DefaultServicesMetadataKeys{
+			Labels:      []string{"app", "team"},
+			Annotations: []string{"one"},
+		}))
+	})
+
+	It("removes the annotation when there are no keys", func() {
+		objectMeta := metav1.ObjectMeta{
+			Annotations: map[string]string{DefaultServicesMetadataAnnotationName: `{"labels":["team"]}`},
+		}
+		SetDefaultServicesMetadataKeys(&objectMeta, DefaultServicesMetadataKeys{})
+		Expect(objectMeta.Annotations).ToNot(HaveKey(DefaultServicesMetadataAnnotationName))
+	})
+
+	It("ignores an invalid annotation", func() {
+		objectMeta := metav1.ObjectMeta{
+			Annotations: map[string]string{DefaultServicesMetadataAnnotationName: "team"},
+		}
+		Expect(GetDefaultServicesMetadataKeys(objectMeta)).To(BeZero())
+	})
+})
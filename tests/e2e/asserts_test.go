@@ -2371,7 +2371,6 @@ func collectAndAssertDefaultMetricsPresentOnEachPod(
 			"cnpg_backends_waiting_total",
 			"cnpg_pg_postmaster_start_time",
 			"cnpg_pg_replication",
-			"cnpg_pg_stat_archiver",
 			"cnpg_pg_stat_bgwriter",
 			"cnpg_pg_stat_database",
 		}
@@ -2392,8 +2391,15 @@ func collectAndAssertDefaultMetricsPresentOnEachPod(
 			// error should be zero on each pod metrics
 			Expect(strings.Contains(out, "cnpg_collector_last_collection_error 0")).Should(BeTrue(),
 				"Metric collection issues on %v.\nCollected metrics:\n%v", podName, out)
+
+			// the archiver statistics are only exported by the primary
+			podMetrics := defaultMetrics
+			if specs.IsPodPrimary(pod) {
+				podMetrics = append(slices.Clone(defaultMetrics), "cnpg_pg_stat_archiver")
+			}
+
 			// verify that, default set of monitoring queries should not be existed on each pod
-			for _, data := range defaultMetrics {
+			for _, data := range podMetrics {
 				if expectPresent {
 					Expect(strings.Contains(out, data)).Should(BeTrue(),
 						"Metric collection issues on pod %v."+
@@ -19,6 +19,7 @@ package e2e
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -427,6 +428,152 @@ var _ = Describe("MinIO - Backup and restore", Label(tests.LabelBackupRestore),
 			})
 		})
 
+		It("backs up and restores a cluster archiving WAL files in a separate object store", func() {
+			const (
+				clusterSeparateWALSampleFile = fixturesDir +
+					"/backup/minio/cluster-with-backup-minio-separate-wal.yaml.template"
+				backupFileSeparateWAL    = fixturesDir + "/backup/minio/backup-minio-separate-wal.yaml"
+				clusterRestoreSampleFile = fixturesDir + "/backup/cluster-from-restore-separate-wal.yaml.template"
+				dataBucket               = "separate-wal-data"
+				walBucket                = "separate-wal-archive"
+			)
+
+			separateWALClusterName, err := env.GetResourceNameFromYAML(clusterSeparateWALSampleFile)
+			Expect(err).ToNot(HaveOccurred())
+
+			By("creating the credentials for the WAL object store", func() {
+				_, err = testUtils.CreateObjectStorageSecret(
+					namespace,
+					"wal-storage-creds",
+					"minio",
+					"minio123",
+					env,
+				)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			AssertCreateCluster(namespace, separateWALClusterName, clusterSeparateWALSampleFile, env)
+
+			tableLocator := TableLocator{
+				Namespace:    namespace,
+				ClusterName:  separateWALClusterName,
+				DatabaseName: testUtils.AppDBName,
+				TableName:    tableName,
+			}
+			AssertCreateTestData(env, tableLocator)
+
+			By("archiving WAL files only in the WAL object store", func() {
+				primary, err := env.GetClusterPrimary(namespace, separateWALClusterName)
+				Expect(err).ToNot(HaveOccurred())
+				latestWAL := switchWalAndGetLatestArchive(namespace, primary.GetName())
+
+				Eventually(func() (int, error) {
+					return minio.CountFiles(minioEnv,
+						filepath.Join(walBucket, separateWALClusterName, "*", latestWAL+".gz"))
+				}, testTimeouts[testUtils.WalsInMinio]).Should(BeEquivalentTo(1))
+				Expect(minio.CountFiles(minioEnv,
+					filepath.Join(dataBucket, separateWALClusterName, "*", latestWAL+"*"))).To(BeEquivalentTo(0))
+			})
+
+			By("storing the base backup only in the base backup object store", func() {
+				testUtils.ExecuteBackup(namespace, backupFileSeparateWAL, false,
+					testTimeouts[testUtils.BackupIsReady], env)
+				testUtils.AssertBackupConditionInClusterStatus(env, namespace, separateWALClusterName)
+
+				Eventually(func() (int, error) {
+					return minio.CountFiles(minioEnv,
+						filepath.Join(dataBucket, separateWALClusterName, "*", "data.tar"))
+				}, 60).Should(BeEquivalentTo(1))
+				Expect(minio.CountFiles(minioEnv,
+					filepath.Join(walBucket, separateWALClusterName, "*", "data.tar"))).To(BeEquivalentTo(0))
+			})
+
+			// The restored cluster takes the base backup from the first
+			// object store, and the WAL files from the second one
+			AssertClusterRestore(namespace, clusterRestoreSampleFile, tableName)
+
+			By("deleting the restored cluster", func() {
+				err = DeleteResourcesFromFile(namespace, clusterRestoreSampleFile)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			By("deleting the primary cluster", func() {
+				err = DeleteResourcesFromFile(namespace, clusterSeparateWALSampleFile)
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
+
+		It("backs up and restores a cluster with a 64MB WAL segment size", func() {
+			const (
+				clusterWalSegSizeSampleFile = fixturesDir +
+					"/backup/minio/cluster-with-backup-minio-wal-segsize.yaml.template"
+				backupFileWalSegSize     = fixturesDir + "/backup/minio/backup-minio-wal-segsize.yaml"
+				clusterRestoreSampleFile = fixturesDir + "/backup/cluster-from-restore-wal-segsize.yaml.template"
+			)
+
+			walSegSizeClusterName, err := env.GetResourceNameFromYAML(clusterWalSegSizeSampleFile)
+			Expect(err).ToNot(HaveOccurred())
+			restoredClusterName, err := env.GetResourceNameFromYAML(clusterRestoreSampleFile)
+			Expect(err).ToNot(HaveOccurred())
+
+			assertWalSegmentSize := func(clusterName string) {
+				primary, err := env.GetClusterPrimary(namespace, clusterName)
+				Expect(err).ToNot(HaveOccurred())
+				out, _, err := env.ExecQueryInInstancePod(
+					testUtils.PodLocator{
+						Namespace: namespace,
+						PodName:   primary.GetName(),
+					},
+					testUtils.PostgresDBName,
+					"SHOW wal_segment_size")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(strings.TrimSpace(out)).To(Equal("64MB"))
+			}
+
+			AssertCreateCluster(namespace, walSegSizeClusterName, clusterWalSegSizeSampleFile, env)
+
+			By("verifying the WAL segment size chosen at bootstrap", func() {
+				assertWalSegmentSize(walSegSizeClusterName)
+			})
+
+			tableLocator := TableLocator{
+				Namespace:    namespace,
+				ClusterName:  walSegSizeClusterName,
+				DatabaseName: testUtils.AppDBName,
+				TableName:    tableName,
+			}
+			AssertCreateTestData(env, tableLocator)
+
+			AssertArchiveWalOnMinio(namespace, walSegSizeClusterName, walSegSizeClusterName)
+
+			By("backing up the cluster and verifying it exists on minio", func() {
+				testUtils.ExecuteBackup(namespace, backupFileWalSegSize, false,
+					testTimeouts[testUtils.BackupIsReady], env)
+				testUtils.AssertBackupConditionInClusterStatus(env, namespace, walSegSizeClusterName)
+				Eventually(func() (int, error) {
+					return minio.CountFiles(minioEnv, minio.GetFilePath(walSegSizeClusterName, "data.tar"))
+				}, 60).Should(BeEquivalentTo(1))
+			})
+
+			// The restored cluster replays the 64MB WAL files archived
+			// after the base backup
+			AssertClusterRestore(namespace, clusterRestoreSampleFile, tableName)
+
+			By("verifying the restored cluster keeps the WAL segment size", func() {
+				assertWalSegmentSize(restoredClusterName)
+			})
+
+			By("deleting the restored cluster", func() {
+				err = DeleteResourcesFromFile(namespace, clusterRestoreSampleFile)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			By("deleting the primary cluster", func() {
+				err = DeleteResourcesFromFile(namespace, clusterWalSegSizeSampleFile)
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
+
 		// Create a scheduled backup with the 'immediate' option enabled. We expect the backup to be available
 		It("immediately starts a backup using ScheduledBackups 'immediate' option", func() {
 			const scheduledBackupSampleFile = fixturesDir +
@@ -0,0 +1,76 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"strings"
+
+	"github.com/cloudnative-pg/cloudnative-pg/tests"
+	"github.com/cloudnative-pg/cloudnative-pg/tests/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Bootstrap readiness", Label(tests.LabelBasic, tests.LabelReplication), func() {
+	const (
+		sampleFile      = fixturesDir + "/bootstrap_readiness/cluster-bootstrap-readiness.yaml.template"
+		namespacePrefix = "bootstrap-readiness-e2e"
+		level           = tests.Medium
+	)
+
+	BeforeEach(func() {
+		if testLevelEnv.Depth < int(level) {
+			Skip("Test depth is lower than the amount requested for this test")
+		}
+	})
+
+	It("reports the cluster as ready only once the replicas have the managed objects", func() {
+		namespace, err := env.CreateUniqueTestNamespace(namespacePrefix)
+		Expect(err).ToNot(HaveOccurred())
+		clusterName, err := env.GetResourceNameFromYAML(sampleFile)
+		Expect(err).ToNot(HaveOccurred())
+
+		AssertCreateCluster(namespace, clusterName, sampleFile, env)
+
+		By("verifying the bootstrap readiness has been completed", func() {
+			cluster, err := env.GetCluster(namespace, clusterName)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cluster.Status.BootstrapReadiness).ToNot(BeNil())
+			Expect(cluster.Status.BootstrapReadiness.Completed).To(BeTrue())
+			Expect(cluster.Status.BootstrapReadiness.ManagedObjectsLSN).ToNot(BeEmpty())
+		})
+
+		By("verifying every replica already has the managed role", func() {
+			replicas, err := env.GetClusterReplicas(namespace, clusterName)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(replicas.Items).To(HaveLen(2))
+
+			for _, replica := range replicas.Items {
+				out, _, err := env.ExecQueryInInstancePod(
+					utils.PodLocator{
+						Namespace: namespace,
+						PodName:   replica.Name,
+					},
+					utils.PostgresDBName,
+					"SELECT count(*) FROM pg_catalog.pg_roles WHERE rolname = 'app_reader'")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(strings.TrimSpace(out)).To(Equal("1"))
+			}
+		})
+	})
+})
@@ -113,6 +113,51 @@ var _ = Describe("Certificates", func() {
 				AssertSSLVerifyFullDBConnectionFromAppPod(namespace, clusterName, pod)
 			})
 
+		It("can authenticate as a differently-named role through a pg_ident user name map",
+			Label(tests.LabelPlugin), func() {
+				const externalUserCertSecretName = "external-user-cert" // #nosec
+
+				cluster, err := env.GetCluster(namespace, clusterName)
+				Expect(err).ToNot(HaveOccurred())
+				originalPgHBA := cluster.Spec.PostgresConfiguration.PgHBA
+				originalPgIdent := cluster.Spec.PostgresConfiguration.PgIdent
+
+				updateAuthenticationRules := func(pgHBA, pgIdent []string) {
+					err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+						cluster, err := env.GetCluster(namespace, clusterName)
+						Expect(err).ToNot(HaveOccurred())
+						cluster.Spec.PostgresConfiguration.PgHBA = pgHBA
+						cluster.Spec.PostgresConfiguration.PgIdent = pgIdent
+						return env.Client.Update(env.Ctx, cluster)
+					})
+					Expect(err).ToNot(HaveOccurred())
+				}
+
+				By("creating a client certificate for a user that is not a PostgreSQL role", func() {
+					err := utils.CreateClientCertificatesViaKubectlPlugin(
+						*cluster,
+						externalUserCertSecretName,
+						"external-user",
+						env,
+					)
+					Expect(err).ToNot(HaveOccurred())
+				})
+
+				By("mapping the certificate to the app role", func() {
+					updateAuthenticationRules(
+						append([]string{"hostssl app app all cert map=external"}, originalPgHBA...),
+						[]string{"external external-user app"},
+					)
+					DeferCleanup(updateAuthenticationRules, originalPgHBA, originalPgIdent)
+				})
+
+				pod := utils.DefaultWebapp(namespace, "app-pod-cert-ident",
+					defaultCASecretName, externalUserCertSecretName)
+				err = utils.PodCreateAndWaitForReady(env, &pod, 240)
+				Expect(err).ToNot(HaveOccurred())
+				AssertSSLVerifyFullDBConnectionFromAppPod(namespace, clusterName, pod)
+			})
+
 		It("can authenticate after switching to user-supplied server certs", Label(tests.LabelServiceConnectivity), func() {
 			CreateAndAssertServerCertificatesSecrets(
 				namespace,
@@ -228,6 +228,32 @@ var _ = Describe("Failover", Label(tests.LabelSelfHealing), func() {
 				return cluster.Status.CurrentPrimary, err
 			}, testTimeouts[utils.NewPrimaryAfterFailover]).Should(BeEquivalentTo(targetPrimary))
 		})
+
+		// The former primary should rejoin the cluster after pg_rewind,
+		// streaming from the new primary on its timeline
+		By("verifying the former primary streams on the new timeline", func() {
+			timelineQuery := "SELECT ('x' || substr(pg_walfile_name(pg_current_wal_lsn()), 1, 8))::bit(32)::int"
+			out, _, err := env.ExecQueryInInstancePod(
+				utils.PodLocator{
+					Namespace: namespace,
+					PodName:   targetPrimary,
+				},
+				utils.PostgresDBName,
+				timelineQuery)
+			Expect(err).ToNot(HaveOccurred())
+			timeline := strings.TrimSpace(out)
+
+			Eventually(func() (string, error) {
+				out, _, err := env.ExecQueryInInstancePod(
+					utils.PodLocator{
+						Namespace: namespace,
+						PodName:   currentPrimary,
+					},
+					utils.PostgresDBName,
+					"SELECT received_tli FROM pg_stat_wal_receiver WHERE status = 'streaming'")
+				return strings.TrimSpace(out), err
+			}, RetryTimeout).Should(BeEquivalentTo(timeline))
+		})
 	}
 
 	// This tests only checks that after the failure of a primary the instance
@@ -173,4 +173,45 @@ var _ = Describe("InitDB settings", Label(tests.LabelSmoke, tests.LabelBasic), f
 			})
 		})
 	})
+
+	Context("explicit locale and timezone", func() {
+		const (
+			clusterName     = "p-locale-timezone"
+			localeTzCluster = fixturesInitdbDir + "/cluster-locale-timezone.yaml.template"
+		)
+
+		var namespace string
+
+		It("use the locale passed to initdb and the configured timezone", func() {
+			const namespacePrefix = "initdb-locale-tz"
+			var err error
+			namespace, err = env.CreateUniqueTestNamespace(namespacePrefix)
+			Expect(err).ToNot(HaveOccurred())
+			AssertCreateCluster(namespace, clusterName, localeTzCluster, env)
+
+			primary, err := env.GetClusterPrimary(namespace, clusterName)
+			Expect(err).ToNot(HaveOccurred())
+			query := func(sql string) string {
+				stdout, _, err := env.ExecQueryInInstancePod(
+					utils.PodLocator{
+						Namespace: namespace,
+						PodName:   primary.Name,
+					}, "postgres", sql)
+				Expect(err).ToNot(HaveOccurred())
+				return stdout
+			}
+
+			By("checking the locale and the encoding", func() {
+				Expect(query("select datcollate from pg_database where datname='app'")).
+					To(Equal("en_US.utf8\n"))
+				Expect(query("select pg_encoding_to_char(encoding) from pg_database where datname='app'")).
+					To(Equal("UTF8\n"))
+			})
+
+			By("checking the timezone and the datestyle", func() {
+				Expect(query("show timezone")).To(Equal("Europe/Rome\n"))
+				Expect(query("show datestyle")).To(Equal("ISO, DMY\n"))
+			})
+		})
+	})
 })
@@ -0,0 +1,127 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/tests"
+	testsUtils "github.com/cloudnative-pg/cloudnative-pg/tests/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Data integrity check", Label(tests.LabelObservability), func() {
+	const (
+		sampleFile      = fixturesDir + "/integrity_check/cluster-integrity-check.yaml.template"
+		namespacePrefix = "integrity-check-e2e"
+		tableName       = "test_integrity_check"
+		level           = tests.Medium
+	)
+
+	BeforeEach(func() {
+		if testLevelEnv.Depth < int(level) {
+			Skip("Test depth is lower than the amount requested for this test")
+		}
+	})
+
+	It("detects a corrupted page on a fenced replica", func() {
+		namespace, err := env.CreateUniqueTestNamespace(namespacePrefix)
+		Expect(err).ToNot(HaveOccurred())
+		clusterName, err := env.GetResourceNameFromYAML(sampleFile)
+		Expect(err).ToNot(HaveOccurred())
+
+		AssertCreateCluster(namespace, clusterName, sampleFile, env)
+		AssertCreateTestData(env, TableLocator{
+			Namespace:    namespace,
+			ClusterName:  clusterName,
+			DatabaseName: testsUtils.AppDBName,
+			TableName:    tableName,
+		})
+		AssertClusterStandbysAreStreaming(namespace, clusterName, 120)
+
+		var replicaName, relationPath string
+		By("finding the replica and the data file of the table", func() {
+			primary, err := env.GetClusterPrimary(namespace, clusterName)
+			Expect(err).ToNot(HaveOccurred())
+			out, _, err := env.ExecQueryInInstancePod(
+				testsUtils.PodLocator{
+					Namespace: namespace,
+					PodName:   primary.Name,
+				},
+				testsUtils.AppDBName,
+				fmt.Sprintf("CHECKPOINT; SELECT pg_relation_filepath('%s')", tableName))
+			Expect(err).ToNot(HaveOccurred())
+			relationPath = strings.TrimSpace(out)
+
+			replicas, err := env.GetClusterReplicas(namespace, clusterName)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(replicas.Items).To(HaveLen(1))
+			replicaName = replicas.Items[0].Name
+		})
+
+		By("fencing the replica", func() {
+			Expect(testsUtils.FencingOn(env, replicaName, namespace, clusterName,
+				testsUtils.UsingAnnotation)).To(Succeed())
+			Eventually(func(g Gomega) {
+				_, _, err := env.ExecCommandInInstancePod(
+					testsUtils.PodLocator{
+						Namespace: namespace,
+						PodName:   replicaName,
+					}, nil,
+					"test", "!", "-f", specs.PgDataPath+"/postmaster.pid")
+				g.Expect(err).ToNot(HaveOccurred())
+			}, 120).Should(Succeed())
+		})
+
+		By("verifying the integrity check reports no errors", func() {
+			Eventually(func(g Gomega) {
+				cluster, err := env.GetCluster(namespace, clusterName)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(cluster.Status.IntegrityCheck).ToNot(BeNil())
+				g.Expect(cluster.Status.IntegrityCheck.InstanceName).To(Equal(replicaName))
+				g.Expect(cluster.Status.IntegrityCheck.Method).To(Equal("checksums"))
+				g.Expect(cluster.Status.IntegrityCheck.Errors).To(BeZero())
+			}, 180).Should(Succeed())
+		})
+
+		By("corrupting a data page of the table on the replica", func() {
+			cmd := fmt.Sprintf("dd if=/dev/urandom of=%s/%s bs=1 count=64 seek=4096 conv=notrunc",
+				specs.PgDataPath, relationPath)
+			_, _, err := env.ExecCommandInInstancePod(
+				testsUtils.PodLocator{
+					Namespace: namespace,
+					PodName:   replicaName,
+				}, nil,
+				"/bin/bash", "-c", cmd)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		By("verifying the integrity check detects the corrupted page", func() {
+			Eventually(func(g Gomega) {
+				cluster, err := env.GetCluster(namespace, clusterName)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(cluster.Status.IntegrityCheck).ToNot(BeNil())
+				g.Expect(cluster.Status.IntegrityCheck.Errors).To(BeNumerically(">", 0))
+				g.Expect(cluster.Status.IntegrityCheck.Message).To(ContainSubstring(relationPath))
+			}, 180).Should(Succeed())
+		})
+	})
+})
@@ -308,6 +308,74 @@ var _ = Describe("Managed roles tests", Label(tests.LabelSmoke, tests.LabelBasic
 			})
 		})
 
+		It("rejects connections above the connection limit of a role without affecting other roles", func() {
+			rwService := fmt.Sprintf("%v-rw.%v.svc", clusterName, namespace)
+			commandTimeout := time.Second * 10
+
+			By("limiting the connections of the role to one", func() {
+				cluster, err := env.GetCluster(namespace, clusterName)
+				Expect(err).ToNot(HaveOccurred())
+				updated := cluster.DeepCopy()
+				updated.Spec.Managed.Roles[0].ConnectionLimit = 1
+				err = env.Client.Patch(env.Ctx, updated, client.MergeFrom(cluster))
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			By("verifying the effective connection limit is reported in the status", func() {
+				Eventually(func(g Gomega) {
+					cluster, err := env.GetCluster(namespace, clusterName)
+					g.Expect(err).ToNot(HaveOccurred())
+					g.Expect(cluster.Status.ManagedRolesStatus.ConnectionLimits).To(HaveKeyWithValue(username, int64(1)))
+				}, 30).Should(Succeed())
+			})
+
+			primaryPod, err := env.GetClusterPrimary(namespace, clusterName)
+			Expect(err).ToNot(HaveOccurred())
+
+			By("holding the only connection allowed to the role", func() {
+				dsn := fmt.Sprintf("host=%v user=%v dbname=%v password=%v sslmode=require",
+					rwService, username, "postgres", password)
+				_, _, err := env.ExecCommand(env.Ctx, *primaryPod, specs.PostgresContainerName, &commandTimeout,
+					"sh", "-c", fmt.Sprintf("nohup psql '%s' -c 'SELECT pg_sleep(120)' >/dev/null 2>&1 &", dsn))
+				Expect(err).ToNot(HaveOccurred())
+
+				Eventually(func() (string, error) {
+					stdout, _, err := env.ExecQueryInInstancePod(
+						utils.PodLocator{
+							Namespace: namespace,
+							PodName:   primaryPod.Name,
+						},
+						utils.PostgresDBName,
+						fmt.Sprintf("SELECT count(*) FROM pg_stat_activity WHERE usename = '%s'", username))
+					return strings.TrimSpace(stdout), err
+				}, 30).Should(Equal("1"))
+			})
+
+			By("rejecting a new connection of the role", func() {
+				dsn := fmt.Sprintf("host=%v user=%v dbname=%v password=%v sslmode=require",
+					rwService, username, "postgres", password)
+				_, stderr, err := env.ExecCommand(env.Ctx, *primaryPod, specs.PostgresContainerName, &commandTimeout,
+					"psql", dsn, "-tAc", "SELECT 1")
+				Expect(err).To(HaveOccurred())
+				Expect(stderr).To(ContainSubstring("too many connections for role"))
+			})
+
+			By("accepting connections of the other roles", func() {
+				AssertConnection(rwService, userWithHashedPassword, "postgres", userWithHashedPassword, primaryPod, 30, env)
+			})
+
+			By("terminating the connection held by the role", func() {
+				_, _, err := env.ExecQueryInInstancePod(
+					utils.PodLocator{
+						Namespace: namespace,
+						PodName:   primaryPod.Name,
+					},
+					utils.PostgresDBName,
+					fmt.Sprintf("SELECT pg_terminate_backend(pid) FROM pg_stat_activity WHERE usename = '%s'", username))
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
+
 		It("Can add role with all attribute omitted and verify it is default", func() {
 			primaryPodInfo, err := env.GetClusterPrimary(namespace, clusterName)
 			Expect(err).ToNot(HaveOccurred())